@@ -233,6 +233,9 @@ Build the args for the service binary.
 {{- end -}}
 - -nginx-plus={{ .Values.controller.nginxplus }}
 - -nginx-reload-timeout={{ .Values.controller.nginxReloadTimeout }}
+{{- if .Values.controller.enableConfigTest }}
+- -enable-config-test={{ .Values.controller.enableConfigTest }}
+{{- end }}
 - -enable-app-protect={{ .Values.controller.appprotect.enable }}
 {{- if and .Values.controller.appprotect.enable .Values.controller.appprotect.logLevel }}
 - -app-protect-log-level={{ .Values.controller.appprotect.logLevel }}
@@ -266,6 +269,9 @@ Build the args for the service binary.
 {{- if .Values.controller.watchSecretNamespace }}
 - -watch-secret-namespace={{ .Values.controller.watchSecretNamespace }}
 {{- end }}
+{{- if .Values.controller.enableNamespaceSharding }}
+- -enable-namespace-sharding={{ .Values.controller.enableNamespaceSharding }}
+{{- end }}
 - -health-status={{ .Values.controller.healthStatus }}
 - -health-status-uri={{ .Values.controller.healthStatusURI }}
 - -nginx-debug={{ .Values.controller.nginxDebug }}
@@ -312,6 +318,7 @@ Build the args for the service binary.
 - -enable-cert-manager={{ .Values.controller.enableCertManager }}
 - -enable-oidc={{ .Values.controller.enableOIDC }}
 - -enable-external-dns={{ .Values.controller.enableExternalDNS }}
+- -enable-gateway-api={{ .Values.controller.enableGatewayAPI }}
 - -default-http-listener-port={{ .Values.controller.defaultHTTPListenerPort}}
 - -default-https-listener-port={{ .Values.controller.defaultHTTPSListenerPort}}
 {{- if .Values.controller.globalConfiguration.create }}
@@ -51,6 +51,9 @@ var (
 	watchNamespaceLabel = flag.String("watch-namespace-label", "",
 		`Configures the Ingress Controller to watch only those namespaces with label foo=bar. By default the Ingress Controller watches all namespaces. Mutually exclusive with "watch-namespace". `)
 
+	enableNamespaceSharding = flag.Bool("enable-namespace-sharding", false,
+		`Enable sharding of the watched namespaces across the replicas of the Ingress Controller. Every replica configures NGINX only for the resources in the namespaces it owns. The namespaces are assigned by consistent hashing across the replicas that hold a Lease named after "leader-election-lock-name". Mutually exclusive with "watch-namespace".`)
+
 	nginxConfigMaps = flag.String("nginx-configmaps", "",
 		`A ConfigMap resource for customizing NGINX configuration. If a ConfigMap is set,
 	but the Ingress Controller is not able to fetch it from Kubernetes API, the Ingress Controller will fail to start.
@@ -147,6 +150,9 @@ var (
 	nginxReloadTimeout = flag.Int("nginx-reload-timeout", 60000,
 		`The timeout in milliseconds which the Ingress Controller will wait for a successful NGINX reload after a change or at the initial start. (default 60000)`)
 
+	enableConfigTest = flag.Bool("enable-config-test", false,
+		`Enable testing of the NGINX configuration with "nginx -t" before every reload. The configuration of the resources that NGINX rejects is not applied.`)
+
 	wildcardTLSSecret = flag.String("wildcard-tls-secret", "",
 		`A Secret with a TLS certificate and key for TLS termination of every Ingress/VirtualServer host for which TLS termination is enabled but the Secret is not specified.
 		Format: <namespace>/<name>. If the argument is not set, for such Ingress/VirtualServer hosts NGINX will break any attempt to establish a TLS connection.
@@ -162,7 +168,7 @@ var (
 		"Set the port where the Prometheus metrics are exposed. [1024 - 65535]")
 
 	enableServiceInsight = flag.Bool("enable-service-insight", false,
-		`Enable service insight for external load balancers. Requires -nginx-plus`)
+		`Enable service insight for external load balancers. Without -nginx-plus, requires -enable-latency-metrics`)
 
 	serviceInsightTLSSecretName = flag.String("service-insight-tls-secret", "",
 		`A Secret with a TLS certificate and key for TLS termination of the service insight.`)
@@ -187,6 +193,9 @@ var (
 
 	tlsPassthroughPort = flag.Int("tls-passthrough-port", 443, "Set custom port for TLS Passthrough. [1024 - 65535]")
 
+	enableGatewayAPI = flag.Bool("enable-gateway-api", false,
+		`Enable support for Gateway API resources: GatewayClasses, Gateways, HTTPRoutes, GRPCRoutes and, if its CRD is installed, TLSRoutes. Requires -enable-custom-resources`)
+
 	spireAgentAddress = flag.String("spire-agent-address", "",
 		`Specifies the address of the running Spire agent. Requires -nginx-plus and is for use with NGINX Service Mesh only. If the flag is set,
 			but the Ingress Controller is not able to connect with the Spire Agent, the Ingress Controller will fail to start.`)
@@ -205,7 +214,7 @@ var (
 		"Enable cert-manager controller for VirtualServer resources. Requires -enable-custom-resources")
 
 	enableExternalDNS = flag.Bool("enable-external-dns", false,
-		"Enable external-dns controller for VirtualServer resources. Requires -enable-custom-resources")
+		"Enable external-dns controller for VirtualServer, TransportServer and Ingress resources. Requires -enable-custom-resources")
 
 	disableIPV6 = flag.Bool("disable-ipv6", false,
 		`Disable IPV6 listeners explicitly for nodes that do not support the IPV6 stack`)
@@ -254,9 +263,8 @@ func initValidate(ctx context.Context) {
 		*enableLatencyMetrics = false
 	}
 
-	if *enableServiceInsight && !*nginxPlus {
-		nl.Warn(l, "enable-service-insight flag support is for NGINX Plus, service insight endpoint will not be exposed")
-		*enableServiceInsight = false
+	if *enableServiceInsight && !*nginxPlus && !*enableLatencyMetrics {
+		nl.Fatal(l, "enable-service-insight flag without NGINX Plus requires enable-latency-metrics and enable-prometheus-metrics")
 	}
 
 	if *enableDynamicWeightChangesReload && !*nginxPlus {
@@ -300,6 +308,10 @@ func mustValidateWatchedNamespaces(ctx context.Context) {
 		nl.Fatal(l, "watch-namespace and -watch-namespace-label are mutually exclusive")
 	}
 
+	if *watchNamespace != "" && *enableNamespaceSharding {
+		nl.Fatal(l, "watch-namespace and -enable-namespace-sharding are mutually exclusive")
+	}
+
 	watchNamespaces = strings.Split(*watchNamespace, ",")
 
 	if *watchNamespace != "" {
@@ -415,6 +427,10 @@ func mustValidateFlags(ctx context.Context) {
 		nl.Fatal(l, "enable-internal-routes flag requires spire-agent-address")
 	}
 
+	if *enableGatewayAPI && !*enableCustomResources {
+		nl.Fatal(l, "enable-gateway-api flag requires -enable-custom-resources")
+	}
+
 	if *enableCertManager && !*enableCustomResources {
 		nl.Fatal(l, "enable-cert-manager flag requires -enable-custom-resources")
 	}
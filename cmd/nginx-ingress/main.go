@@ -13,6 +13,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
@@ -45,6 +46,10 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/client-go/tools/record"
+	gateway_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	gateway_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gateway_clientset "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
+	gateway_scheme "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/scheme"
 
 	nl "github.com/nginx/kubernetes-ingress/internal/logger"
 	nic_glog "github.com/nginx/kubernetes-ingress/internal/logger/glog"
@@ -79,6 +84,10 @@ const (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == renderCommand {
+		os.Exit(runRender(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	commitHash, commitTime, dirtyBuild := getBuildInfo()
 	fmt.Printf("NGINX Ingress Controller Version=%v Commit=%v Date=%v DirtyState=%v Arch=%v/%v Go=%v\n", version, commitHash, commitTime, dirtyBuild, runtime.GOOS, runtime.GOARCH, runtime.Version())
 	parseFlags()
@@ -116,6 +125,8 @@ func main() {
 
 	dynClient, confClient := createCustomClients(ctx, config)
 
+	gatewayClient, isTLSRouteEnabled := createGatewayClient(ctx, config, kubeClient)
+
 	constLabels := map[string]string{"class": *ingressClass}
 
 	managerCollector, controllerCollector, registry := createManagerAndControllerCollectors(ctx, constLabels)
@@ -126,7 +137,7 @@ func main() {
 		licenseReporter = license_reporting.NewLicenseReporter(kubeClient, eventRecorder, pod)
 	}
 
-	nginxManager, useFakeNginxManager := createNginxManager(ctx, managerCollector, licenseReporter)
+	nginxManager, useFakeNginxManager := createNginxManager(ctx, managerCollector, licenseReporter, eventRecorder, pod)
 
 	nginxVersion := getNginxVersionInfo(ctx, nginxManager)
 
@@ -187,34 +198,7 @@ func main() {
 	cfgParams := configs.NewDefaultConfigParams(ctx, *nginxPlus)
 	cfgParams = processConfigMaps(kubeClient, cfgParams, nginxManager, templateExecutor, eventRecorder)
 
-	staticCfgParams := &configs.StaticConfigParams{
-		DisableIPV6:                    *disableIPV6,
-		DefaultHTTPListenerPort:        *defaultHTTPListenerPort,
-		DefaultHTTPSListenerPort:       *defaultHTTPSListenerPort,
-		HealthStatus:                   *healthStatus,
-		HealthStatusURI:                *healthStatusURI,
-		NginxStatus:                    *nginxStatus,
-		NginxStatusAllowCIDRs:          allowedCIDRs,
-		NginxStatusPort:                *nginxStatusPort,
-		StubStatusOverUnixSocketForOSS: *enablePrometheusMetrics,
-		TLSPassthrough:                 *enableTLSPassthrough,
-		TLSPassthroughPort:             *tlsPassthroughPort,
-		EnableSnippets:                 *enableSnippets,
-		NginxServiceMesh:               *spireAgentAddress != "",
-		MainAppProtectLoadModule:       *appProtect,
-		MainAppProtectV5LoadModule:     appProtectV5,
-		MainAppProtectDosLoadModule:    *appProtectDos,
-		MainAppProtectV5EnforcerAddr:   *appProtectEnforcerAddress,
-		EnableLatencyMetrics:           *enableLatencyMetrics,
-		EnableOIDC:                     *enableOIDC,
-		SSLRejectHandshake:             sslRejectHandshake,
-		EnableCertManager:              *enableCertManager,
-		DynamicSSLReload:               *enableDynamicSSLReload,
-		DynamicWeightChangesReload:     *enableDynamicWeightChangesReload,
-		StaticSSLPath:                  staticSSLPath,
-		NginxVersion:                   nginxVersion,
-		AppProtectBundlePath:           appProtectBundlePath,
-	}
+	staticCfgParams := createStaticConfigParams(nginxVersion, staticSSLPath, sslRejectHandshake, appProtectV5, appProtectBundlePath)
 
 	mustWriteNginxMainConfig(staticCfgParams, cfgParams, mgmtCfgParams, templateExecutor, nginxManager)
 
@@ -249,21 +233,16 @@ func main() {
 		NginxVersion:                        nginxVersion,
 	})
 
-	transportServerValidator := cr_validation.NewTransportServerValidator(*enableTLSPassthrough, *enableSnippets, *nginxPlus)
-	virtualServerValidator := cr_validation.NewVirtualServerValidator(
-		cr_validation.IsPlus(*nginxPlus),
-		cr_validation.IsDosEnabled(*appProtectDos),
-		cr_validation.IsCertManagerEnabled(*enableCertManager),
-		cr_validation.IsExternalDNSEnabled(*enableExternalDNS),
-	)
+	virtualServerValidator, transportServerValidator := createCustomResourceValidators()
 
 	if *enableServiceInsight {
-		createHealthProbeEndpoint(kubeClient, plusClient, cnf)
+		createHealthProbeEndpoint(kubeClient, plusClient, latencyCollector, cnf)
 	}
 
 	lbcInput := k8s.NewLoadBalancerControllerInput{
 		KubeClient:                   kubeClient,
 		ConfClient:                   confClient,
+		GatewayClient:                gatewayClient,
 		DynClient:                    dynClient,
 		RestConfig:                   config,
 		Recorder:                     eventRecorder,
@@ -306,12 +285,17 @@ func main() {
 		ExternalDNSEnabled:           *enableExternalDNS,
 		IsIPV6Disabled:               *disableIPV6,
 		WatchNamespaceLabel:          *watchNamespaceLabel,
+		IsNamespaceShardingEnabled:   *enableNamespaceSharding,
 		EnableTelemetryReporting:     *enableTelemetryReporting,
 		TelemetryReportingEndpoint:   telemetryEndpoint,
 		BuildOS:                      buildOS,
 		NICVersion:                   version,
 		DynamicWeightChangesReload:   *enableDynamicWeightChangesReload,
 		InstallationFlags:            parsedFlags,
+		NginxPlusClient:              plusClient,
+		LatencyCollector:             latencyCollector,
+		IsGatewayAPIEnabled:          *enableGatewayAPI,
+		IsTLSRouteEnabled:            isTLSRouteEnabled,
 	}
 
 	lbc := k8s.NewLoadBalancerController(lbcInput)
@@ -439,7 +423,10 @@ func mustValidateIngressClass(ctx context.Context, kubeClient kubernetes.Interfa
 
 func checkNamespaces(ctx context.Context, kubeClient kubernetes.Interface) {
 	l := nl.LoggerFromContext(ctx)
-	if *watchNamespaceLabel != "" {
+	if *enableNamespaceSharding {
+		// the namespaces are added to the watched namespace list once the shard owns them
+		nl.Infof(l, "Namespaces watched using namespace sharding with the label %q", *watchNamespaceLabel)
+	} else if *watchNamespaceLabel != "" {
 		// bootstrap the watched namespace list
 		var newWatchNamespaces []string
 		nsList, err := kubeClient.CoreV1().Namespaces().List(context.TODO(), meta_v1.ListOptions{LabelSelector: *watchNamespaceLabel})
@@ -495,6 +482,47 @@ func createCustomClients(ctx context.Context, config *rest.Config) (dynamic.Inte
 	return dynClient, confClient
 }
 
+// createGatewayClient creates the client for the Gateway API resources if the Gateway API is enabled.
+// It also reports whether the TLSRoute resource, which is only a part of the experimental channel of the Gateway API, is installed.
+func createGatewayClient(ctx context.Context, config *rest.Config, kubeClient kubernetes.Interface) (gateway_clientset.Interface, bool) {
+	l := nl.LoggerFromContext(ctx)
+	if !*enableGatewayAPI {
+		return nil, false
+	}
+
+	resources, err := kubeClient.Discovery().ServerResourcesForGroupVersion(gateway_v1.GroupVersion.String())
+	if err != nil {
+		nl.Fatalf(l, "Failed to get the Gateway API resources, make sure the Gateway API CRDs are installed: %v", err)
+	}
+	for _, name := range []string{"gatewayclasses", "gateways", "httproutes", "grpcroutes"} {
+		if !slices.ContainsFunc(resources.APIResources, func(r meta_v1.APIResource) bool { return r.Name == name }) {
+			nl.Fatalf(l, "The Gateway API resource %s is not installed", name)
+		}
+	}
+
+	isTLSRouteEnabled := false
+	resources, err = kubeClient.Discovery().ServerResourcesForGroupVersion(gateway_v1alpha2.GroupVersion.String())
+	if err == nil {
+		isTLSRouteEnabled = slices.ContainsFunc(resources.APIResources, func(r meta_v1.APIResource) bool { return r.Name == "tlsroutes" })
+	}
+	if !isTLSRouteEnabled {
+		nl.Info(l, "The Gateway API TLSRoute resource is not installed, TLSRoutes are not supported")
+	}
+
+	gatewayClient, err := gateway_clientset.NewForConfig(config)
+	if err != nil {
+		nl.Fatalf(l, "Failed to create a Gateway API client: %v", err)
+	}
+
+	// required for emitting Events for Gateways
+	err = gateway_scheme.AddToScheme(scheme.Scheme)
+	if err != nil {
+		nl.Fatalf(l, "Failed to add Gateway API types to the scheme: %v", err)
+	}
+
+	return gatewayClient, isTLSRouteEnabled
+}
+
 func createPlusClient(ctx context.Context, nginxPlus bool, useFakeNginxManager bool, nginxManager nginx.Manager) *client.NginxClient {
 	l := nl.LoggerFromContext(ctx)
 	var plusClient *client.NginxClient
@@ -550,14 +578,14 @@ func createTemplateExecutors(ctx context.Context) (*version1.TemplateExecutor, *
 	return templateExecutor, templateExecutorV2
 }
 
-func createNginxManager(ctx context.Context, managerCollector collectors.ManagerCollector, licenseReporter *license_reporting.LicenseReporter) (nginx.Manager, bool) {
+func createNginxManager(ctx context.Context, managerCollector collectors.ManagerCollector, licenseReporter *license_reporting.LicenseReporter, eventRecorder record.EventRecorder, pod *api_v1.Pod) (nginx.Manager, bool) {
 	useFakeNginxManager := *proxyURL != ""
 	var nginxManager nginx.Manager
 	if useFakeNginxManager {
 		nginxManager = nginx.NewFakeManager("/etc/nginx")
 	} else {
 		timeout := time.Duration(*nginxReloadTimeout) * time.Millisecond
-		nginxManager = nginx.NewLocalManager(ctx, "/etc/nginx/", *nginxDebug, managerCollector, licenseReporter, timeout, *nginxPlus)
+		nginxManager = nginx.NewLocalManager(ctx, "/etc/nginx", *nginxDebug, managerCollector, licenseReporter, timeout, *nginxPlus, *enableConfigTest, eventRecorder, pod)
 	}
 	return nginxManager, useFakeNginxManager
 }
@@ -717,6 +745,50 @@ func createGlobalConfigurationValidator() *cr_validation.GlobalConfigurationVali
 	return cr_validation.NewGlobalConfigurationValidator(forbiddenListenerPorts)
 }
 
+// createStaticConfigParams creates the configuration parameters that can't be changed while NGINX is running.
+func createStaticConfigParams(nginxVersion nginx.Version, staticSSLPath string, sslRejectHandshake bool, appProtectV5 bool, appProtectBundlePath string) *configs.StaticConfigParams {
+	return &configs.StaticConfigParams{
+		DisableIPV6:                    *disableIPV6,
+		DefaultHTTPListenerPort:        *defaultHTTPListenerPort,
+		DefaultHTTPSListenerPort:       *defaultHTTPSListenerPort,
+		HealthStatus:                   *healthStatus,
+		HealthStatusURI:                *healthStatusURI,
+		NginxStatus:                    *nginxStatus,
+		NginxStatusAllowCIDRs:          allowedCIDRs,
+		NginxStatusPort:                *nginxStatusPort,
+		StubStatusOverUnixSocketForOSS: *enablePrometheusMetrics,
+		TLSPassthrough:                 *enableTLSPassthrough,
+		TLSPassthroughPort:             *tlsPassthroughPort,
+		EnableSnippets:                 *enableSnippets,
+		NginxServiceMesh:               *spireAgentAddress != "",
+		MainAppProtectLoadModule:       *appProtect,
+		MainAppProtectV5LoadModule:     appProtectV5,
+		MainAppProtectDosLoadModule:    *appProtectDos,
+		MainAppProtectV5EnforcerAddr:   *appProtectEnforcerAddress,
+		EnableLatencyMetrics:           *enableLatencyMetrics,
+		EnableOIDC:                     *enableOIDC,
+		SSLRejectHandshake:             sslRejectHandshake,
+		EnableCertManager:              *enableCertManager,
+		DynamicSSLReload:               *enableDynamicSSLReload,
+		DynamicWeightChangesReload:     *enableDynamicWeightChangesReload,
+		StaticSSLPath:                  staticSSLPath,
+		NginxVersion:                   nginxVersion,
+		AppProtectBundlePath:           appProtectBundlePath,
+	}
+}
+
+func createCustomResourceValidators() (*cr_validation.VirtualServerValidator, *cr_validation.TransportServerValidator) {
+	virtualServerValidator := cr_validation.NewVirtualServerValidator(
+		cr_validation.IsPlus(*nginxPlus),
+		cr_validation.IsDosEnabled(*appProtectDos),
+		cr_validation.IsCertManagerEnabled(*enableCertManager),
+		cr_validation.IsExternalDNSEnabled(*enableExternalDNS),
+	)
+	transportServerValidator := cr_validation.NewTransportServerValidator(*enableTLSPassthrough, *enableSnippets, *nginxPlus, *enableCertManager, *enableExternalDNS)
+
+	return virtualServerValidator, transportServerValidator
+}
+
 // mustWriteNginxMainConfig calls internally os.Exit
 // if can't generate a valid NGINX config.
 func mustWriteNginxMainConfig(staticCfgParams *configs.StaticConfigParams, cfgParams *configs.ConfigParams, mgmtCfgParams *configs.MGMTConfigParams, templateExecutor *version1.TemplateExecutor, nginxManager nginx.Manager) {
@@ -728,9 +800,10 @@ func mustWriteNginxMainConfig(staticCfgParams *configs.StaticConfigParams, cfgPa
 	}
 	nginxManager.CreateMainConfig(content)
 
-	nginxManager.UpdateConfigVersionFile(ngxConfig.OpenTracingLoadModule)
+	nginxManager.UpdateConfigVersionFile(ngxConfig.OpenTracingLoadModule, ngxConfig.OtelTraceInHTTP)
 
 	nginxManager.SetOpenTracing(ngxConfig.OpenTracingLoadModule)
+	nginxManager.SetOtelTracing(ngxConfig.OtelTraceInHTTP)
 
 	if ngxConfig.OpenTracingLoadModule {
 		err := nginxManager.CreateOpenTracingTracerConfig(cfgParams.MainOpenTracingTracerConfig)
@@ -940,7 +1013,7 @@ func createPlusAndLatencyCollectors(
 	return plusCollector, syslogListener, lc
 }
 
-func createHealthProbeEndpoint(kubeClient *kubernetes.Clientset, plusClient *client.NginxClient, cnf *configs.Configurator) {
+func createHealthProbeEndpoint(kubeClient *kubernetes.Clientset, plusClient *client.NginxClient, lc collectors.LatencyCollector, cnf *configs.Configurator) {
 	l := nl.LoggerFromContext(cnf.CfgParams.Context)
 	if !*enableServiceInsight {
 		return
@@ -954,7 +1027,7 @@ func createHealthProbeEndpoint(kubeClient *kubernetes.Clientset, plusClient *cli
 			nl.Fatalf(l, "Error trying to get the service insight TLS secret %v: %v", *serviceInsightTLSSecretName, err)
 		}
 	}
-	go healthcheck.RunHealthCheck(*serviceInsightListenPort, plusClient, cnf, serviceInsightSecret)
+	go healthcheck.RunHealthCheck(*serviceInsightListenPort, plusClient, lc, cnf, serviceInsightSecret)
 }
 
 // mustProcessGlobalConfiguration calls internally os.Exit
@@ -984,26 +1057,34 @@ func processConfigMaps(kubeClient *kubernetes.Clientset, cfgParams *configs.Conf
 		if err != nil {
 			nl.Fatalf(l, "Error when getting %v: %v", *nginxConfigMaps, err)
 		}
-		cfgParams, _ = configs.ParseConfigMap(cfgParams.Context, cfm, *nginxPlus, *appProtect, *appProtectDos, *enableTLSPassthrough, eventLog)
-		if cfgParams.MainServerSSLDHParamFileContent != nil {
-			fileName, err := nginxManager.CreateDHParam(*cfgParams.MainServerSSLDHParamFileContent)
-			if err != nil {
-				nl.Fatalf(l, "Configmap %s/%s: Could not update dhparams: %v", ns, name, err)
-			} else {
-				cfgParams.MainServerSSLDHParam = fileName
-			}
+		cfgParams = mustApplyConfigMap(cfm, cfgParams, nginxManager, templateExecutor, eventLog)
+	}
+	return cfgParams
+}
+
+// mustApplyConfigMap parses the NGINX ConfigMap, writes the dhparam file and updates the templates.
+// It calls internally os.Exit if it can't apply the ConfigMap.
+func mustApplyConfigMap(cfm *api_v1.ConfigMap, cfgParams *configs.ConfigParams, nginxManager nginx.Manager, templateExecutor *version1.TemplateExecutor, eventLog record.EventRecorder) *configs.ConfigParams {
+	l := nl.LoggerFromContext(cfgParams.Context)
+	cfgParams, _ = configs.ParseConfigMap(cfgParams.Context, cfm, *nginxPlus, *appProtect, *appProtectDos, *enableTLSPassthrough, eventLog)
+	if cfgParams.MainServerSSLDHParamFileContent != nil {
+		fileName, err := nginxManager.CreateDHParam(*cfgParams.MainServerSSLDHParamFileContent)
+		if err != nil {
+			nl.Fatalf(l, "Configmap %s/%s: Could not update dhparams: %v", cfm.Namespace, cfm.Name, err)
+		} else {
+			cfgParams.MainServerSSLDHParam = fileName
 		}
-		if cfgParams.MainTemplate != nil {
-			err = templateExecutor.UpdateMainTemplate(cfgParams.MainTemplate)
-			if err != nil {
-				nl.Fatalf(l, "Error updating NGINX main template: %v", err)
-			}
+	}
+	if cfgParams.MainTemplate != nil {
+		err := templateExecutor.UpdateMainTemplate(cfgParams.MainTemplate)
+		if err != nil {
+			nl.Fatalf(l, "Error updating NGINX main template: %v", err)
 		}
-		if cfgParams.IngressTemplate != nil {
-			err = templateExecutor.UpdateIngressTemplate(cfgParams.IngressTemplate)
-			if err != nil {
-				nl.Fatalf(l, "Error updating ingress template: %v", err)
-			}
+	}
+	if cfgParams.IngressTemplate != nil {
+		err := templateExecutor.UpdateIngressTemplate(cfgParams.IngressTemplate)
+		if err != nil {
+			nl.Fatalf(l, "Error updating ingress template: %v", err)
 		}
 	}
 	return cfgParams
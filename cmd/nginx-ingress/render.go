@@ -0,0 +1,384 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nginx/kubernetes-ingress/internal/configs"
+	"github.com/nginx/kubernetes-ingress/internal/k8s"
+	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	"github.com/nginx/kubernetes-ingress/internal/nginx"
+	conf_scheme "github.com/nginx/kubernetes-ingress/pkg/client/clientset/versioned/scheme"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	pkg_runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	renderCommand = "render"
+
+	defaultRenderNginxVersion     = "nginx version: nginx/1.27.4"
+	defaultRenderNginxPlusVersion = "nginx version: nginx/1.27.2 (nginx-plus-r33)"
+)
+
+// manifestPaths holds the values of a repeatable flag.
+type manifestPaths []string
+
+func (m *manifestPaths) String() string {
+	return strings.Join(*m, ",")
+}
+
+func (m *manifestPaths) Set(value string) error {
+	*m = append(*m, value)
+	return nil
+}
+
+// runRender generates the NGINX configuration for the resources of the manifests given with -f, without
+// a Kubernetes API server and without running NGINX, and prints it. The problems of the resources are printed to errOut.
+// The flags of the controller, like -nginx-plus or -ingress-class, apply to the generated configuration.
+// It returns the exit code: 1 if the configuration of any resource was rejected or couldn't be generated.
+func runRender(args []string, out io.Writer, errOut io.Writer) int {
+	var paths manifestPaths
+	flag.Var(&paths, "f", "A manifest file or a directory of manifest files with the resources to render the configuration for. Can be repeated.")
+	outputDir := flag.String("output-dir", "", "A directory to keep the generated files in. By default, a temporary directory is used and removed.")
+	renderNginxVersion := flag.String("render-nginx-version", "", "The output of 'nginx -v' of the NGINX to render the configuration for. By default, a recent NGINX or NGINX Plus version is used.")
+
+	if err := flag.CommandLine.Parse(args); err != nil {
+		return 2
+	}
+	ctx := initLogger(*logFormat, logLevels[*logLevel], errOut)
+	l := nl.LoggerFromContext(ctx)
+
+	if len(paths) == 0 {
+		nl.Error(l, "The render command requires at least one -f argument")
+		return 2
+	}
+
+	var err error
+	allowedCIDRs, err = parseNginxStatusAllowCIDRs(*nginxStatusAllowCIDRs)
+	if err != nil {
+		nl.Errorf(l, "Invalid value for nginx-status-allow-cidrs: %v", err)
+		return 2
+	}
+
+	objects, err := loadManifests(paths)
+	if err != nil {
+		nl.Errorf(l, "Error loading manifests: %v", err)
+		return 2
+	}
+	configMap, objects, err := extractNginxConfigMap(objects, *nginxConfigMaps)
+	if err != nil {
+		nl.Error(l, err)
+		return 2
+	}
+
+	dir := *outputDir
+	if dir == "" {
+		dir, err = os.MkdirTemp("", "nginx-ingress-render-")
+		if err != nil {
+			nl.Errorf(l, "Error creating a temporary directory: %v", err)
+			return 2
+		}
+		defer os.RemoveAll(dir)
+	}
+
+	versionLine := *renderNginxVersion
+	if versionLine == "" {
+		versionLine = defaultRenderNginxVersion
+		if *nginxPlus {
+			versionLine = defaultRenderNginxPlusVersion
+		}
+	}
+	nginxManager := nginx.NewRenderManager(l, "/etc/nginx", dir, nginx.NewVersion(versionLine))
+
+	recorder := k8s.NewRenderEventRecorder()
+	templateExecutor, templateExecutorV2 := createTemplateExecutors(ctx)
+
+	cfgParams := configs.NewDefaultConfigParams(ctx, *nginxPlus)
+	if configMap != nil {
+		cfgParams = mustApplyConfigMap(configMap, cfgParams, nginxManager, templateExecutor, recorder)
+	}
+
+	var mgmtCfgParams *configs.MGMTConfigParams
+	if *nginxPlus {
+		mgmtCfgParams = configs.NewDefaultMGMTConfigParams(ctx)
+	}
+
+	// The default server secret is not rendered, so the default server rejects TLS handshakes.
+	staticCfgParams := createStaticConfigParams(nginxManager.Version(), nginxManager.GetSecretsDir(), true, false, appProtectv4BundleFolder)
+	mustWriteNginxMainConfig(staticCfgParams, cfgParams, mgmtCfgParams, templateExecutor, nginxManager)
+
+	if *enableTLSPassthrough {
+		var emptyFile []byte
+		nginxManager.CreateTLSPassthroughHostsConfig(emptyFile)
+	}
+
+	cnf := configs.NewConfigurator(configs.ConfiguratorParams{
+		NginxManager:                        nginxManager,
+		StaticCfgParams:                     staticCfgParams,
+		Config:                              cfgParams,
+		MGMTCfgParams:                       mgmtCfgParams,
+		TemplateExecutor:                    templateExecutor,
+		TemplateExecutorV2:                  templateExecutorV2,
+		IsPlus:                              *nginxPlus,
+		IsWildcardEnabled:                   false,
+		IsPrometheusEnabled:                 *enablePrometheusMetrics,
+		IsLatencyMetricsEnabled:             *enableLatencyMetrics,
+		IsDynamicSSLReloadEnabled:           *enableDynamicSSLReload,
+		IsDynamicWeightChangesReloadEnabled: *enableDynamicWeightChangesReload,
+		NginxVersion:                        nginxManager.Version(),
+	})
+
+	virtualServerValidator, transportServerValidator := createCustomResourceValidators()
+
+	result, err := k8s.Render(k8s.RenderInput{
+		LoggerContext:                ctx,
+		NginxConfigurator:            cnf,
+		Recorder:                     recorder,
+		IsNginxPlus:                  *nginxPlus,
+		IngressClass:                 *ingressClass,
+		EnableOIDC:                   *enableOIDC,
+		InternalRoutesEnabled:        *enableInternalRoutes,
+		IsTLSPassthroughEnabled:      *enableTLSPassthrough,
+		SnippetsEnabled:              *enableSnippets,
+		IsIPV6Disabled:               *disableIPV6,
+		GlobalConfigurationValidator: createGlobalConfigurationValidator(),
+		TransportServerValidator:     transportServerValidator,
+		VirtualServerValidator:       virtualServerValidator,
+		Objects:                      objects,
+	})
+	if err != nil {
+		nl.Errorf(l, "Error rendering the configuration: %v", err)
+		return 2
+	}
+
+	if err := printRenderedFiles(out, dir, nginxManager.GetSecretsDir()); err != nil {
+		nl.Errorf(l, "Error printing the configuration: %v", err)
+		return 2
+	}
+
+	if printRenderDiagnostics(errOut, recorder.Events(), result.Problems) {
+		return 1
+	}
+	return 0
+}
+
+// loadManifests decodes the resources of the manifest files. Directories are searched for .yaml, .yml and .json files.
+// Resources without a namespace are put in the default namespace.
+func loadManifests(paths []string) ([]pkg_runtime.Object, error) {
+	sch := pkg_runtime.NewScheme()
+	if err := scheme.AddToScheme(sch); err != nil {
+		return nil, err
+	}
+	if err := conf_scheme.AddToScheme(sch); err != nil {
+		return nil, err
+	}
+	decoder := serializer.NewCodecFactory(sch).UniversalDeserializer()
+
+	var files []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, p)
+			continue
+		}
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			switch filepath.Ext(path) {
+			case ".yaml", ".yml", ".json":
+				if !d.IsDir() {
+					files = append(files, path)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var objects []pkg_runtime.Object
+	for _, file := range files {
+		objs, err := decodeManifest(decoder, file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		objects = append(objects, objs...)
+	}
+	return objects, nil
+}
+
+func decodeManifest(decoder pkg_runtime.Decoder, file string) ([]pkg_runtime.Object, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var objects []pkg_runtime.Object
+	reader := yaml.NewYAMLReader(bufio.NewReader(f))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		objs, err := decodeObjects(decoder, doc)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, objs...)
+	}
+}
+
+func decodeObjects(decoder pkg_runtime.Decoder, doc []byte) ([]pkg_runtime.Object, error) {
+	obj, _, err := decoder.Decode(doc, nil, nil)
+	if err != nil {
+		if pkg_runtime.IsMissingKind(err) {
+			// an empty document, for example with only comments
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if list, ok := obj.(*api_v1.List); ok {
+		var objects []pkg_runtime.Object
+		for _, item := range list.Items {
+			objs, err := decodeObjects(decoder, item.Raw)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, objs...)
+		}
+		return objects, nil
+	}
+
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	if m.GetNamespace() == "" {
+		m.SetNamespace(api_v1.NamespaceDefault)
+	}
+	return []pkg_runtime.Object{obj}, nil
+}
+
+// extractNginxConfigMap removes the ConfigMaps from the objects and returns the NGINX ConfigMap.
+// If the nginx-configmaps flag is set, the ConfigMap it names is the NGINX ConfigMap, otherwise
+// the only ConfigMap of the manifests is used.
+func extractNginxConfigMap(objects []pkg_runtime.Object, name string) (*api_v1.ConfigMap, []pkg_runtime.Object, error) {
+	var configMaps []*api_v1.ConfigMap
+	var rest []pkg_runtime.Object
+	for _, obj := range objects {
+		if cm, ok := obj.(*api_v1.ConfigMap); ok {
+			configMaps = append(configMaps, cm)
+			continue
+		}
+		rest = append(rest, obj)
+	}
+
+	if name != "" {
+		for _, cm := range configMaps {
+			if cm.Namespace+"/"+cm.Name == name {
+				return cm, rest, nil
+			}
+		}
+		return nil, nil, fmt.Errorf("ConfigMap %s of the nginx-configmaps argument not found in the manifests", name)
+	}
+
+	switch len(configMaps) {
+	case 0:
+		return nil, rest, nil
+	case 1:
+		return configMaps[0], rest, nil
+	default:
+		return nil, nil, fmt.Errorf("found %d ConfigMaps in the manifests, use -nginx-configmaps to choose the NGINX ConfigMap", len(configMaps))
+	}
+}
+
+// printRenderedFiles prints the files below dir, except for the secrets.
+func printRenderedFiles(out io.Writer, dir string, secretsDir string) error {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if filepath.ToSlash(strings.TrimPrefix(path, dir)) == secretsDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "# %s\n%s\n", filepath.ToSlash(strings.TrimPrefix(file, dir)), content)
+	}
+	return nil
+}
+
+// printRenderDiagnostics prints the warning events and the problems of the resources.
+// It returns true if the configuration of any resource was rejected or couldn't be generated.
+func printRenderDiagnostics(out io.Writer, events []k8s.RenderEvent, problems []k8s.ConfigurationProblem) bool {
+	failed := false
+	for _, e := range events {
+		if e.Type != api_v1.EventTypeWarning {
+			continue
+		}
+		if strings.HasSuffix(e.Reason, "WithError") {
+			failed = true
+		}
+		fmt.Fprintf(out, "%s %s: %s: %s\n", e.Type, describeObject(e.Object), e.Reason, e.Message)
+	}
+	for _, p := range problems {
+		if p.IsError {
+			failed = true
+		}
+		fmt.Fprintf(out, "%s %s: %s: %s\n", api_v1.EventTypeWarning, describeObject(p.Object), p.Reason, p.Message)
+	}
+	return failed
+}
+
+func describeObject(obj pkg_runtime.Object) string {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		kind = strings.TrimPrefix(fmt.Sprintf("%T", obj), "*")
+	}
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return kind
+	}
+	return fmt.Sprintf("%s %s/%s", kind, m.GetNamespace(), m.GetName())
+}
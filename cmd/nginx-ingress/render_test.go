@@ -0,0 +1,147 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	pkg_runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+const renderTestManifest = `# the cafe application
+apiVersion: k8s.nginx.org/v1
+kind: VirtualServer
+metadata:
+  name: cafe
+spec:
+  host: cafe.example.com
+---
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: coffee-svc
+    namespace: cafe
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: nginx-config
+    namespace: nginx-ingress
+`
+
+func TestLoadManifests(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "cafe.yaml"), []byte(renderTestManifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a manifest"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	objects, err := loadManifests([]string{dir})
+	if err != nil {
+		t.Fatalf("loadManifests() returned unexpected error: %v", err)
+	}
+	if len(objects) != 3 {
+		t.Fatalf("loadManifests() returned %d objects, want 3", len(objects))
+	}
+
+	vs, ok := objects[0].(*conf_v1.VirtualServer)
+	if !ok || vs.Namespace != "default" || vs.Spec.Host != "cafe.example.com" {
+		t.Errorf("loadManifests() returned unexpected first object %+v", objects[0])
+	}
+	svc, ok := objects[1].(*api_v1.Service)
+	if !ok || svc.Namespace != "cafe" || svc.Name != "coffee-svc" {
+		t.Errorf("loadManifests() returned unexpected second object %+v", objects[1])
+	}
+	if _, ok := objects[2].(*api_v1.ConfigMap); !ok {
+		t.Errorf("loadManifests() returned unexpected third object %+v", objects[2])
+	}
+}
+
+func TestLoadManifests_FailsOnInvalidManifest(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "invalid.yaml")
+	if err := os.WriteFile(file, []byte("apiVersion: example.com/v1\nkind: Unknown\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loadManifests([]string{file}); err == nil {
+		t.Error("loadManifests() returned no error for an unknown kind")
+	}
+}
+
+func TestExtractNginxConfigMap(t *testing.T) {
+	t.Parallel()
+
+	newConfigMap := func(namespace, name string) *api_v1.ConfigMap {
+		return &api_v1.ConfigMap{ObjectMeta: meta_v1.ObjectMeta{Namespace: namespace, Name: name}}
+	}
+	vs := &conf_v1.VirtualServer{}
+	nginxConfig := newConfigMap("nginx-ingress", "nginx-config")
+	other := newConfigMap("default", "other")
+
+	tests := []struct {
+		name          string
+		objects       []pkg_runtime.Object
+		flag          string
+		wantConfigMap *api_v1.ConfigMap
+		wantErr       bool
+	}{
+		{
+			name:    "no ConfigMap",
+			objects: []pkg_runtime.Object{vs},
+		},
+		{
+			name:          "the only ConfigMap",
+			objects:       []pkg_runtime.Object{vs, nginxConfig},
+			wantConfigMap: nginxConfig,
+		},
+		{
+			name:          "the ConfigMap of the flag",
+			objects:       []pkg_runtime.Object{other, vs, nginxConfig},
+			flag:          "nginx-ingress/nginx-config",
+			wantConfigMap: nginxConfig,
+		},
+		{
+			name:    "several ConfigMaps without the flag",
+			objects: []pkg_runtime.Object{other, vs, nginxConfig},
+			wantErr: true,
+		},
+		{
+			name:    "the ConfigMap of the flag is missing",
+			objects: []pkg_runtime.Object{other, vs},
+			flag:    "nginx-ingress/nginx-config",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		configMap, objects, err := extractNginxConfigMap(test.objects, test.flag)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("extractNginxConfigMap() returned no error for the case of %s", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("extractNginxConfigMap() returned unexpected error %v for the case of %s", err, test.name)
+			continue
+		}
+		if configMap != test.wantConfigMap {
+			t.Errorf("extractNginxConfigMap() returned ConfigMap %v, want %v for the case of %s", configMap, test.wantConfigMap, test.name)
+		}
+		if len(objects) != 1 || objects[0] != vs {
+			t.Errorf("extractNginxConfigMap() returned objects %v, want only the VirtualServer for the case of %s", objects, test.name)
+		}
+	}
+}
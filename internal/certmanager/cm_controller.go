@@ -12,7 +12,7 @@ limitations under the License.
 */
 
 // Package certmanager provides a controller for creating and managing
-// certificates for VS resources.
+// certificates for VS and TS resources.
 package certmanager
 
 import (
@@ -38,7 +38,6 @@ import (
 	"k8s.io/client-go/util/workqueue"
 
 	nl "github.com/nginx/kubernetes-ingress/internal/logger"
-	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
 	k8s_nginx "github.com/nginx/kubernetes-ingress/pkg/client/clientset/versioned"
 	vsinformers "github.com/nginx/kubernetes-ingress/pkg/client/informers/externalversions"
 	listers_v1 "github.com/nginx/kubernetes-ingress/pkg/client/listers/configuration/v1"
@@ -56,11 +55,12 @@ const (
 	resyncPeriod = 10 * time.Hour
 )
 
-// CmController watches certificate and virtual server resources,
-// and creates/ updates certificates for VS resources as required,
-// and VS resources when certificate objects are created/ updated
+// CmController watches certificate, virtual server and transport server resources,
+// and creates/ updates certificates for VS and TS resources as required,
+// and VS and TS resources when certificate objects are created/ updated
 type CmController struct {
 	sync          SyncFn
+	tsSync        TransportServerSyncFn
 	ctx           context.Context
 	queue         workqueue.TypedRateLimitingInterface[types.NamespacedName]
 	informerGroup map[string]*namespacedInformer
@@ -87,6 +87,7 @@ type namespacedInformer struct {
 	cmSharedInformerFactory   cm_informers.SharedInformerFactory
 	kubeSharedInformerFactory kubeinformers.SharedInformerFactory
 	vsLister                  listers_v1.VirtualServerLister
+	tsLister                  listers_v1.TransportServerLister
 	cmLister                  cmlisters.CertificateLister
 	stopCh                    chan struct{}
 	lock                      sync.RWMutex
@@ -94,6 +95,7 @@ type namespacedInformer struct {
 
 func (c *CmController) register() workqueue.TypedRateLimitingInterface[types.NamespacedName] {
 	c.sync = SyncFnFor(c.recorder, c.cmClient, c.informerGroup)
+	c.tsSync = TransportServerSyncFnFor(c.recorder, c.cmClient, c.informerGroup)
 	return c.queue
 }
 
@@ -130,6 +132,12 @@ func (c *CmController) addHandlers(nsi *namespacedInformer) {
 	})
 	nsi.mustSync = append(nsi.mustSync, nsi.vsSharedInformerFactory.K8s().V1().VirtualServers().Informer().HasSynced)
 
+	nsi.tsLister = nsi.vsSharedInformerFactory.K8s().V1().TransportServers().Lister()
+	nsi.vsSharedInformerFactory.K8s().V1().TransportServers().Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{
+		Queue: c.queue,
+	})
+	nsi.mustSync = append(nsi.mustSync, nsi.vsSharedInformerFactory.K8s().V1().TransportServers().Informer().HasSynced)
+
 	nsi.cmSharedInformerFactory.Certmanager().V1().Certificates().Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
 		WorkFunc: certificateHandler(c.queue),
 	})
@@ -137,18 +145,29 @@ func (c *CmController) addHandlers(nsi *namespacedInformer) {
 	nsi.mustSync = append(nsi.mustSync, nsi.cmSharedInformerFactory.Certmanager().V1().Certificates().Informer().HasSynced)
 }
 
+// processItem reconciles the VirtualServer and the TransportServer with the name of the key.
+// The queue doesn't distinguish between the two kinds, so both are reconciled if they exist.
 func (c *CmController) processItem(ctx context.Context, key types.NamespacedName) error {
 	l := nl.LoggerFromContext(ctx)
-	nl.Debugf(l, "processing virtual server resource ")
+	nl.Debugf(l, "processing virtual server and transport server resources")
 	namespace := key.Namespace
 	name := key.Name
 
 	nsi := getNamespacedInformer(namespace, c.informerGroup)
 
-	var vs *conf_v1.VirtualServer
 	vs, err := nsi.vsLister.VirtualServers(namespace).Get(name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		if err := c.sync(ctx, vs); err != nil {
+			return err
+		}
+	}
+
+	ts, err := nsi.tsLister.TransportServers(namespace).Get(name)
 
-	// VS has been deleted
+	// TS has been deleted or never existed
 	if apierrors.IsNotFound(err) {
 		return nil
 	}
@@ -156,11 +175,11 @@ func (c *CmController) processItem(ctx context.Context, key types.NamespacedName
 	if err != nil {
 		return err
 	}
-	return c.sync(ctx, vs)
+	return c.tsSync(ctx, ts)
 }
 
 // Whenever a Certificate gets updated, added or deleted, we want to reconcile
-// its parent VirtualServer. This parent VirtualServer is called "controller object". For
+// its parent VirtualServer or TransportServer. This parent is called "controller object". For
 // example, the following Certificate "cert-1" is controlled by the VirtualServer
 // "vs-1":
 //
@@ -190,9 +209,9 @@ func certificateHandler(queue workqueue.TypedRateLimitingInterface[types.Namespa
 		}
 
 		// We don't check the apiVersion
-		// because there is no chance that another object called "VirtualServer" be
-		// the controller of a Certificate.
-		if ref.Kind != "VirtualServer" {
+		// because there is no chance that another object called "VirtualServer" or
+		// "TransportServer" be the controller of a Certificate.
+		if ref.Kind != vsGVK.Kind && ref.Kind != tsGVK.Kind {
 			return
 		}
 
@@ -125,6 +125,29 @@ func Test_controller_Register(t *testing.T) {
 			},
 			expectRequeueKey: "namespace-1/vs-2",
 		},
+		{
+			name: "transportserver is re-queued when an 'Added' event is received for this transportserver",
+			givenCall: func(t *testing.T, _ cmclient.Interface, c k8s_nginx.Interface) {
+				_, err := c.K8sV1().TransportServers("namespace-1").Create(context.Background(), &vsapi.TransportServer{ObjectMeta: metav1.ObjectMeta{
+					Namespace: "namespace-1", Name: "ts-1",
+				}}, metav1.CreateOptions{})
+				require.NoError(t, err)
+			},
+			expectRequeueKey: "namespace-1/ts-1",
+		},
+		{
+			name: "transportserver is re-queued when an 'Added' event is received for its child Certificate",
+			givenCall: func(t *testing.T, c cmclient.Interface, _ k8s_nginx.Interface) {
+				_, err := c.CertmanagerV1().Certificates("namespace-1").Create(context.Background(), &cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{
+					Namespace: "namespace-1", Name: "cert-1",
+					OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&vsapi.TransportServer{ObjectMeta: metav1.ObjectMeta{
+						Namespace: "namespace-1", Name: "ts-2",
+					}}, tsGVK)},
+				}}, metav1.CreateOptions{})
+				require.NoError(t, err)
+			},
+			expectRequeueKey: "namespace-1/ts-2",
+		},
 	}
 
 	for _, test := range tests {
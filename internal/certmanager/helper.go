@@ -15,7 +15,7 @@ limitations under the License.
 */
 
 // Package certmanager provides a controller for creating and managing
-// certificates for VS resources.
+// certificates for VS and TS resources.
 package certmanager
 
 import (
@@ -15,7 +15,7 @@ limitations under the License.
 */
 
 // Package certmanager provides a controller for creating and managing
-// certificates for VS resources.
+// certificates for VS and TS resources.
 package certmanager
 
 import (
@@ -33,6 +33,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/record"
 
@@ -47,11 +48,52 @@ const (
 	reasonDeleteCertificate = "DeleteCertificate"
 )
 
-var vsGVK = vsapi.SchemeGroupVersion.WithKind("VirtualServer")
+var (
+	vsGVK = vsapi.SchemeGroupVersion.WithKind("VirtualServer")
+	tsGVK = vsapi.SchemeGroupVersion.WithKind("TransportServer")
+)
 
 // SyncFn is the reconciliation function passed to cert manager VS controller.
 type SyncFn func(context.Context, *vsapi.VirtualServer) error
 
+// TransportServerSyncFn is the reconciliation function passed to cert manager TS controller.
+type TransportServerSyncFn func(context.Context, *vsapi.TransportServer) error
+
+// ownerObject is a resource that can own a Certificate.
+type ownerObject interface {
+	metav1.Object
+	runtime.Object
+}
+
+// certificateOwner holds the TLS configuration of a resource that owns a Certificate.
+type certificateOwner struct {
+	obj         ownerObject
+	gvk         schema.GroupVersionKind
+	host        string
+	secret      string
+	certManager *vsapi.CertManager
+}
+
+func ownerForVirtualServer(vs *vsapi.VirtualServer) certificateOwner {
+	return certificateOwner{
+		obj:         vs,
+		gvk:         vsGVK,
+		host:        vs.Spec.Host,
+		secret:      vs.Spec.TLS.Secret,
+		certManager: vs.Spec.TLS.CertManager,
+	}
+}
+
+func ownerForTransportServer(ts *vsapi.TransportServer) certificateOwner {
+	return certificateOwner{
+		obj:         ts,
+		gvk:         tsGVK,
+		host:        ts.Spec.Host,
+		secret:      ts.Spec.TLS.Secret,
+		certManager: ts.Spec.TLS.CertManager,
+	}
+}
+
 // SyncFnFor contains logic to reconcile VirtualServer objects.
 //
 // Reconciling a VirtualServer object with respect to Certificates means looking at its annotations
@@ -63,75 +105,103 @@ func SyncFnFor(
 	ig map[string]*namespacedInformer,
 ) SyncFn {
 	return func(ctx context.Context, vs *vsapi.VirtualServer) error {
-		var err error
 		if vs.Spec.TLS == nil || vs.Spec.TLS.CertManager == nil {
 			return nil
 		}
-		l := nl.LoggerFromContext(ctx)
-		issuerName, issuerKind, issuerGroup, err := issuerForVirtualServer(vs)
-		if err != nil {
-			nl.Errorf(l, "Failed to determine issuer to be used for VirtualServer resource: %v", err)
-			rec.Eventf(vs, corev1.EventTypeWarning, reasonBadConfig, "Could not determine issuer for virtual server due to bad config: %s",
-				err)
-			return err
+		return syncCertificates(ctx, rec, cmClient, ig, ownerForVirtualServer(vs))
+	}
+}
+
+// TransportServerSyncFnFor contains logic to reconcile TransportServer objects.
+//
+// Reconciling a TransportServer object with respect to Certificates means creating a Certificate
+// with the host of the TransportServer as the DNS name and the secret of the TLS configuration as the secretName.
+func TransportServerSyncFnFor(
+	rec record.EventRecorder,
+	cmClient clientset.Interface,
+	ig map[string]*namespacedInformer,
+) TransportServerSyncFn {
+	return func(ctx context.Context, ts *vsapi.TransportServer) error {
+		if ts.Spec.TLS == nil || ts.Spec.TLS.CertManager == nil {
+			return nil
 		}
+		return syncCertificates(ctx, rec, cmClient, ig, ownerForTransportServer(ts))
+	}
+}
+
+func syncCertificates(
+	ctx context.Context,
+	rec record.EventRecorder,
+	cmClient clientset.Interface,
+	ig map[string]*namespacedInformer,
+	owner certificateOwner,
+) error {
+	var err error
+	l := nl.LoggerFromContext(ctx)
+	kind := owner.gvk.Kind
+	issuerName, issuerKind, issuerGroup, err := issuerFor(owner)
+	if err != nil {
+		nl.Errorf(l, "Failed to determine issuer to be used for %s resource: %v", kind, err)
+		rec.Eventf(owner.obj, corev1.EventTypeWarning, reasonBadConfig, "Could not determine issuer for %s due to bad config: %s",
+			kind, err)
+		return err
+	}
 
-		nsi := getNamespacedInformer(vs.GetNamespace(), ig)
+	nsi := getNamespacedInformer(owner.obj.GetNamespace(), ig)
 
-		newCrts, updateCrts, err := buildCertificates(ctx, nsi.cmLister, vs, issuerName, issuerKind, issuerGroup)
+	newCrts, updateCrts, err := buildCertificates(ctx, nsi.cmLister, owner, issuerName, issuerKind, issuerGroup)
+	if err != nil {
+		nl.Errorf(l, "Incorrect cert-manager configuration for %s resource: %v", kind, err)
+		rec.Eventf(owner.obj, corev1.EventTypeWarning, reasonBadConfig, "Incorrect cert-manager configuration for %s resource: %s",
+			kind, err)
+		return err
+	}
+
+	for _, crt := range newCrts {
+		_, err := cmClient.CertmanagerV1().Certificates(crt.Namespace).Create(ctx, crt, metav1.CreateOptions{})
 		if err != nil {
-			nl.Errorf(l, "Incorrect cert-manager configuration for VirtualServer resource: %v", err)
-			rec.Eventf(vs, corev1.EventTypeWarning, reasonBadConfig, "Incorrect cert-manager configuration for VirtualServer resource: %s",
-				err)
+			nl.Errorf(l, "Error issuing Certificate for %s resource: %v", kind, err)
+			rec.Eventf(owner.obj, corev1.EventTypeWarning, reasonBadConfig, "Error issuing Certificate for %s resource: %s",
+				kind, err)
 			return err
 		}
+		rec.Eventf(owner.obj, corev1.EventTypeNormal, reasonCreateCertificate, "Successfully created Certificate %q", crt.Name)
+	}
 
-		for _, crt := range newCrts {
-			_, err := cmClient.CertmanagerV1().Certificates(crt.Namespace).Create(ctx, crt, metav1.CreateOptions{})
-			if err != nil {
-				nl.Errorf(l, "Error issuing Certificate for VirtualServer resource: %v", err)
-				rec.Eventf(vs, corev1.EventTypeWarning, reasonBadConfig, "Error issuing Certificate for VirtualServer resource: %s",
-					err)
-				return err
-			}
-			rec.Eventf(vs, corev1.EventTypeNormal, reasonCreateCertificate, "Successfully created Certificate %q", crt.Name)
+	for _, crt := range updateCrts {
+		_, err := cmClient.CertmanagerV1().Certificates(crt.Namespace).Update(ctx, crt, metav1.UpdateOptions{})
+		if err != nil {
+			nl.Errorf(l, "Error updating Certificate for %s resource: %v", kind, err)
+			rec.Eventf(owner.obj, corev1.EventTypeWarning, reasonBadConfig, "Error updating Certificate for %s resource: %s",
+				kind, err)
+			return err
 		}
+		rec.Eventf(owner.obj, corev1.EventTypeNormal, reasonUpdateCertificate, "Successfully updated Certificate %q", crt.Name)
+	}
+	var certs []*cmapi.Certificate
 
-		for _, crt := range updateCrts {
-			_, err := cmClient.CertmanagerV1().Certificates(crt.Namespace).Update(ctx, crt, metav1.UpdateOptions{})
-			if err != nil {
-				nl.Errorf(l, "Error updating Certificate for VirtualServer resource: %v", err)
-				rec.Eventf(vs, corev1.EventTypeWarning, reasonBadConfig, "Error updating Certificate for VirtualServer resource: %s",
-					err)
-				return err
-			}
-			rec.Eventf(vs, corev1.EventTypeNormal, reasonUpdateCertificate, "Successfully updated Certificate %q", crt.Name)
-		}
-		var certs []*cmapi.Certificate
+	certs, err = nsi.cmLister.Certificates(owner.obj.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	unrequiredCertNames := findCertificatesToBeRemoved(certs, owner)
 
-		certs, err = nsi.cmLister.Certificates(vs.GetNamespace()).List(labels.Everything())
+	for _, certName := range unrequiredCertNames {
+		err = cmClient.CertmanagerV1().Certificates(owner.obj.GetNamespace()).Delete(ctx, certName, metav1.DeleteOptions{})
 		if err != nil {
+			nl.Errorf(l, "Error deleting Certificate for %s resource: %v", kind, err)
 			return err
 		}
-		unrequiredCertNames := findCertificatesToBeRemoved(certs, vs)
-
-		for _, certName := range unrequiredCertNames {
-			err = cmClient.CertmanagerV1().Certificates(vs.GetNamespace()).Delete(ctx, certName, metav1.DeleteOptions{})
-			if err != nil {
-				nl.Errorf(l, "Error deleting Certificate for VirtualServer resource: %v", err)
-				return err
-			}
-			rec.Eventf(vs, corev1.EventTypeNormal, reasonDeleteCertificate, "Successfully deleted unrequired Certificate %q", certName)
-		}
-
-		return nil
+		rec.Eventf(owner.obj, corev1.EventTypeNormal, reasonDeleteCertificate, "Successfully deleted unrequired Certificate %q", certName)
 	}
+
+	return nil
 }
 
 func buildCertificates(
 	ctx context.Context,
 	cmLister cmlisters.CertificateLister,
-	vs *vsapi.VirtualServer,
+	owner certificateOwner,
 	issuerName, issuerKind, issuerGroup string,
 ) (newCert, update []*cmapi.Certificate, _ error) {
 	var newCrts []*cmapi.Certificate
@@ -139,25 +209,24 @@ func buildCertificates(
 	var existingCrt *cmapi.Certificate
 	var err error
 
-	existingCrt, err = cmLister.Certificates(vs.Namespace).Get(vs.Spec.TLS.Secret)
+	existingCrt, err = cmLister.Certificates(owner.obj.GetNamespace()).Get(owner.secret)
 
 	if !apierrors.IsNotFound(err) && err != nil {
 		return nil, nil, err
 	}
 
-	var controllerGVK schema.GroupVersionKind = vsGVK
-	hosts := []string{vs.Spec.Host}
+	hosts := []string{owner.host}
 
 	crt := &cmapi.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:            vs.Spec.TLS.Secret,
-			Namespace:       vs.Namespace,
-			Labels:          vs.Labels,
-			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(vs, controllerGVK)},
+			Name:            owner.secret,
+			Namespace:       owner.obj.GetNamespace(),
+			Labels:          owner.obj.GetLabels(),
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(owner.obj, owner.gvk)},
 		},
 		Spec: cmapi.CertificateSpec{
 			DNSNames:   hosts,
-			SecretName: vs.Spec.TLS.Secret,
+			SecretName: owner.secret,
 			IssuerRef: cmmeta.ObjectReference{
 				Name:  issuerName,
 				Kind:  issuerKind,
@@ -169,9 +238,7 @@ func buildCertificates(
 
 	l := nl.LoggerFromContext(ctx)
 
-	vs = vs.DeepCopy()
-
-	if err := translateVsSpec(crt, vs.Spec.TLS.CertManager); err != nil {
+	if err := translateVsSpec(crt, owner.certManager.DeepCopy()); err != nil {
 		return nil, nil, err
 	}
 
@@ -185,7 +252,7 @@ func buildCertificates(
 			return nil, nil, nil
 		}
 
-		if !metav1.IsControlledBy(existingCrt, vs) {
+		if !metav1.IsControlledBy(existingCrt, owner.obj) {
 			nl.Debugf(l, "certificate resource is not owned by this object. refusing to update non-owned certificate resource for object")
 			return nil, nil, nil
 		}
@@ -207,23 +274,19 @@ func buildCertificates(
 	return newCrts, updateCrts, nil
 }
 
-func findCertificatesToBeRemoved(certs []*cmapi.Certificate, vs *vsapi.VirtualServer) []string {
+func findCertificatesToBeRemoved(certs []*cmapi.Certificate, owner certificateOwner) []string {
 	var toBeRemoved []string
 	for _, crt := range certs {
-		if !metav1.IsControlledBy(crt, vs) {
+		if !metav1.IsControlledBy(crt, owner.obj) {
 			continue
 		}
-		if !secretNameUsedIn(crt.Spec.SecretName, *vs) {
+		if crt.Spec.SecretName != owner.secret {
 			toBeRemoved = append(toBeRemoved, crt.Name)
 		}
 	}
 	return toBeRemoved
 }
 
-func secretNameUsedIn(secretName string, vs vsapi.VirtualServer) bool {
-	return secretName == vs.Spec.TLS.Secret
-}
-
 // certNeedsUpdate checks and returns true if two Certificates differ.
 func certNeedsUpdate(a, b *cmapi.Certificate) bool {
 	if a.Name != b.Name {
@@ -267,17 +330,17 @@ func certNeedsUpdate(a, b *cmapi.Certificate) bool {
 	return false
 }
 
-// issuerForVirtualServer determines the Issuer that should be specified on a
-// Certificate created for the given VirtualServer resource. We look up the following
-// VS TLS Cert-Manager fields:
+// issuerFor determines the Issuer that should be specified on a
+// Certificate created for the given VirtualServer or TransportServer resource. We look up the following
+// TLS Cert-Manager fields:
 //
 //	cluster-issuer
 //	issuer
 //	issuer-kind
 //	issuer-group
-func issuerForVirtualServer(vs *vsapi.VirtualServer) (name, kind, group string, err error) {
+func issuerFor(owner certificateOwner) (name, kind, group string, err error) {
 	var errs []string
-	vsCmSpec := vs.Spec.TLS.CertManager
+	vsCmSpec := owner.certManager
 	var issuerNameOK, clusterIssuerNameOK, groupNameOK, kindNameOK bool
 
 	if vsCmSpec.Issuer != "" {
@@ -299,7 +362,7 @@ func issuerForVirtualServer(vs *vsapi.VirtualServer) (name, kind, group string,
 	}
 
 	if len(name) == 0 {
-		errs = append(errs, "failed to determine Issuer name to be used for VirtualServer resource")
+		errs = append(errs, fmt.Sprintf("failed to determine Issuer name to be used for %s resource", owner.gvk.Kind))
 	}
 
 	if issuerNameOK && clusterIssuerNameOK {
@@ -40,6 +40,7 @@ func TestSync(t *testing.T) {
 	type testT struct {
 		Name                string
 		VirtualServer       vsapi.VirtualServer
+		TransportServer     *vsapi.TransportServer
 		Issuer              cmapi.GenericIssuer
 		IssuerLister        []runtime.Object
 		ClusterIssuerLister []runtime.Object
@@ -449,6 +450,98 @@ func TestSync(t *testing.T) {
 		},
 	}
 
+	testTsShim := []testT{
+		{
+			Name:   "return a single Certificate for a transport server with a valid TLS entry",
+			Issuer: issuer,
+			TransportServer: buildTransportServer("ts-name", gen.DefaultTestNamespace, "secret-name", vsapi.CertManager{
+				Issuer: "issuer-name",
+			}),
+			IssuerLister:   []runtime.Object{issuer},
+			ExpectedEvents: []string{`Normal CreateCertificate Successfully created Certificate "secret-name"`},
+			ExpectedCreate: []*cmapi.Certificate{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "secret-name",
+						Namespace:       gen.DefaultTestNamespace,
+						OwnerReferences: buildTsOwnerReferences("ts-name", gen.DefaultTestNamespace, "secret-name"),
+					},
+					Spec: cmapi.CertificateSpec{
+						DNSNames:   []string{"tcp.example.com"},
+						SecretName: "secret-name",
+						IssuerRef: cmmeta.ObjectReference{
+							Name: "issuer-name",
+							Kind: "Issuer",
+						},
+						Usages: cmapi.DefaultKeyUsages(),
+					},
+				},
+			},
+		},
+		{
+			Name:   "remove the Certificate of a transport server when its TLS secret changes",
+			Issuer: issuer,
+			TransportServer: buildTransportServer("ts-name", gen.DefaultTestNamespace, "new-secret", vsapi.CertManager{
+				Issuer: "issuer-name",
+			}),
+			IssuerLister: []runtime.Object{issuer},
+			CertificateLister: []runtime.Object{
+				buildCertificate("old-secret", gen.DefaultTestNamespace, buildTsOwnerReferences("ts-name", gen.DefaultTestNamespace, "old-secret")),
+			},
+			ExpectedEvents: []string{
+				`Normal CreateCertificate Successfully created Certificate "new-secret"`,
+				`Normal DeleteCertificate Successfully deleted unrequired Certificate "old-secret"`,
+			},
+			ExpectedCreate: []*cmapi.Certificate{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "new-secret",
+						Namespace:       gen.DefaultTestNamespace,
+						OwnerReferences: buildTsOwnerReferences("ts-name", gen.DefaultTestNamespace, "new-secret"),
+					},
+					Spec: cmapi.CertificateSpec{
+						DNSNames:   []string{"tcp.example.com"},
+						SecretName: "new-secret",
+						IssuerRef: cmmeta.ObjectReference{
+							Name: "issuer-name",
+							Kind: "Issuer",
+						},
+						Usages: cmapi.DefaultKeyUsages(),
+					},
+				},
+			},
+			ExpectedDelete: []*cmapi.Certificate{
+				buildCertificate("old-secret", gen.DefaultTestNamespace, nil),
+			},
+		},
+		{
+			Name:   "fail if a transport server doesn't specify an issuer",
+			Issuer: issuer,
+			TransportServer: buildTransportServer("ts-name", gen.DefaultTestNamespace, "secret-name", vsapi.CertManager{
+				CommonName: "my-cn",
+			}),
+			Err:            true,
+			ExpectedEvents: []string{`Warning BadConfig Could not determine issuer for TransportServer due to bad config: failed to determine Issuer name to be used for TransportServer resource`},
+		},
+		{
+			Name:   "No CM block specified for a transport server",
+			Issuer: issuer,
+			TransportServer: &vsapi.TransportServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ts-name",
+					Namespace: gen.DefaultTestNamespace,
+					UID:       types.UID("ts-name"),
+				},
+				Spec: vsapi.TransportServerSpec{
+					Host: "tcp.example.com",
+					TLS: &vsapi.TransportServerTLS{
+						Secret: "secret-name",
+					},
+				},
+			},
+		},
+	}
+
 	testFn := func(test testT) func(t *testing.T) {
 		return func(t *testing.T) {
 			var allCMObjects []runtime.Object
@@ -503,9 +596,15 @@ func TestSync(t *testing.T) {
 			ig[""] = nsi
 
 			sync := SyncFnFor(b.Recorder, b.CMClient, ig)
+			tsSync := TransportServerSyncFnFor(b.Recorder, b.CMClient, ig)
 			b.Start()
 
-			err := sync(context.Background(), &test.VirtualServer)
+			var err error
+			if test.TransportServer != nil {
+				err = tsSync(context.Background(), test.TransportServer)
+			} else {
+				err = sync(context.Background(), &test.VirtualServer)
+			}
 
 			// If test.Err == true, err should not be nil and vice versa
 			if test.Err == (err == nil) {
@@ -528,6 +627,11 @@ func TestSync(t *testing.T) {
 			t.Run(test.Name, testFn(test))
 		}
 	})
+	t.Run("ts-shim", func(t *testing.T) {
+		for _, test := range testTsShim {
+			t.Run(test.Name, testFn(test))
+		}
+	})
 }
 
 func TestIssuerForVirtualServer(t *testing.T) {
@@ -572,7 +676,7 @@ func TestIssuerForVirtualServer(t *testing.T) {
 		},
 	}
 	for _, test := range tests {
-		name, kind, group, err := issuerForVirtualServer(test.VirtualServer)
+		name, kind, group, err := issuerFor(ownerForVirtualServer(test.VirtualServer))
 		if err != nil {
 			if test.ExpectedError == nil || err.Error() != test.ExpectedError.Error() {
 				t.Errorf("unexpected error, exp=%v got=%s", test.ExpectedError, err)
@@ -631,6 +735,29 @@ func buildVsOwnerReferences(name, namespace string, secretName string) []metav1.
 	}
 }
 
+func buildTransportServer(name string, namespace string, secretName string, tsCmSpec vsapi.CertManager) *vsapi.TransportServer {
+	return &vsapi.TransportServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       types.UID(name),
+		},
+		Spec: vsapi.TransportServerSpec{
+			Host: "tcp.example.com",
+			TLS: &vsapi.TransportServerTLS{
+				Secret:      secretName,
+				CertManager: &tsCmSpec,
+			},
+		},
+	}
+}
+
+func buildTsOwnerReferences(name, namespace string, secretName string) []metav1.OwnerReference {
+	return []metav1.OwnerReference{
+		*metav1.NewControllerRef(buildTransportServer(name, namespace, secretName, vsapi.CertManager{}), tsGVK),
+	}
+}
+
 func Test_findCertificatesToBeRemoved(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -698,7 +825,7 @@ func Test_findCertificatesToBeRemoved(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			gotCerts := findCertificatesToBeRemoved(test.givenCerts, test.virtualServer)
+			gotCerts := findCertificatesToBeRemoved(test.givenCerts, ownerForVirtualServer(test.virtualServer))
 			assert.Equal(t, test.wantToBeRemoved, gotCerts)
 		})
 	}
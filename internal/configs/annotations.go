@@ -59,6 +59,8 @@ var minionDenylist = map[string]bool{
 	"nginx.org/listen-ports":                            true,
 	"nginx.org/listen-ports-ssl":                        true,
 	"nginx.org/server-snippets":                         true,
+	"nginx.org/access-log-destination":                  true,
+	"nginx.org/access-log-format":                       true,
 	"appprotect.f5.com/app_protect_enable":              true,
 	"appprotect.f5.com/app_protect_policy":              true,
 	"appprotect.f5.com/app_protect_security_log_enable": true,
@@ -188,6 +190,22 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 		cfgParams.ServerSnippets = serverSnippets
 	}
 
+	if accessLogDestination, exists := ingEx.Ingress.Annotations["nginx.org/access-log-destination"]; exists {
+		if parsedAccessLogDestination, err := ParseAccessLogDestination(accessLogDestination); err != nil {
+			nl.Errorf(l, "Ingress %s/%s: Invalid value nginx.org/access-log-destination: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), accessLogDestination, err)
+		} else {
+			cfgParams.AccessLogDestination = parsedAccessLogDestination
+		}
+	}
+
+	if accessLogFormat, exists := ingEx.Ingress.Annotations["nginx.org/access-log-format"]; exists {
+		if parsedAccessLogFormat, err := ParseLogFormatName(accessLogFormat); err != nil {
+			nl.Errorf(l, "Ingress %s/%s: Invalid value nginx.org/access-log-format: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), accessLogFormat, err)
+		} else {
+			cfgParams.AccessLogFormat = parsedAccessLogFormat
+		}
+	}
+
 	if locationSnippets, exists := GetMapKeyAsStringSlice(ingEx.Ingress.Annotations, "nginx.org/location-snippets", ingEx.Ingress, "\n"); exists {
 		cfgParams.LocationSnippets = locationSnippets
 	}
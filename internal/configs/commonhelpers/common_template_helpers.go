@@ -22,3 +22,11 @@ func MakeOnOffFromBool(b *bool) string {
 
 	return "on"
 }
+
+var routePathReplacer = strings.NewReplacer(`$`, "", `"`, "", `\`, "")
+
+// MakeRoutePath will return the path of a route as the value of the $route_path variable
+// with the characters that NGINX interprets in a quoted string removed
+func MakeRoutePath(path string) string {
+	return routePathReplacer.Replace(path)
+}
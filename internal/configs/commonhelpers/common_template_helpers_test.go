@@ -53,6 +53,35 @@ func TestMakeSecretPath(t *testing.T) {
 	}
 }
 
+func TestMakeRoutePath(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{
+			path:     "/coffee",
+			expected: "/coffee",
+		},
+		{
+			path:     "=/exact/match",
+			expected: "=/exact/match",
+		},
+		{
+			path:     `~* "^/tea/[A-Z0-9]{3}\.jpg$"`,
+			expected: "~* ^/tea/[A-Z0-9]{3}.jpg",
+		},
+	}
+
+	for _, tc := range testCases {
+		got := MakeRoutePath(tc.path)
+		if got != tc.expected {
+			t.Errorf("MakeRoutePath(%q) returned %q, expected %q", tc.path, got, tc.expected)
+		}
+	}
+}
+
 func newMakeSecretPathTemplate(t *testing.T) *template.Template {
 	t.Helper()
 	tmpl, err := template.New("testTemplate").Funcs(helperFunctions).Parse(`{{makeSecretPath .Secret .Path .Variable .Enabled}}`)
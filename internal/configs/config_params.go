@@ -3,6 +3,7 @@ package configs
 import (
 	"context"
 
+	"github.com/nginx/kubernetes-ingress/internal/configs/version1"
 	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
 	"github.com/nginx/kubernetes-ingress/internal/nginx"
 )
@@ -11,6 +12,8 @@ import (
 // as well as configs for Ingress resources.
 type ConfigParams struct {
 	Context                                context.Context
+	AccessLogDestination                   string
+	AccessLogFormat                        string
 	ClientMaxBodySize                      string
 	DefaultServerAccessLogOff              bool
 	DefaultServerReturn                    string
@@ -33,11 +36,18 @@ type ConfigParams struct {
 	MainKeepaliveTimeout                   string
 	MainLogFormat                          []string
 	MainLogFormatEscaping                  string
+	MainNamedLogFormats                    []version1.NamedLogFormat
 	MainMainSnippets                       []string
 	MainOpenTracingEnabled                 bool
 	MainOpenTracingLoadModule              bool
 	MainOpenTracingTracer                  string
 	MainOpenTracingTracerConfig            string
+	MainOtelLoadModule                     bool
+	MainOtelExporterEndpoint               string
+	MainOtelServiceName                    string
+	MainOtelResourceAttributes             []version1.OtelAttribute
+	MainOtelSamplerRatio                   float64
+	MainOtelTraceInHTTP                    bool
 	MainServerNamesHashBucketSize          string
 	MainServerNamesHashMaxSize             string
 	MainStreamLogFormat                    []string
@@ -238,6 +248,7 @@ func NewDefaultConfigParams(ctx context.Context, isPlus bool) *ConfigParams {
 		LimitReqZoneSize:              "10m",
 		LimitReqLogLevel:              "error",
 		LimitReqRejectCode:            429,
+		MainOtelSamplerRatio:          1,
 	}
 }
 
@@ -4,6 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -291,6 +295,18 @@ func ParseConfigMap(ctx context.Context, cfgm *v1.ConfigMap, nginxPlus bool, has
 		}
 	}
 
+	if logFormats, exists := cfgm.Data["log-formats"]; exists {
+		namedLogFormats, err := parseNamedLogFormats(logFormats)
+		if err != nil {
+			errorText := fmt.Sprintf("ConfigMap %s/%s: invalid value for 'log-formats': %v, ignoring", cfgm.GetNamespace(), cfgm.GetName(), err)
+			nl.Error(l, errorText)
+			eventLog.Event(cfgm, v1.EventTypeWarning, invalidValueReason, errorText)
+			configOk = false
+		} else {
+			cfgParams.MainNamedLogFormats = namedLogFormats
+		}
+	}
+
 	if streamLogFormat, exists := GetMapKeyAsStringSlice(cfgm.Data, "stream-log-format", cfgm, "\n"); exists {
 		cfgParams.MainStreamLogFormat = streamLogFormat
 	}
@@ -549,6 +565,72 @@ func ParseConfigMap(ctx context.Context, cfgm *v1.ConfigMap, nginxPlus bool, has
 		}
 	}
 
+	if otelExporterEndpoint, exists := cfgm.Data["otel-exporter-endpoint"]; exists {
+		endpoint := strings.TrimSpace(otelExporterEndpoint)
+		if err := validation.ValidateHost(endpoint); err != nil {
+			errorText := fmt.Sprintf("ConfigMap %s/%s: invalid value for 'otel-exporter-endpoint': %q: %v, OpenTelemetry will be disabled, ignoring", cfgm.GetNamespace(), cfgm.GetName(), endpoint, err)
+			nl.Error(l, errorText)
+			eventLog.Event(cfgm, v1.EventTypeWarning, invalidValueReason, errorText)
+			configOk = false
+		} else {
+			cfgParams.MainOtelExporterEndpoint = endpoint
+			cfgParams.MainOtelLoadModule = true
+		}
+	}
+
+	if otelServiceName, exists := cfgm.Data["otel-service-name"]; exists {
+		serviceName := strings.TrimSpace(otelServiceName)
+		if !otelServiceNameRegexp.MatchString(serviceName) {
+			errorText := fmt.Sprintf("ConfigMap %s/%s: invalid value for 'otel-service-name': %q, must not be empty or contain whitespace, quotes or backslashes, ignoring", cfgm.GetNamespace(), cfgm.GetName(), serviceName)
+			nl.Error(l, errorText)
+			eventLog.Event(cfgm, v1.EventTypeWarning, invalidValueReason, errorText)
+			configOk = false
+		} else {
+			cfgParams.MainOtelServiceName = serviceName
+		}
+	}
+
+	if otelResourceAttributes, exists := cfgm.Data["otel-resource-attributes"]; exists {
+		attributes, err := parseOtelResourceAttributes(otelResourceAttributes)
+		if err != nil {
+			errorText := fmt.Sprintf("ConfigMap %s/%s: invalid value for 'otel-resource-attributes': %v, ignoring", cfgm.GetNamespace(), cfgm.GetName(), err)
+			nl.Error(l, errorText)
+			eventLog.Event(cfgm, v1.EventTypeWarning, invalidValueReason, errorText)
+			configOk = false
+		} else {
+			cfgParams.MainOtelResourceAttributes = attributes
+		}
+	}
+
+	if otelSamplerRatio, exists := cfgm.Data["otel-sampler-ratio"]; exists {
+		ratio, err := ParseFloat64(strings.TrimSpace(otelSamplerRatio))
+		if err != nil || ratio < 0 || ratio > 1 {
+			errorText := fmt.Sprintf("ConfigMap %s/%s: invalid value for 'otel-sampler-ratio': %q, must be a number between 0 and 1, ignoring", cfgm.GetNamespace(), cfgm.GetName(), otelSamplerRatio)
+			nl.Error(l, errorText)
+			eventLog.Event(cfgm, v1.EventTypeWarning, invalidValueReason, errorText)
+			configOk = false
+		} else {
+			cfgParams.MainOtelSamplerRatio = ratio
+		}
+	}
+
+	if otelTraceInHTTP, exists, err := GetMapKeyAsBool(cfgm.Data, "otel-trace-in-http", cfgm); exists {
+		if err != nil {
+			nl.Error(l, err)
+			eventLog.Event(cfgm, v1.EventTypeWarning, invalidValueReason, err.Error())
+			configOk = false
+		} else {
+			if cfgParams.MainOtelLoadModule {
+				cfgParams.MainOtelTraceInHTTP = otelTraceInHTTP
+			} else {
+				errorText := "ConfigMap key 'otel-trace-in-http' requires the 'otel-exporter-endpoint' key configured, OpenTelemetry tracing will be disabled, ignoring"
+				nl.Error(l, errorText)
+				eventLog.Event(cfgm, v1.EventTypeWarning, invalidValueReason, errorText)
+				configOk = false
+			}
+		}
+	}
+
 	if hasAppProtect {
 		if appProtectFailureModeAction, exists := cfgm.Data["app-protect-failure-mode-action"]; exists {
 			if appProtectFailureModeAction == "pass" || appProtectFailureModeAction == "drop" {
@@ -794,6 +876,7 @@ func GenerateNginxMainConfig(staticCfgParams *StaticConfigParams, config *Config
 		KeepaliveTimeout:                   config.MainKeepaliveTimeout,
 		LogFormat:                          config.MainLogFormat,
 		LogFormatEscaping:                  config.MainLogFormatEscaping,
+		NamedLogFormats:                    append(slices.Clone(logFormatPresets), config.MainNamedLogFormats...),
 		MainSnippets:                       config.MainMainSnippets,
 		MGMTConfig:                         mgmtConfig,
 		NginxStatus:                        staticCfgParams.NginxStatus,
@@ -803,6 +886,13 @@ func GenerateNginxMainConfig(staticCfgParams *StaticConfigParams, config *Config
 		OpenTracingLoadModule:              config.MainOpenTracingLoadModule,
 		OpenTracingTracer:                  config.MainOpenTracingTracer,
 		OpenTracingTracerConfig:            config.MainOpenTracingTracerConfig,
+		OtelLoadModule:                     config.MainOtelLoadModule,
+		OtelExporterEndpoint:               config.MainOtelExporterEndpoint,
+		OtelServiceName:                    config.MainOtelServiceName,
+		OtelResourceAttributes:             config.MainOtelResourceAttributes,
+		OtelSamplerPercentage:              generateOtelSamplerPercentage(config.MainOtelSamplerRatio),
+		OtelTraceInHTTP:                    config.MainOtelTraceInHTTP,
+		OtelTrace:                          generateOtelTrace(config.MainOtelSamplerRatio),
 		ProxyProtocol:                      config.ProxyProtocol,
 		ResolverAddresses:                  config.ResolverAddresses,
 		ResolverIPV6:                       config.ResolverIPV6,
@@ -857,3 +947,151 @@ func GenerateNginxMainConfig(staticCfgParams *StaticConfigParams, config *Config
 	}
 	return nginxCfg
 }
+
+// logFormatPresets are the log formats defined in addition to the main log format.
+// They use the variables that the servers of the resources set: the type, name and namespace of the resource,
+// the path of the route and the name of the service.
+var logFormatPresets = []version1.NamedLogFormat{
+	{
+		Name:     "json",
+		Escaping: "json",
+		Format: `{"time":"$time_iso8601","remote_addr":"$remote_addr","request":"$request","status":$status,` +
+			`"body_bytes_sent":$body_bytes_sent,"request_time":$request_time,"http_referer":"$http_referer",` +
+			`"http_user_agent":"$http_user_agent","resource_type":"$resource_type","resource_name":"$resource_name",` +
+			`"resource_namespace":"$resource_namespace","route":"$route_path","service":"$service",` +
+			`"upstream":"$proxy_host","upstream_addr":"$upstream_addr","upstream_status":"$upstream_status",` +
+			`"upstream_response_time":"$upstream_response_time"}`,
+	},
+}
+
+// reservedLogFormatNames are the names of the log formats defined in the main template.
+var reservedLogFormatNames = map[string]bool{
+	"main":          true,
+	"log_dos":       true,
+	"response_time": true,
+}
+
+var (
+	logFormatEscapingValues = map[string]bool{"default": true, "json": true, "none": true}
+	logFormatRegexp         = regexp.MustCompile(`^([^'\\]|\\.)+$`)
+)
+
+// parseNamedLogFormats parses the log formats defined one per line as <name> [escape=default|json|none] <format>.
+func parseNamedLogFormats(s string) ([]version1.NamedLogFormat, error) {
+	var formats []version1.NamedLogFormat
+	names := make(map[string]bool)
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, format, _ := strings.Cut(line, " ")
+		if _, err := ParseLogFormatName(name); err != nil {
+			return nil, fmt.Errorf("%q must consist of alphanumeric characters, '_' or '-'", name)
+		}
+		if reservedLogFormatNames[name] || isLogFormatPreset(name) || names[name] {
+			return nil, fmt.Errorf("log format %q is already defined", name)
+		}
+		names[name] = true
+
+		format = strings.TrimSpace(format)
+		var escaping string
+		if strings.HasPrefix(format, "escape=") {
+			escaping, format, _ = strings.Cut(strings.TrimPrefix(format, "escape="), " ")
+			if !logFormatEscapingValues[escaping] {
+				return nil, fmt.Errorf("the escaping %q of the log format %q must be default, json or none", escaping, name)
+			}
+			format = strings.TrimSpace(format)
+		}
+		if !logFormatRegexp.MatchString(format) {
+			return nil, fmt.Errorf("the log format %q must not be empty, must have all \"'\" escaped and must not end with an unescaped '\\'", name)
+		}
+		formats = append(formats, version1.NamedLogFormat{Name: name, Escaping: escaping, Format: format})
+	}
+	return formats, nil
+}
+
+func isLogFormatPreset(name string) bool {
+	return slices.ContainsFunc(logFormatPresets, func(f version1.NamedLogFormat) bool {
+		return f.Name == name
+	})
+}
+
+// isLogFormatDefined checks if the access logs of the servers can refer to the log format.
+func isLogFormatDefined(name string, cfgParams *ConfigParams) bool {
+	if name == defaultAccessLogFormat || isLogFormatPreset(name) {
+		return true
+	}
+	return slices.ContainsFunc(cfgParams.MainNamedLogFormats, func(f version1.NamedLogFormat) bool {
+		return f.Name == name
+	})
+}
+
+var (
+	otelServiceNameRegexp       = regexp.MustCompile(`^[^\s"\\]+$`)
+	otelAttributeNameRegexp     = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+	otelResourceAttributeRegexp = regexp.MustCompile(`^[^"\\]*$`)
+)
+
+// parseOtelResourceAttributes parses a comma-separated list of key=value OpenTelemetry resource attributes.
+func parseOtelResourceAttributes(s string) ([]version1.OtelAttribute, error) {
+	var attributes []version1.OtelAttribute
+	for _, attr := range strings.Split(s, ",") {
+		attr = strings.TrimSpace(attr)
+		if attr == "" {
+			continue
+		}
+		name, value, found := strings.Cut(attr, "=")
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+		if !found || !otelAttributeNameRegexp.MatchString(name) {
+			return nil, fmt.Errorf("%q must be in the format key=value, where key consists of alphanumeric characters, '.', '_' or '-'", attr)
+		}
+		if !otelResourceAttributeRegexp.MatchString(value) {
+			return nil, fmt.Errorf("the value of %q must not contain quotes or backslashes", name)
+		}
+		attributes = append(attributes, version1.OtelAttribute{Name: name, Value: value})
+	}
+	return attributes, nil
+}
+
+// generateOtelSamplerPercentage returns the percentage of the traced requests for the split_clients of the sampler.
+// It returns an empty string if all or none of the requests are traced.
+func generateOtelSamplerPercentage(ratio float64) string {
+	percentage := math.Round(ratio*10000) / 100
+	if percentage <= 0 || percentage >= 100 {
+		return ""
+	}
+	return strconv.FormatFloat(percentage, 'f', -1, 64)
+}
+
+// generateOtelTrace returns the value of the otel_trace directive that traces the requests according to the sampler ratio.
+func generateOtelTrace(ratio float64) string {
+	percentage := math.Round(ratio*10000) / 100
+	switch {
+	case percentage <= 0:
+		return "off"
+	case percentage >= 100:
+		return "on"
+	}
+	return "$otel_trace_sampler"
+}
+
+const (
+	defaultAccessLogDestination = "/dev/stdout"
+	defaultAccessLogFormat      = "main"
+)
+
+// generateAccessLogParams returns the destination and the format of the access logs of a server with the defaults applied.
+func generateAccessLogParams(destination string, format string, cfgParams *ConfigParams) (string, string, error) {
+	if destination == "" {
+		destination = defaultAccessLogDestination
+	}
+	if format == "" {
+		format = defaultAccessLogFormat
+	}
+	if !isLogFormatDefined(format, cfgParams) {
+		return "", "", fmt.Errorf("log format %q is not defined in the log-formats ConfigMap key", format)
+	}
+	return destination, format, nil
+}
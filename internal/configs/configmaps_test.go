@@ -2,8 +2,10 @@ package configs
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
+	"github.com/nginx/kubernetes-ingress/internal/configs/version1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/record"
 )
@@ -285,6 +287,118 @@ func TestParseConfigMapAccessLogDefault(t *testing.T) {
 	}
 }
 
+func TestParseConfigMapLogFormats(t *testing.T) {
+	t.Parallel()
+	cm := &v1.ConfigMap{
+		Data: map[string]string{
+			"log-formats": `json-short escape=json {"status":"$status","route":"$route_path"}
+text $remote_addr "$request" \'$status\'`,
+		},
+	}
+	want := []version1.NamedLogFormat{
+		{Name: "json-short", Escaping: "json", Format: `{"status":"$status","route":"$route_path"}`},
+		{Name: "text", Format: `$remote_addr "$request" \'$status\'`},
+	}
+
+	result, configOk := ParseConfigMap(context.Background(), cm, false, false, false, false, makeEventLogger())
+	if !configOk {
+		t.Error("want configOk true, got false")
+	}
+	if !reflect.DeepEqual(result.MainNamedLogFormats, want) {
+		t.Errorf("want %v, got %v", want, result.MainNamedLogFormats)
+	}
+}
+
+func TestParseNamedLogFormatsFails(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input string
+		msg   string
+	}{
+		{
+			input: "json-short",
+			msg:   "missing format",
+		},
+		{
+			input: "json short $status",
+			msg:   "invalid name",
+		},
+		{
+			input: "main $status",
+			msg:   "reserved name",
+		},
+		{
+			input: "json $status",
+			msg:   "name of a preset",
+		},
+		{
+			input: "short $status\nshort $request",
+			msg:   "duplicate name",
+		},
+		{
+			input: "short escape=xml $status",
+			msg:   "invalid escaping",
+		},
+		{
+			input: "short '$status'",
+			msg:   "unescaped single quote",
+		},
+		{
+			input: `short $status\`,
+			msg:   "ending backslash",
+		},
+	}
+	for _, test := range tests {
+		if _, err := parseNamedLogFormats(test.input); err == nil {
+			t.Errorf("parseNamedLogFormats(%q) returned no error for the case of %s", test.input, test.msg)
+		}
+	}
+}
+
+func TestGenerateAccessLogParams(t *testing.T) {
+	t.Parallel()
+	cfgParams := &ConfigParams{
+		MainNamedLogFormats: []version1.NamedLogFormat{{Name: "short", Format: "$status"}},
+	}
+	tests := []struct {
+		destination     string
+		format          string
+		wantDestination string
+		wantFormat      string
+	}{
+		{
+			wantDestination: "/dev/stdout",
+			wantFormat:      "main",
+		},
+		{
+			destination:     "syslog:server=localhost:514",
+			format:          "json",
+			wantDestination: "syslog:server=localhost:514",
+			wantFormat:      "json",
+		},
+		{
+			destination:     "/var/log/nginx/cafe.log",
+			format:          "short",
+			wantDestination: "/var/log/nginx/cafe.log",
+			wantFormat:      "short",
+		},
+	}
+	for _, test := range tests {
+		destination, format, err := generateAccessLogParams(test.destination, test.format, cfgParams)
+		if err != nil {
+			t.Errorf("generateAccessLogParams(%q, %q) returned error %v", test.destination, test.format, err)
+		}
+		if destination != test.wantDestination || format != test.wantFormat {
+			t.Errorf("generateAccessLogParams(%q, %q) returned %q, %q, want %q, %q",
+				test.destination, test.format, destination, format, test.wantDestination, test.wantFormat)
+		}
+	}
+
+	if _, _, err := generateAccessLogParams("", "verbose", cfgParams); err == nil {
+		t.Error("generateAccessLogParams() returned no error for an undefined log format")
+	}
+}
+
 func TestParseMGMTConfigMapError(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -791,3 +905,176 @@ func TestParseMGMTConfigMapUsageReportEndpoint(t *testing.T) {
 func makeEventLogger() record.EventRecorder {
 	return record.NewFakeRecorder(1024)
 }
+
+func TestParseConfigMapOtel(t *testing.T) {
+	t.Parallel()
+	cm := &v1.ConfigMap{
+		Data: map[string]string{
+			"otel-exporter-endpoint":   "otel-collector.monitoring.svc:4317",
+			"otel-service-name":        "nginx-ingress",
+			"otel-resource-attributes": "deployment.environment=production, service.namespace=nginx-ingress",
+			"otel-sampler-ratio":       "0.25",
+			"otel-trace-in-http":       "true",
+		},
+	}
+	result, configOk := ParseConfigMap(context.Background(), cm, false, false, false, false, makeEventLogger())
+	if !configOk {
+		t.Error("want configOk true, got false")
+	}
+	if !result.MainOtelLoadModule {
+		t.Error("want MainOtelLoadModule true, got false")
+	}
+	if result.MainOtelExporterEndpoint != "otel-collector.monitoring.svc:4317" {
+		t.Errorf("want MainOtelExporterEndpoint %q, got %q", "otel-collector.monitoring.svc:4317", result.MainOtelExporterEndpoint)
+	}
+	if result.MainOtelServiceName != "nginx-ingress" {
+		t.Errorf("want MainOtelServiceName %q, got %q", "nginx-ingress", result.MainOtelServiceName)
+	}
+	wantAttributes := []version1.OtelAttribute{
+		{Name: "deployment.environment", Value: "production"},
+		{Name: "service.namespace", Value: "nginx-ingress"},
+	}
+	if !reflect.DeepEqual(result.MainOtelResourceAttributes, wantAttributes) {
+		t.Errorf("want MainOtelResourceAttributes %v, got %v", wantAttributes, result.MainOtelResourceAttributes)
+	}
+	if result.MainOtelSamplerRatio != 0.25 {
+		t.Errorf("want MainOtelSamplerRatio 0.25, got %v", result.MainOtelSamplerRatio)
+	}
+	if !result.MainOtelTraceInHTTP {
+		t.Error("want MainOtelTraceInHTTP true, got false")
+	}
+}
+
+func TestParseConfigMapOtelInvalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		data map[string]string
+		msg  string
+	}{
+		{
+			data: map[string]string{"otel-exporter-endpoint": "http://otel-collector:4317"},
+			msg:  "endpoint with a scheme",
+		},
+		{
+			data: map[string]string{"otel-service-name": "nginx ingress"},
+			msg:  "service name with whitespace",
+		},
+		{
+			data: map[string]string{"otel-resource-attributes": "deployment.environment"},
+			msg:  "resource attribute without a value",
+		},
+		{
+			data: map[string]string{"otel-sampler-ratio": "1.5"},
+			msg:  "sampler ratio greater than 1",
+		},
+		{
+			data: map[string]string{"otel-trace-in-http": "true"},
+			msg:  "trace in http without an endpoint",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			cm := &v1.ConfigMap{Data: test.data}
+			result, configOk := ParseConfigMap(context.Background(), cm, false, false, false, false, makeEventLogger())
+			if configOk {
+				t.Error("want configOk false, got true")
+			}
+			if result.MainOtelTraceInHTTP {
+				t.Error("want MainOtelTraceInHTTP false, got true")
+			}
+		})
+	}
+}
+
+func TestParseOtelResourceAttributes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input string
+		want  []version1.OtelAttribute
+	}{
+		{
+			input: "",
+			want:  nil,
+		},
+		{
+			input: "team=cafe",
+			want:  []version1.OtelAttribute{{Name: "team", Value: "cafe"}},
+		},
+		{
+			input: " team = cafe ,, region=eu-west-1,",
+			want: []version1.OtelAttribute{
+				{Name: "team", Value: "cafe"},
+				{Name: "region", Value: "eu-west-1"},
+			},
+		},
+		{
+			input: "host.name=node=1",
+			want:  []version1.OtelAttribute{{Name: "host.name", Value: "node=1"}},
+		},
+	}
+	for _, test := range tests {
+		got, err := parseOtelResourceAttributes(test.input)
+		if err != nil {
+			t.Errorf("parseOtelResourceAttributes(%q) returned error %v", test.input, err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parseOtelResourceAttributes(%q) returned %v, want %v", test.input, got, test.want)
+		}
+	}
+
+	invalidInputs := []string{
+		"team",
+		"=cafe",
+		"team name=cafe",
+		`team="cafe"`,
+		`team=ca\fe`,
+	}
+	for _, input := range invalidInputs {
+		if _, err := parseOtelResourceAttributes(input); err == nil {
+			t.Errorf("parseOtelResourceAttributes(%q) returned no error", input)
+		}
+	}
+}
+
+func TestGenerateOtelSampler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		ratio          float64
+		wantPercentage string
+		wantTrace      string
+	}{
+		{
+			ratio:          0,
+			wantPercentage: "",
+			wantTrace:      "off",
+		},
+		{
+			ratio:          0.00001,
+			wantPercentage: "",
+			wantTrace:      "off",
+		},
+		{
+			ratio:          0.1,
+			wantPercentage: "10",
+			wantTrace:      "$otel_trace_sampler",
+		},
+		{
+			ratio:          0.1234,
+			wantPercentage: "12.34",
+			wantTrace:      "$otel_trace_sampler",
+		},
+		{
+			ratio:          1,
+			wantPercentage: "",
+			wantTrace:      "on",
+		},
+	}
+	for _, test := range tests {
+		if got := generateOtelSamplerPercentage(test.ratio); got != test.wantPercentage {
+			t.Errorf("generateOtelSamplerPercentage(%v) returned %q, want %q", test.ratio, got, test.wantPercentage)
+		}
+		if got := generateOtelTrace(test.ratio); got != test.wantTrace {
+			t.Errorf("generateOtelTrace(%v) returned %q, want %q", test.ratio, got, test.wantTrace)
+		}
+	}
+}
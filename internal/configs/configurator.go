@@ -3,9 +3,14 @@ package configs
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 
 	nl "github.com/nginx/kubernetes-ingress/internal/logger"
 
@@ -26,6 +31,7 @@ import (
 	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	latCollector "github.com/nginx/kubernetes-ingress/internal/metrics/collectors"
 )
@@ -135,6 +141,7 @@ type Configurator struct {
 	virtualServers            map[string]*VirtualServerEx
 	transportServers          map[string]*TransportServerEx
 	tlsPassthroughPairs       map[string]tlsPassthroughPair
+	cacheZones                map[string][]version2.CacheZone
 	isWildcardEnabled         bool
 	isPlus                    bool
 	labelUpdater              collector.LabelUpdater
@@ -145,6 +152,19 @@ type Configurator struct {
 	isReloadsEnabled          bool
 	isDynamicSSLReloadEnabled bool
 	ingressControllerReplicas int
+	reloadResources           map[string]bool
+	rejectedResources         []RejectedResource
+	rejectedConfigs           map[string]string
+	upstreamPeers             map[string]map[string][]UpstreamPeer
+	streamUpstreamPeers       map[string]map[string][]UpstreamPeer
+	upstreamPeersMutex        sync.RWMutex
+}
+
+// RejectedResource is an Ingress, VirtualServer or TransportServer whose configuration was rejected by NGINX
+// and rolled back to the previous version.
+type RejectedResource struct {
+	Object runtime.Object
+	Error  error
 }
 
 // ConfiguratorParams is a collection of parameters used for the
@@ -194,6 +214,9 @@ func NewConfigurator(p ConfiguratorParams) *Configurator {
 		minions:                   make(map[string]map[string]bool),
 		mergeableIngresses:        make(map[string]*MergeableIngresses),
 		tlsPassthroughPairs:       make(map[string]tlsPassthroughPair),
+		cacheZones:                make(map[string][]version2.CacheZone),
+		upstreamPeers:             make(map[string]map[string][]UpstreamPeer),
+		streamUpstreamPeers:       make(map[string]map[string][]UpstreamPeer),
 		isPlus:                    p.IsPlus,
 		isWildcardEnabled:         p.IsWildcardEnabled,
 		labelUpdater:              p.LabelUpdater,
@@ -304,7 +327,7 @@ func (cnf *Configurator) AddOrUpdateIngress(ingEx *IngressEx) (Warnings, error)
 		return warnings, fmt.Errorf("error adding or updating ingress %v/%v: %w", ingEx.Ingress.Namespace, ingEx.Ingress.Name, err)
 	}
 
-	if err := cnf.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.Reload(nginx.ReloadForConfigUpdate); err != nil {
 		return warnings, fmt.Errorf("error reloading NGINX for %v/%v: %w", ingEx.Ingress.Namespace, ingEx.Ingress.Name, err)
 	}
 
@@ -348,6 +371,65 @@ func (cnf *Configurator) UpstreamsForHost(hostname string) []string {
 	return nil
 }
 
+// RouteUpstreams holds the path of a route and the upstreams the route passes requests to.
+type RouteUpstreams struct {
+	Path      string
+	Upstreams []string
+}
+
+// RoutesForHost takes a hostname and returns the routes of the VirtualServer and its VirtualServerRoutes
+// for the given hostname.
+func (cnf *Configurator) RoutesForHost(hostname string) []RouteUpstreams {
+	for _, vsEx := range cnf.virtualServers {
+		if vsEx.VirtualServer.Spec.Host != hostname {
+			continue
+		}
+		var routes []RouteUpstreams
+		namer := NewUpstreamNamerForVirtualServer(vsEx.VirtualServer)
+		for _, r := range vsEx.VirtualServer.Spec.Routes {
+			// the upstreams of the routes that reference a VirtualServerRoute are the upstreams of its subroutes
+			if r.Route != "" {
+				continue
+			}
+			routes = append(routes, RouteUpstreams{Path: r.Path, Upstreams: upstreamsForRoute(namer, r)})
+		}
+		for _, vsr := range vsEx.VirtualServerRoutes {
+			vsrNamer := NewUpstreamNamerForVirtualServerRoute(vsEx.VirtualServer, vsr)
+			for _, r := range vsr.Spec.Subroutes {
+				routes = append(routes, RouteUpstreams{Path: r.Path, Upstreams: upstreamsForRoute(vsrNamer, r)})
+			}
+		}
+		return routes
+	}
+	return nil
+}
+
+// upstreamsForRoute returns the names of the upstreams of the actions, splits and matches of the route.
+func upstreamsForRoute(namer *upstreamNamer, r conf_v1.Route) []string {
+	actions := []*conf_v1.Action{r.Action}
+	for _, s := range r.Splits {
+		actions = append(actions, s.Action)
+	}
+	for _, m := range r.Matches {
+		actions = append(actions, m.Action)
+		for _, s := range m.Splits {
+			actions = append(actions, s.Action)
+		}
+	}
+
+	upstreams := []string{}
+	for _, a := range actions {
+		if a == nil || (a.Pass == "" && (a.Proxy == nil || a.Proxy.Upstream == "")) {
+			continue
+		}
+		name := namer.GetNameForUpstreamFromAction(a)
+		if !slices.Contains(upstreams, name) {
+			upstreams = append(upstreams, name)
+		}
+	}
+	return upstreams
+}
+
 // StreamUpstreamsForName takes a name and returns stream upstreams
 // associated with this name. The name represents TS's
 // (TransportServer) action name.
@@ -361,6 +443,116 @@ func (cnf *Configurator) StreamUpstreamsForName(name string) []string {
 	return nil
 }
 
+// UpstreamPeer is a server of an upstream. The servers of the upstreams are the ready endpoints of the Services.
+type UpstreamPeer struct {
+	Address     string
+	MaxFails    int
+	FailTimeout string
+	// NextUpstream are the conditions of the proxy_next_upstream directive of the locations that pass requests
+	// to the upstream. They define which responses are unsuccessful.
+	NextUpstream []string
+}
+
+// UpstreamPeers returns the peers of the upstreams of the VirtualServers by the upstream name.
+func (cnf *Configurator) UpstreamPeers() map[string][]UpstreamPeer {
+	return mergeUpstreamPeers(&cnf.upstreamPeersMutex, cnf.upstreamPeers)
+}
+
+// StreamUpstreamPeers returns the peers of the upstreams of the TransportServers by the upstream name.
+func (cnf *Configurator) StreamUpstreamPeers() map[string][]UpstreamPeer {
+	return mergeUpstreamPeers(&cnf.upstreamPeersMutex, cnf.streamUpstreamPeers)
+}
+
+func mergeUpstreamPeers(mu *sync.RWMutex, peersByResource map[string]map[string][]UpstreamPeer) map[string][]UpstreamPeer {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	result := make(map[string][]UpstreamPeer)
+	for _, peers := range peersByResource {
+		for name, p := range peers {
+			result[name] = p
+		}
+	}
+	return result
+}
+
+func (cnf *Configurator) updateUpstreamPeers(name string, upstreams []version2.Upstream, locations []version2.Location) {
+	nextUpstreams := nextUpstreamsByUpstream(locations)
+	peers := make(map[string][]UpstreamPeer)
+	for _, u := range upstreams {
+		peers[u.Name] = []UpstreamPeer{}
+		for _, server := range u.Servers {
+			// an upstream without endpoints has a server that makes NGINX return 502
+			if server.Address == nginx502Server {
+				continue
+			}
+			peers[u.Name] = append(peers[u.Name], UpstreamPeer{
+				Address:      server.Address,
+				MaxFails:     u.MaxFails,
+				FailTimeout:  u.FailTimeout,
+				NextUpstream: nextUpstreams[u.Name],
+			})
+		}
+	}
+
+	cnf.upstreamPeersMutex.Lock()
+	defer cnf.upstreamPeersMutex.Unlock()
+	cnf.upstreamPeers[name] = peers
+}
+
+// nextUpstreamsByUpstream returns the conditions of the proxy_next_upstream directive of the locations by the name
+// of the upstream the locations pass requests to. If the locations of an upstream have different conditions,
+// the conditions are merged.
+func nextUpstreamsByUpstream(locations []version2.Location) map[string][]string {
+	result := make(map[string][]string)
+	for _, l := range locations {
+		if l.ProxyPass == "" {
+			continue
+		}
+		_, upstream, found := strings.Cut(l.ProxyPass, "://")
+		if !found {
+			continue
+		}
+		upstream, _, _ = strings.Cut(upstream, "$")
+		for _, c := range strings.Fields(l.ProxyNextUpstream) {
+			if !slices.Contains(result[upstream], c) {
+				result[upstream] = append(result[upstream], c)
+			}
+		}
+	}
+	return result
+}
+
+func (cnf *Configurator) updateStreamUpstreamPeers(name string, upstreams []version2.StreamUpstream) {
+	peers := make(map[string][]UpstreamPeer)
+	for _, u := range upstreams {
+		peers[u.Name] = []UpstreamPeer{}
+		for _, server := range u.Servers {
+			// an upstream without endpoints has a server that makes NGINX fail the connections
+			if server.Address == nginxNonExistingUnixSocket {
+				continue
+			}
+			peers[u.Name] = append(peers[u.Name], UpstreamPeer{Address: server.Address, MaxFails: server.MaxFails, FailTimeout: server.FailTimeout})
+		}
+	}
+
+	cnf.upstreamPeersMutex.Lock()
+	defer cnf.upstreamPeersMutex.Unlock()
+	cnf.streamUpstreamPeers[name] = peers
+}
+
+func (cnf *Configurator) deleteUpstreamPeers(name string) {
+	cnf.upstreamPeersMutex.Lock()
+	defer cnf.upstreamPeersMutex.Unlock()
+	delete(cnf.upstreamPeers, name)
+}
+
+func (cnf *Configurator) deleteStreamUpstreamPeers(name string) {
+	cnf.upstreamPeersMutex.Lock()
+	defer cnf.upstreamPeersMutex.Unlock()
+	delete(cnf.streamUpstreamPeers, name)
+}
+
 // transportServerForActionName takes an action name and returns
 // Transport Server obj associated with that name.
 func (cnf *Configurator) transportServerForActionName(name string) *conf_v1.TransportServer {
@@ -389,6 +581,10 @@ func (cnf *Configurator) streamUpstreamsForTransportServer(ts *conf_v1.Transport
 // addOrUpdateIngress returns a bool that specifies if the underlying config
 // file has changed, and any warnings or errors
 func (cnf *Configurator) addOrUpdateIngress(ingEx *IngressEx) (bool, Warnings, error) {
+	if warnings, rejected := cnf.checkRejectedConfig(objectMetaToFileName(&ingEx.Ingress.ObjectMeta), ingEx.Ingress, ingEx.Ingress); rejected {
+		return false, warnings, nil
+	}
+	cnf.addReloadResource("Ingress", generateNamespaceNameKey(&ingEx.Ingress.ObjectMeta))
 	apResources := cnf.updateApResources(ingEx)
 
 	cnf.updateDosResource(ingEx.DosEx)
@@ -439,7 +635,7 @@ func (cnf *Configurator) AddOrUpdateMergeableIngress(mergeableIngs *MergeableIng
 		return warnings, fmt.Errorf("error when adding or updating ingress %v/%v: %w", mergeableIngs.Master.Ingress.Namespace, mergeableIngs.Master.Ingress.Name, err)
 	}
 
-	if err := cnf.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.Reload(nginx.ReloadForConfigUpdate); err != nil {
 		return warnings, fmt.Errorf("error reloading NGINX for %v/%v: %w", mergeableIngs.Master.Ingress.Namespace, mergeableIngs.Master.Ingress.Name, err)
 	}
 
@@ -447,6 +643,10 @@ func (cnf *Configurator) AddOrUpdateMergeableIngress(mergeableIngs *MergeableIng
 }
 
 func (cnf *Configurator) addOrUpdateMergeableIngress(mergeableIngs *MergeableIngresses) (bool, Warnings, error) {
+	if warnings, rejected := cnf.checkRejectedConfig(objectMetaToFileName(&mergeableIngs.Master.Ingress.ObjectMeta), mergeableIngs.Master.Ingress, mergeableIngressesObjects(mergeableIngs)...); rejected {
+		return false, warnings, nil
+	}
+	cnf.addReloadResource("Ingress", generateNamespaceNameKey(&mergeableIngs.Master.Ingress.ObjectMeta))
 	apResources := cnf.updateApResources(mergeableIngs.Master)
 	cnf.updateDosResource(mergeableIngs.Master.DosEx)
 	dosResource := getAppProtectDosResource(mergeableIngs.Master.DosEx)
@@ -594,7 +794,7 @@ func (cnf *Configurator) AddOrUpdateVirtualServer(virtualServerEx *VirtualServer
 		cnf.EnableReloads()
 	}
 
-	if err := cnf.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.Reload(nginx.ReloadForConfigUpdate); err != nil {
 		return warnings, fmt.Errorf("error reloading NGINX for VirtualServer %v/%v: %w", virtualServerEx.VirtualServer.Namespace, virtualServerEx.VirtualServer.Name, err)
 	}
 
@@ -610,6 +810,10 @@ func (cnf *Configurator) addOrUpdateOpenTracingTracerConfig(content string) erro
 }
 
 func (cnf *Configurator) addOrUpdateVirtualServer(virtualServerEx *VirtualServerEx) (bool, Warnings, []WeightUpdate, error) {
+	if warnings, rejected := cnf.checkRejectedConfig(getFileNameForVirtualServer(virtualServerEx.VirtualServer), virtualServerEx.VirtualServer, virtualServerExObjects(virtualServerEx)...); rejected {
+		return false, warnings, nil, nil
+	}
+	cnf.addReloadResource("VirtualServer", generateNamespaceNameKey(&virtualServerEx.VirtualServer.ObjectMeta))
 	var weightUpdates []WeightUpdate
 	apResources := cnf.updateApResourcesForVs(virtualServerEx)
 	dosResources := map[string]*appProtectDosResource{}
@@ -634,10 +838,19 @@ func (cnf *Configurator) addOrUpdateVirtualServer(virtualServerEx *VirtualServer
 
 	cnf.virtualServers[name] = virtualServerEx
 
+	cnf.cacheZones[name] = vsCfg.CacheZones
+	cacheZonesChanged, err := cnf.updateCacheZonesConfig()
+	if err != nil {
+		return false, warnings, weightUpdates, err
+	}
+	changed = changed || cacheZonesChanged
+
 	if (cnf.isPlus && cnf.isPrometheusEnabled) || cnf.isLatencyMetricsEnabled {
 		cnf.updateVirtualServerMetricsLabels(virtualServerEx, vsCfg.Upstreams)
 	}
 
+	cnf.updateUpstreamPeers(name, vsCfg.Upstreams, vsCfg.Server.Locations)
+
 	if cnf.staticCfgParams.DynamicWeightChangesReload && len(vsCfg.TwoWaySplitClients) > 0 {
 		for _, splitClient := range vsCfg.TwoWaySplitClients {
 			if len(splitClient.Weights) != 2 {
@@ -651,8 +864,8 @@ func (cnf *Configurator) addOrUpdateVirtualServer(virtualServerEx *VirtualServer
 	return changed, warnings, weightUpdates, nil
 }
 
-// AddOrUpdateVirtualServers adds or updates NGINX configuration for multiple VirtualServer resources.
-func (cnf *Configurator) AddOrUpdateVirtualServers(virtualServerExes []*VirtualServerEx) (Warnings, error) {
+// AddOrUpdateResourcesThatUsePolicy adds or updates NGINX configuration for multiple VirtualServer and TransportServer resources.
+func (cnf *Configurator) AddOrUpdateResourcesThatUsePolicy(virtualServerExes []*VirtualServerEx, transportServerExes []*TransportServerEx) (Warnings, error) {
 	allWarnings := newWarnings()
 	allWeightUpdates := []WeightUpdate{}
 
@@ -665,7 +878,15 @@ func (cnf *Configurator) AddOrUpdateVirtualServers(virtualServerExes []*VirtualS
 		allWeightUpdates = append(allWeightUpdates, weightUpdates...)
 	}
 
-	if err := cnf.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	for _, tsEx := range transportServerExes {
+		_, warnings, err := cnf.addOrUpdateTransportServer(tsEx)
+		if err != nil {
+			return allWarnings, err
+		}
+		allWarnings.Add(warnings)
+	}
+
+	if err := cnf.Reload(nginx.ReloadForPolicyUpdate); err != nil {
 		return allWarnings, fmt.Errorf("error when reloading NGINX when updating Policy: %w", err)
 	}
 
@@ -750,13 +971,17 @@ func (cnf *Configurator) AddOrUpdateTransportServer(transportServerEx *Transport
 	if err != nil {
 		return nil, fmt.Errorf("error adding or updating TransportServer %v/%v: %w", transportServerEx.TransportServer.Namespace, transportServerEx.TransportServer.Name, err)
 	}
-	if err := cnf.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.Reload(nginx.ReloadForConfigUpdate); err != nil {
 		return nil, fmt.Errorf("error reloading NGINX for TransportServer %v/%v: %w", transportServerEx.TransportServer.Namespace, transportServerEx.TransportServer.Name, err)
 	}
 	return warnings, nil
 }
 
 func (cnf *Configurator) addOrUpdateTransportServer(transportServerEx *TransportServerEx) (bool, Warnings, error) {
+	if warnings, rejected := cnf.checkRejectedConfig(getFileNameForTransportServer(transportServerEx.TransportServer), transportServerEx.TransportServer, transportServerEx.TransportServer); rejected {
+		return false, warnings, nil
+	}
+	cnf.addReloadResource("TransportServer", generateNamespaceNameKey(&transportServerEx.TransportServer.ObjectMeta))
 	name := getFileNameForTransportServer(transportServerEx.TransportServer)
 	tsCfg, warnings := generateTransportServerConfig(transportServerConfigParams{
 		transportServerEx:      transportServerEx,
@@ -777,6 +1002,7 @@ func (cnf *Configurator) addOrUpdateTransportServer(transportServerEx *Transport
 	changed := cnf.nginxManager.CreateStreamConfig(name, content)
 
 	cnf.transportServers[name] = transportServerEx
+	cnf.updateStreamUpstreamPeers(name, tsCfg.Upstreams)
 
 	// update TLS Passthrough Hosts config in case we have a TLS Passthrough TransportServer
 	// A non empty Host, may be a TLS Passthrough TransportServer but we have to check for the existence of the TLS Passthrough listener also, as TransportServers that terminate at the NGINX level can have non empty Hosts now too
@@ -827,6 +1053,44 @@ func generateTLSPassthroughHostsConfig(tlsPassthroughPairs map[string]tlsPassthr
 	return &cfg
 }
 
+// cacheZonesConfigName is the name of the config file with the proxy cache zones shared by VirtualServers.
+// It can't clash with the config files of Ingresses and VirtualServers, because resource names can't include underscores.
+const cacheZonesConfigName = "cache_zones"
+
+func (cnf *Configurator) updateCacheZonesConfig() (bool, error) {
+	cfg := generateCacheZonesConfig(cnf.cacheZones)
+
+	content, err := cnf.templateExecutorV2.ExecuteCacheZonesTemplate(cfg)
+	if err != nil {
+		return false, fmt.Errorf("error generating config for proxy cache zones: %w", err)
+	}
+
+	return cnf.nginxManager.CreateConfig(cacheZonesConfigName, content), nil
+}
+
+// generateCacheZonesConfig merges the cache zones of VirtualServers, so that a zone referenced by multiple
+// VirtualServers is defined only once, and a zone not referenced by any VirtualServer is removed.
+func generateCacheZonesConfig(cacheZones map[string][]version2.CacheZone) *version2.CacheZonesConfig {
+	names := make([]string, 0, len(cacheZones))
+	for name := range cacheZones {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var zones []version2.CacheZone
+	for _, name := range names {
+		zones = append(zones, cacheZones[name]...)
+	}
+	zones = removeDuplicateCacheZones(zones)
+
+	sort.Slice(zones, func(i, j int) bool {
+		return zones[i].Name < zones[j].Name
+	})
+
+	cfg := version2.CacheZonesConfig(zones)
+	return &cfg
+}
+
 // AddOrUpdateCASecret writes the secret content to disk returning the files added/updated
 func (cnf *Configurator) AddOrUpdateCASecret(secret *api_v1.Secret, crtFileName, crlFileName string) string {
 	crtData, crlData := GenerateCAFileContent(secret)
@@ -847,8 +1111,8 @@ func (cnf *Configurator) addOrUpdateHtpasswdSecret(secret *api_v1.Secret) string
 	return cnf.nginxManager.CreateSecret(name, data, nginx.HtpasswdSecretFileMode)
 }
 
-// AddOrUpdateResources adds or updates configuration for resources.
-func (cnf *Configurator) AddOrUpdateResources(resources ExtendedResources, reloadIfUnchanged bool) (Warnings, error) {
+// AddOrUpdateResources adds or updates configuration for resources. The reason tells why they are updated.
+func (cnf *Configurator) AddOrUpdateResources(resources ExtendedResources, reloadIfUnchanged bool, reason nginx.ReloadReason) (Warnings, error) {
 	allWarnings := newWarnings()
 	allWeightUpdates := []WeightUpdate{}
 	configsChanged := false
@@ -916,7 +1180,7 @@ func (cnf *Configurator) AddOrUpdateResources(resources ExtendedResources, reloa
 	}
 
 	if configsChanged || reloadIfUnchanged {
-		if err := cnf.Reload(nginx.ReloadForOtherUpdate); err != nil {
+		if err := cnf.Reload(reason); err != nil {
 			return nil, fmt.Errorf("error when reloading NGINX when updating resources: %w", err)
 		}
 	}
@@ -996,19 +1260,21 @@ func GenerateLicenseSecret(secret *api_v1.Secret) ([]byte, error) {
 
 // DeleteIngress deletes NGINX configuration for the Ingress resource.
 func (cnf *Configurator) DeleteIngress(key string, skipReload bool) error {
+	cnf.addReloadResource("Ingress", key)
 	name := keyToFileName(key)
 	cnf.nginxManager.DeleteConfig(name)
 
 	delete(cnf.ingresses, name)
 	delete(cnf.minions, name)
 	delete(cnf.mergeableIngresses, name)
+	delete(cnf.rejectedConfigs, name)
 
 	if (cnf.isPlus && cnf.isPrometheusEnabled) || cnf.isLatencyMetricsEnabled {
 		cnf.deleteIngressMetricsLabels(key)
 	}
 
 	if !skipReload {
-		if err := cnf.Reload(nginx.ReloadForOtherUpdate); err != nil {
+		if err := cnf.Reload(nginx.ReloadForConfigUpdate); err != nil {
 			return fmt.Errorf("error when removing ingress %v: %w", key, err)
 		}
 	}
@@ -1018,6 +1284,7 @@ func (cnf *Configurator) DeleteIngress(key string, skipReload bool) error {
 
 // DeleteVirtualServer deletes NGINX configuration for the VirtualServer resource.
 func (cnf *Configurator) DeleteVirtualServer(key string, skipReload bool) error {
+	cnf.addReloadResource("VirtualServer", key)
 	name := getFileNameForVirtualServerFromKey(key)
 	cnf.nginxManager.DeleteConfig(name)
 
@@ -1026,12 +1293,19 @@ func (cnf *Configurator) DeleteVirtualServer(key string, skipReload bool) error
 	}
 
 	delete(cnf.virtualServers, name)
+	delete(cnf.rejectedConfigs, name)
 	if (cnf.isPlus && cnf.isPrometheusEnabled) || cnf.isLatencyMetricsEnabled {
 		cnf.deleteVirtualServerMetricsLabels(key)
 	}
+	cnf.deleteUpstreamPeers(name)
+
+	delete(cnf.cacheZones, name)
+	if _, err := cnf.updateCacheZonesConfig(); err != nil {
+		return fmt.Errorf("error when removing VirtualServer %v: %w", key, err)
+	}
 
 	if !skipReload {
-		if err := cnf.Reload(nginx.ReloadForOtherUpdate); err != nil {
+		if err := cnf.Reload(nginx.ReloadForConfigUpdate); err != nil {
 			return fmt.Errorf("error when removing VirtualServer %v: %w", key, err)
 		}
 	}
@@ -1050,7 +1324,7 @@ func (cnf *Configurator) DeleteTransportServer(key string) error {
 		return fmt.Errorf("error when removing TransportServer %v: %w", key, err)
 	}
 
-	err = cnf.Reload(nginx.ReloadForOtherUpdate)
+	err = cnf.Reload(nginx.ReloadForConfigUpdate)
 	if err != nil {
 		return fmt.Errorf("error when removing TransportServer %v: %w", key, err)
 	}
@@ -1059,10 +1333,13 @@ func (cnf *Configurator) DeleteTransportServer(key string) error {
 }
 
 func (cnf *Configurator) deleteTransportServer(key string) error {
+	cnf.addReloadResource("TransportServer", key)
 	name := getFileNameForTransportServerFromKey(key)
 	cnf.nginxManager.DeleteStreamConfig(name)
 
 	delete(cnf.transportServers, name)
+	delete(cnf.rejectedConfigs, name)
+	cnf.deleteStreamUpstreamPeers(name)
 	// update TLS Passthrough Hosts config in case we have a TLS Passthrough TransportServer
 	if _, exists := cnf.tlsPassthroughPairs[key]; exists {
 		delete(cnf.tlsPassthroughPairs, key)
@@ -1301,13 +1578,158 @@ func (cnf *Configurator) DisableReloads() {
 	cnf.isReloadsEnabled = false
 }
 
-// Reload reloads nginx if reloads is enabled
-func (cnf *Configurator) Reload(isEndpointsUpdate bool) error {
+// Reload reloads nginx if reloads is enabled. Before the reload, the configuration is tested with NGINX.
+// The configuration of any resource that NGINX rejects is rolled back, so that the other resources are still applied.
+func (cnf *Configurator) Reload(reason nginx.ReloadReason) error {
 	if !cnf.isReloadsEnabled {
 		return nil
 	}
 
-	return cnf.nginxManager.Reload(isEndpointsUpdate)
+	resources := cnf.takeReloadResources()
+	if err := cnf.testConfig(); err != nil {
+		return err
+	}
+
+	return cnf.nginxManager.Reload(reason, resources)
+}
+
+// testConfig tests the configuration until NGINX accepts it. For every error, the owner of the file with the error
+// gets its previous configuration back, is removed from the configuration and is recorded as rejected. If the file
+// doesn't belong to a resource or didn't change since the previous reload, all the changes since the previous reload
+// are rolled back.
+func (cnf *Configurator) testConfig() error {
+	for {
+		err := cnf.nginxManager.TestConfig()
+		if err == nil {
+			return nil
+		}
+
+		var cfgErr *nginx.ConfigError
+		if !errors.As(err, &cfgErr) {
+			return err
+		}
+
+		name := strings.TrimSuffix(filepath.Base(cfgErr.Filename), ".conf")
+		owner, objects := cnf.findConfigOwner(name)
+		if owner == nil || !cnf.nginxManager.RollbackConfig(cfgErr.Filename) {
+			cnf.nginxManager.RollbackAllConfigs()
+			return fmt.Errorf("%w; the configuration was rolled back to the previous version", cfgErr)
+		}
+		cnf.rejectConfig(name, objects)
+
+		cnf.rejectedResources = append(cnf.rejectedResources, RejectedResource{
+			Object: owner,
+			Error:  cfgErr,
+		})
+	}
+}
+
+// findConfigOwner finds the Ingress, VirtualServer or TransportServer that owns the configuration file with the name.
+// It also returns all the resources that the configuration file is generated from.
+func (cnf *Configurator) findConfigOwner(name string) (runtime.Object, []meta_v1.Object) {
+	if name == "" {
+		return nil, nil
+	}
+
+	if mergeableIngs, exists := cnf.mergeableIngresses[name]; exists {
+		return mergeableIngs.Master.Ingress, mergeableIngressesObjects(mergeableIngs)
+	}
+	if ingEx, exists := cnf.ingresses[name]; exists {
+		return ingEx.Ingress, []meta_v1.Object{ingEx.Ingress}
+	}
+	if vsEx, exists := cnf.virtualServers[name]; exists {
+		return vsEx.VirtualServer, virtualServerExObjects(vsEx)
+	}
+	if tsEx, exists := cnf.transportServers[name]; exists {
+		return tsEx.TransportServer, []meta_v1.Object{tsEx.TransportServer}
+	}
+
+	return nil, nil
+}
+
+// rejectConfig removes the owner of the rejected configuration file from the configuration. The configuration is not
+// generated again until any of the resources that it is generated from changes, so that NGINX keeps the configuration
+// of the previous reload and the resource is not rejected again by every later reload.
+func (cnf *Configurator) rejectConfig(name string, objects []meta_v1.Object) {
+	delete(cnf.ingresses, name)
+	delete(cnf.minions, name)
+	delete(cnf.mergeableIngresses, name)
+	delete(cnf.virtualServers, name)
+	delete(cnf.transportServers, name)
+
+	if cnf.rejectedConfigs == nil {
+		cnf.rejectedConfigs = make(map[string]string)
+	}
+	cnf.rejectedConfigs[name] = resourceVersions(objects)
+}
+
+// checkRejectedConfig checks if the configuration file with the name was rejected by NGINX and none of the resources
+// that it is generated from changed since then. In that case, the configuration must not be generated again, and
+// a warning for the owner of the file is returned.
+func (cnf *Configurator) checkRejectedConfig(name string, owner runtime.Object, objects ...meta_v1.Object) (Warnings, bool) {
+	versions, exists := cnf.rejectedConfigs[name]
+	if !exists {
+		return nil, false
+	}
+	if versions != resourceVersions(objects) {
+		delete(cnf.rejectedConfigs, name)
+		return nil, false
+	}
+
+	warnings := newWarnings()
+	warnings.AddWarning(owner, "The configuration was rejected by NGINX, the configuration of the previous version is used until the resource is updated")
+	return warnings, true
+}
+
+// resourceVersions returns the resource versions of the resources that a configuration file is generated from.
+func resourceVersions(objects []meta_v1.Object) string {
+	versions := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		versions = append(versions, obj.GetNamespace()+"/"+obj.GetName()+"@"+obj.GetResourceVersion())
+	}
+	return strings.Join(versions, ",")
+}
+
+func mergeableIngressesObjects(mergeableIngs *MergeableIngresses) []meta_v1.Object {
+	objects := []meta_v1.Object{mergeableIngs.Master.Ingress}
+	for _, minion := range mergeableIngs.Minions {
+		objects = append(objects, minion.Ingress)
+	}
+	return objects
+}
+
+func virtualServerExObjects(vsEx *VirtualServerEx) []meta_v1.Object {
+	objects := []meta_v1.Object{vsEx.VirtualServer}
+	for _, vsr := range vsEx.VirtualServerRoutes {
+		objects = append(objects, vsr)
+	}
+	return objects
+}
+
+// TakeRejectedResources returns the resources whose configuration was rejected by NGINX since the previous call.
+func (cnf *Configurator) TakeRejectedResources() []RejectedResource {
+	rejected := cnf.rejectedResources
+	cnf.rejectedResources = nil
+	return rejected
+}
+
+// addReloadResource records a resource whose configuration is applied by the next reload.
+func (cnf *Configurator) addReloadResource(kind string, key string) {
+	if cnf.reloadResources == nil {
+		cnf.reloadResources = make(map[string]bool)
+	}
+	cnf.reloadResources[kind+" "+key] = true
+}
+
+// takeReloadResources returns the sorted resources recorded since the previous reload.
+func (cnf *Configurator) takeReloadResources() []string {
+	resources := make([]string, 0, len(cnf.reloadResources))
+	for r := range cnf.reloadResources {
+		resources = append(resources, r)
+	}
+	sort.Strings(resources)
+	cnf.reloadResources = nil
+	return resources
 }
 
 func (cnf *Configurator) updateServersInPlus(upstream string, servers []string, config nginx.ServerConfig) error {
@@ -1427,7 +1849,8 @@ func (cnf *Configurator) UpdateConfig(resources ExtendedResources) (Warnings, er
 	}
 
 	cnf.nginxManager.SetOpenTracing(mainCfg.OpenTracingLoadModule)
-	if err := cnf.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	cnf.nginxManager.SetOtelTracing(mainCfg.OtelTraceInHTTP)
+	if err := cnf.Reload(nginx.ReloadForConfigUpdate); err != nil {
 		return allWarnings, fmt.Errorf("error when updating config from ConfigMap: %w", err)
 	}
 
@@ -1468,7 +1891,7 @@ func (cnf *Configurator) UpdateVirtualServers(updatedVSExes []*VirtualServerEx,
 		}
 	}
 
-	if err := cnf.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.Reload(nginx.ReloadForConfigUpdate); err != nil {
 		errList = append(errList, fmt.Errorf("error when updating VirtualServer: %w", err))
 	}
 
@@ -1496,7 +1919,7 @@ func (cnf *Configurator) UpdateTransportServers(updatedTSExes []*TransportServer
 		}
 	}
 
-	if err := cnf.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.Reload(nginx.ReloadForConfigUpdate); err != nil {
 		errList = append(errList, fmt.Errorf("error when updating TransportServers: %w", err))
 	}
 
@@ -1513,7 +1936,7 @@ func (cnf *Configurator) BatchDeleteVirtualServers(deletedKeys []string) []error
 		}
 	}
 
-	if err := cnf.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.Reload(nginx.ReloadForConfigUpdate); err != nil {
 		errList = append(errList, fmt.Errorf("error when reloading NGINX for deleted VirtualServers: %w", err))
 	}
 
@@ -1530,7 +1953,7 @@ func (cnf *Configurator) BatchDeleteIngresses(deletedKeys []string) []error {
 		}
 	}
 
-	if err := cnf.Reload(nginx.ReloadForOtherUpdate); err != nil {
+	if err := cnf.Reload(nginx.ReloadForConfigUpdate); err != nil {
 		errList = append(errList, fmt.Errorf("error when reloading NGINX for deleted Ingresses: %w", err))
 	}
 
@@ -1706,7 +2129,7 @@ func (cnf *Configurator) AddOrUpdateSpiffeCerts(svidResponse *workloadapi.X509Co
 	cnf.nginxManager.CreateSecret(spiffeCertFileName, pemCerts, spiffeCertsFileMode)
 	cnf.nginxManager.CreateSecret(spiffeBundleFileName, pemBundle, spiffeCertsFileMode)
 
-	err = cnf.Reload(nginx.ReloadForOtherUpdate)
+	err = cnf.Reload(nginx.ReloadForSecretUpdate)
 	if err != nil {
 		return fmt.Errorf("error when reloading NGINX when updating the SPIFFE Certs: %w", err)
 	}
@@ -1851,7 +2274,7 @@ func (cnf *Configurator) AddOrUpdateAppProtectResource(resource *unstructured.Un
 		return warnings, fmt.Errorf("error when updating %v %v/%v: %w", resource.GetKind(), resource.GetNamespace(), resource.GetName(), err)
 	}
 
-	err = cnf.Reload(nginx.ReloadForOtherUpdate)
+	err = cnf.Reload(nginx.ReloadForPolicyUpdate)
 	if err != nil {
 		return warnings, fmt.Errorf("error when reloading NGINX when updating %v %v/%v: %w", resource.GetKind(), resource.GetNamespace(), resource.GetName(), err)
 	}
@@ -1866,7 +2289,7 @@ func (cnf *Configurator) AddOrUpdateResourcesThatUseDosProtected(ingExes []*Ingr
 		return warnings, fmt.Errorf("error when updating resources that use Dos: %w", err)
 	}
 
-	err = cnf.Reload(nginx.ReloadForOtherUpdate)
+	err = cnf.Reload(nginx.ReloadForPolicyUpdate)
 	if err != nil {
 		return warnings, fmt.Errorf("error when updating resources that use Dos: %w", err)
 	}
@@ -1954,7 +2377,7 @@ func (cnf *Configurator) RefreshAppProtectUserSigs(
 		fmt.Fprintf(&builder, "app_protect_user_defined_signatures %s;\n", fName)
 	}
 	cnf.nginxManager.CreateAppProtectResourceFile(appProtectUserSigIndex, []byte(builder.String()))
-	return allWarnings, cnf.Reload(nginx.ReloadForOtherUpdate)
+	return allWarnings, cnf.Reload(nginx.ReloadForPolicyUpdate)
 }
 
 func appProtectDosPolicyFileName(namespace string, name string) string {
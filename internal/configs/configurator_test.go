@@ -17,6 +17,7 @@ import (
 	"github.com/nginx/kubernetes-ingress/internal/configs/version1"
 	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
 	"github.com/nginx/kubernetes-ingress/internal/k8s/secrets"
+	"github.com/nginx/kubernetes-ingress/internal/metrics/collectors"
 	"github.com/nginx/kubernetes-ingress/internal/nginx"
 	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
 	"github.com/nginx/kubernetes-ingress/pkg/apis/dos/v1beta1"
@@ -513,6 +514,34 @@ func TestGenerateTLSPassthroughHostsConfig(t *testing.T) {
 	}
 }
 
+func TestGenerateCacheZonesConfig(t *testing.T) {
+	t.Parallel()
+	sharedZone := version2.CacheZone{
+		Name:   "pol_cache_default_cache",
+		Path:   "/var/cache/nginx/pol_cache_default_cache",
+		Levels: "1:2",
+		Size:   "10m",
+	}
+	extAuthZone := version2.CacheZone{
+		Name:     "pol_ext_auth_default_ext_auth_default_cafe",
+		Path:     "/var/cache/nginx/pol_ext_auth_default_ext_auth_default_cafe",
+		Size:     "1m",
+		Inactive: "30s",
+	}
+	cacheZones := map[string][]version2.CacheZone{
+		"vs_default_cafe": {sharedZone, extAuthZone},
+		"vs_default_tea":  {sharedZone},
+		"vs_default_milk": nil,
+	}
+
+	expectedCfg := &version2.CacheZonesConfig{sharedZone, extAuthZone}
+
+	resultCfg := generateCacheZonesConfig(cacheZones)
+	if !reflect.DeepEqual(resultCfg, expectedCfg) {
+		t.Errorf("generateCacheZonesConfig() returned %v but expected %v", resultCfg, expectedCfg)
+	}
+}
+
 func TestAddInternalRouteConfig(t *testing.T) {
 	t.Parallel()
 	cnf := createTestConfigurator(t)
@@ -577,6 +606,136 @@ func TestFindRemovedKeys(t *testing.T) {
 	}
 }
 
+// rejectingManager is a fake Manager whose configuration test fails with the errors of configErrs one by one.
+type rejectingManager struct {
+	*nginx.FakeManager
+	configErrs    []error
+	rolledBack    []string
+	rolledBackAll bool
+	reloaded      bool
+}
+
+func (m *rejectingManager) TestConfig() error {
+	if len(m.configErrs) == 0 {
+		return nil
+	}
+	err := m.configErrs[0]
+	m.configErrs = m.configErrs[1:]
+	return err
+}
+
+func (m *rejectingManager) RollbackConfig(filename string) bool {
+	m.rolledBack = append(m.rolledBack, filename)
+	return true
+}
+
+func (m *rejectingManager) RollbackAllConfigs() {
+	m.rolledBackAll = true
+}
+
+func (m *rejectingManager) Reload(_ nginx.ReloadReason, _ []string) error {
+	m.reloaded = true
+	return nil
+}
+
+func TestReloadRollsBackRejectedResources(t *testing.T) {
+	t.Parallel()
+
+	cnf := createTestConfigurator(t)
+	manager := &rejectingManager{
+		FakeManager: nginx.NewFakeManager("/etc/nginx"),
+		configErrs: []error{
+			&nginx.ConfigError{Filename: "/etc/nginx/conf.d/vs_default_cafe.conf", Line: 3, Message: `unknown directive "foo"`},
+			&nginx.ConfigError{Filename: "/etc/nginx/stream-conf.d/ts_default_tcp.conf", Line: 5, Message: `unknown directive "bar"`},
+		},
+	}
+	cnf.nginxManager = manager
+
+	vs := &conf_v1.VirtualServer{ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "cafe", ResourceVersion: "1"}}
+	ts := &conf_v1.TransportServer{ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "tcp", ResourceVersion: "2"}}
+	cnf.virtualServers["vs_default_cafe"] = &VirtualServerEx{VirtualServer: vs}
+	cnf.transportServers["ts_default_tcp"] = &TransportServerEx{TransportServer: ts}
+
+	if err := cnf.Reload(nginx.ReloadForConfigUpdate); err != nil {
+		t.Fatalf("Reload() returned unexpected error: %v", err)
+	}
+
+	if !manager.reloaded {
+		t.Error("Reload() didn't reload NGINX after rolling back the rejected resources")
+	}
+	wantRolledBack := []string{"/etc/nginx/conf.d/vs_default_cafe.conf", "/etc/nginx/stream-conf.d/ts_default_tcp.conf"}
+	if !reflect.DeepEqual(manager.rolledBack, wantRolledBack) {
+		t.Errorf("Reload() rolled back %v, want %v", manager.rolledBack, wantRolledBack)
+	}
+
+	rejected := cnf.TakeRejectedResources()
+	if len(rejected) != 2 || rejected[0].Object != vs || rejected[1].Object != ts {
+		t.Fatalf("TakeRejectedResources() returned %+v, want the VirtualServer and the TransportServer", rejected)
+	}
+	if rejected := cnf.TakeRejectedResources(); len(rejected) != 0 {
+		t.Errorf("TakeRejectedResources() returned %+v after the previous call", rejected)
+	}
+
+	if _, exists := cnf.virtualServers["vs_default_cafe"]; exists {
+		t.Error("Reload() kept the rejected VirtualServer in the configuration")
+	}
+	if _, exists := cnf.transportServers["ts_default_tcp"]; exists {
+		t.Error("Reload() kept the rejected TransportServer in the configuration")
+	}
+}
+
+func TestCheckRejectedConfig(t *testing.T) {
+	t.Parallel()
+
+	cnf := createTestConfigurator(t)
+	vs := &conf_v1.VirtualServer{ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "cafe", ResourceVersion: "1"}}
+	vsr := &conf_v1.VirtualServerRoute{ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "tea", ResourceVersion: "2"}}
+	cnf.rejectConfig("vs_default_cafe", []meta_v1.Object{vs, vsr})
+
+	warnings, rejected := cnf.checkRejectedConfig("vs_default_cafe", vs, vs, vsr)
+	if !rejected {
+		t.Error("checkRejectedConfig() returned false for the unchanged rejected VirtualServer")
+	}
+	if len(warnings[vs]) != 1 {
+		t.Errorf("checkRejectedConfig() returned warnings %v, want a warning for the VirtualServer", warnings)
+	}
+
+	updatedVSR := vsr.DeepCopy()
+	updatedVSR.ResourceVersion = "3"
+	if _, rejected := cnf.checkRejectedConfig("vs_default_cafe", vs, vs, updatedVSR); rejected {
+		t.Error("checkRejectedConfig() returned true after the VirtualServerRoute was updated")
+	}
+	if _, rejected := cnf.checkRejectedConfig("vs_default_cafe", vs, vs, vsr); rejected {
+		t.Error("checkRejectedConfig() returned true for a rejected config that was already generated again")
+	}
+}
+
+func TestReloadRollsBackAllConfigsForUnknownFile(t *testing.T) {
+	t.Parallel()
+
+	cnf := createTestConfigurator(t)
+	manager := &rejectingManager{
+		FakeManager: nginx.NewFakeManager("/etc/nginx"),
+		configErrs: []error{
+			&nginx.ConfigError{Filename: "/etc/nginx/nginx.conf", Line: 10, Message: `invalid number of arguments in "worker_processes" directive`},
+		},
+	}
+	cnf.nginxManager = manager
+
+	if err := cnf.Reload(nginx.ReloadForConfigUpdate); err == nil {
+		t.Error("Reload() returned no error for a rejected main config")
+	}
+	if !manager.rolledBackAll {
+		t.Error("Reload() didn't roll back all the configs")
+	}
+	if manager.reloaded {
+		t.Error("Reload() reloaded NGINX with a rejected config")
+	}
+	if rejected := cnf.TakeRejectedResources(); len(rejected) != 0 {
+		t.Errorf("TakeRejectedResources() returned %+v, want none", rejected)
+	}
+}
+
 type mockLabelUpdater struct {
 	upstreamServerLabels           map[string][]string
 	serverZoneLabels               map[string][]string
@@ -762,6 +921,16 @@ func (u *mockLatencyCollector) DeleteUpstreamServerPeerLabels(peers []string) {
 // RecordLatency implements a fake RecordLatency method
 func (u *mockLatencyCollector) RecordLatency(string) {}
 
+// UpstreamStats implements a fake UpstreamStats method
+func (u *mockLatencyCollector) UpstreamStats(string) collectors.UpstreamStats {
+	return collectors.UpstreamStats{}
+}
+
+// UpstreamServerPeerStats implements a fake UpstreamServerPeerStats method
+func (u *mockLatencyCollector) UpstreamServerPeerStats(string) collectors.UpstreamServerPeerStats {
+	return collectors.UpstreamServerPeerStats{}
+}
+
 // Register implements a fake Register method
 func (u *mockLatencyCollector) Register(*prometheus.Registry) error { return nil }
 
@@ -1591,6 +1760,76 @@ func TestUpstreamsForHost_ReturnsUpstreamsNamesForValidHostname(t *testing.T) {
 	}
 }
 
+func TestRoutesForHost_ReturnsUpstreamsOfRoutesAndSubroutes(t *testing.T) {
+	t.Parallel()
+
+	vs := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+		Spec: conf_v1.VirtualServerSpec{
+			Host: "cafe.example.com",
+			Routes: []conf_v1.Route{
+				{
+					Path: "/tea",
+					Splits: []conf_v1.Split{
+						{Weight: 90, Action: &conf_v1.Action{Pass: "tea-v1"}},
+						{Weight: 10, Action: &conf_v1.Action{Pass: "tea-v2"}},
+					},
+				},
+				{
+					Path: "/coffee",
+					Matches: []conf_v1.Match{
+						{Action: &conf_v1.Action{Proxy: &conf_v1.ActionProxy{Upstream: "coffee-v2"}}},
+					},
+					Action: &conf_v1.Action{Pass: "coffee-v1"},
+				},
+				{
+					Path:   "/redirect",
+					Action: &conf_v1.Action{Redirect: &conf_v1.ActionRedirect{URL: "http://nginx.org"}},
+				},
+				{
+					Path:  "/juice",
+					Route: "juice",
+				},
+			},
+		},
+	}
+	vsr := &conf_v1.VirtualServerRoute{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "juice",
+			Namespace: "default",
+		},
+		Spec: conf_v1.VirtualServerRouteSpec{
+			Host: "cafe.example.com",
+			Subroutes: []conf_v1.Route{
+				{Path: "/juice/orange", Action: &conf_v1.Action{Pass: "orange"}},
+			},
+		},
+	}
+
+	tcnf := createTestConfigurator(t)
+	tcnf.virtualServers = map[string]*VirtualServerEx{
+		"vs": {VirtualServer: vs, VirtualServerRoutes: []*conf_v1.VirtualServerRoute{vsr}},
+	}
+
+	want := []RouteUpstreams{
+		{Path: "/tea", Upstreams: []string{"vs_default_cafe_tea-v1", "vs_default_cafe_tea-v2"}},
+		{Path: "/coffee", Upstreams: []string{"vs_default_cafe_coffee-v1", "vs_default_cafe_coffee-v2"}},
+		{Path: "/redirect", Upstreams: []string{}},
+		{Path: "/juice/orange", Upstreams: []string{"vs_default_cafe_vsr_default_juice_orange"}},
+	}
+	got := tcnf.RoutesForHost("cafe.example.com")
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+
+	if got := tcnf.RoutesForHost("bogus.example.com"); got != nil {
+		t.Errorf("want nil, got %+v", got)
+	}
+}
+
 func TestStreamUpstreamsForName_DoesNotReturnUpstreamsForBogusName(t *testing.T) {
 	t.Parallel()
 
@@ -1620,6 +1859,67 @@ func TestStreamUpstreamsForName_ReturnsStreamUpstreamsNamesOnValidServiceName(t
 	}
 }
 
+func TestUpstreamPeers_ReturnsServersOfUpstreamsWithEndpoints(t *testing.T) {
+	t.Parallel()
+
+	tcnf := createTestConfigurator(t)
+	tcnf.updateUpstreamPeers("vs_default_cafe", []version2.Upstream{
+		{
+			Name:        "vs_default_cafe_tea",
+			Servers:     []version2.UpstreamServer{{Address: "10.0.0.1:80"}, {Address: "10.0.0.2:80"}},
+			MaxFails:    1,
+			FailTimeout: "10s",
+		},
+		{
+			Name:     "vs_default_cafe_coffee",
+			Servers:  []version2.UpstreamServer{{Address: nginx502Server}},
+			MaxFails: 1,
+		},
+	}, []version2.Location{
+		{Path: "/tea", ProxyPass: "http://vs_default_cafe_tea", ProxyNextUpstream: "error timeout"},
+		{Path: "/tea/green", ProxyPass: "http://vs_default_cafe_tea$request_uri", ProxyNextUpstream: "error http_503"},
+		{Path: "/coffee", ProxyPass: "http://vs_default_cafe_coffee", ProxyNextUpstream: "off"},
+	})
+	tcnf.updateStreamUpstreamPeers("ts_default_dns", []version2.StreamUpstream{
+		{
+			Name:    "ts_default_dns_dns-app",
+			Servers: []version2.StreamUpstreamServer{{Address: "10.0.0.3:5353", MaxFails: 2, FailTimeout: "5s"}},
+		},
+		{
+			Name:    "ts_default_dns_dns-backup",
+			Servers: []version2.StreamUpstreamServer{{Address: nginxNonExistingUnixSocket}},
+		},
+	})
+
+	want := map[string][]UpstreamPeer{
+		"vs_default_cafe_tea": {
+			{Address: "10.0.0.1:80", MaxFails: 1, FailTimeout: "10s", NextUpstream: []string{"error", "timeout", "http_503"}},
+			{Address: "10.0.0.2:80", MaxFails: 1, FailTimeout: "10s", NextUpstream: []string{"error", "timeout", "http_503"}},
+		},
+		"vs_default_cafe_coffee": {},
+	}
+	if got := tcnf.UpstreamPeers(); !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+
+	wantStream := map[string][]UpstreamPeer{
+		"ts_default_dns_dns-app":    {{Address: "10.0.0.3:5353", MaxFails: 2, FailTimeout: "5s"}},
+		"ts_default_dns_dns-backup": {},
+	}
+	if got := tcnf.StreamUpstreamPeers(); !cmp.Equal(wantStream, got) {
+		t.Error(cmp.Diff(wantStream, got))
+	}
+
+	tcnf.deleteUpstreamPeers("vs_default_cafe")
+	tcnf.deleteStreamUpstreamPeers("ts_default_dns")
+	if got := tcnf.UpstreamPeers(); len(got) != 0 {
+		t.Errorf("want no upstream peers after deletion, got %+v", got)
+	}
+	if got := tcnf.StreamUpstreamPeers(); len(got) != 0 {
+		t.Errorf("want no stream upstream peers after deletion, got %+v", got)
+	}
+}
+
 func TestGetIngressAnnotations(t *testing.T) {
 	t.Parallel()
 
@@ -179,6 +179,19 @@ func generateNginxCfg(p NginxCfgParams) (version1.IngressNginxConfig, Warnings)
 		warnings := addSSLConfig(&server, p.ingEx.Ingress, rule.Host, p.ingEx.Ingress.Spec.TLS, p.ingEx.SecretRefs, p.isWildcardEnabled)
 		allWarnings.Add(warnings)
 
+		if cfgParams.AccessLogDestination != "" || cfgParams.AccessLogFormat != "" {
+			destination, format, err := generateAccessLogParams(cfgParams.AccessLogDestination, cfgParams.AccessLogFormat, &cfgParams)
+			if err != nil {
+				allWarnings.AddWarningf(p.ingEx.Ingress, "%v, the access log is ignored", err)
+			} else {
+				server.AccessLog = &version1.AccessLog{
+					Destination:    destination,
+					Format:         format,
+					LatencyMetrics: p.staticParams.EnableLatencyMetrics,
+				}
+			}
+		}
+
 		if hasAppProtect {
 			server.AppProtectPolicy = p.apResources.AppProtectPolicy
 			server.AppProtectLogConfs = p.apResources.AppProtectLogconfs
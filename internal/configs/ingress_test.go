@@ -1838,6 +1838,67 @@ func TestGenerateNginxCfgForInternalRoute(t *testing.T) {
 	}
 }
 
+func TestGenerateNginxCfgForAccessLog(t *testing.T) {
+	t.Parallel()
+	cafeIngressEx := createCafeIngressEx()
+	cafeIngressEx.Ingress.Annotations["nginx.org/access-log-destination"] = "syslog:server=localhost:514"
+	cafeIngressEx.Ingress.Annotations["nginx.org/access-log-format"] = "json"
+	isPlus := false
+	configParams := NewDefaultConfigParams(context.Background(), isPlus)
+
+	expected := &version1.AccessLog{
+		Destination:    "syslog:server=localhost:514",
+		Format:         "json",
+		LatencyMetrics: true,
+	}
+
+	result, warnings := generateNginxCfg(NginxCfgParams{
+		staticParams:         &StaticConfigParams{EnableLatencyMetrics: true},
+		ingEx:                &cafeIngressEx,
+		apResources:          nil,
+		dosResource:          nil,
+		isMinion:             false,
+		isPlus:               false,
+		BaseCfgParams:        configParams,
+		isResolverConfigured: false,
+		isWildcardEnabled:    false,
+	})
+
+	if diff := cmp.Diff(expected, result.Servers[0].AccessLog); diff != "" {
+		t.Errorf("generateNginxCfg() returned unexpected access log (-want +got):\n%s", diff)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("generateNginxCfg() returned warnings: %v", warnings)
+	}
+}
+
+func TestGenerateNginxCfgForAccessLogWithUndefinedFormat(t *testing.T) {
+	t.Parallel()
+	cafeIngressEx := createCafeIngressEx()
+	cafeIngressEx.Ingress.Annotations["nginx.org/access-log-format"] = "verbose"
+	isPlus := false
+	configParams := NewDefaultConfigParams(context.Background(), isPlus)
+
+	result, warnings := generateNginxCfg(NginxCfgParams{
+		staticParams:         &StaticConfigParams{},
+		ingEx:                &cafeIngressEx,
+		apResources:          nil,
+		dosResource:          nil,
+		isMinion:             false,
+		isPlus:               false,
+		BaseCfgParams:        configParams,
+		isResolverConfigured: false,
+		isWildcardEnabled:    false,
+	})
+
+	if result.Servers[0].AccessLog != nil {
+		t.Errorf("generateNginxCfg() returned access log %v, want nil", result.Servers[0].AccessLog)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("generateNginxCfg() returned %d warnings, want 1", len(warnings))
+	}
+}
+
 func TestIsSSLEnabled(t *testing.T) {
 	t.Parallel()
 	type testCase struct {
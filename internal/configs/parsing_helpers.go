@@ -278,6 +278,37 @@ func ParseProxyBuffersSpec(s string) (string, error) {
 	return "", errors.New("invalid proxy buffers string")
 }
 
+// LogFormatNameFmt https://nginx.org/en/docs/http/ngx_http_log_module.html#log_format
+const LogFormatNameFmt = `[a-zA-Z0-9_-]+`
+
+var logFormatNameRegexp = regexp.MustCompile("^" + LogFormatNameFmt + "$")
+
+// ParseLogFormatName ensures that the string value is a valid name of a log format
+func ParseLogFormatName(s string) (string, error) {
+	s = strings.TrimSpace(s)
+
+	if logFormatNameRegexp.MatchString(s) {
+		return s, nil
+	}
+	return "", errors.New("invalid log format name")
+}
+
+var (
+	accessLogSyslogRegexp = regexp.MustCompile(`^syslog:server=[^\s"'{};$\\]+$`)
+	accessLogFileRegexp   = regexp.MustCompile(`^/var/log/nginx/[a-zA-Z0-9_-][a-zA-Z0-9._-]*$`)
+)
+
+// ParseAccessLogDestination ensures that the string value is a valid destination of access logs:
+// syslog, /dev/stdout, /dev/stderr or a file in /var/log/nginx
+func ParseAccessLogDestination(s string) (string, error) {
+	s = strings.TrimSpace(s)
+
+	if s == "/dev/stdout" || s == "/dev/stderr" || accessLogSyslogRegexp.MatchString(s) || accessLogFileRegexp.MatchString(s) {
+		return s, nil
+	}
+	return "", errors.New("invalid access log destination, must be syslog:server=<address>, /dev/stdout, /dev/stderr or a file in /var/log/nginx")
+}
+
 // parseProxySetHeaders ensures that the string colon-separated list of headers and values
 func parseProxySetHeaders(proxySetHeaders []string) []version2.Header {
 	var headers []version2.Header
@@ -617,6 +617,65 @@ func TestParseProxyBuffersSpec(t *testing.T) {
 	}
 }
 
+func TestParseLogFormatName(t *testing.T) {
+	t.Parallel()
+	testsWithValidInput := []string{"main", "json", "json_verbose", "cafe-1"}
+	invalidInput := []string{"", "json verbose", "json;", "'json'", "json$"}
+	for _, test := range testsWithValidInput {
+		result, err := ParseLogFormatName(test)
+		if err != nil {
+			t.Fatalf("ParseLogFormatName(%q) returned an error for valid input", test)
+		}
+		if test != result {
+			t.Errorf("ParseLogFormatName(%q) returned %q expected %q", test, result, test)
+		}
+	}
+	for _, test := range invalidInput {
+		result, err := ParseLogFormatName(test)
+		if err == nil {
+			t.Errorf("ParseLogFormatName(%q) didn't return error. Returned: %q", test, result)
+		}
+	}
+}
+
+func TestParseAccessLogDestination(t *testing.T) {
+	t.Parallel()
+	testsWithValidInput := []string{
+		"/dev/stdout",
+		"/dev/stderr",
+		"/var/log/nginx/cafe.log",
+		"/var/log/nginx/cafe_access-1.log",
+		"syslog:server=localhost:514",
+		"syslog:server=unix:/var/log/nginx.sock,tag=cafe",
+	}
+	invalidInput := []string{
+		"",
+		"/etc/nginx/nginx.conf",
+		"/var/log/nginx/..",
+		"/var/log/nginx/../../etc/nginx/nginx.conf",
+		"/var/log/nginx/",
+		"/dev/stdout main",
+		"syslog:",
+		"syslog:server=localhost:514;",
+		"syslog:server=${host}",
+	}
+	for _, test := range testsWithValidInput {
+		result, err := ParseAccessLogDestination(test)
+		if err != nil {
+			t.Fatalf("ParseAccessLogDestination(%q) returned an error for valid input", test)
+		}
+		if test != result {
+			t.Errorf("ParseAccessLogDestination(%q) returned %q expected %q", test, result, test)
+		}
+	}
+	for _, test := range invalidInput {
+		result, err := ParseAccessLogDestination(test)
+		if err == nil {
+			t.Errorf("ParseAccessLogDestination(%q) didn't return error. Returned: %q", test, result)
+		}
+	}
+}
+
 func TestVerifyThresholds(t *testing.T) {
 	t.Parallel()
 	validInput := []string{
@@ -23,6 +23,7 @@ type TransportServerEx struct {
 	ExternalNameSvcs map[string]bool
 	DisableIPV6      bool
 	SecretRefs       map[string]*secrets.SecretReference
+	Policies         map[string]*conf_v1.Policy
 	IPv4             string
 	IPv6             string
 }
@@ -68,6 +69,18 @@ func generateTransportServerConfig(p transportServerConfigParams) (*version2.Tra
 	sslConfig, w := generateSSLConfig(p.transportServerEx.TransportServer, p.transportServerEx.TransportServer.Spec.TLS, p.transportServerEx.TransportServer.Namespace, p.transportServerEx.SecretRefs)
 	warnings.Add(w)
 
+	upstreamTLS, err := generateTransportServerUpstreamTLS(p.transportServerEx)
+	if err != nil {
+		passUpstreamName := upstreamNamer.GetNameForUpstream(p.transportServerEx.TransportServer.Spec.Action.Pass)
+		warnings.AddWarningf(p.transportServerEx.TransportServer, "Connections to upstream %s will fail: %v", p.transportServerEx.TransportServer.Spec.Action.Pass, err)
+		for i := range upstreams {
+			if upstreams[i].Name == passUpstreamName {
+				upstreams[i].Servers = []version2.StreamUpstreamServer{{Address: nginxNonExistingUnixSocket}}
+				upstreams[i].BackupServers = nil
+			}
+		}
+	}
+
 	var proxyRequests, proxyResponses *int
 	var connectTimeout, nextUpstreamTimeout string
 	var nextUpstream bool
@@ -90,6 +103,15 @@ func generateTransportServerConfig(p transportServerConfigParams) (*version2.Tra
 		proxyTimeout = p.transportServerEx.TransportServer.Spec.SessionParameters.Timeout
 	}
 
+	policies, w := generateTransportServerPolicies(p.transportServerEx.TransportServer, p.transportServerEx.Policies)
+	warnings.Add(w)
+
+	err = addSSLClientVerification(sslConfig, p.transportServerEx.TransportServer.Spec.TLS, p.transportServerEx.TransportServer.Namespace, p.transportServerEx.SecretRefs)
+	if err != nil {
+		warnings.AddWarningf(p.transportServerEx.TransportServer, "Client certificate verification is invalid: %v. All connections will be denied", err)
+		policies = transportServerPoliciesCfg{Deny: []string{"all"}}
+	}
+
 	serverSnippets := generateSnippets(true, p.transportServerEx.TransportServer.Spec.ServerSnippets, []string{})
 
 	streamSnippets := generateSnippets(true, p.transportServerEx.TransportServer.Spec.StreamSnippets, []string{})
@@ -127,8 +149,15 @@ func generateTransportServerConfig(p transportServerConfigParams) (*version2.Tra
 			SSL:                      sslConfig,
 			IPv4:                     p.transportServerEx.IPv4,
 			IPv6:                     p.transportServerEx.IPv6,
+			Allow:                    policies.Allow,
+			Deny:                     policies.Deny,
+			LimitConns:               policies.LimitConns,
+			ProxyUploadRate:          policies.ProxyUploadRate,
+			ProxyDownloadRate:        policies.ProxyDownloadRate,
+			UpstreamTLS:              upstreamTLS,
 		},
 		Match:                   match,
+		LimitConnZones:          policies.LimitConnZones,
 		Upstreams:               upstreams,
 		StreamSnippets:          streamSnippets,
 		DynamicSSLReloadEnabled: p.isDynamicReloadEnabled,
@@ -137,6 +166,70 @@ func generateTransportServerConfig(p transportServerConfigParams) (*version2.Tra
 	return tsConfig, warnings
 }
 
+type transportServerPoliciesCfg struct {
+	Allow             []string
+	Deny              []string
+	LimitConns        []version2.LimitConn
+	LimitConnZones    []version2.LimitConnZone
+	ProxyUploadRate   string
+	ProxyDownloadRate string
+}
+
+// generateTransportServerPolicies generates the configuration of the policies referenced by a TransportServer.
+// If a policy is missing or invalid, all connections are denied.
+func generateTransportServerPolicies(ts *conf_v1.TransportServer, policies map[string]*conf_v1.Policy) (transportServerPoliciesCfg, Warnings) {
+	warnings := newWarnings()
+	var config transportServerPoliciesCfg
+	bandwidthPolKey := ""
+
+	for _, p := range ts.Spec.Policies {
+		polNamespace := p.Namespace
+		if polNamespace == "" {
+			polNamespace = ts.Namespace
+		}
+
+		key := fmt.Sprintf("%s/%s", polNamespace, p.Name)
+
+		pol, exists := policies[key]
+		if !exists {
+			warnings.AddWarningf(ts, "Policy %s is missing or invalid. All connections will be denied", key)
+			return transportServerPoliciesCfg{Deny: []string{"all"}}, warnings
+		}
+
+		switch {
+		case pol.Spec.AccessControl != nil:
+			config.Allow = append(config.Allow, pol.Spec.AccessControl.Allow...)
+			config.Deny = append(config.Deny, pol.Spec.AccessControl.Deny...)
+			if len(config.Allow) > 0 && len(config.Deny) > 0 {
+				warnings.AddWarning(ts, "AccessControl policy (or policies) with deny rules is overridden by policy (or policies) with allow rules")
+			}
+		case pol.Spec.ConnectionLimit != nil:
+			zoneName := fmt.Sprintf("pol_cl_%v_%v_%v_%v", rfc1123ToSnake(polNamespace), rfc1123ToSnake(p.Name), rfc1123ToSnake(ts.Namespace), rfc1123ToSnake(ts.Name))
+			config.LimitConns = append(config.LimitConns, version2.LimitConn{
+				ZoneName:    zoneName,
+				Connections: pol.Spec.ConnectionLimit.Connections,
+			})
+			config.LimitConnZones = append(config.LimitConnZones, version2.LimitConnZone{
+				Key:      "$binary_remote_addr",
+				ZoneName: zoneName,
+				ZoneSize: generateString(pol.Spec.ConnectionLimit.ZoneSize, "10m"),
+			})
+		case pol.Spec.Bandwidth != nil:
+			if bandwidthPolKey != "" {
+				warnings.AddWarningf(ts, "Bandwidth policy %s is overridden by Bandwidth policy %s", key, bandwidthPolKey)
+				continue
+			}
+			bandwidthPolKey = key
+			config.ProxyUploadRate = pol.Spec.Bandwidth.UploadRate
+			config.ProxyDownloadRate = pol.Spec.Bandwidth.DownloadRate
+		default:
+			warnings.AddWarningf(ts, "Policy %s is not supported for TransportServers and will be ignored", key)
+		}
+	}
+
+	return config, warnings
+}
+
 func generateUnixSocket(transportServerEx *TransportServerEx) string {
 	if transportServerEx.TransportServer.Spec.Listener.Name == conf_v1.TLSPassthroughListenerName {
 		return fmt.Sprintf("unix:/var/lib/nginx/passthrough-%s_%s.sock", transportServerEx.TransportServer.Namespace, transportServerEx.TransportServer.Name)
@@ -177,6 +270,78 @@ func generateSSLConfig(ts *conf_v1.TransportServer, tls *conf_v1.TransportServer
 	return &ssl, warnings
 }
 
+// addSSLClientVerification adds the client certificate verification of a TransportServer to its SSL configuration.
+// The verification requires TLS termination, so an error is returned if it is not enabled.
+func addSSLClientVerification(ssl *version2.StreamSSL, tls *conf_v1.TransportServerTLS, namespace string, secretRefs map[string]*secrets.SecretReference) error {
+	if tls == nil || tls.ClientCertSecret == "" {
+		return nil
+	}
+	if !ssl.Enabled {
+		return fmt.Errorf("TLS termination is not enabled")
+	}
+
+	secretKey := fmt.Sprintf("%v/%v", namespace, tls.ClientCertSecret)
+	secretRef, exists := secretRefs[secretKey]
+	if !exists {
+		return fmt.Errorf("the client certificate secret %s does not exist", secretKey)
+	}
+	var secretType api_v1.SecretType
+	if secretRef.Secret != nil {
+		secretType = secretRef.Secret.Type
+	}
+	if secretType != "" && secretType != secrets.SecretTypeCA {
+		return fmt.Errorf("the client certificate secret %s is of a wrong type '%s', must be '%s'", secretKey, secretType, secrets.SecretTypeCA)
+	} else if secretRef.Error != nil {
+		return fmt.Errorf("the client certificate secret %s is invalid: %w", secretKey, secretRef.Error)
+	}
+
+	caFields := strings.Fields(secretRef.Path)
+	if len(caFields) == 0 {
+		return fmt.Errorf("the client certificate secret %s has no CA certificate", secretKey)
+	}
+
+	ssl.ClientCertificate = caFields[0]
+	if tls.CrlFileName != "" {
+		ssl.ClientCrl = fmt.Sprintf("%s/%s", DefaultSecretPath, tls.CrlFileName)
+	} else if _, hasCrlKey := secretRef.Secret.Data[CACrlKey]; hasCrlKey && len(caFields) > 1 {
+		ssl.ClientCrl = caFields[1]
+	}
+	ssl.VerifyClient = generateString(tls.VerifyClient, "on")
+	ssl.VerifyDepth = generateIntFromPointer(tls.VerifyDepth, 1)
+
+	return nil
+}
+
+// generateTransportServerUpstreamTLS generates the TLS configuration of the connections to the upstream
+// that the TransportServer passes the client connections to.
+func generateTransportServerUpstreamTLS(transportServerEx *TransportServerEx) (*version2.UpstreamTLS, error) {
+	ts := transportServerEx.TransportServer
+
+	for _, u := range ts.Spec.Upstreams {
+		if u.Name != ts.Spec.Action.Pass || !u.TLS.Enable {
+			continue
+		}
+
+		trustedCertPath, err := generateTrustedCertPath(u.TLS.TrustedCertSecret, ts.Namespace, transportServerEx.SecretRefs)
+		if err != nil {
+			return nil, err
+		}
+
+		return &version2.UpstreamTLS{
+			VerifyServer: u.TLS.VerifyServer,
+			VerifyDepth:  generateIntFromPointer(u.TLS.VerifyDepth, 1),
+			Ciphers:      u.TLS.Ciphers,
+			Protocols:    u.TLS.Protocols,
+			TrustedCert:  trustedCertPath,
+			SessionReuse: generateBool(u.TLS.SessionReuse, true),
+			ServerName:   u.TLS.ServerName,
+			SSLName:      u.TLS.SSLName,
+		}, nil
+	}
+
+	return nil, nil
+}
+
 func generateStreamUpstreams(transportServerEx *TransportServerEx, upstreamNamer *upstreamNamer, isPlus bool, isResolverConfigured bool) ([]version2.StreamUpstream, Warnings) {
 	warnings := newWarnings()
 	var upstreams []version2.StreamUpstream
@@ -1901,3 +1901,569 @@ func TestGenerateTsSSLConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateTransportServerPolicies(t *testing.T) {
+	t.Parallel()
+
+	ts := &conf_v1.TransportServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "tcp-server",
+			Namespace: "default",
+		},
+	}
+
+	tests := []struct {
+		policyRefs []conf_v1.PolicyReference
+		policies   map[string]*conf_v1.Policy
+		expected   transportServerPoliciesCfg
+		msg        string
+	}{
+		{
+			policyRefs: nil,
+			policies:   nil,
+			expected:   transportServerPoliciesCfg{},
+			msg:        "no policies",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name: "allow-policy",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/allow-policy": {
+					Spec: conf_v1.PolicySpec{
+						AccessControl: &conf_v1.AccessControl{
+							Allow: []string{"10.0.0.0/8"},
+						},
+					},
+				},
+			},
+			expected: transportServerPoliciesCfg{
+				Allow: []string{"10.0.0.0/8"},
+			},
+			msg: "access control policy",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "connection-limit",
+					Namespace: "policies",
+				},
+				{
+					Name: "default-zone",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"policies/connection-limit": {
+					Spec: conf_v1.PolicySpec{
+						ConnectionLimit: &conf_v1.ConnectionLimit{
+							Connections: 10,
+							ZoneSize:    "1m",
+						},
+					},
+				},
+				"default/default-zone": {
+					Spec: conf_v1.PolicySpec{
+						ConnectionLimit: &conf_v1.ConnectionLimit{
+							Connections: 100,
+						},
+					},
+				},
+			},
+			expected: transportServerPoliciesCfg{
+				LimitConns: []version2.LimitConn{
+					{
+						ZoneName:    "pol_cl_policies_connection_limit_default_tcp_server",
+						Connections: 10,
+					},
+					{
+						ZoneName:    "pol_cl_default_default_zone_default_tcp_server",
+						Connections: 100,
+					},
+				},
+				LimitConnZones: []version2.LimitConnZone{
+					{
+						Key:      "$binary_remote_addr",
+						ZoneName: "pol_cl_policies_connection_limit_default_tcp_server",
+						ZoneSize: "1m",
+					},
+					{
+						Key:      "$binary_remote_addr",
+						ZoneName: "pol_cl_default_default_zone_default_tcp_server",
+						ZoneSize: "10m",
+					},
+				},
+			},
+			msg: "connection limit policies",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name: "bandwidth",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/bandwidth": {
+					Spec: conf_v1.PolicySpec{
+						Bandwidth: &conf_v1.Bandwidth{
+							UploadRate:   "100k",
+							DownloadRate: "1m",
+						},
+					},
+				},
+			},
+			expected: transportServerPoliciesCfg{
+				ProxyUploadRate:   "100k",
+				ProxyDownloadRate: "1m",
+			},
+			msg: "bandwidth policy",
+		},
+	}
+
+	for _, test := range tests {
+		ts.Spec.Policies = test.policyRefs
+
+		result, warnings := generateTransportServerPolicies(ts, test.policies)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateTransportServerPolicies() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+		if len(warnings) != 0 {
+			t.Errorf("generateTransportServerPolicies() returned unexpected warnings %v for the case of %s", warnings, test.msg)
+		}
+	}
+}
+
+func TestGenerateTransportServerPolicies_GeneratesWarnings(t *testing.T) {
+	t.Parallel()
+
+	ts := &conf_v1.TransportServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "tcp-server",
+			Namespace: "default",
+		},
+	}
+
+	tests := []struct {
+		policyRefs []conf_v1.PolicyReference
+		policies   map[string]*conf_v1.Policy
+		expected   transportServerPoliciesCfg
+		msg        string
+	}{
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name: "allow-policy",
+				},
+				{
+					Name: "missing",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/allow-policy": {
+					Spec: conf_v1.PolicySpec{
+						AccessControl: &conf_v1.AccessControl{
+							Allow: []string{"10.0.0.0/8"},
+						},
+					},
+				},
+			},
+			expected: transportServerPoliciesCfg{
+				Deny: []string{"all"},
+			},
+			msg: "missing policy",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name: "rate-limit",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/rate-limit": {
+					Spec: conf_v1.PolicySpec{
+						RateLimit: &conf_v1.RateLimit{
+							Rate:     "10r/s",
+							Key:      "$binary_remote_addr",
+							ZoneSize: "10m",
+						},
+					},
+				},
+			},
+			expected: transportServerPoliciesCfg{},
+			msg:      "unsupported policy",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name: "bandwidth-1",
+				},
+				{
+					Name: "bandwidth-2",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/bandwidth-1": {
+					Spec: conf_v1.PolicySpec{
+						Bandwidth: &conf_v1.Bandwidth{
+							UploadRate: "100k",
+						},
+					},
+				},
+				"default/bandwidth-2": {
+					Spec: conf_v1.PolicySpec{
+						Bandwidth: &conf_v1.Bandwidth{
+							DownloadRate: "1m",
+						},
+					},
+				},
+			},
+			expected: transportServerPoliciesCfg{
+				ProxyUploadRate: "100k",
+			},
+			msg: "multiple bandwidth policies",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name: "allow-policy",
+				},
+				{
+					Name: "deny-policy",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/allow-policy": {
+					Spec: conf_v1.PolicySpec{
+						AccessControl: &conf_v1.AccessControl{
+							Allow: []string{"10.0.0.0/8"},
+						},
+					},
+				},
+				"default/deny-policy": {
+					Spec: conf_v1.PolicySpec{
+						AccessControl: &conf_v1.AccessControl{
+							Deny: []string{"10.0.0.1"},
+						},
+					},
+				},
+			},
+			expected: transportServerPoliciesCfg{
+				Allow: []string{"10.0.0.0/8"},
+				Deny:  []string{"10.0.0.1"},
+			},
+			msg: "allow and deny policies",
+		},
+	}
+
+	for _, test := range tests {
+		ts.Spec.Policies = test.policyRefs
+
+		result, warnings := generateTransportServerPolicies(ts, test.policies)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateTransportServerPolicies() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+		if len(warnings[ts]) != 1 {
+			t.Errorf("generateTransportServerPolicies() returned warnings %v for the case of %s, want exactly one", warnings, test.msg)
+		}
+	}
+}
+
+func TestAddSSLClientVerification(t *testing.T) {
+	t.Parallel()
+
+	secretRefs := map[string]*secrets.SecretReference{
+		"default/ca-secret": {
+			Secret: &api_v1.Secret{
+				Type: secrets.SecretTypeCA,
+			},
+			Path: "/etc/nginx/secrets/default-ca-secret-ca.crt",
+		},
+		"default/ca-crl-secret": {
+			Secret: &api_v1.Secret{
+				Type: secrets.SecretTypeCA,
+				Data: map[string][]byte{
+					CACrlKey: nil,
+				},
+			},
+			Path: "/etc/nginx/secrets/default-ca-crl-secret-ca.crt /etc/nginx/secrets/default-ca-crl-secret-ca.crl",
+		},
+	}
+
+	tests := []struct {
+		tls      *conf_v1.TransportServerTLS
+		expected *version2.StreamSSL
+		msg      string
+	}{
+		{
+			tls: &conf_v1.TransportServerTLS{
+				Secret: "my-secret",
+			},
+			expected: &version2.StreamSSL{
+				Enabled: true,
+			},
+			msg: "no client verification",
+		},
+		{
+			tls: &conf_v1.TransportServerTLS{
+				Secret:           "my-secret",
+				ClientCertSecret: "ca-secret",
+			},
+			expected: &version2.StreamSSL{
+				Enabled:           true,
+				ClientCertificate: "/etc/nginx/secrets/default-ca-secret-ca.crt",
+				VerifyClient:      "on",
+				VerifyDepth:       1,
+			},
+			msg: "client verification with defaults",
+		},
+		{
+			tls: &conf_v1.TransportServerTLS{
+				Secret:           "my-secret",
+				ClientCertSecret: "ca-crl-secret",
+				VerifyClient:     "optional",
+				VerifyDepth:      intPointer(2),
+			},
+			expected: &version2.StreamSSL{
+				Enabled:           true,
+				ClientCertificate: "/etc/nginx/secrets/default-ca-crl-secret-ca.crt",
+				ClientCrl:         "/etc/nginx/secrets/default-ca-crl-secret-ca.crl",
+				VerifyClient:      "optional",
+				VerifyDepth:       2,
+			},
+			msg: "client verification with the crl of the secret",
+		},
+		{
+			tls: &conf_v1.TransportServerTLS{
+				Secret:           "my-secret",
+				ClientCertSecret: "ca-crl-secret",
+				CrlFileName:      "webapp.crl",
+			},
+			expected: &version2.StreamSSL{
+				Enabled:           true,
+				ClientCertificate: "/etc/nginx/secrets/default-ca-crl-secret-ca.crt",
+				ClientCrl:         "/etc/nginx/secrets/webapp.crl",
+				VerifyClient:      "on",
+				VerifyDepth:       1,
+			},
+			msg: "client verification with the crl file name",
+		},
+	}
+
+	for _, test := range tests {
+		ssl := &version2.StreamSSL{Enabled: true}
+
+		err := addSSLClientVerification(ssl, test.tls, "default", secretRefs)
+		if err != nil {
+			t.Errorf("addSSLClientVerification() returned unexpected error %v for the case of %s", err, test.msg)
+		}
+		if diff := cmp.Diff(test.expected, ssl); diff != "" {
+			t.Errorf("addSSLClientVerification() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestAddSSLClientVerification_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	secretRefs := map[string]*secrets.SecretReference{
+		"default/tls-secret": {
+			Secret: &api_v1.Secret{
+				Type: api_v1.SecretTypeTLS,
+			},
+			Path: "/etc/nginx/secrets/default-tls-secret",
+		},
+		"default/invalid-ca-secret": {
+			Secret: &api_v1.Secret{
+				Type: secrets.SecretTypeCA,
+			},
+			Error: errors.New("invalid secret"),
+		},
+	}
+
+	tests := []struct {
+		ssl              *version2.StreamSSL
+		clientCertSecret string
+		msg              string
+	}{
+		{
+			ssl:              &version2.StreamSSL{Enabled: false},
+			clientCertSecret: "tls-secret",
+			msg:              "TLS termination is not enabled",
+		},
+		{
+			ssl:              &version2.StreamSSL{Enabled: true},
+			clientCertSecret: "missing-secret",
+			msg:              "missing secret",
+		},
+		{
+			ssl:              &version2.StreamSSL{Enabled: true},
+			clientCertSecret: "tls-secret",
+			msg:              "wrong secret type",
+		},
+		{
+			ssl:              &version2.StreamSSL{Enabled: true},
+			clientCertSecret: "invalid-ca-secret",
+			msg:              "invalid secret",
+		},
+	}
+
+	for _, test := range tests {
+		tls := &conf_v1.TransportServerTLS{
+			Secret:           "my-secret",
+			ClientCertSecret: test.clientCertSecret,
+		}
+
+		err := addSSLClientVerification(test.ssl, tls, "default", secretRefs)
+		if err == nil {
+			t.Errorf("addSSLClientVerification() returned no error for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestGenerateTransportServerUpstreamTLS(t *testing.T) {
+	t.Parallel()
+
+	transportServerEx := &TransportServerEx{
+		TransportServer: &conf_v1.TransportServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "tcp-server",
+				Namespace: "default",
+			},
+			Spec: conf_v1.TransportServerSpec{
+				Upstreams: []conf_v1.TransportServerUpstream{
+					{
+						Name: "tcp-app",
+						TLS: conf_v1.UpstreamTLS{
+							Enable:            true,
+							TrustedCertSecret: "ca-secret",
+							VerifyServer:      true,
+							VerifyDepth:       intPointer(2),
+							Protocols:         "TLSv1.2 TLSv1.3",
+							ServerName:        true,
+							SSLName:           "tcp-app.example.com",
+						},
+					},
+					{
+						Name: "unused-app",
+						TLS: conf_v1.UpstreamTLS{
+							Enable:            true,
+							TrustedCertSecret: "missing-secret",
+						},
+					},
+				},
+				Action: &conf_v1.TransportServerAction{
+					Pass: "tcp-app",
+				},
+			},
+		},
+		SecretRefs: map[string]*secrets.SecretReference{
+			"default/ca-secret": {
+				Secret: &api_v1.Secret{
+					Type: secrets.SecretTypeCA,
+				},
+				Path: "/etc/nginx/secrets/default-ca-secret-ca.crt",
+			},
+		},
+	}
+
+	expected := &version2.UpstreamTLS{
+		VerifyServer: true,
+		VerifyDepth:  2,
+		Protocols:    "TLSv1.2 TLSv1.3",
+		TrustedCert:  "/etc/nginx/secrets/default-ca-secret-ca.crt",
+		SessionReuse: true,
+		ServerName:   true,
+		SSLName:      "tcp-app.example.com",
+	}
+
+	result, err := generateTransportServerUpstreamTLS(transportServerEx)
+	if err != nil {
+		t.Errorf("generateTransportServerUpstreamTLS() returned unexpected error %v", err)
+	}
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("generateTransportServerUpstreamTLS() mismatch (-want +got):\n%s", diff)
+	}
+
+	transportServerEx.TransportServer.Spec.Upstreams[0].TLS = conf_v1.UpstreamTLS{}
+
+	result, err = generateTransportServerUpstreamTLS(transportServerEx)
+	if err != nil {
+		t.Errorf("generateTransportServerUpstreamTLS() returned unexpected error %v", err)
+	}
+	if result != nil {
+		t.Errorf("generateTransportServerUpstreamTLS() returned %v for an upstream without TLS, want nil", result)
+	}
+}
+
+func TestGenerateTransportServerConfig_FailsClosedOnInvalidTLSSecrets(t *testing.T) {
+	t.Parallel()
+
+	transportServerEx := TransportServerEx{
+		TransportServer: &conf_v1.TransportServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "tcp-server",
+				Namespace: "default",
+			},
+			Spec: conf_v1.TransportServerSpec{
+				Listener: conf_v1.TransportServerListener{
+					Name:     "tcp-listener",
+					Protocol: "TCP",
+				},
+				TLS: &conf_v1.TransportServerTLS{
+					Secret:           "my-secret",
+					ClientCertSecret: "missing-ca-secret",
+				},
+				Upstreams: []conf_v1.TransportServerUpstream{
+					{
+						Name:    "tcp-app",
+						Service: "tcp-app-svc",
+						Port:    5001,
+						TLS: conf_v1.UpstreamTLS{
+							Enable:            true,
+							TrustedCertSecret: "missing-ca-secret",
+							VerifyServer:      true,
+						},
+					},
+				},
+				Action: &conf_v1.TransportServerAction{
+					Pass: "tcp-app",
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"default/tcp-app-svc:5001": {
+				"10.0.0.20:5001",
+			},
+		},
+		SecretRefs: map[string]*secrets.SecretReference{
+			"default/my-secret": {
+				Secret: &api_v1.Secret{
+					Type: api_v1.SecretTypeTLS,
+				},
+				Path: "/etc/nginx/secrets/default-my-secret",
+			},
+		},
+	}
+
+	result, warnings := generateTransportServerConfig(transportServerConfigParams{
+		transportServerEx: &transportServerEx,
+		listenerPort:      2020,
+		isPlus:            true,
+	})
+
+	if len(warnings[transportServerEx.TransportServer]) != 2 {
+		t.Errorf("generateTransportServerConfig() returned warnings %v, want exactly two", warnings)
+	}
+
+	wantServers := []version2.StreamUpstreamServer{{Address: nginxNonExistingUnixSocket}}
+	if diff := cmp.Diff(wantServers, result.Upstreams[0].Servers); diff != "" {
+		t.Errorf("generateTransportServerConfig() upstream servers mismatch (-want +got):\n%s", diff)
+	}
+	if result.Server.UpstreamTLS != nil {
+		t.Errorf("generateTransportServerConfig() returned upstream TLS %v, want nil", result.Server.UpstreamTLS)
+	}
+	if diff := cmp.Diff([]string{"all"}, result.Server.Deny); diff != "" {
+		t.Errorf("generateTransportServerConfig() deny mismatch (-want +got):\n%s", diff)
+	}
+}
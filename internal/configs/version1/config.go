@@ -126,6 +126,8 @@ type Server struct {
 	AppProtectDosAllowListPath   string
 	AppProtectDosAccessLogDst    string
 
+	AccessLog *AccessLog
+
 	SpiffeCerts bool
 
 	DisableIPV6 bool
@@ -189,6 +191,27 @@ type Location struct {
 	MinionIngress *Ingress
 }
 
+// OtelAttribute is an OpenTelemetry attribute.
+type OtelAttribute struct {
+	Name  string
+	Value string
+}
+
+// NamedLogFormat is a log format that access logs can refer to by name.
+type NamedLogFormat struct {
+	Name     string
+	Escaping string
+	Format   string
+}
+
+// AccessLog defines the access logs of a server.
+// LatencyMetrics keeps the access logs for the latency metrics, which the server would not inherit from the http context.
+type AccessLog struct {
+	Destination    string
+	Format         string
+	LatencyMetrics bool
+}
+
 // MGMTConfig is tbe configuration for the MGMT block.
 type MGMTConfig struct {
 	SSLVerify            *bool
@@ -220,6 +243,7 @@ type MainConfig struct {
 	KeepaliveTimeout                   string
 	LogFormat                          []string
 	LogFormatEscaping                  string
+	NamedLogFormats                    []NamedLogFormat
 	MainSnippets                       []string
 	MGMTConfig                         MGMTConfig
 	NginxStatus                        bool
@@ -229,6 +253,13 @@ type MainConfig struct {
 	OpenTracingLoadModule              bool
 	OpenTracingTracer                  string
 	OpenTracingTracerConfig            string
+	OtelLoadModule                     bool
+	OtelExporterEndpoint               string
+	OtelServiceName                    string
+	OtelResourceAttributes             []OtelAttribute
+	OtelSamplerPercentage              string
+	OtelTraceInHTTP                    bool
+	OtelTrace                          string
 	ProxyProtocol                      bool
 	ResolverAddresses                  []string
 	ResolverIPV6                       bool
@@ -217,6 +217,7 @@ var helperFunctions = template.FuncMap{
 	"replaceAll":              strings.ReplaceAll,
 	"makeLocationPath":        makeLocationPath,
 	"makeSecretPath":          commonhelpers.MakeSecretPath,
+	"makeRoutePath":           commonhelpers.MakeRoutePath,
 	"makeOnOffFromBool":       commonhelpers.MakeOnOffFromBool,
 	"generateProxySetHeaders": generateProxySetHeaders,
 	"boolToPointerBool":       boolToPointerBool,
@@ -880,6 +880,135 @@ func TestExecuteTemplate_ForMainForNGINXPlusWithHTTP2Off(t *testing.T) {
 	snaps.MatchSnapshot(t, buf.String())
 }
 
+func TestExecuteTemplate_ForMainWithOtel(t *testing.T) {
+	t.Parallel()
+
+	cfg := mainCfg
+	cfg.OtelLoadModule = true
+	cfg.OtelExporterEndpoint = "otel-collector.monitoring.svc:4317"
+	cfg.OtelServiceName = "nginx-ingress"
+	cfg.OtelResourceAttributes = []OtelAttribute{{Name: "deployment.environment", Value: "production"}}
+	cfg.OtelSamplerPercentage = "25"
+	cfg.OtelTraceInHTTP = true
+	cfg.OtelTrace = "$otel_trace_sampler"
+
+	wantDirectives := []string{
+		"load_module modules/ngx_otel_module.so;",
+		"endpoint otel-collector.monitoring.svc:4317;",
+		`otel_service_name "nginx-ingress";`,
+		`otel_resource_attr deployment.environment "production";`,
+		`split_clients "$otel_trace_id" $otel_trace_sampler {`,
+		"25% on;",
+		"otel_trace_context propagate;",
+		"otel_trace $otel_trace_sampler;",
+		"otel_trace off;",
+	}
+
+	for _, tmpl := range []*template.Template{newNGINXPlusMainTmpl(t), newNGINXMainTmpl(t)} {
+		buf := &bytes.Buffer{}
+
+		err := tmpl.Execute(buf, cfg)
+		t.Log(buf.String())
+		if err != nil {
+			t.Fatalf("Failed to write template %v", err)
+		}
+
+		mainConf := buf.String()
+		for _, want := range wantDirectives {
+			if !strings.Contains(mainConf, want) {
+				t.Errorf("want %q in generated config", want)
+			}
+		}
+		snaps.MatchSnapshot(t, buf.String())
+	}
+}
+
+func TestExecuteTemplate_ForMainWithoutOtel(t *testing.T) {
+	t.Parallel()
+
+	for _, tmpl := range []*template.Template{newNGINXPlusMainTmpl(t), newNGINXMainTmpl(t)} {
+		buf := &bytes.Buffer{}
+
+		err := tmpl.Execute(buf, mainCfg)
+		t.Log(buf.String())
+		if err != nil {
+			t.Fatalf("Failed to write template %v", err)
+		}
+
+		if strings.Contains(buf.String(), "otel_") {
+			t.Error("want no otel directives in generated config")
+		}
+	}
+}
+
+func TestExecuteTemplate_ForMainWithNamedLogFormats(t *testing.T) {
+	t.Parallel()
+
+	cfg := mainCfg
+	cfg.NamedLogFormats = []NamedLogFormat{
+		{Name: "json", Escaping: "json", Format: `{"status":"$status"}`},
+		{Name: "short", Format: "$remote_addr $status"},
+	}
+
+	wantDirectives := []string{
+		`log_format  json escape=json '{"status":"$status"}';`,
+		`log_format  short '$remote_addr $status';`,
+	}
+
+	for _, tmpl := range []*template.Template{newNGINXPlusMainTmpl(t), newNGINXMainTmpl(t)} {
+		buf := &bytes.Buffer{}
+
+		err := tmpl.Execute(buf, cfg)
+		t.Log(buf.String())
+		if err != nil {
+			t.Fatalf("Failed to write template %v", err)
+		}
+
+		mainConf := buf.String()
+		for _, want := range wantDirectives {
+			if !strings.Contains(mainConf, want) {
+				t.Errorf("want %q in generated config", want)
+			}
+		}
+	}
+}
+
+func TestExecuteTemplate_ForIngressWithAccessLog(t *testing.T) {
+	t.Parallel()
+
+	cfg := ingressCfg
+	server := cfg.Servers[0]
+	server.AccessLog = &AccessLog{
+		Destination:    "syslog:server=localhost:514",
+		Format:         "json",
+		LatencyMetrics: true,
+	}
+	cfg.Servers = []Server{server}
+
+	wantDirectives := []string{
+		"access_log syslog:server=localhost:514 json;",
+		"access_log syslog:server=unix:/var/lib/nginx/nginx-syslog.sock,nohostname,tag=nginx response_time;",
+		`set $route_path "/tea";`,
+	}
+
+	for _, tmpl := range []*template.Template{newNGINXPlusIngressTmpl(t), newNGINXIngressTmpl(t)} {
+		buf := &bytes.Buffer{}
+
+		err := tmpl.Execute(buf, cfg)
+		t.Log(buf.String())
+		if err != nil {
+			t.Fatalf("Failed to write template %v", err)
+		}
+
+		ingressConf := buf.String()
+		for _, want := range wantDirectives {
+			if !strings.Contains(ingressConf, want) {
+				t.Errorf("want %q in generated config", want)
+			}
+		}
+	}
+}
+
 func TestExecuteTemplate_ForIngressForNGINXWithProxySetHeadersAnnotationWithDefaultValue(t *testing.T) {
 	t.Parallel()
 
@@ -18,6 +18,7 @@ type VirtualServerConfig struct {
 	KeyVals                 []KeyVal
 	LimitReqZones           []LimitReqZone
 	Maps                    []Map
+	CacheZones              []CacheZone // written by the Configurator into a config file shared by all VirtualServers
 	Server                  Server
 	SpiffeCerts             bool
 	SpiffeClientCerts       bool
@@ -45,6 +46,19 @@ type Upstream struct {
 	UpstreamLabels   UpstreamLabels
 	NTLM             bool
 	BackupServers    []UpstreamServer
+	TLS              *UpstreamTLS
+}
+
+// UpstreamTLS defines TLS configuration for the connections to the servers of an upstream.
+type UpstreamTLS struct {
+	VerifyServer bool
+	VerifyDepth  int
+	Ciphers      string
+	Protocols    string
+	TrustedCert  string
+	SessionReuse bool
+	ServerName   bool
+	SSLName      string
 }
 
 // UpstreamServer defines an upstream server.
@@ -74,6 +88,8 @@ type Server struct {
 	Locations                 []Location
 	ErrorPageLocations        []ErrorPageLocation
 	ReturnLocations           []ReturnLocation
+	MirrorLocations           []MirrorLocation
+	ExternalAuthLocations     []ExternalAuthLocation
 	HealthChecks              []HealthCheck
 	TLSRedirect               *TLSRedirect
 	TLSPassthrough            bool
@@ -92,6 +108,8 @@ type Server struct {
 	APIKeyEnabled             bool
 	WAF                       *WAF
 	Dos                       *Dos
+	Tracing                   *Tracing
+	AccessLog                 *AccessLog
 	PoliciesErrorReturn       *Return
 	VSNamespace               string
 	VSName                    string
@@ -152,6 +170,67 @@ type APIKey struct {
 	MapName string
 }
 
+// CORS defines the Cross-Origin Resource Sharing configuration.
+type CORS struct {
+	OriginVariable           string
+	PreflightVariable        string
+	AllowMethods             string
+	AllowHeaders             string
+	ExposeHeadersVariable    string
+	AllowCredentialsVariable string
+	MaxAge                   int
+}
+
+// Cache defines the caching of the responses in a Location.
+type Cache struct {
+	Zone        string
+	Key         string
+	Valid       []string
+	Lock        bool
+	LockTimeout string
+	LockAge     string
+	UseStale    string
+	Bypass      string
+}
+
+// ExternalAuth defines the external authorization of the requests in a Location.
+type ExternalAuth struct {
+	Path            string
+	URIVariable     string
+	ResponseHeaders []ExternalAuthHeader
+}
+
+// ExternalAuthHeader defines a header of the external authorization response that is passed to the upstream.
+type ExternalAuthHeader struct {
+	Name             string
+	Variable         string
+	UpstreamVariable string
+}
+
+// ExternalAuthLocation defines an internal location for the external authorization subrequests.
+type ExternalAuthLocation struct {
+	Path           string
+	ProxyPass      string
+	ForwardHeaders []Header
+	SkipVariable   string
+	CacheZone      string
+	CacheKey       string
+	CacheTTL       string
+}
+
+// CacheZone defines a proxy cache zone.
+type CacheZone struct {
+	Name     string
+	Path     string
+	Levels   string
+	Size     string
+	MaxSize  string
+	Inactive string
+}
+
+// CacheZonesConfig defines the proxy cache zones shared by VirtualServers.
+type CacheZonesConfig []CacheZone
+
 // WAF defines WAF configuration.
 type WAF struct {
 	Enable              string
@@ -175,6 +254,26 @@ type Dos struct {
 	ApDosAccessLogDest     string
 }
 
+// Tracing defines the OpenTelemetry tracing of the requests.
+type Tracing struct {
+	Trace          string
+	SpanAttributes []SpanAttribute
+}
+
+// SpanAttribute defines an attribute of a span.
+type SpanAttribute struct {
+	Name  string
+	Value string
+}
+
+// AccessLog defines the access logs of a server.
+// LatencyMetrics keeps the access logs for the latency metrics, which the server would not inherit from the http context.
+type AccessLog struct {
+	Destination    string
+	Format         string
+	LatencyMetrics bool
+}
+
 // Location defines a location.
 type Location struct {
 	Path                     string
@@ -212,16 +311,23 @@ type Location struct {
 	JWTAuth                  *JWTAuth
 	BasicAuth                *BasicAuth
 	EgressMTLS               *EgressMTLS
+	UpstreamTLS              *UpstreamTLS
 	OIDC                     bool
 	APIKey                   *APIKey
+	CORS                     *CORS
+	ExternalAuth             *ExternalAuth
+	Cache                    *Cache
 	WAF                      *WAF
 	Dos                      *Dos
+	Tracing                  *Tracing
 	PoliciesErrorReturn      *Return
 	ServiceName              string
 	IsVSR                    bool
 	VSRName                  string
 	VSRNamespace             string
 	GRPCPass                 string
+	Mirror                   *MirrorLocation
+	GRPCErrorPageLocations   []ErrorPageLocation
 }
 
 // ReturnLocation defines a location for returning a fixed response.
@@ -232,6 +338,19 @@ type ReturnLocation struct {
 	Headers     []Header
 }
 
+// MirrorLocation defines an internal location for proxying mirrored requests to an upstream.
+type MirrorLocation struct {
+	Path                string
+	ProxyPass           string
+	ProxyConnectTimeout string
+	ProxyReadTimeout    string
+	ProxySendTimeout    string
+	RequestBody         bool
+	Percentage          int
+	Variable            string
+	UpstreamTLS         *UpstreamTLS
+}
+
 // SplitClient defines a split_clients.
 type SplitClient struct {
 	Source        string
@@ -324,6 +443,7 @@ type Distribution struct {
 type InternalRedirectLocation struct {
 	Path        string
 	Destination string
+	Internal    bool
 }
 
 // Map defines a map.
@@ -6,6 +6,7 @@ type TransportServerConfig struct {
 	Upstreams               []StreamUpstream
 	StreamSnippets          []string
 	Match                   *Match
+	LimitConnZones          []LimitConnZone
 	DisableIPV6             bool
 	DynamicSSLReloadEnabled bool
 	StaticSSLPath           string
@@ -59,13 +60,36 @@ type StreamServer struct {
 	SSL                      *StreamSSL
 	IPv4                     string
 	IPv6                     string
+	Allow                    []string
+	Deny                     []string
+	LimitConns               []LimitConn
+	ProxyUploadRate          string
+	ProxyDownloadRate        string
+	UpstreamTLS              *UpstreamTLS
+}
+
+// LimitConnZone defines a shared memory zone for limiting the number of connections per key.
+type LimitConnZone struct {
+	Key      string
+	ZoneName string
+	ZoneSize string
+}
+
+// LimitConn defines the maximum number of connections allowed per key of a zone.
+type LimitConn struct {
+	ZoneName    string
+	Connections int
 }
 
 // StreamSSL defines SSL configuration for a server.
 type StreamSSL struct {
-	Enabled        bool
-	Certificate    string
-	CertificateKey string
+	Enabled           bool
+	Certificate       string
+	CertificateKey    string
+	ClientCertificate string
+	ClientCrl         string
+	VerifyClient      string
+	VerifyDepth       int
 }
 
 // StreamHealthCheck defines a health check for a StreamUpstream in a StreamServer.
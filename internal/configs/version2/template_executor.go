@@ -13,6 +13,13 @@ const tlsPassthroughHostsTemplateString = `# mapping between TLS Passthrough hos
 {{ end }}
 `
 
+const cacheZonesTemplateString = `# proxy cache zones of VirtualServers
+{{- range $z := . }}
+proxy_cache_path {{ $z.Path }}{{ if $z.Levels }} levels={{ $z.Levels }}{{ end }} keys_zone={{ $z.Name }}:{{ $z.Size }}
+    {{- if $z.MaxSize }} max_size={{ $z.MaxSize }}{{ end }}{{ if $z.Inactive }} inactive={{ $z.Inactive }}{{ end }};
+{{- end }}
+`
+
 // TemplateExecutor executes NGINX configuration templates.
 type TemplateExecutor struct {
 	originalVirtualServerTemplate  *template.Template
@@ -20,6 +27,7 @@ type TemplateExecutor struct {
 	virtualServerTemplate          *template.Template
 	transportServerTemplate        *template.Template
 	tlsPassthroughHostsTemplate    *template.Template
+	cacheZonesTemplate             *template.Template
 }
 
 // NewTemplateExecutor creates a TemplateExecutor.
@@ -41,12 +49,18 @@ func NewTemplateExecutor(virtualServerTemplatePath string, transportServerTempla
 		return nil, err
 	}
 
+	cacheZonesTemplate, err := template.New("cacheZones").Parse(cacheZonesTemplateString)
+	if err != nil {
+		return nil, err
+	}
+
 	return &TemplateExecutor{
 		originalVirtualServerTemplate:  vsTemplate,
 		originalTrasportServerTemplate: tsTemplate,
 		virtualServerTemplate:          vsTemplate,
 		transportServerTemplate:        tsTemplate,
 		tlsPassthroughHostsTemplate:    tlsPassthroughHostsTemplate,
+		cacheZonesTemplate:             cacheZonesTemplate,
 	}, nil
 }
 
@@ -109,3 +123,13 @@ func (te *TemplateExecutor) ExecuteTLSPassthroughHostsTemplate(cfg *TLSPassthrou
 	}
 	return configBuffer.Bytes(), nil
 }
+
+// ExecuteCacheZonesTemplate generates the content of an NGINX configuration file with the proxy cache zones
+// shared by VirtualServers.
+func (te *TemplateExecutor) ExecuteCacheZonesTemplate(cfg *CacheZonesConfig) ([]byte, error) {
+	var configBuffer bytes.Buffer
+	if err := te.cacheZonesTemplate.Execute(&configBuffer, cfg); err != nil {
+		return nil, err
+	}
+	return configBuffer.Bytes(), nil
+}
@@ -243,6 +243,7 @@ var helperFunctions = template.FuncMap{
 	"makeHTTPListener":      makeHTTPListener,
 	"makeHTTPSListener":     makeHTTPSListener,
 	"makeSecretPath":        commonhelpers.MakeSecretPath,
+	"makeRoutePath":         commonhelpers.MakeRoutePath,
 	"makeHeaderQueryValue":  makeHeaderQueryValue,
 	"makeTransportListener": makeTransportListener,
 	"makeServerName":        makeServerName,
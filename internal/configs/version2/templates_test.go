@@ -414,6 +414,108 @@ func TestExecuteVirtualServerTemplate_RendersOSSTemplateWithHTTP2On(t *testing.T
 	t.Log(string(got))
 }
 
+func TestExecuteVirtualServerTemplate_RendersGRPCErrorPagesBeforeBuiltInGRPCErrorPages(t *testing.T) {
+	t.Parallel()
+	cfg := virtualServerCfg
+	cfg.Server.Locations = []Location{
+		{
+			Path:                 "/helloworld.Greeter/",
+			GRPCPass:             "grpc://grpc-app",
+			ProxyInterceptErrors: true,
+			ErrorPages: []ErrorPage{
+				{Name: "@grpc_error_page_0_0_404", Codes: "404", ResponseCode: 204},
+			},
+		},
+	}
+	cfg.Server.ErrorPageLocations = []ErrorPageLocation{
+		{
+			Name:        "@grpc_error_page_0_0_404",
+			DefaultType: "application/grpc",
+			Return:      &Return{},
+			Headers: []Header{
+				{Name: "content-type", Value: "application/grpc"},
+				{Name: "grpc-status", Value: "12"},
+				{Name: "grpc-message", Value: "no such method"},
+			},
+		},
+	}
+
+	for _, executor := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := executor.ExecuteVirtualServerTemplate(&cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// NGINX uses the first error_page for a status code, so the error pages of the route must come first
+		errorPage := bytes.Index(got, []byte(`error_page 404 =204 "@grpc_error_page_0_0_404";`))
+		builtInErrorPage := bytes.Index(got, []byte("error_page 404 = @grpc_unimplemented;"))
+		if errorPage == -1 || builtInErrorPage == -1 || errorPage > builtInErrorPage {
+			t.Errorf("want the error page of the route before the built-in gRPC error page in generated template:\n%s", got)
+		}
+		if !bytes.Contains(got, []byte(`add_header grpc-message "no such method" always;`)) {
+			t.Errorf("want the gRPC message of the error page in generated template:\n%s", got)
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersUpstreamTLSUnlessEgressMTLSIsSet(t *testing.T) {
+	t.Parallel()
+	upstreamTLS := &UpstreamTLS{
+		VerifyServer: true,
+		VerifyDepth:  2,
+		Protocols:    "TLSv1.2 TLSv1.3",
+		TrustedCert:  "/etc/nginx/secrets/default-tea-ca-secret-ca.crt",
+		SessionReuse: true,
+		ServerName:   true,
+		SSLName:      "tea.example.com",
+	}
+	cfg := virtualServerCfg
+	cfg.Server.Locations = []Location{
+		{
+			Path:        "/tea",
+			ProxyPass:   "https://vs_default_cafe_tea",
+			UpstreamTLS: upstreamTLS,
+		},
+		{
+			Path:        "/coffee",
+			ProxyPass:   "https://vs_default_cafe_coffee",
+			UpstreamTLS: upstreamTLS,
+			EgressMTLS: &EgressMTLS{
+				VerifyDepth:  1,
+				Protocols:    "TLSv1 TLSv1.1 TLSv1.2",
+				Ciphers:      "DEFAULT",
+				SessionReuse: true,
+				SSLName:      "$proxy_host",
+			},
+		},
+	}
+
+	wantStrings := []string{
+		"proxy_ssl_trusted_certificate /etc/nginx/secrets/default-tea-ca-secret-ca.crt;",
+		"proxy_ssl_verify on;",
+		"proxy_ssl_verify_depth 2;",
+		"proxy_ssl_protocols TLSv1.2 TLSv1.3;",
+		"proxy_ssl_server_name on;",
+		"proxy_ssl_name tea.example.com;",
+	}
+
+	for _, executor := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := executor.ExecuteVirtualServerTemplate(&cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, want := range wantStrings {
+			if count := bytes.Count(got, []byte(want)); count != 1 {
+				t.Errorf("want `%s` once in generated template, got %d times:\n%s", want, count, got)
+			}
+		}
+		if bytes.Contains(got, []byte("proxy_ssl_ciphers ;")) {
+			t.Errorf("unwant empty proxy_ssl_ciphers in generated template:\n%s", got)
+		}
+	}
+}
+
 func TestExecuteVirtualServerTemplate_RendersOSSTemplateWithHTTP2Off(t *testing.T) {
 	t.Parallel()
 	executor := newTmplExecutorNGINX(t)
@@ -633,6 +735,103 @@ func TestExecuteTemplateForTransportServerWithBackupServerForNGINXPlus(t *testin
 	t.Log(string(got))
 }
 
+func TestExecuteTemplateForTransportServerWithPolicies(t *testing.T) {
+	t.Parallel()
+
+	tsCfg := tsConfig()
+	tsCfg.LimitConnZones = []LimitConnZone{
+		{
+			Key:      "$binary_remote_addr",
+			ZoneName: "pol_cl_default_connection_limit_default_tcp_server",
+			ZoneSize: "10m",
+		},
+	}
+	tsCfg.Server.LimitConns = []LimitConn{
+		{
+			ZoneName:    "pol_cl_default_connection_limit_default_tcp_server",
+			Connections: 10,
+		},
+	}
+	tsCfg.Server.Allow = []string{"10.0.0.0/8"}
+	tsCfg.Server.ProxyUploadRate = "100k"
+	tsCfg.Server.ProxyDownloadRate = "1m"
+
+	wantDirectives := []string{
+		"limit_conn_zone $binary_remote_addr zone=pol_cl_default_connection_limit_default_tcp_server:10m;",
+		"limit_conn pol_cl_default_connection_limit_default_tcp_server 10;",
+		"allow 10.0.0.0/8;",
+		"deny all;",
+		"proxy_upload_rate 100k;",
+		"proxy_download_rate 1m;",
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteTransportServerTemplate(&tsCfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range wantDirectives {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in the transport server config", want)
+			}
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteTemplateForTransportServerWithClientVerificationAndUpstreamTLS(t *testing.T) {
+	t.Parallel()
+
+	tsCfg := tsConfig()
+	tsCfg.Server.SSL = &StreamSSL{
+		Enabled:           true,
+		Certificate:       "/etc/nginx/secrets/default-my-secret",
+		CertificateKey:    "/etc/nginx/secrets/default-my-secret",
+		ClientCertificate: "/etc/nginx/secrets/default-ca-secret-ca.crt",
+		ClientCrl:         "/etc/nginx/secrets/webapp.crl",
+		VerifyClient:      "on",
+		VerifyDepth:       2,
+	}
+	tsCfg.Server.UpstreamTLS = &UpstreamTLS{
+		VerifyServer: true,
+		VerifyDepth:  1,
+		TrustedCert:  "/etc/nginx/secrets/default-ca-secret-ca.crt",
+		SessionReuse: true,
+		ServerName:   true,
+		SSLName:      "tcp-app.example.com",
+	}
+
+	wantDirectives := []string{
+		"ssl_client_certificate /etc/nginx/secrets/default-ca-secret-ca.crt;",
+		"ssl_crl /etc/nginx/secrets/webapp.crl;",
+		"ssl_verify_client on;",
+		"ssl_verify_depth 2;",
+		"proxy_ssl on;",
+		"proxy_ssl_trusted_certificate /etc/nginx/secrets/default-ca-secret-ca.crt;",
+		"proxy_ssl_verify on;",
+		"proxy_ssl_verify_depth 1;",
+		"proxy_ssl_session_reuse on;",
+		"proxy_ssl_server_name on;",
+		"proxy_ssl_name tcp-app.example.com;",
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteTransportServerTemplate(&tsCfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range wantDirectives {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in the transport server config", want)
+			}
+		}
+		if bytes.Contains(got, []byte("proxy_ssl_protocols")) || bytes.Contains(got, []byte("proxy_ssl_ciphers")) {
+			t.Error("want no proxy_ssl_protocols and proxy_ssl_ciphers in the transport server config")
+		}
+		t.Log(string(got))
+	}
+}
+
 func TestTransportServerWithSSL(t *testing.T) {
 	t.Parallel()
 	executor := newTmplExecutorNGINXPlus(t)
@@ -750,6 +949,394 @@ func TestExecuteVirtualServerTemplateWithAPIKeyPolicyNGINXPlus(t *testing.T) {
 	t.Log(string(got))
 }
 
+func TestExecuteVirtualServerTemplateWithCORS(t *testing.T) {
+	t.Parallel()
+
+	vscfg := vsConfig()
+	vscfg.Maps = append(vscfg.Maps, Map{
+		Source:   "$http_origin",
+		Variable: "$pol_cors_default_cors_policy_default_cafe_origin",
+		Parameters: []Parameter{
+			{Value: "default", Result: `""`},
+			{Value: `"https://example.com"`, Result: "$http_origin"},
+		},
+	}, Map{
+		Source:   "$pol_cors_default_cors_policy_default_cafe_origin",
+		Variable: "$pol_cors_default_cors_policy_default_cafe_expose_headers",
+		Parameters: []Parameter{
+			{Value: "default", Result: `"X-Request-ID"`},
+			{Value: `""`, Result: `""`},
+		},
+	}, Map{
+		Source:   "$pol_cors_default_cors_policy_default_cafe_origin",
+		Variable: "$pol_cors_default_cors_policy_default_cafe_allow_credentials",
+		Parameters: []Parameter{
+			{Value: "default", Result: `"true"`},
+			{Value: `""`, Result: `""`},
+		},
+	})
+	vscfg.Server.Locations[0].CORS = &CORS{
+		OriginVariable:           "$pol_cors_default_cors_policy_default_cafe_origin",
+		PreflightVariable:        "$pol_cors_default_cors_policy_default_cafe_preflight",
+		AllowMethods:             "GET, POST",
+		AllowHeaders:             "Content-Type",
+		ExposeHeadersVariable:    "$pol_cors_default_cors_policy_default_cafe_expose_headers",
+		AllowCredentialsVariable: "$pol_cors_default_cors_policy_default_cafe_allow_credentials",
+		MaxAge:                   3600,
+	}
+
+	wantStrings := []string{
+		"map $http_origin $pol_cors_default_cors_policy_default_cafe_origin {",
+		"if ($pol_cors_default_cors_policy_default_cafe_preflight) {",
+		`add_header Access-Control-Allow-Origin "$pol_cors_default_cors_policy_default_cafe_origin" always;`,
+		`add_header Access-Control-Allow-Methods "GET, POST" always;`,
+		`add_header Access-Control-Allow-Headers "Content-Type" always;`,
+		`add_header Access-Control-Max-Age 3600 always;`,
+		"return 204;",
+		"map $pol_cors_default_cors_policy_default_cafe_origin $pol_cors_default_cors_policy_default_cafe_expose_headers {",
+		`add_header Access-Control-Expose-Headers $pol_cors_default_cors_policy_default_cafe_expose_headers always;`,
+		`add_header Access-Control-Allow-Credentials $pol_cors_default_cors_policy_default_cafe_allow_credentials always;`,
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithMirrorUpstreamTLS(t *testing.T) {
+	t.Parallel()
+
+	mirror := &MirrorLocation{
+		Path:                "/internal_location_mirror_coffee-v2_100",
+		ProxyPass:           "https://coffee-v2$request_uri",
+		ProxyConnectTimeout: "30s",
+		ProxyReadTimeout:    "31s",
+		ProxySendTimeout:    "32s",
+		RequestBody:         true,
+		Percentage:          100,
+		UpstreamTLS: &UpstreamTLS{
+			VerifyServer: true,
+			VerifyDepth:  2,
+			TrustedCert:  "/etc/nginx/secrets/default-coffee-ca",
+			SessionReuse: true,
+			ServerName:   true,
+			SSLName:      "coffee-v2.example.com",
+		},
+	}
+	vscfg := vsConfig()
+	vscfg.Server.Locations[0].Mirror = mirror
+	vscfg.Server.MirrorLocations = []MirrorLocation{*mirror}
+
+	wantStrings := []string{
+		"location /internal_location_mirror_coffee-v2_100 {",
+		"proxy_ssl_trusted_certificate /etc/nginx/secrets/default-coffee-ca;",
+		"proxy_ssl_verify on;",
+		"proxy_ssl_verify_depth 2;",
+		"proxy_ssl_server_name on;",
+		"proxy_ssl_name coffee-v2.example.com;",
+		"proxy_pass https://coffee-v2$request_uri;",
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithExternalAuth(t *testing.T) {
+	t.Parallel()
+
+	vscfg := vsConfig()
+	vscfg.Server.ExternalAuthLocations = []ExternalAuthLocation{
+		{
+			Path:           "/_pol_ext_auth_default_ext_auth_default_cafe",
+			ProxyPass:      "http://pol_ext_auth_default_ext_auth_default_cafe/check",
+			ForwardHeaders: []Header{{Name: "Authorization", Value: "$http_authorization"}},
+			SkipVariable:   "$pol_ext_auth_default_ext_auth_default_cafe_skip",
+			CacheZone:      "pol_ext_auth_default_ext_auth_default_cafe",
+			CacheKey:       "$request_method:$request_uri:$http_authorization",
+			CacheTTL:       "30s",
+		},
+	}
+	vscfg.Server.Locations[0].ExternalAuth = &ExternalAuth{
+		Path:        "/_pol_ext_auth_default_ext_auth_default_cafe",
+		URIVariable: "$pol_ext_auth_default_ext_auth_default_cafe_uri",
+		ResponseHeaders: []ExternalAuthHeader{
+			{
+				Name:             "X-User-ID",
+				Variable:         "$pol_ext_auth_default_ext_auth_default_cafe_x_user_id",
+				UpstreamVariable: "$upstream_http_x_user_id",
+			},
+		},
+	}
+
+	wantStrings := []string{
+		"location = /_pol_ext_auth_default_ext_auth_default_cafe {",
+		"if ($pol_ext_auth_default_ext_auth_default_cafe_skip) {",
+		"proxy_pass_request_headers off;",
+		"proxy_set_header Authorization $http_authorization;",
+		`proxy_cache_key "$request_method:$request_uri:$http_authorization";`,
+		"proxy_cache_valid 200 401 403 30s;",
+		"proxy_pass http://pol_ext_auth_default_ext_auth_default_cafe/check;",
+		"set $pol_ext_auth_default_ext_auth_default_cafe_uri $uri;\n        auth_request /_pol_ext_auth_default_ext_auth_default_cafe;",
+		"auth_request_set $pol_ext_auth_default_ext_auth_default_cafe_x_user_id $upstream_http_x_user_id;",
+		"proxy_set_header X-User-ID $pol_ext_auth_default_ext_auth_default_cafe_x_user_id;",
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithInternalRedirectLocation(t *testing.T) {
+	t.Parallel()
+
+	vscfg := vsConfig()
+	vscfg.Server.InternalRedirectLocations = []InternalRedirectLocation{
+		{
+			Path:        "/internal_location_splits_0_split_0",
+			Destination: "$vs_default_cafe_splits_1",
+			Internal:    true,
+		},
+	}
+
+	wantStrings := []string{
+		"location /internal_location_splits_0_split_0 {\n        internal;\n        rewrite ^ $vs_default_cafe_splits_1 last;",
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithCache(t *testing.T) {
+	t.Parallel()
+
+	vscfg := vsConfig()
+	vscfg.Server.Locations[0].Cache = &Cache{
+		Zone:        "pol_cache_default_cafe_cache",
+		Key:         "${scheme}${host}${request_uri}",
+		Valid:       []string{"200 302 10m", "1m"},
+		Lock:        true,
+		LockTimeout: "5s",
+		UseStale:    "error timeout updating",
+		Bypass:      `"${cookie_nocache}" "${arg_nocache}"`,
+	}
+
+	wantStrings := []string{
+		"proxy_cache pol_cache_default_cafe_cache;",
+		`proxy_cache_key "${scheme}${host}${request_uri}";`,
+		"proxy_cache_valid 200 302 10m;",
+		"proxy_cache_valid 1m;",
+		"proxy_cache_lock on;",
+		"proxy_cache_lock_timeout 5s;",
+		"proxy_cache_use_stale error timeout updating;",
+		`proxy_cache_bypass "${cookie_nocache}" "${arg_nocache}";`,
+		`proxy_no_cache "${cookie_nocache}" "${arg_nocache}";`,
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Error(err)
+		}
+		if bytes.Contains(got, []byte("proxy_cache_lock_age")) {
+			t.Error("want no proxy_cache_lock_age in generated template")
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteCacheZonesTemplate(t *testing.T) {
+	t.Parallel()
+
+	cfg := CacheZonesConfig{
+		{
+			Name:     "pol_cache_default_cafe_cache",
+			Path:     "/var/cache/nginx/pol_cache_default_cafe_cache",
+			Levels:   "1:2",
+			Size:     "10m",
+			MaxSize:  "1g",
+			Inactive: "1h",
+		},
+		{
+			Name:     "pol_ext_auth_default_ext_auth_default_cafe",
+			Path:     "/var/cache/nginx/pol_ext_auth_default_ext_auth_default_cafe",
+			Size:     "1m",
+			Inactive: "30s",
+		},
+	}
+
+	wantStrings := []string{
+		"proxy_cache_path /var/cache/nginx/pol_cache_default_cafe_cache levels=1:2 keys_zone=pol_cache_default_cafe_cache:10m max_size=1g inactive=1h;",
+		"proxy_cache_path /var/cache/nginx/pol_ext_auth_default_ext_auth_default_cafe keys_zone=pol_ext_auth_default_ext_auth_default_cafe:1m inactive=30s;",
+	}
+
+	got, err := newTmplExecutorNGINX(t).ExecuteCacheZonesTemplate(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range wantStrings {
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want %q in generated template", want)
+		}
+	}
+	snaps.MatchSnapshot(t, string(got))
+	t.Log(string(got))
+}
+
+func TestExecuteVirtualServerTemplateWithMirror(t *testing.T) {
+	t.Parallel()
+
+	mirror := &MirrorLocation{
+		Path:                "/internal_location_mirror_coffee-v2_10_no_body",
+		ProxyPass:           "http://coffee-v2$request_uri",
+		ProxyConnectTimeout: "30s",
+		ProxyReadTimeout:    "31s",
+		ProxySendTimeout:    "32s",
+		RequestBody:         false,
+		Percentage:          10,
+		Variable:            "$internal_location_mirror_coffee_v2_10_no_body",
+	}
+	vscfg := vsConfig()
+	vscfg.Server.Locations[0].Mirror = mirror
+	vscfg.Server.MirrorLocations = []MirrorLocation{*mirror}
+
+	wantStrings := []string{
+		"mirror /internal_location_mirror_coffee-v2_10_no_body;",
+		"mirror_request_body off;",
+		"location /internal_location_mirror_coffee-v2_10_no_body {",
+		`if ($internal_location_mirror_coffee_v2_10_no_body = "") {`,
+		"proxy_pass_request_body off;",
+		"proxy_pass http://coffee-v2$request_uri;",
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithTracing(t *testing.T) {
+	t.Parallel()
+
+	vscfg := vsConfig()
+	vscfg.Server.Tracing = &Tracing{
+		Trace:          "$otel_trace_sampler",
+		SpanAttributes: []SpanAttribute{{Name: "tenant", Value: "cafe"}},
+	}
+	vscfg.Server.Locations[0].Tracing = &Tracing{
+		Trace:          "off",
+		SpanAttributes: []SpanAttribute{{Name: "request", Value: "${request_uri}"}},
+	}
+
+	wantStrings := []string{
+		"otel_trace $otel_trace_sampler;",
+		`otel_span_attr tenant "cafe";`,
+		"otel_trace off;",
+		`otel_span_attr request "${request_uri}";`,
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithAccessLog(t *testing.T) {
+	t.Parallel()
+
+	vscfg := vsConfig()
+	vscfg.Server.AccessLog = &AccessLog{
+		Destination:    "/var/log/nginx/cafe.log",
+		Format:         "json",
+		LatencyMetrics: true,
+	}
+
+	wantStrings := []string{
+		"access_log /var/log/nginx/cafe.log json;",
+		"access_log syslog:server=unix:/var/lib/nginx/nginx-syslog.sock,nohostname,tag=nginx response_time;",
+		`set $route_path "@loc0";`,
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
 func vsConfig() VirtualServerConfig {
 	return VirtualServerConfig{
 		LimitReqZones: []LimitReqZone{
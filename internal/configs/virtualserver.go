@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -36,27 +37,32 @@ const (
 	defaultLogOutput                                = "syslog:server=localhost:514"
 )
 
-var grpcConflictingErrors = map[int]bool{
-	400: true,
-	401: true,
-	403: true,
-	404: true,
-	405: true,
-	408: true,
-	413: true,
-	414: true,
-	415: true,
-	426: true,
-	429: true,
-	495: true,
-	496: true,
-	497: true,
-	500: true,
-	501: true,
-	502: true,
-	503: true,
-	504: true,
-}
+// grpcStatusCodes maps the HTTP status codes of errors to the gRPC status codes
+// of the built-in error pages of the locations for gRPC upstreams.
+var grpcStatusCodes = map[int]int{
+	400: 13,
+	401: 16,
+	403: 7,
+	404: 12,
+	405: 13,
+	408: 4,
+	413: 8,
+	414: 8,
+	415: 13,
+	426: 13,
+	429: 14,
+	495: 16,
+	496: 16,
+	497: 13,
+	500: 13,
+	501: 13,
+	502: 14,
+	503: 14,
+	504: 14,
+}
+
+// grpcUnknownStatusCode is the gRPC status code for the errors without a corresponding gRPC status code.
+const grpcUnknownStatusCode = 2
 
 var incompatibleLBMethodsForSlowStart = map[string]bool{
 	"random":                          true,
@@ -102,6 +108,8 @@ type VirtualServerEx struct {
 	LogConfRefs         map[string]*unstructured.Unstructured
 	DosProtectedRefs    map[string]*unstructured.Unstructured
 	DosProtectedEx      map[string]*DosEx
+	// CanaryWeights are the current weights of the second Split of the Routes with a Canary, by the path of the Route.
+	CanaryWeights map[string]int
 }
 
 func (vsx *VirtualServerEx) String() string {
@@ -292,6 +300,7 @@ type virtualServerConfigurator struct {
 	DynamicWeightChangesReload bool
 	bundleValidator            bundleValidator
 	IngressControllerReplicas  int
+	enableLatencyMetrics       bool
 }
 
 type oidcPolicyCfg struct {
@@ -341,6 +350,7 @@ func newVirtualServerConfigurator(
 		StaticSSLPath:              staticParams.StaticSSLPath,
 		DynamicWeightChangesReload: staticParams.DynamicWeightChangesReload,
 		bundleValidator:            bundleValidator,
+		enableLatencyMetrics:       staticParams.EnableLatencyMetrics,
 	}
 }
 
@@ -448,6 +458,9 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 	// necessary for generateLocation to know what Upstream each Location references
 	crUpstreams := make(map[string]conf_v1.Upstream)
 
+	// upstreamTLS maps an UpstreamName to the TLS configuration of the connections to its servers
+	upstreamTLS := make(map[string]*version2.UpstreamTLS)
+
 	virtualServerUpstreamNamer := NewUpstreamNamerForVirtualServer(vsEx.VirtualServer)
 	var upstreams []version2.Upstream
 	var statusMatches []version2.StatusMatch
@@ -470,11 +483,12 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 
 		// isExternalNameSvc is always false for OSS
 		_, isExternalNameSvc := vsEx.ExternalNameSvcs[GenerateExternalNameSvcKey(upstreamNamespace, u.Service)]
-		ups := vsc.generateUpstream(vsEx.VirtualServer, upstreamName, u, isExternalNameSvc, endpoints, backupEndpoints)
+		ups := vsc.generateUpstream(vsEx.VirtualServer, upstreamName, u, isExternalNameSvc, endpoints, backupEndpoints, vsEx.SecretRefs)
 		upstreams = append(upstreams, ups)
 
 		u.TLS.Enable = isTLSEnabled(u, vsc.spiffeCerts, vsEx.VirtualServer.Spec.InternalRoute)
 		crUpstreams[upstreamName] = u
+		vsc.addUpstreamTLS(vsEx.VirtualServer, upstreamTLS, upstreamName, u, ups.TLS, policiesCfg.EgressMTLS != nil)
 
 		if hc := generateHealthCheck(u, upstreamName, vsc.cfgParams); hc != nil {
 			healthChecks = append(healthChecks, *hc)
@@ -501,10 +515,11 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 
 			// isExternalNameSvc is always false for OSS
 			_, isExternalNameSvc := vsEx.ExternalNameSvcs[GenerateExternalNameSvcKey(upstreamNamespace, u.Service)]
-			ups := vsc.generateUpstream(vsr, upstreamName, u, isExternalNameSvc, endpoints, backup)
+			ups := vsc.generateUpstream(vsr, upstreamName, u, isExternalNameSvc, endpoints, backup, vsEx.SecretRefs)
 			upstreams = append(upstreams, ups)
 			u.TLS.Enable = isTLSEnabled(u, vsc.spiffeCerts, vsEx.VirtualServer.Spec.InternalRoute)
 			crUpstreams[upstreamName] = u
+			vsc.addUpstreamTLS(vsr, upstreamTLS, upstreamName, u, ups.TLS, policiesCfg.EgressMTLS != nil)
 
 			if hc := generateHealthCheck(u, upstreamName, vsc.cfgParams); hc != nil {
 				healthChecks = append(healthChecks, *hc)
@@ -523,6 +538,9 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 	var returnLocations []version2.ReturnLocation
 	var splitClients []version2.SplitClient
 	var maps []version2.Map
+	var externalAuthLocations []version2.ExternalAuthLocation
+	var externalAuthUpstreams []externalAuthUpstream
+	var cacheZones []version2.CacheZone
 	var errorPageLocations []version2.ErrorPageLocation
 	var keyValZones []version2.KeyValZone
 	var keyVals []version2.KeyVal
@@ -531,6 +549,7 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 	vsrErrorPagesRouteIndex := make(map[string]int)
 	vsrLocationSnippetsFromVs := make(map[string]string)
 	vsrPoliciesFromVs := make(map[string][]conf_v1.PolicyReference)
+	vsrTracingFromVs := make(map[string]*conf_v1.Tracing)
 	isVSR := false
 	matchesRoutes := 0
 
@@ -538,6 +557,7 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 
 	// generates config for VirtualServer routes
 	for _, r := range vsEx.VirtualServer.Spec.Routes {
+		r = applyCanaryWeight(r, vsEx.CanaryWeights)
 		errorPages := errorPageDetails{
 			pages: r.ErrorPages,
 			index: len(errorPageLocations),
@@ -568,6 +588,11 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 				vsrPoliciesFromVs[name] = r.Policies
 			}
 
+			// store route tracing for the referenced VirtualServerRoute in case they don't define their own
+			if r.Tracing != nil {
+				vsrTracingFromVs[name] = r.Tracing
+			}
+
 			continue
 		}
 
@@ -582,6 +607,31 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		if policiesCfg.OIDC {
 			routePoliciesCfg.OIDC = policiesCfg.OIDC
 		}
+		if routePoliciesCfg.Retry == nil {
+			routePoliciesCfg.Retry = policiesCfg.Retry
+		}
+		if routePoliciesCfg.CORS == nil {
+			routePoliciesCfg.CORS = policiesCfg.CORS
+		}
+		if routePoliciesCfg.ExternalAuth == nil {
+			routePoliciesCfg.ExternalAuth = policiesCfg.ExternalAuth
+		}
+		if routePoliciesCfg.Cache == nil {
+			routePoliciesCfg.Cache = policiesCfg.Cache
+		}
+		if externalAuthConflictsWithAPIKey(routePoliciesCfg, policiesCfg) {
+			vsc.addWarningf(ownerDetails.owner, "External auth policy can't be used together with an API Key policy")
+			routePoliciesCfg.ExternalAuth = nil
+			routePoliciesCfg.ErrorReturn = &version2.Return{Code: 500}
+		}
+		if routePoliciesCfg.ExternalAuthLocation != nil {
+			externalAuthLocations = append(externalAuthLocations, *routePoliciesCfg.ExternalAuthLocation)
+		}
+		if routePoliciesCfg.ExternalAuthUpstream != nil {
+			externalAuthUpstreams = append(externalAuthUpstreams, *routePoliciesCfg.ExternalAuthUpstream)
+		}
+		maps = append(maps, routePoliciesCfg.Maps...)
+		cacheZones = append(cacheZones, routePoliciesCfg.CacheZones...)
 		if routePoliciesCfg.JWKSAuthEnabled {
 			policiesCfg.JWKSAuthEnabled = routePoliciesCfg.JWKSAuthEnabled
 
@@ -607,12 +657,14 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		limitReqZones = append(limitReqZones, routePoliciesCfg.LimitReqZones...)
 
 		dosRouteCfg := generateDosCfg(dosResources[r.Path])
+		tracingRouteCfg := vsc.generateTracing(vsEx.VirtualServer, r.Tracing)
 
 		if len(r.Matches) > 0 {
 			cfg := generateMatchesConfig(
 				r,
 				virtualServerUpstreamNamer,
 				crUpstreams,
+				upstreamTLS,
 				VariableNamer,
 				matchesRoutes,
 				len(splitClients),
@@ -628,6 +680,8 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			)
 			addPoliciesCfgToLocations(routePoliciesCfg, cfg.Locations)
 			addDosConfigToLocations(dosRouteCfg, cfg.Locations)
+			addTracingToLocations(tracingRouteCfg, cfg.Locations)
+			internalRedirectLocations = append(internalRedirectLocations, addRetrySamplingToRoutingCfg(&cfg, routePoliciesCfg.Retry, VariableNamer, len(splitClients))...)
 
 			maps = append(maps, cfg.Maps...)
 			locations = append(locations, cfg.Locations...)
@@ -639,10 +693,12 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			twoWaySplitClients = append(twoWaySplitClients, cfg.TwoWaySplitClients...)
 			matchesRoutes++
 		} else if len(r.Splits) > 0 {
-			cfg := generateDefaultSplitsConfig(r, virtualServerUpstreamNamer, crUpstreams, VariableNamer, len(splitClients),
+			cfg := generateDefaultSplitsConfig(r, virtualServerUpstreamNamer, crUpstreams, upstreamTLS, VariableNamer, len(splitClients),
 				vsc.cfgParams, errorPages, r.Path, vsLocSnippets, vsc.enableSnippets, len(returnLocations), isVSR, "", "", vsc.warnings, vsc.DynamicWeightChangesReload)
 			addPoliciesCfgToLocations(routePoliciesCfg, cfg.Locations)
 			addDosConfigToLocations(dosRouteCfg, cfg.Locations)
+			addTracingToLocations(tracingRouteCfg, cfg.Locations)
+			internalRedirectLocations = append(internalRedirectLocations, addRetrySamplingToRoutingCfg(&cfg, routePoliciesCfg.Retry, VariableNamer, len(splitClients))...)
 			splitClients = append(splitClients, cfg.SplitClients...)
 			locations = append(locations, cfg.Locations...)
 			internalRedirectLocations = append(internalRedirectLocations, cfg.InternalRedirectLocation)
@@ -657,12 +713,27 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 
 			proxySSLName := generateProxySSLName(upstream.Service, vsEx.VirtualServer.Namespace)
 
-			loc, returnLoc := generateLocation(r.Path, upstreamName, upstream, r.Action, vsc.cfgParams, errorPages, false,
-				proxySSLName, r.Path, vsLocSnippets, vsc.enableSnippets, len(returnLocations), isVSR, "", "", vsc.warnings)
+			// the requests are redirected to the internal locations of the retry sampling
+			retrySampling := routePoliciesCfg.Retry.hasSampling() && isProxyingAction(r.Action)
+
+			loc, returnLoc := generateLocation(r.Path, upstreamName, upstream, r.Action, vsc.cfgParams, errorPages, retrySampling,
+				proxySSLName, r.Path, vsLocSnippets, vsc.enableSnippets, len(returnLocations), isVSR, "", "", vsc.warnings,
+				virtualServerUpstreamNamer, crUpstreams, upstreamTLS)
 			addPoliciesCfgToLocation(routePoliciesCfg, &loc)
 			loc.Dos = dosRouteCfg
-
-			locations = append(locations, loc)
+			loc.Tracing = tracingRouteCfg
+
+			if retrySampling {
+				retryLocs, splitClient := generateRetrySamplingLocations(loc, routePoliciesCfg.Retry.Sampling, VariableNamer, len(splitClients))
+				locations = append(locations, retryLocs...)
+				splitClients = append(splitClients, splitClient)
+				internalRedirectLocations = append(internalRedirectLocations, version2.InternalRedirectLocation{
+					Path:        r.Path,
+					Destination: splitClient.Variable,
+				})
+			} else {
+				locations = append(locations, loc)
+			}
 			if returnLoc != nil {
 				returnLocations = append(returnLocations, *returnLoc)
 			}
@@ -674,6 +745,7 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		isVSR := true
 		upstreamNamer := NewUpstreamNamerForVirtualServerRoute(vsEx.VirtualServer, vsr)
 		for _, r := range vsr.Spec.Subroutes {
+			r = applyCanaryWeight(r, vsEx.CanaryWeights)
 			errorPages := errorPageDetails{
 				pages: r.ErrorPages,
 				index: len(errorPageLocations),
@@ -722,6 +794,31 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			if policiesCfg.OIDC {
 				routePoliciesCfg.OIDC = policiesCfg.OIDC
 			}
+			if routePoliciesCfg.Retry == nil {
+				routePoliciesCfg.Retry = policiesCfg.Retry
+			}
+			if routePoliciesCfg.CORS == nil {
+				routePoliciesCfg.CORS = policiesCfg.CORS
+			}
+			if routePoliciesCfg.ExternalAuth == nil {
+				routePoliciesCfg.ExternalAuth = policiesCfg.ExternalAuth
+			}
+			if routePoliciesCfg.Cache == nil {
+				routePoliciesCfg.Cache = policiesCfg.Cache
+			}
+			if externalAuthConflictsWithAPIKey(routePoliciesCfg, policiesCfg) {
+				vsc.addWarningf(ownerDetails.owner, "External auth policy can't be used together with an API Key policy")
+				routePoliciesCfg.ExternalAuth = nil
+				routePoliciesCfg.ErrorReturn = &version2.Return{Code: 500}
+			}
+			if routePoliciesCfg.ExternalAuthLocation != nil {
+				externalAuthLocations = append(externalAuthLocations, *routePoliciesCfg.ExternalAuthLocation)
+			}
+			if routePoliciesCfg.ExternalAuthUpstream != nil {
+				externalAuthUpstreams = append(externalAuthUpstreams, *routePoliciesCfg.ExternalAuthUpstream)
+			}
+			maps = append(maps, routePoliciesCfg.Maps...)
+			cacheZones = append(cacheZones, routePoliciesCfg.CacheZones...)
 			if routePoliciesCfg.JWKSAuthEnabled {
 				policiesCfg.JWKSAuthEnabled = routePoliciesCfg.JWKSAuthEnabled
 
@@ -749,11 +846,21 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 
 			dosRouteCfg := generateDosCfg(dosResources[r.Path])
 
+			// use the VirtualServer route tracing if the route does not define any
+			tracingOwner := runtime.Object(vsr)
+			tracing := r.Tracing
+			if tracing == nil {
+				tracingOwner = vsEx.VirtualServer
+				tracing = vsrTracingFromVs[vsrNamespaceName]
+			}
+			tracingRouteCfg := vsc.generateTracing(tracingOwner, tracing)
+
 			if len(r.Matches) > 0 {
 				cfg := generateMatchesConfig(
 					r,
 					upstreamNamer,
 					crUpstreams,
+					upstreamTLS,
 					VariableNamer,
 					matchesRoutes,
 					len(splitClients),
@@ -770,6 +877,8 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 				)
 				addPoliciesCfgToLocations(routePoliciesCfg, cfg.Locations)
 				addDosConfigToLocations(dosRouteCfg, cfg.Locations)
+				addTracingToLocations(tracingRouteCfg, cfg.Locations)
+				internalRedirectLocations = append(internalRedirectLocations, addRetrySamplingToRoutingCfg(&cfg, routePoliciesCfg.Retry, VariableNamer, len(splitClients))...)
 
 				maps = append(maps, cfg.Maps...)
 				locations = append(locations, cfg.Locations...)
@@ -781,10 +890,12 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 				twoWaySplitClients = append(twoWaySplitClients, cfg.TwoWaySplitClients...)
 				matchesRoutes++
 			} else if len(r.Splits) > 0 {
-				cfg := generateDefaultSplitsConfig(r, upstreamNamer, crUpstreams, VariableNamer, len(splitClients), vsc.cfgParams,
+				cfg := generateDefaultSplitsConfig(r, upstreamNamer, crUpstreams, upstreamTLS, VariableNamer, len(splitClients), vsc.cfgParams,
 					errorPages, r.Path, locSnippets, vsc.enableSnippets, len(returnLocations), isVSR, vsr.Name, vsr.Namespace, vsc.warnings, vsc.DynamicWeightChangesReload)
 				addPoliciesCfgToLocations(routePoliciesCfg, cfg.Locations)
 				addDosConfigToLocations(dosRouteCfg, cfg.Locations)
+				addTracingToLocations(tracingRouteCfg, cfg.Locations)
+				internalRedirectLocations = append(internalRedirectLocations, addRetrySamplingToRoutingCfg(&cfg, routePoliciesCfg.Retry, VariableNamer, len(splitClients))...)
 
 				splitClients = append(splitClients, cfg.SplitClients...)
 				locations = append(locations, cfg.Locations...)
@@ -799,12 +910,27 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 				upstream := crUpstreams[upstreamName]
 				proxySSLName := generateProxySSLName(upstream.Service, vsr.Namespace)
 
-				loc, returnLoc := generateLocation(r.Path, upstreamName, upstream, r.Action, vsc.cfgParams, errorPages, false,
-					proxySSLName, r.Path, locSnippets, vsc.enableSnippets, len(returnLocations), isVSR, vsr.Name, vsr.Namespace, vsc.warnings)
+				// the requests are redirected to the internal locations of the retry sampling
+				retrySampling := routePoliciesCfg.Retry.hasSampling() && isProxyingAction(r.Action)
+
+				loc, returnLoc := generateLocation(r.Path, upstreamName, upstream, r.Action, vsc.cfgParams, errorPages, retrySampling,
+					proxySSLName, r.Path, locSnippets, vsc.enableSnippets, len(returnLocations), isVSR, vsr.Name, vsr.Namespace, vsc.warnings,
+					upstreamNamer, crUpstreams, upstreamTLS)
 				addPoliciesCfgToLocation(routePoliciesCfg, &loc)
 				loc.Dos = dosRouteCfg
-
-				locations = append(locations, loc)
+				loc.Tracing = tracingRouteCfg
+
+				if retrySampling {
+					retryLocs, splitClient := generateRetrySamplingLocations(loc, routePoliciesCfg.Retry.Sampling, VariableNamer, len(splitClients))
+					locations = append(locations, retryLocs...)
+					splitClients = append(splitClients, splitClient)
+					internalRedirectLocations = append(internalRedirectLocations, version2.InternalRedirectLocation{
+						Path:        r.Path,
+						Destination: splitClient.Variable,
+					})
+				} else {
+					locations = append(locations, loc)
+				}
 				if returnLoc != nil {
 					returnLocations = append(returnLocations, *returnLoc)
 				}
@@ -815,6 +941,20 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 	for mapName, apiKeyClients := range policiesCfg.APIKeyClientMap {
 		maps = append(maps, *generateAPIKeyClientMap(mapName, apiKeyClients))
 	}
+	maps = append(maps, policiesCfg.Maps...)
+	if policiesCfg.ExternalAuthLocation != nil {
+		externalAuthLocations = append(externalAuthLocations, *policiesCfg.ExternalAuthLocation)
+	}
+	if policiesCfg.ExternalAuthUpstream != nil {
+		externalAuthUpstreams = append(externalAuthUpstreams, *policiesCfg.ExternalAuthUpstream)
+	}
+	cacheZones = append(cacheZones, policiesCfg.CacheZones...)
+
+	// generate upstreams for the Services of the external auth policies
+	for _, u := range removeDuplicateExternalAuthUpstreams(externalAuthUpstreams) {
+		endpoints := vsc.generateEndpointsForUpstream(vsEx.VirtualServer, u.Namespace, u.Upstream, vsEx)
+		upstreams = append(upstreams, vsc.generateUpstream(vsEx.VirtualServer, u.Name, u.Upstream, false, endpoints, nil, vsEx.SecretRefs))
+	}
 
 	httpSnippets := generateSnippets(vsc.enableSnippets, vsEx.VirtualServer.Spec.HTTPSnippets, []string{})
 	serverSnippets := generateSnippets(
@@ -827,10 +967,15 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		return upstreams[i].Name < upstreams[j].Name
 	})
 
+	mirrorLocations, mirrorSplitClients := generateMirrorLocations(locations)
+	splitClients = append(splitClients, mirrorSplitClients...)
+	errorPageLocations = append(errorPageLocations, generateGRPCErrorPageLocations(locations)...)
+
 	vsCfg := version2.VirtualServerConfig{
 		Upstreams:     upstreams,
 		SplitClients:  splitClients,
-		Maps:          maps,
+		Maps:          removeDuplicateMaps(maps),
+		CacheZones:    removeDuplicateCacheZones(cacheZones),
 		StatusMatches: statusMatches,
 		LimitReqZones: removeDuplicateLimitReqZones(limitReqZones),
 		HTTPSnippets:  httpSnippets,
@@ -855,6 +1000,8 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			InternalRedirectLocations: internalRedirectLocations,
 			Locations:                 locations,
 			ReturnLocations:           returnLocations,
+			MirrorLocations:           mirrorLocations,
+			ExternalAuthLocations:     removeDuplicateExternalAuthLocations(externalAuthLocations),
 			HealthChecks:              healthChecks,
 			TLSRedirect:               tlsRedirectConfig,
 			ErrorPageLocations:        errorPageLocations,
@@ -874,6 +1021,8 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			OIDC:                      vsc.oidcPolCfg.oidc,
 			WAF:                       policiesCfg.WAF,
 			Dos:                       dosCfg,
+			Tracing:                   vsc.generateTracing(vsEx.VirtualServer, vsEx.VirtualServer.Spec.Tracing),
+			AccessLog:                 vsc.generateAccessLog(vsEx.VirtualServer),
 			PoliciesErrorReturn:       policiesCfg.ErrorReturn,
 			VSNamespace:               vsEx.VirtualServer.Namespace,
 			VSName:                    vsEx.VirtualServer.Name,
@@ -892,25 +1041,56 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 }
 
 type policiesCfg struct {
-	Allow           []string
-	Deny            []string
-	LimitReqOptions version2.LimitReqOptions
-	LimitReqZones   []version2.LimitReqZone
-	LimitReqs       []version2.LimitReq
-	JWTAuth         *version2.JWTAuth
-	JWTAuthList     map[string]*version2.JWTAuth
-	JWKSAuthEnabled bool
-	BasicAuth       *version2.BasicAuth
-	IngressMTLS     *version2.IngressMTLS
-	EgressMTLS      *version2.EgressMTLS
-	OIDC            bool
-	APIKeyEnabled   bool
-	APIKey          *version2.APIKey
-	APIKeyClients   []apiKeyClient
-	APIKeyClientMap map[string][]apiKeyClient
-	WAF             *version2.WAF
-	ErrorReturn     *version2.Return
-	BundleValidator bundleValidator
+	Allow                []string
+	Deny                 []string
+	LimitReqOptions      version2.LimitReqOptions
+	LimitReqZones        []version2.LimitReqZone
+	LimitReqs            []version2.LimitReq
+	JWTAuth              *version2.JWTAuth
+	JWTAuthList          map[string]*version2.JWTAuth
+	JWKSAuthEnabled      bool
+	BasicAuth            *version2.BasicAuth
+	IngressMTLS          *version2.IngressMTLS
+	EgressMTLS           *version2.EgressMTLS
+	OIDC                 bool
+	APIKeyEnabled        bool
+	APIKey               *version2.APIKey
+	APIKeyClients        []apiKeyClient
+	APIKeyClientMap      map[string][]apiKeyClient
+	WAF                  *version2.WAF
+	Retry                *retryCfg
+	CORS                 *version2.CORS
+	ExternalAuth         *version2.ExternalAuth
+	ExternalAuthLocation *version2.ExternalAuthLocation
+	ExternalAuthUpstream *externalAuthUpstream
+	Cache                *version2.Cache
+	CacheZones           []version2.CacheZone
+	Maps                 []version2.Map
+	ErrorReturn          *version2.Return
+	BundleValidator      bundleValidator
+}
+
+// externalAuthUpstream defines the upstream of the Service of an external auth policy.
+type externalAuthUpstream struct {
+	Name      string
+	Namespace string
+	Upstream  conf_v1.Upstream
+}
+
+// retryCfg defines the retries of requests to the next upstream server, which override the retries of the upstream.
+type retryCfg struct {
+	NextUpstream        string
+	NextUpstreamTries   int
+	NextUpstreamTimeout string
+	PerTryTimeout       string
+	// Sampling is the percentage of the requests that can be retried. The requests are sampled
+	// independently of the rate of the retries.
+	Sampling int
+}
+
+// hasSampling reports whether the retries are limited to a share of the requests.
+func (r *retryCfg) hasSampling() bool {
+	return r != nil && r.Sampling > 0 && r.Sampling < 100
 }
 
 type bundleValidator interface {
@@ -1440,6 +1620,356 @@ func generateAPIKeyClientMap(mapName string, apiKeyClients []apiKeyClient) *vers
 	}
 }
 
+func (p *policiesCfg) addRetryConfig(retry *conf_v1.Retry, polKey string) *validationResults {
+	res := newValidationResults()
+	if p.Retry != nil {
+		res.addWarningf("Multiple retry policies in the same context is not valid. Retry policy %s will be ignored", polKey)
+		return res
+	}
+
+	nextUpstream := "error timeout"
+	if len(retry.RetryOn) > 0 {
+		nextUpstream = strings.Join(retry.RetryOn, " ")
+	}
+
+	sampling := generateIntFromPointer(retry.Sampling, 100)
+	if sampling == 0 {
+		nextUpstream = "off"
+	}
+
+	p.Retry = &retryCfg{
+		NextUpstream:        nextUpstream,
+		NextUpstreamTries:   generateIntFromPointer(retry.Tries, 0),
+		NextUpstreamTimeout: generateTimeWithDefault(retry.Timeout, "0s"),
+		PerTryTimeout:       generateTime(retry.PerTryTimeout),
+		Sampling:            sampling,
+	}
+	return res
+}
+
+func (p *policiesCfg) addCORSConfig(
+	cors *conf_v1.CORS,
+	polKey string,
+	polNamespace string,
+	polName string,
+	vsNamespace string,
+	vsName string,
+) *validationResults {
+	res := newValidationResults()
+	if p.CORS != nil {
+		res.addWarningf("Multiple CORS policies in the same context is not valid. CORS policy %s will be ignored", polKey)
+		return res
+	}
+
+	allowCredentials := generateBool(cors.AllowCredentials, false)
+	varPrefix := fmt.Sprintf("$pol_cors_%v_%v_%v_%v", rfc1123ToSnake(polNamespace), rfc1123ToSnake(polName), rfc1123ToSnake(vsNamespace), rfc1123ToSnake(vsName))
+	originMap := generateCORSOriginMap(varPrefix+"_origin", cors.AllowOrigins, allowCredentials)
+	preflightMap := version2.Map{
+		Source:   `"$request_method:$http_access_control_request_method"`,
+		Variable: varPrefix + "_preflight",
+		Parameters: []version2.Parameter{
+			{Value: "default", Result: "0"},
+			{Value: `"~^OPTIONS:."`, Result: "1"},
+		},
+	}
+
+	allowHeaders := "$http_access_control_request_headers"
+	if len(cors.AllowHeaders) > 0 {
+		allowHeaders = strings.Join(cors.AllowHeaders, ", ")
+	}
+
+	p.CORS = &version2.CORS{
+		OriginVariable:    originMap.Variable,
+		PreflightVariable: preflightMap.Variable,
+		AllowMethods:      strings.Join(generateCORSAllowMethods(cors.AllowMethods), ", "),
+		AllowHeaders:      allowHeaders,
+		MaxAge:            generateIntFromPointer(cors.MaxAge, 0),
+	}
+	p.Maps = append(p.Maps, originMap, preflightMap)
+
+	if len(cors.ExposeHeaders) > 0 {
+		exposeHeadersMap := generateCORSAllowedOriginMap(varPrefix+"_expose_headers", originMap.Variable, fmt.Sprintf(`"%v"`, strings.Join(cors.ExposeHeaders, ", ")))
+		p.CORS.ExposeHeadersVariable = exposeHeadersMap.Variable
+		p.Maps = append(p.Maps, exposeHeadersMap)
+	}
+	if allowCredentials {
+		allowCredentialsMap := generateCORSAllowedOriginMap(varPrefix+"_allow_credentials", originMap.Variable, `"true"`)
+		p.CORS.AllowCredentialsVariable = allowCredentialsMap.Variable
+		p.Maps = append(p.Maps, allowCredentialsMap)
+	}
+	return res
+}
+
+// generateCORSAllowedOriginMap generates a map that returns the result only if the origin of a request is allowed,
+// so that NGINX doesn't add the header for the origins that aren't allowed.
+func generateCORSAllowedOriginMap(variable string, originVariable string, result string) version2.Map {
+	return version2.Map{
+		Source:   originVariable,
+		Variable: variable,
+		Parameters: []version2.Parameter{
+			{Value: "default", Result: result},
+			{Value: `""`, Result: `""`},
+		},
+	}
+}
+
+func generateCORSAllowMethods(methods []string) []string {
+	if len(methods) == 0 {
+		return []string{"GET", "HEAD", "POST"}
+	}
+	return methods
+}
+
+// generateCORSOriginMap generates a map that returns the origin of a request if the origin is allowed
+// and an empty string otherwise, so that NGINX doesn't add the Access-Control-Allow-Origin header.
+func generateCORSOriginMap(variable string, allowOrigins []string, allowCredentials bool) version2.Map {
+	defaultResult := `""`
+	var params []version2.Parameter
+
+	for _, origin := range allowOrigins {
+		switch {
+		case origin == "*":
+			// the wildcard can't be used for requests with credentials, so we reflect the origin instead
+			defaultResult = "$http_origin"
+			if !allowCredentials {
+				defaultResult = `"*"`
+			}
+		case strings.HasPrefix(origin, "~"):
+			params = append(params, version2.Parameter{Value: fmt.Sprintf(`"%v"`, origin), Result: "$http_origin"})
+		case strings.Contains(origin, "*"):
+			parts := strings.Split(origin, "*")
+			for i := range parts {
+				parts[i] = regexp.QuoteMeta(parts[i])
+			}
+			params = append(params, version2.Parameter{
+				Value:  fmt.Sprintf(`"~^%v$"`, strings.Join(parts, "[^./]+")),
+				Result: "$http_origin",
+			})
+		default:
+			params = append(params, version2.Parameter{Value: fmt.Sprintf(`"%v"`, origin), Result: "$http_origin"})
+		}
+	}
+
+	return version2.Map{
+		Source:     "$http_origin",
+		Variable:   variable,
+		Parameters: append([]version2.Parameter{{Value: "default", Result: defaultResult}}, params...),
+	}
+}
+
+func (p *policiesCfg) addExternalAuthConfig(
+	externalAuth *conf_v1.ExternalAuth,
+	polKey string,
+	polNamespace string,
+	polName string,
+	vsNamespace string,
+	vsName string,
+) *validationResults {
+	res := newValidationResults()
+	if p.ExternalAuth != nil {
+		res.addWarningf("Multiple external auth policies in the same context is not valid. External auth policy %s will be ignored", polKey)
+		return res
+	}
+
+	name := fmt.Sprintf("pol_ext_auth_%v_%v_%v_%v", rfc1123ToSnake(polNamespace), rfc1123ToSnake(polName), rfc1123ToSnake(vsNamespace), rfc1123ToSnake(vsName))
+
+	authLoc := &version2.ExternalAuthLocation{
+		Path:      "/_" + name,
+		ProxyPass: externalAuth.URL,
+	}
+	if externalAuth.Service != nil {
+		p.ExternalAuthUpstream = &externalAuthUpstream{
+			Name:      name,
+			Namespace: polNamespace,
+			Upstream: conf_v1.Upstream{
+				Name:    externalAuth.Service.Name,
+				Service: externalAuth.Service.Name,
+				Port:    uint16(externalAuth.Service.Port), //nolint:gosec
+			},
+		}
+		authLoc.ProxyPass = fmt.Sprintf("http://%v%v", name, generateString(externalAuth.Service.Path, "/"))
+	}
+
+	cacheKeyParts := []string{"$request_method", "$request_uri"}
+	for _, h := range externalAuth.ForwardHeaders {
+		headerVariable := "$http_" + strings.ReplaceAll(strings.ToLower(h), "-", "_")
+		authLoc.ForwardHeaders = append(authLoc.ForwardHeaders, version2.Header{Name: h, Value: headerVariable})
+		cacheKeyParts = append(cacheKeyParts, headerVariable)
+	}
+
+	auth := &version2.ExternalAuth{
+		Path: authLoc.Path,
+	}
+	if len(externalAuth.SkipPaths) > 0 {
+		auth.URIVariable = "$" + name + "_uri"
+		skipMap := generateExternalAuthSkipMap("$"+name+"_skip", auth.URIVariable, externalAuth.SkipPaths)
+		authLoc.SkipVariable = skipMap.Variable
+		p.Maps = append(p.Maps, skipMap)
+	}
+
+	if externalAuth.CacheTTL != "" {
+		cacheTTL := generateTime(externalAuth.CacheTTL)
+		authLoc.CacheZone = name
+		authLoc.CacheKey = strings.Join(cacheKeyParts, ":")
+		authLoc.CacheTTL = cacheTTL
+		p.CacheZones = append(p.CacheZones, version2.CacheZone{
+			Name:     name,
+			Path:     fmt.Sprintf("/var/cache/nginx/%v", name),
+			Size:     "1m",
+			Inactive: cacheTTL,
+		})
+	}
+
+	for _, h := range externalAuth.ResponseHeaders {
+		snakeHeader := strings.ReplaceAll(strings.ToLower(h), "-", "_")
+		auth.ResponseHeaders = append(auth.ResponseHeaders, version2.ExternalAuthHeader{
+			Name:             h,
+			Variable:         fmt.Sprintf("$%v_%v", name, snakeHeader),
+			UpstreamVariable: "$upstream_http_" + snakeHeader,
+		})
+	}
+
+	p.ExternalAuth = auth
+	p.ExternalAuthLocation = authLoc
+	return res
+}
+
+// addCacheConfig configures the caching of the responses with a zone per policy, so that the VirtualServers
+// referencing the same policy share the zone.
+func (p *policiesCfg) addCacheConfig(cache *conf_v1.Cache, polKey string, polNamespace string, polName string) *validationResults {
+	res := newValidationResults()
+	if p.Cache != nil {
+		res.addWarningf("Multiple cache policies in the same context is not valid. Cache policy %s will be ignored", polKey)
+		return res
+	}
+
+	zoneName := fmt.Sprintf("pol_cache_%v_%v", rfc1123ToSnake(polNamespace), rfc1123ToSnake(polName))
+
+	cfg := &version2.Cache{
+		Zone: zoneName,
+		Key:  cache.Key,
+	}
+
+	for _, valid := range cache.Valid {
+		var parts []string
+		for _, code := range valid.Codes {
+			parts = append(parts, strconv.Itoa(code))
+		}
+		parts = append(parts, generateTime(valid.Time))
+		cfg.Valid = append(cfg.Valid, strings.Join(parts, " "))
+	}
+
+	if cache.Lock != nil && cache.Lock.Enable {
+		cfg.Lock = true
+		cfg.LockTimeout = generateTime(cache.Lock.Timeout)
+		cfg.LockAge = generateTime(cache.Lock.Age)
+	}
+
+	cfg.UseStale = strings.Join(cache.UseStale, " ")
+
+	var bypass []string
+	for _, b := range cache.Bypass {
+		bypass = append(bypass, fmt.Sprintf(`"%v"`, b))
+	}
+	cfg.Bypass = strings.Join(bypass, " ")
+
+	p.Cache = cfg
+	p.CacheZones = append(p.CacheZones, version2.CacheZone{
+		Name:     zoneName,
+		Path:     fmt.Sprintf("/var/cache/nginx/%v", zoneName),
+		Levels:   "1:2",
+		Size:     generateString(cache.Size, "10m"),
+		MaxSize:  cache.MaxSize,
+		Inactive: generateTime(cache.Inactive),
+	})
+	return res
+}
+
+// generateExternalAuthSkipMap generates a map that returns 1 for the requests with a URI that matches one of the paths
+// or starts with one of the paths followed by a slash. The map uses the normalized URI of the main request, which is saved
+// in uriVariable before the authorization subrequest, because in the subrequest $uri is the URI of the subrequest.
+func generateExternalAuthSkipMap(variable string, uriVariable string, skipPaths []string) version2.Map {
+	params := []version2.Parameter{{Value: "default", Result: "0"}}
+	for _, path := range skipPaths {
+		regex := "^" + regexp.QuoteMeta(path)
+		if !strings.HasSuffix(path, "/") {
+			regex += "(/|$)"
+		}
+		params = append(params, version2.Parameter{
+			Value:  fmt.Sprintf(`"~%v"`, regex),
+			Result: "1",
+		})
+	}
+
+	return version2.Map{
+		Source:     uriVariable,
+		Variable:   variable,
+		Parameters: params,
+	}
+}
+
+// externalAuthConflictsWithAPIKey checks if an external auth policy is applied together with an API Key policy,
+// which is not supported because both use the auth_request directive.
+func externalAuthConflictsWithAPIKey(routeCfg policiesCfg, specCfg policiesCfg) bool {
+	return routeCfg.ExternalAuth != nil && (routeCfg.APIKey != nil || specCfg.APIKey != nil)
+}
+
+func removeDuplicateExternalAuthLocations(locations []version2.ExternalAuthLocation) []version2.ExternalAuthLocation {
+	encountered := make(map[string]bool)
+	var result []version2.ExternalAuthLocation
+
+	for _, l := range locations {
+		if !encountered[l.Path] {
+			encountered[l.Path] = true
+			result = append(result, l)
+		}
+	}
+
+	return result
+}
+
+func removeDuplicateExternalAuthUpstreams(upstreams []externalAuthUpstream) []externalAuthUpstream {
+	encountered := make(map[string]bool)
+	var result []externalAuthUpstream
+
+	for _, u := range upstreams {
+		if !encountered[u.Name] {
+			encountered[u.Name] = true
+			result = append(result, u)
+		}
+	}
+
+	return result
+}
+
+func removeDuplicateCacheZones(zones []version2.CacheZone) []version2.CacheZone {
+	encountered := make(map[string]bool)
+	var result []version2.CacheZone
+
+	for _, z := range zones {
+		if !encountered[z.Name] {
+			encountered[z.Name] = true
+			result = append(result, z)
+		}
+	}
+
+	return result
+}
+
+func removeDuplicateMaps(maps []version2.Map) []version2.Map {
+	encountered := make(map[string]bool)
+	var result []version2.Map
+
+	for _, m := range maps {
+		if !encountered[m.Variable] {
+			encountered[m.Variable] = true
+			result = append(result, m)
+		}
+	}
+
+	return result
+}
+
 func (p *policiesCfg) addWAFConfig(
 	ctx context.Context,
 	waf *conf_v1.WAF,
@@ -1577,6 +2107,24 @@ func (vsc *virtualServerConfigurator) generatePolicies(
 					ownerDetails.vsName, policyOpts.secretRefs)
 			case pol.Spec.WAF != nil:
 				res = config.addWAFConfig(vsc.cfgParams.Context, pol.Spec.WAF, key, polNamespace, policyOpts.apResources)
+			case pol.Spec.Retry != nil:
+				res = config.addRetryConfig(pol.Spec.Retry, key)
+			case pol.Spec.CORS != nil:
+				res = config.addCORSConfig(pol.Spec.CORS, key, polNamespace, p.Name, ownerDetails.vsNamespace, ownerDetails.vsName)
+			case pol.Spec.ExternalAuth != nil:
+				res = config.addExternalAuthConfig(
+					pol.Spec.ExternalAuth,
+					key,
+					polNamespace,
+					p.Name,
+					ownerDetails.vsNamespace,
+					ownerDetails.vsName,
+				)
+			case pol.Spec.Cache != nil:
+				res = config.addCacheConfig(pol.Spec.Cache, key, polNamespace, p.Name)
+			case pol.Spec.ConnectionLimit != nil, pol.Spec.Bandwidth != nil:
+				res = newValidationResults()
+				res.addWarningf("Policy %s is only supported for TransportServers and will be ignored", key)
 			default:
 				res = newValidationResults()
 			}
@@ -1663,7 +2211,21 @@ func addPoliciesCfgToLocation(cfg policiesCfg, location *version2.Location) {
 	location.OIDC = cfg.OIDC
 	location.WAF = cfg.WAF
 	location.APIKey = cfg.APIKey
+	location.CORS = cfg.CORS
+	location.ExternalAuth = cfg.ExternalAuth
+	location.Cache = cfg.Cache
 	location.PoliciesErrorReturn = cfg.ErrorReturn
+
+	if cfg.Retry != nil {
+		location.ProxyNextUpstream = cfg.Retry.NextUpstream
+		location.ProxyNextUpstreamTries = cfg.Retry.NextUpstreamTries
+		location.ProxyNextUpstreamTimeout = cfg.Retry.NextUpstreamTimeout
+		if cfg.Retry.PerTryTimeout != "" {
+			location.ProxyConnectTimeout = cfg.Retry.PerTryTimeout
+			location.ProxyReadTimeout = cfg.Retry.PerTryTimeout
+			location.ProxySendTimeout = cfg.Retry.PerTryTimeout
+		}
+	}
 }
 
 func addPoliciesCfgToLocations(cfg policiesCfg, locations []version2.Location) {
@@ -1672,12 +2234,120 @@ func addPoliciesCfgToLocations(cfg policiesCfg, locations []version2.Location) {
 	}
 }
 
+// isProxyingAction reports whether the action passes requests to an upstream.
+func isProxyingAction(action *conf_v1.Action) bool {
+	return action != nil && action.Redirect == nil && action.Return == nil && action.GRPCReturn == nil
+}
+
+// generateRetrySamplingLocations generates the locations that retry only the sampled percentage of the requests of the
+// internal location. NGINX can't retry only a share of the requests of a location, so a split client sends the sampled
+// share of the requests to a copy of the location that retries them, and the rest to a copy that doesn't.
+func generateRetrySamplingLocations(loc version2.Location, sampling int, variableNamer *VariableNamer, scIndex int) ([]version2.Location, version2.SplitClient) {
+	retryLoc := loc
+	retryLoc.Path = fmt.Sprintf("/%vsplits_%d_split_%d", internalLocationPrefix, scIndex, 0)
+
+	noRetryLoc := loc
+	noRetryLoc.Path = fmt.Sprintf("/%vsplits_%d_split_%d", internalLocationPrefix, scIndex, 1)
+	noRetryLoc.ProxyNextUpstream = "off"
+
+	splitClient := version2.SplitClient{
+		// a different source than $request_id of the other split clients makes the split independent of them
+		Source:   `"${request_id}_retry"`,
+		Variable: variableNamer.GetNameForSplitClientVariable(scIndex),
+		Distributions: []version2.Distribution{
+			{Weight: fmt.Sprintf("%d%%", sampling), Value: retryLoc.Path},
+			{Weight: "*", Value: noRetryLoc.Path},
+		},
+	}
+
+	return []version2.Location{retryLoc, noRetryLoc}, splitClient
+}
+
+// addRetrySamplingToRoutingCfg replaces the internal locations of the routing config that pass requests to the upstreams
+// with the locations that retry only the percentage of the requests sampled by the retry policy. It returns the locations that redirect
+// the requests of the replaced locations.
+func addRetrySamplingToRoutingCfg(cfg *routingCfg, retry *retryCfg, variableNamer *VariableNamer, scIndex int) []version2.InternalRedirectLocation {
+	if !retry.hasSampling() {
+		return nil
+	}
+
+	var locations []version2.Location
+	var redirects []version2.InternalRedirectLocation
+	for _, l := range cfg.Locations {
+		if !l.Internal || l.ProxyPass == "" {
+			locations = append(locations, l)
+			continue
+		}
+
+		retryLocs, splitClient := generateRetrySamplingLocations(l, retry.Sampling, variableNamer, scIndex+len(cfg.SplitClients))
+		locations = append(locations, retryLocs...)
+		cfg.SplitClients = append(cfg.SplitClients, splitClient)
+		redirects = append(redirects, version2.InternalRedirectLocation{
+			Path:        l.Path,
+			Destination: splitClient.Variable,
+			Internal:    true,
+		})
+	}
+	cfg.Locations = locations
+
+	return redirects
+}
+
 func addDosConfigToLocations(dosCfg *version2.Dos, locations []version2.Location) {
 	for i := range locations {
 		locations[i].Dos = dosCfg
 	}
 }
 
+func addTracingToLocations(tracingCfg *version2.Tracing, locations []version2.Location) {
+	for i := range locations {
+		locations[i].Tracing = tracingCfg
+	}
+}
+
+// generateAccessLog generates the access log of a VirtualServer.
+// An invalid destination or format is reported as a warning and the access log is ignored.
+func (vsc *virtualServerConfigurator) generateAccessLog(vs *conf_v1.VirtualServer) *version2.AccessLog {
+	if vs.Spec.AccessLog == nil {
+		return nil
+	}
+
+	destination, format, err := generateAccessLogParams(vs.Spec.AccessLog.Destination, vs.Spec.AccessLog.Format, vsc.cfgParams)
+	if err != nil {
+		vsc.addWarningf(vs, "%v, the access log is ignored", err)
+		return nil
+	}
+	return &version2.AccessLog{
+		Destination:    destination,
+		Format:         format,
+		LatencyMetrics: vsc.enableLatencyMetrics,
+	}
+}
+
+// generateTracing generates the OpenTelemetry tracing of a VirtualServer or a route.
+// The tracing requires the OpenTelemetry module, which is loaded if the otel-exporter-endpoint ConfigMap key is set.
+func (vsc *virtualServerConfigurator) generateTracing(owner runtime.Object, tracing *conf_v1.Tracing) *version2.Tracing {
+	if tracing == nil {
+		return nil
+	}
+	if !vsc.cfgParams.MainOtelLoadModule {
+		vsc.addWarningf(owner, "Tracing requires the otel-exporter-endpoint ConfigMap key, tracing is ignored")
+		return nil
+	}
+
+	tracingCfg := &version2.Tracing{}
+	if tracing.Enable != nil {
+		tracingCfg.Trace = "off"
+		if *tracing.Enable {
+			tracingCfg.Trace = generateOtelTrace(vsc.cfgParams.MainOtelSamplerRatio)
+		}
+	}
+	for _, attr := range tracing.SpanAttributes {
+		tracingCfg.SpanAttributes = append(tracingCfg.SpanAttributes, version2.SpanAttribute{Name: attr.Name, Value: attr.Value})
+	}
+	return tracingCfg
+}
+
 func getUpstreamResourceLabels(owner runtime.Object) version2.UpstreamLabels {
 	var resourceType, resourceName, resourceNamespace string
 
@@ -1706,6 +2376,7 @@ func (vsc *virtualServerConfigurator) generateUpstream(
 	isExternalNameSvc bool,
 	endpoints []string,
 	backupEndpoints []string,
+	secretRefs map[string]*secrets.SecretReference,
 ) version2.Upstream {
 	var upsServers []version2.UpstreamServer
 	for _, e := range endpoints {
@@ -1755,9 +2426,91 @@ func (vsc *virtualServerConfigurator) generateUpstream(
 		ups.NTLM = upstream.NTLM
 	}
 
+	upsTLS, err := generateUpstreamTLS(upstream.TLS, upstreamLabels.ResourceNamespace, secretRefs)
+	if err != nil {
+		vsc.addWarningf(owner, "Requests to upstream %s will fail: %v", upstream.Name, err)
+		ups.Servers = []version2.UpstreamServer{{Address: nginx502Server}}
+		ups.BackupServers = nil
+	}
+	ups.TLS = upsTLS
+
 	return ups
 }
 
+// generateUpstreamTLS generates the TLS configuration for the connections to the servers of an upstream.
+// It returns nil if TLS is disabled or if the upstream only enables it without any other TLS settings.
+func generateUpstreamTLS(tls conf_v1.UpstreamTLS, namespace string, secretRefs map[string]*secrets.SecretReference) (*version2.UpstreamTLS, error) {
+	if !tls.Enable || tls == (conf_v1.UpstreamTLS{Enable: true}) {
+		return nil, nil
+	}
+
+	trustedCertPath, err := generateTrustedCertPath(tls.TrustedCertSecret, namespace, secretRefs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &version2.UpstreamTLS{
+		VerifyServer: tls.VerifyServer,
+		VerifyDepth:  generateIntFromPointer(tls.VerifyDepth, 1),
+		Ciphers:      tls.Ciphers,
+		Protocols:    tls.Protocols,
+		TrustedCert:  trustedCertPath,
+		SessionReuse: generateBool(tls.SessionReuse, true),
+		ServerName:   tls.ServerName,
+		SSLName:      generateString(tls.SSLName, "$proxy_host"),
+	}, nil
+}
+
+// generateTrustedCertPath returns the path of the CA certificate of the trusted cert secret of an UpstreamTLS.
+func generateTrustedCertPath(trustedCertSecret string, namespace string, secretRefs map[string]*secrets.SecretReference) (string, error) {
+	if trustedCertSecret == "" {
+		return "", nil
+	}
+
+	secretKey := fmt.Sprintf("%v/%v", namespace, trustedCertSecret)
+
+	secretRef, exists := secretRefs[secretKey]
+	if !exists {
+		return "", fmt.Errorf("the TLS configuration references a secret %s that does not exist", secretKey)
+	}
+	var secretType api_v1.SecretType
+	if secretRef.Secret != nil {
+		secretType = secretRef.Secret.Type
+	}
+	if secretType != "" && secretType != secrets.SecretTypeCA {
+		return "", fmt.Errorf("the TLS configuration references a secret %s of a wrong type '%s', must be '%s'", secretKey, secretType, secrets.SecretTypeCA)
+	} else if secretRef.Error != nil {
+		return "", fmt.Errorf("the TLS configuration references an invalid secret %s: %w", secretKey, secretRef.Error)
+	}
+
+	caFields := strings.Fields(secretRef.Path)
+	if len(caFields) == 0 {
+		return "", nil
+	}
+
+	return caFields[0], nil
+}
+
+// addUpstreamTLS adds the TLS configuration of an upstream to upstreamTLS, unless the connections to the upstream
+// servers are already configured by an EgressMTLS policy of the VirtualServer or by the SPIFFE certificates.
+func (vsc *virtualServerConfigurator) addUpstreamTLS(
+	owner runtime.Object,
+	upstreamTLS map[string]*version2.UpstreamTLS,
+	upstreamName string,
+	upstream conf_v1.Upstream,
+	tls *version2.UpstreamTLS,
+	hasEgressMTLS bool,
+) {
+	if tls == nil || !upstream.TLS.Enable {
+		return
+	}
+	if hasEgressMTLS || vsc.spiffeCerts {
+		vsc.addWarningf(owner, "The TLS settings of upstream %s are ignored, because the connections to its servers are configured by an EgressMTLS policy or the SPIFFE certificates", upstream.Name)
+		return
+	}
+	upstreamTLS[upstreamName] = tls
+}
+
 func (vsc *virtualServerConfigurator) generateSlowStartForPlus(
 	owner runtime.Object,
 	upstream conf_v1.Upstream,
@@ -1907,6 +2660,10 @@ func upstreamHasKeepalive(upstream conf_v1.Upstream, cfgParams *ConfigParams) bo
 }
 
 func generateRewrites(path string, proxy *conf_v1.ActionProxy, internal bool, originalPath string, grpcEnabled bool) []string {
+	if proxy != nil && len(proxy.Rewrites) > 0 {
+		return generateRegexRewrites(proxy.Rewrites, internal)
+	}
+
 	if proxy == nil || proxy.RewritePath == "" {
 		if grpcEnabled && internal {
 			return []string{"^ $request_uri break"}
@@ -1945,6 +2702,34 @@ func generateRewrites(path string, proxy *conf_v1.ActionProxy, internal bool, or
 	return rewrites
 }
 
+// generateRegexRewrites generates the rewrite rules for the rewrites of an action proxy.
+// The rules are evaluated in order and the first matching rule stops the processing.
+func generateRegexRewrites(proxyRewrites []conf_v1.ProxyRewrite, internal bool) []string {
+	var rewrites []string
+
+	if internal {
+		// For internal locations, recover the original request_uri without the arguments (see generateRewrites).
+		rewrites = append(rewrites, "^ $request_uri_no_args")
+	}
+
+	for _, r := range proxyRewrites {
+		replace := r.Replace
+		if r.Query != nil {
+			// A replacement with a query string (even an empty one) makes NGINX drop the original arguments.
+			replace = fmt.Sprintf("%v?%v", replace, *r.Query)
+		}
+		rewrites = append(rewrites, fmt.Sprintf(`"%v" "%v" break`, r.Match, replace))
+	}
+
+	if internal {
+		// Stop the processing of the rewrite directives if none of the rules matched,
+		// so that NGINX doesn't search for a new location for the recovered URI.
+		rewrites = append(rewrites, "^ $request_uri_no_args break")
+	}
+
+	return rewrites
+}
+
 func generateProxyPassRewrite(path string, proxy *conf_v1.ActionProxy, internal bool) string {
 	if proxy == nil || internal {
 		return ""
@@ -1960,7 +2745,7 @@ func generateProxyPassRewrite(path string, proxy *conf_v1.ActionProxy, internal
 func generateProxyPass(tlsEnabled bool, upstreamName string, internal bool, proxy *conf_v1.ActionProxy) string {
 	proxyPass := fmt.Sprintf("%v://%v", generateProxyPassProtocol(tlsEnabled), upstreamName)
 
-	if internal && (proxy == nil || proxy.RewritePath == "") {
+	if internal && (proxy == nil || (proxy.RewritePath == "" && len(proxy.Rewrites) == 0)) {
 		return fmt.Sprintf("%v$request_uri", proxyPass)
 	}
 
@@ -2069,7 +2854,8 @@ type errorPageDetails struct {
 func generateLocation(path string, upstreamName string, upstream conf_v1.Upstream, action *conf_v1.Action,
 	cfgParams *ConfigParams, errorPages errorPageDetails, internal bool, proxySSLName string,
 	originalPath string, locSnippets string, enableSnippets bool, retLocIndex int, isVSR bool, vsrName string,
-	vsrNamespace string, vscWarnings Warnings,
+	vsrNamespace string, vscWarnings Warnings, upstreamNamer *upstreamNamer, crUpstreams map[string]conf_v1.Upstream,
+	upstreamTLS map[string]*version2.UpstreamTLS,
 ) (version2.Location, *version2.ReturnLocation) {
 	locationSnippets := generateSnippets(enableSnippets, locSnippets, cfgParams.LocationSnippets)
 
@@ -2081,10 +2867,88 @@ func generateLocation(path string, upstreamName string, upstream conf_v1.Upstrea
 		return generateLocationForReturn(path, cfgParams.LocationSnippets, action.Return, retLocIndex)
 	}
 
-	checkGrpcErrorPageCodes(errorPages, isGRPC(upstream.Type), upstream.Name, vscWarnings)
+	if action.GRPCReturn != nil {
+		return generateLocationForGRPCReturn(path, locationSnippets, action.GRPCReturn, retLocIndex)
+	}
+
+	checkGrpcErrorPageRedirects(errorPages, isGRPC(upstream.Type), upstream.Name, vscWarnings)
+
+	loc := generateLocationForProxying(path, upstreamName, upstream, cfgParams, errorPages.pages, internal,
+		errorPages.index, proxySSLName, action.Proxy, originalPath, locationSnippets, isVSR, vsrName, vsrNamespace)
+	loc.Mirror = generateMirror(action.Mirror, upstream, upstreamNamer, crUpstreams, upstreamTLS, cfgParams, errorPages.owner, vscWarnings)
+	loc.UpstreamTLS = upstreamTLS[upstreamName]
+
+	return loc, nil
+}
+
+func generateMirror(mirror *conf_v1.ActionMirror, primaryUpstream conf_v1.Upstream, upstreamNamer *upstreamNamer,
+	crUpstreams map[string]conf_v1.Upstream, upstreamTLS map[string]*version2.UpstreamTLS, cfgParams *ConfigParams, owner runtime.Object, vscWarnings Warnings,
+) *version2.MirrorLocation {
+	if mirror == nil {
+		return nil
+	}
+
+	upstreamName := upstreamNamer.GetNameForUpstream(mirror.Upstream)
+	upstream := crUpstreams[upstreamName]
+	if isGRPC(primaryUpstream.Type) || isGRPC(upstream.Type) {
+		vscWarnings.AddWarningf(owner, "Mirroring requests to upstream %s is not supported for gRPC upstreams and will be ignored", mirror.Upstream)
+		return nil
+	}
+
+	percentage := generateIntFromPointer(mirror.Percentage, 100)
+	requestBody := generateBool(mirror.RequestBody, true)
+
+	path := fmt.Sprintf("/%vmirror_%s_%d", internalLocationPrefix, upstreamName, percentage)
+	if !requestBody {
+		path += "_no_body"
+	}
+
+	// a percentage below 100 samples the mirrored requests through a split_clients variable
+	var variable string
+	if percentage < 100 {
+		variable = fmt.Sprintf("$%s", strings.ReplaceAll(strings.TrimPrefix(path, "/"), "-", "_"))
+	}
+
+	return &version2.MirrorLocation{
+		Path:                path,
+		ProxyPass:           fmt.Sprintf("%v://%v$request_uri", generateProxyPassProtocol(upstream.TLS.Enable), upstreamName),
+		ProxyConnectTimeout: generateTimeWithDefault(upstream.ProxyConnectTimeout, cfgParams.ProxyConnectTimeout),
+		ProxyReadTimeout:    generateTimeWithDefault(upstream.ProxyReadTimeout, cfgParams.ProxyReadTimeout),
+		ProxySendTimeout:    generateTimeWithDefault(upstream.ProxySendTimeout, cfgParams.ProxySendTimeout),
+		RequestBody:         requestBody,
+		Percentage:          percentage,
+		Variable:            variable,
+		UpstreamTLS:         upstreamTLS[upstreamName],
+	}
+}
+
+// generateMirrorLocations returns the unique mirror locations referenced by the locations
+// along with the split clients that sample the mirrored requests.
+func generateMirrorLocations(locations []version2.Location) ([]version2.MirrorLocation, []version2.SplitClient) {
+	var mirrorLocations []version2.MirrorLocation
+	var splitClients []version2.SplitClient
+	seen := make(map[string]bool)
+
+	for _, l := range locations {
+		if l.Mirror == nil || seen[l.Mirror.Path] {
+			continue
+		}
+		seen[l.Mirror.Path] = true
+		mirrorLocations = append(mirrorLocations, *l.Mirror)
+
+		if l.Mirror.Variable != "" {
+			splitClients = append(splitClients, version2.SplitClient{
+				Source:   "$request_id",
+				Variable: l.Mirror.Variable,
+				Distributions: []version2.Distribution{
+					{Weight: fmt.Sprintf("%d%%", l.Mirror.Percentage), Value: "1"},
+					{Weight: "*", Value: `""`},
+				},
+			})
+		}
+	}
 
-	return generateLocationForProxying(path, upstreamName, upstream, cfgParams, errorPages.pages, internal,
-		errorPages.index, proxySSLName, action.Proxy, originalPath, locationSnippets, isVSR, vsrName, vsrNamespace), nil
+	return mirrorLocations, splitClients
 }
 
 func generateProxySetHeaders(proxy *conf_v1.ActionProxy) []version2.Header {
@@ -2171,6 +3035,12 @@ func generateLocationForProxying(path string, upstreamName string, upstream conf
 	cfgParams *ConfigParams, errorPages []conf_v1.ErrorPage, internal bool, errPageIndex int,
 	proxySSLName string, proxy *conf_v1.ActionProxy, originalPath string, locationSnippets []string, isVSR bool, vsrName string, vsrNamespace string,
 ) version2.Location {
+	ePages := generateErrorPages(errPageIndex, errorPages)
+	var grpcErrorPageLocations []version2.ErrorPageLocation
+	if isGRPC(upstream.Type) {
+		ePages, grpcErrorPageLocations = generateGRPCErrorPages(errPageIndex, errorPages)
+	}
+
 	return version2.Location{
 		Path:                     generatePath(path),
 		Internal:                 internal,
@@ -2197,13 +3067,14 @@ func generateLocationForProxying(path string, upstreamName string, upstream conf
 		ProxyPassRewrite:         generateProxyPassRewrite(path, proxy, internal),
 		Rewrites:                 generateRewrites(path, proxy, internal, originalPath, isGRPC(upstream.Type)),
 		HasKeepalive:             upstreamHasKeepalive(upstream, cfgParams),
-		ErrorPages:               generateErrorPages(errPageIndex, errorPages),
+		ErrorPages:               ePages,
 		ProxySSLName:             proxySSLName,
 		ServiceName:              upstream.Service,
 		IsVSR:                    isVSR,
 		VSRName:                  vsrName,
 		VSRNamespace:             vsrNamespace,
 		GRPCPass:                 generateGRPCPass(isGRPC(upstream.Type), upstream.TLS.Enable, upstreamName),
+		GRPCErrorPageLocations:   grpcErrorPageLocations,
 	}
 }
 
@@ -2282,6 +3153,30 @@ func generateLocationForReturn(path string, locationSnippets []string, actionRet
 		}
 }
 
+// generateLocationForGRPCReturn returns a location that responds to gRPC calls with the status and the message
+// of the action in the headers of the response without a body, the Trailers-Only response of gRPC.
+func generateLocationForGRPCReturn(path string, locationSnippets []string, grpcReturn *conf_v1.ActionGRPCReturn,
+	retLocIndex int,
+) (version2.Location, *version2.ReturnLocation) {
+	loc, returnLoc := generateLocationForReturn(path, locationSnippets, &conf_v1.ActionReturn{
+		Code: 204,
+		Type: grpcContentType,
+	}, retLocIndex)
+	returnLoc.Headers = generateGRPCStatusHeaders(grpcReturn.Status, grpcReturn.Message)
+
+	return loc, returnLoc
+}
+
+// generateGRPCStatusHeaders returns the headers of a gRPC response with a status and a message.
+// NGINX doesn't send the Content-Type header with 204 responses, so it's added explicitly.
+func generateGRPCStatusHeaders(status int, message string) []version2.Header {
+	return []version2.Header{
+		{Name: "content-type", Value: grpcContentType},
+		{Name: "grpc-status", Value: strconv.Itoa(status)},
+		{Name: "grpc-message", Value: message},
+	}
+}
+
 type routingCfg struct {
 	Maps                     []version2.Map
 	SplitClients             []version2.SplitClient
@@ -2297,6 +3192,7 @@ func generateSplits(
 	splits []conf_v1.Split,
 	upstreamNamer *upstreamNamer,
 	crUpstreams map[string]conf_v1.Upstream,
+	upstreamTLS map[string]*version2.UpstreamTLS,
 	VariableNamer *VariableNamer,
 	scIndex int,
 	cfgParams *ConfigParams,
@@ -2373,7 +3269,8 @@ func generateSplits(
 		proxySSLName := generateProxySSLName(upstream.Service, upstreamNamer.namespace)
 		newRetLocIndex := retLocIndex + len(returnLocations)
 		loc, returnLoc := generateLocation(path, upstreamName, upstream, s.Action, cfgParams, errorPages, true,
-			proxySSLName, originalPath, locSnippets, enableSnippets, newRetLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings)
+			proxySSLName, originalPath, locSnippets, enableSnippets, newRetLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings,
+			upstreamNamer, crUpstreams, upstreamTLS)
 		locations = append(locations, loc)
 		if returnLoc != nil {
 			returnLocations = append(returnLocations, *returnLoc)
@@ -2383,10 +3280,27 @@ func generateSplits(
 	return splitClients, locations, returnLocations, maps, keyValZones, keyVals, twoWaySplitClients
 }
 
+// applyCanaryWeight returns the route with the weights of its Splits set according to the current weight of its Canary.
+func applyCanaryWeight(route conf_v1.Route, canaryWeights map[string]int) conf_v1.Route {
+	weight, exists := canaryWeights[route.Path]
+	if route.Canary == nil || !exists || len(route.Splits) != 2 {
+		return route
+	}
+
+	splits := make([]conf_v1.Split, len(route.Splits))
+	copy(splits, route.Splits)
+	splits[0].Weight = 100 - weight
+	splits[1].Weight = weight
+	route.Splits = splits
+
+	return route
+}
+
 func generateDefaultSplitsConfig(
 	route conf_v1.Route,
 	upstreamNamer *upstreamNamer,
 	crUpstreams map[string]conf_v1.Upstream,
+	upstreamTLS map[string]*version2.UpstreamTLS,
 	VariableNamer *VariableNamer,
 	scIndex int,
 	cfgParams *ConfigParams,
@@ -2401,7 +3315,7 @@ func generateDefaultSplitsConfig(
 	vscWarnings Warnings,
 	weightChangesDynamicReload bool,
 ) routingCfg {
-	scs, locs, returnLocs, maps, keyValZones, keyVals, twoWaySplitClients := generateSplits(route.Splits, upstreamNamer, crUpstreams, VariableNamer, scIndex, cfgParams, errorPages, originalPath, locSnippets, enableSnippets, retLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings, weightChangesDynamicReload)
+	scs, locs, returnLocs, maps, keyValZones, keyVals, twoWaySplitClients := generateSplits(route.Splits, upstreamNamer, crUpstreams, upstreamTLS, VariableNamer, scIndex, cfgParams, errorPages, originalPath, locSnippets, enableSnippets, retLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings, weightChangesDynamicReload)
 
 	var irl version2.InternalRedirectLocation
 	if weightChangesDynamicReload && len(route.Splits) == 2 {
@@ -2484,7 +3398,7 @@ func generateSplitsForWeightChangesDynamicReload(splits []conf_v1.Split, scIndex
 }
 
 func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, crUpstreams map[string]conf_v1.Upstream,
-	VariableNamer *VariableNamer, index int, scIndex int, cfgParams *ConfigParams, errorPages errorPageDetails,
+	upstreamTLS map[string]*version2.UpstreamTLS, VariableNamer *VariableNamer, index int, scIndex int, cfgParams *ConfigParams, errorPages errorPageDetails,
 	locSnippets string, enableSnippets bool, retLocIndex int, isVSR bool, vsrName string, vsrNamespace string, vscWarnings Warnings, weightChangesDynamicReload bool,
 ) routingCfg {
 	// Generate maps
@@ -2500,7 +3414,12 @@ func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, cr
 				successfulResult = VariableNamer.GetNameForVariableForMatchesRouteMap(index, i, j+1)
 			}
 
-			params := generateParametersForMatchesRouteMap(c.Value, successfulResult)
+			value := c.Value
+			if c.GRPC != "" {
+				value = generateGRPCConditionValue(c)
+			}
+
+			params := generateParametersForMatchesRouteMap(value, successfulResult)
 
 			matchMap := version2.Map{
 				Source:     source,
@@ -2577,6 +3496,7 @@ func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, cr
 				m.Splits,
 				upstreamNamer,
 				crUpstreams,
+				upstreamTLS,
 				VariableNamer,
 				scIndex+scLocalIndex,
 				cfgParams,
@@ -2606,7 +3526,8 @@ func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, cr
 			proxySSLName := generateProxySSLName(upstream.Service, upstreamNamer.namespace)
 			newRetLocIndex := retLocIndex + len(returnLocations)
 			loc, returnLoc := generateLocation(path, upstreamName, upstream, m.Action, cfgParams, errorPages, true,
-				proxySSLName, route.Path, locSnippets, enableSnippets, newRetLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings)
+				proxySSLName, route.Path, locSnippets, enableSnippets, newRetLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings,
+				upstreamNamer, crUpstreams, upstreamTLS)
 			locations = append(locations, loc)
 			if returnLoc != nil {
 				returnLocations = append(returnLocations, *returnLoc)
@@ -2621,6 +3542,7 @@ func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, cr
 			route.Splits,
 			upstreamNamer,
 			crUpstreams,
+			upstreamTLS,
 			VariableNamer,
 			scIndex+scLocalIndex,
 			cfgParams,
@@ -2649,7 +3571,8 @@ func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, cr
 		proxySSLName := generateProxySSLName(upstream.Service, upstreamNamer.namespace)
 		newRetLocIndex := retLocIndex + len(returnLocations)
 		loc, returnLoc := generateLocation(path, upstreamName, upstream, route.Action, cfgParams, errorPages, true,
-			proxySSLName, route.Path, locSnippets, enableSnippets, newRetLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings)
+			proxySSLName, route.Path, locSnippets, enableSnippets, newRetLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings,
+			upstreamNamer, crUpstreams, upstreamTLS)
 		locations = append(locations, loc)
 		if returnLoc != nil {
 			returnLocations = append(returnLocations, *returnLoc)
@@ -2735,9 +3658,29 @@ func getNameForSourceForMatchesRouteMapFromCondition(condition conf_v1.Condition
 		return fmt.Sprintf("$arg_%s", condition.Argument)
 	}
 
+	if condition.GRPC != "" {
+		return "$uri"
+	}
+
 	return condition.Variable
 }
 
+// generateGRPCConditionValue returns a regular expression for the value of a condition on the service or the method
+// of a gRPC call. The URI of a gRPC call is /<service>/<method>, where the service is the fully qualified name.
+func generateGRPCConditionValue(condition conf_v1.Condition) string {
+	name, isNegative := strings.CutPrefix(condition.Value, "!")
+
+	value := fmt.Sprintf("~^/[^/]+/%s$", regexp.QuoteMeta(name))
+	if condition.GRPC == conf_v1.GRPCConditionService {
+		value = fmt.Sprintf("~^/%s/[^/]+$", regexp.QuoteMeta(name))
+	}
+
+	if isNegative {
+		return "!" + value
+	}
+	return value
+}
+
 func (vsc *virtualServerConfigurator) generateSSLConfig(owner runtime.Object, tls *conf_v1.TLS, namespace string,
 	secretRefs map[string]*secrets.SecretReference, cfgParams *ConfigParams,
 ) *version2.SSL {
@@ -2845,7 +3788,7 @@ func createUpstreamsForPlus(
 			backupEndpointsKey := GenerateEndpointsKey(upstreamNamespace, u.Backup, u.Subselector, *u.BackupPort)
 			backupEndpoints = virtualServerEx.Endpoints[backupEndpointsKey]
 		}
-		ups := vsc.generateUpstream(virtualServerEx.VirtualServer, upstreamName, u, isExternalNameSvc, endpoints, backupEndpoints)
+		ups := vsc.generateUpstream(virtualServerEx.VirtualServer, upstreamName, u, isExternalNameSvc, endpoints, backupEndpoints, virtualServerEx.SecretRefs)
 		upstreams = append(upstreams, ups)
 	}
 
@@ -2870,7 +3813,7 @@ func createUpstreamsForPlus(
 				backupEndpointsKey := GenerateEndpointsKey(upstreamNamespace, u.Backup, u.Subselector, *u.BackupPort)
 				backupEndpoints = virtualServerEx.Endpoints[backupEndpointsKey]
 			}
-			ups := vsc.generateUpstream(vsr, upstreamName, u, isExternalNameSvc, endpoints, backupEndpoints)
+			ups := vsc.generateUpstream(vsr, upstreamName, u, isExternalNameSvc, endpoints, backupEndpoints, virtualServerEx.SecretRefs)
 			upstreams = append(upstreams, ups)
 		}
 	}
@@ -2905,21 +3848,19 @@ func generateErrorPageName(errPageIndex int, index int) string {
 	return fmt.Sprintf("@error_page_%v_%v", errPageIndex, index)
 }
 
-func checkGrpcErrorPageCodes(errorPages errorPageDetails, isGRPC bool, uName string, vscWarnings Warnings) {
+func checkGrpcErrorPageRedirects(errorPages errorPageDetails, isGRPC bool, uName string, vscWarnings Warnings) {
 	if errorPages.pages == nil || !isGRPC {
 		return
 	}
 
 	var c []int
 	for _, e := range errorPages.pages {
-		for _, code := range e.Codes {
-			if grpcConflictingErrors[code] {
-				c = append(c, code)
-			}
+		if e.Redirect != nil {
+			c = append(c, e.Codes...)
 		}
 	}
 	if len(c) > 0 {
-		vscWarnings.AddWarningf(errorPages.owner, "The error page configuration for the upstream %s is ignored for status code(s) %v, which cannot be used for GRPC upstreams.", uName, c)
+		vscWarnings.AddWarningf(errorPages.owner, "The error page redirect for the upstream %s is ignored for status code(s) %v, because redirects cannot be used for GRPC upstreams.", uName, c)
 	}
 }
 
@@ -2961,6 +3902,75 @@ func generateErrorPages(errPageIndex int, errorPages []conf_v1.ErrorPage) []vers
 	return ePages
 }
 
+func generateGRPCErrorPageName(errPageIndex int, index int, code int) string {
+	return fmt.Sprintf("@grpc_error_page_%v_%v_%v", errPageIndex, index, code)
+}
+
+// grpcMessageReplacer replaces the escaped line breaks of the bodies of error pages, which are not allowed in headers.
+var grpcMessageReplacer = strings.NewReplacer(`\n`, " ", `\r`, " ")
+
+// generateGRPCErrorPages returns the error pages of a location for a gRPC upstream along with their named locations.
+// Every status code gets its own named location that returns the corresponding gRPC status with the body
+// of the error page as the message. Redirects are ignored.
+func generateGRPCErrorPages(errPageIndex int, errorPages []conf_v1.ErrorPage) ([]version2.ErrorPage, []version2.ErrorPageLocation) {
+	var ePages []version2.ErrorPage
+	var locations []version2.ErrorPageLocation
+
+	for i, e := range errorPages {
+		if e.Return == nil {
+			continue
+		}
+
+		for _, code := range e.Codes {
+			status, ok := grpcStatusCodes[code]
+			if !ok {
+				status = grpcUnknownStatusCode
+			}
+
+			var headers []version2.Header
+			for _, h := range e.Return.Headers {
+				headers = append(headers, version2.Header{
+					Name:  h.Name,
+					Value: h.Value,
+				})
+			}
+
+			name := generateGRPCErrorPageName(errPageIndex, i, code)
+			ePages = append(ePages, version2.ErrorPage{
+				Name:         name,
+				Codes:        strconv.Itoa(code),
+				ResponseCode: 204,
+			})
+			locations = append(locations, version2.ErrorPageLocation{
+				Name:        name,
+				DefaultType: grpcContentType,
+				Return:      generateReturnBlock("", 0, 0),
+				Headers:     append(headers, generateGRPCStatusHeaders(status, grpcMessageReplacer.Replace(e.Return.Body))...),
+			})
+		}
+	}
+
+	return ePages, locations
+}
+
+// generateGRPCErrorPageLocations returns the unique named locations of the error pages of the locations for gRPC upstreams.
+func generateGRPCErrorPageLocations(locations []version2.Location) []version2.ErrorPageLocation {
+	var errorPageLocations []version2.ErrorPageLocation
+	seen := make(map[string]bool)
+
+	for _, l := range locations {
+		for _, epl := range l.GRPCErrorPageLocations {
+			if seen[epl.Name] {
+				continue
+			}
+			seen[epl.Name] = true
+			errorPageLocations = append(errorPageLocations, epl)
+		}
+	}
+
+	return errorPageLocations
+}
+
 func generateErrorPageLocations(errPageIndex int, errorPages []conf_v1.ErrorPage) []version2.ErrorPageLocation {
 	var errorPageLocations []version2.ErrorPageLocation
 	for i, e := range errorPages {
@@ -3012,6 +4022,8 @@ func isTLSEnabled(upstream conf_v1.Upstream, hasSpiffeCerts, isInternalRoute boo
 	return upstream.TLS.Enable || hasSpiffeCerts
 }
 
+const grpcContentType = "application/grpc"
+
 func isGRPC(protocolType string) bool {
 	return protocolType == "grpc"
 }
@@ -4,12 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
+	"path"
 	"reflect"
+	"regexp"
 	"sort"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/nginx/kubernetes-ingress/internal/configs/version1"
 	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
 	"github.com/nginx/kubernetes-ingress/internal/k8s/secrets"
 	"github.com/nginx/kubernetes-ingress/internal/nginx"
@@ -23,6 +27,10 @@ func createPointerFromBool(b bool) *bool {
 	return &b
 }
 
+func createPointerFromString(s string) *string {
+	return &s
+}
+
 func createPointerFromInt(n int) *int {
 	return &n
 }
@@ -3375,7 +3383,7 @@ func TestGenerateVirtualServerConfigIPV6Disabled(t *testing.T) {
 	}
 }
 
-func TestGenerateVirtualServerConfigGrpcErrorPageWarning(t *testing.T) {
+func TestGenerateVirtualServerConfigGrpcErrorPages(t *testing.T) {
 	t.Parallel()
 	virtualServerEx := VirtualServerEx{
 		VirtualServer: &conf_v1.VirtualServer{
@@ -3427,6 +3435,17 @@ func TestGenerateVirtualServerConfigGrpcErrorPageWarning(t *testing.T) {
 										Code: 200,
 										Type: "text/plain",
 										Body: "All Good",
+										Headers: []conf_v1.Header{
+											{Name: "x-debug", Value: "true"},
+										},
+									},
+								},
+							},
+							{
+								Codes: []int{502},
+								Redirect: &conf_v1.ErrorPageRedirect{
+									ActionRedirect: conf_v1.ActionRedirect{
+										URL: "http://nginx.org",
 									},
 								},
 							},
@@ -3507,6 +3526,61 @@ func TestGenerateVirtualServerConfigGrpcErrorPageWarning(t *testing.T) {
 		HTTP2:   true,
 	}
 
+	grpcErrorPageLocations := []version2.ErrorPageLocation{
+		{
+			Name:        "@grpc_error_page_0_0_404",
+			DefaultType: "application/grpc",
+			Return:      &version2.Return{},
+			Headers: []version2.Header{
+				{Name: "x-debug", Value: "true"},
+				{Name: "content-type", Value: "application/grpc"},
+				{Name: "grpc-status", Value: "12"},
+				{Name: "grpc-message", Value: "All Good"},
+			},
+		},
+		{
+			Name:        "@grpc_error_page_0_0_405",
+			DefaultType: "application/grpc",
+			Return:      &version2.Return{},
+			Headers: []version2.Header{
+				{Name: "x-debug", Value: "true"},
+				{Name: "content-type", Value: "application/grpc"},
+				{Name: "grpc-status", Value: "13"},
+				{Name: "grpc-message", Value: "All Good"},
+			},
+		},
+		{
+			Name:        "@grpc_error_page_1_0_404",
+			DefaultType: "application/grpc",
+			Return:      &version2.Return{},
+			Headers: []version2.Header{
+				{Name: "content-type", Value: "application/grpc"},
+				{Name: "grpc-status", Value: "12"},
+				{Name: "grpc-message", Value: "Original resource not found, but success!"},
+			},
+		},
+		{
+			Name:        "@grpc_error_page_2_0_404",
+			DefaultType: "application/grpc",
+			Return:      &version2.Return{},
+			Headers: []version2.Header{
+				{Name: "content-type", Value: "application/grpc"},
+				{Name: "grpc-status", Value: "12"},
+				{Name: "grpc-message", Value: "All Good"},
+			},
+		},
+		{
+			Name:        "@grpc_error_page_2_0_405",
+			DefaultType: "application/grpc",
+			Return:      &version2.Return{},
+			Headers: []version2.Header{
+				{Name: "content-type", Value: "application/grpc"},
+				{Name: "grpc-status", Value: "13"},
+				{Name: "grpc-message", Value: "All Good"},
+			},
+		},
+	}
+
 	expected := version2.VirtualServerConfig{
 		Upstreams: []version2.Upstream{
 			{
@@ -3611,13 +3685,17 @@ func TestGenerateVirtualServerConfigGrpcErrorPageWarning(t *testing.T) {
 					ProxyNextUpstream:        "error timeout",
 					ProxyNextUpstreamTimeout: "0s",
 					ProxyNextUpstreamTries:   0,
-					ErrorPages:               []version2.ErrorPage{{Name: "@error_page_0_0", Codes: "404 405", ResponseCode: 200}},
-					ProxyInterceptErrors:     true,
-					ProxySSLName:             "grpc-svc.default.svc",
-					ProxyPassRequestHeaders:  true,
-					ProxySetHeaders:          []version2.Header{{Name: "Host", Value: "$host"}},
-					ServiceName:              "grpc-svc",
-					GRPCPass:                 "grpcs://vs_default_cafe_grpc-app-1",
+					ErrorPages: []version2.ErrorPage{
+						{Name: "@grpc_error_page_0_0_404", Codes: "404", ResponseCode: 204},
+						{Name: "@grpc_error_page_0_0_405", Codes: "405", ResponseCode: 204},
+					},
+					ProxyInterceptErrors:    true,
+					ProxySSLName:            "grpc-svc.default.svc",
+					ProxyPassRequestHeaders: true,
+					ProxySetHeaders:         []version2.Header{{Name: "Host", Value: "$host"}},
+					ServiceName:             "grpc-svc",
+					GRPCPass:                "grpcs://vs_default_cafe_grpc-app-1",
+					GRPCErrorPageLocations:  grpcErrorPageLocations[0:2],
 				},
 				{
 					Path:                     "/internal_location_matches_0_match_0",
@@ -3627,13 +3705,14 @@ func TestGenerateVirtualServerConfigGrpcErrorPageWarning(t *testing.T) {
 					ProxyNextUpstreamTimeout: "0s",
 					ProxyNextUpstreamTries:   0,
 					Rewrites:                 []string{"^ $request_uri break"},
-					ErrorPages:               []version2.ErrorPage{{Name: "@error_page_1_0", Codes: "404", ResponseCode: 200}},
+					ErrorPages:               []version2.ErrorPage{{Name: "@grpc_error_page_1_0_404", Codes: "404", ResponseCode: 204}},
 					ProxyInterceptErrors:     true,
 					ProxySSLName:             "grpc-svc2.default.svc",
 					ProxyPassRequestHeaders:  true,
 					ProxySetHeaders:          []version2.Header{{Name: "Host", Value: "$host"}},
 					ServiceName:              "grpc-svc2",
 					GRPCPass:                 "grpcs://vs_default_cafe_grpc-app-2",
+					GRPCErrorPageLocations:   grpcErrorPageLocations[2:3],
 				},
 				{
 					Path:                     "/internal_location_matches_0_default",
@@ -3657,14 +3736,18 @@ func TestGenerateVirtualServerConfigGrpcErrorPageWarning(t *testing.T) {
 					ProxyNextUpstreamTimeout: "0s",
 					ProxyNextUpstreamTries:   0,
 					HasKeepalive:             false,
-					ErrorPages:               []version2.ErrorPage{{Name: "@error_page_2_0", Codes: "404 405", ResponseCode: 200}},
-					ProxyInterceptErrors:     true,
-					Rewrites:                 []string{"^ $request_uri break"},
-					ProxySSLName:             "grpc-svc.default.svc",
-					ProxyPassRequestHeaders:  true,
-					ProxySetHeaders:          []version2.Header{{Name: "Host", Value: "$host"}},
-					ServiceName:              "grpc-svc",
-					GRPCPass:                 "grpcs://vs_default_cafe_grpc-app-1",
+					ErrorPages: []version2.ErrorPage{
+						{Name: "@grpc_error_page_2_0_404", Codes: "404", ResponseCode: 204},
+						{Name: "@grpc_error_page_2_0_405", Codes: "405", ResponseCode: 204},
+					},
+					ProxyInterceptErrors:    true,
+					Rewrites:                []string{"^ $request_uri break"},
+					ProxySSLName:            "grpc-svc.default.svc",
+					ProxyPassRequestHeaders: true,
+					ProxySetHeaders:         []version2.Header{{Name: "Host", Value: "$host"}},
+					ServiceName:             "grpc-svc",
+					GRPCPass:                "grpcs://vs_default_cafe_grpc-app-1",
+					GRPCErrorPageLocations:  grpcErrorPageLocations[3:5],
 				},
 				{
 					Path:                     "/internal_location_splits_0_split_1",
@@ -3674,21 +3757,26 @@ func TestGenerateVirtualServerConfigGrpcErrorPageWarning(t *testing.T) {
 					ProxyNextUpstreamTimeout: "0s",
 					ProxyNextUpstreamTries:   0,
 					HasKeepalive:             false,
-					ErrorPages:               []version2.ErrorPage{{Name: "@error_page_2_0", Codes: "404 405", ResponseCode: 200}},
-					ProxyInterceptErrors:     true,
-					Rewrites:                 []string{"^ $request_uri break"},
-					ProxySSLName:             "grpc-svc2.default.svc",
-					ProxyPassRequestHeaders:  true,
-					ProxySetHeaders:          []version2.Header{{Name: "Host", Value: "$host"}},
-					ServiceName:              "grpc-svc2",
-					GRPCPass:                 "grpcs://vs_default_cafe_grpc-app-2",
+					ErrorPages: []version2.ErrorPage{
+						{Name: "@grpc_error_page_2_0_404", Codes: "404", ResponseCode: 204},
+						{Name: "@grpc_error_page_2_0_405", Codes: "405", ResponseCode: 204},
+					},
+					ProxyInterceptErrors:    true,
+					Rewrites:                []string{"^ $request_uri break"},
+					ProxySSLName:            "grpc-svc2.default.svc",
+					ProxyPassRequestHeaders: true,
+					ProxySetHeaders:         []version2.Header{{Name: "Host", Value: "$host"}},
+					ServiceName:             "grpc-svc2",
+					GRPCPass:                "grpcs://vs_default_cafe_grpc-app-2",
+					GRPCErrorPageLocations:  grpcErrorPageLocations[3:5],
 				},
 			},
-			ErrorPageLocations: []version2.ErrorPageLocation{
+			ErrorPageLocations: append([]version2.ErrorPageLocation{
 				{
 					Name:        "@error_page_0_0",
 					DefaultType: "text/plain",
 					Return:      &version2.Return{Text: "All Good"},
+					Headers:     []version2.Header{{Name: "x-debug", Value: "true"}},
 				},
 				{
 					Name:        "@error_page_1_0",
@@ -3700,7 +3788,7 @@ func TestGenerateVirtualServerConfigGrpcErrorPageWarning(t *testing.T) {
 					DefaultType: "text/plain",
 					Return:      &version2.Return{Text: "All Good"},
 				},
-			},
+			}, grpcErrorPageLocations...),
 		},
 		SplitClients: []version2.SplitClient{
 			{
@@ -3721,10 +3809,7 @@ func TestGenerateVirtualServerConfigGrpcErrorPageWarning(t *testing.T) {
 	}
 	expectedWarnings := Warnings{
 		virtualServerEx.VirtualServer: {
-			`The error page configuration for the upstream grpc-app-1 is ignored for status code(s) [404 405], which cannot be used for GRPC upstreams.`,
-			`The error page configuration for the upstream grpc-app-2 is ignored for status code(s) [404], which cannot be used for GRPC upstreams.`,
-			`The error page configuration for the upstream grpc-app-1 is ignored for status code(s) [404 405], which cannot be used for GRPC upstreams.`,
-			`The error page configuration for the upstream grpc-app-2 is ignored for status code(s) [404 405], which cannot be used for GRPC upstreams.`,
+			`The error page redirect for the upstream grpc-app-1 is ignored for status code(s) [502], because redirects cannot be used for GRPC upstreams.`,
 		},
 	}
 	isPlus := false
@@ -3734,7 +3819,7 @@ func TestGenerateVirtualServerConfigGrpcErrorPageWarning(t *testing.T) {
 
 	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
 	if diff := cmp.Diff(expected, result); diff != "" {
-		t.Errorf("TestGenerateVirtualServerConfigGrpcErrorPageWarning() mismatch (-want +got):\n%s", diff)
+		t.Errorf("TestGenerateVirtualServerConfigGrpcErrorPages() mismatch (-want +got):\n%s", diff)
 	}
 
 	if !reflect.DeepEqual(vsc.warnings, expectedWarnings) {
@@ -5022,6 +5107,187 @@ func TestGenerateVirtualServerConfigForVirtualServerRoutesWithDos(t *testing.T)
 	}
 }
 
+func TestGenerateVirtualServerConfigWithTracing(t *testing.T) {
+	t.Parallel()
+
+	virtualServerEx := vsEx()
+	virtualServerEx.VirtualServer.Spec.Tracing = &conf_v1.Tracing{
+		Enable: createPointerFromBool(true),
+		SpanAttributes: []conf_v1.SpanAttribute{
+			{Name: "tenant", Value: "cafe"},
+		},
+	}
+	virtualServerEx.VirtualServer.Spec.Routes[0].Tracing = &conf_v1.Tracing{
+		Enable: createPointerFromBool(false),
+	}
+	virtualServerEx.VirtualServer.Spec.Routes[2].Tracing = &conf_v1.Tracing{
+		SpanAttributes: []conf_v1.SpanAttribute{
+			{Name: "menu", Value: "coffee"},
+		},
+	}
+	virtualServerEx.VirtualServer.Spec.Routes[3].Tracing = &conf_v1.Tracing{
+		Enable: createPointerFromBool(false),
+	}
+	virtualServerEx.VirtualServerRoutes[1].Spec.Subroutes[0].Tracing = &conf_v1.Tracing{
+		Enable: createPointerFromBool(true),
+		SpanAttributes: []conf_v1.SpanAttribute{
+			{Name: "request", Value: "${request_uri}"},
+		},
+	}
+
+	cfgParams := baseCfgParams
+	cfgParams.MainOtelLoadModule = true
+	cfgParams.MainOtelSamplerRatio = 0.5
+
+	wantServerTracing := &version2.Tracing{
+		Trace:          "$otel_trace_sampler",
+		SpanAttributes: []version2.SpanAttribute{{Name: "tenant", Value: "cafe"}},
+	}
+	wantLocationTracing := map[string]*version2.Tracing{
+		"/tea": {
+			Trace: "off",
+		},
+		"/tea-latest": nil,
+		"/coffee": {
+			SpanAttributes: []version2.SpanAttribute{{Name: "menu", Value: "coffee"}},
+		},
+		"/subtea": {
+			Trace:          "$otel_trace_sampler",
+			SpanAttributes: []version2.SpanAttribute{{Name: "request", Value: "${request_uri}"}},
+		},
+		"/coffee-errorpage":                  nil,
+		"/coffee-errorpage-subroute":         nil,
+		"/coffee-errorpage-subroute-defined": nil,
+	}
+
+	vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+	if len(warnings) != 0 {
+		t.Errorf("GenerateVirtualServerConfig returned warnings: %v", warnings)
+	}
+	if !cmp.Equal(wantServerTracing, result.Server.Tracing) {
+		t.Error(cmp.Diff(wantServerTracing, result.Server.Tracing))
+	}
+	if len(result.Server.Locations) != len(wantLocationTracing) {
+		t.Fatalf("GenerateVirtualServerConfig returned %d locations, want %d", len(result.Server.Locations), len(wantLocationTracing))
+	}
+	for _, loc := range result.Server.Locations {
+		if !cmp.Equal(wantLocationTracing[loc.Path], loc.Tracing) {
+			t.Errorf("location %s: %s", loc.Path, cmp.Diff(wantLocationTracing[loc.Path], loc.Tracing))
+		}
+	}
+}
+
+func TestGenerateVirtualServerConfigWithTracingWarning(t *testing.T) {
+	t.Parallel()
+
+	virtualServerEx := vsEx()
+	virtualServerEx.VirtualServer.Spec.Tracing = &conf_v1.Tracing{
+		Enable: createPointerFromBool(true),
+	}
+
+	vsc := newVirtualServerConfigurator(&baseCfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+	if result.Server.Tracing != nil {
+		t.Errorf("GenerateVirtualServerConfig returned tracing %v, want nil", result.Server.Tracing)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("GenerateVirtualServerConfig returned %d warnings, want 1", len(warnings))
+	}
+}
+
+func TestGenerateVirtualServerConfigWithAccessLog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		accessLog *conf_v1.AccessLog
+		want      *version2.AccessLog
+		msg       string
+	}{
+		{
+			accessLog: nil,
+			want:      nil,
+			msg:       "no access log",
+		},
+		{
+			accessLog: &conf_v1.AccessLog{},
+			want: &version2.AccessLog{
+				Destination:    "/dev/stdout",
+				Format:         "main",
+				LatencyMetrics: true,
+			},
+			msg: "default destination and format",
+		},
+		{
+			accessLog: &conf_v1.AccessLog{
+				Destination: "syslog:server=localhost:514",
+				Format:      "json",
+			},
+			want: &version2.AccessLog{
+				Destination:    "syslog:server=localhost:514",
+				Format:         "json",
+				LatencyMetrics: true,
+			},
+			msg: "preset format",
+		},
+		{
+			accessLog: &conf_v1.AccessLog{
+				Destination: "/var/log/nginx/cafe.log",
+				Format:      "short",
+			},
+			want: &version2.AccessLog{
+				Destination:    "/var/log/nginx/cafe.log",
+				Format:         "short",
+				LatencyMetrics: true,
+			},
+			msg: "format defined in the ConfigMap",
+		},
+	}
+
+	cfgParams := baseCfgParams
+	cfgParams.MainNamedLogFormats = []version1.NamedLogFormat{{Name: "short", Format: "$status"}}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			t.Parallel()
+
+			virtualServerEx := vsEx()
+			virtualServerEx.VirtualServer.Spec.AccessLog = test.accessLog
+
+			vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{EnableLatencyMetrics: true}, false, &fakeBV)
+
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			if len(warnings) != 0 {
+				t.Errorf("GenerateVirtualServerConfig returned warnings: %v", warnings)
+			}
+			if !cmp.Equal(test.want, result.Server.AccessLog) {
+				t.Error(cmp.Diff(test.want, result.Server.AccessLog))
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigWithAccessLogWarning(t *testing.T) {
+	t.Parallel()
+
+	virtualServerEx := vsEx()
+	virtualServerEx.VirtualServer.Spec.AccessLog = &conf_v1.AccessLog{
+		Format: "verbose",
+	}
+
+	vsc := newVirtualServerConfigurator(&baseCfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+	if result.Server.AccessLog != nil {
+		t.Errorf("GenerateVirtualServerConfig returned access log %v, want nil", result.Server.AccessLog)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("GenerateVirtualServerConfig returned %d warnings, want 1", len(warnings))
+	}
+}
+
 func TestGenerateVirtualServerConfigForVirtualServerWithReturns(t *testing.T) {
 	t.Parallel()
 	virtualServerEx := VirtualServerEx{
@@ -7162,155 +7428,450 @@ func TestGeneratePolicies(t *testing.T) {
 			},
 			msg: "WAF reference",
 		},
-	}
-
-	vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
-	// required to test the scaling of the ratelimit
-	vsc.IngressControllerReplicas = 2
-
-	for _, tc := range tests {
-		t.Run(tc.msg, func(t *testing.T) {
-			result := vsc.generatePolicies(ownerDetails, tc.policyRefs, tc.policies, tc.context, policyOpts)
-			result.BundleValidator = nil
-
-			if !cmp.Equal(tc.expected, result) {
-				t.Error(cmp.Diff(tc.expected, result))
-			}
-			if len(vsc.warnings) > 0 {
-				t.Errorf("generatePolicies() returned unexpected warnings %v for the case of %s", vsc.warnings, tc.msg)
-			}
-		})
-	}
-}
-
-func TestGeneratePolicies_GeneratesWAFPolicyOnValidApBundle(t *testing.T) {
-	t.Parallel()
-
-	ownerDetails := policyOwnerDetails{
-		owner:          nil, // nil is OK for the unit test
-		ownerNamespace: "default",
-		vsNamespace:    "default",
-		vsName:         "test",
-	}
-
-	tests := []struct {
-		name       string
-		policyRefs []conf_v1.PolicyReference
-		policies   map[string]*conf_v1.Policy
-		policyOpts policyOptions
-		context    string
-		want       policiesCfg
-	}{
 		{
-			name: "valid bundle",
 			policyRefs: []conf_v1.PolicyReference{
 				{
-					Name:      "waf-bundle",
+					Name:      "retry-policy",
 					Namespace: "default",
 				},
 			},
 			policies: map[string]*conf_v1.Policy{
-				"default/waf-bundle": {
+				"default/retry-policy": {
 					Spec: conf_v1.PolicySpec{
-						WAF: &conf_v1.WAF{
-							Enable:   true,
-							ApBundle: "testWAFPolicyBundle.tgz",
+						Retry: &conf_v1.Retry{
+							RetryOn:       []string{"error", "http_503"},
+							Tries:         createPointerFromInt(3),
+							PerTryTimeout: "5s",
+							Timeout:       "1m",
 						},
 					},
 				},
 			},
 			context: "route",
-			want: policiesCfg{
-				WAF: &version2.WAF{
-					Enable:   "on",
-					ApBundle: "/fake/bundle/path/testWAFPolicyBundle.tgz",
+			expected: policiesCfg{
+				Retry: &retryCfg{
+					NextUpstream:        "error http_503",
+					NextUpstreamTries:   3,
+					NextUpstreamTimeout: "1m",
+					PerTryTimeout:       "5s",
+					Sampling:            100,
 				},
 			},
+			msg: "retry reference",
 		},
 		{
-			name: "valid bundle with logConf",
 			policyRefs: []conf_v1.PolicyReference{
 				{
-					Name:      "waf-bundle",
+					Name:      "retry-policy",
 					Namespace: "default",
 				},
 			},
 			policies: map[string]*conf_v1.Policy{
-				"default/waf-bundle": {
+				"default/retry-policy": {
 					Spec: conf_v1.PolicySpec{
-						WAF: &conf_v1.WAF{
-							Enable:   true,
-							ApBundle: "testWAFPolicyBundle.tgz",
-							SecurityLogs: []*conf_v1.SecurityLog{
-								{
-									Enable:      true,
-									ApLogBundle: "secops_dashboard.tgz",
-								},
-							},
+						Retry: &conf_v1.Retry{
+							RetryOn:  []string{"error", "http_503"},
+							Sampling: createPointerFromInt(25),
 						},
 					},
 				},
 			},
 			context: "route",
-			want: policiesCfg{
-				WAF: &version2.WAF{
-					Enable:              "on",
-					ApBundle:            "/fake/bundle/path/testWAFPolicyBundle.tgz",
-					ApSecurityLogEnable: true,
-					ApLogConf:           []string{"/fake/bundle/path/secops_dashboard.tgz syslog:server=localhost:514"},
+			expected: policiesCfg{
+				Retry: &retryCfg{
+					NextUpstream:        "error http_503",
+					NextUpstreamTries:   0,
+					NextUpstreamTimeout: "0s",
+					Sampling:            25,
 				},
 			},
+			msg: "retry reference with sampling",
 		},
-	}
-	for _, tc := range tests {
-		t.Run(tc.name, func(t *testing.T) {
-			vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
-			res := vsc.generatePolicies(ownerDetails, tc.policyRefs, tc.policies, tc.context, policyOptions{apResources: &appProtectResourcesForVS{}})
-			res.BundleValidator = nil
-			if !cmp.Equal(tc.want, res) {
-				t.Error(cmp.Diff(tc.want, res))
-			}
-		})
-	}
-}
-
-func TestGeneratePoliciesFails(t *testing.T) {
-	t.Parallel()
-	ownerDetails := policyOwnerDetails{
-		owner:          nil, // nil is OK for the unit test
-		ownerNamespace: "default",
-		vsNamespace:    "default",
-		vsName:         "test",
-	}
-
-	dryRunOverride := true
-	rejectCodeOverride := 505
-
-	ingressMTLSCertPath := "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crt"
-	ingressMTLSCrlPath := "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crl"
-
-	tests := []struct {
-		policyRefs        []conf_v1.PolicyReference
-		policies          map[string]*conf_v1.Policy
-		policyOpts        policyOptions
-		trustedCAFileName string
-		context           string
-		oidcPolCfg        *oidcPolicyCfg
-		expected          policiesCfg
-		expectedWarnings  Warnings
-		expectedOidc      *oidcPolicyCfg
-		msg               string
-	}{
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
-					Name:      "allow-policy",
+					Name:      "retry-policy",
 					Namespace: "default",
 				},
 			},
-			policies:   map[string]*conf_v1.Policy{},
-			policyOpts: policyOptions{},
-			expected: policiesCfg{
+			policies: map[string]*conf_v1.Policy{
+				"default/retry-policy": {
+					Spec: conf_v1.PolicySpec{
+						Retry: &conf_v1.Retry{
+							Sampling: createPointerFromInt(0),
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				Retry: &retryCfg{
+					NextUpstream:        "off",
+					NextUpstreamTries:   0,
+					NextUpstreamTimeout: "0s",
+					Sampling:            0,
+				},
+			},
+			msg: "retry reference with zero sampling",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "cors-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/cors-policy": {
+					Spec: conf_v1.PolicySpec{
+						CORS: &conf_v1.CORS{
+							AllowOrigins:  []string{"https://example.com"},
+							ExposeHeaders: []string{"X-Request-ID", "X-Version"},
+							MaxAge:        createPointerFromInt(3600),
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				CORS: &version2.CORS{
+					OriginVariable:        "$pol_cors_default_cors_policy_default_test_origin",
+					PreflightVariable:     "$pol_cors_default_cors_policy_default_test_preflight",
+					AllowMethods:          "GET, HEAD, POST",
+					AllowHeaders:          "$http_access_control_request_headers",
+					ExposeHeadersVariable: "$pol_cors_default_cors_policy_default_test_expose_headers",
+					MaxAge:                3600,
+				},
+				Maps: []version2.Map{
+					{
+						Source:   "$http_origin",
+						Variable: "$pol_cors_default_cors_policy_default_test_origin",
+						Parameters: []version2.Parameter{
+							{Value: "default", Result: `""`},
+							{Value: `"https://example.com"`, Result: "$http_origin"},
+						},
+					},
+					{
+						Source:   `"$request_method:$http_access_control_request_method"`,
+						Variable: "$pol_cors_default_cors_policy_default_test_preflight",
+						Parameters: []version2.Parameter{
+							{Value: "default", Result: "0"},
+							{Value: `"~^OPTIONS:."`, Result: "1"},
+						},
+					},
+					{
+						Source:   "$pol_cors_default_cors_policy_default_test_origin",
+						Variable: "$pol_cors_default_cors_policy_default_test_expose_headers",
+						Parameters: []version2.Parameter{
+							{Value: "default", Result: `"X-Request-ID, X-Version"`},
+							{Value: `""`, Result: `""`},
+						},
+					},
+				},
+			},
+			msg: "cors reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "cache-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/cache-policy": {
+					Spec: conf_v1.PolicySpec{
+						Cache: &conf_v1.Cache{
+							MaxSize:  "1g",
+							Inactive: "1h",
+							Key:      "${scheme}${host}${request_uri}",
+							Valid: []conf_v1.CacheValid{
+								{Codes: []int{200, 302}, Time: "10m"},
+								{Time: "1m"},
+							},
+							Lock: &conf_v1.CacheLock{
+								Enable:  true,
+								Timeout: "5s",
+							},
+							UseStale: []string{"error", "updating"},
+							Bypass:   []string{"${cookie_nocache}", "${arg_nocache}"},
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				Cache: &version2.Cache{
+					Zone:        "pol_cache_default_cache_policy",
+					Key:         "${scheme}${host}${request_uri}",
+					Valid:       []string{"200 302 10m", "1m"},
+					Lock:        true,
+					LockTimeout: "5s",
+					UseStale:    "error updating",
+					Bypass:      `"${cookie_nocache}" "${arg_nocache}"`,
+				},
+				CacheZones: []version2.CacheZone{
+					{
+						Name:     "pol_cache_default_cache_policy",
+						Path:     "/var/cache/nginx/pol_cache_default_cache_policy",
+						Levels:   "1:2",
+						Size:     "10m",
+						MaxSize:  "1g",
+						Inactive: "1h",
+					},
+				},
+			},
+			msg: "cache reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "ext-auth-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/ext-auth-policy": {
+					Spec: conf_v1.PolicySpec{
+						ExternalAuth: &conf_v1.ExternalAuth{
+							Service: &conf_v1.ExternalAuthService{
+								Name: "authz",
+								Port: 8080,
+								Path: "/check",
+							},
+							ForwardHeaders:  []string{"Authorization"},
+							ResponseHeaders: []string{"X-User-ID"},
+							CacheTTL:        "30s",
+							SkipPaths:       []string{"/public"},
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				ExternalAuth: &version2.ExternalAuth{
+					Path:        "/_pol_ext_auth_default_ext_auth_policy_default_test",
+					URIVariable: "$pol_ext_auth_default_ext_auth_policy_default_test_uri",
+					ResponseHeaders: []version2.ExternalAuthHeader{
+						{
+							Name:             "X-User-ID",
+							Variable:         "$pol_ext_auth_default_ext_auth_policy_default_test_x_user_id",
+							UpstreamVariable: "$upstream_http_x_user_id",
+						},
+					},
+				},
+				ExternalAuthLocation: &version2.ExternalAuthLocation{
+					Path:           "/_pol_ext_auth_default_ext_auth_policy_default_test",
+					ProxyPass:      "http://pol_ext_auth_default_ext_auth_policy_default_test/check",
+					ForwardHeaders: []version2.Header{{Name: "Authorization", Value: "$http_authorization"}},
+					SkipVariable:   "$pol_ext_auth_default_ext_auth_policy_default_test_skip",
+					CacheZone:      "pol_ext_auth_default_ext_auth_policy_default_test",
+					CacheKey:       "$request_method:$request_uri:$http_authorization",
+					CacheTTL:       "30s",
+				},
+				ExternalAuthUpstream: &externalAuthUpstream{
+					Name:      "pol_ext_auth_default_ext_auth_policy_default_test",
+					Namespace: "default",
+					Upstream:  conf_v1.Upstream{Name: "authz", Service: "authz", Port: 8080},
+				},
+				CacheZones: []version2.CacheZone{
+					{
+						Name:     "pol_ext_auth_default_ext_auth_policy_default_test",
+						Path:     "/var/cache/nginx/pol_ext_auth_default_ext_auth_policy_default_test",
+						Size:     "1m",
+						Inactive: "30s",
+					},
+				},
+				Maps: []version2.Map{
+					{
+						Source:   "$pol_ext_auth_default_ext_auth_policy_default_test_uri",
+						Variable: "$pol_ext_auth_default_ext_auth_policy_default_test_skip",
+						Parameters: []version2.Parameter{
+							{Value: "default", Result: "0"},
+							{Value: `"~^/public(/|$)"`, Result: "1"},
+						},
+					},
+				},
+			},
+			msg: "external auth reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "ext-auth-url-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/ext-auth-url-policy": {
+					Spec: conf_v1.PolicySpec{
+						ExternalAuth: &conf_v1.ExternalAuth{
+							URL: "https://authz.example.com/check",
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				ExternalAuth: &version2.ExternalAuth{
+					Path: "/_pol_ext_auth_default_ext_auth_url_policy_default_test",
+				},
+				ExternalAuthLocation: &version2.ExternalAuthLocation{
+					Path:      "/_pol_ext_auth_default_ext_auth_url_policy_default_test",
+					ProxyPass: "https://authz.example.com/check",
+				},
+			},
+			msg: "external auth reference with url",
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, true, &StaticConfigParams{}, false, &fakeBV)
+	// required to test the scaling of the ratelimit
+	vsc.IngressControllerReplicas = 2
+
+	for _, tc := range tests {
+		t.Run(tc.msg, func(t *testing.T) {
+			result := vsc.generatePolicies(ownerDetails, tc.policyRefs, tc.policies, tc.context, policyOpts)
+			result.BundleValidator = nil
+
+			if !cmp.Equal(tc.expected, result) {
+				t.Error(cmp.Diff(tc.expected, result))
+			}
+			if len(vsc.warnings) > 0 {
+				t.Errorf("generatePolicies() returned unexpected warnings %v for the case of %s", vsc.warnings, tc.msg)
+			}
+		})
+	}
+}
+
+func TestGeneratePolicies_GeneratesWAFPolicyOnValidApBundle(t *testing.T) {
+	t.Parallel()
+
+	ownerDetails := policyOwnerDetails{
+		owner:          nil, // nil is OK for the unit test
+		ownerNamespace: "default",
+		vsNamespace:    "default",
+		vsName:         "test",
+	}
+
+	tests := []struct {
+		name       string
+		policyRefs []conf_v1.PolicyReference
+		policies   map[string]*conf_v1.Policy
+		policyOpts policyOptions
+		context    string
+		want       policiesCfg
+	}{
+		{
+			name: "valid bundle",
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "waf-bundle",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/waf-bundle": {
+					Spec: conf_v1.PolicySpec{
+						WAF: &conf_v1.WAF{
+							Enable:   true,
+							ApBundle: "testWAFPolicyBundle.tgz",
+						},
+					},
+				},
+			},
+			context: "route",
+			want: policiesCfg{
+				WAF: &version2.WAF{
+					Enable:   "on",
+					ApBundle: "/fake/bundle/path/testWAFPolicyBundle.tgz",
+				},
+			},
+		},
+		{
+			name: "valid bundle with logConf",
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "waf-bundle",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/waf-bundle": {
+					Spec: conf_v1.PolicySpec{
+						WAF: &conf_v1.WAF{
+							Enable:   true,
+							ApBundle: "testWAFPolicyBundle.tgz",
+							SecurityLogs: []*conf_v1.SecurityLog{
+								{
+									Enable:      true,
+									ApLogBundle: "secops_dashboard.tgz",
+								},
+							},
+						},
+					},
+				},
+			},
+			context: "route",
+			want: policiesCfg{
+				WAF: &version2.WAF{
+					Enable:              "on",
+					ApBundle:            "/fake/bundle/path/testWAFPolicyBundle.tgz",
+					ApSecurityLogEnable: true,
+					ApLogConf:           []string{"/fake/bundle/path/secops_dashboard.tgz syslog:server=localhost:514"},
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+			res := vsc.generatePolicies(ownerDetails, tc.policyRefs, tc.policies, tc.context, policyOptions{apResources: &appProtectResourcesForVS{}})
+			res.BundleValidator = nil
+			if !cmp.Equal(tc.want, res) {
+				t.Error(cmp.Diff(tc.want, res))
+			}
+		})
+	}
+}
+
+func TestGeneratePoliciesFails(t *testing.T) {
+	t.Parallel()
+	ownerDetails := policyOwnerDetails{
+		owner:          nil, // nil is OK for the unit test
+		ownerNamespace: "default",
+		vsNamespace:    "default",
+		vsName:         "test",
+	}
+
+	dryRunOverride := true
+	rejectCodeOverride := 505
+
+	ingressMTLSCertPath := "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crt"
+	ingressMTLSCrlPath := "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crl"
+
+	tests := []struct {
+		policyRefs        []conf_v1.PolicyReference
+		policies          map[string]*conf_v1.Policy
+		policyOpts        policyOptions
+		trustedCAFileName string
+		context           string
+		oidcPolCfg        *oidcPolicyCfg
+		expected          policiesCfg
+		expectedWarnings  Warnings
+		expectedOidc      *oidcPolicyCfg
+		msg               string
+	}{
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "allow-policy",
+					Namespace: "default",
+				},
+			},
+			policies:   map[string]*conf_v1.Policy{},
+			policyOpts: policyOptions{},
+			expected: policiesCfg{
 				ErrorReturn: &version2.Return{
 					Code: 500,
 				},
@@ -8823,6 +9384,50 @@ func TestGeneratePoliciesFails(t *testing.T) {
 			expectedOidc: &oidcPolicyCfg{},
 			msg:          "multi waf",
 		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "retry-policy",
+					Namespace: "default",
+				},
+				{
+					Name:      "retry-policy2",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/retry-policy": {
+					Spec: conf_v1.PolicySpec{
+						Retry: &conf_v1.Retry{
+							Tries: createPointerFromInt(3),
+						},
+					},
+				},
+				"default/retry-policy2": {
+					Spec: conf_v1.PolicySpec{
+						Retry: &conf_v1.Retry{
+							Tries: createPointerFromInt(5),
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				Retry: &retryCfg{
+					NextUpstream:        "error timeout",
+					NextUpstreamTries:   3,
+					NextUpstreamTimeout: "0s",
+					Sampling:            100,
+				},
+			},
+			expectedWarnings: Warnings{
+				nil: {
+					`Multiple retry policies in the same context is not valid. Retry policy default/retry-policy2 will be ignored`,
+				},
+			},
+			expectedOidc: &oidcPolicyCfg{},
+			msg:          "multi retry",
+		},
 	}
 
 	for _, test := range tests {
@@ -8856,78 +9461,441 @@ func TestGeneratePoliciesFails(t *testing.T) {
 	}
 }
 
-func TestRemoveDuplicates(t *testing.T) {
+func TestRemoveDuplicates(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		rlz      []version2.LimitReqZone
+		expected []version2.LimitReqZone
+	}{
+		{
+			rlz: []version2.LimitReqZone{
+				{ZoneName: "test"},
+				{ZoneName: "test"},
+				{ZoneName: "test2"},
+				{ZoneName: "test3"},
+			},
+			expected: []version2.LimitReqZone{
+				{ZoneName: "test"},
+				{ZoneName: "test2"},
+				{ZoneName: "test3"},
+			},
+		},
+		{
+			rlz: []version2.LimitReqZone{
+				{ZoneName: "test"},
+				{ZoneName: "test"},
+				{ZoneName: "test2"},
+				{ZoneName: "test3"},
+				{ZoneName: "test3"},
+			},
+			expected: []version2.LimitReqZone{
+				{ZoneName: "test"},
+				{ZoneName: "test2"},
+				{ZoneName: "test3"},
+			},
+		},
+	}
+	for _, test := range tests {
+		result := removeDuplicateLimitReqZones(test.rlz)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("removeDuplicateLimitReqZones() returned \n%v, but expected \n%v", result, test.expected)
+		}
+	}
+}
+
+func TestAddPoliciesCfgToLocations(t *testing.T) {
+	t.Parallel()
+	cfg := policiesCfg{
+		Allow: []string{"127.0.0.1"},
+		Deny:  []string{"127.0.0.2"},
+		ErrorReturn: &version2.Return{
+			Code: 400,
+		},
+	}
+
+	locations := []version2.Location{
+		{
+			Path: "/",
+		},
+	}
+
+	expectedLocations := []version2.Location{
+		{
+			Path:  "/",
+			Allow: []string{"127.0.0.1"},
+			Deny:  []string{"127.0.0.2"},
+			PoliciesErrorReturn: &version2.Return{
+				Code: 400,
+			},
+		},
+	}
+
+	addPoliciesCfgToLocations(cfg, locations)
+	if !reflect.DeepEqual(locations, expectedLocations) {
+		t.Errorf("addPoliciesCfgToLocations() returned \n%+v but expected \n%+v", locations, expectedLocations)
+	}
+}
+
+func TestGenerateCORSOriginMap(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		allowOrigins     []string
+		allowCredentials bool
+		expected         []version2.Parameter
+		msg              string
+	}{
+		{
+			allowOrigins: []string{"https://example.com", "http://example.com:8080"},
+			expected: []version2.Parameter{
+				{Value: "default", Result: `""`},
+				{Value: `"https://example.com"`, Result: "$http_origin"},
+				{Value: `"http://example.com:8080"`, Result: "$http_origin"},
+			},
+			msg: "exact origins",
+		},
+		{
+			allowOrigins: []string{"https://*.example.com", `~*^https://(foo|bar)\.example\.org$`},
+			expected: []version2.Parameter{
+				{Value: "default", Result: `""`},
+				{Value: `"~^https://[^./]+\.example\.com$"`, Result: "$http_origin"},
+				{Value: `"~*^https://(foo|bar)\.example\.org$"`, Result: "$http_origin"},
+			},
+			msg: "wildcard and regex origins",
+		},
+		{
+			allowOrigins: []string{"*"},
+			expected: []version2.Parameter{
+				{Value: "default", Result: `"*"`},
+			},
+			msg: "any origin",
+		},
+		{
+			allowOrigins:     []string{"*"},
+			allowCredentials: true,
+			expected: []version2.Parameter{
+				{Value: "default", Result: "$http_origin"},
+			},
+			msg: "any origin with credentials",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateCORSOriginMap("$cors_origin", test.allowOrigins, test.allowCredentials)
+		if result.Source != "$http_origin" || result.Variable != "$cors_origin" {
+			t.Errorf("generateCORSOriginMap() returned map %v with unexpected source or variable for the case of %s", result, test.msg)
+		}
+		if diff := cmp.Diff(test.expected, result.Parameters); diff != "" {
+			t.Errorf("generateCORSOriginMap() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestGenerateExternalAuthSkipMap(t *testing.T) {
+	t.Parallel()
+
+	skipMap := generateExternalAuthSkipMap("$pol_ext_auth_skip", "$pol_ext_auth_uri", []string{"/public", "/static/"})
+	if skipMap.Source != "$pol_ext_auth_uri" {
+		t.Errorf("generateExternalAuthSkipMap() returned the source %q, want the normalized URI variable", skipMap.Source)
+	}
+
+	// normalize mimics NGINX, which decodes and merges the URI of the request before it is saved in the variable
+	normalize := func(requestURI string) string {
+		decoded, err := url.PathUnescape(requestURI)
+		if err != nil {
+			t.Fatal(err)
+		}
+		normalized := path.Clean(decoded)
+		if strings.HasSuffix(decoded, "/") && normalized != "/" {
+			normalized += "/"
+		}
+		return normalized
+	}
+	skips := func(uri string) bool {
+		for _, p := range skipMap.Parameters[1:] {
+			if regexp.MustCompile(strings.Trim(p.Value, `"~`)).MatchString(uri) {
+				return true
+			}
+		}
+		return false
+	}
+
+	tests := []struct {
+		requestURI string
+		expected   bool
+	}{
+		{requestURI: "/public", expected: true},
+		{requestURI: "/public/", expected: true},
+		{requestURI: "/public/docs", expected: true},
+		{requestURI: "/static/app.js", expected: true},
+		{requestURI: "/publicity", expected: false},
+		{requestURI: "/public-admin", expected: false},
+		{requestURI: "/static", expected: false},
+		{requestURI: "/public/../admin", expected: false},
+		{requestURI: "/public/%2e%2e/admin", expected: false},
+		{requestURI: "/public/%2E%2E/admin", expected: false},
+		{requestURI: "/%70ublic/docs", expected: true},
+		{requestURI: "/admin/../public/docs", expected: true},
+	}
+
+	for _, test := range tests {
+		if got := skips(normalize(test.requestURI)); got != test.expected {
+			t.Errorf("the skip map returned %v for the request URI %q, want %v", got, test.requestURI, test.expected)
+		}
+	}
+}
+
+func TestGenerateVirtualServerConfigForExternalAuthService(t *testing.T) {
+	t.Parallel()
+
+	vsEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Policies: []conf_v1.PolicyReference{
+					{Name: "ext-auth", Namespace: "auth"},
+				},
+				Upstreams: []conf_v1.Upstream{
+					{Name: "tea", Service: "tea-svc", Port: 80},
+				},
+				Routes: []conf_v1.Route{
+					{Path: "/tea", Action: &conf_v1.Action{Pass: "tea"}},
+				},
+			},
+		},
+		Policies: map[string]*conf_v1.Policy{
+			"auth/ext-auth": {
+				ObjectMeta: meta_v1.ObjectMeta{Name: "ext-auth", Namespace: "auth"},
+				Spec: conf_v1.PolicySpec{
+					ExternalAuth: &conf_v1.ExternalAuth{
+						Service: &conf_v1.ExternalAuthService{Name: "authz", Port: 8080, Path: "/check"},
+					},
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"default/tea-svc:80": {"10.0.0.20:80"},
+			"auth/authz:8080":    {"10.0.0.30:8080", "10.0.0.31:8080"},
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&baseCfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+	result, warnings := vsc.GenerateVirtualServerConfig(&vsEx, nil, nil)
+	if len(warnings) != 0 {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings: %v", warnings)
+	}
+
+	var authUpstream *version2.Upstream
+	for i := range result.Upstreams {
+		if result.Upstreams[i].Name == "pol_ext_auth_auth_ext_auth_default_cafe" {
+			authUpstream = &result.Upstreams[i]
+		}
+	}
+	if authUpstream == nil {
+		t.Fatalf("GenerateVirtualServerConfig() didn't generate the upstream of the external auth Service: %+v", result.Upstreams)
+	}
+	expectedServers := []version2.UpstreamServer{{Address: "10.0.0.30:8080"}, {Address: "10.0.0.31:8080"}}
+	if diff := cmp.Diff(expectedServers, authUpstream.Servers); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() generated unexpected servers of the external auth upstream (-want +got):\n%s", diff)
+	}
+	if len(result.Server.ExternalAuthLocations) != 1 ||
+		result.Server.ExternalAuthLocations[0].ProxyPass != "http://pol_ext_auth_auth_ext_auth_default_cafe/check" {
+		t.Errorf("GenerateVirtualServerConfig() generated unexpected external auth locations: %+v", result.Server.ExternalAuthLocations)
+	}
+}
+
+func TestExternalAuthConflictsWithAPIKey(t *testing.T) {
 	t.Parallel()
+	externalAuth := &version2.ExternalAuth{Path: "/_pol_ext_auth_default_ext_auth_policy_default_cafe"}
+	apiKey := &version2.APIKey{Header: []string{"X-API-Key"}}
+
 	tests := []struct {
-		rlz      []version2.LimitReqZone
-		expected []version2.LimitReqZone
+		routeCfg policiesCfg
+		specCfg  policiesCfg
+		expected bool
+		msg      string
 	}{
 		{
-			rlz: []version2.LimitReqZone{
-				{ZoneName: "test"},
-				{ZoneName: "test"},
-				{ZoneName: "test2"},
-				{ZoneName: "test3"},
-			},
-			expected: []version2.LimitReqZone{
-				{ZoneName: "test"},
-				{ZoneName: "test2"},
-				{ZoneName: "test3"},
-			},
+			routeCfg: policiesCfg{ExternalAuth: externalAuth},
+			expected: false,
+			msg:      "external auth only",
 		},
 		{
-			rlz: []version2.LimitReqZone{
-				{ZoneName: "test"},
-				{ZoneName: "test"},
-				{ZoneName: "test2"},
-				{ZoneName: "test3"},
-				{ZoneName: "test3"},
-			},
-			expected: []version2.LimitReqZone{
-				{ZoneName: "test"},
-				{ZoneName: "test2"},
-				{ZoneName: "test3"},
-			},
+			routeCfg: policiesCfg{ExternalAuth: externalAuth, APIKey: apiKey},
+			expected: true,
+			msg:      "external auth and api key in the route",
+		},
+		{
+			routeCfg: policiesCfg{ExternalAuth: externalAuth},
+			specCfg:  policiesCfg{APIKey: apiKey},
+			expected: true,
+			msg:      "external auth in the route and api key in the spec",
+		},
+		{
+			routeCfg: policiesCfg{APIKey: apiKey},
+			expected: false,
+			msg:      "api key only",
 		},
 	}
+
 	for _, test := range tests {
-		result := removeDuplicateLimitReqZones(test.rlz)
-		if !reflect.DeepEqual(result, test.expected) {
-			t.Errorf("removeDuplicateLimitReqZones() returned \n%v, but expected \n%v", result, test.expected)
+		result := externalAuthConflictsWithAPIKey(test.routeCfg, test.specCfg)
+		if result != test.expected {
+			t.Errorf("externalAuthConflictsWithAPIKey() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
 		}
 	}
 }
 
-func TestAddPoliciesCfgToLocations(t *testing.T) {
+func TestAddPoliciesCfgToLocationsWithRetry(t *testing.T) {
 	t.Parallel()
 	cfg := policiesCfg{
-		Allow: []string{"127.0.0.1"},
-		Deny:  []string{"127.0.0.2"},
-		ErrorReturn: &version2.Return{
-			Code: 400,
+		Retry: &retryCfg{
+			NextUpstream:        "error timeout http_503",
+			NextUpstreamTries:   3,
+			NextUpstreamTimeout: "1m",
+			PerTryTimeout:       "5s",
 		},
 	}
 
 	locations := []version2.Location{
 		{
-			Path: "/",
+			Path:                     "/",
+			ProxyConnectTimeout:      "60s",
+			ProxyReadTimeout:         "60s",
+			ProxySendTimeout:         "60s",
+			ProxyNextUpstream:        "error timeout",
+			ProxyNextUpstreamTimeout: "0s",
 		},
 	}
 
 	expectedLocations := []version2.Location{
 		{
-			Path:  "/",
-			Allow: []string{"127.0.0.1"},
-			Deny:  []string{"127.0.0.2"},
-			PoliciesErrorReturn: &version2.Return{
-				Code: 400,
-			},
+			Path:                     "/",
+			ProxyConnectTimeout:      "5s",
+			ProxyReadTimeout:         "5s",
+			ProxySendTimeout:         "5s",
+			ProxyNextUpstream:        "error timeout http_503",
+			ProxyNextUpstreamTimeout: "1m",
+			ProxyNextUpstreamTries:   3,
 		},
 	}
 
 	addPoliciesCfgToLocations(cfg, locations)
-	if !reflect.DeepEqual(locations, expectedLocations) {
-		t.Errorf("addPoliciesCfgToLocations() returned \n%+v but expected \n%+v", locations, expectedLocations)
+	if diff := cmp.Diff(expectedLocations, locations); diff != "" {
+		t.Errorf("addPoliciesCfgToLocations() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestAddRetrySamplingToRoutingCfg(t *testing.T) {
+	t.Parallel()
+	virtualServer := conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	variableNamer := NewVSVariableNamer(&virtualServer)
+
+	cfg := routingCfg{
+		SplitClients: []version2.SplitClient{
+			{
+				Source:   "$request_id",
+				Variable: "$vs_default_cafe_splits_1",
+				Distributions: []version2.Distribution{
+					{Weight: "90%", Value: "/internal_location_splits_1_split_0"},
+					{Weight: "10%", Value: "/internal_location_splits_1_split_1"},
+				},
+			},
+		},
+		Locations: []version2.Location{
+			{
+				Path:              "/internal_location_splits_1_split_0",
+				Internal:          true,
+				ProxyPass:         "http://vs_default_cafe_tea-v1$request_uri",
+				ProxyNextUpstream: "error timeout",
+			},
+			{
+				Path:      "/coffee",
+				ProxyPass: "http://vs_default_cafe_coffee",
+			},
+		},
+	}
+	retry := &retryCfg{
+		NextUpstream: "error timeout",
+		Sampling:     20,
+	}
+
+	expectedLocations := []version2.Location{
+		{
+			Path:              "/internal_location_splits_2_split_0",
+			Internal:          true,
+			ProxyPass:         "http://vs_default_cafe_tea-v1$request_uri",
+			ProxyNextUpstream: "error timeout",
+		},
+		{
+			Path:              "/internal_location_splits_2_split_1",
+			Internal:          true,
+			ProxyPass:         "http://vs_default_cafe_tea-v1$request_uri",
+			ProxyNextUpstream: "off",
+		},
+		{
+			Path:      "/coffee",
+			ProxyPass: "http://vs_default_cafe_coffee",
+		},
+	}
+	expectedSplitClients := []version2.SplitClient{
+		cfg.SplitClients[0],
+		{
+			Source:   `"${request_id}_retry"`,
+			Variable: "$vs_default_cafe_splits_2",
+			Distributions: []version2.Distribution{
+				{Weight: "20%", Value: "/internal_location_splits_2_split_0"},
+				{Weight: "*", Value: "/internal_location_splits_2_split_1"},
+			},
+		},
+	}
+	expectedRedirects := []version2.InternalRedirectLocation{
+		{
+			Path:        "/internal_location_splits_1_split_0",
+			Destination: "$vs_default_cafe_splits_2",
+			Internal:    true,
+		},
+	}
+
+	redirects := addRetrySamplingToRoutingCfg(&cfg, retry, variableNamer, 1)
+	if diff := cmp.Diff(expectedRedirects, redirects); diff != "" {
+		t.Errorf("addRetrySamplingToRoutingCfg() returned unexpected redirects (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedLocations, cfg.Locations); diff != "" {
+		t.Errorf("addRetrySamplingToRoutingCfg() generated unexpected locations (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedSplitClients, cfg.SplitClients); diff != "" {
+		t.Errorf("addRetrySamplingToRoutingCfg() generated unexpected split clients (-want +got):\n%s", diff)
+	}
+}
+
+func TestAddRetrySamplingToRoutingCfgWithoutSampling(t *testing.T) {
+	t.Parallel()
+	locations := []version2.Location{
+		{
+			Path:      "/internal_location_splits_0_split_0",
+			Internal:  true,
+			ProxyPass: "http://vs_default_cafe_tea-v1$request_uri",
+		},
+	}
+	cfg := routingCfg{Locations: locations}
+
+	for _, retry := range []*retryCfg{nil, {Sampling: 100}, {Sampling: 0, NextUpstream: "off"}} {
+		redirects := addRetrySamplingToRoutingCfg(&cfg, retry, &VariableNamer{safeNsName: "default_cafe"}, 0)
+		if redirects != nil {
+			t.Errorf("addRetrySamplingToRoutingCfg() returned %v but expected no redirects for %v", redirects, retry)
+		}
+		if diff := cmp.Diff(locations, cfg.Locations); diff != "" {
+			t.Errorf("addRetrySamplingToRoutingCfg() changed the locations for %v (-want +got):\n%s", retry, diff)
+		}
 	}
 }
 
@@ -8975,7 +9943,7 @@ func TestGenerateUpstream(t *testing.T) {
 	}
 
 	vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
-	result := vsc.generateUpstream(nil, name, upstream, false, endpoints, backupEndpoints)
+	result := vsc.generateUpstream(nil, name, upstream, false, endpoints, backupEndpoints, nil)
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("generateUpstream() returned %v but expected %v", result, expected)
 	}
@@ -9054,92 +10022,323 @@ func TestGenerateUpstreamWithKeepalive(t *testing.T) {
 
 	for _, test := range tests {
 		vsc := newVirtualServerConfigurator(test.cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
-		result := vsc.generateUpstream(nil, name, test.upstream, false, endpoints, nil)
+		result := vsc.generateUpstream(nil, name, test.upstream, false, endpoints, nil, nil)
 		if !reflect.DeepEqual(result, test.expected) {
 			t.Errorf("generateUpstream() returned %v but expected %v for the case of %v", result, test.expected, test.msg)
 		}
-
-		if len(vsc.warnings) != 0 {
-			t.Errorf("generateUpstream() returned warnings for %v", test.upstream)
+
+		if len(vsc.warnings) != 0 {
+			t.Errorf("generateUpstream() returned warnings for %v", test.upstream)
+		}
+	}
+}
+
+func TestGenerateUpstreamForExternalNameService(t *testing.T) {
+	t.Parallel()
+	name := "test-upstream"
+	endpoints := []string{"example.com"}
+	upstream := conf_v1.Upstream{Service: name}
+	cfgParams := ConfigParams{Context: context.Background()}
+
+	expected := version2.Upstream{
+		Name: name,
+		UpstreamLabels: version2.UpstreamLabels{
+			Service: "test-upstream",
+		},
+		Servers: []version2.UpstreamServer{
+			{
+				Address: "example.com",
+			},
+		},
+		Resolve: true,
+	}
+
+	vsc := newVirtualServerConfigurator(&cfgParams, true, true, &StaticConfigParams{}, false, &fakeBV)
+	result := vsc.generateUpstream(nil, name, upstream, true, endpoints, nil, nil)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("generateUpstream() returned %v but expected %v", result, expected)
+	}
+
+	if len(vsc.warnings) != 0 {
+		t.Errorf("generateUpstream() returned warnings for %v", upstream)
+	}
+}
+
+func TestGenerateUpstreamWithNTLM(t *testing.T) {
+	t.Parallel()
+	name := "test-upstream"
+	upstream := conf_v1.Upstream{Service: name, Port: 80, NTLM: true}
+	endpoints := []string{
+		"192.168.10.10:8080",
+	}
+	cfgParams := ConfigParams{
+		Context:          context.Background(),
+		LBMethod:         "random",
+		MaxFails:         1,
+		MaxConns:         0,
+		FailTimeout:      "10s",
+		Keepalive:        21,
+		UpstreamZoneSize: "256k",
+	}
+
+	expected := version2.Upstream{
+		Name: "test-upstream",
+		UpstreamLabels: version2.UpstreamLabels{
+			Service: "test-upstream",
+		},
+		Servers: []version2.UpstreamServer{
+			{
+				Address: "192.168.10.10:8080",
+			},
+		},
+		MaxFails:         1,
+		MaxConns:         0,
+		FailTimeout:      "10s",
+		LBMethod:         "random",
+		Keepalive:        21,
+		UpstreamZoneSize: "256k",
+		NTLM:             true,
+	}
+
+	vsc := newVirtualServerConfigurator(&cfgParams, true, false, &StaticConfigParams{}, false, &fakeBV)
+	result := vsc.generateUpstream(nil, name, upstream, false, endpoints, nil, nil)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("generateUpstream() returned %v but expected %v", result, expected)
+	}
+
+	if len(vsc.warnings) != 0 {
+		t.Errorf("generateUpstream returned warnings for %v", upstream)
+	}
+}
+
+func TestGenerateUpstreamWithInvalidUpstreamTLSSecret(t *testing.T) {
+	t.Parallel()
+	owner := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	upstream := conf_v1.Upstream{
+		Name:    "tea",
+		Service: "tea-svc",
+		Port:    443,
+		TLS: conf_v1.UpstreamTLS{
+			Enable:            true,
+			TrustedCertSecret: "tea-ca-secret",
+			VerifyServer:      true,
+		},
+	}
+	secretRefs := map[string]*secrets.SecretReference{
+		"default/tea-ca-secret": {
+			Secret: &api_v1.Secret{
+				Type: api_v1.SecretTypeTLS,
+			},
+			Path: "/etc/nginx/secrets/default-tea-ca-secret",
+		},
+	}
+	cfgParams := ConfigParams{Context: context.Background()}
+
+	vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+	result := vsc.generateUpstream(owner, "vs_default_cafe_tea", upstream, false, []string{"10.0.0.20:443"}, []string{"10.0.0.30:443"}, secretRefs)
+
+	expectedServers := []version2.UpstreamServer{{Address: nginx502Server}}
+	if !cmp.Equal(expectedServers, result.Servers) {
+		t.Errorf("generateUpstream() returned unexpected servers (-want +got):\n%s", cmp.Diff(expectedServers, result.Servers))
+	}
+	if result.BackupServers != nil || result.TLS != nil {
+		t.Errorf("generateUpstream() returned backup servers %v and TLS %v but expected none", result.BackupServers, result.TLS)
+	}
+
+	expectedWarnings := Warnings{
+		owner: {
+			"Requests to upstream tea will fail: the TLS configuration references a secret default/tea-ca-secret of a wrong type 'kubernetes.io/tls', must be 'nginx.org/ca'",
+		},
+	}
+	if !cmp.Equal(expectedWarnings, vsc.warnings) {
+		t.Errorf("generateUpstream() returned unexpected warnings (-want +got):\n%s", cmp.Diff(expectedWarnings, vsc.warnings))
+	}
+}
+
+func TestGenerateUpstreamTLS(t *testing.T) {
+	t.Parallel()
+	secretRefs := map[string]*secrets.SecretReference{
+		"default/ca-secret": {
+			Secret: &api_v1.Secret{
+				Type: secrets.SecretTypeCA,
+			},
+			Path: "/etc/nginx/secrets/default-ca-secret-ca.crt /etc/nginx/secrets/default-ca-secret-ca.crl",
+		},
+	}
+	tests := []struct {
+		tls      conf_v1.UpstreamTLS
+		expected *version2.UpstreamTLS
+		msg      string
+	}{
+		{
+			tls:      conf_v1.UpstreamTLS{},
+			expected: nil,
+			msg:      "tls disabled",
+		},
+		{
+			tls: conf_v1.UpstreamTLS{
+				Enable: true,
+			},
+			expected: nil,
+			msg:      "tls enabled without settings",
+		},
+		{
+			tls: conf_v1.UpstreamTLS{
+				Enable:     true,
+				ServerName: true,
+			},
+			expected: &version2.UpstreamTLS{
+				VerifyDepth:  1,
+				SessionReuse: true,
+				ServerName:   true,
+				SSLName:      "$proxy_host",
+			},
+			msg: "defaults",
+		},
+		{
+			tls: conf_v1.UpstreamTLS{
+				Enable:            true,
+				TrustedCertSecret: "ca-secret",
+				VerifyServer:      true,
+				VerifyDepth:       createPointerFromInt(2),
+				Protocols:         "TLSv1.2 TLSv1.3",
+				Ciphers:           "HIGH:!aNULL:!MD5",
+				SessionReuse:      createPointerFromBool(false),
+				ServerName:        true,
+				SSLName:           "backend.example.com",
+			},
+			expected: &version2.UpstreamTLS{
+				VerifyServer: true,
+				VerifyDepth:  2,
+				Ciphers:      "HIGH:!aNULL:!MD5",
+				Protocols:    "TLSv1.2 TLSv1.3",
+				TrustedCert:  "/etc/nginx/secrets/default-ca-secret-ca.crt",
+				SessionReuse: false,
+				ServerName:   true,
+				SSLName:      "backend.example.com",
+			},
+			msg: "all settings",
+		},
+	}
+
+	for _, test := range tests {
+		result, err := generateUpstreamTLS(test.tls, "default", secretRefs)
+		if err != nil {
+			t.Errorf("generateUpstreamTLS() returned unexpected error %v for the case of %s", err, test.msg)
+		}
+		if !cmp.Equal(test.expected, result) {
+			t.Errorf("generateUpstreamTLS() mismatch for the case of %s (-want +got):\n%s", test.msg, cmp.Diff(test.expected, result))
 		}
 	}
 }
 
-func TestGenerateUpstreamForExternalNameService(t *testing.T) {
+func TestGenerateUpstreamTLSFails(t *testing.T) {
 	t.Parallel()
-	name := "test-upstream"
-	endpoints := []string{"example.com"}
-	upstream := conf_v1.Upstream{Service: name}
-	cfgParams := ConfigParams{Context: context.Background()}
-
-	expected := version2.Upstream{
-		Name: name,
-		UpstreamLabels: version2.UpstreamLabels{
-			Service: "test-upstream",
+	secretRefs := map[string]*secrets.SecretReference{
+		"default/tls-secret": {
+			Secret: &api_v1.Secret{
+				Type: api_v1.SecretTypeTLS,
+			},
+			Path: "/etc/nginx/secrets/default-tls-secret",
 		},
-		Servers: []version2.UpstreamServer{
-			{
-				Address: "example.com",
+		"default/invalid-ca-secret": {
+			Secret: &api_v1.Secret{
+				Type: secrets.SecretTypeCA,
 			},
+			Error: errors.New("secret is invalid"),
 		},
-		Resolve: true,
 	}
-
-	vsc := newVirtualServerConfigurator(&cfgParams, true, true, &StaticConfigParams{}, false, &fakeBV)
-	result := vsc.generateUpstream(nil, name, upstream, true, endpoints, nil)
-	if !reflect.DeepEqual(result, expected) {
-		t.Errorf("generateUpstream() returned %v but expected %v", result, expected)
+	tests := []struct {
+		secret string
+		msg    string
+	}{
+		{
+			secret: "missing-secret",
+			msg:    "missing secret",
+		},
+		{
+			secret: "tls-secret",
+			msg:    "wrong secret type",
+		},
+		{
+			secret: "invalid-ca-secret",
+			msg:    "invalid secret",
+		},
 	}
 
-	if len(vsc.warnings) != 0 {
-		t.Errorf("generateUpstream() returned warnings for %v", upstream)
+	for _, test := range tests {
+		tls := conf_v1.UpstreamTLS{
+			Enable:            true,
+			TrustedCertSecret: test.secret,
+			VerifyServer:      true,
+		}
+		result, err := generateUpstreamTLS(tls, "default", secretRefs)
+		if err == nil || result != nil {
+			t.Errorf("generateUpstreamTLS() returned %v and no error for the case of %s", result, test.msg)
+		}
 	}
 }
 
-func TestGenerateUpstreamWithNTLM(t *testing.T) {
+func TestAddUpstreamTLS(t *testing.T) {
 	t.Parallel()
-	name := "test-upstream"
-	upstream := conf_v1.Upstream{Service: name, Port: 80, NTLM: true}
-	endpoints := []string{
-		"192.168.10.10:8080",
+	owner := &conf_v1.VirtualServer{}
+	upstream := conf_v1.Upstream{
+		Name: "tea",
+		TLS: conf_v1.UpstreamTLS{
+			Enable:     true,
+			ServerName: true,
+		},
 	}
-	cfgParams := ConfigParams{
-		Context:          context.Background(),
-		LBMethod:         "random",
-		MaxFails:         1,
-		MaxConns:         0,
-		FailTimeout:      "10s",
-		Keepalive:        21,
-		UpstreamZoneSize: "256k",
+	tls := &version2.UpstreamTLS{
+		VerifyDepth:  1,
+		SessionReuse: true,
+		ServerName:   true,
+		SSLName:      "$proxy_host",
 	}
-
-	expected := version2.Upstream{
-		Name: "test-upstream",
-		UpstreamLabels: version2.UpstreamLabels{
-			Service: "test-upstream",
-		},
-		Servers: []version2.UpstreamServer{
-			{
-				Address: "192.168.10.10:8080",
+	tests := []struct {
+		spiffeCerts      bool
+		hasEgressMTLS    bool
+		expected         map[string]*version2.UpstreamTLS
+		expectedWarnings int
+		msg              string
+	}{
+		{
+			expected: map[string]*version2.UpstreamTLS{
+				"vs_default_cafe_tea": tls,
 			},
+			msg: "upstream tls is added",
+		},
+		{
+			hasEgressMTLS:    true,
+			expected:         map[string]*version2.UpstreamTLS{},
+			expectedWarnings: 1,
+			msg:              "egress mtls policy takes precedence",
+		},
+		{
+			spiffeCerts:      true,
+			expected:         map[string]*version2.UpstreamTLS{},
+			expectedWarnings: 1,
+			msg:              "spiffe certs take precedence",
 		},
-		MaxFails:         1,
-		MaxConns:         0,
-		FailTimeout:      "10s",
-		LBMethod:         "random",
-		Keepalive:        21,
-		UpstreamZoneSize: "256k",
-		NTLM:             true,
 	}
 
-	vsc := newVirtualServerConfigurator(&cfgParams, true, false, &StaticConfigParams{}, false, &fakeBV)
-	result := vsc.generateUpstream(nil, name, upstream, false, endpoints, nil)
-	if !reflect.DeepEqual(result, expected) {
-		t.Errorf("generateUpstream() returned %v but expected %v", result, expected)
-	}
+	for _, test := range tests {
+		vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+		vsc.spiffeCerts = test.spiffeCerts
 
-	if len(vsc.warnings) != 0 {
-		t.Errorf("generateUpstream returned warnings for %v", upstream)
+		upstreamTLS := make(map[string]*version2.UpstreamTLS)
+		vsc.addUpstreamTLS(owner, upstreamTLS, "vs_default_cafe_tea", upstream, tls, test.hasEgressMTLS)
+		if !cmp.Equal(test.expected, upstreamTLS) {
+			t.Errorf("addUpstreamTLS() mismatch for the case of %s (-want +got):\n%s", test.msg, cmp.Diff(test.expected, upstreamTLS))
+		}
+		if len(vsc.warnings[owner]) != test.expectedWarnings {
+			t.Errorf("addUpstreamTLS() returned warnings %v for the case of %s", vsc.warnings[owner], test.msg)
+		}
 	}
 }
 
@@ -9185,6 +10384,24 @@ func TestGenerateProxyPass(t *testing.T) {
 	}
 }
 
+func TestGenerateProxyPassWithRewrites(t *testing.T) {
+	t.Parallel()
+	proxy := &conf_v1.ActionProxy{
+		Rewrites: []conf_v1.ProxyRewrite{
+			{
+				Match:   "^/tea/(.*)$",
+				Replace: "/green-tea/$1",
+			},
+		},
+	}
+	expected := "http://test-upstream"
+
+	result := generateProxyPass(false, "test-upstream", true, proxy)
+	if result != expected {
+		t.Errorf("generateProxyPass() returned %v but expected %v", result, expected)
+	}
+}
+
 func TestGenerateProxyPassProtocol(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -9458,6 +10675,139 @@ func TestGenerateLocationForGrpcProxying(t *testing.T) {
 	}
 }
 
+func TestGenerateMirror(t *testing.T) {
+	t.Parallel()
+	cfgParams := ConfigParams{
+		ProxyConnectTimeout: "30s",
+		ProxyReadTimeout:    "31s",
+		ProxySendTimeout:    "32s",
+	}
+	namer := &upstreamNamer{prefix: "vs_default_cafe", namespace: "default"}
+	crUpstreams := map[string]conf_v1.Upstream{
+		"vs_default_cafe_tea":    {Name: "tea", Service: "tea-svc"},
+		"vs_default_cafe_tea-v2": {Name: "tea-v2", Service: "tea-v2-svc", ProxyReadTimeout: "60s", TLS: conf_v1.UpstreamTLS{Enable: true}},
+	}
+	upstreamTLS := map[string]*version2.UpstreamTLS{
+		"vs_default_cafe_tea-v2": {VerifyServer: true, VerifyDepth: 1, SSLName: "tea-v2-svc.default.svc"},
+	}
+
+	tests := []struct {
+		mirror   *conf_v1.ActionMirror
+		expected *version2.MirrorLocation
+		msg      string
+	}{
+		{
+			mirror:   nil,
+			expected: nil,
+			msg:      "no mirror",
+		},
+		{
+			mirror: &conf_v1.ActionMirror{
+				Upstream: "tea-v2",
+			},
+			expected: &version2.MirrorLocation{
+				Path:                "/internal_location_mirror_vs_default_cafe_tea-v2_100",
+				ProxyPass:           "https://vs_default_cafe_tea-v2$request_uri",
+				ProxyConnectTimeout: "30s",
+				ProxyReadTimeout:    "60s",
+				ProxySendTimeout:    "32s",
+				RequestBody:         true,
+				Percentage:          100,
+				UpstreamTLS:         upstreamTLS["vs_default_cafe_tea-v2"],
+			},
+			msg: "mirror with defaults",
+		},
+		{
+			mirror: &conf_v1.ActionMirror{
+				Upstream:    "tea-v2",
+				Percentage:  createPointerFromInt(25),
+				RequestBody: createPointerFromBool(false),
+			},
+			expected: &version2.MirrorLocation{
+				Path:                "/internal_location_mirror_vs_default_cafe_tea-v2_25_no_body",
+				ProxyPass:           "https://vs_default_cafe_tea-v2$request_uri",
+				ProxyConnectTimeout: "30s",
+				ProxyReadTimeout:    "60s",
+				ProxySendTimeout:    "32s",
+				RequestBody:         false,
+				Percentage:          25,
+				Variable:            "$internal_location_mirror_vs_default_cafe_tea_v2_25_no_body",
+				UpstreamTLS:         upstreamTLS["vs_default_cafe_tea-v2"],
+			},
+			msg: "mirror with percentage and no request body",
+		},
+	}
+
+	for _, test := range tests {
+		warnings := make(Warnings)
+		result := generateMirror(test.mirror, crUpstreams["vs_default_cafe_tea"], namer, crUpstreams, upstreamTLS, &cfgParams, &conf_v1.VirtualServer{}, warnings)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateMirror() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+		if len(warnings) != 0 {
+			t.Errorf("generateMirror() returned unexpected warnings %v for the case of %s", warnings, test.msg)
+		}
+	}
+}
+
+func TestGenerateMirrorWarnsForGRPC(t *testing.T) {
+	t.Parallel()
+	namer := &upstreamNamer{prefix: "vs_default_cafe", namespace: "default"}
+	crUpstreams := map[string]conf_v1.Upstream{
+		"vs_default_cafe_grpc":    {Name: "grpc", Service: "grpc-svc", Type: "grpc"},
+		"vs_default_cafe_grpc-v2": {Name: "grpc-v2", Service: "grpc-v2-svc", Type: "grpc"},
+	}
+	owner := &conf_v1.VirtualServer{}
+	warnings := make(Warnings)
+
+	result := generateMirror(&conf_v1.ActionMirror{Upstream: "grpc-v2"}, crUpstreams["vs_default_cafe_grpc"], namer, crUpstreams, nil, &ConfigParams{}, owner, warnings)
+	if result != nil {
+		t.Errorf("generateMirror() returned %v but expected nil", result)
+	}
+	if len(warnings[owner]) != 1 {
+		t.Errorf("generateMirror() returned warnings %v but expected exactly one", warnings[owner])
+	}
+}
+
+func TestGenerateMirrorLocations(t *testing.T) {
+	t.Parallel()
+	sampled := &version2.MirrorLocation{
+		Path:       "/internal_location_mirror_vs_default_cafe_tea-v2_10",
+		Percentage: 10,
+		Variable:   "$internal_location_mirror_vs_default_cafe_tea_v2_10",
+	}
+	full := &version2.MirrorLocation{
+		Path:       "/internal_location_mirror_vs_default_cafe_coffee-v2_100",
+		Percentage: 100,
+	}
+	locations := []version2.Location{
+		{Path: "/tea", Mirror: sampled},
+		{Path: "/coffee", Mirror: full},
+		{Path: "/tea-latte", Mirror: sampled},
+		{Path: "/juice"},
+	}
+
+	expectedMirrorLocations := []version2.MirrorLocation{*sampled, *full}
+	expectedSplitClients := []version2.SplitClient{
+		{
+			Source:   "$request_id",
+			Variable: "$internal_location_mirror_vs_default_cafe_tea_v2_10",
+			Distributions: []version2.Distribution{
+				{Weight: "10%", Value: "1"},
+				{Weight: "*", Value: `""`},
+			},
+		},
+	}
+
+	mirrorLocations, splitClients := generateMirrorLocations(locations)
+	if diff := cmp.Diff(expectedMirrorLocations, mirrorLocations); diff != "" {
+		t.Errorf("generateMirrorLocations() mirror locations mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedSplitClients, splitClients); diff != "" {
+		t.Errorf("generateMirrorLocations() split clients mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestGenerateReturnBlock(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -9494,6 +10844,41 @@ func TestGenerateReturnBlock(t *testing.T) {
 	}
 }
 
+func TestGenerateLocationForGRPCReturn(t *testing.T) {
+	t.Parallel()
+	expectedLocation := version2.Location{
+		Path:     "/",
+		Snippets: []string{"# location snippet"},
+		ErrorPages: []version2.ErrorPage{
+			{
+				Name:         "@return_1",
+				Codes:        "418",
+				ResponseCode: 204,
+			},
+		},
+		ProxyInterceptErrors: true,
+		InternalProxyPass:    "http://unix:/var/lib/nginx/nginx-418-server.sock",
+	}
+	expectedReturnLocation := &version2.ReturnLocation{
+		Name:        "@return_1",
+		DefaultType: "application/grpc",
+		Headers: []version2.Header{
+			{Name: "content-type", Value: "application/grpc"},
+			{Name: "grpc-status", Value: "14"},
+			{Name: "grpc-message", Value: "maintenance"},
+		},
+	}
+
+	location, returnLocation := generateLocationForGRPCReturn("/", []string{"# location snippet"},
+		&conf_v1.ActionGRPCReturn{Status: 14, Message: "maintenance"}, 1)
+	if diff := cmp.Diff(expectedLocation, location); diff != "" {
+		t.Errorf("generateLocationForGRPCReturn() location mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedReturnLocation, returnLocation); diff != "" {
+		t.Errorf("generateLocationForGRPCReturn() return location mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestGenerateLocationForReturn(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -10338,6 +11723,7 @@ func TestGenerateSplits(t *testing.T) {
 				test.splits,
 				upstreamNamer,
 				crUpstreams,
+				nil,
 				variableNamer,
 				scIndex,
 				&cfgParams,
@@ -12009,6 +13395,7 @@ func TestGenerateSplitsWeightChangesDynamicReload(t *testing.T) {
 				test.splits,
 				upstreamNamer,
 				crUpstreams,
+				nil,
 				variableNamer,
 				scIndex,
 				&cfgParams,
@@ -12050,6 +13437,32 @@ func TestGenerateSplitsWeightChangesDynamicReload(t *testing.T) {
 	}
 }
 
+func TestApplyCanaryWeight(t *testing.T) {
+	t.Parallel()
+
+	route := conf_v1.Route{
+		Path: "/tea",
+		Splits: []conf_v1.Split{
+			{Weight: 90, Action: &conf_v1.Action{Pass: "tea-v1"}},
+			{Weight: 10, Action: &conf_v1.Action{Pass: "tea-v2"}},
+		},
+		Canary: &conf_v1.Canary{Interval: "1m", StepWeight: 10},
+	}
+
+	result := applyCanaryWeight(route, map[string]int{"/tea": 30})
+	if result.Splits[0].Weight != 70 || result.Splits[1].Weight != 30 {
+		t.Errorf("applyCanaryWeight() set the weights %d and %d, want 70 and 30", result.Splits[0].Weight, result.Splits[1].Weight)
+	}
+	if route.Splits[0].Weight != 90 || route.Splits[1].Weight != 10 {
+		t.Errorf("applyCanaryWeight() changed the weights of the original route")
+	}
+
+	result = applyCanaryWeight(route, map[string]int{"/coffee": 30})
+	if result.Splits[1].Weight != 10 {
+		t.Errorf("applyCanaryWeight() set the weight %d for a route without a canary weight, want 10", result.Splits[1].Weight)
+	}
+}
+
 func TestGenerateDefaultSplitsConfig(t *testing.T) {
 	t.Parallel()
 	route := conf_v1.Route{
@@ -12153,7 +13566,7 @@ func TestGenerateDefaultSplitsConfig(t *testing.T) {
 		owner: nil,
 	}
 
-	result := generateDefaultSplitsConfig(route, upstreamNamer, crUpstreams, variableNamer, index, &cfgParams,
+	result := generateDefaultSplitsConfig(route, upstreamNamer, crUpstreams, nil, variableNamer, index, &cfgParams,
 		errorPageDetails, "", locSnippet, enableSnippets, 0, true, "coffee", "default", Warnings{}, weightChangesDynamicReload)
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("generateDefaultSplitsConfig() returned \n%+v but expected \n%+v", result, expected)
@@ -12556,6 +13969,7 @@ func TestGenerateMatchesConfig(t *testing.T) {
 		route,
 		upstreamNamer,
 		crUpstreams,
+		nil,
 		variableNamer,
 		index,
 		scIndex,
@@ -12968,6 +14382,7 @@ func TestGenerateMatchesConfigWithMultipleSplits(t *testing.T) {
 		route,
 		upstreamNamer,
 		crUpstreams,
+		nil,
 		variableNamer,
 		index,
 		scIndex,
@@ -13127,6 +14542,12 @@ func TestGetNameForSourceForMatchesRouteMapFromCondition(t *testing.T) {
 			},
 			expected: "$request_method",
 		},
+		{
+			input: conf_v1.Condition{
+				GRPC: "service",
+			},
+			expected: "$uri",
+		},
 	}
 
 	for _, test := range tests {
@@ -13137,6 +14558,43 @@ func TestGetNameForSourceForMatchesRouteMapFromCondition(t *testing.T) {
 	}
 }
 
+func TestGenerateGRPCConditionValue(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input    conf_v1.Condition
+		expected string
+	}{
+		{
+			input: conf_v1.Condition{
+				GRPC:  "service",
+				Value: "helloworld.Greeter",
+			},
+			expected: `~^/helloworld\.Greeter/[^/]+$`,
+		},
+		{
+			input: conf_v1.Condition{
+				GRPC:  "service",
+				Value: "!helloworld.Greeter",
+			},
+			expected: `!~^/helloworld\.Greeter/[^/]+$`,
+		},
+		{
+			input: conf_v1.Condition{
+				GRPC:  "method",
+				Value: "SayHello",
+			},
+			expected: `~^/[^/]+/SayHello$`,
+		},
+	}
+
+	for _, test := range tests {
+		result := generateGRPCConditionValue(test.input)
+		if result != test.expected {
+			t.Errorf("generateGRPCConditionValue() returned %q but expected %q for input %v", result, test.expected, test.input)
+		}
+	}
+}
+
 func TestGenerateLBMethod(t *testing.T) {
 	t.Parallel()
 	defaultMethod := "random two least_conn"
@@ -13881,7 +15339,7 @@ func TestGenerateUpstreamWithQueue(t *testing.T) {
 
 	for _, test := range tests {
 		vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, test.isPlus, false, &StaticConfigParams{}, false, &fakeBV)
-		result := vsc.generateUpstream(nil, test.name, test.upstream, false, []string{}, []string{})
+		result := vsc.generateUpstream(nil, test.name, test.upstream, false, []string{}, []string{}, nil)
 		if !reflect.DeepEqual(result, test.expected) {
 			t.Errorf("generateUpstream() returned %v but expected %v for the case of %v", result, test.expected, test.msg)
 		}
@@ -14108,6 +15566,68 @@ func TestGenerateErrorPages(t *testing.T) {
 	}
 }
 
+func TestGenerateGRPCErrorPages(t *testing.T) {
+	t.Parallel()
+	errorPages := []conf_v1.ErrorPage{
+		{
+			Codes: []int{502, 418},
+			Return: &conf_v1.ErrorPageReturn{
+				ActionReturn: conf_v1.ActionReturn{
+					Body: `Upstream ${upstream_status}\n`,
+					Headers: []conf_v1.Header{
+						{Name: "x-upstream-status", Value: "${upstream_status}"},
+					},
+				},
+			},
+		},
+		{
+			Codes: []int{404},
+			Redirect: &conf_v1.ErrorPageRedirect{
+				ActionRedirect: conf_v1.ActionRedirect{
+					URL: "http://nginx.com",
+				},
+			},
+		},
+	}
+
+	expectedErrorPages := []version2.ErrorPage{
+		{Name: "@grpc_error_page_2_0_502", Codes: "502", ResponseCode: 204},
+		{Name: "@grpc_error_page_2_0_418", Codes: "418", ResponseCode: 204},
+	}
+	expectedLocations := []version2.ErrorPageLocation{
+		{
+			Name:        "@grpc_error_page_2_0_502",
+			DefaultType: "application/grpc",
+			Return:      &version2.Return{},
+			Headers: []version2.Header{
+				{Name: "x-upstream-status", Value: "${upstream_status}"},
+				{Name: "content-type", Value: "application/grpc"},
+				{Name: "grpc-status", Value: "14"},
+				{Name: "grpc-message", Value: "Upstream ${upstream_status} "},
+			},
+		},
+		{
+			Name:        "@grpc_error_page_2_0_418",
+			DefaultType: "application/grpc",
+			Return:      &version2.Return{},
+			Headers: []version2.Header{
+				{Name: "x-upstream-status", Value: "${upstream_status}"},
+				{Name: "content-type", Value: "application/grpc"},
+				{Name: "grpc-status", Value: "2"},
+				{Name: "grpc-message", Value: "Upstream ${upstream_status} "},
+			},
+		},
+	}
+
+	ePages, locations := generateGRPCErrorPages(2, errorPages)
+	if diff := cmp.Diff(expectedErrorPages, ePages); diff != "" {
+		t.Errorf("generateGRPCErrorPages() error pages mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedLocations, locations); diff != "" {
+		t.Errorf("generateGRPCErrorPages() locations mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestGenerateErrorPageLocations(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -14331,6 +15851,52 @@ func TestGenerateRewrites(t *testing.T) {
 			expected:     []string{`^ $request_uri break`},
 			msg:          "empty rewrite for internal location with grpc enabled",
 		},
+		{
+			path: "/tea",
+			proxy: &conf_v1.ActionProxy{
+				Rewrites: []conf_v1.ProxyRewrite{
+					{
+						Match:   "^/tea/(.*)/green$",
+						Replace: "/green-tea/$1",
+					},
+					{
+						Match:   "^/tea/(v[0-9]+)/(.*)$",
+						Replace: "/$2",
+						Query:   createPointerFromString("version=$1"),
+					},
+					{
+						Match:   "^/tea/legacy$",
+						Replace: "/tea",
+						Query:   createPointerFromString(""),
+					},
+				},
+			},
+			expected: []string{
+				`"^/tea/(.*)/green$" "/green-tea/$1" break`,
+				`"^/tea/(v[0-9]+)/(.*)$" "/$2?version=$1" break`,
+				`"^/tea/legacy$" "/tea?" break`,
+			},
+			msg: "regex rewrites for non-internal location",
+		},
+		{
+			path:     "/_internal_path",
+			internal: true,
+			proxy: &conf_v1.ActionProxy{
+				Rewrites: []conf_v1.ProxyRewrite{
+					{
+						Match:   "^/tea/(.*)$",
+						Replace: "/green-tea/$1",
+					},
+				},
+			},
+			originalPath: "/tea",
+			expected: []string{
+				`^ $request_uri_no_args`,
+				`"^/tea/(.*)$" "/green-tea/$1" break`,
+				`^ $request_uri_no_args break`,
+			},
+			msg: "regex rewrites for internal location",
+		},
 	}
 
 	for _, test := range tests {
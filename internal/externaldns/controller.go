@@ -7,15 +7,16 @@ import (
 	"time"
 
 	nl "github.com/nginx/kubernetes-ingress/internal/logger"
-	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
 	extdns_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/externaldns/v1"
 	k8s_nginx "github.com/nginx/kubernetes-ingress/pkg/client/clientset/versioned"
 	listersV1 "github.com/nginx/kubernetes-ingress/pkg/client/listers/configuration/v1"
 	extdnslisters "github.com/nginx/kubernetes-ingress/pkg/client/listers/externaldns/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	networkinglisters "k8s.io/client-go/listers/networking/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -28,24 +29,38 @@ const (
 	ControllerName = "externaldns"
 )
 
+// QueueKey identifies a resource that can own a DNSEndpoint in the workqueue. The kind is a part of the key,
+// so that the resources of different kinds with the same name are synced and retried independently.
+type QueueKey struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
 // ExtDNSController represents ExternalDNS controller.
 type ExtDNSController struct {
 	sync          SyncFn
+	tsSync        TransportServerSyncFn
+	ingressSync   IngressSyncFn
 	ctx           context.Context
-	queue         workqueue.TypedRateLimitingInterface[types.NamespacedName]
+	queue         workqueue.TypedRateLimitingInterface[QueueKey]
 	recorder      record.EventRecorder
 	client        k8s_nginx.Interface
+	kubeClient    kubernetes.Interface
 	informerGroup map[string]*namespacedInformer
 	resync        time.Duration
 }
 
 type namespacedInformer struct {
-	vsLister              listersV1.VirtualServerLister
-	sharedInformerFactory k8s_nginx_informers.SharedInformerFactory
-	extdnslister          extdnslisters.DNSEndpointLister
-	mustSync              []cache.InformerSynced
-	stopCh                chan struct{}
-	lock                  sync.RWMutex
+	vsLister                  listersV1.VirtualServerLister
+	tsLister                  listersV1.TransportServerLister
+	ingressLister             networkinglisters.IngressLister
+	sharedInformerFactory     k8s_nginx_informers.SharedInformerFactory
+	kubeSharedInformerFactory kubeinformers.SharedInformerFactory
+	extdnslister              extdnslisters.DNSEndpointLister
+	mustSync                  []cache.InformerSynced
+	stopCh                    chan struct{}
+	lock                      sync.RWMutex
 }
 
 // ExtDNSOpts represents config required for building the External DNS Controller.
@@ -54,6 +69,7 @@ type ExtDNSOpts struct {
 	namespace     []string
 	eventRecorder record.EventRecorder
 	client        k8s_nginx.Interface
+	kubeClient    kubernetes.Interface
 	resyncPeriod  time.Duration
 	isDynamicNs   bool
 }
@@ -62,9 +78,9 @@ type ExtDNSOpts struct {
 func NewController(opts *ExtDNSOpts) *ExtDNSController {
 	ig := make(map[string]*namespacedInformer)
 
-	rateLimiter := workqueue.DefaultTypedControllerRateLimiter[types.NamespacedName]()
+	rateLimiter := workqueue.DefaultTypedControllerRateLimiter[QueueKey]()
 
-	queue := workqueue.NewTypedRateLimitingQueueWithConfig(rateLimiter, workqueue.TypedRateLimitingQueueConfig[types.NamespacedName]{Name: ControllerName})
+	queue := workqueue.NewTypedRateLimitingQueueWithConfig(rateLimiter, workqueue.TypedRateLimitingQueueConfig[QueueKey]{Name: ControllerName})
 
 	c := &ExtDNSController{
 		ctx:           opts.context,
@@ -72,6 +88,7 @@ func NewController(opts *ExtDNSOpts) *ExtDNSController {
 		informerGroup: ig,
 		recorder:      opts.eventRecorder,
 		client:        opts.client,
+		kubeClient:    opts.kubeClient,
 		resync:        opts.resyncPeriod,
 	}
 
@@ -84,18 +101,38 @@ func NewController(opts *ExtDNSOpts) *ExtDNSController {
 	}
 
 	c.sync = SyncFnFor(c.recorder, c.client, c.informerGroup)
+	c.tsSync = TransportServerSyncFnFor(c.recorder, c.client, c.informerGroup)
+	c.ingressSync = IngressSyncFnFor(c.recorder, c.client, c.informerGroup)
 	return c
 }
 
 func (c *ExtDNSController) newNamespacedInformer(ns string) *namespacedInformer {
 	nsi := &namespacedInformer{sharedInformerFactory: k8s_nginx_informers.NewSharedInformerFactoryWithOptions(c.client, c.resync, k8s_nginx_informers.WithNamespace(ns))}
+	nsi.kubeSharedInformerFactory = kubeinformers.NewSharedInformerFactoryWithOptions(c.kubeClient, c.resync, kubeinformers.WithNamespace(ns))
 	nsi.stopCh = make(chan struct{})
 	nsi.vsLister = nsi.sharedInformerFactory.K8s().V1().VirtualServers().Lister()
+	nsi.tsLister = nsi.sharedInformerFactory.K8s().V1().TransportServers().Lister()
+	nsi.ingressLister = nsi.kubeSharedInformerFactory.Networking().V1().Ingresses().Lister()
 	nsi.extdnslister = nsi.sharedInformerFactory.Externaldns().V1().DNSEndpoints().Lister()
 
 	nsi.sharedInformerFactory.K8s().V1().VirtualServers().Informer().AddEventHandler( //nolint:errcheck,gosec
 		&QueuingEventHandler{
 			Queue: c.queue,
+			Kind:  vsGVK.Kind,
+		},
+	)
+
+	nsi.sharedInformerFactory.K8s().V1().TransportServers().Informer().AddEventHandler( //nolint:errcheck,gosec
+		&QueuingEventHandler{
+			Queue: c.queue,
+			Kind:  tsGVK.Kind,
+		},
+	)
+
+	nsi.kubeSharedInformerFactory.Networking().V1().Ingresses().Informer().AddEventHandler( //nolint:errcheck,gosec
+		&QueuingEventHandler{
+			Queue: c.queue,
+			Kind:  ingressGVK.Kind,
 		},
 	)
 
@@ -105,6 +142,8 @@ func (c *ExtDNSController) newNamespacedInformer(ns string) *namespacedInformer
 
 	nsi.mustSync = append(nsi.mustSync,
 		nsi.sharedInformerFactory.K8s().V1().VirtualServers().Informer().HasSynced,
+		nsi.sharedInformerFactory.K8s().V1().TransportServers().Informer().HasSynced,
+		nsi.kubeSharedInformerFactory.Networking().V1().Ingresses().Informer().HasSynced,
 		nsi.sharedInformerFactory.Externaldns().V1().DNSEndpoints().Informer().HasSynced,
 	)
 	c.informerGroup[ns] = nsi
@@ -149,6 +188,7 @@ func (c *ExtDNSController) Run(stopCh <-chan struct{}) {
 
 func (nsi *namespacedInformer) start() {
 	go nsi.sharedInformerFactory.Start(nsi.stopCh)
+	go nsi.kubeSharedInformerFactory.Start(nsi.stopCh)
 }
 
 func (nsi *namespacedInformer) stop() {
@@ -179,27 +219,50 @@ func (c *ExtDNSController) runWorker(ctx context.Context) {
 	}
 }
 
-func (c *ExtDNSController) processItem(ctx context.Context, key types.NamespacedName) error {
+// processItem reconciles the VirtualServer, TransportServer or Ingress of the key.
+func (c *ExtDNSController) processItem(ctx context.Context, key QueueKey) error {
 	namespace := key.Namespace
 	name := key.Name
 	l := nl.LoggerFromContext(ctx)
-	var vs *conf_v1.VirtualServer
 	nsi := getNamespacedInformer(namespace, c.informerGroup)
-	vs, err := nsi.vsLister.VirtualServers(namespace).Get(name)
 
-	// VS has been deleted
-	if apierrors.IsNotFound(err) {
-		return nil
+	switch key.Kind {
+	case vsGVK.Kind:
+		vs, err := nsi.vsLister.VirtualServers(namespace).Get(name)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		nl.Debugf(l, "processing virtual server resource")
+		return c.sync(ctx, vs)
+	case tsGVK.Kind:
+		ts, err := nsi.tsLister.TransportServers(namespace).Get(name)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		nl.Debugf(l, "processing transport server resource")
+		return c.tsSync(ctx, ts)
+	case ingressGVK.Kind:
+		ing, err := nsi.ingressLister.Ingresses(namespace).Get(name)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		nl.Debugf(l, "processing ingress resource")
+		return c.ingressSync(ctx, ing)
 	}
 
-	if err != nil {
-		return err
-	}
-	nl.Debugf(l, "processing virtual server resource")
-	return c.sync(ctx, vs)
+	return nil
 }
 
-func externalDNSHandler(queue workqueue.TypedRateLimitingInterface[types.NamespacedName]) func(obj interface{}) {
+func externalDNSHandler(queue workqueue.TypedRateLimitingInterface[QueueKey]) func(obj interface{}) {
 	return func(obj interface{}) {
 		ep, ok := obj.(*extdns_v1.DNSEndpoint)
 		if !ok {
@@ -215,24 +278,25 @@ func externalDNSHandler(queue workqueue.TypedRateLimitingInterface[types.Namespa
 		}
 
 		// We don't check the apiVersion
-		// because there is no chance that another object called "VirtualServer" be
-		// the controller of a DNSEndpoint.
-		if ref.Kind != "VirtualServer" {
+		// because there is no chance that another object called "VirtualServer",
+		// "TransportServer" or "Ingress" be the controller of a DNSEndpoint.
+		if ref.Kind != vsGVK.Kind && ref.Kind != tsGVK.Kind && ref.Kind != ingressGVK.Kind {
 			return
 		}
 
-		key := types.NamespacedName{Namespace: ep.Namespace, Name: ref.Name}
+		key := QueueKey{Kind: ref.Kind, Namespace: ep.Namespace, Name: ref.Name}
 		queue.Add(key)
 	}
 }
 
 // BuildOpts builds the externalDNS controller options
-func BuildOpts(ctx context.Context, ns []string, rdr record.EventRecorder, client k8s_nginx.Interface, resync time.Duration, idn bool) *ExtDNSOpts {
+func BuildOpts(ctx context.Context, ns []string, rdr record.EventRecorder, client k8s_nginx.Interface, kubeClient kubernetes.Interface, resync time.Duration, idn bool) *ExtDNSOpts {
 	return &ExtDNSOpts{
 		context:       ctx,
 		namespace:     ns,
 		eventRecorder: rdr,
 		client:        client,
+		kubeClient:    kubeClient,
 		resyncPeriod:  resync,
 		isDynamicNs:   idn,
 	}
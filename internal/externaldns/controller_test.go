@@ -0,0 +1,104 @@
+package externaldns
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	vsapi "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	extdnsapi "github.com/nginx/kubernetes-ingress/pkg/apis/externaldns/v1"
+	listersV1 "github.com/nginx/kubernetes-ingress/pkg/client/listers/configuration/v1"
+	networking "k8s.io/api/networking/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	networkinglisters "k8s.io/client-go/listers/networking/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestProcessItem_SyncsResourcesOfDifferentKindsWithSameNameIndependently(t *testing.T) {
+	t.Parallel()
+
+	meta := v1.ObjectMeta{Name: "cafe", Namespace: "default"}
+	vsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	tsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	ingressIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for indexer, obj := range map[cache.Indexer]interface{}{
+		vsIndexer:      &vsapi.VirtualServer{ObjectMeta: meta},
+		tsIndexer:      &vsapi.TransportServer{ObjectMeta: meta},
+		ingressIndexer: &networking.Ingress{ObjectMeta: meta},
+	} {
+		if err := indexer.Add(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var synced []string
+	errTS := errors.New("failed to sync")
+	c := &ExtDNSController{
+		sync: func(context.Context, *vsapi.VirtualServer) error {
+			synced = append(synced, vsGVK.Kind)
+			return nil
+		},
+		tsSync: func(context.Context, *vsapi.TransportServer) error {
+			synced = append(synced, tsGVK.Kind)
+			return errTS
+		},
+		ingressSync: func(context.Context, *networking.Ingress) error {
+			synced = append(synced, ingressGVK.Kind)
+			return nil
+		},
+		informerGroup: map[string]*namespacedInformer{
+			"": {
+				vsLister:      listersV1.NewVirtualServerLister(vsIndexer),
+				tsLister:      listersV1.NewTransportServerLister(tsIndexer),
+				ingressLister: networkinglisters.NewIngressLister(ingressIndexer),
+			},
+		},
+	}
+
+	tests := []struct {
+		kind    string
+		wantErr error
+	}{
+		{kind: tsGVK.Kind, wantErr: errTS},
+		{kind: vsGVK.Kind},
+		{kind: ingressGVK.Kind},
+	}
+	for _, tc := range tests {
+		synced = nil
+		err := c.processItem(context.Background(), QueueKey{Kind: tc.kind, Namespace: "default", Name: "cafe"})
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("processItem() for %s returned %v, want %v", tc.kind, err, tc.wantErr)
+		}
+		if len(synced) != 1 || synced[0] != tc.kind {
+			t.Errorf("processItem() for %s synced %v, want only %s", tc.kind, synced, tc.kind)
+		}
+	}
+}
+
+func TestExternalDNSHandler_QueuesOwnerWithItsKind(t *testing.T) {
+	t.Parallel()
+
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[QueueKey]())
+	defer queue.ShutDown()
+
+	isController := true
+	externalDNSHandler(queue)(&extdnsapi.DNSEndpoint{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "cafe-transportserver",
+			Namespace: "default",
+			OwnerReferences: []v1.OwnerReference{
+				{Kind: tsGVK.Kind, Name: "cafe", Controller: &isController},
+			},
+		},
+	})
+
+	if queue.Len() != 1 {
+		t.Fatalf("queue has %d keys, want 1", queue.Len())
+	}
+	key, _ := queue.Get()
+	want := QueueKey{Kind: tsGVK.Kind, Namespace: "default", Name: "cafe"}
+	if key != want {
+		t.Errorf("queued %+v, want %+v", key, want)
+	}
+}
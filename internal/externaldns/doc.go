@@ -1,2 +1,2 @@
-// Package externaldns implements External DNS controller for Virtual Server.
+// Package externaldns implements External DNS controller for VirtualServer, TransportServer and Ingress resources.
 package externaldns
@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
@@ -18,14 +17,16 @@ var KeyFunc = cache.DeletionHandlingMetaNamespaceKeyFunc
 
 // DefaultItemBasedRateLimiter returns a new rate limiter with base delay of 5
 // seconds, max delay of 5 minutes.
-func DefaultItemBasedRateLimiter() workqueue.TypedRateLimiter[types.NamespacedName] {
-	return workqueue.NewTypedItemExponentialFailureRateLimiter[types.NamespacedName](5*time.Second, 5*time.Minute)
+func DefaultItemBasedRateLimiter() workqueue.TypedRateLimiter[QueueKey] {
+	return workqueue.NewTypedItemExponentialFailureRateLimiter[QueueKey](5*time.Second, 5*time.Minute)
 }
 
 // QueuingEventHandler is an implementation of cache.ResourceEventHandler that
 // simply queues objects that are added/updated/deleted.
 type QueuingEventHandler struct {
-	Queue workqueue.TypedRateLimitingInterface[types.NamespacedName]
+	Queue workqueue.TypedRateLimitingInterface[QueueKey]
+	// Kind is the kind of the objects, which is a part of their keys.
+	Kind string
 }
 
 // Enqueue adds a key for an object to the workqueue.
@@ -35,7 +36,8 @@ func (q *QueuingEventHandler) Enqueue(obj interface{}) {
 		runtime.HandleError(err)
 		return
 	}
-	key := types.NamespacedName{
+	key := QueueKey{
+		Kind:      q.Kind,
 		Namespace: accessor.GetNamespace(),
 		Name:      accessor.GetName(),
 	}
@@ -0,0 +1,115 @@
+package externaldns
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	vsapi "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	networking "k8s.io/api/networking/v1"
+)
+
+// Ingress annotations that configure the DNSEndpoint of an Ingress.
+const (
+	EnableAnnotation           = "nginx.org/external-dns-enable"
+	RecordTypeAnnotation       = "nginx.org/external-dns-record-type"
+	RecordTTLAnnotation        = "nginx.org/external-dns-record-ttl"
+	LabelsAnnotation           = "nginx.org/external-dns-labels"
+	ProviderSpecificAnnotation = "nginx.org/external-dns-provider-specific"
+)
+
+// ExternalDNSForIngress builds the ExternalDNS configuration of an Ingress from its annotations.
+func ExternalDNSForIngress(ing *networking.Ingress) (vsapi.ExternalDNS, error) {
+	var externalDNS vsapi.ExternalDNS
+
+	enable, exists := ing.Annotations[EnableAnnotation]
+	if !exists {
+		return externalDNS, nil
+	}
+	e, err := strconv.ParseBool(enable)
+	if err != nil {
+		return externalDNS, fmt.Errorf("%s must be a boolean: %w", EnableAnnotation, err)
+	}
+	externalDNS.Enable = e
+
+	externalDNS.RecordType = ing.Annotations[RecordTypeAnnotation]
+
+	if ttl, exists := ing.Annotations[RecordTTLAnnotation]; exists {
+		t, err := strconv.ParseInt(ttl, 10, 64)
+		if err != nil || t < 0 {
+			return externalDNS, fmt.Errorf("%s must be a non-negative integer", RecordTTLAnnotation)
+		}
+		externalDNS.RecordTTL = t
+	}
+
+	if labels, exists := ing.Annotations[LabelsAnnotation]; exists {
+		l, err := ParseKeyValueList(labels)
+		if err != nil {
+			return externalDNS, fmt.Errorf("%s: %w", LabelsAnnotation, err)
+		}
+		externalDNS.Labels = l
+	}
+
+	if providerSpecific, exists := ing.Annotations[ProviderSpecificAnnotation]; exists {
+		p, err := ParseKeyValueList(providerSpecific)
+		if err != nil {
+			return externalDNS, fmt.Errorf("%s: %w", ProviderSpecificAnnotation, err)
+		}
+		names := make([]string, 0, len(p))
+		for name := range p {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			externalDNS.ProviderSpecific = append(externalDNS.ProviderSpecific, vsapi.ProviderSpecificProperty{
+				Name:  name,
+				Value: p[name],
+			})
+		}
+	}
+
+	return externalDNS, nil
+}
+
+// ParseKeyValueList parses a comma-separated list of key=value pairs, for example "team=a,env=prod".
+func ParseKeyValueList(s string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid key=value pair %q", pair)
+		}
+		if _, exists := result[key]; exists {
+			return nil, fmt.Errorf("duplicate key %q", key)
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+// ingressHosts returns the unique hosts of the rules of an Ingress.
+func ingressHosts(ing *networking.Ingress) []string {
+	var hosts []string
+	seen := make(map[string]bool)
+	for _, rule := range ing.Spec.Rules {
+		if rule.Host == "" || seen[rule.Host] {
+			continue
+		}
+		seen[rule.Host] = true
+		hosts = append(hosts, rule.Host)
+	}
+	return hosts
+}
+
+// ingressExternalEndpoints converts the load balancer status of an Ingress to external endpoints.
+func ingressExternalEndpoints(ing *networking.Ingress) []vsapi.ExternalEndpoint {
+	var endpoints []vsapi.ExternalEndpoint
+	for _, lb := range ing.Status.LoadBalancer.Ingress {
+		endpoints = append(endpoints, vsapi.ExternalEndpoint{
+			IP:       lb.IP,
+			Hostname: lb.Hostname,
+		})
+	}
+	return endpoints
+}
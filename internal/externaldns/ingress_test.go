@@ -0,0 +1,76 @@
+package externaldns
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	vsapi "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	networking "k8s.io/api/networking/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExternalDNSForIngress(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		name        string
+		annotations map[string]string
+		want        vsapi.ExternalDNS
+	}{
+		{
+			name:        "without annotations",
+			annotations: nil,
+			want:        vsapi.ExternalDNS{},
+		},
+		{
+			name: "with all annotations",
+			annotations: map[string]string{
+				EnableAnnotation:           "true",
+				RecordTypeAnnotation:       "CNAME",
+				RecordTTLAnnotation:        "60",
+				LabelsAnnotation:           "team=a, env=prod",
+				ProviderSpecificAnnotation: "b=2,a=1",
+			},
+			want: vsapi.ExternalDNS{
+				Enable:     true,
+				RecordType: "CNAME",
+				RecordTTL:  60,
+				Labels: map[string]string{
+					"team": "a",
+					"env":  "prod",
+				},
+				ProviderSpecific: vsapi.ProviderSpecific{
+					{Name: "a", Value: "1"},
+					{Name: "b", Value: "2"},
+				},
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: tc.annotations}}
+			got, err := ExternalDNSForIngress(ing)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tc.want, got) {
+				t.Error(cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestExternalDNSForIngress_FailsOnInvalidAnnotations(t *testing.T) {
+	t.Parallel()
+	tt := []map[string]string{
+		{EnableAnnotation: "yes please"},
+		{EnableAnnotation: "true", RecordTTLAnnotation: "-5"},
+		{EnableAnnotation: "true", LabelsAnnotation: "team"},
+		{EnableAnnotation: "true", ProviderSpecificAnnotation: "a=1,a=2"},
+	}
+	for _, annotations := range tt {
+		ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: annotations}}
+		if _, err := ExternalDNSForIngress(ing); err == nil {
+			t.Errorf("ExternalDNSForIngress() returned no error for %v", annotations)
+		}
+	}
+}
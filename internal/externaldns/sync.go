@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/google/go-cmp/cmp"
 	nl "github.com/nginx/kubernetes-ingress/internal/logger"
@@ -12,6 +13,8 @@ import (
 	clientset "github.com/nginx/kubernetes-ingress/pkg/client/clientset/versioned"
 	extdnslisters "github.com/nginx/kubernetes-ingress/pkg/client/listers/externaldns/v1"
 	corev1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	validators "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -23,19 +26,77 @@ import (
 )
 
 const (
-	reasonBadConfig         = "BadConfig"
-	reasonCreateDNSEndpoint = "CreateDNSEndpoint"
-	reasonUpdateDNSEndpoint = "UpdateDNSEndpoint"
-	recordTypeA             = "A"
-	recordTypeAAAA          = "AAAA"
-	recordTypeCNAME         = "CNAME"
+	reasonBadConfig           = "BadConfig"
+	reasonCreateDNSEndpoint   = "CreateDNSEndpoint"
+	reasonUpdateDNSEndpoint   = "UpdateDNSEndpoint"
+	reasonDNSEndpointConflict = "DNSEndpointConflict"
+	recordTypeA               = "A"
+	recordTypeAAAA            = "AAAA"
+	recordTypeCNAME           = "CNAME"
 )
 
-var vsGVK = vsapi.SchemeGroupVersion.WithKind("VirtualServer")
+var (
+	vsGVK      = vsapi.SchemeGroupVersion.WithKind("VirtualServer")
+	tsGVK      = vsapi.SchemeGroupVersion.WithKind("TransportServer")
+	ingressGVK = networking.SchemeGroupVersion.WithKind("Ingress")
+
+	errDNSEndpointNotOwned = errors.New("DNSEndpoint is not owned by the resource")
+)
 
 // SyncFn is the reconciliation function passed to externaldns controller.
 type SyncFn func(context.Context, *vsapi.VirtualServer) error
 
+// TransportServerSyncFn is the reconciliation function for TransportServers passed to externaldns controller.
+type TransportServerSyncFn func(context.Context, *vsapi.TransportServer) error
+
+// IngressSyncFn is the reconciliation function for Ingresses passed to externaldns controller.
+type IngressSyncFn func(context.Context, *networking.Ingress) error
+
+// ownerObject is a resource that can own a DNSEndpoint.
+type ownerObject interface {
+	metav1.Object
+	runtime.Object
+}
+
+// dnsEndpointOwner holds the ExternalDNS configuration of a resource that owns a DNSEndpoint.
+type dnsEndpointOwner struct {
+	obj               ownerObject
+	gvk               schema.GroupVersionKind
+	hosts             []string
+	externalDNS       vsapi.ExternalDNS
+	externalEndpoints []vsapi.ExternalEndpoint
+}
+
+func ownerForVirtualServer(vs *vsapi.VirtualServer) dnsEndpointOwner {
+	return dnsEndpointOwner{
+		obj:               vs,
+		gvk:               vsGVK,
+		hosts:             []string{vs.Spec.Host},
+		externalDNS:       vs.Spec.ExternalDNS,
+		externalEndpoints: vs.Status.ExternalEndpoints,
+	}
+}
+
+func ownerForTransportServer(ts *vsapi.TransportServer) dnsEndpointOwner {
+	return dnsEndpointOwner{
+		obj:               ts,
+		gvk:               tsGVK,
+		hosts:             []string{ts.Spec.Host},
+		externalDNS:       ts.Spec.ExternalDNS,
+		externalEndpoints: ts.Status.ExternalEndpoints,
+	}
+}
+
+func ownerForIngress(ing *networking.Ingress, externalDNS vsapi.ExternalDNS) dnsEndpointOwner {
+	return dnsEndpointOwner{
+		obj:               ing,
+		gvk:               ingressGVK,
+		hosts:             ingressHosts(ing),
+		externalDNS:       externalDNS,
+		externalEndpoints: ingressExternalEndpoints(ing),
+	}
+}
+
 // SyncFnFor knows how to reconcile VirtualServer DNSEndpoint object.
 func SyncFnFor(rec record.EventRecorder, client clientset.Interface, ig map[string]*namespacedInformer) SyncFn {
 	return func(ctx context.Context, vs *vsapi.VirtualServer) error {
@@ -43,64 +104,111 @@ func SyncFnFor(rec record.EventRecorder, client clientset.Interface, ig map[stri
 		if !vs.Spec.ExternalDNS.Enable {
 			return nil
 		}
-		l := nl.LoggerFromContext(ctx)
+		return syncDNSEndpoint(ctx, rec, client, ig, ownerForVirtualServer(vs))
+	}
+}
 
-		if vs.Status.ExternalEndpoints == nil {
-			// It can take time for the external endpoints to sync - kick it back to the queue
-			nl.Info(l, "Failed to determine external endpoints - retrying")
-			return fmt.Errorf("failed to determine external endpoints")
+// TransportServerSyncFnFor knows how to reconcile TransportServer DNSEndpoint object.
+func TransportServerSyncFnFor(rec record.EventRecorder, client clientset.Interface, ig map[string]*namespacedInformer) TransportServerSyncFn {
+	return func(ctx context.Context, ts *vsapi.TransportServer) error {
+		// Do nothing if ExternalDNS is not enabled in TS.
+		if !ts.Spec.ExternalDNS.Enable {
+			return nil
 		}
+		return syncDNSEndpoint(ctx, rec, client, ig, ownerForTransportServer(ts))
+	}
+}
 
-		targets, recordType, err := getValidTargets(ctx, vs.Status.ExternalEndpoints)
+// IngressSyncFnFor knows how to reconcile Ingress DNSEndpoint object.
+// The ExternalDNS configuration of an Ingress is taken from its annotations.
+func IngressSyncFnFor(rec record.EventRecorder, client clientset.Interface, ig map[string]*namespacedInformer) IngressSyncFn {
+	return func(ctx context.Context, ing *networking.Ingress) error {
+		externalDNS, err := ExternalDNSForIngress(ing)
 		if err != nil {
-			nl.Error(l, "Invalid external endpoint")
-			rec.Eventf(vs, corev1.EventTypeWarning, reasonBadConfig, "Invalid external endpoint")
-			return err
+			l := nl.LoggerFromContext(ctx)
+			nl.Errorf(l, "Invalid ExternalDNS annotations: %s", err)
+			rec.Eventf(ing, corev1.EventTypeWarning, reasonBadConfig, "Invalid ExternalDNS annotations: %s", err)
+			// Retrying will not fix invalid annotations
+			return nil
+		}
+		// Do nothing if ExternalDNS is not enabled in Ingress annotations.
+		if !externalDNS.Enable {
+			return nil
 		}
+		if len(ingressHosts(ing)) == 0 {
+			rec.Eventf(ing, corev1.EventTypeWarning, reasonBadConfig, "Ingress has no hosts to create DNS records for")
+			return nil
+		}
+		return syncDNSEndpoint(ctx, rec, client, ig, ownerForIngress(ing, externalDNS))
+	}
+}
 
-		nsi := getNamespacedInformer(vs.Namespace, ig)
+func syncDNSEndpoint(ctx context.Context, rec record.EventRecorder, client clientset.Interface, ig map[string]*namespacedInformer, owner dnsEndpointOwner) error {
+	l := nl.LoggerFromContext(ctx)
+	obj := owner.obj
+	kind := owner.gvk.Kind
 
-		newDNSEndpoint, updateDNSEndpoint, err := buildDNSEndpoint(ctx, nsi.extdnslister, vs, targets, recordType)
-		if err != nil {
-			nl.Errorf(l, "incorrect DNSEndpoint config for VirtualServer resource: %s", err)
-			rec.Eventf(vs, corev1.EventTypeWarning, reasonBadConfig, "Incorrect DNSEndpoint config for VirtualServer resource: %s", err)
-			return err
-		}
+	if owner.externalEndpoints == nil {
+		// It can take time for the external endpoints to sync - kick it back to the queue
+		nl.Info(l, "Failed to determine external endpoints - retrying")
+		return fmt.Errorf("failed to determine external endpoints")
+	}
+
+	targets, recordType, err := getValidTargets(ctx, owner.externalEndpoints)
+	if err != nil {
+		nl.Error(l, "Invalid external endpoint")
+		rec.Eventf(obj, corev1.EventTypeWarning, reasonBadConfig, "Invalid external endpoint")
+		return err
+	}
 
-		var dep *extdnsapi.DNSEndpoint
-
-		// Create new DNSEndpoint object
-		if newDNSEndpoint != nil {
-			nl.Debugf(l, "Creating DNSEndpoint for VirtualServer resource: %v", vs.Name)
-			dep, err = client.ExternaldnsV1().DNSEndpoints(newDNSEndpoint.Namespace).Create(ctx, newDNSEndpoint, metav1.CreateOptions{})
-			if err != nil {
-				if apierrors.IsAlreadyExists(err) {
-					// Another replica likely created the DNSEndpoint since we last checked - kick it back to the queue
-					nl.Debugf(l, "DNSEndpoint has been created since we last checked - retrying")
-					return fmt.Errorf("DNSEndpoint has already been created")
-				}
-				nl.Errorf(l, "Error creating DNSEndpoint for VirtualServer resource: %v", err)
-				rec.Eventf(vs, corev1.EventTypeWarning, reasonBadConfig, "Error creating DNSEndpoint for VirtualServer resource %s", err)
-				return err
+	nsi := getNamespacedInformer(obj.GetNamespace(), ig)
+
+	newDNSEndpoint, updateDNSEndpoint, err := buildDNSEndpoint(ctx, nsi.extdnslister, owner, targets, recordType)
+	if errors.Is(err, errDNSEndpointNotOwned) {
+		// Retry, so that the DNSEndpoint is created once the conflicting DNSEndpoint is deleted
+		nl.Warnf(l, "Conflicting DNSEndpoint for %s resource: %s", kind, err)
+		rec.Eventf(obj, corev1.EventTypeWarning, reasonDNSEndpointConflict, "Conflicting DNSEndpoint for %s resource: %s", kind, err)
+		return err
+	}
+	if err != nil {
+		nl.Errorf(l, "incorrect DNSEndpoint config for %s resource: %s", kind, err)
+		rec.Eventf(obj, corev1.EventTypeWarning, reasonBadConfig, "Incorrect DNSEndpoint config for %s resource: %s", kind, err)
+		return err
+	}
+
+	var dep *extdnsapi.DNSEndpoint
+
+	// Create new DNSEndpoint object
+	if newDNSEndpoint != nil {
+		nl.Debugf(l, "Creating DNSEndpoint for %s resource: %v", kind, obj.GetName())
+		dep, err = client.ExternaldnsV1().DNSEndpoints(newDNSEndpoint.Namespace).Create(ctx, newDNSEndpoint, metav1.CreateOptions{})
+		if err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				// Another replica likely created the DNSEndpoint since we last checked - kick it back to the queue
+				nl.Debugf(l, "DNSEndpoint has been created since we last checked - retrying")
+				return fmt.Errorf("DNSEndpoint has already been created")
 			}
-			rec.Eventf(vs, corev1.EventTypeNormal, reasonCreateDNSEndpoint, "Successfully created DNSEndpoint %q", newDNSEndpoint.Name)
-			rec.Eventf(dep, corev1.EventTypeNormal, reasonCreateDNSEndpoint, "Successfully created DNSEndpoint for VirtualServer %q", vs.Name)
+			nl.Errorf(l, "Error creating DNSEndpoint for %s resource: %v", kind, err)
+			rec.Eventf(obj, corev1.EventTypeWarning, reasonBadConfig, "Error creating DNSEndpoint for %s resource %s", kind, err)
+			return err
 		}
+		rec.Eventf(obj, corev1.EventTypeNormal, reasonCreateDNSEndpoint, "Successfully created DNSEndpoint %q", newDNSEndpoint.Name)
+		rec.Eventf(dep, corev1.EventTypeNormal, reasonCreateDNSEndpoint, "Successfully created DNSEndpoint for %s %q", kind, obj.GetName())
+	}
 
-		// Update existing DNSEndpoint object
-		if updateDNSEndpoint != nil {
-			nl.Debugf(l, "Updating DNSEndpoint for VirtualServer resource: %v", vs.Name)
-			dep, err = client.ExternaldnsV1().DNSEndpoints(updateDNSEndpoint.Namespace).Update(ctx, updateDNSEndpoint, metav1.UpdateOptions{})
-			if err != nil {
-				nl.Errorf(l, "Error updating DNSEndpoint endpoint for VirtualServer resource: %v", err)
-				rec.Eventf(vs, corev1.EventTypeWarning, reasonBadConfig, "Error updating DNSEndpoint for VirtualServer resource: %s", err)
-				return err
-			}
-			rec.Eventf(vs, corev1.EventTypeNormal, reasonUpdateDNSEndpoint, "Successfully updated DNSEndpoint %q", updateDNSEndpoint.Name)
-			rec.Eventf(dep, corev1.EventTypeNormal, reasonUpdateDNSEndpoint, "Successfully updated DNSEndpoint for VirtualServer %q", vs.Name)
+	// Update existing DNSEndpoint object
+	if updateDNSEndpoint != nil {
+		nl.Debugf(l, "Updating DNSEndpoint for %s resource: %v", kind, obj.GetName())
+		dep, err = client.ExternaldnsV1().DNSEndpoints(updateDNSEndpoint.Namespace).Update(ctx, updateDNSEndpoint, metav1.UpdateOptions{})
+		if err != nil {
+			nl.Errorf(l, "Error updating DNSEndpoint endpoint for %s resource: %v", kind, err)
+			rec.Eventf(obj, corev1.EventTypeWarning, reasonBadConfig, "Error updating DNSEndpoint for %s resource: %s", kind, err)
+			return err
 		}
-		return nil
+		rec.Eventf(obj, corev1.EventTypeNormal, reasonUpdateDNSEndpoint, "Successfully updated DNSEndpoint %q", updateDNSEndpoint.Name)
+		rec.Eventf(dep, corev1.EventTypeNormal, reasonUpdateDNSEndpoint, "Successfully updated DNSEndpoint for %s %q", kind, obj.GetName())
 	}
+	return nil
 }
 
 func getValidTargets(ctx context.Context, endpoints []vsapi.ExternalEndpoint) (extdnsapi.Targets, string, error) {
@@ -146,55 +254,58 @@ func getValidTargets(ctx context.Context, endpoints []vsapi.ExternalEndpoint) (e
 	return targets, recordType, err
 }
 
-func buildDNSEndpoint(ctx context.Context, extdnsLister extdnslisters.DNSEndpointLister, vs *vsapi.VirtualServer, targets extdnsapi.Targets, recordType string) (*extdnsapi.DNSEndpoint, *extdnsapi.DNSEndpoint, error) {
+func buildDNSEndpoint(ctx context.Context, extdnsLister extdnslisters.DNSEndpointLister, owner dnsEndpointOwner, targets extdnsapi.Targets, recordType string) (*extdnsapi.DNSEndpoint, *extdnsapi.DNSEndpoint, error) {
 	var updateDNSEndpoint *extdnsapi.DNSEndpoint
 	var newDNSEndpoint *extdnsapi.DNSEndpoint
 	var existingDNSEndpoint *extdnsapi.DNSEndpoint
 	var err error
 	l := nl.LoggerFromContext(ctx)
+	obj := owner.obj
 
-	existingDNSEndpoint, err = extdnsLister.DNSEndpoints(vs.Namespace).Get(vs.ObjectMeta.Name)
+	name := dnsEndpointName(obj, owner.gvk)
+	existingDNSEndpoint, err = extdnsLister.DNSEndpoints(obj.GetNamespace()).Get(name)
 
 	if !apierrors.IsNotFound(err) && err != nil {
 		return nil, nil, err
 	}
-	var controllerGVK schema.GroupVersionKind = vsGVK
-	ownerRef := *metav1.NewControllerRef(vs, controllerGVK)
+	ownerRef := *metav1.NewControllerRef(obj, owner.gvk)
 	blockOwnerDeletion := false
 	ownerRef.BlockOwnerDeletion = &blockOwnerDeletion
 
+	var endpoints []*extdnsapi.Endpoint
+	for _, host := range owner.hosts {
+		endpoints = append(endpoints, &extdnsapi.Endpoint{
+			DNSName:          host,
+			Targets:          targets,
+			RecordType:       buildRecordType(owner.externalDNS, recordType),
+			RecordTTL:        buildTTL(owner.externalDNS),
+			Labels:           buildLabels(owner.externalDNS),
+			ProviderSpecific: buildProviderSpecificProperties(owner.externalDNS),
+		})
+	}
+
 	dnsEndpoint := &extdnsapi.DNSEndpoint{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:            vs.ObjectMeta.Name,
-			Namespace:       vs.Namespace,
-			Labels:          vs.Labels,
+			Name:            name,
+			Namespace:       obj.GetNamespace(),
+			Labels:          obj.GetLabels(),
 			OwnerReferences: []metav1.OwnerReference{ownerRef},
 		},
 		Spec: extdnsapi.DNSEndpointSpec{
-			Endpoints: []*extdnsapi.Endpoint{
-				{
-					DNSName:          vs.Spec.Host,
-					Targets:          targets,
-					RecordType:       buildRecordType(vs.Spec.ExternalDNS, recordType),
-					RecordTTL:        buildTTL(vs.Spec.ExternalDNS),
-					Labels:           buildLabels(vs.Spec.ExternalDNS),
-					ProviderSpecific: buildProviderSpecificProperties(vs.Spec.ExternalDNS),
-				},
-			},
+			Endpoints: endpoints,
 		},
 	}
 
-	vs = vs.DeepCopy()
-
 	if existingDNSEndpoint != nil {
 		nl.Debugf(l, "DNSEndpoint already exists for this object, ensuring it is up to date")
-		if metav1.GetControllerOf(existingDNSEndpoint) == nil {
+		ref := metav1.GetControllerOf(existingDNSEndpoint)
+		if ref == nil {
 			nl.Debugf(l, "DNSEndpoint has no owner. refusing to update non-owned resource")
-			return nil, nil, nil
+			return nil, nil, fmt.Errorf("%w: DNSEndpoint %q has no owner", errDNSEndpointNotOwned, name)
 		}
-		if !metav1.IsControlledBy(existingDNSEndpoint, vs) {
+		if !metav1.IsControlledBy(existingDNSEndpoint, obj) {
 			nl.Debugf(l, "external DNS endpoint resource is not owned by this object. refusing to update non-owned resource")
-			return nil, nil, nil
+			return nil, nil, fmt.Errorf("%w: DNSEndpoint %q is owned by %s %q", errDNSEndpointNotOwned, name, ref.Kind, ref.Name)
 		}
 		if !extdnsendpointNeedsUpdate(existingDNSEndpoint, dnsEndpoint) {
 			nl.Debugf(l, "external DNS resource is already up to date for object")
@@ -211,6 +322,21 @@ func buildDNSEndpoint(ctx context.Context, extdnsLister extdnslisters.DNSEndpoin
 	return newDNSEndpoint, updateDNSEndpoint, nil
 }
 
+// dnsEndpointName returns the name of the DNSEndpoint of a resource. The DNSEndpoint of a VirtualServer is named
+// after the VirtualServer, while the name of the DNSEndpoint of a TransportServer or an Ingress is suffixed with the
+// kind, so that a VirtualServer, a TransportServer and an Ingress with the same name get different DNSEndpoints.
+func dnsEndpointName(obj metav1.Object, gvk schema.GroupVersionKind) string {
+	if gvk == vsGVK {
+		return obj.GetName()
+	}
+	suffix := "-" + strings.ToLower(gvk.Kind)
+	name := obj.GetName()
+	if len(name)+len(suffix) > validators.DNS1123SubdomainMaxLength {
+		name = strings.TrimRight(name[:validators.DNS1123SubdomainMaxLength-len(suffix)], ".-")
+	}
+	return name + suffix
+}
+
 func buildTTL(extdnsSpec vsapi.ExternalDNS) extdnsapi.TTL {
 	return extdnsapi.TTL(extdnsSpec.RecordTTL)
 }
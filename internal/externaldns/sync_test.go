@@ -3,15 +3,18 @@ package externaldns
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	vsapi "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
 	extdnsapi "github.com/nginx/kubernetes-ingress/pkg/apis/externaldns/v1"
 	extdnsclient "github.com/nginx/kubernetes-ingress/pkg/client/listers/externaldns/v1"
+	networking "k8s.io/api/networking/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
 )
 
 // EventRecorder implements EventRecorder interface.
@@ -287,3 +290,284 @@ func TestSync_ReturnsErrorOnFailure(t *testing.T) {
 		})
 	}
 }
+
+func TestSync_TransportServerNotRunningOnExternalDNSDisabled(t *testing.T) {
+	t.Parallel()
+	ts := &vsapi.TransportServer{
+		Spec: vsapi.TransportServerSpec{
+			Host: "tcp.example.com",
+			ExternalDNS: vsapi.ExternalDNS{
+				Enable: false,
+			},
+		},
+	}
+	fn := TransportServerSyncFnFor(nil, nil, nil)
+	err := fn(context.TODO(), ts)
+	if err != nil {
+		t.Errorf("want nil got %v", err)
+	}
+}
+
+func TestSync_TransportServerReturnsErrorOnNilExternalEndpoints(t *testing.T) {
+	t.Parallel()
+	ts := &vsapi.TransportServer{
+		Spec: vsapi.TransportServerSpec{
+			Host: "tcp.example.com",
+			ExternalDNS: vsapi.ExternalDNS{
+				Enable: true,
+			},
+		},
+	}
+
+	rec := EventRecorder{}
+	fn := TransportServerSyncFnFor(rec, nil, nil)
+	err := fn(context.TODO(), ts)
+	if err == nil {
+		t.Errorf("want error got nil")
+	}
+}
+
+func TestSync_IngressNotRunningWithoutAnnotations(t *testing.T) {
+	t.Parallel()
+	ing := &networking.Ingress{
+		Spec: networking.IngressSpec{
+			Rules: []networking.IngressRule{{Host: "cafe.example.com"}},
+		},
+	}
+	fn := IngressSyncFnFor(nil, nil, nil)
+	err := fn(context.TODO(), ing)
+	if err != nil {
+		t.Errorf("want nil got %v", err)
+	}
+}
+
+func TestSync_IngressReturnsErrorOnNilExternalEndpoints(t *testing.T) {
+	t.Parallel()
+	ing := &networking.Ingress{
+		ObjectMeta: v1.ObjectMeta{
+			Annotations: map[string]string{
+				EnableAnnotation: "true",
+			},
+		},
+		Spec: networking.IngressSpec{
+			Rules: []networking.IngressRule{{Host: "cafe.example.com"}},
+		},
+	}
+
+	rec := EventRecorder{}
+	fn := IngressSyncFnFor(rec, nil, nil)
+	err := fn(context.TODO(), ing)
+	if err == nil {
+		t.Errorf("want error got nil")
+	}
+}
+
+func TestBuildDNSEndpoint(t *testing.T) {
+	t.Parallel()
+
+	blockOwnerDeletion := false
+	isController := true
+
+	tt := []struct {
+		name  string
+		owner dnsEndpointOwner
+		want  *extdnsapi.DNSEndpoint
+	}{
+		{
+			name: "for transport server",
+			owner: ownerForTransportServer(&vsapi.TransportServer{
+				ObjectMeta: v1.ObjectMeta{Name: "tcp", Namespace: "default", UID: "ts-uid"},
+				Spec: vsapi.TransportServerSpec{
+					Host: "tcp.example.com",
+					ExternalDNS: vsapi.ExternalDNS{
+						Enable:    true,
+						RecordTTL: 30,
+					},
+				},
+				Status: vsapi.TransportServerStatus{
+					ExternalEndpoints: []vsapi.ExternalEndpoint{{IP: "10.0.0.1"}},
+				},
+			}),
+			want: &extdnsapi.DNSEndpoint{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "tcp-transportserver",
+					Namespace: "default",
+					OwnerReferences: []v1.OwnerReference{
+						{
+							APIVersion:         "k8s.nginx.org/v1",
+							Kind:               "TransportServer",
+							Name:               "tcp",
+							UID:                "ts-uid",
+							Controller:         &isController,
+							BlockOwnerDeletion: &blockOwnerDeletion,
+						},
+					},
+				},
+				Spec: extdnsapi.DNSEndpointSpec{
+					Endpoints: []*extdnsapi.Endpoint{
+						{
+							DNSName:    "tcp.example.com",
+							Targets:    extdnsapi.Targets{"10.0.0.1"},
+							RecordType: "A",
+							RecordTTL:  30,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "for ingress with multiple hosts",
+			owner: ownerForIngress(&networking.Ingress{
+				ObjectMeta: v1.ObjectMeta{Name: "cafe", Namespace: "default", UID: "ing-uid"},
+				Spec: networking.IngressSpec{
+					Rules: []networking.IngressRule{
+						{Host: "cafe.example.com"},
+						{Host: "tea.example.com"},
+						{Host: "cafe.example.com"},
+					},
+				},
+				Status: networking.IngressStatus{
+					LoadBalancer: networking.IngressLoadBalancerStatus{
+						Ingress: []networking.IngressLoadBalancerIngress{{Hostname: "lb.example.com"}},
+					},
+				},
+			}, vsapi.ExternalDNS{Enable: true}),
+			want: &extdnsapi.DNSEndpoint{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "cafe-ingress",
+					Namespace: "default",
+					OwnerReferences: []v1.OwnerReference{
+						{
+							APIVersion:         "networking.k8s.io/v1",
+							Kind:               "Ingress",
+							Name:               "cafe",
+							UID:                "ing-uid",
+							Controller:         &isController,
+							BlockOwnerDeletion: &blockOwnerDeletion,
+						},
+					},
+				},
+				Spec: extdnsapi.DNSEndpointSpec{
+					Endpoints: []*extdnsapi.Endpoint{
+						{
+							DNSName:    "cafe.example.com",
+							Targets:    extdnsapi.Targets{"lb.example.com"},
+							RecordType: "CNAME",
+						},
+						{
+							DNSName:    "tea.example.com",
+							Targets:    extdnsapi.Targets{"lb.example.com"},
+							RecordType: "CNAME",
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			lister := extdnsclient.NewDNSEndpointLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}))
+			targets, recordType, err := getValidTargets(context.Background(), tc.owner.externalEndpoints)
+			if err != nil {
+				t.Fatal(err)
+			}
+			newDNSEndpoint, updateDNSEndpoint, err := buildDNSEndpoint(context.Background(), lister, tc.owner, targets, recordType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if updateDNSEndpoint != nil {
+				t.Errorf("want no DNSEndpoint to update, got %v", updateDNSEndpoint)
+			}
+			if !cmp.Equal(tc.want, newDNSEndpoint) {
+				t.Error(cmp.Diff(tc.want, newDNSEndpoint))
+			}
+		})
+	}
+}
+
+func TestBuildDNSEndpoint_NamesDNSEndpointsOfResourcesOfDifferentKindsWithSameNameDifferently(t *testing.T) {
+	t.Parallel()
+
+	meta := v1.ObjectMeta{Name: "cafe", Namespace: "default"}
+	externalDNS := vsapi.ExternalDNS{Enable: true}
+	owners := []dnsEndpointOwner{
+		ownerForVirtualServer(&vsapi.VirtualServer{
+			ObjectMeta: meta,
+			Spec:       vsapi.VirtualServerSpec{Host: "cafe.example.com", ExternalDNS: externalDNS},
+		}),
+		ownerForTransportServer(&vsapi.TransportServer{
+			ObjectMeta: meta,
+			Spec:       vsapi.TransportServerSpec{Host: "tcp.example.com", ExternalDNS: externalDNS},
+		}),
+		ownerForIngress(&networking.Ingress{
+			ObjectMeta: meta,
+			Spec:       networking.IngressSpec{Rules: []networking.IngressRule{{Host: "ingress.example.com"}}},
+		}, externalDNS),
+	}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	lister := extdnsclient.NewDNSEndpointLister(indexer)
+	var names []string
+	for _, owner := range owners {
+		newDNSEndpoint, updateDNSEndpoint, err := buildDNSEndpoint(context.Background(), lister, owner, extdnsapi.Targets{"10.0.0.1"}, recordTypeA)
+		if err != nil {
+			t.Fatalf("buildDNSEndpoint() for %s returned unexpected error: %v", owner.gvk.Kind, err)
+		}
+		if newDNSEndpoint == nil || updateDNSEndpoint != nil {
+			t.Fatalf("buildDNSEndpoint() for %s returned %v, %v, want a new DNSEndpoint", owner.gvk.Kind, newDNSEndpoint, updateDNSEndpoint)
+		}
+		if err := indexer.Add(newDNSEndpoint); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, newDNSEndpoint.Name)
+	}
+
+	want := []string{"cafe", "cafe-transportserver", "cafe-ingress"}
+	if !cmp.Equal(want, names) {
+		t.Error(cmp.Diff(want, names))
+	}
+}
+
+func TestBuildDNSEndpoint_ReturnsErrorOnDNSEndpointOwnedByAnotherResource(t *testing.T) {
+	t.Parallel()
+
+	vs := &vsapi.VirtualServer{
+		ObjectMeta: v1.ObjectMeta{Name: "cafe-transportserver", Namespace: "default", UID: "vs-uid"},
+		Spec:       vsapi.VirtualServerSpec{Host: "cafe.example.com", ExternalDNS: vsapi.ExternalDNS{Enable: true}},
+	}
+	ts := &vsapi.TransportServer{
+		ObjectMeta: v1.ObjectMeta{Name: "cafe", Namespace: "default", UID: "ts-uid"},
+		Spec:       vsapi.TransportServerSpec{Host: "tcp.example.com", ExternalDNS: vsapi.ExternalDNS{Enable: true}},
+	}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	lister := extdnsclient.NewDNSEndpointLister(indexer)
+	vsDNSEndpoint, _, err := buildDNSEndpoint(context.Background(), lister, ownerForVirtualServer(vs), extdnsapi.Targets{"10.0.0.1"}, recordTypeA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := indexer.Add(vsDNSEndpoint); err != nil {
+		t.Fatal(err)
+	}
+
+	newDNSEndpoint, updateDNSEndpoint, err := buildDNSEndpoint(context.Background(), lister, ownerForTransportServer(ts), extdnsapi.Targets{"10.0.0.1"}, recordTypeA)
+	if !errors.Is(err, errDNSEndpointNotOwned) {
+		t.Errorf("want errDNSEndpointNotOwned, got %v", err)
+	}
+	if newDNSEndpoint != nil || updateDNSEndpoint != nil {
+		t.Errorf("want no DNSEndpoint to create or update, got %v, %v", newDNSEndpoint, updateDNSEndpoint)
+	}
+}
+
+func TestDNSEndpointName_TruncatesLongNames(t *testing.T) {
+	t.Parallel()
+
+	ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Name: strings.Repeat("a", 244) + "." + strings.Repeat("b", 8)}}
+
+	name := dnsEndpointName(ing, ingressGVK)
+
+	want := strings.Repeat("a", 244) + "-ingress"
+	if name != want {
+		t.Errorf("dnsEndpointName() returned %q, want %q", name, want)
+	}
+}
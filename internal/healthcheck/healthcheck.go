@@ -18,15 +18,18 @@ import (
 	v1 "k8s.io/api/core/v1"
 
 	"github.com/nginx/kubernetes-ingress/internal/configs"
+	"github.com/nginx/kubernetes-ingress/internal/metrics/collectors"
 	"github.com/nginx/nginx-plus-go-client/v2/client"
 	"k8s.io/utils/strings/slices"
 )
 
 // RunHealthCheck starts the deep healthcheck service.
-func RunHealthCheck(port int, plusClient *client.NginxClient, cnf *configs.Configurator, healthProbeTLSSecret *v1.Secret) {
+// Without the NGINX Plus client, the health of the peers is derived from the configuration
+// and the failed responses recorded by the latency collector.
+func RunHealthCheck(port int, plusClient *client.NginxClient, lc collectors.LatencyCollector, cnf *configs.Configurator, healthProbeTLSSecret *v1.Secret) {
 	l := nl.LoggerFromContext(cnf.CfgParams.Context)
 	addr := fmt.Sprintf(":%s", strconv.Itoa(port))
-	hs, err := NewHealthServer(addr, plusClient, cnf, healthProbeTLSSecret)
+	hs, err := NewHealthServer(addr, plusClient, lc, cnf, healthProbeTLSSecret)
 	if err != nil {
 		nl.Fatal(l, err)
 	}
@@ -40,6 +43,7 @@ type HealthServer struct {
 	Server                 *http.Server
 	URL                    string
 	UpstreamsForHost       func(host string) []string
+	RoutesForHost          func(host string) []configs.RouteUpstreams
 	NginxUpstreams         func(ctx context.Context) (*client.Upstreams, error)
 	StreamUpstreamsForName func(host string) []string
 	NginxStreamUpstreams   func(ctx context.Context) (*client.StreamUpstreams, error)
@@ -47,8 +51,9 @@ type HealthServer struct {
 }
 
 // NewHealthServer creates Health Server. If secret is provided,
-// the server is configured with TLS Config.
-func NewHealthServer(addr string, nc *client.NginxClient, cnf *configs.Configurator, secret *v1.Secret) (*HealthServer, error) {
+// the server is configured with TLS Config. If the NGINX Plus client is nil,
+// the server reports the upstreams of NGINX using the latency collector.
+func NewHealthServer(addr string, nc *client.NginxClient, lc collectors.LatencyCollector, cnf *configs.Configurator, secret *v1.Secret) (*HealthServer, error) {
 	hs := HealthServer{
 		Server: &http.Server{
 			Addr:         addr,
@@ -57,11 +62,18 @@ func NewHealthServer(addr string, nc *client.NginxClient, cnf *configs.Configura
 		},
 		URL:                    fmt.Sprintf("http://%s/", addr),
 		UpstreamsForHost:       cnf.UpstreamsForHost,
-		NginxUpstreams:         nc.GetUpstreams,
+		RoutesForHost:          cnf.RoutesForHost,
 		StreamUpstreamsForName: cnf.StreamUpstreamsForName,
-		NginxStreamUpstreams:   nc.GetStreamUpstreams,
 		Logger:                 nl.LoggerFromContext(cnf.CfgParams.Context),
 	}
+	if nc != nil {
+		hs.NginxUpstreams = nc.GetUpstreams
+		hs.NginxStreamUpstreams = nc.GetStreamUpstreams
+	} else {
+		o := newOSSUpstreams(cnf, lc)
+		hs.NginxUpstreams = o.Upstreams
+		hs.NginxStreamUpstreams = o.StreamUpstreams
+	}
 
 	if secret != nil {
 		tlsCert, err := makeCert(secret)
@@ -82,6 +94,8 @@ func (hs *HealthServer) ListenAndServe() error {
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /probe/{hostname}", hs.UpstreamStats)
 	mux.HandleFunc("GET /probe/ts/{name}", hs.StreamStats)
+	mux.HandleFunc("GET /probe/details/{hostname}", hs.UpstreamDetails)
+	mux.HandleFunc("GET /probe/details/ts/{name}", hs.StreamDetails)
 	hs.Server.Handler = mux
 	if hs.Server.TLSConfig != nil {
 		return hs.Server.ListenAndServeTLS("", "")
@@ -96,6 +110,11 @@ func (hs *HealthServer) Shutdown(ctx context.Context) error {
 
 // UpstreamStats calculates health stats for the host identified by the hostname in the request URL.
 func (hs *HealthServer) UpstreamStats(w http.ResponseWriter, r *http.Request) {
+	minHealthy, err := minHealthyPercentage(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	hostname := r.PathValue("hostname")
 	host := sanitize(hostname)
 
@@ -114,28 +133,61 @@ func (hs *HealthServer) UpstreamStats(w http.ResponseWriter, r *http.Request) {
 	}
 
 	stats := countStats(upstreams, upstreamNames)
-	data, err := json.Marshal(stats)
+	hs.writeStats(w, stats, stats.statusCode(minHealthy))
+}
+
+// UpstreamDetails calculates health stats for the host identified by the hostname in the request URL
+// broken down by the routes, the upstreams and the peers of the host.
+func (hs *HealthServer) UpstreamDetails(w http.ResponseWriter, r *http.Request) {
+	minHealthy, err := minHealthyPercentage(r)
 	if err != nil {
-		nl.Error(hs.Logger, "error marshaling result", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	host := sanitize(r.PathValue("hostname"))
+
+	upstreamNames := hs.UpstreamsForHost(host)
+	if len(upstreamNames) == 0 {
+		nl.Errorf(hs.Logger, "no upstreams for requested hostname %s or hostname does not exist", host)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	upstreams, err := hs.NginxUpstreams(context.Background())
+	if err != nil {
+		nl.Errorf(hs.Logger, "error retrieving upstreams for requested hostname: %s", host)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	switch stats.Up {
-	case 0:
-		w.WriteHeader(http.StatusTeapot)
-	default:
-		w.WriteHeader(http.StatusOK)
+
+	details := HostDetails{
+		HostStats: countStats(upstreams, upstreamNames),
+		Routes:    []RouteStats{},
 	}
-	if _, err = w.Write(data); err != nil {
-		nl.Error(hs.Logger, "error writing result", err)
-		http.Error(w, "internal error", http.StatusInternalServerError)
+	var routes []configs.RouteUpstreams
+	if hs.RoutesForHost != nil {
+		routes = hs.RoutesForHost(host)
+	}
+	for _, route := range routes {
+		rs := RouteStats{Path: route.Path, Upstreams: []UpstreamStats{}}
+		for _, name := range route.Upstreams {
+			us := upstreamStats(name, (*upstreams)[name])
+			rs.Upstreams = append(rs.Upstreams, us)
+			rs.add(us.HostStats)
+		}
+		details.Routes = append(details.Routes, rs)
 	}
+	hs.writeStats(w, details, details.statusCode(minHealthy))
 }
 
 // StreamStats calculates health stats for the TransportServer(s)
 // identified by the service (action) name in the request URL.
 func (hs *HealthServer) StreamStats(w http.ResponseWriter, r *http.Request) {
+	minHealthy, err := minHealthyPercentage(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	name := r.PathValue("name")
 	n := sanitize(name)
 	streamUpstreamNames := hs.StreamUpstreamsForName(n)
@@ -151,6 +203,43 @@ func (hs *HealthServer) StreamStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	stats := countStreamStats(streams, streamUpstreamNames)
+	hs.writeStats(w, stats, stats.statusCode(minHealthy))
+}
+
+// StreamDetails calculates health stats for the TransportServer(s) identified by
+// the service (action) name in the request URL broken down by the upstreams and the peers.
+func (hs *HealthServer) StreamDetails(w http.ResponseWriter, r *http.Request) {
+	minHealthy, err := minHealthyPercentage(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	n := sanitize(r.PathValue("name"))
+	streamUpstreamNames := hs.StreamUpstreamsForName(n)
+	if len(streamUpstreamNames) == 0 {
+		nl.Errorf(hs.Logger, "no stream upstreams for requested name '%s' or name does not exist", n)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	streams, err := hs.NginxStreamUpstreams(context.Background())
+	if err != nil {
+		nl.Errorf(hs.Logger, "error retrieving stream upstreams for requested name: %s", n)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	details := StreamDetails{
+		HostStats: countStreamStats(streams, streamUpstreamNames),
+		Upstreams: []UpstreamStats{},
+	}
+	for _, name := range streamUpstreamNames {
+		details.Upstreams = append(details.Upstreams, streamUpstreamStats(name, (*streams)[name]))
+	}
+	hs.writeStats(w, details, details.statusCode(minHealthy))
+}
+
+// writeStats writes the stats as JSON with the status code.
+func (hs *HealthServer) writeStats(w http.ResponseWriter, stats any, statusCode int) {
 	data, err := json.Marshal(stats)
 	if err != nil {
 		nl.Error(hs.Logger, "error marshaling result", err)
@@ -158,18 +247,27 @@ func (hs *HealthServer) StreamStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	switch stats.Up {
-	case 0:
-		w.WriteHeader(http.StatusTeapot)
-	default:
-		w.WriteHeader(http.StatusOK)
-	}
+	w.WriteHeader(statusCode)
 	if _, err := w.Write(data); err != nil {
 		nl.Error(hs.Logger, "error writing result", err)
 		http.Error(w, "internal error", http.StatusInternalServerError)
 	}
 }
 
+// minHealthyPercentage returns the value of the min-healthy-percentage query parameter of the request.
+// It returns 0 if the parameter is not set.
+func minHealthyPercentage(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("min-healthy-percentage")
+	if v == "" {
+		return 0, nil
+	}
+	p, err := strconv.Atoi(v)
+	if err != nil || p < 0 || p > 100 {
+		return 0, fmt.Errorf("invalid min-healthy-percentage %q: must be an integer between 0 and 100", v)
+	}
+	return p, nil
+}
+
 func sanitize(s string) string {
 	hostname := strings.TrimSpace(s)
 	hostname = strings.ReplaceAll(hostname, "\n", "")
@@ -198,47 +296,148 @@ type HostStats struct {
 	Total     int
 	Up        int
 	Unhealthy int
+	// Unknown is the number of the peers whose state is unknown, because NGINX doesn't report
+	// the failed connections of the TransportServers.
+	Unknown int `json:",omitempty"`
+}
+
+// isHealthy reports whether at least one peer is up and the percentage
+// of the peers that are up is not less than minHealthyPercentage.
+func (s HostStats) isHealthy(minHealthyPercentage int) bool {
+	return s.Up > 0 && s.Up*100 >= minHealthyPercentage*s.Total
+}
+
+// statusCode returns the status code of the response with the stats: 200 if the stats are healthy,
+// 203 if the state of all the peers is unknown, and 418 otherwise.
+func (s HostStats) statusCode(minHealthyPercentage int) int {
+	if s.isHealthy(minHealthyPercentage) {
+		return http.StatusOK
+	}
+	if s.Total > 0 && s.Unknown == s.Total {
+		return http.StatusNonAuthoritativeInfo
+	}
+	return http.StatusTeapot
+}
+
+// PeerStats holds information about a 'peer' of an upstream.
+type PeerStats struct {
+	Server string
+	State  string
+	// HealthCheck is the result of the last active health check of the peer: passed or failed.
+	// It is empty if the peer is not checked.
+	HealthCheck string `json:",omitempty"`
+	// LastFailure is the time of the last failed response of the peer with NGINX,
+	// or the time the peer became unavailable or unhealthy with NGINX Plus.
+	LastFailure string `json:",omitempty"`
+	// ResponseTime is the average time in milliseconds to get the response from the peer.
+	ResponseTime uint64
+}
+
+// UpstreamStats holds information about the 'peers' of an upstream.
+type UpstreamStats struct {
+	Name string
+	HostStats
+	Peers []PeerStats
+}
+
+// RouteStats holds information about the upstreams of a route of a host.
+type RouteStats struct {
+	Path string
+	HostStats
+	Upstreams []UpstreamStats
+}
+
+// HostDetails holds information about the routes of a host.
+type HostDetails struct {
+	HostStats
+	Routes []RouteStats
+}
+
+// StreamDetails holds information about the upstreams of TransportServer(s).
+type StreamDetails struct {
+	HostStats
+	Upstreams []UpstreamStats
+}
+
+// upstreamStats calculates and returns statistics for an upstream.
+func upstreamStats(name string, u client.Upstream) UpstreamStats {
+	stats := UpstreamStats{Name: name, Peers: []PeerStats{}}
+	for _, p := range u.Peers {
+		stats.addPeer(PeerStats{
+			Server:       p.Server,
+			State:        p.State,
+			HealthCheck:  healthCheckResult(p.HealthChecks),
+			LastFailure:  p.Downstart,
+			ResponseTime: p.ResponseTime,
+		})
+	}
+	return stats
+}
+
+// streamUpstreamStats calculates and returns statistics for a stream upstream.
+func streamUpstreamStats(name string, s client.StreamUpstream) UpstreamStats {
+	stats := UpstreamStats{Name: name, Peers: []PeerStats{}}
+	for _, p := range s.Peers {
+		stats.addPeer(PeerStats{
+			Server:       p.Server,
+			State:        p.State,
+			HealthCheck:  healthCheckResult(p.HealthChecks),
+			LastFailure:  p.Downstart,
+			ResponseTime: p.ResponseTime,
+		})
+	}
+	return stats
+}
+
+// addPeer adds the peer to the upstream and counts it by its state.
+func (s *UpstreamStats) addPeer(p PeerStats) {
+	s.Peers = append(s.Peers, p)
+	s.Total++
+	switch strings.ToLower(p.State) {
+	case "up":
+		s.Up++
+	case peerStateUnknown:
+		s.Unknown++
+	}
+	s.Unhealthy = s.Total - s.Up - s.Unknown
+}
+
+// add adds the counts of the other stats to the stats.
+func (s *HostStats) add(other HostStats) {
+	s.Total += other.Total
+	s.Up += other.Up
+	s.Unhealthy += other.Unhealthy
+	s.Unknown += other.Unknown
+}
+
+func healthCheckResult(hc client.HealthChecks) string {
+	if hc.Checks == 0 {
+		return ""
+	}
+	if hc.LastPassed {
+		return "passed"
+	}
+	return "failed"
 }
 
 // countStats calculates and returns statistics for a host.
 func countStats(upstreams *client.Upstreams, upstreamNames []string) HostStats {
-	total, up := 0, 0
+	var stats HostStats
 	for name, u := range *upstreams {
-		if !slices.Contains(upstreamNames, name) {
-			continue
-		}
-		for _, p := range u.Peers {
-			total++
-			if strings.ToLower(p.State) != "up" {
-				continue
-			}
-			up++
+		if slices.Contains(upstreamNames, name) {
+			stats.add(upstreamStats(name, u).HostStats)
 		}
 	}
-	return HostStats{
-		Total:     total,
-		Up:        up,
-		Unhealthy: total - up,
-	}
+	return stats
 }
 
+// countStreamStats calculates and returns statistics for TransportServer(s).
 func countStreamStats(streams *client.StreamUpstreams, streamUpstreamNames []string) HostStats {
-	total, up := 0, 0
+	var stats HostStats
 	for name, s := range *streams {
-		if !slices.Contains(streamUpstreamNames, name) {
-			continue
+		if slices.Contains(streamUpstreamNames, name) {
+			stats.add(streamUpstreamStats(name, s).HostStats)
 		}
-		for _, p := range s.Peers {
-			total++
-			if strings.ToLower(p.State) != "up" {
-				continue
-			}
-			up++
-		}
-	}
-	return HostStats{
-		Total:     total,
-		Up:        up,
-		Unhealthy: total - up,
 	}
+	return stats
 }
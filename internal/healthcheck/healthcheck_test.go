@@ -14,6 +14,7 @@ import (
 	"github.com/nginx/kubernetes-ingress/internal/logger/levels"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/nginx/kubernetes-ingress/internal/configs"
 	"github.com/nginx/kubernetes-ingress/internal/healthcheck"
 	"github.com/nginx/nginx-plus-go-client/v2/client"
 )
@@ -23,6 +24,8 @@ func testHandler(hs *healthcheck.HealthServer) http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /probe/{hostname}", hs.UpstreamStats)
 	mux.HandleFunc("GET /probe/ts/{name}", hs.StreamStats)
+	mux.HandleFunc("GET /probe/details/{hostname}", hs.UpstreamDetails)
+	mux.HandleFunc("GET /probe/details/ts/{name}", hs.StreamDetails)
 	return mux
 }
 
@@ -337,6 +340,180 @@ func TestHealthCheckServer_ReturnsCorrectTransportServerStatsForNameOnAllPeersDo
 	}
 }
 
+func TestHealthCheckServer_Returns203ForTransportServerOnAllPeersUnknown(t *testing.T) {
+	hs := healthcheck.HealthServer{
+		StreamUpstreamsForName: streamUpstreamsForName,
+		NginxStreamUpstreams:   streamUpstreamsFromNGINXAllPeersUnknown,
+		Logger:                 slog.New(nic_glog.New(io.Discard, &nic_glog.Options{Level: levels.LevelInfo})),
+	}
+
+	ts := httptest.NewServer(testHandler(&hs))
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/probe/ts/bar-app") //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusNonAuthoritativeInfo {
+		t.Fatal(resp.StatusCode)
+	}
+
+	want := healthcheck.HostStats{
+		Total:     2,
+		Up:        0,
+		Unhealthy: 0,
+		Unknown:   2,
+	}
+	var got healthcheck.HostStats
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestHealthCheckServer_RespondsWithStatusCodeBasedOnMinHealthyPercentage(t *testing.T) {
+	hs := healthcheck.HealthServer{
+		UpstreamsForHost:       getUpstreamsForHost,
+		NginxUpstreams:         getUpstreamsFromNGINXPartiallyUp,
+		StreamUpstreamsForName: streamUpstreamsForName,
+		NginxStreamUpstreams:   streamUpstreamsFromNGINXPartiallyUp,
+		Logger:                 slog.New(nic_glog.New(io.Discard, &nic_glog.Options{Level: levels.LevelInfo})),
+	}
+
+	ts := httptest.NewServer(testHandler(&hs))
+	defer ts.Close()
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "/probe/bar.tea.com?min-healthy-percentage=30", want: http.StatusOK},
+		{path: "/probe/bar.tea.com?min-healthy-percentage=50", want: http.StatusTeapot},
+		{path: "/probe/bar.tea.com?min-healthy-percentage=101", want: http.StatusBadRequest},
+		{path: "/probe/bar.tea.com?min-healthy-percentage=bogus", want: http.StatusBadRequest},
+		{path: "/probe/ts/bar-app?min-healthy-percentage=60", want: http.StatusOK},
+		{path: "/probe/ts/bar-app?min-healthy-percentage=70", want: http.StatusTeapot},
+		{path: "/probe/details/bar.tea.com?min-healthy-percentage=50", want: http.StatusTeapot},
+		{path: "/probe/details/ts/bar-app?min-healthy-percentage=70", want: http.StatusTeapot},
+		{path: "/probe/details/ts/bar-app?min-healthy-percentage=-1", want: http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		resp, err := ts.Client().Get(ts.URL + test.path) //nolint:noctx
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != test.want {
+			t.Errorf("GET %s returned %d, want %d", test.path, resp.StatusCode, test.want)
+		}
+	}
+}
+
+func TestHealthCheckServer_ReturnsDetailsForHostnameByRoutes(t *testing.T) {
+	hs := healthcheck.HealthServer{
+		UpstreamsForHost: getUpstreamsForHost,
+		RoutesForHost:    getRoutesForHost,
+		NginxUpstreams:   getUpstreamsFromNGINXWithDetails,
+		Logger:           slog.New(nic_glog.New(io.Discard, &nic_glog.Options{Level: levels.LevelInfo})),
+	}
+
+	ts := httptest.NewServer(testHandler(&hs))
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/probe/details/foo.tea.com") //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatal(resp.StatusCode)
+	}
+
+	want := healthcheck.HostDetails{
+		HostStats: healthcheck.HostStats{Total: 3, Up: 2, Unhealthy: 1},
+		Routes: []healthcheck.RouteStats{
+			{
+				Path:      "/tea",
+				HostStats: healthcheck.HostStats{Total: 2, Up: 1, Unhealthy: 1},
+				Upstreams: []healthcheck.UpstreamStats{
+					{
+						Name:      "upstream1",
+						HostStats: healthcheck.HostStats{Total: 2, Up: 1, Unhealthy: 1},
+						Peers: []healthcheck.PeerStats{
+							{Server: "10.0.0.1:80", State: "up", HealthCheck: "passed", ResponseTime: 12},
+							{Server: "10.0.0.2:80", State: "unhealthy", HealthCheck: "failed", LastFailure: "2024-01-01T00:00:00.000Z"},
+						},
+					},
+				},
+			},
+			{
+				Path:      "/coffee",
+				HostStats: healthcheck.HostStats{Total: 1, Up: 1, Unhealthy: 0},
+				Upstreams: []healthcheck.UpstreamStats{
+					{
+						Name:      "upstream2",
+						HostStats: healthcheck.HostStats{Total: 1, Up: 1, Unhealthy: 0},
+						Peers: []healthcheck.PeerStats{
+							{Server: "10.0.0.3:80", State: "up", ResponseTime: 5},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var got healthcheck.HostDetails
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestHealthCheckServer_ReturnsTransportServerDetailsByUpstreams(t *testing.T) {
+	hs := healthcheck.HealthServer{
+		StreamUpstreamsForName: streamUpstreamsForName,
+		NginxStreamUpstreams:   streamUpstreamsFromNGINXAllPeersDown,
+		Logger:                 slog.New(nic_glog.New(io.Discard, &nic_glog.Options{Level: levels.LevelInfo})),
+	}
+
+	ts := httptest.NewServer(testHandler(&hs))
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/probe/details/ts/foo-app") //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusTeapot {
+		t.Fatal(resp.StatusCode)
+	}
+
+	down := []healthcheck.PeerStats{{State: "Down"}, {State: "Down"}, {State: "Down"}}
+	want := healthcheck.StreamDetails{
+		HostStats: healthcheck.HostStats{Total: 6, Up: 0, Unhealthy: 6},
+		Upstreams: []healthcheck.UpstreamStats{
+			{Name: "streamUpstream1", HostStats: healthcheck.HostStats{Total: 3, Up: 0, Unhealthy: 3}, Peers: down},
+			{Name: "streamUpstream2", HostStats: healthcheck.HostStats{Total: 3, Up: 0, Unhealthy: 3}, Peers: down},
+		},
+	}
+
+	var got healthcheck.StreamDetails
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
 // getUpstreamsForHost is a helper func faking response from IC.
 func getUpstreamsForHost(host string) []string {
 	upstreams := map[string][]string{
@@ -350,6 +527,47 @@ func getUpstreamsForHost(host string) []string {
 	return u
 }
 
+// getRoutesForHost is a helper func faking response from IC.
+func getRoutesForHost(host string) []configs.RouteUpstreams {
+	routes := map[string][]configs.RouteUpstreams{
+		"foo.tea.com": {
+			{Path: "/tea", Upstreams: []string{"upstream1"}},
+			{Path: "/coffee", Upstreams: []string{"upstream2"}},
+		},
+	}
+	return routes[host]
+}
+
+// getUpstreamsFromNGINXWithDetails is a helper func used
+// for faking response data from NGINX API. It responds
+// with 'peers' with active health checks and response times.
+func getUpstreamsFromNGINXWithDetails(_ context.Context) (*client.Upstreams, error) {
+	ups := client.Upstreams{
+		"upstream1": client.Upstream{
+			Peers: []client.Peer{
+				{
+					Server:       "10.0.0.1:80",
+					State:        "up",
+					HealthChecks: client.HealthChecks{Checks: 10, LastPassed: true},
+					ResponseTime: 12,
+				},
+				{
+					Server:       "10.0.0.2:80",
+					State:        "unhealthy",
+					Downstart:    "2024-01-01T00:00:00.000Z",
+					HealthChecks: client.HealthChecks{Checks: 10, Fails: 3, Unhealthy: 1},
+				},
+			},
+		},
+		"upstream2": client.Upstream{
+			Peers: []client.Peer{
+				{Server: "10.0.0.3:80", State: "up", ResponseTime: 5},
+			},
+		},
+	}
+	return &ups, nil
+}
+
 // getUpstreamsFromNGINXAllUP is a helper func used
 // for faking response data from NGINX API. It responds
 // with all upstreams and 'peers' in 'Up' state.
@@ -551,3 +769,19 @@ func streamUpstreamsFromNGINXAllPeersDown(_ context.Context) (*client.StreamUpst
 	}
 	return &streamUpstreams, nil
 }
+
+// streamUpstreamsFromNGINXAllPeersUnknown is a helper func
+// for faking response from NGINX, which doesn't report the state of the stream peers.
+//
+//nolint:unparam
+func streamUpstreamsFromNGINXAllPeersUnknown(_ context.Context) (*client.StreamUpstreams, error) {
+	streamUpstreams := client.StreamUpstreams{
+		"streamUpstream1": client.StreamUpstream{
+			Peers: []client.StreamPeer{
+				{State: "unknown"},
+				{State: "unknown"},
+			},
+		},
+	}
+	return &streamUpstreams, nil
+}
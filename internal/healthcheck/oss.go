@@ -0,0 +1,147 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/nginx/kubernetes-ingress/internal/configs"
+	"github.com/nginx/kubernetes-ingress/internal/metrics/collectors"
+	"github.com/nginx/nginx-plus-go-client/v2/client"
+)
+
+// defaultFailTimeout is the default value of the fail_timeout parameter of the NGINX server directive.
+const defaultFailTimeout = 10 * time.Second
+
+// downstartFormat is the format of the time of the downstart field of the peers in the NGINX Plus API.
+const downstartFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// peerStateUnknown is the state of the peers whose failures are not reported by NGINX.
+const peerStateUnknown = "unknown"
+
+// ossUpstreams reports the state of the upstream peers for NGINX, which has no API.
+// The peers are the servers of the upstreams generated by the Ingress Controller from the EndpointSlices.
+// A peer is unavailable when NGINX would consider it unavailable because of passive health checks:
+// max_fails consecutive unsuccessful attempts happened within fail_timeout.
+// The downstart of a peer is the time of its last unsuccessful attempt.
+type ossUpstreams struct {
+	upstreamPeers       func() map[string][]configs.UpstreamPeer
+	streamUpstreamPeers func() map[string][]configs.UpstreamPeer
+	peerStats           func(peer string) collectors.UpstreamServerPeerStats
+	now                 func() time.Time
+}
+
+func newOSSUpstreams(cnf *configs.Configurator, lc collectors.LatencyCollector) *ossUpstreams {
+	if lc == nil {
+		lc = collectors.NewLatencyFakeCollector()
+	}
+	return &ossUpstreams{
+		upstreamPeers:       cnf.UpstreamPeers,
+		streamUpstreamPeers: cnf.StreamUpstreamPeers,
+		peerStats:           lc.UpstreamServerPeerStats,
+		now:                 time.Now,
+	}
+}
+
+// Upstreams returns the HTTP upstreams in the format of the NGINX Plus API.
+func (o *ossUpstreams) Upstreams(_ context.Context) (*client.Upstreams, error) {
+	upstreams := make(client.Upstreams)
+	for name, peers := range o.upstreamPeers() {
+		u := client.Upstream{Peers: []client.Peer{}}
+		for _, p := range peers {
+			u.Peers = append(u.Peers, o.peer(name, p))
+		}
+		upstreams[name] = u
+	}
+	return &upstreams, nil
+}
+
+// StreamUpstreams returns the stream upstreams in the format of the NGINX Plus API.
+// The failed connections are not reported by NGINX, so the state of all the peers is unknown.
+func (o *ossUpstreams) StreamUpstreams(_ context.Context) (*client.StreamUpstreams, error) {
+	streams := make(client.StreamUpstreams)
+	for name, peers := range o.streamUpstreamPeers() {
+		s := client.StreamUpstream{Peers: []client.StreamPeer{}}
+		for _, p := range peers {
+			s.Peers = append(s.Peers, client.StreamPeer{Server: p.Address, State: peerStateUnknown})
+		}
+		streams[name] = s
+	}
+	return &streams, nil
+}
+
+func (o *ossUpstreams) peer(upstream string, p configs.UpstreamPeer) client.Peer {
+	stats := o.peerStats(fmt.Sprintf("%s/%s", upstream, p.Address))
+	fails, lastFailure := peerFails(p, stats.Failures)
+	peer := client.Peer{
+		Server: p.Address,
+		State:  o.peerState(p, fails, lastFailure),
+	}
+	if !lastFailure.IsZero() {
+		peer.Downstart = lastFailure.UTC().Format(downstartFormat)
+	}
+	if stats.Responses > 0 {
+		peer.ResponseTime = uint64(stats.ResponseTimeSum.Milliseconds()) / stats.Responses
+	}
+	return peer
+}
+
+func (o *ossUpstreams) peerState(p configs.UpstreamPeer, fails int, lastFailure time.Time) string {
+	if p.MaxFails <= 0 {
+		return "up"
+	}
+	if fails < p.MaxFails {
+		return "up"
+	}
+	if o.now().Sub(lastFailure) >= parseFailTimeout(p.FailTimeout) {
+		return "up"
+	}
+	return "unavail"
+}
+
+// peerFails returns the number of the consecutive unsuccessful attempts of the peer since its last successful attempt
+// and the time of the last unsuccessful attempt.
+func peerFails(p configs.UpstreamPeer, failures []collectors.UpstreamServerPeerFailure) (int, time.Time) {
+	fails := 0
+	var lastFailure time.Time
+	for i := len(failures) - 1; i >= 0; i-- {
+		if !isUnsuccessfulAttempt(failures[i].Code, p.NextUpstream) {
+			break
+		}
+		if fails == 0 {
+			lastFailure = failures[i].Time
+		}
+		fails++
+	}
+	return fails, lastFailure
+}
+
+// isUnsuccessfulAttempt reports whether NGINX considers the attempt with the status code unsuccessful
+// for the max_fails parameter. The errors and the timeouts, reported with the 502 and 504 status codes,
+// are always unsuccessful. The responses with the 500, 503 and 429 status codes are unsuccessful only if
+// the conditions of the proxy_next_upstream directive include them. The 502 and 504 responses of the servers
+// can't be told apart from the errors and the timeouts, so they are always unsuccessful too.
+func isUnsuccessfulAttempt(code string, nextUpstream []string) bool {
+	switch code {
+	case "502", "504":
+		return true
+	case "500", "503", "429":
+		return slices.Contains(nextUpstream, "http_"+code)
+	}
+	return false
+}
+
+// parseFailTimeout converts the NGINX time of the fail_timeout parameter to a duration.
+// It returns the default of NGINX if the time is empty or uses units not supported by time.ParseDuration.
+func parseFailTimeout(s string) time.Duration {
+	t, err := configs.ParseTime(s)
+	if err != nil {
+		return defaultFailTimeout
+	}
+	d, err := time.ParseDuration(t)
+	if err != nil {
+		return defaultFailTimeout
+	}
+	return d
+}
@@ -0,0 +1,173 @@
+package healthcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nginx/kubernetes-ingress/internal/configs"
+	"github.com/nginx/kubernetes-ingress/internal/metrics/collectors"
+	"github.com/nginx/nginx-plus-go-client/v2/client"
+)
+
+func TestOSSUpstreams_ReportsPeersUnavailableAfterMaxFailsWithinFailTimeout(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	o := &ossUpstreams{
+		upstreamPeers: func() map[string][]configs.UpstreamPeer {
+			return map[string][]configs.UpstreamPeer{
+				"vs_default_cafe_tea": {
+					{Address: "10.0.0.1:80", MaxFails: 1, FailTimeout: "10s"},
+					{Address: "10.0.0.2:80", MaxFails: 1, FailTimeout: "10s"},
+					{Address: "10.0.0.3:80", MaxFails: 3, FailTimeout: "10s"},
+					{Address: "10.0.0.4:80", MaxFails: 1, FailTimeout: "10s"},
+					{Address: "10.0.0.5:80", MaxFails: 0, FailTimeout: "10s"},
+				},
+			}
+		},
+		peerStats: func(peer string) collectors.UpstreamServerPeerStats {
+			return map[string]collectors.UpstreamServerPeerStats{
+				// failed within fail timeout
+				"vs_default_cafe_tea/10.0.0.2:80": {
+					Failures:        failures(now.Add(-5*time.Second), "502"),
+					Responses:       2,
+					ResponseTimeSum: 40 * time.Millisecond,
+				},
+				// fewer failures than max fails
+				"vs_default_cafe_tea/10.0.0.3:80": {Failures: failures(now.Add(-5*time.Second), "502", "504")},
+				// failed before fail timeout
+				"vs_default_cafe_tea/10.0.0.4:80": {Failures: failures(now.Add(-10*time.Second), "502")},
+				// max fails disables the accounting of failures
+				"vs_default_cafe_tea/10.0.0.5:80": {Failures: failures(now.Add(-5*time.Second), "502", "502", "502", "502", "502")},
+			}[peer]
+		},
+		now: func() time.Time { return now },
+	}
+
+	want := &client.Upstreams{
+		"vs_default_cafe_tea": client.Upstream{
+			Peers: []client.Peer{
+				{Server: "10.0.0.1:80", State: "up"},
+				{Server: "10.0.0.2:80", State: "unavail", Downstart: "2023-12-31T23:59:55.000Z", ResponseTime: 20},
+				{Server: "10.0.0.3:80", State: "up", Downstart: "2023-12-31T23:59:55.000Z"},
+				{Server: "10.0.0.4:80", State: "up", Downstart: "2023-12-31T23:59:50.000Z"},
+				{Server: "10.0.0.5:80", State: "up", Downstart: "2023-12-31T23:59:55.000Z"},
+			},
+		},
+	}
+	got, err := o.Upstreams(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestOSSUpstreams_CountsUnsuccessfulAttemptsOfProxyNextUpstream(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	o := &ossUpstreams{
+		upstreamPeers: func() map[string][]configs.UpstreamPeer {
+			return map[string][]configs.UpstreamPeer{
+				"vs_default_cafe_tea": {
+					{Address: "10.0.0.1:80", MaxFails: 2, FailTimeout: "10s", NextUpstream: []string{"error", "timeout"}},
+					{Address: "10.0.0.2:80", MaxFails: 2, FailTimeout: "10s", NextUpstream: []string{"error", "timeout", "http_500"}},
+					{Address: "10.0.0.3:80", MaxFails: 2, FailTimeout: "10s", NextUpstream: []string{"error", "timeout", "http_500"}},
+				},
+			}
+		},
+		peerStats: func(peer string) collectors.UpstreamServerPeerStats {
+			return map[string]collectors.UpstreamServerPeerStats{
+				// 500 is not an unsuccessful attempt without http_500
+				"vs_default_cafe_tea/10.0.0.1:80": {Failures: failures(now.Add(-5*time.Second), "500", "500")},
+				"vs_default_cafe_tea/10.0.0.2:80": {Failures: failures(now.Add(-5*time.Second), "500", "502")},
+				// 501 is a successful attempt that resets the failures
+				"vs_default_cafe_tea/10.0.0.3:80": {Failures: failures(now.Add(-5*time.Second), "502", "501", "500")},
+			}[peer]
+		},
+		now: func() time.Time { return now },
+	}
+
+	want := &client.Upstreams{
+		"vs_default_cafe_tea": client.Upstream{
+			Peers: []client.Peer{
+				{Server: "10.0.0.1:80", State: "up"},
+				{Server: "10.0.0.2:80", State: "unavail", Downstart: "2023-12-31T23:59:55.000Z"},
+				{Server: "10.0.0.3:80", State: "up", Downstart: "2023-12-31T23:59:55.000Z"},
+			},
+		},
+	}
+	got, err := o.Upstreams(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+// failures returns the failures with the status codes at the time.
+func failures(at time.Time, codes ...string) []collectors.UpstreamServerPeerFailure {
+	var result []collectors.UpstreamServerPeerFailure
+	for _, c := range codes {
+		result = append(result, collectors.UpstreamServerPeerFailure{Code: c, Time: at})
+	}
+	return result
+}
+
+func TestOSSUpstreams_ReportsStreamPeersUnknown(t *testing.T) {
+	t.Parallel()
+
+	o := &ossUpstreams{
+		streamUpstreamPeers: func() map[string][]configs.UpstreamPeer {
+			return map[string][]configs.UpstreamPeer{
+				"ts_default_dns_dns-app": {
+					{Address: "10.0.0.1:5353", MaxFails: 1, FailTimeout: "10s"},
+				},
+				"ts_default_dns_dns-backup": {},
+			}
+		},
+	}
+
+	want := &client.StreamUpstreams{
+		"ts_default_dns_dns-app": client.StreamUpstream{
+			Peers: []client.StreamPeer{{Server: "10.0.0.1:5353", State: "unknown"}},
+		},
+		"ts_default_dns_dns-backup": client.StreamUpstream{
+			Peers: []client.StreamPeer{},
+		},
+	}
+	got, err := o.StreamUpstreams(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestParseFailTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{input: "30s", want: 30 * time.Second},
+		{input: "30", want: 30 * time.Second},
+		{input: "1m30s", want: 90 * time.Second},
+		{input: "500ms", want: 500 * time.Millisecond},
+		{input: "", want: defaultFailTimeout},
+		{input: "1d", want: defaultFailTimeout},
+		{input: "bogus", want: defaultFailTimeout},
+	}
+	for _, test := range tests {
+		if got := parseFailTimeout(test.input); got != test.want {
+			t.Errorf("parseFailTimeout(%q) returned %v, want %v", test.input, got, test.want)
+		}
+	}
+}
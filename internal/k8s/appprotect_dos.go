@@ -6,6 +6,7 @@ import (
 
 	"github.com/nginx/kubernetes-ingress/internal/k8s/appprotectdos"
 	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
 	"github.com/nginx/kubernetes-ingress/pkg/apis/dos/v1beta1"
 	api_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -217,6 +218,7 @@ func (lbc *LoadBalancerController) processAppProtectDosChanges(changes []appprot
 				lbc.updateResourcesStatusAndEvents(resources, warnings, err)
 				msg := fmt.Sprintf("Configuration for %s/%s was added or updated", impl.Obj.Namespace, impl.Obj.Name)
 				lbc.recorder.Event(impl.Obj, api_v1.EventTypeNormal, "AddedOrUpdated", msg)
+				lbc.updateDosProtectedResourceStatus(impl)
 			case *appprotectdos.DosPolicyEx:
 				msg := "Configuration was added or updated"
 				lbc.recorder.Event(impl.Obj, api_v1.EventTypeNormal, "AddedOrUpdated", msg)
@@ -245,11 +247,61 @@ func (lbc *LoadBalancerController) processAppProtectDosChanges(changes []appprot
 				resourceExes := lbc.createExtendedResources(resources)
 				warnings, err := lbc.configurator.AddOrUpdateResourcesThatUseDosProtected(resourceExes.IngressExes, resourceExes.MergeableIngresses, resourceExes.VirtualServerExes)
 				lbc.updateResourcesStatusAndEvents(resources, warnings, err)
+				// the resource is removed from the configuration only when it is deleted;
+				// otherwise, it was rejected and its status must report why
+				if !impl.IsValid || lbc.dosConfiguration.CheckDosProtectedResourceReferences(impl) != nil {
+					lbc.updateDosProtectedResourceStatus(impl)
+				}
 			}
 		}
 	}
 }
 
+// updateDosProtectedResourceStatus updates the status of the DosProtectedResource, including the list of
+// the Ingresses and VirtualServers that reference it.
+func (lbc *LoadBalancerController) updateDosProtectedResourceStatus(protectedEx *appprotectdos.DosProtectedResourceEx) {
+	if !lbc.reportCustomResourceStatusEnabled() {
+		return
+	}
+
+	pr := protectedEx.Obj
+	state := conf_v1.StateValid
+	reason := "AddedOrUpdated"
+	msg := fmt.Sprintf("DosProtectedResource %s/%s was added or updated", pr.Namespace, pr.Name)
+
+	if !protectedEx.IsValid {
+		state = conf_v1.StateInvalid
+		reason = "Rejected"
+		msg = protectedEx.ErrorMsg
+	} else if err := lbc.dosConfiguration.CheckDosProtectedResourceReferences(protectedEx); err != nil {
+		state = conf_v1.StateInvalid
+		reason = "Rejected"
+		msg = err.Error()
+	}
+
+	var referencedBy []string
+	for _, r := range lbc.configuration.FindResourcesForAppProtectDosProtected(pr.Namespace, pr.Name) {
+		referencedBy = append(referencedBy, r.GetKeyWithKind())
+	}
+
+	err := lbc.statusUpdater.UpdateDosProtectedResourceStatus(pr, state, reason, msg, referencedBy)
+	if err != nil {
+		nl.Debugf(lbc.Logger, "Failed to update DosProtectedResource %s/%s status: %v", pr.Namespace, pr.Name, err)
+	}
+}
+
+// updateDosProtectedResourcesStatus updates the status of all DosProtectedResources, so that the list of
+// the resources that reference them stays up to date when Ingresses and VirtualServers change.
+func (lbc *LoadBalancerController) updateDosProtectedResourcesStatus() {
+	if !lbc.appProtectDosEnabled {
+		return
+	}
+
+	for _, protectedEx := range lbc.dosConfiguration.GetDosProtectedResources() {
+		lbc.updateDosProtectedResourceStatus(protectedEx)
+	}
+}
+
 func (lbc *LoadBalancerController) processAppProtectDosProblems(problems []appprotectdos.Problem) {
 	nl.Debugf(lbc.Logger, "Processing %v App Protect Dos problems", len(problems))
 
@@ -2,6 +2,7 @@ package appprotectdos
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/nginx/kubernetes-ingress/internal/configs"
@@ -164,6 +165,15 @@ func (ci *Configuration) AddOrUpdateDosProtectedResource(protectedConf *v1beta1.
 		return []Change{{Op: Delete, Resource: protectedEx}},
 			[]Problem{{Object: protectedConf, Reason: "Rejected", Message: err.Error()}}
 	}
+	if err := ci.CheckDosProtectedResourceReferences(protectedEx); err != nil {
+		return []Change{{Op: Delete, Resource: protectedEx}},
+			[]Problem{{Object: protectedConf, Reason: "Rejected", Message: err.Error()}}
+	}
+	return []Change{{Op: AddOrUpdate, Resource: protectedEx}}, nil
+}
+
+// CheckDosProtectedResourceReferences checks that the DosPolicy and DosLogConf referenced by the DosProtectedResource exist and are valid
+func (ci *Configuration) CheckDosProtectedResourceReferences(protectedEx *DosProtectedResourceEx) error {
 	if protectedEx.Obj.Spec.ApDosPolicy != "" {
 		policyReference := protectedEx.Obj.Spec.ApDosPolicy
 		// if the policy reference does not have a namespace, use the dos protected' namespace
@@ -172,8 +182,7 @@ func (ci *Configuration) AddOrUpdateDosProtectedResource(protectedConf *v1beta1.
 		}
 		_, err := ci.getPolicy(policyReference)
 		if err != nil {
-			return []Change{{Op: Delete, Resource: protectedEx}},
-				[]Problem{{Object: protectedConf, Reason: "Rejected", Message: fmt.Sprintf("dos protected refers (%s) to an invalid DosPolicy: %s", policyReference, err.Error())}}
+			return fmt.Errorf("dos protected refers (%s) to an invalid DosPolicy: %s", policyReference, err.Error())
 		}
 	}
 	if protectedEx.Obj.Spec.DosSecurityLog != nil && protectedEx.Obj.Spec.DosSecurityLog.ApDosLogConf != "" {
@@ -184,11 +193,10 @@ func (ci *Configuration) AddOrUpdateDosProtectedResource(protectedConf *v1beta1.
 		}
 		_, err := ci.getLogConf(logConfReference)
 		if err != nil {
-			return []Change{{Op: Delete, Resource: protectedEx}},
-				[]Problem{{Object: protectedConf, Reason: "Rejected", Message: fmt.Sprintf("dos protected refers (%s) to an invalid DosLogConf: %s", logConfReference, err.Error())}}
+			return fmt.Errorf("dos protected refers (%s) to an invalid DosLogConf: %s", logConfReference, err.Error())
 		}
 	}
-	return []Change{{Op: AddOrUpdate, Resource: protectedEx}}, nil
+	return nil
 }
 
 func (ci *Configuration) getPolicy(key string) (*unstructured.Unstructured, error) {
@@ -272,6 +280,21 @@ func getNsName(defaultNamespace string, name string) string {
 	return name
 }
 
+// GetDosProtectedResources returns all DosProtectedResources sorted by namespace and name
+func (ci *Configuration) GetDosProtectedResources() []*DosProtectedResourceEx {
+	keys := make([]string, 0, len(ci.dosProtectedResource))
+	for key := range ci.dosProtectedResource {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	protectedResources := make([]*DosProtectedResourceEx, 0, len(keys))
+	for _, key := range keys {
+		protectedResources = append(protectedResources, ci.dosProtectedResource[key])
+	}
+	return protectedResources
+}
+
 // GetDosProtectedThatReferencedDosPolicy gets dos protected resources that mention the given dos policy
 func (ci *Configuration) GetDosProtectedThatReferencedDosPolicy(key string) []*v1beta1.DosProtectedResource {
 	var protectedResources []*v1beta1.DosProtectedResource
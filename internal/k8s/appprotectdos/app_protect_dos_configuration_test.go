@@ -582,6 +582,91 @@ func TestGetDosProtected(t *testing.T) {
 	}
 }
 
+func TestGetDosProtectedResources(t *testing.T) {
+	t.Parallel()
+	appProtectConfiguration := NewConfiguration(true)
+	first := &DosProtectedResourceEx{IsValid: true, Obj: &v1beta1.DosProtectedResource{ObjectMeta: v1.ObjectMeta{Namespace: "default", Name: "first"}}}
+	second := &DosProtectedResourceEx{IsValid: false, Obj: &v1beta1.DosProtectedResource{ObjectMeta: v1.ObjectMeta{Namespace: "testing", Name: "second"}}, ErrorMsg: "Validation Failed"}
+	appProtectConfiguration.dosProtectedResource["testing/second"] = second
+	appProtectConfiguration.dosProtectedResource["default/first"] = first
+
+	expected := []*DosProtectedResourceEx{first, second}
+
+	result := appProtectConfiguration.GetDosProtectedResources()
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("GetDosProtectedResources() returned unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestCheckDosProtectedResourceReferences(t *testing.T) {
+	t.Parallel()
+	appProtectConfiguration := NewConfiguration(true)
+	appProtectConfiguration.dosPolicies["testing/valid-policy"] = &DosPolicyEx{IsValid: true, Obj: &unstructured.Unstructured{}}
+	appProtectConfiguration.dosPolicies["testing/invalid-policy"] = &DosPolicyEx{IsValid: false, Obj: &unstructured.Unstructured{}, ErrorMsg: "Validation Failed"}
+	appProtectConfiguration.dosLogConfs["testing/valid-logconf"] = &DosLogConfEx{IsValid: true, Obj: &unstructured.Unstructured{}}
+
+	tests := []struct {
+		spec    v1beta1.DosProtectedResourceSpec
+		wantErr bool
+		errMsg  string
+		msg     string
+	}{
+		{
+			spec: v1beta1.DosProtectedResourceSpec{},
+			msg:  "no references",
+		},
+		{
+			spec: v1beta1.DosProtectedResourceSpec{
+				ApDosPolicy: "valid-policy",
+				DosSecurityLog: &v1beta1.DosSecurityLog{
+					ApDosLogConf: "testing/valid-logconf",
+				},
+			},
+			msg: "valid references",
+		},
+		{
+			spec: v1beta1.DosProtectedResourceSpec{
+				ApDosPolicy: "invalid-policy",
+			},
+			wantErr: true,
+			errMsg:  "dos protected refers (testing/invalid-policy) to an invalid DosPolicy: Validation Failed",
+			msg:     "invalid policy",
+		},
+		{
+			spec: v1beta1.DosProtectedResourceSpec{
+				ApDosPolicy: "valid-policy",
+				DosSecurityLog: &v1beta1.DosSecurityLog{
+					ApDosLogConf: "default/missing-logconf",
+				},
+			},
+			wantErr: true,
+			errMsg:  "dos protected refers (default/missing-logconf) to an invalid DosLogConf: DosLogConf default/missing-logconf not found",
+			msg:     "missing log conf",
+		},
+	}
+
+	for _, test := range tests {
+		protectedEx := &DosProtectedResourceEx{
+			Obj: &v1beta1.DosProtectedResource{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "testing",
+					Name:      "dos-protected",
+				},
+				Spec: test.spec,
+			},
+			IsValid: true,
+		}
+
+		err := appProtectConfiguration.CheckDosProtectedResourceReferences(protectedEx)
+		if (err != nil) != test.wantErr {
+			t.Errorf("CheckDosProtectedResourceReferences() returned %v on case %s", err, test.msg)
+		}
+		if test.wantErr && err != nil && test.errMsg != err.Error() {
+			t.Errorf("CheckDosProtectedResourceReferences() returned error message '%s' on case '%s' (expected '%s')", err.Error(), test.msg, test.errMsg)
+		}
+	}
+}
+
 func TestGetPolicy(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
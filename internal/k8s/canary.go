@@ -0,0 +1,532 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nginx/kubernetes-ingress/internal/configs"
+	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	"github.com/nginx/kubernetes-ingress/internal/metrics/collectors"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	"github.com/nginx/nginx-plus-go-client/v2/client"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// canaryCheckPeriod is the period of the checks whether the canaries are due for the next step.
+	canaryCheckPeriod = time.Second
+
+	canaryProgressingReason = "CanaryProgressing"
+	canarySucceededReason   = "CanarySucceeded"
+	canaryRolledBackReason  = "CanaryRolledBack"
+	canaryHaltedReason      = "CanaryHalted"
+)
+
+type canaryPhase int
+
+const (
+	canaryProgressing canaryPhase = iota
+	canarySucceeded
+	canaryRolledBack
+	canaryHalted
+)
+
+var canaryPhaseNames = map[canaryPhase]string{
+	canaryProgressing: "Progressing",
+	canarySucceeded:   "Succeeded",
+	canaryRolledBack:  "RolledBack",
+	canaryHalted:      "Halted",
+}
+
+func (p canaryPhase) String() string {
+	return canaryPhaseNames[p]
+}
+
+func parseCanaryPhase(name string) (canaryPhase, bool) {
+	for p, n := range canaryPhaseNames {
+		if n == name {
+			return p, true
+		}
+	}
+	return canaryProgressing, false
+}
+
+// upstreamStatsSource provides the statistics of the responses of the upstreams that gate the steps of the canaries.
+type upstreamStatsSource interface {
+	UpstreamStats(upstream string) (collectors.UpstreamStats, error)
+}
+
+// plusUpstreamStatsSource gets the statistics of the upstreams from the NGINX Plus API.
+type plusUpstreamStatsSource struct {
+	client *client.NginxClient
+}
+
+func (s *plusUpstreamStatsSource) UpstreamStats(upstream string) (collectors.UpstreamStats, error) {
+	var stats collectors.UpstreamStats
+
+	upstreams, err := s.client.GetUpstreams(context.Background())
+	if err != nil {
+		return stats, err
+	}
+
+	for _, peer := range (*upstreams)[upstream].Peers {
+		stats.Responses += peer.Responses.Total
+		stats.ServerErrors += peer.Responses.Responses5xx
+		// the NGINX Plus API reports the average response time of a peer
+		stats.ResponseTimeSum += time.Duration(peer.ResponseTime*peer.Responses.Total) * time.Millisecond
+	}
+
+	return stats, nil
+}
+
+// latencyUpstreamStatsSource gets the statistics of the upstreams from the latency metrics collector.
+type latencyUpstreamStatsSource struct {
+	collector collectors.LatencyCollector
+}
+
+func (s *latencyUpstreamStatsSource) UpstreamStats(upstream string) (collectors.UpstreamStats, error) {
+	return s.collector.UpstreamStats(upstream), nil
+}
+
+// canary is the state of the Canary of a Route.
+type canary struct {
+	owner runtime.Object
+	// generation is the generation of the owner that the state refers to.
+	generation int64
+	path       string
+	spec       conf_v1.Canary
+	splits     []conf_v1.Split
+	upstream   string
+	maxWeight  int
+	interval   time.Duration
+	// maxResponseTime is 0 if the Canary doesn't limit the response time.
+	maxResponseTime time.Duration
+
+	// keyValZone and keyValKey are the keyval of the weights of the Splits when the weights are changed without reloads.
+	keyValZone        string
+	keyValKey         string
+	variableNamer     *configs.VariableNamer
+	splitClientsIndex int
+
+	weight   int
+	phase    canaryPhase
+	lastStep time.Time
+	baseline *collectors.UpstreamStats
+}
+
+// canaryUpdate is a change of the state of a canary, which is applied after the step releases the lock.
+type canaryUpdate struct {
+	vsKey         string
+	canary        canary
+	weightChanged bool
+	// statuses are the states of all canaries of the owner of the canary.
+	statuses []conf_v1.CanaryStatus
+}
+
+// canaryController steps the weights of the Splits of the Routes with a Canary.
+// The controller tracks the canaries of the VirtualServers that were last configured by the LoadBalancerController.
+// Only the leader steps the canaries and persists their state in the status of their owners. The other replicas
+// restore the state from the status, as does the leader for the canaries it doesn't track yet, for example, after a restart.
+// A nil canaryController has no canaries.
+type canaryController struct {
+	lock        sync.Mutex
+	canaries    map[string]map[string]*canary
+	statsSource upstreamStatsSource
+	// isLeader reports whether the replica steps the canaries.
+	isLeader func() bool
+	// applyWeight applies the new weight of a canary of the VirtualServer.
+	applyWeight func(vsKey string, c canary)
+	// persist persists the states of the canaries of the owner in its status.
+	persist func(owner runtime.Object, statuses []conf_v1.CanaryStatus)
+	// recordEvent records an event for the owner of a canary.
+	recordEvent func(object runtime.Object, eventType string, reason string, message string)
+	logger      *slog.Logger
+	now         func() time.Time
+}
+
+func newCanaryController(logger *slog.Logger, statsSource upstreamStatsSource, isLeader func() bool, applyWeight func(string, canary),
+	persist func(runtime.Object, []conf_v1.CanaryStatus), recordEvent func(runtime.Object, string, string, string),
+) *canaryController {
+	return &canaryController{
+		canaries:    make(map[string]map[string]*canary),
+		statsSource: statsSource,
+		isLeader:    isLeader,
+		applyWeight: applyWeight,
+		persist:     persist,
+		recordEvent: recordEvent,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Run steps the canaries until stopCh is closed.
+func (cc *canaryController) Run(stopCh <-chan struct{}) {
+	wait.Until(cc.step, canaryCheckPeriod, stopCh)
+}
+
+// update updates the canaries of the Routes of the VirtualServer and its VirtualServerRoutes and returns their current
+// weights by the paths of the Routes. The state of a canary is kept as long as its Canary and Splits don't change.
+func (cc *canaryController) update(vsEx *configs.VirtualServerEx, dynamicWeightChangesReload bool) map[string]int {
+	if cc == nil {
+		return nil
+	}
+
+	vsKey := getResourceKey(&vsEx.VirtualServer.ObjectMeta)
+	variableNamer := configs.NewVSVariableNamer(vsEx.VirtualServer)
+	isLeader := cc.isLeader()
+
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	oldCanaries := cc.canaries[vsKey]
+	canaries := make(map[string]*canary)
+	weights := make(map[string]int)
+
+	// addCanary adds the canary of a route of the VirtualServer or, if vsr is not nil, of a subroute of the VirtualServerRoute.
+	addCanary := func(vsr *conf_v1.VirtualServerRoute, route conf_v1.Route, splitClientsIndex int) {
+		if route.Canary == nil || len(route.Splits) != 2 || route.Splits[1].Action == nil {
+			return
+		}
+
+		c, exists := oldCanaries[route.Path]
+		isNew := !exists || !reflect.DeepEqual(c.spec, *route.Canary) || !reflect.DeepEqual(c.splits, route.Splits)
+		if isNew {
+			c = newCanary(route, cc.now())
+		}
+
+		var statuses []conf_v1.CanaryStatus
+		if vsr != nil {
+			c.owner = vsr
+			c.generation = vsr.Generation
+			c.upstream = configs.NewUpstreamNamerForVirtualServerRoute(vsEx.VirtualServer, vsr).GetNameForUpstreamFromAction(route.Splits[1].Action)
+			statuses = vsr.Status.Canaries
+		} else {
+			c.owner = vsEx.VirtualServer
+			c.generation = vsEx.VirtualServer.Generation
+			c.upstream = configs.NewUpstreamNamerForVirtualServer(vsEx.VirtualServer).GetNameForUpstreamFromAction(route.Splits[1].Action)
+			statuses = vsEx.VirtualServer.Status.Canaries
+		}
+		if isNew || !isLeader {
+			c.restore(statuses, cc.now())
+		}
+		if dynamicWeightChangesReload {
+			c.variableNamer = variableNamer
+			c.splitClientsIndex = splitClientsIndex
+			c.keyValZone = variableNamer.GetNameOfKeyvalZoneForSplitClientIndex(splitClientsIndex)
+			c.keyValKey = variableNamer.GetNameOfKeyvalKeyForSplitClientIndex(splitClientsIndex)
+		} else {
+			c.keyValZone = ""
+			c.keyValKey = ""
+		}
+
+		canaries[route.Path] = c
+		weights[route.Path] = c.weight
+	}
+
+	// the split clients are counted in the same order as in the config generation with dynamic weight changes
+	splitClientsIndex := 0
+	countSplitClients := func(route conf_v1.Route) {
+		for _, m := range route.Matches {
+			splitClientsIndex += countSplitClientsOfSplits(m.Splits)
+		}
+	}
+
+	for _, r := range vsEx.VirtualServer.Spec.Routes {
+		countSplitClients(r)
+		addCanary(nil, r, splitClientsIndex)
+		splitClientsIndex += countSplitClientsOfSplits(r.Splits)
+	}
+	for _, vsr := range vsEx.VirtualServerRoutes {
+		for _, r := range vsr.Spec.Subroutes {
+			countSplitClients(r)
+			addCanary(vsr, r, splitClientsIndex)
+			splitClientsIndex += countSplitClientsOfSplits(r.Splits)
+		}
+	}
+
+	if len(canaries) == 0 {
+		delete(cc.canaries, vsKey)
+		return nil
+	}
+
+	cc.canaries[vsKey] = canaries
+	return weights
+}
+
+func countSplitClientsOfSplits(splits []conf_v1.Split) int {
+	if len(splits) == 2 {
+		return splitClientAmountWhenWeightChangesDynamicReload
+	}
+	if len(splits) > 0 {
+		return 1
+	}
+	return 0
+}
+
+func newCanary(route conf_v1.Route, now time.Time) *canary {
+	splits := make([]conf_v1.Split, len(route.Splits))
+	for i := range route.Splits {
+		splits[i] = *route.Splits[i].DeepCopy()
+	}
+
+	c := &canary{
+		path:      route.Path,
+		spec:      *route.Canary.DeepCopy(),
+		splits:    splits,
+		maxWeight: 100,
+		weight:    route.Splits[1].Weight,
+		phase:     canaryProgressing,
+		lastStep:  now,
+	}
+
+	if route.Canary.MaxWeight != nil {
+		c.maxWeight = *route.Canary.MaxWeight
+	}
+	// the durations are validated by the VirtualServer validation
+	c.interval, _ = time.ParseDuration(route.Canary.Interval)
+	if route.Canary.MaxResponseTime != "" {
+		c.maxResponseTime, _ = time.ParseDuration(route.Canary.MaxResponseTime)
+	}
+
+	if c.weight >= c.maxWeight {
+		c.phase = canarySucceeded
+	}
+
+	return c
+}
+
+// restore restores the weight and the phase of the canary from its persisted state, if the state refers to the
+// current generation of the owner.
+func (c *canary) restore(statuses []conf_v1.CanaryStatus, now time.Time) {
+	for _, status := range statuses {
+		if status.Path != c.path || status.ObservedGeneration != c.generation {
+			continue
+		}
+
+		phase, ok := parseCanaryPhase(status.Phase)
+		if !ok {
+			return
+		}
+		if c.weight != status.Weight || c.phase != phase {
+			c.weight = status.Weight
+			c.phase = phase
+			c.lastStep = now
+			c.baseline = nil
+		}
+		return
+	}
+}
+
+// canaryStatuses returns the states of the canaries of the owner sorted by the paths of their Routes.
+func canaryStatuses(canaries map[string]*canary, owner runtime.Object) []conf_v1.CanaryStatus {
+	var statuses []conf_v1.CanaryStatus
+	for _, c := range canaries {
+		if c.owner != owner {
+			continue
+		}
+		statuses = append(statuses, conf_v1.CanaryStatus{
+			Path:               c.path,
+			Weight:             c.weight,
+			Phase:              c.phase.String(),
+			ObservedGeneration: c.generation,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Path < statuses[j].Path
+	})
+
+	return statuses
+}
+
+// delete deletes the canaries of the VirtualServer.
+func (cc *canaryController) delete(vsKey string) {
+	if cc == nil {
+		return
+	}
+
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	delete(cc.canaries, vsKey)
+}
+
+// step advances or rolls back the canaries that are due for the next step. The new weights are applied and
+// persisted after the lock is released, because applying them can call the NGINX Plus API.
+func (cc *canaryController) step() {
+	if !cc.isLeader() {
+		return
+	}
+
+	for _, u := range cc.stepCanaries() {
+		if u.weightChanged {
+			cc.applyWeight(u.vsKey, u.canary)
+		}
+		cc.persist(u.canary.owner, u.statuses)
+	}
+}
+
+// stepCanaries steps the canaries that are due for the next step and returns the changes of their states.
+func (cc *canaryController) stepCanaries() []canaryUpdate {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	var updates []canaryUpdate
+
+	now := cc.now()
+	for vsKey, canaries := range cc.canaries {
+		for _, c := range canaries {
+			if c.phase != canaryProgressing || now.Sub(c.lastStep) < c.interval {
+				continue
+			}
+
+			weightChanged := cc.stepCanary(c, now)
+			if !weightChanged && c.phase == canaryProgressing {
+				continue
+			}
+
+			updates = append(updates, canaryUpdate{
+				vsKey:         vsKey,
+				canary:        *c,
+				weightChanged: weightChanged,
+				statuses:      canaryStatuses(canaries, c.owner),
+			})
+		}
+	}
+
+	return updates
+}
+
+// stepCanary checks the thresholds of the canary and advances or rolls back its weight.
+// It returns true if the weight changed.
+func (cc *canaryController) stepCanary(c *canary, now time.Time) bool {
+	hasThresholds := c.spec.MaxErrorRate != nil || c.maxResponseTime > 0
+
+	if hasThresholds {
+		if cc.statsSource == nil {
+			c.phase = canaryHalted
+			cc.recordEvent(c.owner, api_v1.EventTypeWarning, canaryHaltedReason,
+				fmt.Sprintf("Halted the canary of the route %v: checking its thresholds requires NGINX Plus or the latency metrics", c.path))
+			return false
+		}
+
+		stats, err := cc.statsSource.UpstreamStats(c.upstream)
+		if err != nil {
+			nl.Warnf(cc.logger, "Failed to get the statistics of the upstream %v of the canary of the route %v: %v", c.upstream, c.path, err)
+			return false
+		}
+
+		baseline := c.baseline
+		c.baseline = &stats
+		if baseline == nil || stats.Responses < baseline.Responses {
+			// the interval starts with the first statistics of the upstream
+			c.lastStep = now
+			return false
+		}
+
+		responses := stats.Responses - baseline.Responses
+		if responses == 0 {
+			nl.Debugf(cc.logger, "The canary of the route %v holds its weight %d: no responses from the upstream %v", c.path, c.weight, c.upstream)
+			c.lastStep = now
+			return false
+		}
+
+		if c.spec.MaxErrorRate != nil {
+			errorRate := int((stats.ServerErrors - baseline.ServerErrors) * 100 / responses)
+			if errorRate > *c.spec.MaxErrorRate {
+				cc.rollback(c, fmt.Sprintf("the error rate %d%% exceeds %d%%", errorRate, *c.spec.MaxErrorRate))
+				return true
+			}
+		}
+
+		if c.maxResponseTime > 0 {
+			responseTime := (stats.ResponseTimeSum - baseline.ResponseTimeSum) / time.Duration(responses)
+			if responseTime > c.maxResponseTime {
+				cc.rollback(c, fmt.Sprintf("the response time %v exceeds %v", responseTime, c.maxResponseTime))
+				return true
+			}
+		}
+	}
+
+	c.weight = min(c.weight+c.spec.StepWeight, c.maxWeight)
+	c.lastStep = now
+
+	if c.weight >= c.maxWeight {
+		c.phase = canarySucceeded
+		cc.recordEvent(c.owner, api_v1.EventTypeNormal, canarySucceededReason,
+			fmt.Sprintf("The canary of the route %v reached the weight %d", c.path, c.weight))
+	} else {
+		cc.recordEvent(c.owner, api_v1.EventTypeNormal, canaryProgressingReason,
+			fmt.Sprintf("Set the weight of the canary of the route %v to %d", c.path, c.weight))
+	}
+
+	return true
+}
+
+func (cc *canaryController) rollback(c *canary, reason string) {
+	c.weight = 0
+	c.phase = canaryRolledBack
+	cc.recordEvent(c.owner, api_v1.EventTypeWarning, canaryRolledBackReason,
+		fmt.Sprintf("Rolled back the canary of the route %v: %v", c.path, reason))
+}
+
+// addCanaryController adds the canary controller to the controller. The steps of the canaries are gated on the
+// statistics of the NGINX Plus API or, for NGINX, the latency metrics.
+func (lbc *LoadBalancerController) addCanaryController(plusClient *client.NginxClient, latencyCollector collectors.LatencyCollector) {
+	var statsSource upstreamStatsSource
+	if plusClient != nil {
+		statsSource = &plusUpstreamStatsSource{client: plusClient}
+	} else if lbc.isLatencyMetricsEnabled && latencyCollector != nil {
+		statsSource = &latencyUpstreamStatsSource{collector: latencyCollector}
+	}
+
+	recordEvent := func(object runtime.Object, eventType string, reason string, message string) {
+		lbc.recorder.Event(object, eventType, reason, message)
+	}
+
+	lbc.canaryController = newCanaryController(lbc.Logger, statsSource, lbc.reportCustomResourceStatusEnabled,
+		lbc.applyCanaryWeight, lbc.persistCanaryStatuses, recordEvent)
+}
+
+// persistCanaryStatuses persists the states of the canaries in the status of their owner, so that the other replicas
+// and the leader after a restart continue the canaries from their current weights.
+func (lbc *LoadBalancerController) persistCanaryStatuses(owner runtime.Object, statuses []conf_v1.CanaryStatus) {
+	var err error
+	switch o := owner.(type) {
+	case *conf_v1.VirtualServer:
+		err = lbc.statusUpdater.updateVirtualServerCanaries(o, statuses)
+	case *conf_v1.VirtualServerRoute:
+		err = lbc.statusUpdater.updateVirtualServerRouteCanaries(o, statuses)
+	}
+	if err != nil {
+		nl.Debugf(lbc.Logger, "Failed to persist the state of the canaries: %v", err)
+	}
+}
+
+// applyCanaryWeight applies the weight of the canary. With dynamic weight changes, the weight is changed in the keyval
+// of the Splits without a reload. Otherwise, the VirtualServer is synced to regenerate its config with the new weight.
+func (lbc *LoadBalancerController) applyCanaryWeight(vsKey string, c canary) {
+	if c.keyValZone != "" {
+		value := c.variableNamer.GetNameOfKeyOfMapForWeights(c.splitClientsIndex, 100-c.weight, c.weight)
+		lbc.configurator.UpsertSplitClientsKeyVal(c.keyValZone, c.keyValKey, value)
+		return
+	}
+
+	ns, _, _ := ParseNamespaceName(vsKey)
+	nsi := lbc.getNamespacedInformer(ns)
+	if nsi == nil || nsi.virtualServerLister == nil {
+		return
+	}
+	obj, exists, err := nsi.virtualServerLister.GetByKey(vsKey)
+	if err != nil || !exists {
+		return
+	}
+	lbc.AddSyncQueue(obj)
+}
@@ -0,0 +1,268 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nginx/kubernetes-ingress/internal/configs"
+	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	"github.com/nginx/kubernetes-ingress/internal/metrics/collectors"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func createPointerFromInt(n int) *int {
+	return &n
+}
+
+type fakeUpstreamStatsSource struct {
+	stats map[string]collectors.UpstreamStats
+}
+
+func (s *fakeUpstreamStatsSource) UpstreamStats(upstream string) (collectors.UpstreamStats, error) {
+	return s.stats[upstream], nil
+}
+
+type testCanaryController struct {
+	*canaryController
+	now       time.Time
+	follower  bool
+	applied   []int
+	persisted [][]conf_v1.CanaryStatus
+	reasons   []string
+}
+
+func newTestCanaryController(statsSource upstreamStatsSource) *testCanaryController {
+	tcc := &testCanaryController{now: time.Now()}
+	tcc.canaryController = newCanaryController(nl.LoggerFromContext(context.Background()), statsSource,
+		func() bool { return !tcc.follower },
+		func(_ string, c canary) { tcc.applied = append(tcc.applied, c.weight) },
+		func(_ runtime.Object, statuses []conf_v1.CanaryStatus) {
+			tcc.persisted = append(tcc.persisted, statuses)
+		},
+		func(_ runtime.Object, _ string, reason string, _ string) { tcc.reasons = append(tcc.reasons, reason) })
+	tcc.canaryController.now = func() time.Time { return tcc.now }
+	return tcc
+}
+
+func createTestCanaryVirtualServerEx(canary *conf_v1.Canary) *configs.VirtualServerEx {
+	return &configs.VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "cafe", Generation: 1},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Routes: []conf_v1.Route{
+					{
+						Path: "/coffee",
+						Splits: []conf_v1.Split{
+							{Weight: 90, Action: &conf_v1.Action{Pass: "coffee-v1"}},
+							{Weight: 10, Action: &conf_v1.Action{Pass: "coffee-v2"}},
+						},
+					},
+					{
+						Path: "/tea",
+						Splits: []conf_v1.Split{
+							{Weight: 90, Action: &conf_v1.Action{Pass: "tea-v1"}},
+							{Weight: 10, Action: &conf_v1.Action{Pass: "tea-v2"}},
+						},
+						Canary: canary,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCanaryControllerStepsWeights(t *testing.T) {
+	t.Parallel()
+
+	tcc := newTestCanaryController(nil)
+	vsEx := createTestCanaryVirtualServerEx(&conf_v1.Canary{Interval: "1m", StepWeight: 30, MaxWeight: createPointerFromInt(60)})
+
+	weights := tcc.update(vsEx, false)
+	if weights["/tea"] != 10 || len(weights) != 1 {
+		t.Fatalf("update() returned %v, want the initial weight 10 of /tea", weights)
+	}
+
+	tcc.step()
+	if len(tcc.applied) != 0 {
+		t.Fatalf("step() applied the weights %v before the interval passed", tcc.applied)
+	}
+
+	for i := 0; i < 3; i++ {
+		tcc.now = tcc.now.Add(time.Minute)
+		tcc.step()
+	}
+
+	expectedWeights := []int{40, 60}
+	if len(tcc.applied) != len(expectedWeights) || tcc.applied[0] != 40 || tcc.applied[1] != 60 {
+		t.Errorf("step() applied the weights %v, want %v", tcc.applied, expectedWeights)
+	}
+	expectedReasons := []string{canaryProgressingReason, canarySucceededReason}
+	if len(tcc.reasons) != 2 || tcc.reasons[0] != expectedReasons[0] || tcc.reasons[1] != expectedReasons[1] {
+		t.Errorf("step() recorded the events %v, want %v", tcc.reasons, expectedReasons)
+	}
+
+	weights = tcc.update(vsEx, false)
+	if weights["/tea"] != 60 {
+		t.Errorf("update() returned the weight %d for an unchanged canary, want 60", weights["/tea"])
+	}
+
+	vsEx.VirtualServer.Spec.Routes[1].Splits[1].Action.Pass = "tea-v3"
+	weights = tcc.update(vsEx, false)
+	if weights["/tea"] != 10 {
+		t.Errorf("update() returned the weight %d for a canary with a new split, want the initial weight 10", weights["/tea"])
+	}
+}
+
+func TestCanaryControllerRollsBackOnErrorRate(t *testing.T) {
+	t.Parallel()
+
+	statsSource := &fakeUpstreamStatsSource{stats: make(map[string]collectors.UpstreamStats)}
+	tcc := newTestCanaryController(statsSource)
+	vsEx := createTestCanaryVirtualServerEx(&conf_v1.Canary{Interval: "1m", StepWeight: 10, MaxErrorRate: createPointerFromInt(5)})
+	tcc.update(vsEx, false)
+
+	upstream := "vs_default_cafe_tea-v2"
+	statsSource.stats[upstream] = collectors.UpstreamStats{Responses: 100, ServerErrors: 50}
+	tcc.now = tcc.now.Add(time.Minute)
+	tcc.step()
+	if len(tcc.applied) != 0 {
+		t.Fatalf("step() applied the weights %v without a baseline of the statistics", tcc.applied)
+	}
+
+	statsSource.stats[upstream] = collectors.UpstreamStats{Responses: 200, ServerErrors: 52}
+	tcc.now = tcc.now.Add(time.Minute)
+	tcc.step()
+
+	statsSource.stats[upstream] = collectors.UpstreamStats{Responses: 300, ServerErrors: 62}
+	tcc.now = tcc.now.Add(time.Minute)
+	tcc.step()
+
+	tcc.now = tcc.now.Add(time.Minute)
+	tcc.step()
+
+	if len(tcc.applied) != 2 || tcc.applied[0] != 20 || tcc.applied[1] != 0 {
+		t.Errorf("step() applied the weights %v, want [20 0]", tcc.applied)
+	}
+	if len(tcc.reasons) != 2 || tcc.reasons[1] != canaryRolledBackReason {
+		t.Errorf("step() recorded the events %v, want a rollback", tcc.reasons)
+	}
+}
+
+func TestCanaryControllerHaltsWithoutStatistics(t *testing.T) {
+	t.Parallel()
+
+	tcc := newTestCanaryController(nil)
+	vsEx := createTestCanaryVirtualServerEx(&conf_v1.Canary{Interval: "1m", StepWeight: 10, MaxResponseTime: "100ms"})
+	tcc.update(vsEx, false)
+
+	tcc.now = tcc.now.Add(time.Minute)
+	tcc.step()
+	tcc.now = tcc.now.Add(time.Minute)
+	tcc.step()
+
+	if len(tcc.applied) != 0 {
+		t.Errorf("step() applied the weights %v without the statistics to check the thresholds", tcc.applied)
+	}
+	if len(tcc.reasons) != 1 || tcc.reasons[0] != canaryHaltedReason {
+		t.Errorf("step() recorded the events %v, want a single halt", tcc.reasons)
+	}
+}
+
+func TestCanaryControllerUpdateWithDynamicWeightChanges(t *testing.T) {
+	t.Parallel()
+
+	tcc := newTestCanaryController(nil)
+	vsEx := createTestCanaryVirtualServerEx(&conf_v1.Canary{Interval: "1m", StepWeight: 10})
+	tcc.update(vsEx, true)
+
+	c := tcc.canaries["default/cafe"]["/tea"]
+	variableNamer := configs.NewVSVariableNamer(vsEx.VirtualServer)
+	// the splits of /coffee come first
+	expectedIndex := splitClientAmountWhenWeightChangesDynamicReload
+	if c.splitClientsIndex != expectedIndex {
+		t.Errorf("update() set the split clients index %d, want %d", c.splitClientsIndex, expectedIndex)
+	}
+	if c.keyValZone != variableNamer.GetNameOfKeyvalZoneForSplitClientIndex(expectedIndex) ||
+		c.keyValKey != variableNamer.GetNameOfKeyvalKeyForSplitClientIndex(expectedIndex) {
+		t.Errorf("update() set the keyval %v %v", c.keyValZone, c.keyValKey)
+	}
+
+	tcc.delete("default/cafe")
+	if len(tcc.canaries) != 0 {
+		t.Errorf("delete() kept the canaries %v", tcc.canaries)
+	}
+}
+
+func TestCanaryControllerPersistsStates(t *testing.T) {
+	t.Parallel()
+
+	tcc := newTestCanaryController(nil)
+	vsEx := createTestCanaryVirtualServerEx(&conf_v1.Canary{Interval: "1m", StepWeight: 30, MaxWeight: createPointerFromInt(60)})
+	tcc.update(vsEx, false)
+
+	tcc.now = tcc.now.Add(time.Minute)
+	tcc.step()
+	tcc.now = tcc.now.Add(time.Minute)
+	tcc.step()
+
+	expected := [][]conf_v1.CanaryStatus{
+		{{Path: "/tea", Weight: 40, Phase: "Progressing", ObservedGeneration: 1}},
+		{{Path: "/tea", Weight: 60, Phase: "Succeeded", ObservedGeneration: 1}},
+	}
+	if diff := cmp.Diff(expected, tcc.persisted); diff != "" {
+		t.Errorf("step() persisted unexpected states (-want +got):\n%s", diff)
+	}
+}
+
+func TestCanaryControllerRestoresStates(t *testing.T) {
+	t.Parallel()
+
+	vsEx := createTestCanaryVirtualServerEx(&conf_v1.Canary{Interval: "1m", StepWeight: 10})
+	vsEx.VirtualServer.Status.Canaries = []conf_v1.CanaryStatus{
+		{Path: "/tea", Weight: 40, Phase: "Progressing", ObservedGeneration: 1},
+	}
+
+	// the leader restores the state of the canaries it doesn't track, for example, after a restart
+	tcc := newTestCanaryController(nil)
+	weights := tcc.update(vsEx, false)
+	if weights["/tea"] != 40 {
+		t.Errorf("update() returned the weight %d, want the persisted weight 40", weights["/tea"])
+	}
+
+	// the state of another generation of the VirtualServer is ignored
+	vsEx.VirtualServer.Generation = 2
+	tcc = newTestCanaryController(nil)
+	weights = tcc.update(vsEx, false)
+	if weights["/tea"] != 10 {
+		t.Errorf("update() returned the weight %d for a state of another generation, want the initial weight 10", weights["/tea"])
+	}
+}
+
+func TestCanaryControllerStepsOnlyOnLeader(t *testing.T) {
+	t.Parallel()
+
+	tcc := newTestCanaryController(nil)
+	tcc.follower = true
+	vsEx := createTestCanaryVirtualServerEx(&conf_v1.Canary{Interval: "1m", StepWeight: 10})
+	tcc.update(vsEx, false)
+
+	tcc.now = tcc.now.Add(time.Minute)
+	tcc.step()
+	if len(tcc.applied) != 0 || len(tcc.persisted) != 0 {
+		t.Errorf("step() applied the weights %v and persisted the states %v on a follower", tcc.applied, tcc.persisted)
+	}
+
+	// a follower takes the weights persisted by the leader
+	vsEx.VirtualServer.Status.Canaries = []conf_v1.CanaryStatus{
+		{Path: "/tea", Weight: 30, Phase: "Progressing", ObservedGeneration: 1},
+	}
+	weights := tcc.update(vsEx, false)
+	if weights["/tea"] != 30 {
+		t.Errorf("update() returned the weight %d on a follower, want the persisted weight 30", weights["/tea"])
+	}
+}
@@ -3,6 +3,7 @@ package k8s
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -12,6 +13,7 @@ import (
 	"github.com/nginx/kubernetes-ingress/pkg/apis/configuration/validation"
 	networking "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	gateway_v1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -38,6 +40,7 @@ const (
 // - Regular or Master Ingress
 // - VirtualServer
 // - TransportServer
+// - Gateway
 type Resource interface {
 	GetObjectMeta() *metav1.ObjectMeta
 	GetKeyWithKind() string
@@ -319,6 +322,103 @@ func (tsc *TransportServerConfiguration) IsEqual(resource Resource) bool {
 	return compareObjectMetas(tsc.GetObjectMeta(), resource.GetObjectMeta()) && tsc.ListenerPort == tsConfig.ListenerPort
 }
 
+// GatewayConfiguration holds the VirtualServers and the TransportServers generated for a Gateway and the routes attached to it.
+// It implements the Resource interface.
+type GatewayConfiguration struct {
+	Gateway          *gateway_v1.Gateway
+	VirtualServers   []*gatewayVirtualServer
+	TransportServers []*conf_v1.TransportServer
+	// ValidHosts marks the hosts of the generated resources as valid (true) or invalid (false).
+	// Like for Ingress resources, some of the hosts might be taken by other resources.
+	ValidHosts map[string]bool
+	// ValidListeners marks the listeners of the Gateway as valid (true) or invalid (false).
+	// A listener is invalid if its port is taken by a listener of the GlobalConfiguration with a different protocol.
+	ValidListeners map[string]bool
+	Warnings       []string
+}
+
+// NewGatewayConfiguration creates a GatewayConfiguration.
+func NewGatewayConfiguration(gw *gateway_v1.Gateway, virtualServers []*gatewayVirtualServer, transportServers []*conf_v1.TransportServer) *GatewayConfiguration {
+	return &GatewayConfiguration{
+		Gateway:          gw,
+		VirtualServers:   virtualServers,
+		TransportServers: transportServers,
+		ValidHosts:       make(map[string]bool),
+		ValidListeners:   make(map[string]bool),
+	}
+}
+
+// GetObjectMeta returns the resource ObjectMeta.
+func (gc *GatewayConfiguration) GetObjectMeta() *metav1.ObjectMeta {
+	return &gc.Gateway.ObjectMeta
+}
+
+// GetKeyWithKind returns the key of the resource with its kind. For example, Gateway/my-namespace/my-name.
+func (gc *GatewayConfiguration) GetKeyWithKind() string {
+	key := getResourceKey(&gc.Gateway.ObjectMeta)
+	return fmt.Sprintf("%s/%s", gatewayKind, key)
+}
+
+// Wins tells if this resource wins over the specified resource.
+// It is used to determine which resource should win over a host.
+func (gc *GatewayConfiguration) Wins(resource Resource) bool {
+	return chooseObjectMetaWinner(gc.GetObjectMeta(), resource.GetObjectMeta())
+}
+
+// AddWarning adds a warning.
+func (gc *GatewayConfiguration) AddWarning(warning string) {
+	gc.Warnings = append(gc.Warnings, warning)
+}
+
+// IsEqual tests if the GatewayConfiguration is equal to the resource.
+func (gc *GatewayConfiguration) IsEqual(resource Resource) bool {
+	gwConfig, ok := resource.(*GatewayConfiguration)
+	if !ok {
+		return false
+	}
+
+	return compareObjectMetas(&gc.Gateway.ObjectMeta, &gwConfig.Gateway.ObjectMeta) &&
+		reflect.DeepEqual(gc.ValidHosts, gwConfig.ValidHosts) &&
+		reflect.DeepEqual(gc.ValidListeners, gwConfig.ValidListeners) &&
+		reflect.DeepEqual(gc.VirtualServers, gwConfig.VirtualServers) &&
+		reflect.DeepEqual(gc.TransportServers, gwConfig.TransportServers)
+}
+
+// hosts returns the hosts of the generated resources.
+func (gc *GatewayConfiguration) hosts() []string {
+	var hosts []string
+	for _, gvs := range gc.VirtualServers {
+		hosts = append(hosts, gvs.virtualServer.Spec.Host)
+	}
+	for _, ts := range gc.TransportServers {
+		hosts = append(hosts, ts.Spec.Host)
+	}
+	sort.Strings(hosts)
+	return slices.Compact(hosts)
+}
+
+// ActiveVirtualServers returns the generated VirtualServers with valid hosts and listeners.
+func (gc *GatewayConfiguration) ActiveVirtualServers() []*gatewayVirtualServer {
+	var result []*gatewayVirtualServer
+	for _, gvs := range gc.VirtualServers {
+		if gc.ValidHosts[gvs.virtualServer.Spec.Host] && gc.ValidListeners[gvs.listener.Name] {
+			result = append(result, gvs)
+		}
+	}
+	return result
+}
+
+// ActiveTransportServers returns the generated TransportServers with valid hosts.
+func (gc *GatewayConfiguration) ActiveTransportServers() []*conf_v1.TransportServer {
+	var result []*conf_v1.TransportServer
+	for _, ts := range gc.TransportServers {
+		if gc.ValidHosts[ts.Spec.Host] {
+			result = append(result, ts)
+		}
+	}
+	return result
+}
+
 func compareObjectMetas(meta1 *metav1.ObjectMeta, meta2 *metav1.ObjectMeta) bool {
 	return meta1.Namespace == meta2.Namespace &&
 		meta1.Name == meta2.Name &&
@@ -351,6 +451,8 @@ type Configuration struct {
 	virtualServers      map[string]*conf_v1.VirtualServer
 	virtualServerRoutes map[string]*conf_v1.VirtualServerRoute
 	transportServers    map[string]*conf_v1.TransportServer
+	// gateways holds the resources generated for the Gateways
+	gateways map[string]*GatewayConfiguration
 
 	globalConfiguration *conf_v1.GlobalConfiguration
 
@@ -404,6 +506,7 @@ func NewConfiguration(
 		virtualServers:               make(map[string]*conf_v1.VirtualServer),
 		virtualServerRoutes:          make(map[string]*conf_v1.VirtualServerRoute),
 		transportServers:             make(map[string]*conf_v1.TransportServer),
+		gateways:                     make(map[string]*GatewayConfiguration),
 		hostProblems:                 make(map[string]ConfigurationProblem),
 		hasCorrectIngressClass:       hasCorrectIngressClass,
 		virtualServerValidator:       virtualServerValidator,
@@ -744,6 +847,48 @@ func (c *Configuration) DeleteTransportServer(key string) ([]ResourceChange, []C
 	return changes, problems
 }
 
+// AddOrUpdateGateway adds or updates the VirtualServers and the TransportServers generated for the Gateway.
+// The generated resources are checked for host collisions with the other resources like Ingress resources.
+func (c *Configuration) AddOrUpdateGateway(gw *gateway_v1.Gateway, virtualServers []*gatewayVirtualServer, transportServers []*conf_v1.TransportServer) ([]ResourceChange, []ConfigurationProblem) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.gateways[getResourceKey(&gw.ObjectMeta)] = NewGatewayConfiguration(gw, virtualServers, transportServers)
+
+	return c.rebuildHosts()
+}
+
+// DeleteGateway deletes the resources generated for a Gateway by the key.
+func (c *Configuration) DeleteGateway(key string) ([]ResourceChange, []ConfigurationProblem) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, exists := c.gateways[key]
+	if !exists {
+		return nil, nil
+	}
+
+	delete(c.gateways, key)
+
+	return c.rebuildHosts()
+}
+
+// GetGatewayConfiguration returns the GatewayConfiguration of the Gateway with the key,
+// if the Gateway has at least one valid host.
+func (c *Configuration) GetGatewayConfiguration(key string) *GatewayConfiguration {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	keyWithKind := fmt.Sprintf("%s/%s", gatewayKind, key)
+	for _, r := range c.hosts {
+		if gwConfig, ok := r.(*GatewayConfiguration); ok && r.GetKeyWithKind() == keyWithKind {
+			return gwConfig
+		}
+	}
+
+	return nil
+}
+
 func (c *Configuration) rebuildListenerHosts() ([]ResourceChange, []ConfigurationProblem) {
 	newListenerHosts, newTSConfigs := c.buildListenerHostsAndTSConfigurations()
 
@@ -1007,6 +1152,7 @@ func (c *Configuration) rebuildHosts() ([]ResourceChange, []ConfigurationProblem
 	newHosts, newResources := c.buildHostsAndResources()
 
 	updateActiveHostsForIngresses(newHosts, newResources)
+	updateActiveHostsForGateways(newHosts, newResources)
 
 	removedHosts, updatedHosts, addedHosts := detectChangesInHosts(c.hosts, newHosts)
 	changes := createResourceChangesForHosts(removedHosts, updatedHosts, addedHosts, c.hosts, newHosts)
@@ -1054,6 +1200,20 @@ func updateActiveHostsForIngresses(hosts map[string]Resource, resources map[stri
 	}
 }
 
+func updateActiveHostsForGateways(hosts map[string]Resource, resources map[string]Resource) {
+	for _, r := range resources {
+		gwConfig, ok := r.(*GatewayConfiguration)
+		if !ok {
+			continue
+		}
+
+		for _, host := range gwConfig.hosts() {
+			res, exists := hosts[host]
+			gwConfig.ValidHosts[host] = exists && res.GetKeyWithKind() == r.GetKeyWithKind()
+		}
+	}
+}
+
 func detectChangesInProblems(newProblems map[string]ConfigurationProblem, oldProblems map[string]ConfigurationProblem) []ConfigurationProblem {
 	var result []ConfigurationProblem
 
@@ -1154,6 +1314,16 @@ func (c *Configuration) addProblemsForResourcesWithoutActiveHost(resources map[s
 				}
 				problems[r.GetKeyWithKind()] = p
 			}
+		case *GatewayConfiguration:
+			if len(impl.VirtualServers)+len(impl.TransportServers) > 0 && len(impl.ActiveVirtualServers())+len(impl.ActiveTransportServers()) == 0 {
+				p := ConfigurationProblem{
+					Object:  impl.Gateway,
+					IsError: false,
+					Reason:  "Rejected",
+					Message: "All hosts are taken by other resources or all listeners are taken by the listeners of the GlobalConfiguration",
+				}
+				problems[r.GetKeyWithKind()] = p
+			}
 		}
 	}
 }
@@ -1529,9 +1699,57 @@ func (c *Configuration) buildHostsAndResources() (newHosts map[string]Resource,
 		}
 	}
 
+	// Step 4 - Build hosts from the resources generated for Gateways
+
+	for _, key := range getSortedGatewayKeys(c.gateways) {
+		gw := c.gateways[key]
+
+		resource := NewGatewayConfiguration(gw.Gateway, gw.VirtualServers, gw.TransportServers)
+		newResources[resource.GetKeyWithKind()] = resource
+
+		for _, gvs := range resource.VirtualServers {
+			l := gvs.listener
+			if _, checked := resource.ValidListeners[l.Name]; checked {
+				continue
+			}
+			resource.ValidListeners[l.Name] = true
+			if gcListener, taken := c.findListenerWithPort(l.Port); taken && (gcListener.Protocol != l.Protocol || gcListener.Ssl != l.Ssl) {
+				resource.ValidListeners[l.Name] = false
+				resource.AddWarning(fmt.Sprintf("port %d of listener %s is taken by listener %s of the GlobalConfiguration", l.Port, l.Name, gcListener.Name))
+			}
+		}
+
+		for _, host := range resource.hosts() {
+			holder, exists := newHosts[host]
+			if !exists {
+				newHosts[host] = resource
+				continue
+			}
+
+			warning := fmt.Sprintf("host %s is taken by another resource", host)
+
+			if !holder.Wins(resource) {
+				holder.AddWarning(warning)
+				newHosts[host] = resource
+			} else {
+				resource.AddWarning(warning)
+			}
+		}
+	}
+
 	return newHosts, newResources
 }
 
+// findListenerWithPort returns the listener of the GlobalConfiguration with the port.
+func (c *Configuration) findListenerWithPort(port int) (conf_v1.Listener, bool) {
+	for _, l := range c.listenerMap {
+		if l.Port == port {
+			return l, true
+		}
+	}
+	return conf_v1.Listener{}, false
+}
+
 func (c *Configuration) isChallengeIngress(ing *networking.Ingress) bool {
 	if !c.isCertManagerEnabled {
 		return false
@@ -1773,6 +1991,18 @@ func getSortedTransportServerKeys(m map[string]*conf_v1.TransportServer) []strin
 	return keys
 }
 
+func getSortedGatewayKeys(m map[string]*GatewayConfiguration) []string {
+	var keys []string
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
 func getSortedListenerHostKeys(m map[listenerHostKey]*TransportServerConfiguration) []listenerHostKey {
 	var keys []listenerHostKey
 
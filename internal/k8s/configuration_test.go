@@ -11,6 +11,7 @@ import (
 	"github.com/nginx/kubernetes-ingress/pkg/apis/configuration/validation"
 	networking "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gateway_v1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 func createTestConfiguration() *Configuration {
@@ -37,7 +38,7 @@ func createTestConfiguration() *Configuration {
 			80:  true,
 			443: true,
 		}),
-		validation.NewTransportServerValidator(isTLSPassthroughEnabled, snippetsEnabled, isPlus),
+		validation.NewTransportServerValidator(isTLSPassthroughEnabled, snippetsEnabled, isPlus, certManagerEnabled, false),
 		isTLSPassthroughEnabled,
 		snippetsEnabled,
 		certManagerEnabled,
@@ -1878,6 +1879,132 @@ func TestHostCollisions(t *testing.T) {
 	}
 }
 
+func TestGatewayHostCollisions(t *testing.T) {
+	t.Parallel()
+	configuration := createTestConfiguration()
+
+	vs := createTestVirtualServer("virtualserver", "foo.example.com")
+	addOrUpdateVirtualServer(t, configuration, vs, []ResourceChange{{Op: AddOrUpdate, Resource: &VirtualServerConfiguration{VirtualServer: vs}}}, noProblems)
+
+	gw := createTestGateway(metav1.NewTime(vs.CreationTimestamp.Add(time.Second)))
+	httpListener := &gatewayListener{Listener: conf_v1.Listener{Name: "http", Port: 80, Protocol: "HTTP"}}
+	virtualServers := []*gatewayVirtualServer{
+		{virtualServer: createTestVirtualServer("gateway_cafe_http_foo_example_com", "foo.example.com"), listener: httpListener},
+		{virtualServer: createTestVirtualServer("gateway_cafe_http_bar_example_com", "bar.example.com"), listener: httpListener},
+	}
+
+	// Add Gateway, whose host foo.example.com is taken by the older VirtualServer
+
+	changes, problems := configuration.AddOrUpdateGateway(gw, virtualServers, nil)
+	if len(problems) != 0 {
+		t.Errorf("AddOrUpdateGateway() returned unexpected problems %v", problems)
+	}
+	gwConfig := getTestGatewayConfiguration(t, changes, AddOrUpdate)
+	if diff := cmp.Diff(map[string]bool{"foo.example.com": false, "bar.example.com": true}, gwConfig.ValidHosts); diff != "" {
+		t.Errorf("AddOrUpdateGateway() returned unexpected valid hosts (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"host foo.example.com is taken by another resource"}, gwConfig.Warnings); diff != "" {
+		t.Errorf("AddOrUpdateGateway() returned unexpected warnings (-want +got):\n%s", diff)
+	}
+	active := gwConfig.ActiveVirtualServers()
+	if len(active) != 1 || active[0] != virtualServers[1] {
+		t.Errorf("ActiveVirtualServers() returned %v, want only the VirtualServer for bar.example.com", active)
+	}
+
+	// Delete VirtualServer, so that the Gateway gets the host
+
+	changes, problems = configuration.DeleteVirtualServer("default/virtualserver")
+	if len(problems) != 0 {
+		t.Errorf("DeleteVirtualServer() returned unexpected problems %v", problems)
+	}
+	gwConfig = getTestGatewayConfiguration(t, changes, AddOrUpdate)
+	if len(gwConfig.ActiveVirtualServers()) != 2 {
+		t.Errorf("ActiveVirtualServers() returned %d VirtualServers, want 2", len(gwConfig.ActiveVirtualServers()))
+	}
+	if configuration.GetGatewayConfiguration("default/cafe") == nil {
+		t.Errorf("GetGatewayConfiguration() returned nil for the Gateway with hosts")
+	}
+
+	// Delete Gateway
+
+	changes, _ = configuration.DeleteGateway("default/cafe")
+	getTestGatewayConfiguration(t, changes, Delete)
+	if configuration.GetGatewayConfiguration("default/cafe") != nil {
+		t.Errorf("GetGatewayConfiguration() returned the deleted Gateway")
+	}
+}
+
+func TestGatewayListenerCollisions(t *testing.T) {
+	t.Parallel()
+	configuration := createTestConfiguration()
+
+	listeners := []conf_v1.Listener{
+		{
+			Name:     "tcp-8080",
+			Port:     8080,
+			Protocol: "TCP",
+		},
+	}
+	addOrUpdateGlobalConfiguration(t, configuration, listeners, noChanges, noProblems)
+
+	gw := createTestGateway(metav1.Now())
+	listener := &gatewayListener{Listener: conf_v1.Listener{Name: "http", Port: 8080, Protocol: "HTTP"}}
+	virtualServers := []*gatewayVirtualServer{
+		{virtualServer: createTestVirtualServer("gateway_cafe_http_foo_example_com", "foo.example.com"), listener: listener},
+	}
+
+	expectedProblems := []ConfigurationProblem{
+		{
+			Object:  gw,
+			IsError: false,
+			Reason:  "Rejected",
+			Message: "All hosts are taken by other resources or all listeners are taken by the listeners of the GlobalConfiguration",
+		},
+	}
+
+	changes, problems := configuration.AddOrUpdateGateway(gw, virtualServers, nil)
+	if diff := cmp.Diff(expectedProblems, problems); diff != "" {
+		t.Errorf("AddOrUpdateGateway() returned unexpected problems (-want +got):\n%s", diff)
+	}
+	gwConfig := getTestGatewayConfiguration(t, changes, AddOrUpdate)
+	if len(gwConfig.ActiveVirtualServers()) != 0 {
+		t.Errorf("ActiveVirtualServers() returned %d VirtualServers, want 0", len(gwConfig.ActiveVirtualServers()))
+	}
+	if diff := cmp.Diff([]string{"port 8080 of listener http is taken by listener tcp-8080 of the GlobalConfiguration"}, gwConfig.Warnings); diff != "" {
+		t.Errorf("AddOrUpdateGateway() returned unexpected warnings (-want +got):\n%s", diff)
+	}
+
+	// Delete GlobalConfiguration, so that the listener of the Gateway becomes valid
+
+	changes, _ = configuration.DeleteGlobalConfiguration()
+	gwConfig = getTestGatewayConfiguration(t, changes, AddOrUpdate)
+	if len(gwConfig.ActiveVirtualServers()) != 1 {
+		t.Errorf("ActiveVirtualServers() returned %d VirtualServers, want 1", len(gwConfig.ActiveVirtualServers()))
+	}
+}
+
+func createTestGateway(creationTimestamp metav1.Time) *gateway_v1.Gateway {
+	return &gateway_v1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              "cafe",
+			CreationTimestamp: creationTimestamp,
+		},
+	}
+}
+
+// getTestGatewayConfiguration returns the GatewayConfiguration of the change of the Gateway, which must have the operation.
+func getTestGatewayConfiguration(t *testing.T, changes []ResourceChange, op Operation) *GatewayConfiguration {
+	t.Helper()
+	for _, c := range changes {
+		if gwConfig, ok := c.Resource.(*GatewayConfiguration); ok && c.Op == op {
+			return gwConfig
+		}
+	}
+	t.Fatalf("expected a change of a GatewayConfiguration with operation %v, got %v", op, changes)
+	return nil
+}
+
 func TestAddTransportServer(t *testing.T) {
 	configuration := createTestConfiguration()
 
@@ -36,6 +36,7 @@ import (
 	"k8s.io/client-go/rest"
 
 	"github.com/nginx/kubernetes-ingress/internal/k8s/secrets"
+	"github.com/nginx/nginx-plus-go-client/v2/client"
 	"github.com/nginxinc/nginx-service-mesh/pkg/spiffe"
 	"github.com/spiffe/go-spiffe/v2/workloadapi"
 
@@ -52,6 +53,7 @@ import (
 	"github.com/nginx/kubernetes-ingress/internal/configs"
 	ed_controller "github.com/nginx/kubernetes-ingress/internal/externaldns"
 	"github.com/nginx/kubernetes-ingress/internal/metrics/collectors"
+	"github.com/nginx/kubernetes-ingress/internal/nginx"
 
 	api_v1 "k8s.io/api/core/v1"
 	discovery_v1 "k8s.io/api/discovery/v1"
@@ -69,6 +71,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
+	gateway_clientset "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
+	gateway_informers "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
 )
 
 const (
@@ -80,6 +84,9 @@ const (
 	helmReleaseType                                 = "helm.sh/release.v1"
 	splitClientAmountWhenWeightChangesDynamicReload = 101
 	secretDeletedReason                             = "SecretDeleted"
+	// syncQueueWorkers is the number of workers of the sync queue. The sync of the controller
+	// updates the shared state of the controller and the Configurator, so the tasks are synced one by one.
+	syncQueueWorkers = 1
 )
 
 var (
@@ -120,6 +127,7 @@ type controllerMetadata struct {
 type LoadBalancerController struct {
 	client                        kubernetes.Interface
 	confClient                    k8s_nginx.Interface
+	gatewayClient                 gateway_clientset.Interface
 	dynClient                     dynamic.Interface
 	restConfig                    *rest.Config
 	cacheSyncs                    []cache.InformerSynced
@@ -128,10 +136,14 @@ type LoadBalancerController struct {
 	mgmtConfigMapController       cache.Controller
 	globalConfigurationController cache.Controller
 	ingressLinkInformer           cache.SharedIndexInformer
+	gatewayClassInformer          cache.SharedIndexInformer
+	gatewayNamespaceInformer      cache.SharedIndexInformer
 	configMapLister               storeToConfigMapLister
 	mgmtConfigMapLister           storeToConfigMapLister
 	globalConfigurationLister     cache.Store
 	ingressLinkLister             cache.Store
+	gatewayClassLister            cache.Store
+	gatewayNamespaceLister        cache.Store
 	namespaceLabeledLister        cache.Store
 	syncQueue                     *taskQueue
 	ctx                           context.Context
@@ -150,6 +162,8 @@ type LoadBalancerController struct {
 	ingressClass                  string
 	statusUpdater                 *statusUpdater
 	leaderElector                 *leaderelection.LeaderElector
+	shardManager                  *shardManager
+	canaryController              *canaryController
 	reportIngressStatus           bool
 	isLeaderElectionEnabled       bool
 	leaderElectionLockName        string
@@ -186,6 +200,10 @@ type LoadBalancerController struct {
 	weightChangesDynamicReload    bool
 	nginxConfigMapName            string
 	mgmtConfigMapName             string
+	isGatewayAPIEnabled           bool
+	isTLSRouteEnabled             bool
+	tlsPassthroughPort            int
+	gatewayConfigs                map[string]*gatewayConfig
 }
 
 var keyFunc = cache.DeletionHandlingMetaNamespaceKeyFunc
@@ -194,6 +212,7 @@ var keyFunc = cache.DeletionHandlingMetaNamespaceKeyFunc
 type NewLoadBalancerControllerInput struct {
 	KubeClient                   kubernetes.Interface
 	ConfClient                   k8s_nginx.Interface
+	GatewayClient                gateway_clientset.Interface
 	DynClient                    dynamic.Interface
 	RestConfig                   *rest.Config
 	Recorder                     record.EventRecorder
@@ -236,12 +255,17 @@ type NewLoadBalancerControllerInput struct {
 	ExternalDNSEnabled           bool
 	IsIPV6Disabled               bool
 	WatchNamespaceLabel          string
+	IsNamespaceShardingEnabled   bool
 	EnableTelemetryReporting     bool
 	TelemetryReportingEndpoint   string
 	BuildOS                      string
 	NICVersion                   string
 	DynamicWeightChangesReload   bool
 	InstallationFlags            []string
+	NginxPlusClient              *client.NginxClient
+	LatencyCollector             collectors.LatencyCollector
+	IsGatewayAPIEnabled          bool
+	IsTLSRouteEnabled            bool
 }
 
 // NewLoadBalancerController creates a controller
@@ -258,6 +282,7 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 	lbc := &LoadBalancerController{
 		client:                       input.KubeClient,
 		confClient:                   input.ConfClient,
+		gatewayClient:                input.GatewayClient,
 		dynClient:                    input.DynClient,
 		restConfig:                   input.RestConfig,
 		recorder:                     input.Recorder,
@@ -287,9 +312,14 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 		weightChangesDynamicReload:   input.DynamicWeightChangesReload,
 		nginxConfigMapName:           input.ConfigMaps,
 		mgmtConfigMapName:            input.MGMTConfigMap,
+		isGatewayAPIEnabled:          input.IsGatewayAPIEnabled,
+		isTLSRouteEnabled:            input.IsTLSRouteEnabled,
+		tlsPassthroughPort:           input.TLSPassthroughPort,
+		gatewayConfigs:               make(map[string]*gatewayConfig),
 	}
 
-	lbc.syncQueue = newTaskQueue(lbc.Logger, lbc.sync)
+	lbc.syncQueue = newTaskQueue(lbc.Logger, lbc.sync, lbc.metricsCollector)
+	lbc.addCanaryController(input.NginxPlusClient, input.LatencyCollector)
 	var err error
 	if input.SpireAgentAddress != "" {
 		lbc.spiffeCertFetcher, err = spiffe.NewX509CertFetcher(input.SpireAgentAddress, nil)
@@ -298,18 +328,22 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 		}
 	}
 
-	isDynamicNs := input.WatchNamespaceLabel != ""
+	isDynamicNs := input.WatchNamespaceLabel != "" || input.IsNamespaceShardingEnabled
 
 	if isDynamicNs {
 		lbc.addNamespaceHandler(createNamespaceHandlers(lbc), input.WatchNamespaceLabel)
 	}
 
+	if input.IsNamespaceShardingEnabled {
+		lbc.addShardManager(input.LeaderElectionLockName)
+	}
+
 	if input.CertManagerEnabled {
 		lbc.certManagerController = cm_controller.NewCmController(cm_controller.BuildOpts(input.LoggerContext, lbc.restConfig, lbc.client, lbc.namespaceList, lbc.recorder, lbc.confClient, isDynamicNs))
 	}
 
 	if input.ExternalDNSEnabled {
-		lbc.externalDNSController = ed_controller.NewController(ed_controller.BuildOpts(input.LoggerContext, lbc.namespaceList, lbc.recorder, lbc.confClient, input.ResyncPeriod, isDynamicNs))
+		lbc.externalDNSController = ed_controller.NewController(ed_controller.BuildOpts(input.LoggerContext, lbc.namespaceList, lbc.recorder, lbc.confClient, lbc.client, input.ResyncPeriod, isDynamicNs))
 	}
 
 	nl.Debugf(lbc.Logger, "Nginx Ingress Controller has class: %v", input.IngressClass)
@@ -329,6 +363,10 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 			ns, name, _ := ParseNamespaceName(input.GlobalConfiguration)
 			lbc.addGlobalConfigurationHandler(createGlobalConfigurationHandlers(lbc), ns, name)
 		}
+		if lbc.isGatewayAPIEnabled {
+			lbc.addGatewayClassHandler(createGatewayClassHandlers(lbc))
+			lbc.addGatewayNamespaceHandler(createGatewayNamespaceHandlers(lbc))
+		}
 	}
 
 	if input.ConfigMaps != "" {
@@ -367,6 +405,7 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 		namespacedInformers:    lbc.namespacedInformers,
 		keyFunc:                keyFunc,
 		confClient:             input.ConfClient,
+		gatewayClient:          input.GatewayClient,
 		hasCorrectIngressClass: lbc.HasCorrectIngressClass,
 		logger:                 lbc.Logger,
 	}
@@ -443,6 +482,7 @@ type namespacedInformer struct {
 	confSharedInformerFactory    k8s_nginx_informers.SharedInformerFactory
 	secretInformerFactory        informers.SharedInformerFactory
 	dynInformerFactory           dynamicinformer.DynamicSharedInformerFactory
+	gatewayInformerFactory       gateway_informers.SharedInformerFactory
 	ingressLister                storeToIngressLister
 	svcLister                    cache.Store
 	endpointSliceLister          storeToEndpointSliceLister
@@ -458,10 +498,15 @@ type namespacedInformer struct {
 	appProtectUserSigLister      cache.Store
 	transportServerLister        cache.Store
 	policyLister                 cache.Store
+	gatewayLister                cache.Store
+	httpRouteLister              cache.Store
+	grpcRouteLister              cache.Store
+	tlsRouteLister               cache.Store
 	isSecretsEnabledNamespace    bool
 	areCustomResourcesEnabled    bool
 	appProtectEnabled            bool
 	appProtectDosEnabled         bool
+	isGatewayAPIEnabled          bool
 	stopCh                       chan struct{}
 	lock                         sync.RWMutex
 	cacheSyncs                   []cache.InformerSynced
@@ -510,6 +555,13 @@ func (lbc *LoadBalancerController) newNamespacedInformer(ns string) *namespacedI
 		nsi.addTransportServerHandler(createTransportServerHandlers(lbc))
 		nsi.addPolicyHandler(createPolicyHandlers(lbc))
 
+		if lbc.isGatewayAPIEnabled {
+			nsi.isGatewayAPIEnabled = true
+			nsi.gatewayInformerFactory = gateway_informers.NewSharedInformerFactoryWithOptions(lbc.gatewayClient, lbc.resync, gateway_informers.WithNamespace(ns))
+
+			nsi.addGatewayHandler(createGatewayHandlers(lbc))
+			nsi.addGatewayRouteHandlers(createGatewayRouteHandlers(lbc), lbc.isTLSRouteEnabled)
+		}
 	}
 
 	if lbc.appProtectEnabled || lbc.appProtectDosEnabled {
@@ -627,6 +679,12 @@ func (lbc *LoadBalancerController) Run() {
 		go lbc.leaderElector.Run(lbc.ctx)
 	}
 
+	if lbc.shardManager != nil {
+		go lbc.shardManager.Run(lbc.ctx.Done())
+	}
+
+	go lbc.canaryController.Run(lbc.ctx.Done())
+
 	if lbc.telemetryCollector != nil {
 		go func(ctx context.Context) {
 			select {
@@ -656,6 +714,12 @@ func (lbc *LoadBalancerController) Run() {
 	if lbc.watchIngressLink {
 		go lbc.ingressLinkInformer.Run(lbc.ctx.Done())
 	}
+	if lbc.gatewayClassInformer != nil {
+		go lbc.gatewayClassInformer.Run(lbc.ctx.Done())
+	}
+	if lbc.gatewayNamespaceInformer != nil {
+		go lbc.gatewayNamespaceInformer.Run(lbc.ctx.Done())
+	}
 
 	totalCacheSyncs := lbc.cacheSyncs
 
@@ -673,7 +737,7 @@ func (lbc *LoadBalancerController) Run() {
 
 	nl.Debugf(lbc.Logger, "Starting the queue with %d initial elements", lbc.syncQueue.Len())
 
-	go lbc.syncQueue.Run(time.Second, lbc.ctx.Done())
+	go lbc.syncQueue.Run(time.Second, syncQueueWorkers, lbc.ctx.Done())
 	<-lbc.ctx.Done()
 }
 
@@ -697,6 +761,10 @@ func (nsi *namespacedInformer) start() {
 		go nsi.confSharedInformerFactory.Start(nsi.stopCh)
 	}
 
+	if nsi.isGatewayAPIEnabled {
+		go nsi.gatewayInformerFactory.Start(nsi.stopCh)
+	}
+
 	if nsi.appProtectEnabled || nsi.appProtectDosEnabled {
 		go nsi.dynInformerFactory.Start(nsi.stopCh)
 	}
@@ -796,6 +864,12 @@ func (lbc *LoadBalancerController) virtualServerRequiresEndpointsUpdate(vsEx *co
 		}
 	}
 
+	for _, pol := range vsEx.Policies {
+		if pol.Spec.ExternalAuth != nil && pol.Spec.ExternalAuth.Service != nil && pol.Spec.ExternalAuth.Service.Name == serviceName {
+			return true
+		}
+	}
+
 	return false
 }
 
@@ -933,6 +1007,9 @@ func (lbc *LoadBalancerController) updateAllConfigs() {
 	resources := lbc.configuration.GetResources()
 	nl.Debugf(lbc.Logger, "Updating %v resources", len(resources))
 	resourceExes := lbc.createExtendedResources(resources)
+	gatewayExes := lbc.getGatewayExtendedResources()
+	resourceExes.VirtualServerExes = append(resourceExes.VirtualServerExes, gatewayExes.VirtualServerExes...)
+	resourceExes.TransportServerExes = append(resourceExes.TransportServerExes, gatewayExes.TransportServerExes...)
 	warnings, updateErr := lbc.configurator.UpdateConfig(resourceExes)
 
 	eventTitle := "Updated"
@@ -1070,6 +1147,10 @@ func (lbc *LoadBalancerController) sync(task task) {
 		lbc.syncDosProtectedResource(task)
 	case ingressLink:
 		lbc.syncIngressLink(task)
+	case gatewayClass:
+		lbc.syncGatewayClass(task)
+	case gateway:
+		lbc.syncGateway(task)
 	}
 
 	if !lbc.isNginxReady && lbc.syncQueue.Len() == 0 {
@@ -1094,6 +1175,8 @@ func (lbc *LoadBalancerController) sync(task task) {
 		lbc.enableBatchReload = false
 		nl.Debug(lbc.Logger, "Batch sync completed - disabling batch reload")
 	}
+
+	lbc.updateRejectedResourcesStatusAndEvents()
 }
 
 func (lbc *LoadBalancerController) removeNamespacedInformer(nsi *namespacedInformer, key string) {
@@ -1135,6 +1218,7 @@ func (lbc *LoadBalancerController) cleanupUnwatchedNamespacedResources(nsi *name
 			key := getResourceKey(&vs.ObjectMeta)
 			delVsList = append(delVsList, key)
 			lbc.configuration.DeleteVirtualServer(key)
+			lbc.canaryController.delete(key)
 		}
 		delVsErrs := lbc.configurator.BatchDeleteVirtualServers(delVsList)
 		if len(delVsErrs) > 0 {
@@ -1159,6 +1243,10 @@ func (lbc *LoadBalancerController) cleanupUnwatchedNamespacedResources(nsi *name
 			key := getResourceKey(&vsr.ObjectMeta)
 			lbc.configuration.DeleteVirtualServerRoute(key)
 		}
+
+		if nsi.isGatewayAPIEnabled {
+			lbc.cleanupUnwatchedGateways(nsi.namespace)
+		}
 	}
 	if nsi.appProtectEnabled {
 		lbc.cleanupUnwatchedAppWafResources(nsi)
@@ -1284,6 +1372,8 @@ func (lbc *LoadBalancerController) processChanges(changes []ResourceChange) {
 				tsEx := lbc.createTransportServerEx(impl.TransportServer, impl.ListenerPort, impl.IPv4, impl.IPv6)
 				warnings, addOrUpdateErr := lbc.configurator.AddOrUpdateTransportServer(tsEx)
 				lbc.updateTransportServerStatusAndEvents(impl, warnings, addOrUpdateErr)
+			case *GatewayConfiguration:
+				lbc.updateGatewayConfig(impl)
 			}
 		} else if c.Op == Delete {
 			switch impl := c.Resource.(type) {
@@ -1294,6 +1384,7 @@ func (lbc *LoadBalancerController) processChanges(changes []ResourceChange) {
 				if deleteErr != nil {
 					nl.Errorf(lbc.Logger, "Error when deleting configuration for VirtualServer %v: %v", key, deleteErr)
 				}
+				lbc.canaryController.delete(key)
 
 				var vsExists bool
 				var err error
@@ -1349,9 +1440,15 @@ func (lbc *LoadBalancerController) processChanges(changes []ResourceChange) {
 				if tsExists {
 					lbc.updateTransportServerStatusAndEventsOnDelete(impl, c.Error, deleteErr)
 				}
+			case *GatewayConfiguration:
+				lbc.removeGatewayConfig(impl)
 			}
 		}
 	}
+
+	if len(changes) > 0 {
+		lbc.updateDosProtectedResourcesStatus()
+	}
 }
 
 // UpdateVirtualServerStatusAndEventsOnDelete updates the virtual server status and events
@@ -1430,6 +1527,44 @@ func (lbc *LoadBalancerController) UpdateIngressStatusAndEventsOnDelete(ingConfi
 	// for each minion, a dedicated problem exists
 }
 
+// updateRejectedResourcesStatusAndEvents reports the resources whose configuration was rejected by NGINX
+// and rolled back to the previous version.
+func (lbc *LoadBalancerController) updateRejectedResourcesStatusAndEvents() {
+	for _, r := range lbc.configurator.TakeRejectedResources() {
+		switch obj := r.Object.(type) {
+		case *networking.Ingress:
+			msg := fmt.Sprintf("Configuration for %v was rolled back to the previous version: %v", getResourceKey(&obj.ObjectMeta), r.Error)
+			lbc.recorder.Event(obj, api_v1.EventTypeWarning, "AddedOrUpdatedWithError", msg)
+		case *conf_v1.VirtualServer:
+			msg := fmt.Sprintf("Configuration for %v was rolled back to the previous version: %v", getResourceKey(&obj.ObjectMeta), r.Error)
+			if gw := lbc.findGatewayForGeneratedResource(&obj.ObjectMeta); gw != nil {
+				lbc.recorder.Event(gw, api_v1.EventTypeWarning, "AddedOrUpdatedWithError", msg)
+				continue
+			}
+			lbc.recorder.Event(obj, api_v1.EventTypeWarning, "AddedOrUpdatedWithError", msg)
+			if lbc.reportCustomResourceStatusEnabled() {
+				err := lbc.statusUpdater.UpdateVirtualServerStatus(obj, conf_v1.StateInvalid, "AddedOrUpdatedWithError", msg)
+				if err != nil {
+					nl.Errorf(lbc.Logger, "Error when updating the status for VirtualServer %v/%v: %v", obj.Namespace, obj.Name, err)
+				}
+			}
+		case *conf_v1.TransportServer:
+			msg := fmt.Sprintf("Configuration for %v was rolled back to the previous version: %v", getResourceKey(&obj.ObjectMeta), r.Error)
+			if gw := lbc.findGatewayForGeneratedResource(&obj.ObjectMeta); gw != nil {
+				lbc.recorder.Event(gw, api_v1.EventTypeWarning, "AddedOrUpdatedWithError", msg)
+				continue
+			}
+			lbc.recorder.Event(obj, api_v1.EventTypeWarning, "AddedOrUpdatedWithError", msg)
+			if lbc.reportCustomResourceStatusEnabled() {
+				err := lbc.statusUpdater.UpdateTransportServerStatus(obj, conf_v1.StateInvalid, "AddedOrUpdatedWithError", msg)
+				if err != nil {
+					nl.Errorf(lbc.Logger, "Error when updating the status for TransportServer %v/%v: %v", obj.Namespace, obj.Name, err)
+				}
+			}
+		}
+	}
+}
+
 func (lbc *LoadBalancerController) updateResourcesStatusAndEvents(resources []Resource, warnings configs.Warnings, operationErr error) {
 	for _, r := range resources {
 		switch impl := r.(type) {
@@ -1722,9 +1857,10 @@ func (lbc *LoadBalancerController) IsExternalServiceKeyForStatus(key string) boo
 }
 
 // reportStatusEnabled determines if we should attempt to report status for Ingress resources.
+// With namespace sharding, every shard reports the status of the resources in its namespaces.
 func (lbc *LoadBalancerController) reportStatusEnabled() bool {
 	if lbc.reportIngressStatus {
-		if lbc.isLeaderElectionEnabled {
+		if lbc.isLeaderElectionEnabled && lbc.shardManager == nil {
 			return lbc.leaderElector != nil && lbc.leaderElector.IsLeader()
 		}
 		return true
@@ -1734,7 +1870,7 @@ func (lbc *LoadBalancerController) reportStatusEnabled() bool {
 
 // reportCustomResourceStatusEnabled determines if we should attempt to report status for Custom Resources.
 func (lbc *LoadBalancerController) reportCustomResourceStatusEnabled() bool {
-	if lbc.isLeaderElectionEnabled {
+	if lbc.isLeaderElectionEnabled && lbc.shardManager == nil {
 		return lbc.leaderElector != nil && lbc.leaderElector.IsLeader()
 	}
 
@@ -1771,6 +1907,10 @@ func (lbc *LoadBalancerController) syncSecret(task task) {
 
 	nl.Debugf(lbc.Logger, "Found %v Resources with Secret %v", len(resources), key)
 
+	if lbc.isGatewayAPIEnabled {
+		lbc.enqueueGatewaysForSecret(key)
+	}
+
 	if !secretWatched {
 		lbc.secretStore.DeleteSecret(key)
 
@@ -1837,7 +1977,7 @@ func (lbc *LoadBalancerController) isSpecialSecret(secretName string) bool {
 func (lbc *LoadBalancerController) handleRegularSecretDeletion(resources []Resource) {
 	resourceExes := lbc.createExtendedResources(resources)
 
-	warnings, addOrUpdateErr := lbc.configurator.AddOrUpdateResources(resourceExes, true)
+	warnings, addOrUpdateErr := lbc.configurator.AddOrUpdateResources(resourceExes, true, nginx.ReloadForSecretUpdate)
 
 	lbc.updateResourcesStatusAndEvents(resources, warnings, addOrUpdateErr)
 }
@@ -1850,7 +1990,7 @@ func (lbc *LoadBalancerController) handleSecretUpdate(secret *api_v1.Secret, res
 
 	resourceExes := lbc.createExtendedResources(resources)
 
-	warnings, addOrUpdateErr = lbc.configurator.AddOrUpdateResources(resourceExes, !lbc.configurator.DynamicSSLReloadEnabled())
+	warnings, addOrUpdateErr = lbc.configurator.AddOrUpdateResources(resourceExes, !lbc.configurator.DynamicSSLReloadEnabled(), nginx.ReloadForSecretUpdate)
 	if addOrUpdateErr != nil {
 		nl.Errorf(lbc.Logger, "Error when updating Secret %v: %v", secretNsName, addOrUpdateErr)
 		lbc.recorder.Eventf(lbc.metadata.pod, api_v1.EventTypeWarning, "UpdatedWithError", "%v was updated, but not applied: %v", secretNsName, addOrUpdateErr)
@@ -1993,7 +2133,7 @@ func (lbc *LoadBalancerController) performDynamicSSLReload(secret *api_v1.Secret
 
 func (lbc *LoadBalancerController) performNGINXReload(secret *api_v1.Secret) bool {
 	secretNsName := generateSecretNSName(secret)
-	if err := lbc.configurator.Reload(false); err != nil {
+	if err := lbc.configurator.Reload(nginx.ReloadForSecretUpdate); err != nil {
 		nl.Errorf(lbc.Logger, "error when reloading NGINX when updating the special Secrets: %v", err)
 		lbc.recorder.Eventf(lbc.metadata.pod, api_v1.EventTypeWarning, "UpdatedWithError", "the special Secret %v was updated, but not applied: %v", secretNsName, err)
 		return false
@@ -2398,6 +2538,10 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 	if err != nil {
 		nl.Warnf(lbc.Logger, "Error getting APIKey secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
 	}
+	err = lbc.addUpstreamTLSSecretRefs(virtualServerEx.SecretRefs, virtualServer.Namespace, virtualServer.Spec.Upstreams)
+	if err != nil {
+		nl.Warnf(lbc.Logger, "Error getting upstream TLS secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
+	}
 
 	err = lbc.addWAFPolicyRefs(virtualServerEx.ApPolRefs, virtualServerEx.LogConfRefs, policies)
 	if err != nil {
@@ -2530,6 +2674,11 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 	}
 
 	for _, vsr := range virtualServerRoutes {
+		err = lbc.addUpstreamTLSSecretRefs(virtualServerEx.SecretRefs, vsr.Namespace, vsr.Spec.Upstreams)
+		if err != nil {
+			nl.Warnf(lbc.Logger, "Error getting upstream TLS secrets for VirtualServerRoute %v/%v: %v", vsr.Namespace, vsr.Name, err)
+		}
+
 		for _, sr := range vsr.Spec.Subroutes {
 			vsrSubroutePolicies, policyErrors := lbc.getPolicies(sr.Policies, vsr.Namespace)
 			for _, err := range policyErrors {
@@ -2622,11 +2771,14 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 		}
 	}
 
+	lbc.addExternalAuthEndpoints(endpoints, policies)
+
 	virtualServerEx.Endpoints = endpoints
 	virtualServerEx.VirtualServerRoutes = virtualServerRoutes
 	virtualServerEx.ExternalNameSvcs = externalNameSvcs
 	virtualServerEx.Policies = createPolicyMap(policies)
 	virtualServerEx.PodsByIP = podsByIP
+	virtualServerEx.CanaryWeights = lbc.canaryController.update(&virtualServerEx, lbc.weightChangesDynamicReload)
 
 	return &virtualServerEx
 }
@@ -2803,6 +2955,26 @@ func (lbc *LoadBalancerController) addEgressMTLSSecretRefs(secretRefs map[string
 	return nil
 }
 
+func (lbc *LoadBalancerController) addUpstreamTLSSecretRefs(secretRefs map[string]*secrets.SecretReference, namespace string, upstreams []conf_v1.Upstream) error {
+	var err error
+	for _, u := range upstreams {
+		if u.TLS.TrustedCertSecret == "" {
+			continue
+		}
+
+		secretKey := fmt.Sprintf("%v/%v", namespace, u.TLS.TrustedCertSecret)
+		secretRef := lbc.secretStore.GetSecret(secretKey)
+
+		secretRefs[secretKey] = secretRef
+
+		if secretRef.Error != nil {
+			err = secretRef.Error
+		}
+	}
+
+	return err
+}
+
 func (lbc *LoadBalancerController) addOIDCSecretRefs(secretRefs map[string]*secrets.SecretReference, policies []*conf_v1.Policy) error {
 	for _, pol := range policies {
 		if pol.Spec.OIDC == nil {
@@ -2868,6 +3040,50 @@ func findPoliciesForSecret(policies []*conf_v1.Policy, secretNamespace string, s
 	return res
 }
 
+// addExternalAuthEndpoints adds the endpoints of the Services of the external auth policies.
+func (lbc *LoadBalancerController) addExternalAuthEndpoints(endpoints map[string][]string, policies []*conf_v1.Policy) {
+	for _, pol := range policies {
+		if pol.Spec.ExternalAuth == nil || pol.Spec.ExternalAuth.Service == nil {
+			continue
+		}
+
+		svc := pol.Spec.ExternalAuth.Service
+		port := uint16(svc.Port) //nolint:gosec
+		podEndps, _, err := lbc.getEndpointsForUpstream(pol.Namespace, svc.Name, port)
+		if err != nil {
+			nl.Warnf(lbc.Logger, "Error getting Endpoints for the Service %v of Policy %v/%v: %v", svc.Name, pol.Namespace, pol.Name, err)
+		}
+		endpoints[configs.GenerateEndpointsKey(pol.Namespace, svc.Name, nil, port)] = getIPAddressesFromEndpoints(podEndps)
+	}
+}
+
+// findResourcesForService finds the resources that reference the Service directly or through an external auth policy.
+func (lbc *LoadBalancerController) findResourcesForService(svcNamespace string, svcName string) []Resource {
+	resources := lbc.configuration.FindResourcesForService(svcNamespace, svcName)
+
+	if lbc.areCustomResourcesEnabled {
+		for _, pol := range findPoliciesForService(lbc.getAllPolicies(), svcNamespace, svcName) {
+			resources = append(resources, lbc.configuration.FindResourcesForPolicy(pol.Namespace, pol.Name)...)
+		}
+
+		resources = removeDuplicateResources(resources)
+	}
+
+	return resources
+}
+
+func findPoliciesForService(policies []*conf_v1.Policy, svcNamespace string, svcName string) []*conf_v1.Policy {
+	var res []*conf_v1.Policy
+
+	for _, pol := range policies {
+		if pol.Spec.ExternalAuth != nil && pol.Spec.ExternalAuth.Service != nil && pol.Spec.ExternalAuth.Service.Name == svcName && pol.Namespace == svcNamespace {
+			res = append(res, pol)
+		}
+	}
+
+	return res
+}
+
 func (lbc *LoadBalancerController) getTransportServerBackupEndpointsAndKey(transportServer *conf_v1.TransportServer, u conf_v1.TransportServerUpstream, externalNameSvcs map[string]bool) ([]string, string) {
 	backupEndpointsKey := configs.GenerateEndpointsKey(transportServer.Namespace, u.Backup, nil, *u.BackupPort)
 	backupEndps, external, err := lbc.getEndpointsForUpstream(transportServer.Namespace, u.Backup, *u.BackupPort)
@@ -3542,6 +3758,7 @@ func (lbc *LoadBalancerController) haltIfVSConfigInvalid(vsNew *conf_v1.VirtualS
 				if deleteErr != nil {
 					nl.Errorf(lbc.Logger, "Error when deleting configuration for VirtualServer %v: %v", key, deleteErr)
 				}
+				lbc.canaryController.delete(key)
 
 				var vsExists bool
 				var err error
@@ -2109,7 +2109,7 @@ func TestGetPoliciesGlobalWatch(t *testing.T) {
 
 	expectedPolicies := []*conf_v1.Policy{validPolicy}
 	expectedErrors := []error{
-		errors.New("policy default/invalid-policy is invalid: spec: Invalid value: \"\": must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `basicAuth`, `apiKey`, `jwt`, `oidc`, `waf`"),
+		errors.New("policy default/invalid-policy is invalid: spec: Invalid value: \"\": must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `basicAuth`, `apiKey`, `retry`, `cors`, `externalAuth`, `cache`, `connectionLimit`, `bandwidth`, `jwt`, `oidc`, `waf`"),
 		errors.New("policy nginx-ingress/valid-policy doesn't exist"),
 		errors.New("failed to get policy nginx-ingress/some-policy: GetByKey error"),
 		errors.New("referenced policy default/valid-policy-ingress-class has incorrect ingress class: test-class (controller ingress class: )"),
@@ -2207,7 +2207,7 @@ func TestGetPoliciesNamespacedWatch(t *testing.T) {
 
 	expectedPolicies := []*conf_v1.Policy{validPolicy}
 	expectedErrors := []error{
-		errors.New("policy default/invalid-policy is invalid: spec: Invalid value: \"\": must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `basicAuth`, `apiKey`, `jwt`, `oidc`, `waf`"),
+		errors.New("policy default/invalid-policy is invalid: spec: Invalid value: \"\": must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `basicAuth`, `apiKey`, `retry`, `cors`, `externalAuth`, `cache`, `connectionLimit`, `bandwidth`, `jwt`, `oidc`, `waf`"),
 		errors.New("failed to get namespace nginx-ingress"),
 		errors.New("referenced policy default/valid-policy-ingress-class has incorrect ingress class: test-class (controller ingress class: )"),
 	}
@@ -2669,6 +2669,65 @@ func TestFindPoliciesForSecret(t *testing.T) {
 	}
 }
 
+func TestFindPoliciesForService(t *testing.T) {
+	t.Parallel()
+	extAuthPol := &conf_v1.Policy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "ext-auth-policy",
+			Namespace: "default",
+		},
+		Spec: conf_v1.PolicySpec{
+			ExternalAuth: &conf_v1.ExternalAuth{
+				Service: &conf_v1.ExternalAuthService{Name: "authz", Port: 8080},
+			},
+		},
+	}
+	extAuthURLPol := &conf_v1.Policy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "ext-auth-url-policy",
+			Namespace: "default",
+		},
+		Spec: conf_v1.PolicySpec{
+			ExternalAuth: &conf_v1.ExternalAuth{
+				URL: "https://authz.example.com/check",
+			},
+		},
+	}
+	policies := []*conf_v1.Policy{extAuthPol, extAuthURLPol}
+
+	tests := []struct {
+		svcNamespace string
+		svcName      string
+		expected     []*conf_v1.Policy
+		msg          string
+	}{
+		{
+			svcNamespace: "default",
+			svcName:      "authz",
+			expected:     []*conf_v1.Policy{extAuthPol},
+			msg:          "find the policy of the service",
+		},
+		{
+			svcNamespace: "ns-1",
+			svcName:      "authz",
+			expected:     nil,
+			msg:          "ignore the service in another namespace",
+		},
+		{
+			svcNamespace: "default",
+			svcName:      "tea-svc",
+			expected:     nil,
+			msg:          "ignore other services",
+		},
+	}
+	for _, test := range tests {
+		result := findPoliciesForService(policies, test.svcNamespace, test.svcName)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("findPoliciesForService() '%v' mismatch (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
 func errorComparer(e1, e2 error) bool {
 	if e1 == nil || e2 == nil {
 		return errors.Is(e1, e2)
@@ -73,7 +73,7 @@ func (lbc *LoadBalancerController) syncEndpointSlices(task task) bool {
 
 	endpointSlice := obj.(*discovery_v1.EndpointSlice)
 	svcName := endpointSlice.Labels["kubernetes.io/service-name"]
-	svcResource := lbc.configuration.FindResourcesForService(endpointSlice.Namespace, svcName)
+	svcResource := lbc.findResourcesForService(endpointSlice.Namespace, svcName)
 
 	// check if this is the endpointslice for the controller's own service
 	if lbc.statusUpdater.namespace == endpointSlice.Namespace && lbc.statusUpdater.externalServiceName == svcName {
@@ -133,6 +133,10 @@ func (lbc *LoadBalancerController) syncEndpointSlices(task task) bool {
 				nl.Errorf(lbc.Logger, "Error updating EndpointSlices for %v: %v", resourceExes.TransportServerExes, err)
 			}
 		}
+
+		if lbc.isGatewayAPIEnabled && lbc.updateGatewayEndpointsForService(endpointSlice.Namespace+"/"+svcName) {
+			resourcesFound = true
+		}
 	}
 	return resourcesFound
 }
@@ -0,0 +1,758 @@
+package k8s
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/nginx/kubernetes-ingress/internal/configs"
+	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	"github.com/nginx/kubernetes-ingress/internal/nginx"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	gateway_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	gateway_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gateway_informers "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
+)
+
+// gatewayConfig holds the configuration generated for a Gateway, so that it can be updated when the endpoints
+// of the referenced Services change and removed when the Gateway or its routes change.
+type gatewayConfig struct {
+	// virtualServers and transportServers hold the applied resources, whose hosts and listeners are not taken by other resources.
+	virtualServers   []*gatewayVirtualServer
+	transportServers []*conf_v1.TransportServer
+	services         map[string]bool
+	secrets          map[string]bool
+	// routes holds the keys of the routes that reference the Gateway, see getGatewayRouteKey.
+	routes map[string]bool
+	// applyErr holds the error of the last application of the resources.
+	applyErr error
+}
+
+func createGatewayClassHandlers(lbc *LoadBalancerController) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			gc := obj.(*gateway_v1.GatewayClass)
+			nl.Debugf(lbc.Logger, "Adding GatewayClass: %v", gc.Name)
+			lbc.AddSyncQueue(gc)
+		},
+		DeleteFunc: func(obj interface{}) {
+			gc, isGc := obj.(*gateway_v1.GatewayClass)
+			if !isGc {
+				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					nl.Debugf(lbc.Logger, "Error received unexpected object: %v", obj)
+					return
+				}
+				gc, ok = deletedState.Obj.(*gateway_v1.GatewayClass)
+				if !ok {
+					nl.Debugf(lbc.Logger, "Error DeletedFinalStateUnknown contained non-GatewayClass object: %v", deletedState.Obj)
+					return
+				}
+			}
+			nl.Debugf(lbc.Logger, "Removing GatewayClass: %v", gc.Name)
+			lbc.AddSyncQueue(gc)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			oldGc := old.(*gateway_v1.GatewayClass)
+			curGc := cur.(*gateway_v1.GatewayClass)
+			if oldGc.Generation != curGc.Generation {
+				nl.Debugf(lbc.Logger, "GatewayClass %v changed, syncing", curGc.Name)
+				lbc.AddSyncQueue(curGc)
+			}
+		},
+	}
+}
+
+func createGatewayHandlers(lbc *LoadBalancerController) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			gw := obj.(*gateway_v1.Gateway)
+			nl.Debugf(lbc.Logger, "Adding Gateway: %v", gw.Name)
+			lbc.AddSyncQueue(gw)
+		},
+		DeleteFunc: func(obj interface{}) {
+			gw, isGw := obj.(*gateway_v1.Gateway)
+			if !isGw {
+				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					nl.Debugf(lbc.Logger, "Error received unexpected object: %v", obj)
+					return
+				}
+				gw, ok = deletedState.Obj.(*gateway_v1.Gateway)
+				if !ok {
+					nl.Debugf(lbc.Logger, "Error DeletedFinalStateUnknown contained non-Gateway object: %v", deletedState.Obj)
+					return
+				}
+			}
+			nl.Debugf(lbc.Logger, "Removing Gateway: %v", gw.Name)
+			lbc.AddSyncQueue(gw)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			oldGw := old.(*gateway_v1.Gateway)
+			curGw := cur.(*gateway_v1.Gateway)
+			// the updates of the status by the Ingress Controller don't change the generation
+			if oldGw.Generation != curGw.Generation {
+				nl.Debugf(lbc.Logger, "Gateway %v changed, syncing", curGw.Name)
+				lbc.AddSyncQueue(curGw)
+			}
+		},
+	}
+}
+
+// createGatewayNamespaceHandlers creates the handlers for namespaces, whose labels select the routes
+// that can attach to the listeners of Gateways.
+func createGatewayNamespaceHandlers(lbc *LoadBalancerController) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, cur interface{}) {
+			oldNs := old.(*api_v1.Namespace)
+			curNs := cur.(*api_v1.Namespace)
+			if !reflect.DeepEqual(oldNs.Labels, curNs.Labels) {
+				nl.Debugf(lbc.Logger, "Labels of Namespace %v changed, syncing Gateways", curNs.Name)
+				lbc.enqueueGatewaysWithNamespaceSelectors()
+			}
+		},
+	}
+}
+
+// createGatewayRouteHandlers creates the handlers for HTTPRoutes, GRPCRoutes and TLSRoutes.
+// The routes are configured through their parent Gateways, so the handlers enqueue the Gateways.
+func createGatewayRouteHandlers(lbc *LoadBalancerController) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			route, parentRefs := getGatewayRouteParentRefs(obj)
+			if route == nil {
+				return
+			}
+			nl.Debugf(lbc.Logger, "Adding route: %v", route.GetName())
+			lbc.enqueueGatewaysForParentRefs(route.GetNamespace(), parentRefs)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if deletedState, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = deletedState.Obj
+			}
+			route, parentRefs := getGatewayRouteParentRefs(obj)
+			if route == nil {
+				nl.Debugf(lbc.Logger, "Error received unexpected object: %v", obj)
+				return
+			}
+			nl.Debugf(lbc.Logger, "Removing route: %v", route.GetName())
+			lbc.enqueueGatewaysForParentRefs(route.GetNamespace(), parentRefs)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			oldRoute, oldParentRefs := getGatewayRouteParentRefs(old)
+			curRoute, curParentRefs := getGatewayRouteParentRefs(cur)
+			if oldRoute == nil || curRoute == nil || oldRoute.GetGeneration() == curRoute.GetGeneration() {
+				return
+			}
+			nl.Debugf(lbc.Logger, "Route %v changed, syncing", curRoute.GetName())
+			// the route might be removed from the old parents
+			lbc.enqueueGatewaysForParentRefs(oldRoute.GetNamespace(), oldParentRefs)
+			lbc.enqueueGatewaysForParentRefs(curRoute.GetNamespace(), curParentRefs)
+		},
+	}
+}
+
+func getGatewayRouteParentRefs(obj interface{}) (meta_v1.Object, []gateway_v1.ParentReference) {
+	switch route := obj.(type) {
+	case *gateway_v1.HTTPRoute:
+		return route, route.Spec.ParentRefs
+	case *gateway_v1.GRPCRoute:
+		return route, route.Spec.ParentRefs
+	case *gateway_v1alpha2.TLSRoute:
+		return route, route.Spec.ParentRefs
+	}
+	return nil, nil
+}
+
+func (lbc *LoadBalancerController) enqueueGatewaysForParentRefs(routeNamespace string, parentRefs []gateway_v1.ParentReference) {
+	for _, ref := range parentRefs {
+		if (ref.Group != nil && *ref.Group != gatewayAPIGroup) || (ref.Kind != nil && *ref.Kind != gatewayKind) {
+			continue
+		}
+		namespace := routeNamespace
+		if ref.Namespace != nil {
+			namespace = string(*ref.Namespace)
+		}
+		nsi := lbc.getNamespacedInformer(namespace)
+		if nsi == nil {
+			continue
+		}
+		obj, exists, err := nsi.gatewayLister.GetByKey(fmt.Sprintf("%s/%s", namespace, ref.Name))
+		if err != nil || !exists {
+			continue
+		}
+		lbc.AddSyncQueue(obj)
+	}
+}
+
+// addGatewayClassHandler adds the handler for GatewayClasses, which are cluster-scoped.
+func (lbc *LoadBalancerController) addGatewayClassHandler(handlers cache.ResourceEventHandlerFuncs) {
+	factory := gateway_informers.NewSharedInformerFactory(lbc.gatewayClient, lbc.resync)
+	lbc.gatewayClassInformer = factory.Gateway().V1().GatewayClasses().Informer()
+	lbc.gatewayClassInformer.AddEventHandler(handlers) //nolint:errcheck,gosec
+	lbc.gatewayClassLister = lbc.gatewayClassInformer.GetStore()
+
+	lbc.cacheSyncs = append(lbc.cacheSyncs, lbc.gatewayClassInformer.HasSynced)
+}
+
+// addGatewayNamespaceHandler adds the handler for namespaces, so that the labels of the namespaces of the routes
+// can be matched against the namespace selectors of the listeners of Gateways.
+func (lbc *LoadBalancerController) addGatewayNamespaceHandler(handlers cache.ResourceEventHandlerFuncs) {
+	lbc.gatewayNamespaceInformer = informers.NewSharedInformerFactory(lbc.client, lbc.resync).Core().V1().Namespaces().Informer()
+	lbc.gatewayNamespaceInformer.AddEventHandler(handlers) //nolint:errcheck,gosec
+	lbc.gatewayNamespaceLister = lbc.gatewayNamespaceInformer.GetStore()
+
+	lbc.cacheSyncs = append(lbc.cacheSyncs, lbc.gatewayNamespaceInformer.HasSynced)
+}
+
+func (nsi *namespacedInformer) addGatewayHandler(handlers cache.ResourceEventHandlerFuncs) {
+	informer := nsi.gatewayInformerFactory.Gateway().V1().Gateways().Informer()
+	informer.AddEventHandler(handlers) //nolint:errcheck,gosec
+	nsi.gatewayLister = informer.GetStore()
+
+	nsi.cacheSyncs = append(nsi.cacheSyncs, informer.HasSynced)
+}
+
+func (nsi *namespacedInformer) addGatewayRouteHandlers(handlers cache.ResourceEventHandlerFuncs, isTLSRouteEnabled bool) {
+	httpRouteInformer := nsi.gatewayInformerFactory.Gateway().V1().HTTPRoutes().Informer()
+	httpRouteInformer.AddEventHandler(handlers) //nolint:errcheck,gosec
+	nsi.httpRouteLister = httpRouteInformer.GetStore()
+
+	grpcRouteInformer := nsi.gatewayInformerFactory.Gateway().V1().GRPCRoutes().Informer()
+	grpcRouteInformer.AddEventHandler(handlers) //nolint:errcheck,gosec
+	nsi.grpcRouteLister = grpcRouteInformer.GetStore()
+
+	nsi.cacheSyncs = append(nsi.cacheSyncs, httpRouteInformer.HasSynced, grpcRouteInformer.HasSynced)
+
+	// TLSRoute is only a part of the experimental channel of the Gateway API, so its CRD might not be installed
+	if isTLSRouteEnabled {
+		tlsRouteInformer := nsi.gatewayInformerFactory.Gateway().V1alpha2().TLSRoutes().Informer()
+		tlsRouteInformer.AddEventHandler(handlers) //nolint:errcheck,gosec
+		nsi.tlsRouteLister = tlsRouteInformer.GetStore()
+
+		nsi.cacheSyncs = append(nsi.cacheSyncs, tlsRouteInformer.HasSynced)
+	}
+}
+
+// isGatewayClassAccepted reports whether the GatewayClass with the name exists and is handled by the Ingress Controller.
+// The Ingress Controller handles the GatewayClass with the name of its ingress class and its controller name.
+func (lbc *LoadBalancerController) isGatewayClassAccepted(name string) bool {
+	if name != lbc.ingressClass {
+		return false
+	}
+	obj, exists, err := lbc.gatewayClassLister.GetByKey(name)
+	if err != nil || !exists {
+		return false
+	}
+	return obj.(*gateway_v1.GatewayClass).Spec.ControllerName == gateway_v1.GatewayController(IngressControllerName)
+}
+
+func (lbc *LoadBalancerController) syncGatewayClass(task task) {
+	key := task.Key
+	obj, gcExists, err := lbc.gatewayClassLister.GetByKey(key)
+	if err != nil {
+		lbc.syncQueue.Requeue(task, err)
+		return
+	}
+
+	if gcExists {
+		gc := obj.(*gateway_v1.GatewayClass)
+		if gc.Name == lbc.ingressClass && gc.Spec.ControllerName == gateway_v1.GatewayController(IngressControllerName) && lbc.reportCustomResourceStatusEnabled() {
+			condition := newGatewayCondition(string(gateway_v1.GatewayClassConditionStatusAccepted), true, string(gateway_v1.GatewayClassReasonAccepted),
+				"GatewayClass is accepted", gc.Generation)
+			err = lbc.statusUpdater.UpdateGatewayClassStatus(gc, []meta_v1.Condition{condition})
+			if err != nil {
+				nl.Errorf(lbc.Logger, "Error when updating the status for GatewayClass %v: %v", gc.Name, err)
+			}
+		}
+	}
+
+	for _, nsi := range lbc.namespacedInformers {
+		for _, obj := range nsi.gatewayLister.List() {
+			gw := obj.(*gateway_v1.Gateway)
+			if string(gw.Spec.GatewayClassName) == key {
+				lbc.AddSyncQueue(gw)
+			}
+		}
+	}
+}
+
+func (lbc *LoadBalancerController) syncGateway(task task) {
+	key := task.Key
+	ns, _, _ := cache.SplitMetaNamespaceKey(key)
+	obj, gwExists, err := lbc.getNamespacedInformer(ns).gatewayLister.GetByKey(key)
+	if err != nil {
+		lbc.syncQueue.Requeue(task, err)
+		return
+	}
+
+	if !gwExists || !lbc.isGatewayClassAccepted(string(obj.(*gateway_v1.Gateway).Spec.GatewayClassName)) {
+		nl.Debugf(lbc.Logger, "Deleting configuration for Gateway: %v", key)
+		lbc.deleteGatewayConfig(key)
+		return
+	}
+
+	nl.Debugf(lbc.Logger, "Adding or Updating Gateway: %v", key)
+	gw := obj.(*gateway_v1.Gateway)
+
+	translator := lbc.newGatewayTranslator()
+	translation := translator.translate(gw, lbc.getGatewayRoutes(gw))
+
+	oldCfg := lbc.gatewayConfigs[key]
+	cfg := &gatewayConfig{
+		services: translation.services,
+		secrets:  translation.secrets,
+		routes:   make(map[string]bool),
+	}
+	for routeKey := range translation.routeParents {
+		cfg.routes[routeKey] = true
+	}
+	if oldCfg != nil {
+		// the applied resources are replaced when the changes of the Configuration are processed
+		cfg.virtualServers, cfg.transportServers, cfg.applyErr = oldCfg.virtualServers, oldCfg.transportServers, oldCfg.applyErr
+	}
+	lbc.gatewayConfigs[key] = cfg
+
+	// The generated resources are added to the Configuration, so that the hosts taken by other resources are not applied.
+	changes, problems := lbc.configuration.AddOrUpdateGateway(gw, translation.virtualServers, translation.transportServers)
+	lbc.processChanges(changes)
+	lbc.processProblems(problems)
+
+	// The resources are applied even if the Configuration didn't change, because the Secrets they reference might have changed.
+	keyWithKind := getResourceKeyWithKind(gatewayKind, &gw.ObjectMeta)
+	if !slices.ContainsFunc(changes, func(c ResourceChange) bool { return c.Resource.GetKeyWithKind() == keyWithKind }) {
+		if gwConfig := lbc.configuration.GetGatewayConfiguration(key); gwConfig != nil {
+			lbc.updateGatewayConfig(gwConfig)
+		}
+	}
+
+	programmed := newGatewayCondition(string(gateway_v1.GatewayConditionProgrammed), true, string(gateway_v1.GatewayReasonProgrammed), "Gateway is programmed", gw.Generation)
+	if applyErr := lbc.gatewayConfigs[key].applyErr; applyErr != nil {
+		programmed = newGatewayCondition(string(gateway_v1.GatewayConditionProgrammed), false, string(gateway_v1.GatewayReasonInvalid), applyErr.Error(), gw.Generation)
+	}
+
+	if !lbc.reportCustomResourceStatusEnabled() {
+		return
+	}
+
+	conditions := translation.conditions
+	if meta.IsStatusConditionTrue(conditions, string(gateway_v1.GatewayConditionAccepted)) {
+		conditions = append(conditions, programmed)
+	} else if len(conditions) == 1 {
+		conditions = append(conditions, newGatewayCondition(string(gateway_v1.GatewayConditionProgrammed), false, string(gateway_v1.GatewayReasonInvalid),
+			"Gateway is not accepted", gw.Generation))
+	}
+
+	var listeners []gateway_v1.ListenerStatus
+	for _, l := range translation.listeners {
+		status := gateway_v1.ListenerStatus{
+			Name:           gateway_v1.SectionName(l.Name),
+			SupportedKinds: []gateway_v1.RouteGroupKind{},
+			AttachedRoutes: l.attachedRoutes,
+			Conditions:     l.conditions,
+		}
+		for _, k := range l.kinds {
+			group := gateway_v1.Group(gatewayAPIGroup)
+			status.SupportedKinds = append(status.SupportedKinds, gateway_v1.RouteGroupKind{Group: &group, Kind: gateway_v1.Kind(k)})
+		}
+		listeners = append(listeners, status)
+	}
+
+	err = lbc.statusUpdater.UpdateGatewayStatus(gw, conditions, listeners)
+	if err != nil {
+		nl.Errorf(lbc.Logger, "Error when updating the status for Gateway %v: %v", key, err)
+	}
+
+	lbc.updateGatewayRouteStatuses(gw, translation.routeParents, oldCfg)
+}
+
+// updateGatewayRouteStatuses updates the statuses of the parent references of the routes to the Gateway.
+// The routes that no longer reference the Gateway lose the statuses of their parent references to the Gateway.
+func (lbc *LoadBalancerController) updateGatewayRouteStatuses(gw *gateway_v1.Gateway, routeParents map[string][]gateway_v1.RouteParentStatus, oldCfg *gatewayConfig) {
+	routeKeys := make(map[string]bool)
+	for routeKey := range routeParents {
+		routeKeys[routeKey] = true
+	}
+	if oldCfg != nil {
+		for routeKey := range oldCfg.routes {
+			routeKeys[routeKey] = true
+		}
+	}
+
+	for routeKey := range routeKeys {
+		parts := strings.SplitN(routeKey, "/", 3)
+		kind, namespace, name := parts[0], parts[1], parts[2]
+		if lbc.getNamespacedInformer(namespace) == nil {
+			continue
+		}
+
+		var err error
+		switch kind {
+		case httpRouteKind:
+			err = lbc.statusUpdater.UpdateHTTPRouteStatus(namespace, name, gw, routeParents[routeKey])
+		case grpcRouteKind:
+			err = lbc.statusUpdater.UpdateGRPCRouteStatus(namespace, name, gw, routeParents[routeKey])
+		case tlsRouteKind:
+			err = lbc.statusUpdater.UpdateTLSRouteStatus(namespace, name, gw, routeParents[routeKey])
+		}
+		if err != nil {
+			nl.Errorf(lbc.Logger, "Error when updating the status for %v %v/%v: %v", kind, namespace, name, err)
+		}
+	}
+}
+
+func (lbc *LoadBalancerController) newGatewayTranslator() *gatewayTranslator {
+	return &gatewayTranslator{
+		isTLSPassthroughEnabled: lbc.configuration.isTLSPassthroughEnabled,
+		tlsPassthroughPort:      lbc.tlsPassthroughPort,
+		isTLSRouteEnabled:       lbc.isTLSRouteEnabled,
+		validateSecret: func(key string) error {
+			return lbc.secretStore.GetSecret(key).Error
+		},
+		serviceExists: func(key string) bool {
+			ns, _, _ := cache.SplitMetaNamespaceKey(key)
+			nsi := lbc.getNamespacedInformer(ns)
+			if nsi == nil {
+				return false
+			}
+			_, exists, err := nsi.svcLister.GetByKey(key)
+			return err == nil && exists
+		},
+		validateVirtualServer: lbc.configuration.virtualServerValidator.ValidateVirtualServer,
+		namespaceLabels: func(namespace string) (map[string]string, bool) {
+			obj, exists, err := lbc.gatewayNamespaceLister.GetByKey(namespace)
+			if err != nil || !exists {
+				return nil, false
+			}
+			return obj.(*api_v1.Namespace).Labels, true
+		},
+	}
+}
+
+// getGatewayRoutes returns the routes in the watched namespaces that reference the Gateway.
+func (lbc *LoadBalancerController) getGatewayRoutes(gw *gateway_v1.Gateway) gatewayRoutes {
+	var routes gatewayRoutes
+
+	referencesGateway := func(namespace string, parentRefs []gateway_v1.ParentReference) bool {
+		for _, ref := range parentRefs {
+			if isGatewayParentRef(ref, namespace, gw) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, nsi := range lbc.namespacedInformers {
+		for _, obj := range nsi.httpRouteLister.List() {
+			route := obj.(*gateway_v1.HTTPRoute)
+			if referencesGateway(route.Namespace, route.Spec.ParentRefs) {
+				routes.httpRoutes = append(routes.httpRoutes, route)
+			}
+		}
+		for _, obj := range nsi.grpcRouteLister.List() {
+			route := obj.(*gateway_v1.GRPCRoute)
+			if referencesGateway(route.Namespace, route.Spec.ParentRefs) {
+				routes.grpcRoutes = append(routes.grpcRoutes, route)
+			}
+		}
+		if nsi.tlsRouteLister == nil {
+			continue
+		}
+		for _, obj := range nsi.tlsRouteLister.List() {
+			route := obj.(*gateway_v1alpha2.TLSRoute)
+			if referencesGateway(route.Namespace, route.Spec.ParentRefs) {
+				routes.tlsRoutes = append(routes.tlsRoutes, route)
+			}
+		}
+	}
+
+	return routes
+}
+
+// createGatewayVirtualServerEx creates the VirtualServerEx for a VirtualServer generated for a Gateway listener.
+// The VirtualServer only listens on the port of the listener.
+func (lbc *LoadBalancerController) createGatewayVirtualServerEx(gvs *gatewayVirtualServer) *configs.VirtualServerEx {
+	vsEx := lbc.createVirtualServerEx(gvs.virtualServer, nil)
+
+	vsEx.HTTPPort, vsEx.HTTPSPort = 0, 0
+	vsEx.HTTPIPv4, vsEx.HTTPIPv6, vsEx.HTTPSIPv4, vsEx.HTTPSIPv6 = "", "", "", ""
+	if gvs.listener.Ssl {
+		vsEx.HTTPSPort = gvs.listener.Port
+	} else {
+		vsEx.HTTPPort = gvs.listener.Port
+	}
+
+	return vsEx
+}
+
+func (lbc *LoadBalancerController) createGatewayExtendedResources(cfg *gatewayConfig) configs.ExtendedResources {
+	var resourceExes configs.ExtendedResources
+	for _, gvs := range cfg.virtualServers {
+		resourceExes.VirtualServerExes = append(resourceExes.VirtualServerExes, lbc.createGatewayVirtualServerEx(gvs))
+	}
+	for _, ts := range cfg.transportServers {
+		// TLS Passthrough TransportServers don't have a listener port
+		resourceExes.TransportServerExes = append(resourceExes.TransportServerExes, lbc.createTransportServerEx(ts, 0, "", ""))
+	}
+	return resourceExes
+}
+
+// applyGatewayConfig removes the configuration of the VirtualServers and TransportServers of the old configuration
+// of a Gateway that are not in the new configuration and adds or updates the configuration of the new ones.
+// It returns the warnings of the configuration of the new resources.
+func (lbc *LoadBalancerController) applyGatewayConfig(oldCfg *gatewayConfig, cfg *gatewayConfig) ([]string, error) {
+	if oldCfg != nil {
+		vsKeys := make(map[string]bool)
+		tsKeys := make(map[string]bool)
+		if cfg != nil {
+			for _, gvs := range cfg.virtualServers {
+				vsKeys[getResourceKey(&gvs.virtualServer.ObjectMeta)] = true
+			}
+			for _, ts := range cfg.transportServers {
+				tsKeys[getResourceKey(&ts.ObjectMeta)] = true
+			}
+		}
+
+		var delVsList, delTsList []string
+		for _, gvs := range oldCfg.virtualServers {
+			if key := getResourceKey(&gvs.virtualServer.ObjectMeta); !vsKeys[key] {
+				delVsList = append(delVsList, key)
+				lbc.canaryController.delete(key)
+			}
+		}
+		for _, ts := range oldCfg.transportServers {
+			if key := getResourceKey(&ts.ObjectMeta); !tsKeys[key] {
+				delTsList = append(delTsList, key)
+			}
+		}
+
+		if len(delVsList) > 0 {
+			if errs := lbc.configurator.BatchDeleteVirtualServers(delVsList); len(errs) > 0 {
+				return nil, fmt.Errorf("error deleting VirtualServers of the Gateway: %v", errs)
+			}
+		}
+		if len(delTsList) > 0 {
+			if errs := lbc.configurator.UpdateTransportServers(nil, delTsList); len(errs) > 0 {
+				return nil, fmt.Errorf("error deleting TransportServers of the Gateway: %v", errs)
+			}
+		}
+	}
+
+	if cfg == nil {
+		return nil, nil
+	}
+
+	resourceExes := lbc.createGatewayExtendedResources(cfg)
+	warnings, err := lbc.configurator.AddOrUpdateResources(resourceExes, false, nginx.ReloadForConfigUpdate)
+
+	var messages []string
+	for _, w := range warnings {
+		messages = append(messages, w...)
+	}
+	sort.Strings(messages)
+
+	return messages, err
+}
+
+// updateGatewayConfig applies the resources of the Gateway whose hosts and listeners are not taken by other resources
+// and reports the result in an event of the Gateway.
+func (lbc *LoadBalancerController) updateGatewayConfig(gwConfig *GatewayConfiguration) {
+	gw := gwConfig.Gateway
+	key := getResourceKey(&gw.ObjectMeta)
+
+	oldCfg := lbc.gatewayConfigs[key]
+	cfg := &gatewayConfig{
+		virtualServers:   gwConfig.ActiveVirtualServers(),
+		transportServers: gwConfig.ActiveTransportServers(),
+	}
+	if oldCfg != nil {
+		cfg.services, cfg.secrets, cfg.routes = oldCfg.services, oldCfg.secrets, oldCfg.routes
+	}
+	lbc.gatewayConfigs[key] = cfg
+
+	configWarnings, applyErr := lbc.applyGatewayConfig(oldCfg, cfg)
+	cfg.applyErr = applyErr
+	warnings := append(slices.Clone(gwConfig.Warnings), configWarnings...)
+
+	eventType := api_v1.EventTypeNormal
+	eventTitle := "AddedOrUpdated"
+	msg := fmt.Sprintf("Configuration for %v was added or updated", key)
+	if applyErr != nil {
+		eventType = api_v1.EventTypeWarning
+		eventTitle = "AddedOrUpdatedWithError"
+		msg = fmt.Sprintf("Configuration for %v was added or updated, but not applied: %v", key, applyErr)
+	} else if len(warnings) > 0 {
+		eventType = api_v1.EventTypeWarning
+		eventTitle = "AddedOrUpdatedWithWarning"
+		msg = fmt.Sprintf("Configuration for %v was added or updated with warning(s): %s", key, strings.Join(warnings, "; "))
+	}
+	lbc.recorder.Eventf(gw, eventType, eventTitle, msg)
+}
+
+// removeGatewayConfig removes the applied resources of the Gateway, whose hosts and listeners are all taken by other resources.
+func (lbc *LoadBalancerController) removeGatewayConfig(gwConfig *GatewayConfiguration) {
+	key := getResourceKey(&gwConfig.Gateway.ObjectMeta)
+
+	oldCfg, exists := lbc.gatewayConfigs[key]
+	if !exists {
+		return
+	}
+	cfg := &gatewayConfig{services: oldCfg.services, secrets: oldCfg.secrets, routes: oldCfg.routes}
+	lbc.gatewayConfigs[key] = cfg
+
+	if _, err := lbc.applyGatewayConfig(oldCfg, cfg); err != nil {
+		cfg.applyErr = err
+		nl.Errorf(lbc.Logger, "Error when deleting configuration for Gateway %v: %v", key, err)
+	}
+}
+
+func (lbc *LoadBalancerController) deleteGatewayConfig(key string) {
+	changes, problems := lbc.configuration.DeleteGateway(key)
+	lbc.processChanges(changes)
+	lbc.processProblems(problems)
+
+	delete(lbc.gatewayConfigs, key)
+}
+
+// updateGatewayEndpointsForService updates the endpoints of the VirtualServers and TransportServers of the Gateways
+// whose routes reference the Service. It reports whether any Gateway references the Service.
+func (lbc *LoadBalancerController) updateGatewayEndpointsForService(svcKey string) bool {
+	var resourceExes configs.ExtendedResources
+	for _, cfg := range lbc.gatewayConfigs {
+		if !cfg.services[svcKey] {
+			continue
+		}
+		exes := lbc.createGatewayExtendedResources(cfg)
+		resourceExes.VirtualServerExes = append(resourceExes.VirtualServerExes, exes.VirtualServerExes...)
+		resourceExes.TransportServerExes = append(resourceExes.TransportServerExes, exes.TransportServerExes...)
+	}
+
+	found := false
+	if len(resourceExes.VirtualServerExes) > 0 {
+		found = true
+		if err := lbc.configurator.UpdateEndpointsForVirtualServers(resourceExes.VirtualServerExes); err != nil {
+			nl.Errorf(lbc.Logger, "Error updating endpoints for Gateways for Service %v: %v", svcKey, err)
+		}
+	}
+	if len(resourceExes.TransportServerExes) > 0 {
+		found = true
+		if err := lbc.configurator.UpdateEndpointsForTransportServers(resourceExes.TransportServerExes); err != nil {
+			nl.Errorf(lbc.Logger, "Error updating endpoints for Gateways for Service %v: %v", svcKey, err)
+		}
+	}
+
+	return found
+}
+
+// enqueueGatewaysForSecret enqueues the Gateways whose listeners reference the Secret.
+func (lbc *LoadBalancerController) enqueueGatewaysForSecret(secretKey string) {
+	lbc.enqueueGatewaysWithConfig(func(cfg *gatewayConfig) bool {
+		return cfg.secrets[secretKey]
+	})
+}
+
+// enqueueGatewaysForService enqueues the Gateways whose routes reference the Service, because the Service
+// might make the references to the backends resolved or unresolved.
+func (lbc *LoadBalancerController) enqueueGatewaysForService(svcKey string) {
+	lbc.enqueueGatewaysWithConfig(func(cfg *gatewayConfig) bool {
+		return cfg.services[svcKey]
+	})
+}
+
+func (lbc *LoadBalancerController) enqueueGatewaysWithConfig(filter func(cfg *gatewayConfig) bool) {
+	for key, cfg := range lbc.gatewayConfigs {
+		if !filter(cfg) {
+			continue
+		}
+		ns, _, _ := cache.SplitMetaNamespaceKey(key)
+		nsi := lbc.getNamespacedInformer(ns)
+		if nsi == nil {
+			continue
+		}
+		obj, exists, err := nsi.gatewayLister.GetByKey(key)
+		if err != nil || !exists {
+			continue
+		}
+		lbc.AddSyncQueue(obj)
+	}
+}
+
+// enqueueGatewaysWithNamespaceSelectors enqueues the Gateways with listeners that select the namespaces of the routes by labels.
+func (lbc *LoadBalancerController) enqueueGatewaysWithNamespaceSelectors() {
+	for _, nsi := range lbc.namespacedInformers {
+		if !nsi.isGatewayAPIEnabled {
+			continue
+		}
+		for _, obj := range nsi.gatewayLister.List() {
+			gw := obj.(*gateway_v1.Gateway)
+			for _, l := range gw.Spec.Listeners {
+				if l.AllowedRoutes != nil && l.AllowedRoutes.Namespaces != nil && l.AllowedRoutes.Namespaces.From != nil &&
+					*l.AllowedRoutes.Namespaces.From == gateway_v1.NamespacesFromSelector {
+					lbc.AddSyncQueue(gw)
+					break
+				}
+			}
+		}
+	}
+}
+
+// getGatewayExtendedResources returns the resources of all the Gateways.
+func (lbc *LoadBalancerController) getGatewayExtendedResources() configs.ExtendedResources {
+	var resourceExes configs.ExtendedResources
+	for _, cfg := range lbc.gatewayConfigs {
+		exes := lbc.createGatewayExtendedResources(cfg)
+		resourceExes.VirtualServerExes = append(resourceExes.VirtualServerExes, exes.VirtualServerExes...)
+		resourceExes.TransportServerExes = append(resourceExes.TransportServerExes, exes.TransportServerExes...)
+	}
+	return resourceExes
+}
+
+// findGatewayForGeneratedResource returns the Gateway of the VirtualServer or the TransportServer generated for it.
+func (lbc *LoadBalancerController) findGatewayForGeneratedResource(resource *meta_v1.ObjectMeta) *gateway_v1.Gateway {
+	if !strings.HasPrefix(resource.Name, gatewayResourcePrefix+"_") {
+		return nil
+	}
+	resourceKey := getResourceKey(resource)
+
+	for key, cfg := range lbc.gatewayConfigs {
+		found := false
+		for _, gvs := range cfg.virtualServers {
+			found = found || getResourceKey(&gvs.virtualServer.ObjectMeta) == resourceKey
+		}
+		for _, ts := range cfg.transportServers {
+			found = found || getResourceKey(&ts.ObjectMeta) == resourceKey
+		}
+		if !found {
+			continue
+		}
+
+		ns, _, _ := cache.SplitMetaNamespaceKey(key)
+		nsi := lbc.getNamespacedInformer(ns)
+		if nsi == nil {
+			return nil
+		}
+		obj, exists, err := nsi.gatewayLister.GetByKey(key)
+		if err != nil || !exists {
+			return nil
+		}
+		return obj.(*gateway_v1.Gateway)
+	}
+
+	return nil
+}
+
+// cleanupUnwatchedGateways removes the configuration of the Gateways in the namespace that is no longer watched.
+func (lbc *LoadBalancerController) cleanupUnwatchedGateways(namespace string) {
+	for key := range lbc.gatewayConfigs {
+		if ns, _, _ := cache.SplitMetaNamespaceKey(key); ns == namespace {
+			lbc.deleteGatewayConfig(key)
+		}
+	}
+}
@@ -0,0 +1,821 @@
+package k8s
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	"github.com/nginx/kubernetes-ingress/pkg/apis/configuration/validation"
+	"k8s.io/apimachinery/pkg/api/meta"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gateway_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	gateway_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func newTestGatewayTranslator() *gatewayTranslator {
+	return &gatewayTranslator{
+		isTLSPassthroughEnabled: true,
+		tlsPassthroughPort:      443,
+		isTLSRouteEnabled:       true,
+		validateSecret: func(key string) error {
+			if key == "default/cafe-secret" {
+				return nil
+			}
+			return errors.New("secret doesn't exist")
+		},
+		serviceExists: func(key string) bool {
+			return key != "default/missing-svc"
+		},
+		validateVirtualServer: validation.NewVirtualServerValidator().ValidateVirtualServer,
+		namespaceLabels: func(namespace string) (map[string]string, bool) {
+			switch namespace {
+			case "default":
+				return nil, true
+			case "other":
+				return map[string]string{"gateway": "cafe"}, true
+			}
+			return nil, false
+		},
+	}
+}
+
+func newTestGateway(listeners ...gateway_v1.Listener) *gateway_v1.Gateway {
+	return &gateway_v1.Gateway{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:       "cafe",
+			Namespace:  "default",
+			Generation: 2,
+		},
+		Spec: gateway_v1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        listeners,
+		},
+	}
+}
+
+func newTestHTTPListener() gateway_v1.Listener {
+	return gateway_v1.Listener{Name: "http", Port: 80, Protocol: gateway_v1.HTTPProtocolType}
+}
+
+func newTestHTTPSListener(hostname string) gateway_v1.Listener {
+	h := gateway_v1.Hostname(hostname)
+	return gateway_v1.Listener{
+		Name:     "https",
+		Hostname: &h,
+		Port:     443,
+		Protocol: gateway_v1.HTTPSProtocolType,
+		TLS: &gateway_v1.GatewayTLSConfig{
+			CertificateRefs: []gateway_v1.SecretObjectReference{{Name: "cafe-secret"}},
+		},
+	}
+}
+
+func newTestBackendRef(name string, port int32, weight *int32) gateway_v1.HTTPBackendRef {
+	p := gateway_v1.PortNumber(port)
+	return gateway_v1.HTTPBackendRef{
+		BackendRef: gateway_v1.BackendRef{
+			BackendObjectReference: gateway_v1.BackendObjectReference{Name: gateway_v1.ObjectName(name), Port: &p},
+			Weight:                 weight,
+		},
+	}
+}
+
+func newTestPathMatch(pathType gateway_v1.PathMatchType, value string) []gateway_v1.HTTPRouteMatch {
+	return []gateway_v1.HTTPRouteMatch{{Path: &gateway_v1.HTTPPathMatch{Type: &pathType, Value: &value}}}
+}
+
+func findGatewayVirtualServer(translation *gatewayTranslation, name string) *conf_v1.VirtualServer {
+	for _, gvs := range translation.virtualServers {
+		if gvs.virtualServer.Name == name {
+			return gvs.virtualServer
+		}
+	}
+	return nil
+}
+
+func TestIntersectGatewayHostnames(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		listenerHostname string
+		routeHostnames   []gateway_v1.Hostname
+		expected         []string
+	}{
+		{
+			listenerHostname: "",
+			routeHostnames:   []gateway_v1.Hostname{"cafe.example.com", "tea.example.com"},
+			expected:         []string{"cafe.example.com", "tea.example.com"},
+		},
+		{
+			listenerHostname: "cafe.example.com",
+			routeHostnames:   nil,
+			expected:         []string{"cafe.example.com"},
+		},
+		{
+			listenerHostname: "*.example.com",
+			routeHostnames:   []gateway_v1.Hostname{"cafe.example.com", "cafe.example.org"},
+			expected:         []string{"cafe.example.com"},
+		},
+		{
+			listenerHostname: "cafe.example.com",
+			routeHostnames:   []gateway_v1.Hostname{"*.example.com"},
+			expected:         []string{"cafe.example.com"},
+		},
+		{
+			listenerHostname: "cafe.example.com",
+			routeHostnames:   []gateway_v1.Hostname{"tea.example.com"},
+			expected:         nil,
+		},
+	}
+
+	for _, test := range tests {
+		result := intersectGatewayHostnames(test.listenerHostname, test.routeHostnames)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("intersectGatewayHostnames(%q, %v) mismatch (-want +got):\n%s", test.listenerHostname, test.routeHostnames, diff)
+		}
+	}
+}
+
+func TestNormalizeGatewayWeights(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		weights  []int32
+		expected []int
+	}{
+		{weights: []int32{1, 1}, expected: []int{50, 50}},
+		{weights: []int32{80, 20}, expected: []int{80, 20}},
+		{weights: []int32{1, 1, 1}, expected: []int{34, 33, 33}},
+		{weights: []int32{1, 2}, expected: []int{33, 67}},
+		{weights: []int32{1000, 1}, expected: []int{100, 0}},
+	}
+
+	for _, test := range tests {
+		result := normalizeGatewayWeights(test.weights)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("normalizeGatewayWeights(%v) mismatch (-want +got):\n%s", test.weights, diff)
+		}
+	}
+}
+
+func TestTranslateGatewayHTTPRoute(t *testing.T) {
+	t.Parallel()
+
+	gw := newTestGateway(newTestHTTPListener(), newTestHTTPSListener("*.example.com"))
+	weight80, weight20 := int32(80), int32(20)
+	header := "x-version"
+	route := &gateway_v1.HTTPRoute{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "cafe", Namespace: "default", Generation: 3},
+		Spec: gateway_v1.HTTPRouteSpec{
+			CommonRouteSpec: gateway_v1.CommonRouteSpec{ParentRefs: []gateway_v1.ParentReference{{Name: "cafe"}}},
+			Hostnames:       []gateway_v1.Hostname{"cafe.example.com"},
+			Rules: []gateway_v1.HTTPRouteRule{
+				{
+					Matches:     newTestPathMatch(gateway_v1.PathMatchPathPrefix, "/coffee"),
+					BackendRefs: []gateway_v1.HTTPBackendRef{newTestBackendRef("coffee-v1", 80, &weight80), newTestBackendRef("coffee-v2", 80, &weight20)},
+				},
+				{
+					Matches: []gateway_v1.HTTPRouteMatch{{
+						Path:    newTestPathMatch(gateway_v1.PathMatchExact, "/tea")[0].Path,
+						Headers: []gateway_v1.HTTPHeaderMatch{{Name: gateway_v1.HTTPHeaderName(header), Value: "v2"}},
+					}},
+					Filters: []gateway_v1.HTTPRouteFilter{{
+						Type: gateway_v1.HTTPRouteFilterRequestHeaderModifier,
+						RequestHeaderModifier: &gateway_v1.HTTPHeaderFilter{
+							Set: []gateway_v1.HTTPHeader{{Name: "X-Tea", Value: "green"}},
+						},
+					}},
+					BackendRefs: []gateway_v1.HTTPBackendRef{newTestBackendRef("tea", 8080, nil)},
+				},
+			},
+		},
+	}
+
+	translation := newTestGatewayTranslator().translate(gw, gatewayRoutes{httpRoutes: []*gateway_v1.HTTPRoute{route}})
+
+	if len(translation.virtualServers) != 2 {
+		t.Fatalf("translate() generated %d VirtualServers, want 2", len(translation.virtualServers))
+	}
+
+	expectedRoutes := []conf_v1.Route{
+		{
+			Path: "/coffee",
+			Splits: []conf_v1.Split{
+				{Weight: 80, Action: &conf_v1.Action{Pass: "coffee-v1-80"}},
+				{Weight: 20, Action: &conf_v1.Action{Pass: "coffee-v2-80"}},
+			},
+		},
+		{
+			Path:   "=/tea",
+			Action: &conf_v1.Action{Return: &conf_v1.ActionReturn{Code: 404, Body: "Not Found"}},
+			Matches: []conf_v1.Match{{
+				Conditions: []conf_v1.Condition{{Header: header, Value: "v2"}},
+				Action: &conf_v1.Action{Proxy: &conf_v1.ActionProxy{
+					Upstream:       "tea-8080",
+					RequestHeaders: &conf_v1.ProxyRequestHeaders{Set: []conf_v1.Header{{Name: "X-Tea", Value: "green"}}},
+				}},
+			}},
+		},
+	}
+	expectedUpstreams := []conf_v1.Upstream{
+		{Name: "coffee-v1-80", Service: "coffee-v1", Port: 80},
+		{Name: "coffee-v2-80", Service: "coffee-v2", Port: 80},
+		{Name: "tea-8080", Service: "tea", Port: 8080},
+	}
+
+	httpVS := findGatewayVirtualServer(translation, "gateway_cafe_http_cafe_example_com")
+	if httpVS == nil {
+		t.Fatalf("translate() didn't generate the VirtualServer for the HTTP listener")
+	}
+	expectedHTTPSpec := conf_v1.VirtualServerSpec{
+		Host:      "cafe.example.com",
+		Listener:  &conf_v1.VirtualServerListener{HTTP: "http"},
+		Upstreams: expectedUpstreams,
+		Routes:    expectedRoutes,
+	}
+	if diff := cmp.Diff(expectedHTTPSpec, httpVS.Spec); diff != "" {
+		t.Errorf("translate() VirtualServer for the HTTP listener mismatch (-want +got):\n%s", diff)
+	}
+
+	httpsVS := findGatewayVirtualServer(translation, "gateway_cafe_https_cafe_example_com")
+	if httpsVS == nil {
+		t.Fatalf("translate() didn't generate the VirtualServer for the HTTPS listener")
+	}
+	expectedHTTPSSpec := expectedHTTPSpec
+	expectedHTTPSSpec.Listener = &conf_v1.VirtualServerListener{HTTPS: "https"}
+	expectedHTTPSSpec.TLS = &conf_v1.TLS{Secret: "cafe-secret"}
+	if diff := cmp.Diff(expectedHTTPSSpec, httpsVS.Spec); diff != "" {
+		t.Errorf("translate() VirtualServer for the HTTPS listener mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, l := range translation.listeners {
+		if l.attachedRoutes != 1 {
+			t.Errorf("translate() listener %s has %d attached routes, want 1", l.Name, l.attachedRoutes)
+		}
+	}
+
+	parents := translation.routeParents["HTTPRoute/default/cafe"]
+	if len(parents) != 1 {
+		t.Fatalf("translate() generated %d parent statuses, want 1", len(parents))
+	}
+	if !meta.IsStatusConditionTrue(parents[0].Conditions, string(gateway_v1.RouteConditionAccepted)) {
+		t.Errorf("translate() didn't accept the HTTPRoute: %v", parents[0].Conditions)
+	}
+	if c := meta.FindStatusCondition(parents[0].Conditions, string(gateway_v1.RouteConditionAccepted)); c.ObservedGeneration != 3 {
+		t.Errorf("translate() set observed generation %d, want 3", c.ObservedGeneration)
+	}
+	if !meta.IsStatusConditionTrue(translation.conditions, string(gateway_v1.GatewayConditionAccepted)) {
+		t.Errorf("translate() didn't accept the Gateway: %v", translation.conditions)
+	}
+	if !translation.services["default/tea"] || !translation.secrets["default/cafe-secret"] {
+		t.Errorf("translate() didn't record the references: services %v, secrets %v", translation.services, translation.secrets)
+	}
+}
+
+func TestTranslateGatewayHTTPRouteFilters(t *testing.T) {
+	t.Parallel()
+
+	gw := newTestGateway(newTestHTTPListener())
+	replacePath := "/beans"
+	statusCode := 301
+	route := &gateway_v1.HTTPRoute{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "cafe", Namespace: "default"},
+		Spec: gateway_v1.HTTPRouteSpec{
+			CommonRouteSpec: gateway_v1.CommonRouteSpec{ParentRefs: []gateway_v1.ParentReference{{Name: "cafe"}}},
+			Hostnames:       []gateway_v1.Hostname{"cafe.example.com"},
+			Rules: []gateway_v1.HTTPRouteRule{
+				{
+					Matches: newTestPathMatch(gateway_v1.PathMatchPathPrefix, "/coffee"),
+					Filters: []gateway_v1.HTTPRouteFilter{
+						{
+							Type: gateway_v1.HTTPRouteFilterURLRewrite,
+							URLRewrite: &gateway_v1.HTTPURLRewriteFilter{
+								Path: &gateway_v1.HTTPPathModifier{Type: gateway_v1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: &replacePath},
+							},
+						},
+						{
+							Type: gateway_v1.HTTPRouteFilterResponseHeaderModifier,
+							ResponseHeaderModifier: &gateway_v1.HTTPHeaderFilter{
+								Set:    []gateway_v1.HTTPHeader{{Name: "X-Cafe", Value: "open"}},
+								Remove: []string{"Server"},
+							},
+						},
+					},
+					BackendRefs: []gateway_v1.HTTPBackendRef{newTestBackendRef("coffee", 80, nil)},
+				},
+				{
+					Matches: newTestPathMatch(gateway_v1.PathMatchPathPrefix, "/old"),
+					Filters: []gateway_v1.HTTPRouteFilter{{
+						Type: gateway_v1.HTTPRouteFilterRequestRedirect,
+						RequestRedirect: &gateway_v1.HTTPRequestRedirectFilter{
+							Path:       &gateway_v1.HTTPPathModifier{Type: gateway_v1.FullPathHTTPPathModifier, ReplaceFullPath: &replacePath},
+							StatusCode: &statusCode,
+						},
+					}},
+				},
+				{
+					Matches: newTestPathMatch(gateway_v1.PathMatchPathPrefix, "/ext"),
+					Filters: []gateway_v1.HTTPRouteFilter{{
+						Type:         gateway_v1.HTTPRouteFilterExtensionRef,
+						ExtensionRef: &gateway_v1.LocalObjectReference{Name: "ext"},
+					}},
+					BackendRefs: []gateway_v1.HTTPBackendRef{newTestBackendRef("coffee", 80, nil)},
+				},
+			},
+		},
+	}
+
+	translation := newTestGatewayTranslator().translate(gw, gatewayRoutes{httpRoutes: []*gateway_v1.HTTPRoute{route}})
+
+	vs := findGatewayVirtualServer(translation, "gateway_cafe_http_cafe_example_com")
+	if vs == nil {
+		t.Fatalf("translate() didn't generate the VirtualServer")
+	}
+
+	expectedRoutes := []conf_v1.Route{
+		{
+			Path: "/coffee",
+			Action: &conf_v1.Action{Proxy: &conf_v1.ActionProxy{
+				Upstream:    "coffee-80",
+				RewritePath: "/beans",
+				ResponseHeaders: &conf_v1.ProxyResponseHeaders{
+					Hide: []string{"X-Cafe", "Server"},
+					Add:  []conf_v1.AddHeader{{Header: conf_v1.Header{Name: "X-Cafe", Value: "open"}, Always: true}},
+				},
+			}},
+		},
+		{
+			Path:   "/old",
+			Action: &conf_v1.Action{Redirect: &conf_v1.ActionRedirect{URL: "${scheme}://${host}/beans", Code: 301}},
+		},
+	}
+	if diff := cmp.Diff(expectedRoutes, vs.Spec.Routes); diff != "" {
+		t.Errorf("translate() routes mismatch (-want +got):\n%s", diff)
+	}
+
+	parents := translation.routeParents["HTTPRoute/default/cafe"]
+	if len(parents) != 1 {
+		t.Fatalf("translate() generated %d parent statuses, want 1", len(parents))
+	}
+	if !meta.IsStatusConditionTrue(parents[0].Conditions, string(gateway_v1.RouteConditionAccepted)) {
+		t.Errorf("translate() didn't accept the HTTPRoute with a supported rule: %v", parents[0].Conditions)
+	}
+	if !meta.IsStatusConditionTrue(parents[0].Conditions, string(gateway_v1.RouteConditionPartiallyInvalid)) {
+		t.Errorf("translate() didn't report the unsupported rule: %v", parents[0].Conditions)
+	}
+}
+
+func TestTranslateGatewayHTTPRouteBackends(t *testing.T) {
+	t.Parallel()
+
+	gw := newTestGateway(newTestHTTPListener())
+	otherNamespace := gateway_v1.Namespace("other")
+	ref := newTestBackendRef("tea", 80, nil)
+	ref.Namespace = &otherNamespace
+	route := &gateway_v1.HTTPRoute{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "cafe", Namespace: "default"},
+		Spec: gateway_v1.HTTPRouteSpec{
+			CommonRouteSpec: gateway_v1.CommonRouteSpec{ParentRefs: []gateway_v1.ParentReference{{Name: "cafe"}}},
+			Hostnames:       []gateway_v1.Hostname{"cafe.example.com"},
+			Rules: []gateway_v1.HTTPRouteRule{
+				{BackendRefs: []gateway_v1.HTTPBackendRef{ref}},
+			},
+		},
+	}
+
+	translation := newTestGatewayTranslator().translate(gw, gatewayRoutes{httpRoutes: []*gateway_v1.HTTPRoute{route}})
+
+	vs := findGatewayVirtualServer(translation, "gateway_cafe_http_cafe_example_com")
+	if vs == nil {
+		t.Fatalf("translate() didn't generate the VirtualServer")
+	}
+	expectedAction := &conf_v1.Action{Return: &conf_v1.ActionReturn{Code: 500, Body: "Internal Server Error"}}
+	if diff := cmp.Diff(expectedAction, vs.Spec.Routes[0].Action); diff != "" {
+		t.Errorf("translate() action mismatch (-want +got):\n%s", diff)
+	}
+
+	resolvedRefs := meta.FindStatusCondition(translation.routeParents["HTTPRoute/default/cafe"][0].Conditions, string(gateway_v1.RouteConditionResolvedRefs))
+	if resolvedRefs == nil || resolvedRefs.Status != meta_v1.ConditionFalse || resolvedRefs.Reason != string(gateway_v1.RouteReasonRefNotPermitted) {
+		t.Errorf("translate() ResolvedRefs condition is %v, want False with reason RefNotPermitted", resolvedRefs)
+	}
+}
+
+func TestTranslateGatewayGRPCRoute(t *testing.T) {
+	t.Parallel()
+
+	gw := newTestGateway(newTestHTTPSListener("grpc.example.com"))
+	service, method := "helloworld.Greeter", "SayHello"
+	port := gateway_v1.PortNumber(50051)
+	route := &gateway_v1.GRPCRoute{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "greeter", Namespace: "default"},
+		Spec: gateway_v1.GRPCRouteSpec{
+			CommonRouteSpec: gateway_v1.CommonRouteSpec{ParentRefs: []gateway_v1.ParentReference{{Name: "cafe"}}},
+			Rules: []gateway_v1.GRPCRouteRule{
+				{
+					Matches: []gateway_v1.GRPCRouteMatch{
+						{Method: &gateway_v1.GRPCMethodMatch{Service: &service, Method: &method}},
+						{Method: &gateway_v1.GRPCMethodMatch{Service: &service}},
+					},
+					BackendRefs: []gateway_v1.GRPCBackendRef{{
+						BackendRef: gateway_v1.BackendRef{BackendObjectReference: gateway_v1.BackendObjectReference{Name: "greeter", Port: &port}},
+					}},
+				},
+			},
+		},
+	}
+
+	translation := newTestGatewayTranslator().translate(gw, gatewayRoutes{grpcRoutes: []*gateway_v1.GRPCRoute{route}})
+
+	vs := findGatewayVirtualServer(translation, "gateway_cafe_https_grpc_example_com")
+	if vs == nil {
+		t.Fatalf("translate() didn't generate the VirtualServer")
+	}
+
+	expectedUpstreams := []conf_v1.Upstream{{Name: "grpc-greeter-50051", Service: "greeter", Port: 50051, Type: "grpc"}}
+	if diff := cmp.Diff(expectedUpstreams, vs.Spec.Upstreams); diff != "" {
+		t.Errorf("translate() upstreams mismatch (-want +got):\n%s", diff)
+	}
+
+	var paths []string
+	for _, r := range vs.Spec.Routes {
+		paths = append(paths, r.Path)
+	}
+	if diff := cmp.Diff([]string{"=/helloworld.Greeter/SayHello", "/helloworld.Greeter/"}, paths); diff != "" {
+		t.Errorf("translate() paths mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTranslateGatewayRouteNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	gw := newTestGateway(newTestHTTPListener(), newTestHTTPSListener("cafe.example.com"))
+	gatewayNamespace := gateway_v1.Namespace("default")
+	httpsSection := gateway_v1.SectionName("https")
+
+	otherNamespaceRoute := &gateway_v1.HTTPRoute{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "other", Namespace: "other"},
+		Spec: gateway_v1.HTTPRouteSpec{
+			CommonRouteSpec: gateway_v1.CommonRouteSpec{ParentRefs: []gateway_v1.ParentReference{{Name: "cafe", Namespace: &gatewayNamespace}}},
+			Hostnames:       []gateway_v1.Hostname{"other.example.com"},
+		},
+	}
+	mismatchedHostnameRoute := &gateway_v1.HTTPRoute{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "tea", Namespace: "default"},
+		Spec: gateway_v1.HTTPRouteSpec{
+			CommonRouteSpec: gateway_v1.CommonRouteSpec{ParentRefs: []gateway_v1.ParentReference{{Name: "cafe", SectionName: &httpsSection}}},
+			Hostnames:       []gateway_v1.Hostname{"tea.example.com"},
+		},
+	}
+
+	translation := newTestGatewayTranslator().translate(gw, gatewayRoutes{httpRoutes: []*gateway_v1.HTTPRoute{otherNamespaceRoute, mismatchedHostnameRoute}})
+
+	if len(translation.virtualServers) != 0 {
+		t.Errorf("translate() generated %d VirtualServers, want 0", len(translation.virtualServers))
+	}
+
+	tests := []struct {
+		routeKey string
+		reason   gateway_v1.RouteConditionReason
+	}{
+		{routeKey: "HTTPRoute/other/other", reason: gateway_v1.RouteReasonNotAllowedByListeners},
+		{routeKey: "HTTPRoute/default/tea", reason: gateway_v1.RouteReasonNoMatchingListenerHostname},
+	}
+	for _, test := range tests {
+		parents := translation.routeParents[test.routeKey]
+		if len(parents) != 1 {
+			t.Fatalf("translate() generated %d parent statuses for %s, want 1", len(parents), test.routeKey)
+		}
+		accepted := meta.FindStatusCondition(parents[0].Conditions, string(gateway_v1.RouteConditionAccepted))
+		if accepted == nil || accepted.Status != meta_v1.ConditionFalse || accepted.Reason != string(test.reason) {
+			t.Errorf("translate() Accepted condition of %s is %v, want False with reason %s", test.routeKey, accepted, test.reason)
+		}
+	}
+}
+
+func TestTranslateGatewayRouteAllowedNamespaces(t *testing.T) {
+	t.Parallel()
+
+	newListener := func(name string, port int32, namespaces *gateway_v1.RouteNamespaces) gateway_v1.Listener {
+		return gateway_v1.Listener{
+			Name:          gateway_v1.SectionName(name),
+			Port:          gateway_v1.PortNumber(port),
+			Protocol:      gateway_v1.HTTPProtocolType,
+			AllowedRoutes: &gateway_v1.AllowedRoutes{Namespaces: namespaces},
+		}
+	}
+	fromAll, fromSelector := gateway_v1.NamespacesFromAll, gateway_v1.NamespacesFromSelector
+	gw := newTestGateway(
+		newListener("same", 80, nil),
+		newListener("all", 8080, &gateway_v1.RouteNamespaces{From: &fromAll}),
+		newListener("selector", 8081, &gateway_v1.RouteNamespaces{
+			From:     &fromSelector,
+			Selector: &meta_v1.LabelSelector{MatchLabels: map[string]string{"gateway": "cafe"}},
+		}),
+	)
+	gatewayNamespace := gateway_v1.Namespace("default")
+	newRoute := func(namespace string) *gateway_v1.HTTPRoute {
+		return &gateway_v1.HTTPRoute{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "cafe", Namespace: namespace},
+			Spec: gateway_v1.HTTPRouteSpec{
+				CommonRouteSpec: gateway_v1.CommonRouteSpec{ParentRefs: []gateway_v1.ParentReference{{Name: "cafe", Namespace: &gatewayNamespace}}},
+				Hostnames:       []gateway_v1.Hostname{gateway_v1.Hostname(namespace + ".example.com")},
+				Rules:           []gateway_v1.HTTPRouteRule{{BackendRefs: []gateway_v1.HTTPBackendRef{newTestBackendRef("coffee", 80, nil)}}},
+			},
+		}
+	}
+
+	translation := newTestGatewayTranslator().translate(gw, gatewayRoutes{httpRoutes: []*gateway_v1.HTTPRoute{newRoute("default"), newRoute("other"), newRoute("unlabeled")}})
+
+	expectedNames := []string{
+		"gateway_cafe_same_default_example_com",
+		"gateway_cafe_all_default_example_com",
+		"gateway_cafe_all_other_example_com",
+		"gateway_cafe_selector_other_example_com",
+		"gateway_cafe_all_unlabeled_example_com",
+	}
+	var names []string
+	for _, gvs := range translation.virtualServers {
+		names = append(names, gvs.virtualServer.Name)
+	}
+	if diff := cmp.Diff(expectedNames, names); diff != "" {
+		t.Errorf("translate() VirtualServers mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, l := range translation.listeners {
+		if l.attachedRoutes == 0 {
+			t.Errorf("translate() attached no routes to listener %s", l.Name)
+		}
+	}
+}
+
+func TestTranslateGatewayRouteNamespaceSelectorWithoutSelector(t *testing.T) {
+	t.Parallel()
+
+	fromSelector := gateway_v1.NamespacesFromSelector
+	listener := newTestHTTPListener()
+	listener.AllowedRoutes = &gateway_v1.AllowedRoutes{Namespaces: &gateway_v1.RouteNamespaces{From: &fromSelector}}
+
+	translation := newTestGatewayTranslator().translate(newTestGateway(listener), gatewayRoutes{})
+
+	accepted := meta.FindStatusCondition(translation.listeners[0].conditions, string(gateway_v1.ListenerConditionAccepted))
+	if accepted == nil || accepted.Status != meta_v1.ConditionFalse {
+		t.Errorf("translate() Accepted condition of the listener is %v, want False", accepted)
+	}
+}
+
+func TestTranslateGatewayInvalidRoute(t *testing.T) {
+	t.Parallel()
+
+	gw := newTestGateway(newTestHTTPListener())
+	newRoute := func(name string, path string) *gateway_v1.HTTPRoute {
+		return &gateway_v1.HTTPRoute{
+			ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: gateway_v1.HTTPRouteSpec{
+				CommonRouteSpec: gateway_v1.CommonRouteSpec{ParentRefs: []gateway_v1.ParentReference{{Name: "cafe"}}},
+				Hostnames:       []gateway_v1.Hostname{"cafe.example.com"},
+				Rules: []gateway_v1.HTTPRouteRule{{
+					Matches:     newTestPathMatch(gateway_v1.PathMatchRegularExpression, path),
+					BackendRefs: []gateway_v1.HTTPBackendRef{newTestBackendRef(name, 80, nil)},
+				}},
+			},
+		}
+	}
+	validRoute := newRoute("coffee", "/coffee/[a-z]+")
+	invalidRoute := newRoute("tea", "/tea/[a-z")
+
+	translation := newTestGatewayTranslator().translate(gw, gatewayRoutes{httpRoutes: []*gateway_v1.HTTPRoute{validRoute, invalidRoute}})
+
+	vs := findGatewayVirtualServer(translation, "gateway_cafe_http_cafe_example_com")
+	if vs == nil {
+		t.Fatalf("translate() didn't generate the VirtualServer")
+	}
+	expectedRoutes := []conf_v1.Route{{Path: "~ /coffee/[a-z]+", Action: &conf_v1.Action{Pass: "coffee-80"}}}
+	if diff := cmp.Diff(expectedRoutes, vs.Spec.Routes); diff != "" {
+		t.Errorf("translate() routes mismatch (-want +got):\n%s", diff)
+	}
+
+	tests := []struct {
+		routeKey string
+		accepted bool
+	}{
+		{routeKey: "HTTPRoute/default/coffee", accepted: true},
+		{routeKey: "HTTPRoute/default/tea", accepted: false},
+	}
+	for _, test := range tests {
+		parents := translation.routeParents[test.routeKey]
+		if len(parents) != 1 {
+			t.Fatalf("translate() generated %d parent statuses for %s, want 1", len(parents), test.routeKey)
+		}
+		accepted := meta.FindStatusCondition(parents[0].Conditions, string(gateway_v1.RouteConditionAccepted))
+		if accepted == nil || (accepted.Status == meta_v1.ConditionTrue) != test.accepted {
+			t.Errorf("translate() Accepted condition of %s is %v, want accepted %v", test.routeKey, accepted, test.accepted)
+		}
+		if !test.accepted && accepted.Reason != string(gateway_v1.RouteReasonUnsupportedValue) {
+			t.Errorf("translate() Accepted condition of %s has reason %s, want %s", test.routeKey, accepted.Reason, gateway_v1.RouteReasonUnsupportedValue)
+		}
+	}
+}
+
+func TestTranslateGatewayListeners(t *testing.T) {
+	t.Parallel()
+
+	passthrough := gateway_v1.TLSModePassthrough
+	tlsListener := gateway_v1.Listener{
+		Name:     "tls",
+		Port:     443,
+		Protocol: gateway_v1.TLSProtocolType,
+		TLS:      &gateway_v1.GatewayTLSConfig{Mode: &passthrough},
+	}
+	duplicateHTTPListener := newTestHTTPListener()
+	duplicateHTTPListener.Name = "http-duplicate"
+	tcpListener := gateway_v1.Listener{Name: "tcp", Port: 5432, Protocol: gateway_v1.TCPProtocolType}
+	otherPortHTTPSListener := newTestHTTPSListener("cafe.example.com")
+	otherPortHTTPSListener.Name = "https-8443"
+	otherPortHTTPSListener.Port = 8443
+	missingSecretListener := newTestHTTPSListener("tea.example.com")
+	missingSecretListener.Name = "https-missing-secret"
+	missingSecretListener.TLS.CertificateRefs[0].Name = "missing"
+
+	gw := newTestGateway(newTestHTTPListener(), duplicateHTTPListener, newTestHTTPSListener("cafe.example.com"), tlsListener, tcpListener,
+		otherPortHTTPSListener, missingSecretListener)
+
+	translation := newTestGatewayTranslator().translate(gw, gatewayRoutes{})
+
+	tests := []struct {
+		listener      string
+		accepted      bool
+		reason        gateway_v1.ListenerConditionReason
+		conflicted    bool
+		resolvedRefs  bool
+		resolveReason gateway_v1.ListenerConditionReason
+	}{
+		{listener: "http", accepted: false, reason: gateway_v1.ListenerReasonHostnameConflict, conflicted: true, resolvedRefs: true},
+		{listener: "http-duplicate", accepted: false, reason: gateway_v1.ListenerReasonHostnameConflict, conflicted: true, resolvedRefs: true},
+		{listener: "https", accepted: true, reason: gateway_v1.ListenerReasonAccepted, resolvedRefs: true},
+		{listener: "tls", accepted: true, reason: gateway_v1.ListenerReasonAccepted, resolvedRefs: true},
+		{listener: "tcp", accepted: false, reason: gateway_v1.ListenerReasonUnsupportedProtocol, resolvedRefs: true},
+		{listener: "https-8443", accepted: false, reason: gateway_v1.ListenerReasonPortUnavailable, resolvedRefs: true},
+		{
+			listener: "https-missing-secret", accepted: true, reason: gateway_v1.ListenerReasonAccepted,
+			resolvedRefs: false, resolveReason: gateway_v1.ListenerReasonInvalidCertificateRef,
+		},
+	}
+
+	for _, test := range tests {
+		var listener *gatewayListener
+		for _, l := range translation.listeners {
+			if l.Name == test.listener {
+				listener = l
+			}
+		}
+		if listener == nil {
+			t.Fatalf("translate() didn't translate listener %s", test.listener)
+		}
+
+		if listener.accepted != test.accepted {
+			t.Errorf("translate() listener %s accepted is %v, want %v", test.listener, listener.accepted, test.accepted)
+		}
+		accepted := meta.FindStatusCondition(listener.conditions, string(gateway_v1.ListenerConditionAccepted))
+		if accepted.Reason != string(test.reason) {
+			t.Errorf("translate() listener %s Accepted reason is %s, want %s", test.listener, accepted.Reason, test.reason)
+		}
+		if meta.IsStatusConditionTrue(listener.conditions, string(gateway_v1.ListenerConditionConflicted)) != test.conflicted {
+			t.Errorf("translate() listener %s Conflicted condition is %v, want %v", test.listener, !test.conflicted, test.conflicted)
+		}
+		resolvedRefs := meta.FindStatusCondition(listener.conditions, string(gateway_v1.ListenerConditionResolvedRefs))
+		if (resolvedRefs.Status == meta_v1.ConditionTrue) != test.resolvedRefs {
+			t.Errorf("translate() listener %s ResolvedRefs condition is %v, want %v", test.listener, resolvedRefs.Status, test.resolvedRefs)
+		}
+		if !test.resolvedRefs && resolvedRefs.Reason != string(test.resolveReason) {
+			t.Errorf("translate() listener %s ResolvedRefs reason is %s, want %s", test.listener, resolvedRefs.Reason, test.resolveReason)
+		}
+	}
+
+	accepted := meta.FindStatusCondition(translation.conditions, string(gateway_v1.GatewayConditionAccepted))
+	if accepted.Status != meta_v1.ConditionTrue || accepted.Reason != string(gateway_v1.GatewayReasonListenersNotValid) {
+		t.Errorf("translate() Gateway Accepted condition is %v, want True with reason ListenersNotValid", accepted)
+	}
+}
+
+func TestTranslateGatewayTLSRoute(t *testing.T) {
+	t.Parallel()
+
+	passthrough := gateway_v1.TLSModePassthrough
+	gw := newTestGateway(gateway_v1.Listener{
+		Name:     "tls",
+		Port:     443,
+		Protocol: gateway_v1.TLSProtocolType,
+		TLS:      &gateway_v1.GatewayTLSConfig{Mode: &passthrough},
+	})
+	port := gateway_v1.PortNumber(8443)
+	route := &gateway_v1alpha2.TLSRoute{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "secure", Namespace: "default"},
+		Spec: gateway_v1alpha2.TLSRouteSpec{
+			CommonRouteSpec: gateway_v1.CommonRouteSpec{ParentRefs: []gateway_v1.ParentReference{{Name: "cafe"}}},
+			Hostnames:       []gateway_v1.Hostname{"secure.example.com"},
+			Rules: []gateway_v1alpha2.TLSRouteRule{{
+				BackendRefs: []gateway_v1.BackendRef{{BackendObjectReference: gateway_v1.BackendObjectReference{Name: "secure-app", Port: &port}}},
+			}},
+		},
+	}
+
+	translation := newTestGatewayTranslator().translate(gw, gatewayRoutes{tlsRoutes: []*gateway_v1alpha2.TLSRoute{route}})
+
+	if len(translation.transportServers) != 1 {
+		t.Fatalf("translate() generated %d TransportServers, want 1", len(translation.transportServers))
+	}
+
+	expected := &conf_v1.TransportServer{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "gateway_cafe_tls_secure_example_com", Namespace: "default"},
+		Spec: conf_v1.TransportServerSpec{
+			Listener: conf_v1.TransportServerListener{
+				Name:     conf_v1.TLSPassthroughListenerName,
+				Protocol: conf_v1.TLSPassthroughListenerProtocol,
+			},
+			Host:      "secure.example.com",
+			Upstreams: []conf_v1.TransportServerUpstream{{Name: "secure-app-8443", Service: "secure-app", Port: 8443}},
+			Action:    &conf_v1.TransportServerAction{Pass: "secure-app-8443"},
+		},
+	}
+	if diff := cmp.Diff(expected, translation.transportServers[0]); diff != "" {
+		t.Errorf("translate() TransportServer mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTranslateGatewayWithoutTLSPassthrough(t *testing.T) {
+	t.Parallel()
+
+	passthrough := gateway_v1.TLSModePassthrough
+	gw := newTestGateway(gateway_v1.Listener{
+		Name:     "tls",
+		Port:     443,
+		Protocol: gateway_v1.TLSProtocolType,
+		TLS:      &gateway_v1.GatewayTLSConfig{Mode: &passthrough},
+	})
+
+	translator := newTestGatewayTranslator()
+	translator.isTLSPassthroughEnabled = false
+	translation := translator.translate(gw, gatewayRoutes{})
+
+	accepted := meta.FindStatusCondition(translation.listeners[0].conditions, string(gateway_v1.ListenerConditionAccepted))
+	if accepted.Status != meta_v1.ConditionFalse || accepted.Reason != string(gateway_v1.ListenerReasonUnsupportedProtocol) {
+		t.Errorf("translate() listener Accepted condition is %v, want False with reason UnsupportedProtocol", accepted)
+	}
+	if meta.IsStatusConditionTrue(translation.conditions, string(gateway_v1.GatewayConditionAccepted)) {
+		t.Errorf("translate() accepted the Gateway without valid listeners")
+	}
+}
+
+func TestMergeRouteParentStatuses(t *testing.T) {
+	t.Parallel()
+
+	gw := newTestGateway(newTestHTTPListener())
+	transitionTime := meta_v1.Unix(1000, 0)
+	existing := []gateway_v1.RouteParentStatus{
+		{
+			ParentRef:      gateway_v1.ParentReference{Name: "cafe"},
+			ControllerName: IngressControllerName,
+			Conditions: []meta_v1.Condition{
+				{Type: string(gateway_v1.RouteConditionAccepted), Status: meta_v1.ConditionTrue, Reason: "Accepted", LastTransitionTime: transitionTime},
+				{Type: string(gateway_v1.RouteConditionPartiallyInvalid), Status: meta_v1.ConditionTrue, Reason: "UnsupportedValue", LastTransitionTime: transitionTime},
+			},
+		},
+		{
+			ParentRef:      gateway_v1.ParentReference{Name: "other"},
+			ControllerName: IngressControllerName,
+		},
+		{
+			ParentRef:      gateway_v1.ParentReference{Name: "cafe"},
+			ControllerName: "example.com/other-controller",
+		},
+	}
+	parents := []gateway_v1.RouteParentStatus{{
+		ParentRef:      gateway_v1.ParentReference{Name: "cafe"},
+		ControllerName: IngressControllerName,
+		Conditions: []meta_v1.Condition{
+			{Type: string(gateway_v1.RouteConditionAccepted), Status: meta_v1.ConditionTrue, Reason: "Accepted"},
+		},
+	}}
+
+	merged := mergeRouteParentStatuses(existing, "default", gw, parents)
+
+	expected := []gateway_v1.RouteParentStatus{
+		existing[1],
+		existing[2],
+		{
+			ParentRef:      gateway_v1.ParentReference{Name: "cafe"},
+			ControllerName: IngressControllerName,
+			Conditions: []meta_v1.Condition{
+				{Type: string(gateway_v1.RouteConditionAccepted), Status: meta_v1.ConditionTrue, Reason: "Accepted", LastTransitionTime: transitionTime},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, merged); diff != "" {
+		t.Errorf("mergeRouteParentStatuses() mismatch (-want +got):\n%s", diff)
+	}
+
+	if merged := mergeRouteParentStatuses(existing, "default", gw, nil); len(merged) != 2 {
+		t.Errorf("mergeRouteParentStatuses() kept %d parent statuses without new statuses, want 2", len(merged))
+	}
+}
@@ -0,0 +1,1269 @@
+package k8s
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	gateway_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	gateway_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+const (
+	gatewayKind      = "Gateway"
+	httpRouteKind    = "HTTPRoute"
+	grpcRouteKind    = "GRPCRoute"
+	tlsRouteKind     = "TLSRoute"
+	serviceKind      = "Service"
+	secretKind       = "Secret"
+	gatewayAPIGroup  = gateway_v1.GroupName
+	grpcUpstreamType = "grpc"
+
+	// gatewayResourcePrefix is the prefix of the names of the VirtualServers and TransportServers generated for the
+	// listeners of a Gateway. The names can't clash with the names of the resources, because resource names can't include underscores.
+	gatewayResourcePrefix = "gateway"
+)
+
+// gatewayRoutes holds the routes that reference a Gateway in their parent references.
+type gatewayRoutes struct {
+	httpRoutes []*gateway_v1.HTTPRoute
+	grpcRoutes []*gateway_v1.GRPCRoute
+	tlsRoutes  []*gateway_v1alpha2.TLSRoute
+}
+
+// gatewayListener is a listener of a Gateway translated into a GlobalConfiguration listener.
+type gatewayListener struct {
+	conf_v1.Listener
+	hostname       string
+	secret         string
+	kinds          []string
+	accepted       bool
+	attachedRoutes int32
+	conditions     []meta_v1.Condition
+	// namespacesFrom and namespaceSelector define the namespaces of the routes that can attach to the listener.
+	namespacesFrom    gateway_v1.FromNamespaces
+	namespaceSelector labels.Selector
+}
+
+// gatewayVirtualServer is the VirtualServer generated for a hostname of an HTTP or HTTPS listener.
+type gatewayVirtualServer struct {
+	virtualServer *conf_v1.VirtualServer
+	listener      *gatewayListener
+}
+
+// gatewayTranslation is the configuration generated for a Gateway and the routes attached to its listeners.
+type gatewayTranslation struct {
+	virtualServers   []*gatewayVirtualServer
+	transportServers []*conf_v1.TransportServer
+	listeners        []*gatewayListener
+	conditions       []meta_v1.Condition
+	// routeParents holds the statuses of the parent references to the Gateway by the key of the route, see getGatewayRouteKey.
+	routeParents map[string][]gateway_v1.RouteParentStatus
+	// services and secrets hold the keys of the Services and the Secrets referenced by the Gateway and its routes.
+	services map[string]bool
+	secrets  map[string]bool
+}
+
+// gatewayTranslator translates a Gateway and the routes attached to its listeners into VirtualServers
+// and TransportServers, so that NGINX is configured for them like for the custom resources.
+// HTTPRoutes and GRPCRoutes become the routes of the VirtualServers generated for the hostnames of HTTP and HTTPS listeners,
+// TLSRoutes become TLS Passthrough TransportServers.
+type gatewayTranslator struct {
+	isTLSPassthroughEnabled bool
+	tlsPassthroughPort      int
+	isTLSRouteEnabled       bool
+	// validateSecret returns an error if the TLS Secret with the key doesn't exist or is invalid.
+	validateSecret func(key string) error
+	// serviceExists reports whether the Service with the key exists.
+	serviceExists func(key string) bool
+	// validateVirtualServer returns an error if the generated VirtualServer is invalid.
+	validateVirtualServer func(vs *conf_v1.VirtualServer) error
+	// namespaceLabels returns the labels of the namespace or false if the namespace doesn't exist.
+	namespaceLabels func(namespace string) (map[string]string, bool)
+}
+
+// gatewayMatch is a match of a rule of a route translated into the path and the conditions of a VirtualServer route.
+type gatewayMatch struct {
+	path       string
+	conditions []conf_v1.Condition
+}
+
+// gatewayRule is a rule of an HTTPRoute or a GRPCRoute translated into the action of VirtualServer routes.
+type gatewayRule struct {
+	matches   []gatewayMatch
+	action    *conf_v1.Action
+	splits    []conf_v1.Split
+	upstreams []conf_v1.Upstream
+}
+
+// gatewayRouteResult holds the problems found during the translation of a route.
+type gatewayRouteResult struct {
+	// unsupported holds the reasons why rules of the route were dropped.
+	unsupported []string
+	// unresolvedReason and unresolvedRefs hold the reason and the problems of the references to the backends that couldn't be resolved.
+	unresolvedReason gateway_v1.RouteConditionReason
+	unresolvedRefs   []string
+}
+
+func (r *gatewayRouteResult) addUnresolvedRef(reason gateway_v1.RouteConditionReason, format string, args ...interface{}) {
+	if r.unresolvedReason == "" {
+		r.unresolvedReason = reason
+	}
+	r.unresolvedRefs = append(r.unresolvedRefs, fmt.Sprintf(format, args...))
+}
+
+// gatewayServer accumulates the routes for a hostname of an HTTP or HTTPS listener.
+type gatewayServer struct {
+	listener  *gatewayListener
+	hostname  string
+	upstreams []conf_v1.Upstream
+	routes    []*gatewayServerRoute
+}
+
+type gatewayServerRoute struct {
+	route      conf_v1.Route
+	hasDefault bool
+}
+
+// gatewayAttachment is a listener that a route is attached to with the hostnames of the route for the listener.
+type gatewayAttachment struct {
+	listener  *gatewayListener
+	hostnames []string
+}
+
+func getGatewayRouteKey(kind string, route meta_v1.Object) string {
+	return fmt.Sprintf("%s/%s/%s", kind, route.GetNamespace(), route.GetName())
+}
+
+// getGatewayResourceName returns the name of the VirtualServer or the TransportServer generated for the hostname of the listener.
+func getGatewayResourceName(gw *gateway_v1.Gateway, listener string, hostname string) string {
+	safeHostname := strings.ReplaceAll(strings.ReplaceAll(hostname, "*", "wildcard"), ".", "_")
+	return fmt.Sprintf("%s_%s_%s_%s", gatewayResourcePrefix, gw.Name, listener, safeHostname)
+}
+
+func newGatewayCondition(condType string, status bool, reason string, message string, generation int64) meta_v1.Condition {
+	conditionStatus := meta_v1.ConditionFalse
+	if status {
+		conditionStatus = meta_v1.ConditionTrue
+	}
+	return meta_v1.Condition{
+		Type:               condType,
+		Status:             conditionStatus,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	}
+}
+
+func (t *gatewayTranslator) translate(gw *gateway_v1.Gateway, routes gatewayRoutes) *gatewayTranslation {
+	translation := &gatewayTranslation{
+		routeParents: make(map[string][]gateway_v1.RouteParentStatus),
+		services:     make(map[string]bool),
+		secrets:      make(map[string]bool),
+	}
+
+	translation.listeners = t.translateListeners(gw, translation)
+
+	var servers []*gatewayServer
+	getServer := func(listener *gatewayListener, hostname string) *gatewayServer {
+		for _, s := range servers {
+			if s.listener == listener && s.hostname == hostname {
+				return s
+			}
+		}
+		s := &gatewayServer{listener: listener, hostname: hostname}
+		servers = append(servers, s)
+		return s
+	}
+
+	httpRoutes := slices.Clone(routes.httpRoutes)
+	sortGatewayRoutes(httpRoutes)
+	for _, route := range httpRoutes {
+		result := &gatewayRouteResult{}
+		rules := t.translateHTTPRouteRules(route, result, translation)
+		t.attachRoute(gw, httpRouteKind, route, route.Spec.ParentRefs, route.Spec.Hostnames, len(route.Spec.Rules), rules, result, translation, getServer)
+	}
+
+	grpcRoutes := slices.Clone(routes.grpcRoutes)
+	sortGatewayRoutes(grpcRoutes)
+	for _, route := range grpcRoutes {
+		result := &gatewayRouteResult{}
+		rules := t.translateGRPCRouteRules(route, result, translation)
+		t.attachRoute(gw, grpcRouteKind, route, route.Spec.ParentRefs, route.Spec.Hostnames, len(route.Spec.Rules), rules, result, translation, getServer)
+	}
+
+	tlsRoutes := slices.Clone(routes.tlsRoutes)
+	sortGatewayRoutes(tlsRoutes)
+	for _, route := range tlsRoutes {
+		t.attachTLSRoute(gw, route, translation)
+	}
+
+	for _, s := range servers {
+		translation.virtualServers = append(translation.virtualServers, s.virtualServer(gw))
+	}
+
+	translation.conditions = getGatewayConditions(gw, translation.listeners)
+
+	return translation
+}
+
+// sortGatewayRoutes sorts the routes by the precedence of the Gateway API: the oldest route comes first,
+// then the routes in the alphabetical order of their namespace and name.
+func sortGatewayRoutes[T meta_v1.Object](routes []T) {
+	sort.SliceStable(routes, func(i, j int) bool {
+		ti, tj := routes[i].GetCreationTimestamp(), routes[j].GetCreationTimestamp()
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return fmt.Sprintf("%s/%s", routes[i].GetNamespace(), routes[i].GetName()) < fmt.Sprintf("%s/%s", routes[j].GetNamespace(), routes[j].GetName())
+	})
+}
+
+func getGatewayConditions(gw *gateway_v1.Gateway, listeners []*gatewayListener) []meta_v1.Condition {
+	if len(gw.Spec.Addresses) > 0 {
+		message := "Addresses of Gateways are not supported, the Gateway uses the addresses of the Ingress Controller"
+		return []meta_v1.Condition{
+			newGatewayCondition(string(gateway_v1.GatewayConditionAccepted), false, string(gateway_v1.GatewayReasonUnsupportedAddress), message, gw.Generation),
+			newGatewayCondition(string(gateway_v1.GatewayConditionProgrammed), false, string(gateway_v1.GatewayReasonInvalid), message, gw.Generation),
+		}
+	}
+
+	accepted := 0
+	for _, l := range listeners {
+		if l.accepted {
+			accepted++
+		}
+	}
+
+	var acceptedCondition meta_v1.Condition
+	switch {
+	case accepted == len(listeners):
+		acceptedCondition = newGatewayCondition(string(gateway_v1.GatewayConditionAccepted), true, string(gateway_v1.GatewayReasonAccepted), "Gateway is accepted", gw.Generation)
+	case accepted > 0:
+		acceptedCondition = newGatewayCondition(string(gateway_v1.GatewayConditionAccepted), true, string(gateway_v1.GatewayReasonListenersNotValid), "Some listeners of the Gateway are invalid", gw.Generation)
+	default:
+		acceptedCondition = newGatewayCondition(string(gateway_v1.GatewayConditionAccepted), false, string(gateway_v1.GatewayReasonListenersNotValid), "All listeners of the Gateway are invalid", gw.Generation)
+	}
+
+	return []meta_v1.Condition{acceptedCondition}
+}
+
+func (t *gatewayTranslator) translateListeners(gw *gateway_v1.Gateway, translation *gatewayTranslation) []*gatewayListener {
+	var listeners []*gatewayListener
+
+	for _, l := range gw.Spec.Listeners {
+		listener := &gatewayListener{
+			Listener: conf_v1.Listener{
+				Name: string(l.Name),
+				Port: int(l.Port),
+			},
+			namespacesFrom: gateway_v1.NamespacesFromSame,
+			accepted:       true,
+		}
+		if l.Hostname != nil {
+			listener.hostname = string(*l.Hostname)
+		}
+
+		acceptedReason, acceptedMessage := string(gateway_v1.ListenerReasonAccepted), "Listener is accepted"
+		resolvedReason, resolvedMessage := string(gateway_v1.ListenerReasonResolvedRefs), "All references are resolved"
+		resolved := true
+
+		switch l.Protocol {
+		case gateway_v1.HTTPProtocolType:
+			listener.Protocol = "HTTP"
+			listener.kinds = []string{httpRouteKind, grpcRouteKind}
+		case gateway_v1.HTTPSProtocolType:
+			listener.Protocol = "HTTP"
+			listener.Ssl = true
+			listener.kinds = []string{httpRouteKind, grpcRouteKind}
+
+			if t.isTLSPassthroughEnabled && listener.Port != t.tlsPassthroughPort {
+				listener.accepted = false
+				acceptedReason = string(gateway_v1.ListenerReasonPortUnavailable)
+				acceptedMessage = fmt.Sprintf("HTTPS listeners must use the TLS Passthrough port %d when TLS Passthrough is enabled", t.tlsPassthroughPort)
+			} else if l.TLS != nil && l.TLS.Mode != nil && *l.TLS.Mode != gateway_v1.TLSModeTerminate {
+				listener.accepted = false
+				acceptedReason = string(gateway_v1.ListenerReasonUnsupportedProtocol)
+				acceptedMessage = "HTTPS listeners only support the Terminate TLS mode"
+			} else {
+				var err error
+				listener.secret, err = t.translateCertificateRefs(gw, l.TLS, translation)
+				if err != nil {
+					resolved = false
+					resolvedReason, resolvedMessage = string(gateway_v1.ListenerReasonInvalidCertificateRef), err.Error()
+				}
+			}
+		case gateway_v1.TLSProtocolType:
+			listener.Protocol = conf_v1.TLSPassthroughListenerProtocol
+			listener.kinds = []string{tlsRouteKind}
+
+			switch {
+			case !t.isTLSPassthroughEnabled || !t.isTLSRouteEnabled:
+				listener.accepted = false
+				acceptedReason = string(gateway_v1.ListenerReasonUnsupportedProtocol)
+				acceptedMessage = "TLS listeners require TLS Passthrough and the TLSRoute resource"
+			case l.TLS == nil || l.TLS.Mode == nil || *l.TLS.Mode != gateway_v1.TLSModePassthrough:
+				listener.accepted = false
+				acceptedReason = string(gateway_v1.ListenerReasonUnsupportedProtocol)
+				acceptedMessage = "TLS listeners only support the Passthrough TLS mode"
+			case listener.Port != t.tlsPassthroughPort:
+				listener.accepted = false
+				acceptedReason = string(gateway_v1.ListenerReasonPortUnavailable)
+				acceptedMessage = fmt.Sprintf("TLS listeners must use the TLS Passthrough port %d", t.tlsPassthroughPort)
+			}
+		default:
+			listener.accepted = false
+			acceptedReason = string(gateway_v1.ListenerReasonUnsupportedProtocol)
+			acceptedMessage = fmt.Sprintf("Protocol %s is not supported", l.Protocol)
+		}
+
+		if l.AllowedRoutes != nil && len(l.AllowedRoutes.Kinds) > 0 {
+			var kinds []string
+			for _, k := range l.AllowedRoutes.Kinds {
+				if (k.Group == nil || *k.Group == gatewayAPIGroup) && slices.Contains(listener.kinds, string(k.Kind)) {
+					kinds = append(kinds, string(k.Kind))
+				} else {
+					resolved = false
+					resolvedReason = string(gateway_v1.ListenerReasonInvalidRouteKinds)
+					resolvedMessage = fmt.Sprintf("Route kind %s is not supported by the listener", k.Kind)
+				}
+			}
+			listener.kinds = kinds
+		}
+
+		if l.AllowedRoutes != nil && l.AllowedRoutes.Namespaces != nil && l.AllowedRoutes.Namespaces.From != nil {
+			listener.namespacesFrom = *l.AllowedRoutes.Namespaces.From
+			if listener.namespacesFrom == gateway_v1.NamespacesFromSelector {
+				var err error
+				listener.namespaceSelector, err = translateNamespaceSelector(l.AllowedRoutes.Namespaces.Selector)
+				if err != nil && listener.accepted {
+					listener.accepted = false
+					acceptedReason, acceptedMessage = string(gateway_v1.ListenerReasonInvalid), err.Error()
+				}
+			}
+		}
+
+		listener.conditions = []meta_v1.Condition{
+			newGatewayCondition(string(gateway_v1.ListenerConditionAccepted), listener.accepted, acceptedReason, acceptedMessage, gw.Generation),
+			newGatewayCondition(string(gateway_v1.ListenerConditionResolvedRefs), resolved, resolvedReason, resolvedMessage, gw.Generation),
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	t.detectListenerConflicts(gw, listeners)
+
+	for _, l := range listeners {
+		programmedReason, programmedMessage := string(gateway_v1.ListenerReasonProgrammed), "Listener is programmed"
+		if !l.accepted {
+			programmedReason, programmedMessage = string(gateway_v1.ListenerReasonInvalid), "Listener is invalid"
+		}
+		l.conditions = append(l.conditions, newGatewayCondition(string(gateway_v1.ListenerConditionProgrammed), l.accepted, programmedReason, programmedMessage, gw.Generation))
+	}
+
+	return listeners
+}
+
+// translateNamespaceSelector translates the selector of the namespaces of the routes allowed by a listener.
+func translateNamespaceSelector(selector *meta_v1.LabelSelector) (labels.Selector, error) {
+	if selector == nil {
+		return nil, fmt.Errorf("allowed routes from the Selector namespaces require a selector")
+	}
+	result, err := meta_v1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("selector of the namespaces of the allowed routes is invalid: %w", err)
+	}
+	return result, nil
+}
+
+// allowsNamespace reports whether the listener allows the routes in the namespace to attach to it.
+func (t *gatewayTranslator) allowsNamespace(gw *gateway_v1.Gateway, l *gatewayListener, namespace string) bool {
+	switch l.namespacesFrom {
+	case gateway_v1.NamespacesFromAll:
+		return true
+	case gateway_v1.NamespacesFromSelector:
+		nsLabels, exists := t.namespaceLabels(namespace)
+		return exists && l.namespaceSelector.Matches(labels.Set(nsLabels))
+	default:
+		return namespace == gw.Namespace
+	}
+}
+
+// detectListenerConflicts marks the listeners that share a port with listeners of an incompatible protocol
+// or that share a port, a protocol and a hostname with other listeners.
+// HTTPS and TLS listeners can share the TLS Passthrough port, because NGINX routes the connections by the SNI.
+func (t *gatewayTranslator) detectListenerConflicts(gw *gateway_v1.Gateway, listeners []*gatewayListener) {
+	protocolFamily := func(l *gatewayListener) string {
+		if l.Ssl || l.Protocol == conf_v1.TLSPassthroughListenerProtocol {
+			if t.isTLSPassthroughEnabled && l.Port == t.tlsPassthroughPort {
+				return "TLS"
+			}
+		}
+		if l.Ssl {
+			return "HTTPS"
+		}
+		return l.Protocol
+	}
+
+	for i, l := range listeners {
+		conflictReason := string(gateway_v1.ListenerReasonNoConflicts)
+		for j, other := range listeners {
+			if i == j || l.Port != other.Port {
+				continue
+			}
+			if protocolFamily(l) != protocolFamily(other) {
+				conflictReason = string(gateway_v1.ListenerReasonProtocolConflict)
+				break
+			}
+			if l.Protocol == other.Protocol && l.Ssl == other.Ssl && l.hostname == other.hostname {
+				conflictReason = string(gateway_v1.ListenerReasonHostnameConflict)
+			}
+		}
+
+		conflicted := conflictReason != string(gateway_v1.ListenerReasonNoConflicts)
+		message := "Listener has no conflicts"
+		if conflicted {
+			message = "Listener conflicts with other listeners on the same port"
+			if l.accepted {
+				l.accepted = false
+				l.conditions[0] = newGatewayCondition(string(gateway_v1.ListenerConditionAccepted), false, conflictReason, message, gw.Generation)
+			}
+		}
+		l.conditions = append(l.conditions, newGatewayCondition(string(gateway_v1.ListenerConditionConflicted), conflicted, conflictReason, message, gw.Generation))
+	}
+}
+
+func (t *gatewayTranslator) translateCertificateRefs(gw *gateway_v1.Gateway, tls *gateway_v1.GatewayTLSConfig, translation *gatewayTranslation) (string, error) {
+	if tls == nil || len(tls.CertificateRefs) == 0 {
+		return "", fmt.Errorf("HTTPS listeners require a certificate reference")
+	}
+
+	ref := tls.CertificateRefs[0]
+	if (ref.Group != nil && *ref.Group != "") || (ref.Kind != nil && *ref.Kind != secretKind) {
+		return "", fmt.Errorf("certificate reference %s must reference a Secret", ref.Name)
+	}
+	if ref.Namespace != nil && string(*ref.Namespace) != gw.Namespace {
+		return "", fmt.Errorf("certificate reference %s must reference a Secret in the namespace of the Gateway", ref.Name)
+	}
+
+	key := fmt.Sprintf("%s/%s", gw.Namespace, ref.Name)
+	translation.secrets[key] = true
+	if err := t.validateSecret(key); err != nil {
+		return string(ref.Name), fmt.Errorf("certificate reference %s is invalid: %w", ref.Name, err)
+	}
+
+	return string(ref.Name), nil
+}
+
+// intersectGatewayHostnames returns the hostnames of the route that match the hostname of the listener.
+// A wildcard hostname of the listener or the route is replaced by the more specific hostname.
+func intersectGatewayHostnames(listenerHostname string, routeHostnames []gateway_v1.Hostname) []string {
+	if listenerHostname == "" {
+		var hostnames []string
+		for _, h := range routeHostnames {
+			hostnames = append(hostnames, string(h))
+		}
+		return hostnames
+	}
+
+	if len(routeHostnames) == 0 {
+		return []string{listenerHostname}
+	}
+
+	var hostnames []string
+	for _, h := range routeHostnames {
+		hostname := string(h)
+		switch {
+		case hostname == listenerHostname:
+			hostnames = append(hostnames, hostname)
+		case strings.HasPrefix(listenerHostname, "*.") && strings.HasSuffix(hostname, listenerHostname[1:]):
+			hostnames = append(hostnames, hostname)
+		case strings.HasPrefix(hostname, "*.") && strings.HasSuffix(listenerHostname, hostname[1:]):
+			hostnames = append(hostnames, listenerHostname)
+		}
+	}
+
+	return hostnames
+}
+
+// isGatewayParentRef reports whether the parent reference of the route in the namespace references the Gateway.
+func isGatewayParentRef(ref gateway_v1.ParentReference, routeNamespace string, gw *gateway_v1.Gateway) bool {
+	if ref.Group != nil && *ref.Group != gatewayAPIGroup {
+		return false
+	}
+	if ref.Kind != nil && *ref.Kind != gatewayKind {
+		return false
+	}
+	namespace := routeNamespace
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+	return namespace == gw.Namespace && string(ref.Name) == gw.Name
+}
+
+// findGatewayAttachments returns the listeners of the Gateway that the parent reference attaches the route to.
+// If the route can't be attached, it returns the reason and the message for the Accepted condition.
+func (t *gatewayTranslator) findGatewayAttachments(gw *gateway_v1.Gateway, listeners []*gatewayListener, kind string, routeNamespace string,
+	ref gateway_v1.ParentReference, routeHostnames []gateway_v1.Hostname,
+) ([]gatewayAttachment, gateway_v1.RouteConditionReason, string) {
+	var attachments []gatewayAttachment
+	reason := gateway_v1.RouteReasonNoMatchingParent
+	message := "No listener of the Gateway matches the parent reference"
+
+	for _, l := range listeners {
+		if ref.SectionName != nil && string(*ref.SectionName) != l.Name {
+			continue
+		}
+		if ref.Port != nil && int(*ref.Port) != l.Port {
+			continue
+		}
+		if !l.accepted {
+			reason, message = gateway_v1.RouteReasonNoMatchingParent, fmt.Sprintf("Listener %s is invalid", l.Name)
+			continue
+		}
+		if !slices.Contains(l.kinds, kind) {
+			reason, message = gateway_v1.RouteReasonNotAllowedByListeners, fmt.Sprintf("Listener %s doesn't allow %s routes", l.Name, kind)
+			continue
+		}
+		if !t.allowsNamespace(gw, l, routeNamespace) {
+			reason, message = gateway_v1.RouteReasonNotAllowedByListeners, fmt.Sprintf("Listener %s doesn't allow routes from namespace %s", l.Name, routeNamespace)
+			continue
+		}
+
+		hostnames := intersectGatewayHostnames(l.hostname, routeHostnames)
+		if len(hostnames) == 0 {
+			reason, message = gateway_v1.RouteReasonNoMatchingListenerHostname, fmt.Sprintf("No hostname of the route matches the hostname of listener %s", l.Name)
+			if l.hostname == "" {
+				message = fmt.Sprintf("Routes attached to listener %s without a hostname must have hostnames", l.Name)
+			}
+			continue
+		}
+
+		attachments = append(attachments, gatewayAttachment{listener: l, hostnames: hostnames})
+	}
+
+	return attachments, reason, message
+}
+
+// attachRoute adds the rules of an HTTPRoute or a GRPCRoute to the servers of the listeners that the route is attached to
+// and records the statuses of the parent references of the route to the Gateway.
+func (t *gatewayTranslator) attachRoute(gw *gateway_v1.Gateway, kind string, route meta_v1.Object, parentRefs []gateway_v1.ParentReference,
+	routeHostnames []gateway_v1.Hostname, ruleCount int, rules []gatewayRule, result *gatewayRouteResult, translation *gatewayTranslation,
+	getServer func(*gatewayListener, string) *gatewayServer,
+) {
+	routeKey := getGatewayRouteKey(kind, route)
+	attachedListeners := make(map[*gatewayListener]bool)
+
+	for _, ref := range parentRefs {
+		if !isGatewayParentRef(ref, route.GetNamespace(), gw) {
+			continue
+		}
+
+		attachments, reason, message := t.findGatewayAttachments(gw, translation.listeners, kind, route.GetNamespace(), ref, routeHostnames)
+		accepted := len(attachments) > 0
+		if accepted && len(rules) == 0 && ruleCount > 0 {
+			accepted = false
+			reason, message = gateway_v1.RouteReasonUnsupportedValue, strings.Join(result.unsupported, "; ")
+		}
+		if accepted {
+			if err := t.validateRules(gw, attachments, rules); err != nil {
+				accepted = false
+				reason, message = gateway_v1.RouteReasonUnsupportedValue, fmt.Sprintf("The configuration generated for the route is invalid: %v", err)
+			}
+		}
+
+		if accepted {
+			reason, message = gateway_v1.RouteReasonAccepted, "Route is accepted"
+			for _, a := range attachments {
+				attachedListeners[a.listener] = true
+				for _, hostname := range a.hostnames {
+					server := getServer(a.listener, hostname)
+					for _, rule := range rules {
+						server.addRule(rule)
+					}
+				}
+			}
+		}
+
+		translation.routeParents[routeKey] = append(translation.routeParents[routeKey], newRouteParentStatus(ref, route.GetGeneration(), accepted, reason, message, result))
+	}
+
+	for l := range attachedListeners {
+		l.attachedRoutes++
+	}
+}
+
+// validateRules validates the VirtualServers generated for the rules of a route for every hostname it is attached to,
+// so that the values of the route that are invalid in NGINX reject the route and not the other routes of the hostnames.
+func (t *gatewayTranslator) validateRules(gw *gateway_v1.Gateway, attachments []gatewayAttachment, rules []gatewayRule) error {
+	for _, a := range attachments {
+		for _, hostname := range a.hostnames {
+			server := &gatewayServer{listener: a.listener, hostname: hostname}
+			for _, rule := range rules {
+				server.addRule(rule)
+			}
+			if err := t.validateVirtualServer(server.virtualServer(gw).virtualServer); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func newRouteParentStatus(ref gateway_v1.ParentReference, generation int64, accepted bool, reason gateway_v1.RouteConditionReason, message string,
+	result *gatewayRouteResult,
+) gateway_v1.RouteParentStatus {
+	status := gateway_v1.RouteParentStatus{
+		ParentRef:      ref,
+		ControllerName: gateway_v1.GatewayController(IngressControllerName),
+	}
+
+	status.Conditions = append(status.Conditions, newGatewayCondition(string(gateway_v1.RouteConditionAccepted), accepted, string(reason), message, generation))
+
+	if result.unresolvedReason != "" {
+		status.Conditions = append(status.Conditions, newGatewayCondition(string(gateway_v1.RouteConditionResolvedRefs), false, string(result.unresolvedReason),
+			strings.Join(result.unresolvedRefs, "; "), generation))
+	} else {
+		status.Conditions = append(status.Conditions, newGatewayCondition(string(gateway_v1.RouteConditionResolvedRefs), true, string(gateway_v1.RouteReasonResolvedRefs),
+			"All references are resolved", generation))
+	}
+
+	if accepted && len(result.unsupported) > 0 {
+		status.Conditions = append(status.Conditions, newGatewayCondition(string(gateway_v1.RouteConditionPartiallyInvalid), true, string(gateway_v1.RouteReasonUnsupportedValue),
+			strings.Join(result.unsupported, "; "), generation))
+	}
+
+	return status
+}
+
+func (s *gatewayServer) addRule(rule gatewayRule) {
+	for _, u := range rule.upstreams {
+		if !slices.ContainsFunc(s.upstreams, func(existing conf_v1.Upstream) bool { return existing.Name == u.Name }) {
+			s.upstreams = append(s.upstreams, u)
+		}
+	}
+
+	for _, m := range rule.matches {
+		var route *gatewayServerRoute
+		for _, r := range s.routes {
+			if r.route.Path == m.path {
+				route = r
+				break
+			}
+		}
+		if route == nil {
+			route = &gatewayServerRoute{route: conf_v1.Route{Path: m.path}}
+			s.routes = append(s.routes, route)
+		}
+
+		if len(m.conditions) == 0 {
+			// a rule without conditions for the same path of a route with a higher precedence wins
+			if !route.hasDefault {
+				route.route.Action = rule.action
+				route.route.Splits = rule.splits
+				route.hasDefault = true
+			}
+			continue
+		}
+
+		route.route.Matches = append(route.route.Matches, conf_v1.Match{
+			Conditions: m.conditions,
+			Action:     rule.action,
+			Splits:     rule.splits,
+		})
+	}
+}
+
+func (s *gatewayServer) virtualServer(gw *gateway_v1.Gateway) *gatewayVirtualServer {
+	vs := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: gw.Namespace,
+			Name:      getGatewayResourceName(gw, s.listener.Name, s.hostname),
+		},
+		Spec: conf_v1.VirtualServerSpec{
+			Host:      s.hostname,
+			Listener:  &conf_v1.VirtualServerListener{},
+			Upstreams: s.upstreams,
+		},
+	}
+
+	if s.listener.Ssl {
+		vs.Spec.Listener.HTTPS = s.listener.Name
+		vs.Spec.TLS = &conf_v1.TLS{Secret: s.listener.secret}
+	} else {
+		vs.Spec.Listener.HTTP = s.listener.Name
+	}
+
+	for _, r := range s.routes {
+		route := r.route
+		if !r.hasDefault {
+			route.Action = &conf_v1.Action{Return: &conf_v1.ActionReturn{Code: 404, Body: "Not Found"}}
+		}
+		// the matches with more conditions are more specific, so they are checked first
+		sort.SliceStable(route.Matches, func(i, j int) bool {
+			return len(route.Matches[i].Conditions) > len(route.Matches[j].Conditions)
+		})
+		vs.Spec.Routes = append(vs.Spec.Routes, route)
+	}
+
+	return &gatewayVirtualServer{virtualServer: vs, listener: s.listener}
+}
+
+// translateBackendRefs translates the references to the backends of a rule into upstreams with weights.
+func (t *gatewayTranslator) translateBackendRefs(routeNamespace string, refs []gateway_v1.BackendRef, upstreamType string,
+	result *gatewayRouteResult, translation *gatewayTranslation,
+) ([]conf_v1.Upstream, []int32) {
+	var upstreams []conf_v1.Upstream
+	var weights []int32
+
+	for _, ref := range refs {
+		weight := int32(1)
+		if ref.Weight != nil {
+			weight = *ref.Weight
+		}
+		if weight == 0 {
+			continue
+		}
+
+		upstream, ok := t.translateBackendRef(routeNamespace, ref.BackendObjectReference, upstreamType, result, translation)
+		if !ok {
+			continue
+		}
+
+		upstreams = append(upstreams, upstream)
+		weights = append(weights, weight)
+	}
+
+	return upstreams, weights
+}
+
+func (t *gatewayTranslator) translateBackendRef(routeNamespace string, ref gateway_v1.BackendObjectReference, upstreamType string,
+	result *gatewayRouteResult, translation *gatewayTranslation,
+) (conf_v1.Upstream, bool) {
+	if (ref.Group != nil && *ref.Group != "") || (ref.Kind != nil && *ref.Kind != serviceKind) {
+		result.addUnresolvedRef(gateway_v1.RouteReasonInvalidKind, "backend %s must reference a Service", ref.Name)
+		return conf_v1.Upstream{}, false
+	}
+	if ref.Namespace != nil && string(*ref.Namespace) != routeNamespace {
+		result.addUnresolvedRef(gateway_v1.RouteReasonRefNotPermitted, "backend %s must reference a Service in the namespace of the route", ref.Name)
+		return conf_v1.Upstream{}, false
+	}
+	if ref.Port == nil {
+		result.addUnresolvedRef(gateway_v1.RouteReasonUnsupportedValue, "backend %s must have a port", ref.Name)
+		return conf_v1.Upstream{}, false
+	}
+
+	key := fmt.Sprintf("%s/%s", routeNamespace, ref.Name)
+	translation.services[key] = true
+	if !t.serviceExists(key) {
+		result.addUnresolvedRef(gateway_v1.RouteReasonBackendNotFound, "Service %s doesn't exist", ref.Name)
+	}
+
+	name := fmt.Sprintf("%s-%d", ref.Name, *ref.Port)
+	if upstreamType == grpcUpstreamType {
+		name = fmt.Sprintf("grpc-%s", name)
+	}
+
+	return conf_v1.Upstream{
+		Name:    name,
+		Service: string(ref.Name),
+		Port:    uint16(*ref.Port), //nolint:gosec
+		Type:    upstreamType,
+	}, true
+}
+
+// normalizeGatewayWeights converts the weights of the backends into the weights of splits that add up to 100.
+func normalizeGatewayWeights(weights []int32) []int {
+	var total int64
+	for _, w := range weights {
+		total += int64(w)
+	}
+
+	normalized := make([]int, len(weights))
+	sum := 0
+	largest := 0
+	for i, w := range weights {
+		normalized[i] = int(int64(w) * 100 / total)
+		sum += normalized[i]
+		if weights[i] > weights[largest] {
+			largest = i
+		}
+	}
+	// the rounding remainder goes to the backend with the largest weight
+	normalized[largest] += 100 - sum
+
+	return normalized
+}
+
+// gatewayFilters are the filters of a rule translated into the fields of the actions of a VirtualServer.
+type gatewayFilters struct {
+	requestHeaders  *conf_v1.ProxyRequestHeaders
+	responseHeaders *conf_v1.ProxyResponseHeaders
+	rewritePath     string
+	rewrites        []conf_v1.ProxyRewrite
+	redirect        *conf_v1.ActionRedirect
+	mirror          *conf_v1.Upstream
+}
+
+func (f *gatewayFilters) addRequestHeaders(filter *gateway_v1.HTTPHeaderFilter) {
+	if f.requestHeaders == nil {
+		f.requestHeaders = &conf_v1.ProxyRequestHeaders{}
+	}
+	// NGINX can only set a request header, so added values replace the values of the request
+	for _, h := range append(slices.Clone(filter.Set), filter.Add...) {
+		f.requestHeaders.Set = append(f.requestHeaders.Set, conf_v1.Header{Name: string(h.Name), Value: h.Value})
+	}
+	// NGINX doesn't pass a request header with an empty value
+	for _, name := range filter.Remove {
+		f.requestHeaders.Set = append(f.requestHeaders.Set, conf_v1.Header{Name: name})
+	}
+}
+
+func (f *gatewayFilters) addResponseHeaders(filter *gateway_v1.HTTPHeaderFilter) {
+	if f.responseHeaders == nil {
+		f.responseHeaders = &conf_v1.ProxyResponseHeaders{}
+	}
+	for _, h := range filter.Set {
+		f.responseHeaders.Hide = append(f.responseHeaders.Hide, string(h.Name))
+		f.responseHeaders.Add = append(f.responseHeaders.Add, conf_v1.AddHeader{Header: conf_v1.Header{Name: string(h.Name), Value: h.Value}, Always: true})
+	}
+	for _, h := range filter.Add {
+		f.responseHeaders.Add = append(f.responseHeaders.Add, conf_v1.AddHeader{Header: conf_v1.Header{Name: string(h.Name), Value: h.Value}, Always: true})
+	}
+	f.responseHeaders.Hide = append(f.responseHeaders.Hide, filter.Remove...)
+}
+
+func (f *gatewayFilters) addURLRewrite(filter *gateway_v1.HTTPURLRewriteFilter) error {
+	if filter.Hostname != nil {
+		f.addRequestHeaders(&gateway_v1.HTTPHeaderFilter{Set: []gateway_v1.HTTPHeader{{Name: "Host", Value: string(*filter.Hostname)}}})
+	}
+	if filter.Path == nil {
+		return nil
+	}
+
+	switch filter.Path.Type {
+	case gateway_v1.FullPathHTTPPathModifier:
+		if filter.Path.ReplaceFullPath == nil {
+			return fmt.Errorf("URLRewrite filter must have the full path")
+		}
+		f.rewrites = append(f.rewrites, conf_v1.ProxyRewrite{Match: "^.*$", Replace: *filter.Path.ReplaceFullPath})
+	case gateway_v1.PrefixMatchHTTPPathModifier:
+		if filter.Path.ReplacePrefixMatch == nil {
+			return fmt.Errorf("URLRewrite filter must have the prefix")
+		}
+		f.rewritePath = *filter.Path.ReplacePrefixMatch
+	default:
+		return fmt.Errorf("path modifier %s of the URLRewrite filter is not supported", filter.Path.Type)
+	}
+
+	return nil
+}
+
+func (f *gatewayFilters) addRequestRedirect(filter *gateway_v1.HTTPRequestRedirectFilter) error {
+	// the variables of the redirect URL are limited to the ones allowed in the redirect actions of VirtualServers
+	scheme := "${scheme}"
+	if filter.Scheme != nil {
+		scheme = *filter.Scheme
+	}
+	host := "${host}"
+	if filter.Hostname != nil {
+		host = string(*filter.Hostname)
+	}
+	if filter.Port != nil {
+		host = fmt.Sprintf("%s:%d", host, *filter.Port)
+	}
+	path := "${request_uri}"
+	if filter.Path != nil {
+		if filter.Path.Type != gateway_v1.FullPathHTTPPathModifier || filter.Path.ReplaceFullPath == nil {
+			return fmt.Errorf("only the ReplaceFullPath path modifier is supported in the RequestRedirect filter")
+		}
+		path = *filter.Path.ReplaceFullPath
+	}
+
+	code := 302
+	if filter.StatusCode != nil {
+		code = *filter.StatusCode
+	}
+
+	f.redirect = &conf_v1.ActionRedirect{URL: fmt.Sprintf("%s://%s%s", scheme, host, path), Code: code}
+	return nil
+}
+
+// action returns the action of a VirtualServer route that passes the requests to the upstream and applies the filters.
+func (f *gatewayFilters) action(upstream string) *conf_v1.Action {
+	action := &conf_v1.Action{Pass: upstream}
+
+	if f.requestHeaders != nil || f.responseHeaders != nil || f.rewritePath != "" || len(f.rewrites) > 0 {
+		action = &conf_v1.Action{
+			Proxy: &conf_v1.ActionProxy{
+				Upstream:        upstream,
+				RequestHeaders:  f.requestHeaders,
+				ResponseHeaders: f.responseHeaders,
+				RewritePath:     f.rewritePath,
+				Rewrites:        f.rewrites,
+			},
+		}
+	}
+
+	if f.mirror != nil {
+		action.Mirror = &conf_v1.ActionMirror{Upstream: f.mirror.Name}
+	}
+
+	return action
+}
+
+// newGatewayRule builds the action or the splits of the rule for the upstreams of the backends.
+func newGatewayRule(matches []gatewayMatch, filters *gatewayFilters, upstreams []conf_v1.Upstream, weights []int32) gatewayRule {
+	rule := gatewayRule{matches: matches}
+
+	switch {
+	case filters.redirect != nil:
+		rule.action = &conf_v1.Action{Redirect: filters.redirect}
+		return rule
+	case len(upstreams) == 0:
+		// the Gateway API requires 500 responses for the requests to a rule without valid backends
+		rule.action = &conf_v1.Action{Return: &conf_v1.ActionReturn{Code: 500, Body: "Internal Server Error"}}
+		return rule
+	case len(upstreams) == 1:
+		rule.action = filters.action(upstreams[0].Name)
+	default:
+		for i, w := range normalizeGatewayWeights(weights) {
+			if w == 0 {
+				continue
+			}
+			rule.splits = append(rule.splits, conf_v1.Split{Weight: w, Action: filters.action(upstreams[i].Name)})
+		}
+		if len(rule.splits) == 1 {
+			rule.action, rule.splits = rule.splits[0].Action, nil
+		}
+	}
+
+	rule.upstreams = upstreams
+	if filters.mirror != nil {
+		rule.upstreams = append(rule.upstreams, *filters.mirror)
+	}
+
+	return rule
+}
+
+func (t *gatewayTranslator) translateHTTPRouteRules(route *gateway_v1.HTTPRoute, result *gatewayRouteResult, translation *gatewayTranslation) []gatewayRule {
+	var rules []gatewayRule
+
+	for i, r := range route.Spec.Rules {
+		matches, err := translateHTTPRouteMatches(r.Matches)
+		if err != nil {
+			result.unsupported = append(result.unsupported, fmt.Sprintf("rule %d: %v", i, err))
+			continue
+		}
+
+		filters, err := t.translateHTTPRouteFilters(route.Namespace, r.Filters, result, translation)
+		if err != nil {
+			result.unsupported = append(result.unsupported, fmt.Sprintf("rule %d: %v", i, err))
+			continue
+		}
+
+		var refs []gateway_v1.BackendRef
+		for _, ref := range r.BackendRefs {
+			if len(ref.Filters) > 0 {
+				err = fmt.Errorf("filters of backends are not supported")
+				break
+			}
+			refs = append(refs, ref.BackendRef)
+		}
+		if err != nil {
+			result.unsupported = append(result.unsupported, fmt.Sprintf("rule %d: %v", i, err))
+			continue
+		}
+
+		upstreams, weights := t.translateBackendRefs(route.Namespace, refs, "", result, translation)
+		rules = append(rules, newGatewayRule(matches, filters, upstreams, weights))
+	}
+
+	return rules
+}
+
+func translateHTTPRouteMatches(routeMatches []gateway_v1.HTTPRouteMatch) ([]gatewayMatch, error) {
+	if len(routeMatches) == 0 {
+		return []gatewayMatch{{path: "/"}}, nil
+	}
+
+	var matches []gatewayMatch
+	for _, m := range routeMatches {
+		match := gatewayMatch{path: "/"}
+
+		if m.Path != nil && m.Path.Value != nil {
+			pathType := gateway_v1.PathMatchPathPrefix
+			if m.Path.Type != nil {
+				pathType = *m.Path.Type
+			}
+			switch pathType {
+			case gateway_v1.PathMatchExact:
+				match.path = "=" + *m.Path.Value
+			case gateway_v1.PathMatchPathPrefix:
+				match.path = *m.Path.Value
+			case gateway_v1.PathMatchRegularExpression:
+				if _, err := regexp.Compile(*m.Path.Value); err != nil {
+					return nil, fmt.Errorf("invalid regular expression of the path: %w", err)
+				}
+				match.path = "~ " + *m.Path.Value
+			default:
+				return nil, fmt.Errorf("path match type %s is not supported", pathType)
+			}
+		}
+
+		for _, h := range m.Headers {
+			if h.Type != nil && *h.Type != gateway_v1.HeaderMatchExact {
+				return nil, fmt.Errorf("header match type %s is not supported", *h.Type)
+			}
+			match.conditions = append(match.conditions, conf_v1.Condition{Header: string(h.Name), Value: h.Value})
+		}
+
+		for _, q := range m.QueryParams {
+			if q.Type != nil && *q.Type != gateway_v1.QueryParamMatchExact {
+				return nil, fmt.Errorf("query parameter match type %s is not supported", *q.Type)
+			}
+			match.conditions = append(match.conditions, conf_v1.Condition{Argument: string(q.Name), Value: q.Value})
+		}
+
+		if m.Method != nil {
+			match.conditions = append(match.conditions, conf_v1.Condition{Variable: "$request_method", Value: string(*m.Method)})
+		}
+
+		matches = append(matches, match)
+	}
+
+	return matches, nil
+}
+
+func (t *gatewayTranslator) translateHTTPRouteFilters(routeNamespace string, routeFilters []gateway_v1.HTTPRouteFilter, result *gatewayRouteResult,
+	translation *gatewayTranslation,
+) (*gatewayFilters, error) {
+	filters := &gatewayFilters{}
+
+	for _, f := range routeFilters {
+		switch {
+		case f.Type == gateway_v1.HTTPRouteFilterRequestHeaderModifier && f.RequestHeaderModifier != nil:
+			filters.addRequestHeaders(f.RequestHeaderModifier)
+		case f.Type == gateway_v1.HTTPRouteFilterResponseHeaderModifier && f.ResponseHeaderModifier != nil:
+			filters.addResponseHeaders(f.ResponseHeaderModifier)
+		case f.Type == gateway_v1.HTTPRouteFilterURLRewrite && f.URLRewrite != nil:
+			if err := filters.addURLRewrite(f.URLRewrite); err != nil {
+				return nil, err
+			}
+		case f.Type == gateway_v1.HTTPRouteFilterRequestRedirect && f.RequestRedirect != nil:
+			if err := filters.addRequestRedirect(f.RequestRedirect); err != nil {
+				return nil, err
+			}
+		case f.Type == gateway_v1.HTTPRouteFilterRequestMirror && f.RequestMirror != nil:
+			if err := t.addRequestMirror(routeNamespace, filters, f.RequestMirror, "", result, translation); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("filter %s is not supported", f.Type)
+		}
+	}
+
+	return filters, nil
+}
+
+func (t *gatewayTranslator) addRequestMirror(routeNamespace string, filters *gatewayFilters, filter *gateway_v1.HTTPRequestMirrorFilter, upstreamType string,
+	result *gatewayRouteResult, translation *gatewayTranslation,
+) error {
+	if filters.mirror != nil {
+		return fmt.Errorf("only a single RequestMirror filter is supported")
+	}
+
+	upstream, ok := t.translateBackendRef(routeNamespace, filter.BackendRef, upstreamType, result, translation)
+	if ok {
+		filters.mirror = &upstream
+	}
+
+	return nil
+}
+
+func (t *gatewayTranslator) translateGRPCRouteRules(route *gateway_v1.GRPCRoute, result *gatewayRouteResult, translation *gatewayTranslation) []gatewayRule {
+	var rules []gatewayRule
+
+	for i, r := range route.Spec.Rules {
+		matches, err := translateGRPCRouteMatches(r.Matches)
+		if err != nil {
+			result.unsupported = append(result.unsupported, fmt.Sprintf("rule %d: %v", i, err))
+			continue
+		}
+
+		filters := &gatewayFilters{}
+		for _, f := range r.Filters {
+			switch {
+			case f.Type == gateway_v1.GRPCRouteFilterRequestHeaderModifier && f.RequestHeaderModifier != nil:
+				filters.addRequestHeaders(f.RequestHeaderModifier)
+			case f.Type == gateway_v1.GRPCRouteFilterResponseHeaderModifier && f.ResponseHeaderModifier != nil:
+				filters.addResponseHeaders(f.ResponseHeaderModifier)
+			default:
+				err = fmt.Errorf("filter %s is not supported", f.Type)
+			}
+		}
+		if err != nil {
+			result.unsupported = append(result.unsupported, fmt.Sprintf("rule %d: %v", i, err))
+			continue
+		}
+
+		var refs []gateway_v1.BackendRef
+		for _, ref := range r.BackendRefs {
+			if len(ref.Filters) > 0 {
+				err = fmt.Errorf("filters of backends are not supported")
+				break
+			}
+			refs = append(refs, ref.BackendRef)
+		}
+		if err != nil {
+			result.unsupported = append(result.unsupported, fmt.Sprintf("rule %d: %v", i, err))
+			continue
+		}
+
+		upstreams, weights := t.translateBackendRefs(route.Namespace, refs, grpcUpstreamType, result, translation)
+		rules = append(rules, newGatewayRule(matches, filters, upstreams, weights))
+	}
+
+	return rules
+}
+
+// translateGRPCRouteMatches translates the matches of the gRPC methods into the paths of the requests,
+// because gRPC requests use the /<service>/<method> path.
+func translateGRPCRouteMatches(routeMatches []gateway_v1.GRPCRouteMatch) ([]gatewayMatch, error) {
+	if len(routeMatches) == 0 {
+		return []gatewayMatch{{path: "/"}}, nil
+	}
+
+	var matches []gatewayMatch
+	for _, m := range routeMatches {
+		match := gatewayMatch{path: "/"}
+
+		if m.Method != nil {
+			matchType := gateway_v1.GRPCMethodMatchExact
+			if m.Method.Type != nil {
+				matchType = *m.Method.Type
+			}
+
+			var service, method string
+			if m.Method.Service != nil {
+				service = *m.Method.Service
+			}
+			if m.Method.Method != nil {
+				method = *m.Method.Method
+			}
+
+			switch matchType {
+			case gateway_v1.GRPCMethodMatchExact:
+				switch {
+				case service != "" && method != "":
+					match.path = fmt.Sprintf("=/%s/%s", service, method)
+				case service != "":
+					match.path = fmt.Sprintf("/%s/", service)
+				case method != "":
+					match.path = fmt.Sprintf("~ ^/[^/]+/%s$", regexp.QuoteMeta(method))
+				}
+			case gateway_v1.GRPCMethodMatchRegularExpression:
+				if service == "" {
+					service = "[^/]+"
+				}
+				if method == "" {
+					method = "[^/]+"
+				}
+				path := fmt.Sprintf("^/%s/%s$", service, method)
+				if _, err := regexp.Compile(path); err != nil {
+					return nil, fmt.Errorf("invalid regular expression of the method: %w", err)
+				}
+				match.path = "~ " + path
+			default:
+				return nil, fmt.Errorf("method match type %s is not supported", matchType)
+			}
+		}
+
+		for _, h := range m.Headers {
+			if h.Type != nil && *h.Type != gateway_v1.HeaderMatchExact {
+				return nil, fmt.Errorf("header match type %s is not supported", *h.Type)
+			}
+			match.conditions = append(match.conditions, conf_v1.Condition{Header: string(h.Name), Value: h.Value})
+		}
+
+		matches = append(matches, match)
+	}
+
+	return matches, nil
+}
+
+// attachTLSRoute generates a TLS Passthrough TransportServer for every hostname of the route on the TLS listeners
+// that the route is attached to and records the statuses of the parent references of the route to the Gateway.
+func (t *gatewayTranslator) attachTLSRoute(gw *gateway_v1.Gateway, route *gateway_v1alpha2.TLSRoute, translation *gatewayTranslation) {
+	routeKey := getGatewayRouteKey(tlsRouteKind, route)
+	result := &gatewayRouteResult{}
+
+	var refs []gateway_v1.BackendRef
+	for _, r := range route.Spec.Rules {
+		refs = append(refs, r.BackendRefs...)
+	}
+	upstreams, _ := t.translateBackendRefs(route.Namespace, refs, "", result, translation)
+	if len(upstreams) > 1 {
+		result.unsupported = append(result.unsupported, "only a single backend is supported, the first backend is used")
+	}
+
+	attachedListeners := make(map[*gatewayListener]bool)
+
+	for _, ref := range route.Spec.ParentRefs {
+		if !isGatewayParentRef(ref, route.Namespace, gw) {
+			continue
+		}
+
+		attachments, reason, message := t.findGatewayAttachments(gw, translation.listeners, tlsRouteKind, route.Namespace, ref, route.Spec.Hostnames)
+		accepted := len(attachments) > 0
+		if accepted && len(upstreams) == 0 {
+			accepted = false
+			reason, message = gateway_v1.RouteReasonUnsupportedValue, "Route must have a valid backend"
+		}
+
+		if accepted {
+			reason, message = gateway_v1.RouteReasonAccepted, "Route is accepted"
+			for _, a := range attachments {
+				attachedListeners[a.listener] = true
+				for _, hostname := range a.hostnames {
+					translation.transportServers = append(translation.transportServers, newGatewayTransportServer(gw, a.listener, hostname, upstreams[0]))
+				}
+			}
+		}
+
+		translation.routeParents[routeKey] = append(translation.routeParents[routeKey], newRouteParentStatus(ref, route.Generation, accepted, reason, message, result))
+	}
+
+	for l := range attachedListeners {
+		l.attachedRoutes++
+	}
+}
+
+func newGatewayTransportServer(gw *gateway_v1.Gateway, listener *gatewayListener, hostname string, upstream conf_v1.Upstream) *conf_v1.TransportServer {
+	return &conf_v1.TransportServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: gw.Namespace,
+			Name:      getGatewayResourceName(gw, listener.Name, hostname),
+		},
+		Spec: conf_v1.TransportServerSpec{
+			Listener: conf_v1.TransportServerListener{
+				Name:     conf_v1.TLSPassthroughListenerName,
+				Protocol: conf_v1.TLSPassthroughListenerProtocol,
+			},
+			Host: hostname,
+			Upstreams: []conf_v1.TransportServerUpstream{
+				{
+					Name:    upstream.Name,
+					Service: upstream.Service,
+					Port:    int(upstream.Port),
+				},
+			},
+			Action: &conf_v1.TransportServerAction{Pass: upstream.Name},
+		},
+	}
+}
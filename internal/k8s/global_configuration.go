@@ -119,6 +119,8 @@ func (lbc *LoadBalancerController) processChangesFromGlobalConfiguration(changes
 	var deletedVSKeys []string
 
 	var updatedResources []Resource
+	// the resources of Gateways are applied per Gateway
+	var gatewayChanges []ResourceChange
 
 	for _, c := range changes {
 		switch impl := c.Resource.(type) {
@@ -144,6 +146,8 @@ func (lbc *LoadBalancerController) processChangesFromGlobalConfiguration(changes
 
 				deletedTSKeys = append(deletedTSKeys, key)
 			}
+		case *GatewayConfiguration:
+			gatewayChanges = append(gatewayChanges, c)
 		}
 	}
 
@@ -167,5 +171,7 @@ func (lbc *LoadBalancerController) processChangesFromGlobalConfiguration(changes
 
 	lbc.updateResourcesStatusAndEvents(updatedResources, configs.Warnings{}, updateErr)
 
+	lbc.processChanges(gatewayChanges)
+
 	return updateErr
 }
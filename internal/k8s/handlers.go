@@ -131,6 +131,13 @@ func createVirtualServerHandlers(lbc *LoadBalancerController) cache.ResourceEven
 			curVs := cur.(*conf_v1.VirtualServer)
 			oldVs := old.(*conf_v1.VirtualServer)
 
+			// the leader steps the canaries and persists their weights in the status
+			if !lbc.reportCustomResourceStatusEnabled() && !reflect.DeepEqual(oldVs.Status.Canaries, curVs.Status.Canaries) {
+				nl.Debugf(lbc.Logger, "Canaries of VirtualServer %v changed, syncing", curVs.Name)
+				lbc.AddSyncQueue(curVs)
+				return
+			}
+
 			if lbc.weightChangesDynamicReload {
 				var curVsCopy, oldVsCopy conf_v1.VirtualServer
 				err := copier.CopyWithOption(&curVsCopy, curVs, copier.Option{DeepCopy: true})
@@ -191,6 +198,13 @@ func createVirtualServerRouteHandlers(lbc *LoadBalancerController) cache.Resourc
 			curVsr := cur.(*conf_v1.VirtualServerRoute)
 			oldVsr := old.(*conf_v1.VirtualServerRoute)
 
+			// the leader steps the canaries and persists their weights in the status
+			if !lbc.reportCustomResourceStatusEnabled() && !reflect.DeepEqual(oldVsr.Status.Canaries, curVsr.Status.Canaries) {
+				nl.Debugf(lbc.Logger, "Canaries of VirtualServerRoute %v changed, syncing", curVsr.Name)
+				lbc.AddSyncQueue(curVsr)
+				return
+			}
+
 			if lbc.weightChangesDynamicReload {
 				var curVsrCopy, oldVsrCopy conf_v1.VirtualServerRoute
 				err := copier.CopyWithOption(&curVsrCopy, curVsr, copier.Option{DeepCopy: true})
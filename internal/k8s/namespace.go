@@ -93,6 +93,20 @@ func (lbc *LoadBalancerController) syncNamespace(task task) {
 		if lbc.externalDNSController != nil {
 			lbc.externalDNSController.RemoveNamespacedInformer(key)
 		}
+	} else if !lbc.ownsNamespace(key) {
+		// the namespace is owned by another shard
+		nsi := lbc.getNamespacedInformer(key)
+		if nsi != nil {
+			nl.Infof(lbc.Logger, "Removing Configuration for Namespace owned by another shard: %v", key)
+			lbc.cleanupUnwatchedNamespacedResources(nsi)
+			delete(lbc.namespacedInformers, key)
+		}
+		if lbc.certManagerController != nil {
+			lbc.certManagerController.RemoveNamespacedInformer(key)
+		}
+		if lbc.externalDNSController != nil {
+			lbc.externalDNSController.RemoveNamespacedInformer(key)
+		}
 	} else {
 		// check if informer group already exists
 		// if not create new namespaced informer group
@@ -83,10 +83,24 @@ func (rc *secretReferenceChecker) IsReferencedByVirtualServer(secretNamespace st
 		return true
 	}
 
-	return false
+	return isSecretReferencedByUpstreams(secretName, vs.Spec.Upstreams)
+}
+
+func (rc *secretReferenceChecker) IsReferencedByVirtualServerRoute(secretNamespace string, secretName string, vsr *conf_v1.VirtualServerRoute) bool {
+	if vsr.Namespace != secretNamespace {
+		return false
+	}
+
+	return isSecretReferencedByUpstreams(secretName, vsr.Spec.Upstreams)
 }
 
-func (rc *secretReferenceChecker) IsReferencedByVirtualServerRoute(_ string, _ string, _ *conf_v1.VirtualServerRoute) bool {
+func isSecretReferencedByUpstreams(secretName string, upstreams []conf_v1.Upstream) bool {
+	for _, u := range upstreams {
+		if u.TLS.TrustedCertSecret == secretName {
+			return true
+		}
+	}
+
 	return false
 }
 
@@ -95,10 +109,16 @@ func (rc *secretReferenceChecker) IsReferencedByTransportServer(secretNamespace
 		return false
 	}
 
-	if ts.Spec.TLS != nil && ts.Spec.TLS.Secret == secretName {
+	if ts.Spec.TLS != nil && (ts.Spec.TLS.Secret == secretName || ts.Spec.TLS.ClientCertSecret == secretName) {
 		return true
 	}
 
+	for _, u := range ts.Spec.Upstreams {
+		if u.TLS.TrustedCertSecret == secretName {
+			return true
+		}
+	}
+
 	return false
 }
 
@@ -224,8 +244,8 @@ func (rc *policyReferenceChecker) IsReferencedByVirtualServerRoute(policyNamespa
 	return false
 }
 
-func (rc *policyReferenceChecker) IsReferencedByTransportServer(_ string, _ string, _ *conf_v1.TransportServer) bool {
-	return false
+func (rc *policyReferenceChecker) IsReferencedByTransportServer(policyNamespace string, policyName string, ts *conf_v1.TransportServer) bool {
+	return isPolicyReferenced(ts.Spec.Policies, ts.Namespace, policyNamespace, policyName)
 }
 
 // appProtectResourceReferenceChecker is a reference checker for AppProtect related resources.
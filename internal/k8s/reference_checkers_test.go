@@ -287,6 +287,48 @@ func TestSecretIsReferencedByVirtualServer(t *testing.T) {
 			expected:        false,
 			msg:             "wrong namespace for tls secret",
 		},
+		{
+			vs: &conf_v1.VirtualServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1.VirtualServerSpec{
+					Upstreams: []conf_v1.Upstream{
+						{
+							TLS: conf_v1.UpstreamTLS{
+								Enable:            true,
+								TrustedCertSecret: "test-secret",
+							},
+						},
+					},
+				},
+			},
+			secretNamespace: "default",
+			secretName:      "test-secret",
+			expected:        true,
+			msg:             "upstream trusted cert secret is referenced",
+		},
+		{
+			vs: &conf_v1.VirtualServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1.VirtualServerSpec{
+					Upstreams: []conf_v1.Upstream{
+						{
+							TLS: conf_v1.UpstreamTLS{
+								Enable:            true,
+								TrustedCertSecret: "test-secret",
+							},
+						},
+					},
+				},
+			},
+			secretNamespace: "default",
+			secretName:      "some-secret",
+			expected:        false,
+			msg:             "wrong name for upstream trusted cert secret",
+		},
 	}
 
 	for _, test := range tests {
@@ -302,12 +344,55 @@ func TestSecretIsReferencedByVirtualServer(t *testing.T) {
 
 func TestSecretIsReferencedByVirtualServerRoute(t *testing.T) {
 	t.Parallel()
-	isPlus := false // doesn't matter for VirtualServerRoute
-	rc := newSecretReferenceChecker(isPlus)
+	vsr := &conf_v1.VirtualServerRoute{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "default",
+		},
+		Spec: conf_v1.VirtualServerRouteSpec{
+			Upstreams: []conf_v1.Upstream{
+				{
+					TLS: conf_v1.UpstreamTLS{
+						Enable:            true,
+						TrustedCertSecret: "test-secret",
+					},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		secretNamespace string
+		secretName      string
+		expected        bool
+		msg             string
+	}{
+		{
+			secretNamespace: "default",
+			secretName:      "test-secret",
+			expected:        true,
+			msg:             "upstream trusted cert secret is referenced",
+		},
+		{
+			secretNamespace: "default",
+			secretName:      "some-secret",
+			expected:        false,
+			msg:             "wrong name for upstream trusted cert secret",
+		},
+		{
+			secretNamespace: "some-namespace",
+			secretName:      "test-secret",
+			expected:        false,
+			msg:             "wrong namespace for upstream trusted cert secret",
+		},
+	}
 
-	result := rc.IsReferencedByVirtualServerRoute("", "", nil)
-	if result != false {
-		t.Error("IsReferencedByVirtualServer() returned true but expected false")
+	for _, test := range tests {
+		isPlus := false // doesn't matter for VirtualServerRoute
+		rc := newSecretReferenceChecker(isPlus)
+
+		result := rc.IsReferencedByVirtualServerRoute(test.secretNamespace, test.secretName, vsr)
+		if result != test.expected {
+			t.Errorf("IsReferencedByVirtualServerRoute() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
 	}
 }
 
@@ -364,6 +449,67 @@ func TestSecretIsReferencedByTransportServer(t *testing.T) {
 			expected:        false,
 			msg:             "tls secret is not but in another namespace",
 		},
+		{
+			ts: &conf_v1.TransportServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1.TransportServerSpec{
+					TLS: &conf_v1.TransportServerTLS{
+						Secret:           "test-secret",
+						ClientCertSecret: "ca-secret",
+					},
+				},
+			},
+			secretNamespace: "default",
+			secretName:      "ca-secret",
+			expected:        true,
+			msg:             "client cert secret is referenced",
+		},
+		{
+			ts: &conf_v1.TransportServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1.TransportServerSpec{
+					Upstreams: []conf_v1.TransportServerUpstream{
+						{
+							Name: "upstream1",
+							TLS: conf_v1.UpstreamTLS{
+								Enable:            true,
+								TrustedCertSecret: "ca-secret",
+							},
+						},
+					},
+				},
+			},
+			secretNamespace: "default",
+			secretName:      "ca-secret",
+			expected:        true,
+			msg:             "trusted cert secret of an upstream is referenced",
+		},
+		{
+			ts: &conf_v1.TransportServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1.TransportServerSpec{
+					Upstreams: []conf_v1.TransportServerUpstream{
+						{
+							Name: "upstream1",
+							TLS: conf_v1.UpstreamTLS{
+								Enable:            true,
+								TrustedCertSecret: "ca-secret",
+							},
+						},
+					},
+				},
+			},
+			secretNamespace: "other-namespace",
+			secretName:      "ca-secret",
+			expected:        false,
+			msg:             "trusted cert secret of an upstream is referenced but in another namespace",
+		},
 	}
 
 	for _, test := range tests {
@@ -854,7 +1000,7 @@ func TestBackupServiceIsReferencedByTransportServer(t *testing.T) {
 	}
 }
 
-func TestPolicyIsReferencedByIngressesAndTransportServers(t *testing.T) {
+func TestPolicyIsReferencedByIngresses(t *testing.T) {
 	t.Parallel()
 	rc := newPolicyReferenceChecker()
 
@@ -867,10 +1013,92 @@ func TestPolicyIsReferencedByIngressesAndTransportServers(t *testing.T) {
 	if result {
 		t.Error("IsReferencedByMinion() returned true but expected false")
 	}
+}
 
-	result = rc.IsReferencedByTransportServer("", "", nil)
-	if result {
-		t.Error("IsReferencedByTransportServer() returned true but expected false")
+func TestPolicyIsReferencedByTransportServer(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		ts              *conf_v1.TransportServer
+		policyNamespace string
+		policyName      string
+		expected        bool
+		msg             string
+	}{
+		{
+			ts: &conf_v1.TransportServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1.TransportServerSpec{
+					Policies: []conf_v1.PolicyReference{
+						{
+							Name: "test-policy",
+						},
+					},
+				},
+			},
+			policyNamespace: "default",
+			policyName:      "test-policy",
+			expected:        true,
+			msg:             "policy is referenced in the same namespace",
+		},
+		{
+			ts: &conf_v1.TransportServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1.TransportServerSpec{
+					Policies: []conf_v1.PolicyReference{
+						{
+							Name:      "test-policy",
+							Namespace: "policies",
+						},
+					},
+				},
+			},
+			policyNamespace: "policies",
+			policyName:      "test-policy",
+			expected:        true,
+			msg:             "policy is referenced in another namespace",
+		},
+		{
+			ts: &conf_v1.TransportServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1.TransportServerSpec{
+					Policies: []conf_v1.PolicyReference{
+						{
+							Name: "test-policy",
+						},
+					},
+				},
+			},
+			policyNamespace: "policies",
+			policyName:      "test-policy",
+			expected:        false,
+			msg:             "wrong namespace",
+		},
+		{
+			ts: &conf_v1.TransportServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+			},
+			policyNamespace: "default",
+			policyName:      "test-policy",
+			expected:        false,
+			msg:             "no policies",
+		},
+	}
+
+	for _, test := range tests {
+		rc := newPolicyReferenceChecker()
+
+		result := rc.IsReferencedByTransportServer(test.policyNamespace, test.policyName, test.ts)
+		if result != test.expected {
+			t.Errorf("IsReferencedByTransportServer() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
 	}
 }
 
@@ -0,0 +1,273 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/nginx/kubernetes-ingress/internal/configs"
+	"github.com/nginx/kubernetes-ingress/internal/k8s/appprotect"
+	"github.com/nginx/kubernetes-ingress/internal/k8s/appprotectdos"
+	"github.com/nginx/kubernetes-ingress/internal/k8s/secrets"
+	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	"github.com/nginx/kubernetes-ingress/internal/metrics/collectors"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	"github.com/nginx/kubernetes-ingress/pkg/apis/configuration/validation"
+	api_v1 "k8s.io/api/core/v1"
+	discovery_v1 "k8s.io/api/discovery/v1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+// RenderInput holds the input needed to call Render.
+type RenderInput struct {
+	LoggerContext                context.Context
+	NginxConfigurator            *configs.Configurator
+	Recorder                     *RenderEventRecorder
+	IsNginxPlus                  bool
+	IngressClass                 string
+	EnableOIDC                   bool
+	InternalRoutesEnabled        bool
+	IsTLSPassthroughEnabled      bool
+	SnippetsEnabled              bool
+	IsIPV6Disabled               bool
+	GlobalConfigurationValidator *validation.GlobalConfigurationValidator
+	TransportServerValidator     *validation.TransportServerValidator
+	VirtualServerValidator       *validation.VirtualServerValidator
+	// Objects are the resources to render the configuration for: Ingresses, VirtualServers, VirtualServerRoutes,
+	// TransportServers, Policies, a GlobalConfiguration and the Services, EndpointSlices, Pods and Secrets they reference.
+	Objects []runtime.Object
+}
+
+// RenderResult holds the result of Render.
+type RenderResult struct {
+	// Problems are the problems of the resources as they would be reported in their events and statuses.
+	Problems []ConfigurationProblem
+}
+
+// Render generates the NGINX configuration for the resources of the input, the same way the controller would,
+// but without a Kubernetes API server and without reloading NGINX.
+// The configuration is written by the NginxManager of the Configurator.
+func Render(input RenderInput) (*RenderResult, error) {
+	nsi := &namespacedInformer{
+		ingressLister:             storeToIngressLister{Store: cache.NewStore(keyFunc)},
+		svcLister:                 cache.NewStore(keyFunc),
+		endpointSliceLister:       storeToEndpointSliceLister{Store: cache.NewStore(keyFunc)},
+		podLister:                 indexerToPodLister{Indexer: cache.NewIndexer(keyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})},
+		secretLister:              cache.NewStore(keyFunc),
+		virtualServerLister:       cache.NewStore(keyFunc),
+		virtualServerRouteLister:  cache.NewStore(keyFunc),
+		transportServerLister:     cache.NewStore(keyFunc),
+		policyLister:              cache.NewStore(keyFunc),
+		isSecretsEnabledNamespace: true,
+		areCustomResourcesEnabled: true,
+	}
+
+	lbc := &LoadBalancerController{
+		namespacedInformers:       map[string]*namespacedInformer{"": nsi},
+		Logger:                    nl.LoggerFromContext(input.LoggerContext),
+		configurator:              input.NginxConfigurator,
+		isNginxPlus:               input.IsNginxPlus,
+		recorder:                  input.Recorder,
+		ingressClass:              input.IngressClass,
+		areCustomResourcesEnabled: true,
+		enableOIDC:                input.EnableOIDC,
+		metricsCollector:          collectors.NewControllerFakeCollector(),
+		transportServerValidator:  input.TransportServerValidator,
+		internalRoutesEnabled:     input.InternalRoutesEnabled,
+		isIPV6Disabled:            input.IsIPV6Disabled,
+		// There is no leader, so statuses are never reported.
+		isLeaderElectionEnabled: true,
+	}
+	lbc.appProtectConfiguration = appprotect.NewConfiguration(lbc.Logger)
+	lbc.dosConfiguration = appprotectdos.NewConfiguration(false)
+	lbc.secretStore = secrets.NewLocalSecretStore(lbc.configurator)
+	lbc.configuration = NewConfiguration(
+		lbc.HasCorrectIngressClass,
+		input.IsNginxPlus,
+		false,
+		false,
+		input.InternalRoutesEnabled,
+		input.VirtualServerValidator,
+		input.GlobalConfigurationValidator,
+		input.TransportServerValidator,
+		input.IsTLSPassthroughEnabled,
+		input.SnippetsEnabled,
+		false,
+		input.IsIPV6Disabled,
+	)
+
+	var (
+		globalConfiguration *conf_v1.GlobalConfiguration
+		ingresses           []*networking.Ingress
+		virtualServers      []*conf_v1.VirtualServer
+		virtualServerRoutes []*conf_v1.VirtualServerRoute
+		transportServers    []*conf_v1.TransportServer
+	)
+
+	for _, obj := range input.Objects {
+		var err error
+		switch o := obj.(type) {
+		case *networking.Ingress:
+			err = nsi.ingressLister.Add(o)
+			ingresses = append(ingresses, o)
+		case *conf_v1.VirtualServer:
+			err = nsi.virtualServerLister.Add(o)
+			virtualServers = append(virtualServers, o)
+		case *conf_v1.VirtualServerRoute:
+			err = nsi.virtualServerRouteLister.Add(o)
+			virtualServerRoutes = append(virtualServerRoutes, o)
+		case *conf_v1.TransportServer:
+			err = nsi.transportServerLister.Add(o)
+			transportServers = append(transportServers, o)
+		case *conf_v1.Policy:
+			err = nsi.policyLister.Add(o)
+		case *conf_v1.GlobalConfiguration:
+			if globalConfiguration != nil {
+				return nil, fmt.Errorf("only one GlobalConfiguration is supported, found %s/%s and %s/%s",
+					globalConfiguration.Namespace, globalConfiguration.Name, o.Namespace, o.Name)
+			}
+			globalConfiguration = o
+		case *api_v1.Service:
+			err = nsi.svcLister.Add(o)
+		case *discovery_v1.EndpointSlice:
+			err = nsi.endpointSliceLister.Add(o)
+		case *api_v1.Pod:
+			err = nsi.podLister.Add(o)
+		case *api_v1.Secret:
+			err = nsi.secretLister.Add(o)
+			lbc.secretStore.AddOrUpdateSecret(o)
+		default:
+			return nil, fmt.Errorf("unsupported resource %v", obj.GetObjectKind().GroupVersionKind())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Only the validation errors are kept from the problems returned while adding the resources.
+	// The host and listener problems can change with every resource added, so they are taken from the final state.
+	var problems []ConfigurationProblem
+	keepErrors := func(p []ConfigurationProblem) {
+		for _, problem := range p {
+			if problem.IsError {
+				problems = append(problems, problem)
+			}
+		}
+	}
+
+	if globalConfiguration != nil {
+		_, p, err := lbc.configuration.AddOrUpdateGlobalConfiguration(globalConfiguration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GlobalConfiguration %s/%s: %w", globalConfiguration.Namespace, globalConfiguration.Name, err)
+		}
+		keepErrors(p)
+	}
+	for _, ing := range ingresses {
+		_, p := lbc.configuration.AddOrUpdateIngress(ing)
+		keepErrors(p)
+	}
+	for _, vsr := range virtualServerRoutes {
+		_, p := lbc.configuration.AddOrUpdateVirtualServerRoute(vsr)
+		keepErrors(p)
+	}
+	for _, vs := range virtualServers {
+		_, p := lbc.configuration.AddOrUpdateVirtualServer(vs)
+		keepErrors(p)
+	}
+	for _, ts := range transportServers {
+		_, p := lbc.configuration.AddOrUpdateTransportServer(ts)
+		keepErrors(p)
+	}
+
+	problems = append(problems, lbc.configuration.getHostAndListenerProblems()...)
+
+	var changes []ResourceChange
+	for _, r := range lbc.configuration.GetResources() {
+		changes = append(changes, ResourceChange{
+			Op:       AddOrUpdate,
+			Resource: r,
+		})
+	}
+	lbc.processChanges(changes)
+
+	return &RenderResult{Problems: problems}, nil
+}
+
+// getHostAndListenerProblems returns the current host and listener problems, sorted by their keys.
+func (c *Configuration) getHostAndListenerProblems() []ConfigurationProblem {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	all := make(map[string]ConfigurationProblem)
+	for key, p := range c.hostProblems {
+		all[key] = p
+	}
+	for key, p := range c.listenerProblems {
+		all[key] = p
+	}
+
+	keys := make([]string, 0, len(all))
+	for key := range all {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	problems := make([]ConfigurationProblem, 0, len(keys))
+	for _, key := range keys {
+		problems = append(problems, all[key])
+	}
+	return problems
+}
+
+// RenderEvent is an event recorded while rendering the configuration.
+type RenderEvent struct {
+	Object  runtime.Object
+	Type    string
+	Reason  string
+	Message string
+}
+
+// RenderEventRecorder is an EventRecorder that keeps the events in memory.
+type RenderEventRecorder struct {
+	lock   sync.Mutex
+	events []RenderEvent
+}
+
+// NewRenderEventRecorder creates a RenderEventRecorder.
+func NewRenderEventRecorder() *RenderEventRecorder {
+	return &RenderEventRecorder{}
+}
+
+// Event records an event.
+func (r *RenderEventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.events = append(r.events, RenderEvent{
+		Object:  object,
+		Type:    eventtype,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// Eventf records an event with a formatted message.
+func (r *RenderEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf records an event with a formatted message. The annotations are ignored.
+func (r *RenderEventRecorder) AnnotatedEventf(object runtime.Object, _ map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+// Events returns the recorded events.
+func (r *RenderEventRecorder) Events() []RenderEvent {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]RenderEvent(nil), r.events...)
+}
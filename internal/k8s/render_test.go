@@ -0,0 +1,206 @@
+package k8s
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nginx/kubernetes-ingress/internal/configs"
+	"github.com/nginx/kubernetes-ingress/internal/configs/version1"
+	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
+	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	"github.com/nginx/kubernetes-ingress/internal/nginx"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	"github.com/nginx/kubernetes-ingress/pkg/apis/configuration/validation"
+	api_v1 "k8s.io/api/core/v1"
+	discovery_v1 "k8s.io/api/discovery/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func newRenderInput(t *testing.T, outputDir string, objects []runtime.Object) RenderInput {
+	t.Helper()
+
+	ctx := context.Background()
+	templateExecutor, err := version1.NewTemplateExecutor("../configs/version1/nginx.tmpl", "../configs/version1/nginx.ingress.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	templateExecutorV2, err := version2.NewTemplateExecutor("../configs/version2/nginx.virtualserver.tmpl", "../configs/version2/nginx.transportserver.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version := nginx.NewVersion("nginx version: nginx/1.27.4")
+	manager := nginx.NewRenderManager(nl.LoggerFromContext(ctx), "/etc/nginx", outputDir, version)
+	cnf := configs.NewConfigurator(configs.ConfiguratorParams{
+		NginxManager: manager,
+		StaticCfgParams: &configs.StaticConfigParams{
+			NginxVersion: version,
+		},
+		Config:             configs.NewDefaultConfigParams(ctx, false),
+		TemplateExecutor:   templateExecutor,
+		TemplateExecutorV2: templateExecutorV2,
+		NginxVersion:       version,
+	})
+
+	return RenderInput{
+		LoggerContext:                ctx,
+		NginxConfigurator:            cnf,
+		Recorder:                     NewRenderEventRecorder(),
+		IngressClass:                 "nginx",
+		GlobalConfigurationValidator: validation.NewGlobalConfigurationValidator(map[int]bool{80: true, 443: true}),
+		TransportServerValidator:     validation.NewTransportServerValidator(false, false, false, false, false),
+		VirtualServerValidator:       validation.NewVirtualServerValidator(validation.IsPlus(false)),
+		Objects:                      objects,
+	}
+}
+
+func createRenderTestVirtualServer(name string, pass string) *conf_v1.VirtualServer {
+	return &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: conf_v1.VirtualServerSpec{
+			Host: "cafe.example.com",
+			Upstreams: []conf_v1.Upstream{
+				{
+					Name:    "coffee",
+					Service: "coffee-svc",
+					Port:    80,
+				},
+			},
+			Routes: []conf_v1.Route{
+				{
+					Path: "/coffee",
+					Action: &conf_v1.Action{
+						Pass: pass,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	ready := true
+	objects := []runtime.Object{
+		&api_v1.Service{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "coffee-svc",
+				Namespace: "default",
+			},
+			Spec: api_v1.ServiceSpec{
+				Ports: []api_v1.ServicePort{
+					{
+						Port:       80,
+						TargetPort: intstr.FromInt32(8080),
+					},
+				},
+			},
+		},
+		&discovery_v1.EndpointSlice{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "coffee-svc-abc",
+				Namespace: "default",
+				Labels:    map[string]string{"kubernetes.io/service-name": "coffee-svc"},
+			},
+			Ports: []discovery_v1.EndpointPort{
+				{
+					Port: func(p int32) *int32 { return &p }(8080),
+				},
+			},
+			Endpoints: []discovery_v1.Endpoint{
+				{
+					Addresses:  []string{"10.0.0.1"},
+					Conditions: discovery_v1.EndpointConditions{Ready: &ready},
+				},
+			},
+		},
+		createRenderTestVirtualServer("cafe", "coffee"),
+		createRenderTestVirtualServer("invalid", "tea"),
+	}
+
+	outputDir := t.TempDir()
+	input := newRenderInput(t, outputDir, objects)
+
+	result, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() returned unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "etc", "nginx", "conf.d", "vs_default_cafe.conf"))
+	if err != nil {
+		t.Fatalf("Render() didn't write the config of the VirtualServer: %v", err)
+	}
+	if !strings.Contains(string(content), "server 10.0.0.1:8080") {
+		t.Errorf("Render() wrote a config without the endpoint of the upstream:\n%s", content)
+	}
+
+	if _, err := os.Stat(filepath.Join(outputDir, "etc", "nginx", "conf.d", "vs_default_invalid.conf")); !os.IsNotExist(err) {
+		t.Errorf("Render() wrote the config of an invalid VirtualServer")
+	}
+
+	if len(result.Problems) != 1 {
+		t.Fatalf("Render() returned %d problems, want 1: %+v", len(result.Problems), result.Problems)
+	}
+	problem := result.Problems[0]
+	if !problem.IsError || problem.Reason != "Rejected" || problem.Object != objects[3] {
+		t.Errorf("Render() returned unexpected problem %+v", problem)
+	}
+
+	var addedOrUpdated bool
+	for _, e := range input.Recorder.Events() {
+		if e.Object == objects[2] && e.Reason == "AddedOrUpdated" {
+			addedOrUpdated = true
+		}
+	}
+	if !addedOrUpdated {
+		t.Errorf("Render() didn't record an AddedOrUpdated event for the VirtualServer: %+v", input.Recorder.Events())
+	}
+}
+
+func TestRender_ReportsHostProblems(t *testing.T) {
+	t.Parallel()
+
+	cafe := createRenderTestVirtualServer("cafe", "coffee")
+	cafe.CreationTimestamp = meta_v1.Unix(1, 0)
+	cafeCopy := createRenderTestVirtualServer("cafe-copy", "coffee")
+	cafeCopy.CreationTimestamp = meta_v1.Unix(2, 0)
+
+	result, err := Render(newRenderInput(t, t.TempDir(), []runtime.Object{cafeCopy, cafe}))
+	if err != nil {
+		t.Fatalf("Render() returned unexpected error: %v", err)
+	}
+
+	if len(result.Problems) != 1 {
+		t.Fatalf("Render() returned %d problems, want 1: %+v", len(result.Problems), result.Problems)
+	}
+	problem := result.Problems[0]
+	if problem.IsError || problem.Reason != "Rejected" || problem.Object != cafeCopy {
+		t.Errorf("Render() returned unexpected problem %+v", problem)
+	}
+}
+
+func TestRender_FailsOnUnsupportedResource(t *testing.T) {
+	t.Parallel()
+
+	objects := []runtime.Object{
+		&api_v1.Namespace{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name: "default",
+			},
+		},
+	}
+
+	_, err := Render(newRenderInput(t, t.TempDir(), objects))
+	if err == nil {
+		t.Error("Render() returned no error for an unsupported resource")
+	}
+}
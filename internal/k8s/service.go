@@ -5,6 +5,7 @@ import (
 	"sort"
 
 	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	"github.com/nginx/kubernetes-ingress/internal/nginx"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/cache"
 )
@@ -161,16 +162,21 @@ func (lbc *LoadBalancerController) syncService(task task) {
 		}
 
 		if lbc.areCustomResourcesEnabled && lbc.reportCustomResourceStatusEnabled() {
-			virtualServers := lbc.configuration.GetResourcesWithFilter(resourceFilter{VirtualServers: true})
+			virtualServers := lbc.configuration.GetResourcesWithFilter(resourceFilter{VirtualServers: true, TransportServers: true})
 
-			nl.Infof(lbc.Logger, "Updating status for %v VirtualServers", len(virtualServers))
+			nl.Infof(lbc.Logger, "Updating status for %v VirtualServers and TransportServers", len(virtualServers))
 
 			err := lbc.statusUpdater.UpdateExternalEndpointsForResources(virtualServers)
 			if err != nil {
-				nl.Infof(lbc.Logger, "error updating VirtualServer/VirtualServerRoute status in syncService: %v", err)
+				nl.Infof(lbc.Logger, "error updating VirtualServer/VirtualServerRoute/TransportServer status in syncService: %v", err)
 			}
 		}
 
+		if lbc.isGatewayAPIEnabled && lbc.reportCustomResourceStatusEnabled() {
+			// the addresses of the Gateways are the addresses of the external service
+			lbc.enqueueGatewaysWithConfig(func(*gatewayConfig) bool { return true })
+		}
+
 		// we don't return here because technically the same service could be used in the second case
 	}
 
@@ -179,7 +185,11 @@ func (lbc *LoadBalancerController) syncService(task task) {
 	// it is safe to ignore the error
 	namespace, name, _ := ParseNamespaceName(key)
 
-	resources := lbc.configuration.FindResourcesForService(namespace, name)
+	if lbc.isGatewayAPIEnabled {
+		lbc.enqueueGatewaysForService(key)
+	}
+
+	resources := lbc.findResourcesForService(namespace, name)
 
 	if len(resources) == 0 {
 		return
@@ -190,6 +200,6 @@ func (lbc *LoadBalancerController) syncService(task task) {
 
 	resourceExes := lbc.createExtendedResources(resources)
 
-	warnings, updateErr := lbc.configurator.AddOrUpdateResources(resourceExes, true)
+	warnings, updateErr := lbc.configurator.AddOrUpdateResources(resourceExes, true, nginx.ReloadForConfigUpdate)
 	lbc.updateResourcesStatusAndEvents(resources, warnings, updateErr)
 }
@@ -0,0 +1,261 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	coordination_v1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// shardGroupLabel is the label of the shard Leases with the group of the shards.
+	shardGroupLabel = "nginx.org/namespace-sharding-group"
+	// shardLeaseDuration is the time after the last renewal when a shard is considered gone.
+	shardLeaseDuration = 30 * time.Second
+	// shardLeaseRenewPeriod is the period of the renewal of the shard Lease.
+	shardLeaseRenewPeriod = 10 * time.Second
+	// shardVirtualNodes is the number of points of every shard on the hash ring.
+	shardVirtualNodes = 100
+)
+
+// shardManager splits the ownership of namespaces across the replicas of the Ingress Controller. Every replica
+// is a shard that holds its own Lease in the namespace of the Ingress Controller. The shards with a Lease that
+// hasn't expired are the members of the group, and every namespace is owned by a single member picked by
+// consistent hashing of the namespace name, so that a change of the members moves as few namespaces as possible.
+type shardManager struct {
+	client        kubernetes.Interface
+	namespace     string
+	group         string
+	identity      string
+	leaseName     string
+	leaseInformer cache.SharedIndexInformer
+	leaseLister   cache.Store
+	// onMembersChange is called when the members of the group change.
+	onMembersChange func()
+	lock            sync.RWMutex
+	members         []string
+	ring            *hashRing
+	logger          *slog.Logger
+}
+
+func newShardManager(logger *slog.Logger, client kubernetes.Interface, namespace string, group string, identity string, resync time.Duration, onMembersChange func()) *shardManager {
+	optionsModifier := func(options *meta_v1.ListOptions) {
+		options.LabelSelector = fmt.Sprintf("%s=%s", shardGroupLabel, group)
+	}
+	leaseInformer := informers.NewSharedInformerFactoryWithOptions(client, resync, informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(optionsModifier)).Coordination().V1().Leases().Informer()
+
+	sm := &shardManager{
+		client:          client,
+		namespace:       namespace,
+		group:           group,
+		identity:        identity,
+		leaseName:       fmt.Sprintf("%s-shard-%s", group, identity),
+		leaseInformer:   leaseInformer,
+		leaseLister:     leaseInformer.GetStore(),
+		onMembersChange: onMembersChange,
+		members:         []string{identity},
+		ring:            newHashRing([]string{identity}),
+		logger:          logger,
+	}
+
+	handler := func(interface{}) { sm.updateMembers() }
+	leaseInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{ //nolint:errcheck,gosec
+		AddFunc:    handler,
+		UpdateFunc: func(_, cur interface{}) { handler(cur) },
+		DeleteFunc: handler,
+	})
+
+	return sm
+}
+
+// Run renews the Lease of the shard until stopCh is closed and then deletes it,
+// so that the other shards take over the namespaces of the shard right away.
+func (sm *shardManager) Run(stopCh <-chan struct{}) {
+	go sm.leaseInformer.Run(stopCh)
+
+	wait.Until(func() {
+		if err := sm.renewLease(context.Background()); err != nil {
+			nl.Warnf(sm.logger, "Failed to renew the Lease %v/%v of the namespace shard: %v", sm.namespace, sm.leaseName, err)
+		}
+		sm.updateMembers()
+	}, shardLeaseRenewPeriod, stopCh)
+
+	ctx, cancel := context.WithTimeout(context.Background(), shardLeaseRenewPeriod)
+	defer cancel()
+	err := sm.client.CoordinationV1().Leases(sm.namespace).Delete(ctx, sm.leaseName, meta_v1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		nl.Warnf(sm.logger, "Failed to delete the Lease %v/%v of the namespace shard: %v", sm.namespace, sm.leaseName, err)
+	}
+}
+
+// HasSynced returns true when the Leases of the group are listed.
+func (sm *shardManager) HasSynced() bool {
+	return sm.leaseInformer.HasSynced()
+}
+
+// renewLease creates or renews the Lease of the shard.
+func (sm *shardManager) renewLease(ctx context.Context) error {
+	now := meta_v1.NewMicroTime(time.Now())
+	duration := int32(shardLeaseDuration.Seconds())
+	leases := sm.client.CoordinationV1().Leases(sm.namespace)
+
+	lease, err := leases.Get(ctx, sm.leaseName, meta_v1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		lease = &coordination_v1.Lease{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      sm.leaseName,
+				Namespace: sm.namespace,
+				Labels:    map[string]string{shardGroupLabel: sm.group},
+			},
+			Spec: coordination_v1.LeaseSpec{
+				HolderIdentity:       &sm.identity,
+				LeaseDurationSeconds: &duration,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		_, err = leases.Create(ctx, lease, meta_v1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	lease.Spec.HolderIdentity = &sm.identity
+	lease.Spec.LeaseDurationSeconds = &duration
+	lease.Spec.RenewTime = &now
+	_, err = leases.Update(ctx, lease, meta_v1.UpdateOptions{})
+	return err
+}
+
+// updateMembers updates the members of the group from the Leases that haven't expired.
+// The shard itself is always a member.
+func (sm *shardManager) updateMembers() {
+	now := time.Now()
+	members := []string{sm.identity}
+
+	for _, obj := range sm.leaseLister.List() {
+		lease, ok := obj.(*coordination_v1.Lease)
+		if !ok || lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == sm.identity {
+			continue
+		}
+		if isShardLeaseExpired(lease, now) {
+			continue
+		}
+		members = append(members, *lease.Spec.HolderIdentity)
+	}
+	sort.Strings(members)
+
+	sm.lock.Lock()
+	changed := !reflect.DeepEqual(members, sm.members)
+	if changed {
+		sm.members = members
+		sm.ring = newHashRing(members)
+	}
+	sm.lock.Unlock()
+
+	if changed {
+		nl.Infof(sm.logger, "The namespace shards changed: %v", members)
+		sm.onMembersChange()
+	}
+}
+
+func isShardLeaseExpired(lease *coordination_v1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil {
+		return true
+	}
+	duration := shardLeaseDuration
+	if lease.Spec.LeaseDurationSeconds != nil {
+		duration = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	}
+	return lease.Spec.RenewTime.Add(duration).Before(now)
+}
+
+// owns returns true if the namespace is owned by the shard.
+func (sm *shardManager) owns(namespace string) bool {
+	sm.lock.RLock()
+	defer sm.lock.RUnlock()
+
+	return sm.ring.get(namespace) == sm.identity
+}
+
+// hashRing is a consistent hashing ring of the members of a shard group.
+type hashRing struct {
+	points  []uint64
+	members map[uint64]string
+}
+
+func newHashRing(members []string) *hashRing {
+	r := &hashRing{
+		members: make(map[uint64]string),
+	}
+	for _, m := range members {
+		for i := 0; i < shardVirtualNodes; i++ {
+			point := hashKey(m + "#" + strconv.Itoa(i))
+			r.points = append(r.points, point)
+			r.members[point] = m
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+	return r
+}
+
+// get returns the member that owns the key: the member of the first point of the ring after the hash of the key.
+func (r *hashRing) get(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+
+	h := hashKey(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.members[r.points[i]]
+}
+
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key)) //nolint:errcheck,gosec // writing to a hash never fails
+	return h.Sum64()
+}
+
+// addShardManager adds the shard manager for namespace sharding to the controller.
+func (lbc *LoadBalancerController) addShardManager(group string) {
+	lbc.shardManager = newShardManager(lbc.Logger, lbc.client, lbc.metadata.namespace, group, os.Getenv("POD_NAME"), lbc.resync, lbc.syncAllNamespaces)
+	lbc.cacheSyncs = append(lbc.cacheSyncs, lbc.shardManager.HasSynced)
+}
+
+// ownsNamespace returns true if the namespace is owned by the shard of the controller. Without namespace sharding,
+// the controller owns all namespaces. The namespace of the controller is sharded like any other namespace,
+// so that its resources are configured by a single shard.
+func (lbc *LoadBalancerController) ownsNamespace(namespace string) bool {
+	if lbc.shardManager == nil {
+		return true
+	}
+	return lbc.shardManager.owns(namespace)
+}
+
+// syncAllNamespaces enqueues all the watched namespaces, so that the namespaces are added or removed according
+// to the current members of the shard group.
+func (lbc *LoadBalancerController) syncAllNamespaces() {
+	for _, obj := range lbc.namespaceLabeledLister.List() {
+		lbc.AddSyncQueue(obj)
+	}
+}
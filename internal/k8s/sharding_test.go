@@ -0,0 +1,156 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	coordination_v1 "k8s.io/api/coordination/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHashRingMovesNamespacesOfRemovedMember(t *testing.T) {
+	t.Parallel()
+
+	ring := newHashRing([]string{"nic-a", "nic-b", "nic-c"})
+	ringWithoutC := newHashRing([]string{"nic-a", "nic-b"})
+
+	owned := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		ns := fmt.Sprintf("namespace-%d", i)
+		owner := ring.get(ns)
+		owned[owner]++
+
+		if owner != ring.get(ns) {
+			t.Fatalf("get(%q) returned different members", ns)
+		}
+		if newOwner := ringWithoutC.get(ns); owner != "nic-c" && newOwner != owner {
+			t.Errorf("get(%q) moved the namespace from %v to %v after nic-c was removed", ns, owner, newOwner)
+		}
+	}
+
+	for _, member := range []string{"nic-a", "nic-b", "nic-c"} {
+		if owned[member] < 200 {
+			t.Errorf("member %v owns %d of 1000 namespaces, want at least 200", member, owned[member])
+		}
+	}
+}
+
+func TestHashRingWithoutMembers(t *testing.T) {
+	t.Parallel()
+
+	if owner := newHashRing(nil).get("default"); owner != "" {
+		t.Errorf("get() returned %q for an empty ring, want an empty string", owner)
+	}
+}
+
+func newTestShardLease(name string, holder string, renewTime time.Time) *coordination_v1.Lease {
+	duration := int32(shardLeaseDuration.Seconds())
+	renew := meta_v1.NewMicroTime(renewTime)
+	return &coordination_v1.Lease{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      name,
+			Namespace: "nginx-ingress",
+			Labels:    map[string]string{shardGroupLabel: "nginx-ingress-leader"},
+		},
+		Spec: coordination_v1.LeaseSpec{
+			HolderIdentity:       &holder,
+			LeaseDurationSeconds: &duration,
+			RenewTime:            &renew,
+		},
+	}
+}
+
+func TestShardManagerUpdateMembers(t *testing.T) {
+	t.Parallel()
+
+	changes := 0
+	sm := newShardManager(nl.LoggerFromContext(context.Background()), fake.NewSimpleClientset(), "nginx-ingress",
+		"nginx-ingress-leader", "nic-a", 0, func() { changes++ })
+
+	now := time.Now()
+	for _, lease := range []*coordination_v1.Lease{
+		newTestShardLease("nginx-ingress-leader-shard-nic-b", "nic-b", now),
+		newTestShardLease("nginx-ingress-leader-shard-nic-c", "nic-c", now.Add(-time.Minute)),
+	} {
+		if err := sm.leaseLister.Add(lease); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sm.updateMembers()
+	sm.updateMembers()
+
+	expected := []string{"nic-a", "nic-b"}
+	if !reflect.DeepEqual(sm.members, expected) {
+		t.Errorf("updateMembers() set the members %v, want %v", sm.members, expected)
+	}
+	if changes != 1 {
+		t.Errorf("updateMembers() reported %d changes of the members, want 1", changes)
+	}
+
+	for i := 0; i < 100; i++ {
+		ns := fmt.Sprintf("namespace-%d", i)
+		if sm.owns(ns) != (sm.ring.get(ns) == "nic-a") {
+			t.Errorf("owns(%q) doesn't match the owner %v", ns, sm.ring.get(ns))
+		}
+	}
+}
+
+func TestOwnsNamespaceShardsControllerNamespace(t *testing.T) {
+	t.Parallel()
+
+	lbc := &LoadBalancerController{metadata: controllerMetadata{namespace: "nginx-ingress"}}
+	if !lbc.ownsNamespace("nginx-ingress") {
+		t.Error("ownsNamespace() returned false without namespace sharding, want true")
+	}
+
+	owners := make(map[string]int)
+	for _, identity := range []string{"nic-a", "nic-b"} {
+		lbc.shardManager = newShardManager(nl.LoggerFromContext(context.Background()), fake.NewSimpleClientset(), "nginx-ingress",
+			"nginx-ingress-leader", identity, 0, func() {})
+		lbc.shardManager.ring = newHashRing([]string{"nic-a", "nic-b"})
+		if lbc.ownsNamespace("nginx-ingress") {
+			owners[identity]++
+		}
+	}
+	if len(owners) != 1 {
+		t.Errorf("ownsNamespace() returned true for the controller namespace on shards %v, want exactly one shard", owners)
+	}
+}
+
+func TestShardManagerRenewLease(t *testing.T) {
+	t.Parallel()
+
+	client := fake.NewSimpleClientset()
+	sm := newShardManager(nl.LoggerFromContext(context.Background()), client, "nginx-ingress",
+		"nginx-ingress-leader", "nic-a", 0, func() {})
+
+	if err := sm.renewLease(context.Background()); err != nil {
+		t.Fatalf("renewLease() returned unexpected error for a new Lease: %v", err)
+	}
+	lease, err := client.CoordinationV1().Leases("nginx-ingress").Get(context.Background(), "nginx-ingress-leader-shard-nic-a", meta_v1.GetOptions{})
+	if err != nil {
+		t.Fatalf("renewLease() didn't create the Lease: %v", err)
+	}
+	if lease.Labels[shardGroupLabel] != "nginx-ingress-leader" || *lease.Spec.HolderIdentity != "nic-a" {
+		t.Errorf("renewLease() created an unexpected Lease %+v", lease)
+	}
+	firstRenewTime := lease.Spec.RenewTime.Time
+
+	time.Sleep(time.Millisecond)
+	if err := sm.renewLease(context.Background()); err != nil {
+		t.Fatalf("renewLease() returned unexpected error for an existing Lease: %v", err)
+	}
+	lease, err = client.CoordinationV1().Leases("nginx-ingress").Get(context.Background(), "nginx-ingress-leader-shard-nic-a", meta_v1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lease.Spec.RenewTime.After(firstRenewTime) {
+		t.Errorf("renewLease() didn't renew the Lease: renew time %v, previous renew time %v", lease.Spec.RenewTime.Time, firstRenewTime)
+	}
+}
@@ -11,18 +11,23 @@ import (
 
 	nl "github.com/nginx/kubernetes-ingress/internal/logger"
 	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	dos_v1beta1 "github.com/nginx/kubernetes-ingress/pkg/apis/dos/v1beta1"
 	k8s_nginx "github.com/nginx/kubernetes-ingress/pkg/client/clientset/versioned"
 	api_v1 "k8s.io/api/core/v1"
 	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	typednetworking "k8s.io/client-go/kubernetes/typed/networking/v1"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
+	gateway_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	gateway_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gateway_clientset "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 )
 
-// statusUpdater reports Ingress, VirtualServer and VirtualServerRoute status information via the kubernetes
+// statusUpdater reports Ingress, VirtualServer, VirtualServerRoute, DosProtectedResource and Gateway API status information via the kubernetes
 // API. For external information, it primarily reports the IP or host of the LoadBalancer Service exposing the
 // Ingress Controller, or an external IP specified in the ConfigMap.
 type statusUpdater struct {
@@ -40,6 +45,7 @@ type statusUpdater struct {
 	keyFunc                  func(obj interface{}) (string, error)
 	namespacedInformers      map[string]*namespacedInformer
 	confClient               k8s_nginx.Interface
+	gatewayClient            gateway_clientset.Interface
 	hasCorrectIngressClass   func(interface{}) bool
 	logger                   *slog.Logger
 }
@@ -90,6 +96,8 @@ func (su *statusUpdater) UpdateExternalEndpointsForResource(r Resource) error {
 		if failed {
 			return fmt.Errorf("not all Resources updated")
 		}
+	case *TransportServerConfiguration:
+		return su.updateTransportServerExternalEndpoints(impl.TransportServer)
 	}
 
 	return nil
@@ -445,6 +453,7 @@ func (su *statusUpdater) UpdateTransportServerStatus(ts *conf_v1.TransportServer
 	tsCopy.Status.State = state
 	tsCopy.Status.Reason = reason
 	tsCopy.Status.Message = message
+	tsCopy.Status.ExternalEndpoints = su.externalEndpoints
 
 	_, err = su.confClient.K8sV1().TransportServers(tsCopy.Namespace).UpdateStatus(context.TODO(), tsCopy, metav1.UpdateOptions{})
 	if err != nil {
@@ -631,6 +640,64 @@ func (su *statusUpdater) updateVirtualServerExternalEndpoints(vs *conf_v1.Virtua
 	return err
 }
 
+// updateVirtualServerCanaries updates the states of the canaries in the status of a VirtualServer.
+func (su *statusUpdater) updateVirtualServerCanaries(vs *conf_v1.VirtualServer, canaries []conf_v1.CanaryStatus) error {
+	// Get an up-to-date VirtualServer from the Store
+	var vsLatest interface{}
+	var exists bool
+	var err error
+
+	vsLatest, exists, err = su.getNamespacedInformer(vs.Namespace).virtualServerLister.Get(vs)
+	if err != nil {
+		nl.Infof(su.logger, "error getting VirtualServer from Store: %v", err)
+		return err
+	}
+	if !exists {
+		nl.Infof(su.logger, "VirtualServer doesn't exist in Store")
+		return nil
+	}
+
+	vsCopy := vsLatest.(*conf_v1.VirtualServer).DeepCopy()
+	if reflect.DeepEqual(vsCopy.Status.Canaries, canaries) {
+		return nil
+	}
+	vsCopy.Status.Canaries = canaries
+
+	_, err = su.confClient.K8sV1().VirtualServers(vsCopy.Namespace).UpdateStatus(context.TODO(), vsCopy, metav1.UpdateOptions{})
+	if err != nil {
+		nl.Infof(su.logger, "error setting VirtualServer %v/%v status, retrying: %v", vsCopy.Namespace, vsCopy.Name, err)
+		return su.retryUpdateVirtualServerStatus(vsCopy)
+	}
+	return err
+}
+
+func (su *statusUpdater) updateTransportServerExternalEndpoints(ts *conf_v1.TransportServer) error {
+	// Get a pristine TransportServer from the Store
+	var tsLatest interface{}
+	var exists bool
+	var err error
+
+	tsLatest, exists, err = su.getNamespacedInformer(ts.Namespace).transportServerLister.Get(ts)
+	if err != nil {
+		nl.Infof(su.logger, "error getting TransportServer from Store: %v", err)
+		return err
+	}
+	if !exists {
+		nl.Infof(su.logger, "TransportServer doesn't exist in Store")
+		return nil
+	}
+
+	tsCopy := tsLatest.(*conf_v1.TransportServer).DeepCopy()
+	tsCopy.Status.ExternalEndpoints = su.externalEndpoints
+
+	_, err = su.confClient.K8sV1().TransportServers(tsCopy.Namespace).UpdateStatus(context.TODO(), tsCopy, metav1.UpdateOptions{})
+	if err != nil {
+		nl.Infof(su.logger, "error setting TransportServer %v/%v status, retrying: %v", tsCopy.Namespace, tsCopy.Name, err)
+		return su.retryUpdateTransportServerStatus(tsCopy)
+	}
+	return err
+}
+
 func (su *statusUpdater) updateVirtualServerRouteExternalEndpoints(vsr *conf_v1.VirtualServerRoute) error {
 	// Get an up-to-date VirtualServerRoute from the Store
 	var vsrLatest interface{}
@@ -658,6 +725,37 @@ func (su *statusUpdater) updateVirtualServerRouteExternalEndpoints(vsr *conf_v1.
 	return err
 }
 
+// updateVirtualServerRouteCanaries updates the states of the canaries in the status of a VirtualServerRoute.
+func (su *statusUpdater) updateVirtualServerRouteCanaries(vsr *conf_v1.VirtualServerRoute, canaries []conf_v1.CanaryStatus) error {
+	// Get an up-to-date VirtualServerRoute from the Store
+	var vsrLatest interface{}
+	var exists bool
+	var err error
+
+	vsrLatest, exists, err = su.getNamespacedInformer(vsr.Namespace).virtualServerRouteLister.Get(vsr)
+	if err != nil {
+		nl.Infof(su.logger, "error getting VirtualServerRoute from Store: %v", err)
+		return err
+	}
+	if !exists {
+		nl.Infof(su.logger, "VirtualServerRoute doesn't exist in Store")
+		return nil
+	}
+
+	vsrCopy := vsrLatest.(*conf_v1.VirtualServerRoute).DeepCopy()
+	if reflect.DeepEqual(vsrCopy.Status.Canaries, canaries) {
+		return nil
+	}
+	vsrCopy.Status.Canaries = canaries
+
+	_, err = su.confClient.K8sV1().VirtualServerRoutes(vsrCopy.Namespace).UpdateStatus(context.TODO(), vsrCopy, metav1.UpdateOptions{})
+	if err != nil {
+		nl.Infof(su.logger, "error setting VirtualServerRoute %v/%v status, retrying: %v", vsrCopy.Namespace, vsrCopy.Name, err)
+		return su.retryUpdateVirtualServerRouteStatus(vsrCopy)
+	}
+	return err
+}
+
 func (su *statusUpdater) generateExternalEndpointsFromStatus(status []networking.IngressLoadBalancerIngress) []conf_v1.ExternalEndpoint {
 	var externalEndpoints []conf_v1.ExternalEndpoint
 	for _, lb := range status {
@@ -732,3 +830,308 @@ func (su *statusUpdater) retryUpdatePolicyStatus(polCopy *conf_v1.Policy) error
 
 	return nil
 }
+
+func hasDosProtectedResourceStatusChanged(pr *dos_v1beta1.DosProtectedResource, state string, reason string, message string, referencedBy []string) bool {
+	return pr.Status.State != state || pr.Status.Reason != reason || pr.Status.Message != message ||
+		!reflect.DeepEqual(pr.Status.ReferencedBy, referencedBy)
+}
+
+// UpdateDosProtectedResourceStatus updates the status of a DosProtectedResource, including the referencedBy field.
+func (su *statusUpdater) UpdateDosProtectedResourceStatus(pr *dos_v1beta1.DosProtectedResource, state string, reason string, message string, referencedBy []string) error {
+	// Get an up-to-date DosProtectedResource from the Store
+	prLatest, exists, err := su.getNamespacedInformer(pr.Namespace).appProtectDosProtectedLister.Get(pr)
+	if err != nil {
+		nl.Infof(su.logger, "error getting DosProtectedResource from Store: %v", err)
+		return err
+	}
+	if !exists {
+		nl.Infof(su.logger, "DosProtectedResource doesn't exist in Store")
+		return nil
+	}
+
+	prCopy := prLatest.(*dos_v1beta1.DosProtectedResource).DeepCopy()
+
+	if !hasDosProtectedResourceStatusChanged(prCopy, state, reason, message, referencedBy) {
+		return nil
+	}
+
+	prCopy.Status.State = state
+	prCopy.Status.Reason = reason
+	prCopy.Status.Message = message
+	prCopy.Status.ReferencedBy = referencedBy
+
+	_, err = su.confClient.AppprotectdosV1beta1().DosProtectedResources(prCopy.Namespace).UpdateStatus(context.TODO(), prCopy, metav1.UpdateOptions{})
+	if err != nil {
+		nl.Infof(su.logger, "error setting DosProtectedResource %v/%v status, retrying: %v", prCopy.Namespace, prCopy.Name, err)
+		return su.retryUpdateDosProtectedResourceStatus(prCopy)
+	}
+
+	return nil
+}
+
+func (su *statusUpdater) retryUpdateDosProtectedResourceStatus(prCopy *dos_v1beta1.DosProtectedResource) error {
+	pr, err := su.confClient.AppprotectdosV1beta1().DosProtectedResources(prCopy.Namespace).Get(context.TODO(), prCopy.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	pr.Status = prCopy.Status
+	_, err = su.confClient.AppprotectdosV1beta1().DosProtectedResources(pr.Namespace).UpdateStatus(context.TODO(), pr, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// mergeGatewayConditions returns the conditions with the types of the new conditions.
+// The conditions that didn't change their status keep their last transition time.
+func mergeGatewayConditions(existing []metav1.Condition, conditions []metav1.Condition) []metav1.Condition {
+	var merged []metav1.Condition
+	for _, c := range existing {
+		if meta.FindStatusCondition(conditions, c.Type) != nil {
+			merged = append(merged, c)
+		}
+	}
+	for _, c := range conditions {
+		meta.SetStatusCondition(&merged, c)
+	}
+	return merged
+}
+
+// mergeRouteParentStatuses replaces the statuses of the parent references to the Gateway set by the Ingress Controller
+// with the new statuses. The statuses of other parents and of other controllers are kept.
+func mergeRouteParentStatuses(existing []gateway_v1.RouteParentStatus, routeNamespace string, gw *gateway_v1.Gateway,
+	parents []gateway_v1.RouteParentStatus,
+) []gateway_v1.RouteParentStatus {
+	var merged []gateway_v1.RouteParentStatus
+	existingConditions := make(map[string][]metav1.Condition)
+
+	for _, p := range existing {
+		if p.ControllerName == gateway_v1.GatewayController(IngressControllerName) && isGatewayParentRef(p.ParentRef, routeNamespace, gw) {
+			existingConditions[getParentRefKey(p.ParentRef)] = p.Conditions
+			continue
+		}
+		merged = append(merged, p)
+	}
+
+	for _, p := range parents {
+		parent := *p.DeepCopy()
+		parent.Conditions = mergeGatewayConditions(existingConditions[getParentRefKey(p.ParentRef)], p.Conditions)
+		merged = append(merged, parent)
+	}
+
+	return merged
+}
+
+// getParentRefKey returns a key that identifies the parent reference, so that the statuses of the same parent can be matched.
+func getParentRefKey(ref gateway_v1.ParentReference) string {
+	var namespace, sectionName string
+	var port gateway_v1.PortNumber
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+	if ref.SectionName != nil {
+		sectionName = string(*ref.SectionName)
+	}
+	if ref.Port != nil {
+		port = *ref.Port
+	}
+	return fmt.Sprintf("%s/%s/%s/%d", namespace, ref.Name, sectionName, port)
+}
+
+// generateGatewayAddresses returns the addresses of the Ingress Controller for the status of Gateways.
+func (su *statusUpdater) generateGatewayAddresses() []gateway_v1.GatewayStatusAddress {
+	var addresses []gateway_v1.GatewayStatusAddress
+	for _, e := range su.externalEndpoints {
+		if e.IP != "" {
+			addresses = append(addresses, gateway_v1.GatewayStatusAddress{Type: createPointerFromGatewayAddressType(gateway_v1.IPAddressType), Value: e.IP})
+		}
+		if e.Hostname != "" {
+			addresses = append(addresses, gateway_v1.GatewayStatusAddress{Type: createPointerFromGatewayAddressType(gateway_v1.HostnameAddressType), Value: e.Hostname})
+		}
+	}
+	return addresses
+}
+
+func createPointerFromGatewayAddressType(t gateway_v1.AddressType) *gateway_v1.AddressType {
+	return &t
+}
+
+// UpdateGatewayClassStatus updates the status of a GatewayClass.
+func (su *statusUpdater) UpdateGatewayClassStatus(gc *gateway_v1.GatewayClass, conditions []metav1.Condition) error {
+	gcCopy := gc.DeepCopy()
+	gcCopy.Status.Conditions = mergeGatewayConditions(gc.Status.Conditions, conditions)
+
+	if reflect.DeepEqual(gc.Status, gcCopy.Status) {
+		return nil
+	}
+
+	_, err := su.gatewayClient.GatewayV1().GatewayClasses().UpdateStatus(context.TODO(), gcCopy, metav1.UpdateOptions{})
+	if err != nil {
+		nl.Infof(su.logger, "error setting GatewayClass %v status, retrying: %v", gcCopy.Name, err)
+		return su.retryUpdateGatewayClassStatus(gcCopy)
+	}
+	return nil
+}
+
+func (su *statusUpdater) retryUpdateGatewayClassStatus(gcCopy *gateway_v1.GatewayClass) error {
+	gc, err := su.gatewayClient.GatewayV1().GatewayClasses().Get(context.TODO(), gcCopy.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	gc.Status = gcCopy.Status
+	_, err = su.gatewayClient.GatewayV1().GatewayClasses().UpdateStatus(context.TODO(), gc, metav1.UpdateOptions{})
+	return err
+}
+
+// UpdateGatewayStatus updates the status of a Gateway.
+func (su *statusUpdater) UpdateGatewayStatus(gw *gateway_v1.Gateway, conditions []metav1.Condition, listeners []gateway_v1.ListenerStatus) error {
+	// Get an up-to-date Gateway from the Store
+	gwLatest, exists, err := su.getNamespacedInformer(gw.Namespace).gatewayLister.Get(gw)
+	if err != nil {
+		nl.Infof(su.logger, "error getting Gateway from Store: %v", err)
+		return err
+	}
+	if !exists {
+		nl.Infof(su.logger, "Gateway doesn't exist in Store")
+		return nil
+	}
+
+	gwCopy := gwLatest.(*gateway_v1.Gateway).DeepCopy()
+	gwCopy.Status.Addresses = su.generateGatewayAddresses()
+	gwCopy.Status.Conditions = mergeGatewayConditions(gwCopy.Status.Conditions, conditions)
+
+	var listenerStatuses []gateway_v1.ListenerStatus
+	for _, l := range listeners {
+		status := *l.DeepCopy()
+		var existing []metav1.Condition
+		for _, el := range gwCopy.Status.Listeners {
+			if el.Name == l.Name {
+				existing = el.Conditions
+			}
+		}
+		status.Conditions = mergeGatewayConditions(existing, l.Conditions)
+		listenerStatuses = append(listenerStatuses, status)
+	}
+	gwCopy.Status.Listeners = listenerStatuses
+
+	if reflect.DeepEqual(gwLatest.(*gateway_v1.Gateway).Status, gwCopy.Status) {
+		return nil
+	}
+
+	_, err = su.gatewayClient.GatewayV1().Gateways(gwCopy.Namespace).UpdateStatus(context.TODO(), gwCopy, metav1.UpdateOptions{})
+	if err != nil {
+		nl.Infof(su.logger, "error setting Gateway %v/%v status, retrying: %v", gwCopy.Namespace, gwCopy.Name, err)
+		return su.retryUpdateGatewayStatus(gwCopy)
+	}
+	return nil
+}
+
+func (su *statusUpdater) retryUpdateGatewayStatus(gwCopy *gateway_v1.Gateway) error {
+	gw, err := su.gatewayClient.GatewayV1().Gateways(gwCopy.Namespace).Get(context.TODO(), gwCopy.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	gw.Status = gwCopy.Status
+	_, err = su.gatewayClient.GatewayV1().Gateways(gw.Namespace).UpdateStatus(context.TODO(), gw, metav1.UpdateOptions{})
+	return err
+}
+
+// UpdateHTTPRouteStatus updates the statuses of the parent references of an HTTPRoute to the Gateway.
+func (su *statusUpdater) UpdateHTTPRouteStatus(namespace string, name string, gw *gateway_v1.Gateway, parents []gateway_v1.RouteParentStatus) error {
+	routeLatest, exists, err := su.getNamespacedInformer(namespace).httpRouteLister.GetByKey(namespace + "/" + name)
+	if err != nil {
+		nl.Infof(su.logger, "error getting HTTPRoute from Store: %v", err)
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	route := routeLatest.(*gateway_v1.HTTPRoute)
+	routeCopy := route.DeepCopy()
+	routeCopy.Status.Parents = mergeRouteParentStatuses(route.Status.Parents, namespace, gw, parents)
+
+	if reflect.DeepEqual(route.Status, routeCopy.Status) {
+		return nil
+	}
+
+	_, err = su.gatewayClient.GatewayV1().HTTPRoutes(namespace).UpdateStatus(context.TODO(), routeCopy, metav1.UpdateOptions{})
+	if err != nil {
+		nl.Infof(su.logger, "error setting HTTPRoute %v/%v status, retrying: %v", namespace, name, err)
+		route, err = su.gatewayClient.GatewayV1().HTTPRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		route.Status.Parents = mergeRouteParentStatuses(route.Status.Parents, namespace, gw, parents)
+		_, err = su.gatewayClient.GatewayV1().HTTPRoutes(namespace).UpdateStatus(context.TODO(), route, metav1.UpdateOptions{})
+	}
+	return err
+}
+
+// UpdateGRPCRouteStatus updates the statuses of the parent references of a GRPCRoute to the Gateway.
+func (su *statusUpdater) UpdateGRPCRouteStatus(namespace string, name string, gw *gateway_v1.Gateway, parents []gateway_v1.RouteParentStatus) error {
+	routeLatest, exists, err := su.getNamespacedInformer(namespace).grpcRouteLister.GetByKey(namespace + "/" + name)
+	if err != nil {
+		nl.Infof(su.logger, "error getting GRPCRoute from Store: %v", err)
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	route := routeLatest.(*gateway_v1.GRPCRoute)
+	routeCopy := route.DeepCopy()
+	routeCopy.Status.Parents = mergeRouteParentStatuses(route.Status.Parents, namespace, gw, parents)
+
+	if reflect.DeepEqual(route.Status, routeCopy.Status) {
+		return nil
+	}
+
+	_, err = su.gatewayClient.GatewayV1().GRPCRoutes(namespace).UpdateStatus(context.TODO(), routeCopy, metav1.UpdateOptions{})
+	if err != nil {
+		nl.Infof(su.logger, "error setting GRPCRoute %v/%v status, retrying: %v", namespace, name, err)
+		route, err = su.gatewayClient.GatewayV1().GRPCRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		route.Status.Parents = mergeRouteParentStatuses(route.Status.Parents, namespace, gw, parents)
+		_, err = su.gatewayClient.GatewayV1().GRPCRoutes(namespace).UpdateStatus(context.TODO(), route, metav1.UpdateOptions{})
+	}
+	return err
+}
+
+// UpdateTLSRouteStatus updates the statuses of the parent references of a TLSRoute to the Gateway.
+func (su *statusUpdater) UpdateTLSRouteStatus(namespace string, name string, gw *gateway_v1.Gateway, parents []gateway_v1.RouteParentStatus) error {
+	routeLatest, exists, err := su.getNamespacedInformer(namespace).tlsRouteLister.GetByKey(namespace + "/" + name)
+	if err != nil {
+		nl.Infof(su.logger, "error getting TLSRoute from Store: %v", err)
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	route := routeLatest.(*gateway_v1alpha2.TLSRoute)
+	routeCopy := route.DeepCopy()
+	routeCopy.Status.Parents = mergeRouteParentStatuses(route.Status.Parents, namespace, gw, parents)
+
+	if reflect.DeepEqual(route.Status, routeCopy.Status) {
+		return nil
+	}
+
+	_, err = su.gatewayClient.GatewayV1alpha2().TLSRoutes(namespace).UpdateStatus(context.TODO(), routeCopy, metav1.UpdateOptions{})
+	if err != nil {
+		nl.Infof(su.logger, "error setting TLSRoute %v/%v status, retrying: %v", namespace, name, err)
+		route, err = su.gatewayClient.GatewayV1alpha2().TLSRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		route.Status.Parents = mergeRouteParentStatuses(route.Status.Parents, namespace, gw, parents)
+		_, err = su.gatewayClient.GatewayV1alpha2().TLSRoutes(namespace).UpdateStatus(context.TODO(), route, metav1.UpdateOptions{})
+	}
+	return err
+}
@@ -12,6 +12,7 @@ import (
 	nic_glog "github.com/nginx/kubernetes-ingress/internal/logger/glog"
 	"github.com/nginx/kubernetes-ingress/internal/logger/levels"
 	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	dos_v1beta1 "github.com/nginx/kubernetes-ingress/pkg/apis/dos/v1beta1"
 	fake_v1 "github.com/nginx/kubernetes-ingress/pkg/client/clientset/versioned/fake"
 	v1 "k8s.io/api/core/v1"
 	networking "k8s.io/api/networking/v1"
@@ -20,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 )
 
 func TestUpdateTransportServerStatus(t *testing.T) {
@@ -74,6 +76,59 @@ func TestUpdateTransportServerStatus(t *testing.T) {
 	}
 }
 
+func TestUpdateDosProtectedResourceStatus(t *testing.T) {
+	t.Parallel()
+	pr := &dos_v1beta1.DosProtectedResource{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "dos-protected",
+			Namespace: "default",
+		},
+		Status: dos_v1beta1.DosProtectedResourceStatus{
+			State:   "before status",
+			Reason:  "before reason",
+			Message: "before message",
+		},
+	}
+
+	fakeClient := fake_v1.NewSimpleClientset(
+		&dos_v1beta1.DosProtectedResourceList{
+			Items: []dos_v1beta1.DosProtectedResource{
+				*pr,
+			},
+		})
+
+	prLister := cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc)
+
+	err := prLister.Add(pr)
+	if err != nil {
+		t.Errorf("Error adding DosProtectedResource to the dos protected lister: %v", err)
+	}
+	nsi := make(map[string]*namespacedInformer)
+	nsi["default"] = &namespacedInformer{appProtectDosProtectedLister: prLister}
+	su := statusUpdater{
+		namespacedInformers: nsi,
+		confClient:          fakeClient,
+		keyFunc:             cache.DeletionHandlingMetaNamespaceKeyFunc,
+	}
+
+	err = su.UpdateDosProtectedResourceStatus(pr, "after status", "after reason", "after message", []string{"VirtualServer/default/cafe"})
+	if err != nil {
+		t.Errorf("error updating dos protected resource status: %v", err)
+	}
+	updatedPr, _ := fakeClient.AppprotectdosV1beta1().DosProtectedResources(pr.Namespace).Get(context.TODO(), pr.Name, meta_v1.GetOptions{})
+
+	expectedStatus := dos_v1beta1.DosProtectedResourceStatus{
+		State:        "after status",
+		Reason:       "after reason",
+		Message:      "after message",
+		ReferencedBy: []string{"VirtualServer/default/cafe"},
+	}
+
+	if diff := cmp.Diff(expectedStatus, updatedPr.Status); diff != "" {
+		t.Errorf("Unexpected status (-want +got):\n%s", diff)
+	}
+}
+
 func TestUpdateTransportServerStatusIgnoreNoChange(t *testing.T) {
 	t.Parallel()
 	ts := &conf_v1.TransportServer{
@@ -775,3 +830,161 @@ func TestHasPolicyStatusChanged(t *testing.T) {
 		}
 	}
 }
+
+func TestHasDosProtectedResourceStatusChanged(t *testing.T) {
+	t.Parallel()
+	state := "Valid"
+	reason := "AddedOrUpdated"
+	msg := "Configuration was added or updated"
+	referencedBy := []string{"VirtualServer/default/cafe"}
+
+	tests := []struct {
+		expected bool
+		pr       dos_v1beta1.DosProtectedResource
+	}{
+		{
+			expected: false,
+			pr: dos_v1beta1.DosProtectedResource{
+				Status: dos_v1beta1.DosProtectedResourceStatus{
+					State:        state,
+					Reason:       reason,
+					Message:      msg,
+					ReferencedBy: []string{"VirtualServer/default/cafe"},
+				},
+			},
+		},
+		{
+			expected: true,
+			pr: dos_v1beta1.DosProtectedResource{
+				Status: dos_v1beta1.DosProtectedResourceStatus{
+					State:        "DifferentState",
+					Reason:       reason,
+					Message:      msg,
+					ReferencedBy: []string{"VirtualServer/default/cafe"},
+				},
+			},
+		},
+		{
+			expected: true,
+			pr: dos_v1beta1.DosProtectedResource{
+				Status: dos_v1beta1.DosProtectedResourceStatus{
+					State:        state,
+					Reason:       "DifferentReason",
+					Message:      msg,
+					ReferencedBy: []string{"VirtualServer/default/cafe"},
+				},
+			},
+		},
+		{
+			expected: true,
+			pr: dos_v1beta1.DosProtectedResource{
+				Status: dos_v1beta1.DosProtectedResourceStatus{
+					State:        state,
+					Reason:       reason,
+					Message:      "DifferentMessage",
+					ReferencedBy: []string{"VirtualServer/default/cafe"},
+				},
+			},
+		},
+		{
+			expected: true,
+			pr: dos_v1beta1.DosProtectedResource{
+				Status: dos_v1beta1.DosProtectedResourceStatus{
+					State:        state,
+					Reason:       reason,
+					Message:      msg,
+					ReferencedBy: []string{"VirtualServer/default/cafe", "Ingress/default/tea"},
+				},
+			},
+		},
+		{
+			expected: true,
+			pr: dos_v1beta1.DosProtectedResource{
+				Status: dos_v1beta1.DosProtectedResourceStatus{
+					State:   state,
+					Reason:  reason,
+					Message: msg,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		changed := hasDosProtectedResourceStatusChanged(&test.pr, state, reason, msg, referencedBy)
+
+		if changed != test.expected {
+			t.Errorf("hasDosProtectedResourceStatusChanged(%v, %v, %v, %v, %v) returned %v but expected %v.", test.pr, state, reason, msg, referencedBy, changed, test.expected)
+		}
+	}
+}
+
+func TestSyncPolicyUpdatesRetryPolicyStatus(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		retry         *conf_v1.Retry
+		expectedState string
+		msg           string
+	}{
+		{
+			retry: &conf_v1.Retry{
+				RetryOn:  []string{"error", "http_503"},
+				Sampling: createPointerFromInt(20),
+			},
+			expectedState: conf_v1.StateValid,
+			msg:           "valid retry policy",
+		},
+		{
+			retry: &conf_v1.Retry{
+				Sampling: createPointerFromInt(150),
+			},
+			expectedState: conf_v1.StateInvalid,
+			msg:           "invalid retry policy",
+		},
+	}
+
+	for _, test := range tests {
+		pol := &conf_v1.Policy{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "retry-policy",
+				Namespace: "default",
+			},
+			Spec: conf_v1.PolicySpec{
+				Retry: test.retry,
+			},
+		}
+
+		fakeClient := fake_v1.NewSimpleClientset(pol.DeepCopy())
+		polLister := cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc)
+		err := polLister.Add(pol)
+		if err != nil {
+			t.Fatalf("Error adding Policy to the policy lister: %v", err)
+		}
+
+		l := slog.New(nic_glog.New(io.Discard, &nic_glog.Options{Level: levels.LevelInfo}))
+		nsi := map[string]*namespacedInformer{"default": {policyLister: polLister}}
+		lbc := LoadBalancerController{
+			ingressClass:        "nginx",
+			recorder:            record.NewFakeRecorder(10),
+			configuration:       createTestConfiguration(),
+			namespacedInformers: nsi,
+			statusUpdater: &statusUpdater{
+				namespacedInformers:    nsi,
+				confClient:             fakeClient,
+				keyFunc:                cache.DeletionHandlingMetaNamespaceKeyFunc,
+				hasCorrectIngressClass: func(interface{}) bool { return true },
+				logger:                 l,
+			},
+			Logger: l,
+		}
+
+		lbc.syncPolicy(task{Kind: policy, Key: "default/retry-policy"})
+
+		updatedPol, err := fakeClient.K8sV1().Policies(pol.Namespace).Get(context.TODO(), pol.Name, meta_v1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Error getting Policy: %v", err)
+		}
+		if updatedPol.Status.State != test.expectedState {
+			t.Errorf("syncPolicy() set the state %q but expected %q for the case of %s", updatedPol.Status.State, test.expectedState, test.msg)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package k8s
 import (
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/nginx/kubernetes-ingress/pkg/apis/dos/v1beta1"
@@ -10,6 +11,7 @@ import (
 	"github.com/nginx/kubernetes-ingress/internal/k8s/appprotect"
 	"github.com/nginx/kubernetes-ingress/internal/k8s/appprotectdos"
 	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	"github.com/nginx/kubernetes-ingress/internal/metrics/collectors"
 	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
 	v1 "k8s.io/api/core/v1"
 	discovery_v1 "k8s.io/api/discovery/v1"
@@ -17,35 +19,73 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/workqueue"
+	gateway_v1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
-// taskQueue manages a work queue through an independent worker that
-// invokes the given sync function for every work item inserted.
+const (
+	// requeueBaseDelay is the delay of the first requeue of a failed task.
+	requeueBaseDelay = 100 * time.Millisecond
+	// requeueMaxDelay is the maximum delay of a requeue of a failed task.
+	requeueMaxDelay = 5 * time.Minute
+)
+
+// taskQueue manages a rate limiting work queue through independent workers that
+// invoke the given sync function for every work item inserted.
+// Tasks are processed in the order of the priority of their kind and failed tasks are
+// requeued with an exponential backoff per task.
 type taskQueue struct {
-	// queue is the work queue the worker polls
-	queue *workqueue.Type
+	// queue is the work queue the workers poll
+	queue workqueue.TypedRateLimitingInterface[task]
 	// sync is called for each item in the queue
 	sync func(task)
-	// workerDone is closed when the worker exits
-	workerDone chan struct{}
+	// workersDone is done when all the workers exit
+	workersDone sync.WaitGroup
+	// failed keeps the tasks requeued by their sync, so that their backoff is not reset
+	failed     map[task]bool
+	failedLock sync.Mutex
+	metrics    collectors.ControllerCollector
 	// logger
 	logger *slog.Logger
 }
 
 // newTaskQueue creates a new task queue with the given sync function.
 // The sync function is called for every element inserted into the queue.
-func newTaskQueue(logger *slog.Logger, syncFn func(task)) *taskQueue {
+func newTaskQueue(logger *slog.Logger, syncFn func(task), metrics collectors.ControllerCollector) *taskQueue {
+	if metrics == nil {
+		metrics = collectors.NewControllerFakeCollector()
+	}
+
+	queue := workqueue.NewTypedWithConfig(workqueue.TypedQueueConfig[task]{
+		Name:  "taskQueue",
+		Queue: newPriorityQueue(metrics),
+	})
+
 	return &taskQueue{
-		queue:      workqueue.NewNamed("taskQueue"),
-		sync:       syncFn,
-		workerDone: make(chan struct{}),
-		logger:     logger,
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(
+			workqueue.NewTypedItemExponentialFailureRateLimiter[task](requeueBaseDelay, requeueMaxDelay),
+			workqueue.TypedRateLimitingQueueConfig[task]{
+				Name:          "taskQueue",
+				DelayingQueue: workqueue.NewTypedDelayingQueueWithConfig(workqueue.TypedDelayingQueueConfig[task]{Name: "taskQueue", Queue: queue}),
+			},
+		),
+		sync:    syncFn,
+		failed:  make(map[task]bool),
+		metrics: metrics,
+		logger:  logger,
 	}
 }
 
-// Run begins running the worker for the given duration
-func (tq *taskQueue) Run(period time.Duration, stopCh <-chan struct{}) {
-	wait.Until(tq.worker, period, stopCh)
+// Run begins running the given number of workers for the given duration.
+// The sync function must be safe for concurrent use if there is more than one worker.
+func (tq *taskQueue) Run(period time.Duration, workers int, stopCh <-chan struct{}) {
+	for i := 0; i < workers; i++ {
+		tq.workersDone.Add(1)
+		go func() {
+			defer tq.workersDone.Done()
+			wait.Until(tq.worker, period, stopCh)
+		}()
+	}
+	tq.workersDone.Wait()
 }
 
 // Enqueue enqueues ns/name of the given api object in the task queue.
@@ -66,10 +106,16 @@ func (tq *taskQueue) Enqueue(obj interface{}) {
 	tq.queue.Add(task)
 }
 
-// Requeue adds the task to the queue again and logs the given error
+// Requeue adds the task to the queue again after its backoff and logs the given error
 func (tq *taskQueue) Requeue(task task, err error) {
-	nl.Errorf(tq.logger, "Requeuing %v, err %v", task.Key, err)
-	tq.queue.Add(task)
+	nl.Errorf(tq.logger, "Requeuing %v after %v failure(s), err %v", task.Key, tq.queue.NumRequeues(task)+1, err)
+
+	tq.failedLock.Lock()
+	tq.failed[task] = true
+	tq.failedLock.Unlock()
+
+	tq.metrics.IncTaskQueueRetries(task.Kind.String())
+	tq.queue.AddRateLimited(task)
 }
 
 // Len returns the length of the queue
@@ -81,30 +127,122 @@ func (tq *taskQueue) Len() int {
 // RequeueAfter adds the task to the queue after the given duration
 func (tq *taskQueue) RequeueAfter(t task, err error, after time.Duration) {
 	nl.Errorf(tq.logger, "Requeuing %v after %s, err %v", t.Key, after.String(), err)
-	go func(t task, after time.Duration) {
-		time.Sleep(after)
-		tq.queue.Add(t)
-	}(t, after)
+	tq.queue.AddAfter(t, after)
 }
 
 // Worker processes work in the queue through sync.
 func (tq *taskQueue) worker() {
-	for {
-		t, quit := tq.queue.Get()
-		if quit {
-			close(tq.workerDone)
-			return
-		}
-		nl.Debugf(tq.logger, "Syncing %v", t.(task).Key)
-		tq.sync(t.(task))
-		tq.queue.Done(t)
+	for tq.processNextTask() {
 	}
 }
 
-// Shutdown shuts down the work queue and waits for the worker to ACK
+func (tq *taskQueue) processNextTask() bool {
+	t, quit := tq.queue.Get()
+	if quit {
+		return false
+	}
+	defer tq.queue.Done(t)
+
+	nl.Debugf(tq.logger, "Syncing %v", t.Key)
+	tq.sync(t)
+
+	tq.failedLock.Lock()
+	failed := tq.failed[t]
+	delete(tq.failed, t)
+	tq.failedLock.Unlock()
+
+	if !failed {
+		tq.queue.Forget(t)
+	}
+	return true
+}
+
+// Shutdown shuts down the work queue and waits for the workers to ACK
 func (tq *taskQueue) Shutdown() {
 	tq.queue.ShutDown()
-	<-tq.workerDone
+	tq.workersDone.Wait()
+}
+
+// taskPriority is the priority of the tasks of a kind. Tasks with a higher priority are processed first.
+type taskPriority int
+
+const (
+	lowTaskPriority taskPriority = iota
+	normalTaskPriority
+	highTaskPriority
+	numTaskPriorities
+)
+
+func (p taskPriority) String() string {
+	switch p {
+	case lowTaskPriority:
+		return "low"
+	case normalTaskPriority:
+		return "normal"
+	case highTaskPriority:
+		return "high"
+	}
+	return "unknown"
+}
+
+// priorityQueue implements workqueue.Queue with a FIFO lane per priority.
+// It is always accessed under the lock of the work queue.
+type priorityQueue struct {
+	lanes   [numTaskPriorities][]task
+	addedAt map[task]time.Time
+	metrics collectors.ControllerCollector
+}
+
+func newPriorityQueue(metrics collectors.ControllerCollector) *priorityQueue {
+	q := &priorityQueue{
+		addedAt: make(map[task]time.Time),
+		metrics: metrics,
+	}
+	for p := lowTaskPriority; p < numTaskPriorities; p++ {
+		metrics.SetTaskQueueDepth(p.String(), 0)
+	}
+	return q
+}
+
+// Touch is called when a queued task is added again. The task keeps its place in the lane.
+func (q *priorityQueue) Touch(_ task) {}
+
+// Push adds the task to the lane of its priority.
+func (q *priorityQueue) Push(t task) {
+	p := t.Kind.priority()
+	q.lanes[p] = append(q.lanes[p], t)
+	q.addedAt[t] = time.Now()
+	q.metrics.SetTaskQueueDepth(p.String(), len(q.lanes[p]))
+}
+
+// Len returns the number of tasks in all the lanes.
+func (q *priorityQueue) Len() int {
+	l := 0
+	for _, lane := range q.lanes {
+		l += len(lane)
+	}
+	return l
+}
+
+// Pop returns the first task of the lane with the highest priority.
+func (q *priorityQueue) Pop() task {
+	for p := highTaskPriority; p >= lowTaskPriority; p-- {
+		if len(q.lanes[p]) == 0 {
+			continue
+		}
+
+		t := q.lanes[p][0]
+		q.lanes[p][0] = task{}
+		q.lanes[p] = q.lanes[p][1:]
+		q.metrics.SetTaskQueueDepth(p.String(), len(q.lanes[p]))
+
+		if addedAt, exists := q.addedAt[t]; exists {
+			q.metrics.ObserveTaskQueueLatency(t.Kind.String(), time.Since(addedAt))
+			delete(q.addedAt, t)
+		}
+		return t
+	}
+	return task{}
 }
 
 // kind represents the kind of the Kubernetes resources of a task
@@ -130,8 +268,54 @@ const (
 	appProtectDosLogConf
 	appProtectDosProtectedResource
 	ingressLink
+	gatewayClass
+	gateway
 )
 
+var kindNames = map[kind]string{
+	ingress:                        "ingress",
+	endpointslice:                  "endpointslice",
+	configMap:                      "configmap",
+	secret:                         "secret",
+	service:                        "service",
+	namespace:                      "namespace",
+	virtualserver:                  "virtualserver",
+	virtualServerRoute:             "virtualserverroute",
+	globalConfiguration:            "globalconfiguration",
+	transportserver:                "transportserver",
+	policy:                         "policy",
+	appProtectPolicy:               "appprotectpolicy",
+	appProtectLogConf:              "appprotectlogconf",
+	appProtectUserSig:              "appprotectusersig",
+	appProtectDosPolicy:            "appprotectdospolicy",
+	appProtectDosLogConf:           "appprotectdoslogconf",
+	appProtectDosProtectedResource: "dosprotectedresource",
+	ingressLink:                    "ingresslink",
+	gatewayClass:                   "gatewayclass",
+	gateway:                        "gateway",
+}
+
+func (k kind) String() string {
+	if name, exists := kindNames[k]; exists {
+		return name
+	}
+	return "unknown"
+}
+
+// priority returns the priority of the tasks of the kind. Changes of the configuration, like ConfigMaps,
+// Secrets and Policies, come first, then the resources and finally the endpoints, so that a burst of
+// endpoint updates doesn't delay unrelated changes.
+func (k kind) priority() taskPriority {
+	switch k {
+	case endpointslice:
+		return lowTaskPriority
+	case ingress, service, virtualserver, virtualServerRoute, transportserver, appProtectDosProtectedResource, ingressLink, gateway:
+		return normalTaskPriority
+	default:
+		return highTaskPriority
+	}
+}
+
 // task is an element of a taskQueue
 type task struct {
 	Kind kind
@@ -166,6 +350,10 @@ func newTask(key string, obj interface{}) (task, error) {
 		k = transportserver
 	case *v1beta1.DosProtectedResource:
 		k = appProtectDosProtectedResource
+	case *gateway_v1.GatewayClass:
+		k = gatewayClass
+	case *gateway_v1.Gateway:
+		k = gateway
 	case *unstructured.Unstructured:
 		if objectKind := obj.(*unstructured.Unstructured).GetKind(); objectKind == appprotect.PolicyGVK.Kind {
 			k = appProtectPolicy
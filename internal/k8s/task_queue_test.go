@@ -0,0 +1,79 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	v1 "k8s.io/api/core/v1"
+	discovery_v1 "k8s.io/api/discovery/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTaskQueueProcessesTasksByPriority(t *testing.T) {
+	t.Parallel()
+
+	var synced []string
+	tq := newTaskQueue(nl.LoggerFromContext(context.Background()), func(t task) {
+		synced = append(synced, t.Kind.String()+" "+t.Key)
+	}, nil)
+	defer tq.Shutdown()
+
+	tq.Enqueue(&discovery_v1.EndpointSlice{ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "coffee-abc"}})
+	tq.Enqueue(&conf_v1.VirtualServer{ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "cafe"}})
+	tq.Enqueue(&discovery_v1.EndpointSlice{ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "tea-abc"}})
+	tq.Enqueue(&v1.Secret{ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "cafe-secret"}})
+	tq.Enqueue(&conf_v1.VirtualServer{ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "cafe"}})
+
+	if tq.Len() != 4 {
+		t.Fatalf("Len() returned %d, want 4", tq.Len())
+	}
+	for tq.Len() > 0 {
+		tq.processNextTask()
+	}
+
+	expected := []string{
+		"secret default/cafe-secret",
+		"virtualserver default/cafe",
+		"endpointslice default/coffee-abc",
+		"endpointslice default/tea-abc",
+	}
+	if !reflect.DeepEqual(synced, expected) {
+		t.Errorf("the tasks were synced in the order %v, want %v", synced, expected)
+	}
+}
+
+func TestTaskQueueBacksOffFailedTasks(t *testing.T) {
+	t.Parallel()
+
+	fail := true
+	var tq *taskQueue
+	tq = newTaskQueue(nl.LoggerFromContext(context.Background()), func(t task) {
+		if fail {
+			tq.Requeue(t, errors.New("failed"))
+		}
+	}, nil)
+	defer tq.Shutdown()
+
+	tq.Enqueue(&conf_v1.VirtualServer{ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "cafe"}})
+	vsTask := task{Kind: virtualserver, Key: "default/cafe"}
+
+	tq.processNextTask()
+	if requeues := tq.queue.NumRequeues(vsTask); requeues != 1 {
+		t.Errorf("NumRequeues() returned %d after a failure, want 1", requeues)
+	}
+
+	tq.processNextTask()
+	if requeues := tq.queue.NumRequeues(vsTask); requeues != 2 {
+		t.Errorf("NumRequeues() returned %d after two failures, want 2", requeues)
+	}
+
+	fail = false
+	tq.processNextTask()
+	if requeues := tq.queue.NumRequeues(vsTask); requeues != 0 {
+		t.Errorf("NumRequeues() returned %d after a success, want 0", requeues)
+	}
+}
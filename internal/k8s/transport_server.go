@@ -248,6 +248,37 @@ func (lbc *LoadBalancerController) createTransportServerEx(transportServer *conf
 		scrtRefs[scrtKey] = scrtRef
 	}
 
+	if transportServer.Spec.TLS != nil && transportServer.Spec.TLS.ClientCertSecret != "" {
+		scrtKey := transportServer.Namespace + "/" + transportServer.Spec.TLS.ClientCertSecret
+
+		scrtRef := lbc.secretStore.GetSecret(scrtKey)
+		if scrtRef.Error != nil {
+			nl.Warnf(lbc.Logger, "Error trying to get the client certificate secret %v for TransportServer %v: %v", scrtKey, transportServer.Name, scrtRef.Error)
+		}
+
+		scrtRefs[scrtKey] = scrtRef
+	}
+
+	for _, u := range transportServer.Spec.Upstreams {
+		if u.TLS.TrustedCertSecret == "" {
+			continue
+		}
+
+		scrtKey := transportServer.Namespace + "/" + u.TLS.TrustedCertSecret
+
+		scrtRef := lbc.secretStore.GetSecret(scrtKey)
+		if scrtRef.Error != nil {
+			nl.Warnf(lbc.Logger, "Error trying to get the trusted certificate secret %v for TransportServer %v: %v", scrtKey, transportServer.Name, scrtRef.Error)
+		}
+
+		scrtRefs[scrtKey] = scrtRef
+	}
+
+	policies, policyErrors := lbc.getPolicies(transportServer.Spec.Policies, transportServer.Namespace)
+	for _, err := range policyErrors {
+		nl.Warnf(lbc.Logger, "Error getting policy for TransportServer %s/%s: %v", transportServer.Namespace, transportServer.Name, err)
+	}
+
 	return &configs.TransportServerEx{
 		ListenerPort:     listenerPort,
 		IPv4:             ipv4,
@@ -258,6 +289,7 @@ func (lbc *LoadBalancerController) createTransportServerEx(transportServer *conf
 		ExternalNameSvcs: externalNameSvcs,
 		DisableIPV6:      disableIPV6,
 		SecretRefs:       scrtRefs,
+		Policies:         createPolicyMap(policies),
 	}
 }
 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/dlclark/regexp2"
 	"github.com/nginx/kubernetes-ingress/internal/configs"
+	ed_controller "github.com/nginx/kubernetes-ingress/internal/externaldns"
 	ap_validation "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/validation"
 	networking "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -27,6 +28,8 @@ const (
 	serverTokensAnnotation                = "nginx.org/server-tokens" // #nosec G101
 	serverSnippetsAnnotation              = "nginx.org/server-snippets"
 	locationSnippetsAnnotation            = "nginx.org/location-snippets"
+	accessLogDestinationAnnotation        = "nginx.org/access-log-destination"
+	accessLogFormatAnnotation             = "nginx.org/access-log-format"
 	proxyConnectTimeoutAnnotation         = "nginx.org/proxy-connect-timeout"
 	proxyReadTimeoutAnnotation            = "nginx.org/proxy-read-timeout"
 	proxySendTimeoutAnnotation            = "nginx.org/proxy-send-timeout"
@@ -71,6 +74,11 @@ const (
 	stickyCookieServicesAnnotation        = "nginx.com/sticky-cookie-services"
 	pathRegexAnnotation                   = "nginx.org/path-regex"
 	useClusterIPAnnotation                = "nginx.org/use-cluster-ip"
+	externalDNSEnableAnnotation           = ed_controller.EnableAnnotation
+	externalDNSRecordTypeAnnotation       = ed_controller.RecordTypeAnnotation
+	externalDNSRecordTTLAnnotation        = ed_controller.RecordTTLAnnotation
+	externalDNSLabelsAnnotation           = ed_controller.LabelsAnnotation
+	externalDNSProviderSpecificAnnotation = ed_controller.ProviderSpecificAnnotation
 )
 
 const (
@@ -151,6 +159,14 @@ var (
 		locationSnippetsAnnotation: {
 			validateSnippetsAnnotation,
 		},
+		accessLogDestinationAnnotation: {
+			validateRequiredAnnotation,
+			validateAccessLogDestinationAnnotation,
+		},
+		accessLogFormatAnnotation: {
+			validateRequiredAnnotation,
+			validateLogFormatNameAnnotation,
+		},
 		proxyConnectTimeoutAnnotation: {
 			validateRequiredAnnotation,
 			validateTimeAnnotation,
@@ -340,6 +356,29 @@ var (
 		useClusterIPAnnotation: {
 			validateBoolAnnotation,
 		},
+		externalDNSEnableAnnotation: {
+			validateRequiredAnnotation,
+			validateBoolAnnotation,
+		},
+		externalDNSRecordTypeAnnotation: {
+			validateRelatedAnnotation(externalDNSEnableAnnotation, validateIsTrue),
+			validateRequiredAnnotation,
+		},
+		externalDNSRecordTTLAnnotation: {
+			validateRelatedAnnotation(externalDNSEnableAnnotation, validateIsTrue),
+			validateRequiredAnnotation,
+			validateUint64Annotation,
+		},
+		externalDNSLabelsAnnotation: {
+			validateRelatedAnnotation(externalDNSEnableAnnotation, validateIsTrue),
+			validateRequiredAnnotation,
+			validateKeyValueListAnnotation,
+		},
+		externalDNSProviderSpecificAnnotation: {
+			validateRelatedAnnotation(externalDNSEnableAnnotation, validateIsTrue),
+			validateRequiredAnnotation,
+			validateKeyValueListAnnotation,
+		},
 	}
 	annotationNames = sortedAnnotationNames(annotationValidations)
 )
@@ -684,6 +723,20 @@ func validateSizeAnnotation(context *annotationValidationContext) field.ErrorLis
 	return nil
 }
 
+func validateAccessLogDestinationAnnotation(context *annotationValidationContext) field.ErrorList {
+	if _, err := configs.ParseAccessLogDestination(context.value); err != nil {
+		return field.ErrorList{field.Invalid(context.fieldPath, context.value, "must be syslog:server=<address>, /dev/stdout, /dev/stderr or a file in /var/log/nginx")}
+	}
+	return nil
+}
+
+func validateLogFormatNameAnnotation(context *annotationValidationContext) field.ErrorList {
+	if _, err := configs.ParseLogFormatName(context.value); err != nil {
+		return field.ErrorList{field.Invalid(context.fieldPath, context.value, "must be a log format name")}
+	}
+	return nil
+}
+
 func validateProxyBuffersAnnotation(context *annotationValidationContext) field.ErrorList {
 	if _, err := configs.ParseProxyBuffersSpec(context.value); err != nil {
 		return field.ErrorList{field.Invalid(context.fieldPath, context.value, "must be a proxy buffer spec")}
@@ -820,6 +873,13 @@ func validateIsBool(v string) error {
 	return err
 }
 
+func validateKeyValueListAnnotation(context *annotationValidationContext) field.ErrorList {
+	if _, err := ed_controller.ParseKeyValueList(context.value); err != nil {
+		return field.ErrorList{field.Invalid(context.fieldPath, context.value, err.Error())}
+	}
+	return nil
+}
+
 func validateIsTrue(v string) error {
 	b, err := configs.ParseBool(v)
 	if err != nil {
@@ -1656,6 +1656,71 @@ func TestValidateNginxIngressAnnotations(t *testing.T) {
 			msg: "invalid nginx.org/proxy-buffers annotation",
 		},
 
+		{
+			annotations: map[string]string{
+				"nginx.org/access-log-destination": "syslog:server=localhost:514",
+			},
+			specServices:          map[string]bool{},
+			isPlus:                false,
+			appProtectEnabled:     false,
+			appProtectDosEnabled:  false,
+			internalRoutesEnabled: false,
+			expectedErrors:        nil,
+			msg:                   "valid nginx.org/access-log-destination annotation, syslog",
+		},
+		{
+			annotations: map[string]string{
+				"nginx.org/access-log-destination": "/var/log/nginx/cafe.log",
+			},
+			specServices:          map[string]bool{},
+			isPlus:                false,
+			appProtectEnabled:     false,
+			appProtectDosEnabled:  false,
+			internalRoutesEnabled: false,
+			expectedErrors:        nil,
+			msg:                   "valid nginx.org/access-log-destination annotation, file",
+		},
+		{
+			annotations: map[string]string{
+				"nginx.org/access-log-destination": "/etc/nginx/nginx.conf",
+			},
+			specServices:          map[string]bool{},
+			isPlus:                false,
+			appProtectEnabled:     false,
+			appProtectDosEnabled:  false,
+			internalRoutesEnabled: false,
+			expectedErrors: []string{
+				`annotations.nginx.org/access-log-destination: Invalid value: "/etc/nginx/nginx.conf": must be syslog:server=<address>, /dev/stdout, /dev/stderr or a file in /var/log/nginx`,
+			},
+			msg: "invalid nginx.org/access-log-destination annotation, file outside of /var/log/nginx",
+		},
+		{
+			annotations: map[string]string{
+				"nginx.org/access-log-format": "json",
+			},
+			specServices:          map[string]bool{},
+			isPlus:                false,
+			appProtectEnabled:     false,
+			appProtectDosEnabled:  false,
+			internalRoutesEnabled: false,
+			expectedErrors:        nil,
+			msg:                   "valid nginx.org/access-log-format annotation",
+		},
+		{
+			annotations: map[string]string{
+				"nginx.org/access-log-format": "json;",
+			},
+			specServices:          map[string]bool{},
+			isPlus:                false,
+			appProtectEnabled:     false,
+			appProtectDosEnabled:  false,
+			internalRoutesEnabled: false,
+			expectedErrors: []string{
+				`annotations.nginx.org/access-log-format: Invalid value: "json;": must be a log format name`,
+			},
+			msg: "invalid nginx.org/access-log-format annotation",
+		},
+
 		{
 			annotations: map[string]string{
 				"nginx.org/proxy-buffer-size": "16k",
@@ -3176,6 +3241,80 @@ func TestValidateNginxIngressAnnotations(t *testing.T) {
 			expectedErrors:        nil,
 			msg:                   "valid nginx.org/use-cluster-ip annotation",
 		},
+		{
+			annotations: map[string]string{
+				"nginx.org/external-dns-enable":            "true",
+				"nginx.org/external-dns-record-type":       "A",
+				"nginx.org/external-dns-record-ttl":        "30",
+				"nginx.org/external-dns-labels":            "team=a,env=prod",
+				"nginx.org/external-dns-provider-specific": "aws/weight=10",
+			},
+			specServices:          map[string]bool{},
+			isPlus:                false,
+			appProtectEnabled:     false,
+			appProtectDosEnabled:  false,
+			internalRoutesEnabled: false,
+			expectedErrors:        nil,
+			msg:                   "valid nginx.org/external-dns annotations",
+		},
+		{
+			annotations: map[string]string{
+				"nginx.org/external-dns-enable": "not_a_boolean",
+			},
+			specServices:          map[string]bool{},
+			isPlus:                false,
+			appProtectEnabled:     false,
+			appProtectDosEnabled:  false,
+			internalRoutesEnabled: false,
+			expectedErrors: []string{
+				`annotations.nginx.org/external-dns-enable: Invalid value: "not_a_boolean": must be a boolean`,
+			},
+			msg: "invalid nginx.org/external-dns-enable annotation",
+		},
+		{
+			annotations: map[string]string{
+				"nginx.org/external-dns-record-ttl": "30",
+			},
+			specServices:          map[string]bool{},
+			isPlus:                false,
+			appProtectEnabled:     false,
+			appProtectDosEnabled:  false,
+			internalRoutesEnabled: false,
+			expectedErrors: []string{
+				`annotations.nginx.org/external-dns-record-ttl: Forbidden: related annotation nginx.org/external-dns-enable: must be set`,
+			},
+			msg: "invalid nginx.org/external-dns-record-ttl annotation, enable annotation is missing",
+		},
+		{
+			annotations: map[string]string{
+				"nginx.org/external-dns-enable":     "true",
+				"nginx.org/external-dns-record-ttl": "-1",
+			},
+			specServices:          map[string]bool{},
+			isPlus:                false,
+			appProtectEnabled:     false,
+			appProtectDosEnabled:  false,
+			internalRoutesEnabled: false,
+			expectedErrors: []string{
+				`annotations.nginx.org/external-dns-record-ttl: Invalid value: "-1": must be a non-negative integer`,
+			},
+			msg: "invalid nginx.org/external-dns-record-ttl annotation",
+		},
+		{
+			annotations: map[string]string{
+				"nginx.org/external-dns-enable": "true",
+				"nginx.org/external-dns-labels": "team=a,env",
+			},
+			specServices:          map[string]bool{},
+			isPlus:                false,
+			appProtectEnabled:     false,
+			appProtectDosEnabled:  false,
+			internalRoutesEnabled: false,
+			expectedErrors: []string{
+				`annotations.nginx.org/external-dns-labels: Invalid value: "team=a,env": invalid key=value pair "env"`,
+			},
+			msg: "invalid nginx.org/external-dns-labels annotation",
+		},
 	}
 
 	for _, test := range tests {
@@ -103,6 +103,13 @@ func (h *Handler) Handle(_ context.Context, r slog.Record) error {
 	buf = append(buf, "]"...)
 	buf = append(buf, " "...)
 	buf = append(buf, r.Message...)
+	r.Attrs(func(a slog.Attr) bool {
+		buf = append(buf, " "...)
+		buf = append(buf, a.Key...)
+		buf = append(buf, "="...)
+		buf = append(buf, a.Value.String()...)
+		return true
+	})
 	buf = append(buf, "\n"...)
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -22,6 +22,18 @@ func TestGlogFormat(t *testing.T) {
 	}
 }
 
+func TestGlogFormatWithAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(New(&buf, nil))
+	l.Info("hello", "reason", "config", "files", []string{"a.conf", "b.conf"})
+	got := buf.String()
+	wantre := `\]\shello reason=config files=\[a.conf b.conf\]\n$`
+	re := regexp.MustCompile(wantre)
+	if !re.MatchString(got) {
+		t.Errorf("\ngot:\n%q\nwant:\n%q", got, wantre)
+	}
+}
+
 func TestGlogLogLevels(t *testing.T) {
 	testCases := []struct {
 		name   string
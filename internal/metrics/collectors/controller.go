@@ -1,8 +1,16 @@
 package collectors
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"time"
 
-var labelNamesController = []string{"type"}
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	labelNamesController = []string{"type"}
+	labelNamesTaskQueue  = []string{"priority"}
+	labelNamesTask       = []string{"kind"}
+)
 
 // ControllerCollector is an interface for the metrics of the Controller
 type ControllerCollector interface {
@@ -10,6 +18,9 @@ type ControllerCollector interface {
 	SetVirtualServers(count int)
 	SetVirtualServerRoutes(count int)
 	SetTransportServers(tlsPassthroughCount, tcpCount, udpCount int)
+	SetTaskQueueDepth(priority string, depth int)
+	ObserveTaskQueueLatency(kind string, latency time.Duration)
+	IncTaskQueueRetries(kind string)
 	Register(registry *prometheus.Registry) error
 }
 
@@ -20,6 +31,9 @@ type ControllerMetricsCollector struct {
 	virtualServersTotal      prometheus.Gauge
 	virtualServerRoutesTotal prometheus.Gauge
 	transportServersTotal    *prometheus.GaugeVec
+	taskQueueDepth           *prometheus.GaugeVec
+	taskQueueLatency         *prometheus.HistogramVec
+	taskQueueRetries         *prometheus.CounterVec
 }
 
 // NewControllerMetricsCollector creates a new ControllerMetricsCollector
@@ -67,12 +81,46 @@ func NewControllerMetricsCollector(crdsEnabled bool, constLabels map[string]stri
 		)
 	}
 
+	taskQueueDepth := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:        "task_queue_depth",
+			Namespace:   metricsNamespace,
+			Help:        "Number of tasks waiting in the task queue of the controller",
+			ConstLabels: constLabels,
+		},
+		labelNamesTaskQueue,
+	)
+
+	taskQueueLatency := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:        "task_queue_latency_seconds",
+			Namespace:   metricsNamespace,
+			Help:        "Time a task waits in the task queue of the controller before it is processed",
+			ConstLabels: constLabels,
+			Buckets:     prometheus.ExponentialBuckets(0.001, 2, 16),
+		},
+		labelNamesTask,
+	)
+
+	taskQueueRetries := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "task_queue_retries_total",
+			Namespace:   metricsNamespace,
+			Help:        "Number of failed tasks requeued in the task queue of the controller",
+			ConstLabels: constLabels,
+		},
+		labelNamesTask,
+	)
+
 	c := &ControllerMetricsCollector{
 		crdsEnabled:              crdsEnabled,
 		ingressesTotal:           ingResTotal,
 		virtualServersTotal:      vsResTotal,
 		virtualServerRoutesTotal: vsrResTotal,
 		transportServersTotal:    tsResTotal,
+		taskQueueDepth:           taskQueueDepth,
+		taskQueueLatency:         taskQueueLatency,
+		taskQueueRetries:         taskQueueRetries,
 	}
 
 	// if we don't set to 0 metrics with the label type, the metrics will not be created initially
@@ -110,9 +158,27 @@ func (cc *ControllerMetricsCollector) SetTransportServers(tlsPassthroughCount, t
 	cc.transportServersTotal.WithLabelValues("udp").Set(float64(udpCount))
 }
 
+// SetTaskQueueDepth sets the number of tasks of a priority waiting in the task queue
+func (cc *ControllerMetricsCollector) SetTaskQueueDepth(priority string, depth int) {
+	cc.taskQueueDepth.WithLabelValues(priority).Set(float64(depth))
+}
+
+// ObserveTaskQueueLatency observes the time a task of a kind waited in the task queue
+func (cc *ControllerMetricsCollector) ObserveTaskQueueLatency(kind string, latency time.Duration) {
+	cc.taskQueueLatency.WithLabelValues(kind).Observe(latency.Seconds())
+}
+
+// IncTaskQueueRetries increments the counter of the requeued tasks of a kind
+func (cc *ControllerMetricsCollector) IncTaskQueueRetries(kind string) {
+	cc.taskQueueRetries.WithLabelValues(kind).Inc()
+}
+
 // Describe implements prometheus.Collector interface Describe method
 func (cc *ControllerMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
 	cc.ingressesTotal.Describe(ch)
+	cc.taskQueueDepth.Describe(ch)
+	cc.taskQueueLatency.Describe(ch)
+	cc.taskQueueRetries.Describe(ch)
 	if cc.crdsEnabled {
 		cc.virtualServersTotal.Describe(ch)
 		cc.virtualServerRoutesTotal.Describe(ch)
@@ -123,6 +189,9 @@ func (cc *ControllerMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
 // Collect implements the prometheus.Collector interface Collect method
 func (cc *ControllerMetricsCollector) Collect(ch chan<- prometheus.Metric) {
 	cc.ingressesTotal.Collect(ch)
+	cc.taskQueueDepth.Collect(ch)
+	cc.taskQueueLatency.Collect(ch)
+	cc.taskQueueRetries.Collect(ch)
 	if cc.crdsEnabled {
 		cc.virtualServersTotal.Collect(ch)
 		cc.virtualServerRoutesTotal.Collect(ch)
@@ -157,3 +226,12 @@ func (cc *ControllerFakeCollector) SetVirtualServerRoutes(_ int) {}
 
 // SetTransportServers implements a fake SetTransportServers
 func (cc *ControllerFakeCollector) SetTransportServers(int, int, int) {}
+
+// SetTaskQueueDepth implements a fake SetTaskQueueDepth
+func (cc *ControllerFakeCollector) SetTaskQueueDepth(_ string, _ int) {}
+
+// ObserveTaskQueueLatency implements a fake ObserveTaskQueueLatency
+func (cc *ControllerFakeCollector) ObserveTaskQueueLatency(_ string, _ time.Duration) {}
+
+// IncTaskQueueRetries implements a fake IncTaskQueueRetries
+func (cc *ControllerFakeCollector) IncTaskQueueRetries(_ string) {}
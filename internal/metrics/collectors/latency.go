@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	nl "github.com/nginx/kubernetes-ingress/internal/logger"
 	"github.com/prometheus/client_golang/prometheus"
@@ -51,9 +53,37 @@ type LatencyCollector interface {
 	UpdateUpstreamServerPeerLabels(map[string][]string)
 	DeleteUpstreamServerPeerLabels([]string)
 	DeleteMetrics([]string)
+	UpstreamStats(string) UpstreamStats
+	UpstreamServerPeerStats(string) UpstreamServerPeerStats
 	Register(*prometheus.Registry) error
 }
 
+// UpstreamStats are the cumulative statistics of the responses of the servers of an upstream.
+type UpstreamStats struct {
+	Responses       uint64
+	ServerErrors    uint64
+	ResponseTimeSum time.Duration
+}
+
+// UpstreamServerPeerStats are the statistics of the responses of an upstream server peer.
+type UpstreamServerPeerStats struct {
+	// Failures are the attempts that NGINX can consider unsuccessful since the last attempt that NGINX always
+	// considers successful, oldest first. Whether an attempt is unsuccessful depends on the proxy_next_upstream
+	// directive, so the attempts with the 5xx and 429 status codes are kept. At most maxPeerFailures are kept.
+	Failures        []UpstreamServerPeerFailure
+	Responses       uint64
+	ResponseTimeSum time.Duration
+}
+
+// UpstreamServerPeerFailure is a possibly unsuccessful attempt to get a response from an upstream server peer.
+type UpstreamServerPeerFailure struct {
+	Code string
+	Time time.Time
+}
+
+// maxPeerFailures is the maximum number of the failures kept for an upstream server peer.
+const maxPeerFailures = 100
+
 // metricsPublishedMap is a map of upstream server peers (upstream/server) to a metricsSet.
 // This map is used to keep track of all the metrics published for each upstream server peer,
 // so that the metrics can be deleted when the upstream server peers are deleted.
@@ -75,6 +105,9 @@ type LatencyMetricsCollector struct {
 	metricsPublishedMap          metricsPublishedMap
 	metricsPublishedMutex        sync.Mutex
 	variableLabelsMutex          sync.RWMutex
+	upstreamStats                map[string]UpstreamStats
+	upstreamServerPeerStats      map[string]UpstreamServerPeerStats
+	upstreamStatsMutex           sync.RWMutex
 	logger                       *slog.Logger
 }
 
@@ -98,6 +131,8 @@ func NewLatencyMetricsCollector(
 		upstreamServerLabels:         make(map[string][]string),
 		upstreamServerPeerLabels:     make(map[string][]string),
 		metricsPublishedMap:          make(metricsPublishedMap),
+		upstreamStats:                make(map[string]UpstreamStats),
+		upstreamServerPeerStats:      make(map[string]UpstreamServerPeerStats),
 		upstreamServerLabelNames:     upstreamServerLabelNames,
 		upstreamServerPeerLabelNames: upstreamServerPeerLabelNames,
 		logger:                       nl.LoggerFromContext(ctx),
@@ -120,6 +155,12 @@ func (l *LatencyMetricsCollector) DeleteUpstreamServerPeerLabels(peers []string)
 		delete(l.upstreamServerPeerLabels, k)
 	}
 	l.variableLabelsMutex.Unlock()
+
+	l.upstreamStatsMutex.Lock()
+	for _, k := range peers {
+		delete(l.upstreamServerPeerStats, k)
+	}
+	l.upstreamStatsMutex.Unlock()
 }
 
 // UpdateUpstreamServerLabels updates the upstream server label map
@@ -138,6 +179,70 @@ func (l *LatencyMetricsCollector) DeleteUpstreamServerLabels(upstreamNames []str
 		delete(l.upstreamServerLabels, k)
 	}
 	l.variableLabelsMutex.Unlock()
+
+	l.upstreamStatsMutex.Lock()
+	for _, k := range upstreamNames {
+		delete(l.upstreamStats, k)
+	}
+	l.upstreamStatsMutex.Unlock()
+}
+
+// UpstreamStats returns the statistics of the responses of the upstream recorded since the upstream was added.
+func (l *LatencyMetricsCollector) UpstreamStats(upstreamName string) UpstreamStats {
+	l.upstreamStatsMutex.RLock()
+	defer l.upstreamStatsMutex.RUnlock()
+	return l.upstreamStats[upstreamName]
+}
+
+// UpstreamServerPeerStats returns the statistics of the responses of the upstream server peer.
+// The peer is identified by the upstream name and the server address in the format upstream/server.
+func (l *LatencyMetricsCollector) UpstreamServerPeerStats(peer string) UpstreamServerPeerStats {
+	l.upstreamStatsMutex.RLock()
+	defer l.upstreamStatsMutex.RUnlock()
+	stats := l.upstreamServerPeerStats[peer]
+	stats.Failures = slices.Clone(stats.Failures)
+	return stats
+}
+
+func (l *LatencyMetricsCollector) updateUpstreamStats(lm latencyMetric) {
+	l.upstreamStatsMutex.Lock()
+	defer l.upstreamStatsMutex.Unlock()
+
+	failed := strings.HasPrefix(lm.Code, "5")
+	responseTime := time.Duration(lm.Latency * float64(time.Second))
+
+	stats := l.upstreamStats[lm.Upstream]
+	stats.Responses++
+	if failed {
+		stats.ServerErrors++
+	}
+	stats.ResponseTimeSum += responseTime
+	l.upstreamStats[lm.Upstream] = stats
+
+	peer := fmt.Sprintf("%s/%s", lm.Upstream, lm.Server)
+	peerStats := l.upstreamServerPeerStats[peer]
+	peerStats.Responses++
+	peerStats.ResponseTimeSum += responseTime
+	l.upstreamServerPeerStats[peer] = peerStats
+
+	now := time.Now()
+	for _, a := range lm.Attempts {
+		l.updateUpstreamServerPeerFailures(fmt.Sprintf("%s/%s", lm.Upstream, a.Server), a.Code, now)
+	}
+}
+
+// updateUpstreamServerPeerFailures records an attempt of NGINX to get a response from the upstream server peer.
+func (l *LatencyMetricsCollector) updateUpstreamServerPeerFailures(peer string, code string, now time.Time) {
+	peerStats := l.upstreamServerPeerStats[peer]
+	if !strings.HasPrefix(code, "5") && code != "429" {
+		peerStats.Failures = nil
+	} else {
+		peerStats.Failures = append(peerStats.Failures, UpstreamServerPeerFailure{Code: code, Time: now})
+		if len(peerStats.Failures) > maxPeerFailures {
+			peerStats.Failures = peerStats.Failures[len(peerStats.Failures)-maxPeerFailures:]
+		}
+	}
+	l.upstreamServerPeerStats[peer] = peerStats
 }
 
 // DeleteMetrics deletes all metrics published associated with the given upstream server peer names.
@@ -197,6 +302,8 @@ func (l *LatencyMetricsCollector) RecordLatency(syslogMsg string) {
 		return
 	}
 
+	l.updateUpstreamStats(lm)
+
 	labelValues, err := l.createLatencyLabelValues(lm)
 	if err != nil {
 		nl.Errorf(l.logger, "cannot record latency for upstream %s and server %s: %v", lm.Upstream, lm.Server, err)
@@ -259,6 +366,14 @@ type latencyMetric struct {
 	Server   string
 	Code     string
 	Latency  float64
+	// Attempts are all the attempts of NGINX to get the response, including the last one.
+	Attempts []upstreamAttempt
+}
+
+// upstreamAttempt is an attempt of NGINX to get a response from an upstream server.
+type upstreamAttempt struct {
+	Server string
+	Code   string
 }
 
 func parseMessage(msg string) (latencyMetric, error) {
@@ -286,11 +401,30 @@ func parseMessage(msg string) (latencyMetric, error) {
 		Server:   server,
 		Code:     code,
 		Latency:  latency,
+		Attempts: parseAttempts(sm.UpstreamAddr, sm.UpstreamStatus),
 	}
 
 	return lm, nil
 }
 
+// parseAttempts returns the attempts of NGINX to get a response from the upstream servers.
+// It returns nil if the number of the addresses doesn't match the number of the status codes.
+func parseAttempts(addrs string, codes string) []upstreamAttempt {
+	addrParts := strings.Split(addrs, ",")
+	codeParts := strings.Split(codes, ",")
+	if len(addrParts) != len(codeParts) {
+		return nil
+	}
+	attempts := make([]upstreamAttempt, 0, len(addrParts))
+	for i := range addrParts {
+		attempts = append(attempts, upstreamAttempt{
+			Server: strings.TrimSpace(addrParts[i]),
+			Code:   strings.TrimSpace(codeParts[i]),
+		})
+	}
+	return attempts
+}
+
 // parseMultipartResponse checks if the input string contains commas.
 // If it does it returns the last item of the list, otherwise it returns input.
 func parseMultipartResponse(input string) string {
@@ -329,3 +463,11 @@ func (l *LatencyFakeCollector) Register(_ *prometheus.Registry) error { return n
 
 // RecordLatency implements a fake RecordLatency
 func (l *LatencyFakeCollector) RecordLatency(_ string) {}
+
+// UpstreamStats implements a fake UpstreamStats
+func (l *LatencyFakeCollector) UpstreamStats(_ string) UpstreamStats { return UpstreamStats{} }
+
+// UpstreamServerPeerStats implements a fake UpstreamServerPeerStats
+func (l *LatencyFakeCollector) UpstreamServerPeerStats(_ string) UpstreamServerPeerStats {
+	return UpstreamServerPeerStats{}
+}
@@ -3,6 +3,7 @@ package collectors
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func newTestLatencyMetricsCollector() *LatencyMetricsCollector {
@@ -10,6 +11,8 @@ func newTestLatencyMetricsCollector() *LatencyMetricsCollector {
 		upstreamServerLabels:         make(map[string][]string),
 		upstreamServerPeerLabels:     make(map[string][]string),
 		metricsPublishedMap:          make(metricsPublishedMap),
+		upstreamStats:                make(map[string]UpstreamStats),
+		upstreamServerPeerStats:      make(map[string]UpstreamServerPeerStats),
 		upstreamServerLabelNames:     []string{"service", "resource_type", "resource_name", "resource_namespace"},
 		upstreamServerPeerLabelNames: []string{"pod_name"},
 	}
@@ -30,6 +33,7 @@ func TestParseMessageWithValidInputs(t *testing.T) {
 				Server:   "10.0.0.1",
 				Latency:  0.003,
 				Code:     "200",
+				Attempts: []upstreamAttempt{{Server: "10.0.0.1", Code: "200"}},
 			},
 		},
 		{
@@ -40,6 +44,11 @@ func TestParseMessageWithValidInputs(t *testing.T) {
 				Server:   "127.0.0.1:8001",
 				Latency:  0.1,
 				Code:     "200",
+				Attempts: []upstreamAttempt{
+					{Server: "127.0.0.1:6001", Code: "500"},
+					{Server: "127.0.0.1:6002", Code: "500"},
+					{Server: "127.0.0.1:8001", Code: "200"},
+				},
 			},
 		},
 		{
@@ -73,7 +82,7 @@ func TestParseMessageWithValidInputs(t *testing.T) {
 			if err != nil {
 				t.Fatalf("parseMessage returned an unexpected error: %v", err)
 			}
-			if actual != test.expected {
+			if !reflect.DeepEqual(actual, test.expected) {
 				t.Errorf("parseMessage returned: %+v, expected: %+v", actual, test.expected)
 			}
 		}
@@ -232,6 +241,83 @@ func TestMetricsPublished(t *testing.T) {
 	}
 }
 
+func TestUpstreamStats(t *testing.T) {
+	t.Parallel()
+	collector := newTestLatencyMetricsCollector()
+
+	collector.updateUpstreamStats(latencyMetric{Upstream: "upstream-1", Server: "10.0.0.1:80", Code: "200", Latency: 0.1})
+	collector.updateUpstreamStats(latencyMetric{Upstream: "upstream-1", Server: "10.0.0.2:80", Code: "502", Latency: 0.3})
+	collector.updateUpstreamStats(latencyMetric{Upstream: "upstream-2", Server: "10.0.0.3:80", Code: "404", Latency: 0.2})
+
+	expected := UpstreamStats{Responses: 2, ServerErrors: 1, ResponseTimeSum: 400 * time.Millisecond}
+	if stats := collector.UpstreamStats("upstream-1"); stats != expected {
+		t.Errorf("UpstreamStats() returned %+v for upstream-1, expected %+v", stats, expected)
+	}
+
+	collector.DeleteUpstreamServerLabels([]string{"upstream-1"})
+	if stats := collector.UpstreamStats("upstream-1"); stats != (UpstreamStats{}) {
+		t.Errorf("UpstreamStats() returned %+v for a deleted upstream, expected no stats", stats)
+	}
+	if stats := collector.UpstreamStats("upstream-2"); stats.Responses != 1 || stats.ServerErrors != 0 {
+		t.Errorf("UpstreamStats() returned %+v for upstream-2, expected 1 response without errors", stats)
+	}
+}
+
+func TestUpstreamServerPeerStats(t *testing.T) {
+	t.Parallel()
+	collector := newTestLatencyMetricsCollector()
+
+	collector.updateUpstreamStats(latencyMetric{
+		Upstream: "upstream-1", Server: "10.0.0.1:80", Code: "504", Latency: 0.1,
+		Attempts: []upstreamAttempt{{Server: "10.0.0.1:80", Code: "502"}},
+	})
+	collector.updateUpstreamStats(latencyMetric{
+		Upstream: "upstream-1", Server: "10.0.0.2:80", Code: "200", Latency: 0.3,
+		Attempts: []upstreamAttempt{{Server: "10.0.0.1:80", Code: "504"}, {Server: "10.0.0.2:80", Code: "200"}},
+	})
+	collector.updateUpstreamStats(latencyMetric{
+		Upstream: "upstream-1", Server: "10.0.0.2:80", Code: "502", Latency: 0.1,
+		Attempts: []upstreamAttempt{{Server: "10.0.0.2:80", Code: "502"}},
+	})
+	collector.updateUpstreamStats(latencyMetric{
+		Upstream: "upstream-1", Server: "10.0.0.2:80", Code: "404", Latency: 0.1,
+		Attempts: []upstreamAttempt{{Server: "10.0.0.2:80", Code: "404"}},
+	})
+
+	stats := collector.UpstreamServerPeerStats("upstream-1/10.0.0.1:80")
+	if len(stats.Failures) != 2 || stats.Failures[0].Code != "502" || stats.Failures[1].Code != "504" {
+		t.Errorf("UpstreamServerPeerStats() returned %+v for a failing peer, expected the failures of all the attempts", stats)
+	}
+	stats = collector.UpstreamServerPeerStats("upstream-1/10.0.0.2:80")
+	if len(stats.Failures) != 0 {
+		t.Errorf("UpstreamServerPeerStats() returned %+v for a peer with a successful response, expected the failures to be reset", stats)
+	}
+	if stats.Responses != 3 || stats.ResponseTimeSum != 500*time.Millisecond {
+		t.Errorf("UpstreamServerPeerStats() returned %+v, expected 3 responses in 500ms", stats)
+	}
+
+	collector.DeleteUpstreamServerPeerLabels([]string{"upstream-1/10.0.0.1:80"})
+	if stats := collector.UpstreamServerPeerStats("upstream-1/10.0.0.1:80"); !reflect.DeepEqual(stats, UpstreamServerPeerStats{}) {
+		t.Errorf("UpstreamServerPeerStats() returned %+v for a deleted peer, expected no stats", stats)
+	}
+}
+
+func TestUpstreamServerPeerStatsKeepsMaxFailures(t *testing.T) {
+	t.Parallel()
+	collector := newTestLatencyMetricsCollector()
+
+	for range maxPeerFailures + 1 {
+		collector.updateUpstreamStats(latencyMetric{
+			Upstream: "upstream-1", Server: "10.0.0.1:80", Code: "502",
+			Attempts: []upstreamAttempt{{Server: "10.0.0.1:80", Code: "502"}},
+		})
+	}
+
+	if stats := collector.UpstreamServerPeerStats("upstream-1/10.0.0.1:80"); len(stats.Failures) != maxPeerFailures {
+		t.Errorf("UpstreamServerPeerStats() returned %d failures, expected %d", len(stats.Failures), maxPeerFailures)
+	}
+}
+
 func contains(x []string, y [][]string) bool {
 	for _, l := range y {
 		if reflect.DeepEqual(x, l) {
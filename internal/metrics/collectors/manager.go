@@ -8,12 +8,15 @@ import (
 
 // ManagerCollector is an interface for the metrics of the Nginx Manager
 type ManagerCollector interface {
-	IncNginxReloadCount(isEndPointUpdate bool)
+	IncNginxReloadCount(reason string)
 	IncNginxReloadErrors()
 	UpdateLastReloadTime(ms time.Duration)
 	Register(registry *prometheus.Registry) error
 }
 
+// reloadReasons are the reasons of NGINX reloads the reloads are counted for.
+var reloadReasons = []string{"endpoints", "secret", "config", "policy", "other"}
+
 // LocalManagerMetricsCollector implements NginxManagerCollector interface and prometheus.Collector interface
 type LocalManagerMetricsCollector struct {
 	// Metrics
@@ -60,20 +63,15 @@ func NewLocalManagerMetricsCollector(constLabels map[string]string) *LocalManage
 			},
 		),
 	}
-	nc.reloadsTotal.WithLabelValues("other")
-	nc.reloadsTotal.WithLabelValues("endpoints")
+	for _, reason := range reloadReasons {
+		nc.reloadsTotal.WithLabelValues(reason)
+	}
 	return nc
 }
 
-// IncNginxReloadCount increments the counter of successful NGINX reloads and sets the last reload status to true
-func (nc *LocalManagerMetricsCollector) IncNginxReloadCount(isEndPointUpdate bool) {
-	var label string
-	if isEndPointUpdate {
-		label = "endpoints"
-	} else {
-		label = "other"
-	}
-	nc.reloadsTotal.WithLabelValues(label).Inc()
+// IncNginxReloadCount increments the counter of successful NGINX reloads for the reason and sets the last reload status to true
+func (nc *LocalManagerMetricsCollector) IncNginxReloadCount(reason string) {
+	nc.reloadsTotal.WithLabelValues(reason).Inc()
 	nc.updateLastReloadStatus(true)
 }
 
@@ -130,7 +128,7 @@ func NewManagerFakeCollector() *ManagerFakeCollector {
 func (nc *ManagerFakeCollector) Register(_ *prometheus.Registry) error { return nil }
 
 // IncNginxReloadCount implements a fake IncNginxReloadCount
-func (nc *ManagerFakeCollector) IncNginxReloadCount(_ bool) {}
+func (nc *ManagerFakeCollector) IncNginxReloadCount(_ string) {}
 
 // IncNginxReloadErrors implements a fake IncNginxReloadErrors
 func (nc *ManagerFakeCollector) IncNginxReloadErrors() {}
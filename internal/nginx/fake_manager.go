@@ -116,9 +116,26 @@ func (fm *FakeManager) Start(_ chan error) {
 	nl.Debug(fm.logger, "Starting nginx")
 }
 
+// TestConfig provides a fake implementation of TestConfig.
+func (fm *FakeManager) TestConfig() error {
+	nl.Debug(fm.logger, "Testing nginx config")
+	return nil
+}
+
+// RollbackConfig provides a fake implementation of RollbackConfig.
+func (fm *FakeManager) RollbackConfig(filename string) bool {
+	nl.Debugf(fm.logger, "Rolling back config %v", filename)
+	return false
+}
+
+// RollbackAllConfigs provides a fake implementation of RollbackAllConfigs.
+func (fm *FakeManager) RollbackAllConfigs() {
+	nl.Debug(fm.logger, "Rolling back all configs")
+}
+
 // Reload provides a fake implementation of Reload.
-func (fm *FakeManager) Reload(_ bool) error {
-	nl.Debugf(fm.logger, "Reloading nginx")
+func (fm *FakeManager) Reload(reason ReloadReason, _ []string) error {
+	nl.Debugf(fm.logger, "Reloading nginx for %v update", reason)
 	return nil
 }
 
@@ -128,7 +145,7 @@ func (fm *FakeManager) Quit() {
 }
 
 // UpdateConfigVersionFile provides a fake implementation of UpdateConfigVersionFile.
-func (fm *FakeManager) UpdateConfigVersionFile(_ bool) {
+func (fm *FakeManager) UpdateConfigVersionFile(_ bool, _ bool) {
 	nl.Debugf(fm.logger, "Writing config version")
 }
 
@@ -159,6 +176,10 @@ func (fm *FakeManager) CreateOpenTracingTracerConfig(_ string) error {
 func (*FakeManager) SetOpenTracing(_ bool) {
 }
 
+// SetOtelTracing creates a fake implementation of SetOtelTracing.
+func (*FakeManager) SetOtelTracing(_ bool) {
+}
+
 // AppProtectPluginStart is a fake implementation AppProtectPluginStart
 func (fm *FakeManager) AppProtectPluginStart(_ chan error, _ string) {
 	nl.Debugf(fm.logger, "Starting FakeAppProtectPlugin")
@@ -19,13 +19,27 @@ import (
 	"github.com/nginx/kubernetes-ingress/internal/metrics/collectors"
 
 	"github.com/nginx/nginx-plus-go-client/v2/client"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// ReloadReason tells why NGINX is reloaded.
+type ReloadReason string
+
+const (
+	// ReloadForEndpointsUpdate means that a reload is caused by an endpoints update.
+	ReloadForEndpointsUpdate ReloadReason = "endpoints"
+	// ReloadForSecretUpdate means that a reload is caused by a Secret update.
+	ReloadForSecretUpdate ReloadReason = "secret"
+	// ReloadForConfigUpdate means that a reload is caused by an update of the configuration of a resource(s) or of the ConfigMap.
+	ReloadForConfigUpdate ReloadReason = "config"
+	// ReloadForPolicyUpdate means that a reload is caused by a Policy or an App Protect resource update.
+	ReloadForPolicyUpdate ReloadReason = "policy"
+	// ReloadForOtherUpdate means that a reload is caused by any other update.
+	ReloadForOtherUpdate ReloadReason = "other"
 )
 
 const (
-	// ReloadForEndpointsUpdate means that is caused by an endpoints update.
-	ReloadForEndpointsUpdate = true
-	// ReloadForOtherUpdate means that a reload is caused by an update for a resource(s) other than endpoints.
-	ReloadForOtherUpdate = false
 	// ReadWriteOnlyFileMode defines the default filemode for files with Secrets.
 	ReadWriteOnlyFileMode = 0o600
 	// JWKSecretFileMode defines the default filemode for files with JWK Secrets.
@@ -83,13 +97,17 @@ type Manager interface {
 	CreateOpenTracingTracerConfig(content string) error
 	Start(done chan error)
 	Version() Version
-	Reload(isEndpointsUpdate bool) error
+	TestConfig() error
+	RollbackConfig(filename string) bool
+	RollbackAllConfigs()
+	Reload(reason ReloadReason, resources []string) error
 	Quit()
-	UpdateConfigVersionFile(openTracing bool)
+	UpdateConfigVersionFile(openTracing bool, otelTracing bool)
 	SetPlusClients(plusClient *client.NginxClient, plusConfigVersionCheckClient *http.Client)
 	UpdateServersInPlus(upstream string, servers []string, config ServerConfig) error
 	UpdateStreamServersInPlus(upstream string, servers []string) error
 	SetOpenTracing(openTracing bool)
+	SetOtelTracing(otelTracing bool)
 	AppProtectPluginStart(appDone chan error, logLevel string)
 	AppProtectPluginQuit()
 	AppProtectDosAgentStart(apdaDone chan error, debug bool, maxDaemon int, maxWorkers int, memory int)
@@ -105,6 +123,9 @@ type Manager interface {
 // LocalManager updates NGINX configuration, starts, reloads and quits NGINX, updates License Reporting file
 // updates NGINX Plus upstream servers. It assumes that NGINX is running in the same container.
 type LocalManager struct {
+	confPath                     string
+	stagingPath                  string
+	pendingPath                  string
 	confdPath                    string
 	streamConfdPath              string
 	secretsPath                  string
@@ -123,22 +144,32 @@ type LocalManager struct {
 	licenseReporter              *license_reporting.LicenseReporter
 	licenseReporterCancel        context.CancelFunc
 	OpenTracing                  bool
+	OtelTracing                  bool
 	appProtectPluginPid          int
 	appProtectDosAgentPid        int
 	agentPid                     int
 	logger                       *slog.Logger
 	nginxPlus                    bool
+	configTest                   bool
+	configChanges                *configChanges
+	eventRecorder                record.EventRecorder
+	eventObject                  runtime.Object
 }
 
-// NewLocalManager creates a LocalManager.
-func NewLocalManager(ctx context.Context, confPath string, debug bool, mc collectors.ManagerCollector, lr *license_reporting.LicenseReporter, timeout time.Duration, nginxPlus bool) *LocalManager {
+// NewLocalManager creates a LocalManager. Every reload is reported with an event for eventObject.
+// If configTest is true, the configuration files are tested with nginx -t before every reload.
+func NewLocalManager(ctx context.Context, confPath string, debug bool, mc collectors.ManagerCollector, lr *license_reporting.LicenseReporter, timeout time.Duration, nginxPlus bool, configTest bool, eventRecorder record.EventRecorder, eventObject runtime.Object) *LocalManager {
 	l := nl.LoggerFromContext(ctx)
 	verifyConfigGenerator, err := newVerifyConfigGenerator()
 	if err != nil {
 		nl.Fatalf(l, "error instantiating a verifyConfigGenerator: %v", err)
 	}
 
+	confPath = filepath.Clean(confPath)
 	manager := LocalManager{
+		confPath:                    confPath,
+		stagingPath:                 path.Join(confPath, stagingDirName),
+		pendingPath:                 path.Join(confPath, pendingDirName),
 		confdPath:                   path.Join(confPath, "conf.d"),
 		streamConfdPath:             path.Join(confPath, "stream-conf.d"),
 		secretsPath:                 path.Join(confPath, "secrets"),
@@ -154,7 +185,11 @@ func NewLocalManager(ctx context.Context, confPath string, debug bool, mc collec
 		metricsCollector:            mc,
 		licenseReporter:             lr,
 		nginxPlus:                   nginxPlus,
+		configTest:                  configTest,
 		logger:                      l,
+		configChanges:               newConfigChanges(),
+		eventRecorder:               eventRecorder,
+		eventObject:                 eventObject,
 	}
 
 	return &manager
@@ -165,42 +200,60 @@ func (lm *LocalManager) CreateMainConfig(content []byte) bool {
 	nl.Debugf(lm.logger, "Writing main config to %v", lm.mainConfFilename)
 	nl.Debug(lm.logger, string(content))
 
-	configChanged := configContentsChanged(lm.mainConfFilename, content)
-	err := createFileAndWrite(lm.mainConfFilename, content)
+	previous, configChanged := lm.configContentsChanged(lm.mainConfFilename, content)
+	if !configChanged {
+		return false
+	}
+	err := lm.writeConfig(lm.mainConfFilename, content)
 	if err != nil {
 		nl.Fatalf(lm.logger, "Failed to write main config: %v", err)
 	}
-	return configChanged
+	lm.addConfigChange(lm.mainConfFilename, previous, content)
+	return true
 }
 
 // CreateConfig creates a configuration file. If the file already exists, it will be overridden.
 func (lm *LocalManager) CreateConfig(name string, content []byte) bool {
-	return createConfig(lm.logger, lm.getFilenameForConfig(name), content)
+	return lm.createConfig(lm.getFilenameForConfig(name), content)
 }
 
-func createConfig(l *slog.Logger, filename string, content []byte) bool {
-	nl.Debugf(l, "Writing config to %v", filename)
-	nl.Debug(l, string(content))
+func (lm *LocalManager) createConfig(filename string, content []byte) bool {
+	nl.Debugf(lm.logger, "Writing config to %v", filename)
+	nl.Debug(lm.logger, string(content))
 
-	configChanged := configContentsChanged(filename, content)
-	err := createFileAndWrite(filename, content)
+	previous, configChanged := lm.configContentsChanged(filename, content)
+	if !configChanged {
+		return false
+	}
+	err := lm.writeConfig(filename, content)
 	if err != nil {
-		nl.Fatalf(l, "Failed to write config to %v: %v", filename, err)
+		nl.Fatalf(lm.logger, "Failed to write config to %v: %v", filename, err)
 	}
-	return configChanged
+	lm.addConfigChange(filename, previous, content)
+	return true
 }
 
 // DeleteConfig deletes the configuration file from the conf.d folder.
 func (lm *LocalManager) DeleteConfig(name string) {
-	deleteConfig(lm.logger, lm.getFilenameForConfig(name))
+	lm.deleteConfig(lm.getFilenameForConfig(name))
 }
 
-func deleteConfig(l *slog.Logger, filename string) {
-	nl.Infof(l, "Deleting config from %v", filename)
+func (lm *LocalManager) deleteConfig(filename string) {
+	nl.Infof(lm.logger, "Deleting config from %v", filename)
 
-	if err := os.Remove(filename); err != nil {
-		nl.Warnf(l, "Failed to delete config from %v: %v", filename, err)
+	previous, exists := lm.readConfig(filename)
+	if !exists {
+		nl.Warnf(lm.logger, "Failed to delete config from %v: the file doesn't exist", filename)
+		return
 	}
+
+	if !lm.configTest {
+		if err := os.Remove(filename); err != nil {
+			nl.Warnf(lm.logger, "Failed to delete config from %v: %v", filename, err)
+			return
+		}
+	}
+	lm.addConfigChange(filename, previous, nil)
 }
 
 func (lm *LocalManager) getFilenameForConfig(name string) string {
@@ -210,12 +263,12 @@ func (lm *LocalManager) getFilenameForConfig(name string) string {
 // CreateStreamConfig creates a configuration file for stream module.
 // If the file already exists, it will be overridden.
 func (lm *LocalManager) CreateStreamConfig(name string, content []byte) bool {
-	return createConfig(lm.logger, lm.getFilenameForStreamConfig(name), content)
+	return lm.createConfig(lm.getFilenameForStreamConfig(name), content)
 }
 
 // DeleteStreamConfig deletes the configuration file from the stream-conf.d folder.
 func (lm *LocalManager) DeleteStreamConfig(name string) {
-	deleteConfig(lm.logger, lm.getFilenameForStreamConfig(name))
+	lm.deleteConfig(lm.getFilenameForStreamConfig(name))
 }
 
 func (lm *LocalManager) getFilenameForStreamConfig(name string) string {
@@ -227,7 +280,7 @@ func (lm *LocalManager) getFilenameForStreamConfig(name string) string {
 // If the file already exists, it will be overridden.
 func (lm *LocalManager) CreateTLSPassthroughHostsConfig(content []byte) bool {
 	nl.Debugf(lm.logger, "Writing TLS Passthrough Hosts config file to %v", lm.tlsPassthroughHostsFilename)
-	return createConfig(lm.logger, lm.tlsPassthroughHostsFilename, content)
+	return lm.createConfig(lm.tlsPassthroughHostsFilename, content)
 }
 
 // CreateSecret creates a secret file with the specified name, content and mode. If the file already exists,
@@ -310,6 +363,8 @@ func (lm *LocalManager) Start(done chan error) {
 
 	nl.Debug(lm.logger, "Starting nginx")
 
+	lm.applyConfigChanges(lm.configChanges.take())
+
 	binaryFilename := getBinaryFileName(lm.debug)
 	cmd := exec.Command(binaryFilename, "-e", "stderr") // #nosec G204
 	cmd.Stdout = os.Stdout
@@ -327,31 +382,40 @@ func (lm *LocalManager) Start(done chan error) {
 	}
 }
 
-// Reload reloads NGINX.
-func (lm *LocalManager) Reload(isEndpointsUpdate bool) error {
+// Reload reloads NGINX. The reload is reported with the configuration files changed since the previous reload
+// and the resources that caused it.
+func (lm *LocalManager) Reload(reason ReloadReason, resources []string) error {
 	// write a new config version
 	lm.configVersion++
-	lm.UpdateConfigVersionFile(lm.OpenTracing)
+	lm.UpdateConfigVersionFile(lm.OpenTracing, lm.OtelTracing)
 
 	nl.Debugf(lm.logger, "Reloading nginx with configVersion: %v", lm.configVersion)
 
+	changes := lm.configChanges.take()
+	lm.applyConfigChanges(changes)
+
 	t1 := time.Now()
 
 	binaryFilename := getBinaryFileName(lm.debug)
 	if err := shellOut(lm.logger, fmt.Sprintf("%v -s %v -e stderr", binaryFilename, "reload")); err != nil {
 		lm.metricsCollector.IncNginxReloadErrors()
-		return fmt.Errorf("nginx reload failed: %w", err)
+		err = fmt.Errorf("nginx reload failed: %w", err)
+		lm.reportReload(reason, resources, changes, err)
+		return err
 	}
 	err := lm.verifyClient.WaitForCorrectVersion(lm.logger, lm.configVersion)
 	if err != nil {
 		lm.metricsCollector.IncNginxReloadErrors()
-		return fmt.Errorf("could not get newest config version: %w", err)
+		err = fmt.Errorf("could not get newest config version: %w", err)
+		lm.reportReload(reason, resources, changes, err)
+		return err
 	}
 
-	lm.metricsCollector.IncNginxReloadCount(isEndpointsUpdate)
+	lm.metricsCollector.IncNginxReloadCount(string(reason))
 
 	t2 := time.Now()
 	lm.metricsCollector.UpdateLastReloadTime(t2.Sub(t1))
+	lm.reportReload(reason, resources, changes, nil)
 	return nil
 }
 
@@ -386,8 +450,8 @@ func (lm *LocalManager) Version() Version {
 }
 
 // UpdateConfigVersionFile writes the config version file.
-func (lm *LocalManager) UpdateConfigVersionFile(openTracing bool) {
-	cfg, err := lm.verifyConfigGenerator.GenerateVersionConfig(lm.configVersion, openTracing)
+func (lm *LocalManager) UpdateConfigVersionFile(openTracing bool, otelTracing bool) {
+	cfg, err := lm.verifyConfigGenerator.GenerateVersionConfig(lm.configVersion, openTracing, otelTracing)
 	if err != nil {
 		nl.Fatalf(lm.logger, "Error generating config version content: %v", err)
 	}
@@ -537,6 +601,11 @@ func (lm *LocalManager) SetOpenTracing(openTracing bool) {
 	lm.OpenTracing = openTracing
 }
 
+// SetOtelTracing sets the value of OtelTracing for the Manager
+func (lm *LocalManager) SetOtelTracing(otelTracing bool) {
+	lm.OtelTracing = otelTracing
+}
+
 // AppProtectPluginStart starts the AppProtect plugin and sets AppProtect log level.
 func (lm *LocalManager) AppProtectPluginStart(appDone chan error, logLevel string) {
 	nl.Debugf(lm.logger, "Setting log level for App Protect - %s", logLevel)
@@ -677,14 +746,14 @@ func (lm *LocalManager) GetSecretsDir() string {
 	return lm.secretsPath
 }
 
-func configContentsChanged(filename string, content []byte) bool {
-	filename = filepath.Clean(filename)
-	if currentContent, err := os.ReadFile(filename); err == nil {
-		if string(content) == string(currentContent) {
-			return false
-		}
+// configContentsChanged returns the contents of the file that the next reload applies, or nil if it doesn't exist,
+// and whether they are different from the new contents.
+func (lm *LocalManager) configContentsChanged(filename string, content []byte) ([]byte, bool) {
+	currentContent, exists := lm.readConfig(filename)
+	if !exists {
+		return nil, true
 	}
-	return true
+	return currentContent, string(content) != string(currentContent)
 }
 
 // UpsertSplitClientsKeyVal upserts a key value pair in the split clients zone.
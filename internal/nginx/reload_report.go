@@ -0,0 +1,243 @@
+package nginx
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nginx/kubernetes-ingress/internal/logger/levels"
+	"github.com/pmezard/go-difflib/difflib"
+	api_v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// maxReloadEventItems is the maximum number of resources and files listed in a reload event.
+	maxReloadEventItems = 10
+	// maxConfigChangesContentSize is the maximum total size of the contents kept for the diffs of the changes.
+	maxConfigChangesContentSize = 32 << 20
+)
+
+// configFileChange is a change of a configuration file since the previous reload.
+type configFileChange struct {
+	filename string
+	// previous is the content at the time of the previous reload, nil if the file didn't exist.
+	previous []byte
+	// current is the content now, nil if the file was deleted.
+	current []byte
+	created bool
+	deleted bool
+	// previousHash and currentHash identify the contents, even if the contents were evicted.
+	previousHash [sha256.Size]byte
+	currentHash  [sha256.Size]byte
+	// evicted is true if the contents were not kept, because the kept contents reached maxConfigChangesContentSize.
+	evicted bool
+}
+
+// configChanges keeps the changes of the configuration files since the previous reload.
+type configChanges struct {
+	lock    sync.Mutex
+	changes map[string]*configFileChange
+	// contentSize is the total size of the kept contents.
+	contentSize int
+}
+
+func newConfigChanges() *configChanges {
+	return &configChanges{
+		changes: make(map[string]*configFileChange),
+	}
+}
+
+// add records a change of a file. Changes of the same file are combined, so that the previous content
+// is always the content at the time of the previous reload. It returns false if the combined changes
+// cancel each other out.
+func (c *configChanges) add(filename string, previous []byte, current []byte) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	change, exists := c.changes[filename]
+	if !exists {
+		change = &configFileChange{
+			filename:     filename,
+			created:      previous == nil,
+			previousHash: sha256.Sum256(previous),
+		}
+		c.changes[filename] = change
+		c.keep(change, &change.previous, previous)
+	}
+
+	c.contentSize -= len(change.current)
+	change.current = nil
+	change.deleted = current == nil
+	change.currentHash = sha256.Sum256(current)
+	c.keep(change, &change.current, current)
+
+	if exists && change.created == change.deleted && change.previousHash == change.currentHash {
+		c.delete(change)
+		return false
+	}
+	return true
+}
+
+// keep keeps the content of a change, unless the kept contents would exceed maxConfigChangesContentSize.
+// In that case, the contents of the change are evicted and the change is reported without a diff.
+func (c *configChanges) keep(change *configFileChange, dst *[]byte, content []byte) {
+	if change.evicted {
+		return
+	}
+	if c.contentSize+len(content) > maxConfigChangesContentSize {
+		c.contentSize -= len(change.previous) + len(change.current)
+		change.previous, change.current = nil, nil
+		change.evicted = true
+		return
+	}
+	*dst = content
+	c.contentSize += len(content)
+}
+
+// delete deletes a change and releases its kept contents.
+func (c *configChanges) delete(change *configFileChange) {
+	c.contentSize -= len(change.previous) + len(change.current)
+	delete(c.changes, change.filename)
+}
+
+// get returns the change of a file.
+func (c *configChanges) get(filename string) (*configFileChange, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	change, exists := c.changes[filename]
+	return change, exists
+}
+
+// remove removes the change of a file and returns it.
+func (c *configChanges) remove(filename string) (*configFileChange, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	change, exists := c.changes[filename]
+	if exists {
+		c.delete(change)
+	}
+	return change, exists
+}
+
+// list returns the changes sorted by filename.
+func (c *configChanges) list() []*configFileChange {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.sorted()
+}
+
+// take returns the changes sorted by filename and starts recording the changes for the next reload.
+func (c *configChanges) take() []*configFileChange {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	changes := c.sorted()
+	c.changes = make(map[string]*configFileChange)
+	c.contentSize = 0
+	return changes
+}
+
+func (c *configChanges) sorted() []*configFileChange {
+	changes := make([]*configFileChange, 0, len(c.changes))
+	for _, change := range c.changes {
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].filename < changes[j].filename
+	})
+	return changes
+}
+
+// diff returns the unified diff of the change.
+func (c *configFileChange) diff() (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(c.previous),
+		B:        splitLines(c.current),
+		FromFile: c.filename,
+		ToFile:   c.filename,
+		Context:  3,
+	})
+}
+
+// splitLines splits the content into lines, keeping the line endings.
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// reportReload logs a record and records an event for a reload with the changed files and the resources
+// that caused it. In debug mode, the diff of every changed file is logged.
+func (lm *LocalManager) reportReload(reason ReloadReason, resources []string, changes []*configFileChange, reloadErr error) {
+	files := make([]string, 0, len(changes))
+	for _, change := range changes {
+		files = append(files, change.filename)
+	}
+
+	ctx := context.Background()
+	attrs := []slog.Attr{
+		slog.String("reason", string(reason)),
+		slog.Int("configVersion", lm.configVersion),
+		slog.Any("resources", resources),
+		slog.Any("changedFiles", files),
+	}
+	if reloadErr != nil {
+		attrs = append(attrs, slog.String("error", reloadErr.Error()))
+		lm.logger.LogAttrs(ctx, levels.LevelWarning, "NGINX reload failed", attrs...)
+	} else {
+		lm.logger.LogAttrs(ctx, levels.LevelInfo, "NGINX reloaded", attrs...)
+	}
+
+	if lm.logger.Enabled(ctx, levels.LevelDebug) {
+		for _, change := range changes {
+			if change.evicted {
+				lm.logger.LogAttrs(ctx, levels.LevelDebug, "Configuration file changed",
+					slog.String("file", change.filename), slog.String("diff", "omitted, the changes of the reload are too large"))
+				continue
+			}
+			diff, err := change.diff()
+			if err != nil {
+				lm.logger.LogAttrs(ctx, levels.LevelDebug, "Failed to compute the diff of a configuration file",
+					slog.String("file", change.filename), slog.String("error", err.Error()))
+				continue
+			}
+			lm.logger.LogAttrs(ctx, levels.LevelDebug, "Configuration file changed",
+				slog.String("file", change.filename), slog.String("diff", diff))
+		}
+	}
+
+	if lm.eventRecorder == nil || lm.eventObject == nil {
+		return
+	}
+
+	message := fmt.Sprintf("NGINX reloaded (reason: %s) for resources: %s; changed files: %s",
+		reason, listReloadEventItems(resources), listReloadEventItems(files))
+	if reloadErr != nil {
+		lm.eventRecorder.Eventf(lm.eventObject, api_v1.EventTypeWarning, "ReloadFailed", "%s: %v", message, reloadErr)
+		return
+	}
+	lm.eventRecorder.Event(lm.eventObject, api_v1.EventTypeNormal, "Reloaded", message)
+}
+
+// listReloadEventItems lists the items for an event, up to maxReloadEventItems.
+func listReloadEventItems(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	if len(items) <= maxReloadEventItems {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(items[:maxReloadEventItems], ", "), len(items)-maxReloadEventItems)
+}
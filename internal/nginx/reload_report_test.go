@@ -0,0 +1,195 @@
+package nginx
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	nic_glog "github.com/nginx/kubernetes-ingress/internal/logger/glog"
+	"github.com/nginx/kubernetes-ingress/internal/logger/levels"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestConfigChanges(t *testing.T) {
+	t.Parallel()
+
+	c := newConfigChanges()
+	c.add("/etc/nginx/conf.d/b.conf", nil, []byte("b1"))
+	c.add("/etc/nginx/conf.d/a.conf", []byte("a1"), []byte("a2"))
+	c.add("/etc/nginx/conf.d/a.conf", []byte("a2"), []byte("a3"))
+	c.add("/etc/nginx/conf.d/c.conf", []byte("c1"), []byte("c2"))
+	c.add("/etc/nginx/conf.d/c.conf", []byte("c2"), []byte("c1"))
+	c.add("/etc/nginx/conf.d/d.conf", nil, []byte("d1"))
+	c.add("/etc/nginx/conf.d/d.conf", []byte("d1"), nil)
+
+	changes := c.take()
+
+	if len(changes) != 2 {
+		t.Fatalf("take() returned %d changes, want 2: %+v", len(changes), changes)
+	}
+	a, b := changes[0], changes[1]
+	if a.filename != "/etc/nginx/conf.d/a.conf" || string(a.previous) != "a1" || string(a.current) != "a3" {
+		t.Errorf("take() returned unexpected change %+v", a)
+	}
+	if b.filename != "/etc/nginx/conf.d/b.conf" || b.previous != nil || string(b.current) != "b1" {
+		t.Errorf("take() returned unexpected change %+v", b)
+	}
+
+	if changes := c.take(); len(changes) != 0 {
+		t.Errorf("take() returned %d changes after the previous take(), want 0", len(changes))
+	}
+}
+
+func TestConfigChanges_EvictsContentsAboveMaxSize(t *testing.T) {
+	t.Parallel()
+
+	c := newConfigChanges()
+	large := bytes.Repeat([]byte("a"), maxConfigChangesContentSize)
+	c.add("/etc/nginx/conf.d/a.conf", nil, []byte("a1"))
+	c.add("/etc/nginx/conf.d/b.conf", nil, large)
+	c.add("/etc/nginx/conf.d/c.conf", []byte("c1"), large)
+	c.add("/etc/nginx/conf.d/c.conf", large, []byte("c1"))
+
+	if c.contentSize > maxConfigChangesContentSize {
+		t.Errorf("contentSize is %d, want at most %d", c.contentSize, maxConfigChangesContentSize)
+	}
+
+	changes := c.take()
+
+	if len(changes) != 2 {
+		t.Fatalf("take() returned %d changes, want 2: %+v", len(changes), changes)
+	}
+	a, b := changes[0], changes[1]
+	if a.evicted || string(a.current) != "a1" {
+		t.Errorf("take() returned unexpected change %+v", a)
+	}
+	if !b.evicted || b.current != nil || !b.created {
+		t.Errorf("take() returned unexpected change %+v", b)
+	}
+	if c.contentSize != 0 {
+		t.Errorf("contentSize is %d after take(), want 0", c.contentSize)
+	}
+}
+
+func TestConfigFileChangeDiff(t *testing.T) {
+	t.Parallel()
+
+	change := &configFileChange{
+		filename: "/etc/nginx/conf.d/a.conf",
+		previous: []byte("server {\n    listen 80;\n}\n"),
+		current:  []byte("server {\n    listen 8080;\n}\n"),
+	}
+
+	diff, err := change.diff()
+	if err != nil {
+		t.Fatalf("diff() returned unexpected error: %v", err)
+	}
+
+	want := `--- /etc/nginx/conf.d/a.conf
++++ /etc/nginx/conf.d/a.conf
+@@ -1,3 +1,3 @@
+ server {
+-    listen 80;
++    listen 8080;
+ }
+`
+	if diff != want {
+		t.Errorf("diff() returned\n%s\nwant\n%s", diff, want)
+	}
+}
+
+func newTestReportManager(level slog.Level, recorder record.EventRecorder) (*LocalManager, *bytes.Buffer) {
+	var buf bytes.Buffer
+	lm := &LocalManager{
+		configVersion: 3,
+		logger:        slog.New(nic_glog.New(&buf, &nic_glog.Options{Level: level})),
+		configChanges: newConfigChanges(),
+		eventRecorder: recorder,
+		eventObject:   &api_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "nginx-ingress", Namespace: "nginx-ingress"}},
+	}
+	return lm, &buf
+}
+
+func TestReportReload(t *testing.T) {
+	t.Parallel()
+
+	recorder := record.NewFakeRecorder(1)
+	lm, buf := newTestReportManager(levels.LevelInfo, recorder)
+	changes := []*configFileChange{
+		{
+			filename: "/etc/nginx/conf.d/vs_default_cafe.conf",
+			previous: []byte("a\n"),
+			current:  []byte("b\n"),
+		},
+	}
+
+	lm.reportReload(ReloadForConfigUpdate, []string{"VirtualServer default/cafe"}, changes, nil)
+
+	wantLog := "NGINX reloaded reason=config configVersion=3 resources=[VirtualServer default/cafe] changedFiles=[/etc/nginx/conf.d/vs_default_cafe.conf]\n"
+	if log := buf.String(); !strings.HasSuffix(log, wantLog) {
+		t.Errorf("reportReload() logged %q, want a record ending with %q", log, wantLog)
+	}
+	if strings.Contains(buf.String(), "+b") {
+		t.Errorf("reportReload() logged a diff without debug enabled: %q", buf.String())
+	}
+
+	wantEvent := "Normal Reloaded NGINX reloaded (reason: config) for resources: VirtualServer default/cafe; changed files: /etc/nginx/conf.d/vs_default_cafe.conf"
+	if event := <-recorder.Events; event != wantEvent {
+		t.Errorf("reportReload() recorded event %q, want %q", event, wantEvent)
+	}
+}
+
+func TestReportReload_LogsDiffInDebugMode(t *testing.T) {
+	t.Parallel()
+
+	lm, buf := newTestReportManager(levels.LevelDebug, nil)
+	changes := []*configFileChange{
+		{
+			filename: "/etc/nginx/nginx.conf",
+			previous: []byte("worker_processes 1;\n"),
+			current:  []byte("worker_processes 2;\n"),
+		},
+	}
+
+	lm.reportReload(ReloadForConfigUpdate, nil, changes, nil)
+
+	log := buf.String()
+	if !strings.Contains(log, "Configuration file changed file=/etc/nginx/nginx.conf") {
+		t.Errorf("reportReload() didn't log the changed file: %q", log)
+	}
+	if !strings.Contains(log, "-worker_processes 1;\n+worker_processes 2;\n") {
+		t.Errorf("reportReload() didn't log the diff: %q", log)
+	}
+}
+
+func TestReportReload_ReportsFailedReload(t *testing.T) {
+	t.Parallel()
+
+	recorder := record.NewFakeRecorder(1)
+	lm, _ := newTestReportManager(levels.LevelInfo, recorder)
+
+	lm.reportReload(ReloadForSecretUpdate, nil, nil, errors.New("nginx reload failed"))
+
+	wantEvent := "Warning ReloadFailed NGINX reloaded (reason: secret) for resources: none; changed files: none: nginx reload failed"
+	if event := <-recorder.Events; event != wantEvent {
+		t.Errorf("reportReload() recorded event %q, want %q", event, wantEvent)
+	}
+}
+
+func TestListReloadEventItems(t *testing.T) {
+	t.Parallel()
+
+	items := make([]string, 12)
+	for i := range items {
+		items[i] = string(rune('a' + i))
+	}
+
+	want := "a, b, c, d, e, f, g, h, i, j and 2 more"
+	if got := listReloadEventItems(items); got != want {
+		t.Errorf("listReloadEventItems() returned %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,141 @@
+package nginx
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+
+	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+)
+
+// RenderManager is a Manager that writes the NGINX configuration files below an output directory
+// instead of the NGINX configuration folder. It never starts or reloads NGINX.
+// The filenames it returns, for example for secrets, are the filenames NGINX would use,
+// so that the generated configuration can be compared with the configuration of a running NGINX.
+type RenderManager struct {
+	*FakeManager
+	outputDir                   string
+	confdPath                   string
+	streamConfdPath             string
+	secretsPath                 string
+	dhparamFilename             string
+	mainConfFilename            string
+	tlsPassthroughHostsFilename string
+	version                     Version
+	logger                      *slog.Logger
+}
+
+// NewRenderManager creates a RenderManager. The files for confPath are written below outputDir.
+func NewRenderManager(logger *slog.Logger, confPath string, outputDir string, version Version) *RenderManager {
+	return &RenderManager{
+		FakeManager:                 NewFakeManager(confPath),
+		outputDir:                   outputDir,
+		confdPath:                   path.Join(confPath, "conf.d"),
+		streamConfdPath:             path.Join(confPath, "stream-conf.d"),
+		secretsPath:                 path.Join(confPath, "secrets"),
+		dhparamFilename:             path.Join(confPath, "secrets", "dhparam.pem"),
+		mainConfFilename:            path.Join(confPath, "nginx.conf"),
+		tlsPassthroughHostsFilename: path.Join(confPath, "tls-passthrough-hosts.conf"),
+		version:                     version,
+		logger:                      logger,
+	}
+}
+
+// OutputDir returns the directory the files are written to.
+func (rm *RenderManager) OutputDir() string {
+	return rm.outputDir
+}
+
+func (rm *RenderManager) write(filename string, content []byte, mode os.FileMode) error {
+	target := filepath.Join(rm.outputDir, filepath.FromSlash(filename))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %v: %w", target, err)
+	}
+	if err := os.WriteFile(target, content, mode); err != nil {
+		return fmt.Errorf("failed to write %v: %w", target, err)
+	}
+	return nil
+}
+
+func (rm *RenderManager) remove(filename string) {
+	target := filepath.Join(rm.outputDir, filepath.FromSlash(filename))
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		nl.Warnf(rm.logger, "Failed to delete %v: %v", target, err)
+	}
+}
+
+func (rm *RenderManager) createConfig(filename string, content []byte) bool {
+	nl.Debugf(rm.logger, "Writing config to %v", filename)
+	if err := rm.write(filename, content, 0o644); err != nil {
+		nl.Errorf(rm.logger, "Failed to write config: %v", err)
+		return false
+	}
+	return true
+}
+
+// CreateMainConfig writes the main NGINX configuration file.
+func (rm *RenderManager) CreateMainConfig(content []byte) bool {
+	return rm.createConfig(rm.mainConfFilename, content)
+}
+
+// CreateConfig writes a configuration file for the conf.d folder.
+func (rm *RenderManager) CreateConfig(name string, content []byte) bool {
+	return rm.createConfig(path.Join(rm.confdPath, name+".conf"), content)
+}
+
+// DeleteConfig deletes a configuration file from the conf.d folder.
+func (rm *RenderManager) DeleteConfig(name string) {
+	rm.remove(path.Join(rm.confdPath, name+".conf"))
+}
+
+// CreateStreamConfig writes a configuration file for the stream-conf.d folder.
+func (rm *RenderManager) CreateStreamConfig(name string, content []byte) bool {
+	return rm.createConfig(path.Join(rm.streamConfdPath, name+".conf"), content)
+}
+
+// DeleteStreamConfig deletes a configuration file from the stream-conf.d folder.
+func (rm *RenderManager) DeleteStreamConfig(name string) {
+	rm.remove(path.Join(rm.streamConfdPath, name+".conf"))
+}
+
+// CreateTLSPassthroughHostsConfig writes the TLS Passthrough hosts configuration file.
+func (rm *RenderManager) CreateTLSPassthroughHostsConfig(content []byte) bool {
+	return rm.createConfig(rm.tlsPassthroughHostsFilename, content)
+}
+
+// CreateSecret writes a secret file and returns the filename NGINX would use for it.
+func (rm *RenderManager) CreateSecret(name string, content []byte, mode os.FileMode) string {
+	filename := rm.GetFilenameForSecret(name)
+	nl.Debugf(rm.logger, "Writing secret to %v", filename)
+	if err := rm.write(filename, content, mode); err != nil {
+		nl.Errorf(rm.logger, "Failed to write secret: %v", err)
+	}
+	return filename
+}
+
+// DeleteSecret deletes a secret file.
+func (rm *RenderManager) DeleteSecret(name string) {
+	rm.remove(rm.GetFilenameForSecret(name))
+}
+
+// GetFilenameForSecret constructs the filename for the secret.
+func (rm *RenderManager) GetFilenameForSecret(name string) string {
+	return path.Join(rm.secretsPath, name)
+}
+
+// CreateDHParam writes the dhparam.pem file.
+func (rm *RenderManager) CreateDHParam(content string) (string, error) {
+	return rm.dhparamFilename, rm.write(rm.dhparamFilename, []byte(content), 0o644)
+}
+
+// GetSecretsDir returns the secrets folder.
+func (rm *RenderManager) GetSecretsDir() string {
+	return rm.secretsPath
+}
+
+// Version returns the NGINX version the configuration is rendered for.
+func (rm *RenderManager) Version() Version {
+	return rm.version
+}
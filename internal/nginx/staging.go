@@ -0,0 +1,276 @@
+package nginx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+)
+
+const (
+	stagingDirName = "staging"
+	pendingDirName = "pending"
+)
+
+// configErrorRe matches the error of a configuration file reported by nginx -t, for example:
+// nginx: [emerg] unknown directive "foo" in /etc/nginx/conf.d/vs_default_cafe.conf:12
+var configErrorRe = regexp.MustCompile(`\[(?:emerg|alert|crit)\] (.+) in (\S+):(\d+)`)
+
+// ConfigError is an error in the NGINX configuration found by testing the configuration.
+type ConfigError struct {
+	// Filename is the configuration file with the error. It is empty if NGINX didn't report the file.
+	Filename string
+	// Line is the line of the error in the file.
+	Line int
+	// Message is the error reported by NGINX.
+	Message string
+}
+
+func (e *ConfigError) Error() string {
+	if e.Filename == "" {
+		return fmt.Sprintf("nginx configuration test failed: %s", e.Message)
+	}
+	return fmt.Sprintf("nginx configuration test failed: %s in %s:%d", e.Message, e.Filename, e.Line)
+}
+
+// TestConfig tests the configuration files changed since the previous reload with nginx -t before they are applied
+// by a reload. The files are tested in a staging copy of the configuration folder, so that the files of the running
+// NGINX are never used for the test. If NGINX rejects the configuration, a *ConfigError is returned.
+// If testing the configuration is disabled, TestConfig does nothing.
+func (lm *LocalManager) TestConfig() error {
+	if !lm.configTest {
+		return nil
+	}
+
+	if err := lm.stageConfig(); err != nil {
+		return fmt.Errorf("failed to stage the configuration: %w", err)
+	}
+
+	binaryFilename := getBinaryFileName(lm.debug)
+	stagingMainConf := path.Join(lm.stagingPath, path.Base(lm.mainConfFilename))
+	nl.Debugf(lm.logger, "Testing the configuration in %v", lm.stagingPath)
+
+	out, err := exec.Command(binaryFilename, "-t", "-q", "-e", "stderr", "-c", stagingMainConf).CombinedOutput() // #nosec G204
+	if err == nil {
+		return nil
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return fmt.Errorf("failed to execute nginx -t: %w", err)
+	}
+
+	return lm.parseConfigError(string(out))
+}
+
+// parseConfigError parses the output of nginx -t into a ConfigError with the paths of the real configuration folder.
+func (lm *LocalManager) parseConfigError(output string) *ConfigError {
+	output = strings.ReplaceAll(output, lm.stagingPath+"/", lm.confPath+"/")
+
+	match := configErrorRe.FindStringSubmatch(output)
+	if match == nil {
+		return &ConfigError{Message: strings.TrimSpace(output)}
+	}
+
+	line, err := strconv.Atoi(match[3])
+	if err != nil {
+		line = 0
+	}
+	return &ConfigError{
+		Filename: match[2],
+		Line:     line,
+		Message:  match[1],
+	}
+}
+
+// stageConfig creates the staging copy of the configuration folder with the pending changes applied.
+// The configuration files generated by the Ingress Controller are copied with their includes pointing to
+// the staging folder, while any other files and folders are linked, so that the relative includes keep working.
+func (lm *LocalManager) stageConfig() error {
+	if err := os.RemoveAll(lm.stagingPath); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(lm.stagingPath, 0o755); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(lm.confPath)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		source := path.Join(lm.confPath, entry.Name())
+		target := path.Join(lm.stagingPath, entry.Name())
+
+		switch {
+		case source == lm.stagingPath || source == lm.pendingPath:
+			continue
+		case source == lm.confdPath || source == lm.streamConfdPath:
+			err = lm.stageConfigFolder(source, target)
+		case entry.Type().IsRegular() && strings.HasSuffix(entry.Name(), ".conf"):
+			err = lm.stageConfigFile(source, target)
+		default:
+			err = os.Symlink(source, target)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, change := range lm.configChanges.list() {
+		target := path.Join(lm.stagingPath, lm.relativeConfigPath(change.filename))
+		if change.deleted {
+			err = os.Remove(target)
+			if os.IsNotExist(err) {
+				err = nil
+			}
+		} else {
+			err = lm.stageConfigFile(lm.pendingFilename(change.filename), target)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (lm *LocalManager) stageConfigFolder(source string, target string) error {
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(source)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		if err := lm.stageConfigFile(path.Join(source, entry.Name()), path.Join(target, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (lm *LocalManager) stageConfigFile(source string, target string) error {
+	content, err := os.ReadFile(filepath.Clean(source))
+	if err != nil {
+		return err
+	}
+
+	content = bytes.ReplaceAll(content, []byte("include "+lm.confPath+"/"), []byte("include "+lm.stagingPath+"/"))
+
+	return os.WriteFile(target, content, configFileMode)
+}
+
+// relativeConfigPath returns the path of a configuration file relative to the configuration folder.
+func (lm *LocalManager) relativeConfigPath(filename string) string {
+	return strings.TrimPrefix(filename, lm.confPath+"/")
+}
+
+// pendingFilename returns the file in the pending folder that holds a change of a configuration file.
+func (lm *LocalManager) pendingFilename(filename string) string {
+	return path.Join(lm.pendingPath, lm.relativeConfigPath(filename))
+}
+
+// writeConfig writes a configuration file. If testing the configuration is enabled, the file is written to the
+// pending folder and is moved to the configuration folder by the next reload, once NGINX has accepted it.
+func (lm *LocalManager) writeConfig(filename string, content []byte) error {
+	if !lm.configTest {
+		return createFileAndWrite(filename, content)
+	}
+
+	pending := lm.pendingFilename(filename)
+	if err := os.MkdirAll(path.Dir(pending), 0o755); err != nil {
+		return err
+	}
+	return createFileAndWrite(pending, content)
+}
+
+// readConfig reads the content of a configuration file that the next reload applies.
+// It returns false if the file doesn't exist.
+func (lm *LocalManager) readConfig(filename string) ([]byte, bool) {
+	if lm.configTest {
+		if change, exists := lm.configChanges.get(filename); exists {
+			if change.deleted {
+				return nil, false
+			}
+			filename = lm.pendingFilename(filename)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, false
+	}
+	return content, true
+}
+
+// addConfigChange records a change of a configuration file. The pending file is removed once
+// the file is deleted or the change cancels out the previous changes.
+func (lm *LocalManager) addConfigChange(filename string, previous []byte, current []byte) {
+	changed := lm.configChanges.add(filename, previous, current)
+	if lm.configTest && (!changed || current == nil) {
+		lm.removePendingConfig(filename)
+	}
+}
+
+func (lm *LocalManager) removePendingConfig(filename string) {
+	pending := lm.pendingFilename(filename)
+	if err := os.Remove(pending); err != nil && !os.IsNotExist(err) {
+		nl.Warnf(lm.logger, "Failed to delete pending config %v: %v", pending, err)
+	}
+}
+
+// applyConfigChanges moves the pending configuration files to the configuration folder and deletes the deleted
+// files from it, so that NGINX applies the changes. The files are already there if testing the configuration is disabled.
+func (lm *LocalManager) applyConfigChanges(changes []*configFileChange) {
+	if !lm.configTest {
+		return
+	}
+
+	for _, change := range changes {
+		if change.deleted {
+			if err := os.Remove(change.filename); err != nil && !os.IsNotExist(err) {
+				nl.Warnf(lm.logger, "Failed to delete config from %v: %v", change.filename, err)
+			}
+			continue
+		}
+		if err := os.Rename(lm.pendingFilename(change.filename), change.filename); err != nil {
+			nl.Fatalf(lm.logger, "Failed to apply config %v: %v", change.filename, err)
+		}
+	}
+}
+
+// RollbackConfig discards the change of the configuration file since the previous reload, so that the file keeps
+// the content applied by the previous reload. It returns false if the file hasn't changed since the previous reload.
+func (lm *LocalManager) RollbackConfig(filename string) bool {
+	if _, exists := lm.configChanges.remove(filename); !exists {
+		return false
+	}
+
+	nl.Infof(lm.logger, "Rolling back config %v to its previous version", filename)
+	lm.removePendingConfig(filename)
+	return true
+}
+
+// RollbackAllConfigs discards all the changes of the configuration files since the previous reload.
+func (lm *LocalManager) RollbackAllConfigs() {
+	for _, change := range lm.configChanges.take() {
+		nl.Infof(lm.logger, "Rolling back config %v to its previous version", change.filename)
+		lm.removePendingConfig(change.filename)
+	}
+}
@@ -0,0 +1,249 @@
+package nginx
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	nic_glog "github.com/nginx/kubernetes-ingress/internal/logger/glog"
+	"github.com/nginx/kubernetes-ingress/internal/logger/levels"
+)
+
+func newTestStagingManager(t *testing.T) *LocalManager {
+	t.Helper()
+
+	confPath := t.TempDir()
+	for _, dir := range []string{"conf.d", "stream-conf.d", "oidc"} {
+		if err := os.Mkdir(path.Join(confPath, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l := slog.New(nic_glog.New(&bytes.Buffer{}, &nic_glog.Options{Level: levels.LevelInfo}))
+	// the configuration folder is passed with a trailing slash, as by the Ingress Controller
+	return NewLocalManager(nl.ContextWithLogger(context.Background(), l), confPath+"/", false, nil, nil, time.Second, false, true, nil, nil)
+}
+
+func writeTestFile(t *testing.T, filename string, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStageConfig(t *testing.T) {
+	t.Parallel()
+
+	lm := newTestStagingManager(t)
+	writeTestFile(t, lm.mainConfFilename, "include "+lm.confPath+"/mime.types;\ninclude "+lm.confPath+"/conf.d/*.conf;\n")
+	writeTestFile(t, path.Join(lm.confPath, "mime.types"), "types {}\n")
+	writeTestFile(t, path.Join(lm.confdPath, "vs_default_cafe.conf"), "include oidc/oidc.conf;\n")
+	writeTestFile(t, path.Join(lm.confPath, "oidc", "oidc.conf"), "# oidc\n")
+
+	// a leftover of the previous test must be removed
+	if err := os.MkdirAll(path.Join(lm.stagingPath, "conf.d"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, path.Join(lm.stagingPath, "conf.d", "vs_default_deleted.conf"), "")
+
+	if err := lm.stageConfig(); err != nil {
+		t.Fatalf("stageConfig() returned unexpected error: %v", err)
+	}
+
+	mainConf, err := os.ReadFile(path.Join(lm.stagingPath, "nginx.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantMainConf := "include " + lm.stagingPath + "/mime.types;\ninclude " + lm.stagingPath + "/conf.d/*.conf;\n"
+	if string(mainConf) != wantMainConf {
+		t.Errorf("stageConfig() staged nginx.conf\n%s\nwant\n%s", mainConf, wantMainConf)
+	}
+
+	vsConf, err := os.ReadFile(path.Join(lm.stagingPath, "conf.d", "vs_default_cafe.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(vsConf) != "include oidc/oidc.conf;\n" {
+		t.Errorf("stageConfig() staged an unexpected VirtualServer config %q", vsConf)
+	}
+
+	for _, linked := range []string{"mime.types", "oidc"} {
+		target, err := os.Readlink(path.Join(lm.stagingPath, linked))
+		if err != nil {
+			t.Errorf("stageConfig() didn't link %v: %v", linked, err)
+			continue
+		}
+		if target != path.Join(lm.confPath, linked) {
+			t.Errorf("stageConfig() linked %v to %v", linked, target)
+		}
+	}
+
+	if _, err := os.Stat(path.Join(lm.stagingPath, "conf.d", "vs_default_deleted.conf")); !os.IsNotExist(err) {
+		t.Error("stageConfig() kept a config of the previous staging")
+	}
+}
+
+func TestParseConfigError(t *testing.T) {
+	t.Parallel()
+
+	lm := newTestStagingManager(t)
+
+	tests := []struct {
+		output   string
+		expected ConfigError
+	}{
+		{
+			output: `nginx: [emerg] unknown directive "foo" in ` + lm.stagingPath + "/conf.d/vs_default_cafe.conf:12\n" +
+				"nginx: configuration file " + lm.stagingPath + "/nginx.conf test failed\n",
+			expected: ConfigError{
+				Filename: lm.confPath + "/conf.d/vs_default_cafe.conf",
+				Line:     12,
+				Message:  `unknown directive "foo"`,
+			},
+		},
+		{
+			output: "nginx: [emerg] bind() to 0.0.0.0:80 failed (98: Address already in use)\n",
+			expected: ConfigError{
+				Message: "nginx: [emerg] bind() to 0.0.0.0:80 failed (98: Address already in use)",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		result := lm.parseConfigError(test.output)
+		if *result != test.expected {
+			t.Errorf("parseConfigError(%q) returned %+v, want %+v", test.output, *result, test.expected)
+		}
+	}
+}
+
+func TestStageConfigWithPendingChanges(t *testing.T) {
+	t.Parallel()
+
+	lm := newTestStagingManager(t)
+	writeTestFile(t, lm.mainConfFilename, "include "+lm.confPath+"/conf.d/*.conf;\n")
+	writeTestFile(t, path.Join(lm.confdPath, "vs_default_cafe.conf"), "# cafe\n")
+	writeTestFile(t, path.Join(lm.confdPath, "vs_default_deleted.conf"), "# deleted\n")
+
+	lm.CreateConfig("vs_default_cafe", []byte("# updated cafe\n"))
+	lm.CreateConfig("vs_default_tea", []byte("include "+lm.confPath+"/oidc/oidc.conf;\n"))
+	lm.DeleteConfig("vs_default_deleted")
+
+	if err := lm.stageConfig(); err != nil {
+		t.Fatalf("stageConfig() returned unexpected error: %v", err)
+	}
+
+	mainConf, err := os.ReadFile(path.Join(lm.stagingPath, "nginx.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "include " + lm.stagingPath + "/conf.d/*.conf;\n"; string(mainConf) != want {
+		t.Errorf("stageConfig() staged nginx.conf %q, want %q", mainConf, want)
+	}
+
+	staged := map[string]string{
+		"vs_default_cafe.conf": "# updated cafe\n",
+		"vs_default_tea.conf":  "include " + lm.stagingPath + "/oidc/oidc.conf;\n",
+	}
+	for name, want := range staged {
+		content, err := os.ReadFile(path.Join(lm.stagingPath, "conf.d", name))
+		if err != nil {
+			t.Errorf("stageConfig() didn't stage %v: %v", name, err)
+			continue
+		}
+		if string(content) != want {
+			t.Errorf("stageConfig() staged %v with %q, want %q", name, content, want)
+		}
+	}
+	if _, err := os.Stat(path.Join(lm.stagingPath, "conf.d", "vs_default_deleted.conf")); !os.IsNotExist(err) {
+		t.Error("stageConfig() staged a deleted config")
+	}
+	if _, err := os.Stat(path.Join(lm.stagingPath, pendingDirName)); !os.IsNotExist(err) {
+		t.Error("stageConfig() staged the pending folder")
+	}
+}
+
+func TestCreateConfigDoesNotChangeLiveConfigBeforeApply(t *testing.T) {
+	t.Parallel()
+
+	lm := newTestStagingManager(t)
+	updated := path.Join(lm.confdPath, "vs_default_cafe.conf")
+	added := path.Join(lm.confdPath, "vs_default_tea.conf")
+	deleted := path.Join(lm.confdPath, "vs_default_deleted.conf")
+	writeTestFile(t, updated, "good")
+	writeTestFile(t, deleted, "good")
+
+	lm.CreateConfig("vs_default_cafe", []byte("new"))
+	lm.CreateConfig("vs_default_tea", []byte("new"))
+	lm.DeleteConfig("vs_default_deleted")
+
+	assertFileContent(t, updated, "good")
+	assertFileContent(t, deleted, "good")
+	if _, err := os.Stat(added); !os.IsNotExist(err) {
+		t.Errorf("CreateConfig() wrote the new file %v before it was applied", added)
+	}
+
+	lm.applyConfigChanges(lm.configChanges.take())
+
+	assertFileContent(t, updated, "new")
+	assertFileContent(t, added, "new")
+	if _, err := os.Stat(deleted); !os.IsNotExist(err) {
+		t.Errorf("applyConfigChanges() didn't delete %v", deleted)
+	}
+}
+
+func TestRollbackConfig(t *testing.T) {
+	t.Parallel()
+
+	lm := newTestStagingManager(t)
+	updated := path.Join(lm.confdPath, "vs_default_cafe.conf")
+	added := path.Join(lm.confdPath, "vs_default_tea.conf")
+	writeTestFile(t, updated, "good")
+
+	lm.createConfig(updated, []byte("bad"))
+	lm.createConfig(added, []byte("bad"))
+
+	if !lm.RollbackConfig(updated) {
+		t.Errorf("RollbackConfig(%v) returned false for a changed file", updated)
+	}
+	assertFileContent(t, updated, "good")
+	if lm.RollbackConfig(updated) {
+		t.Errorf("RollbackConfig(%v) returned true for a file that was already rolled back", updated)
+	}
+	if _, err := os.Stat(lm.pendingFilename(updated)); !os.IsNotExist(err) {
+		t.Errorf("RollbackConfig(%v) kept the pending file", updated)
+	}
+
+	lm.RollbackAllConfigs()
+	if _, err := os.Stat(lm.pendingFilename(added)); !os.IsNotExist(err) {
+		t.Errorf("RollbackAllConfigs() kept the pending file of %v", added)
+	}
+	if changes := lm.configChanges.take(); len(changes) != 0 {
+		t.Errorf("RollbackAllConfigs() kept %d changes", len(changes))
+	}
+
+	lm.applyConfigChanges(nil)
+	assertFileContent(t, updated, "good")
+	if _, err := os.Stat(added); !os.IsNotExist(err) {
+		t.Errorf("the rolled back file %v was applied", added)
+	}
+}
+
+func assertFileContent(t *testing.T, filename string, want string) {
+	t.Helper()
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Errorf("failed to read %v: %v", filename, err)
+		return
+	}
+	if string(content) != want {
+		t.Errorf("%v has content %q, want %q", filename, content, want)
+	}
+}
@@ -102,6 +102,9 @@ const configVersionTemplateString = `server {
 	{{if .OpenTracingLoadModule}}
 	opentracing off;
 	{{end}}
+	{{if .OtelTraceInHTTP}}
+	otel_trace off;
+	{{end}}
 
     location /configVersion {
         return 200 {{.ConfigVersion}};
@@ -129,14 +132,16 @@ func newVerifyConfigGenerator() (*verifyConfigGenerator, error) {
 }
 
 // GenerateVersionConfig generates the config version file.
-func (c *verifyConfigGenerator) GenerateVersionConfig(configVersion int, openTracing bool) ([]byte, error) {
+func (c *verifyConfigGenerator) GenerateVersionConfig(configVersion int, openTracing bool, otelTracing bool) ([]byte, error) {
 	var configBuffer bytes.Buffer
 	templateValues := struct {
 		ConfigVersion         int
 		OpenTracingLoadModule bool
+		OtelTraceInHTTP       bool
 	}{
 		configVersion,
 		openTracing,
+		otelTracing,
 	}
 	err := c.configVersionTemplate.Execute(&configBuffer, templateValues)
 	if err != nil {
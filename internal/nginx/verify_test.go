@@ -63,7 +63,7 @@ func TestConfigWriter(t *testing.T) {
 	if err != nil {
 		t.Fatalf("error instantiating ConfigWriter: %v", err)
 	}
-	config, err := cw.GenerateVersionConfig(1, true)
+	config, err := cw.GenerateVersionConfig(1, true, true)
 	if err != nil {
 		t.Errorf("error generating version config: %v", err)
 	}
@@ -73,4 +73,7 @@ func TestConfigWriter(t *testing.T) {
 	if !strings.Contains(string(config), "opentracing off") {
 		t.Errorf("opentracing directive missing when is enabled. config contents: %v", string(config))
 	}
+	if !strings.Contains(string(config), "otel_trace off") {
+		t.Errorf("otel_trace directive missing when is enabled. config contents: %v", string(config))
+	}
 }
@@ -17,6 +17,10 @@ const (
 	TLSPassthroughListenerName = "tls-passthrough"
 	// TLSPassthroughListenerProtocol is the protocol of a built-in TLS Passthrough listener.
 	TLSPassthroughListenerProtocol = "TLS_PASSTHROUGH"
+	// GRPCConditionService is used in a Condition to match the service of a gRPC call.
+	GRPCConditionService = "service"
+	// GRPCConditionMethod is used in a Condition to match the method of a gRPC call.
+	GRPCConditionMethod = "method"
 )
 
 // +genclient
@@ -56,6 +60,10 @@ type VirtualServerSpec struct {
 	ExternalDNS    ExternalDNS            `json:"externalDNS"`
 	// InternalRoute allows for the configuration of internal routing.
 	InternalRoute bool `json:"internalRoute"`
+	// Tracing configures the OpenTelemetry tracing of the requests to the VirtualServer.
+	Tracing *Tracing `json:"tracing"`
+	// AccessLog configures the access logs of the VirtualServer.
+	AccessLog *AccessLog `json:"accessLog"`
 }
 
 // VirtualServerListener references a custom http and/or https listener defined in GlobalConfiguration.
@@ -64,7 +72,7 @@ type VirtualServerListener struct {
 	HTTPS string `json:"https"`
 }
 
-// ExternalDNS defines externaldns sub-resource of a virtual server.
+// ExternalDNS defines externaldns sub-resource of a VirtualServer or a TransportServer.
 type ExternalDNS struct {
 	Enable     bool   `json:"enable"`
 	RecordType string `json:"recordType,omitempty"`
@@ -137,7 +145,15 @@ type UpstreamBuffers struct {
 
 // UpstreamTLS defines a TLS configuration for an Upstream.
 type UpstreamTLS struct {
-	Enable bool `json:"enable"`
+	Enable            bool   `json:"enable"`
+	TrustedCertSecret string `json:"trustedCertSecret"`
+	VerifyServer      bool   `json:"verifyServer"`
+	VerifyDepth       *int   `json:"verifyDepth"`
+	Protocols         string `json:"protocols"`
+	Ciphers           string `json:"ciphers"`
+	SessionReuse      *bool  `json:"sessionReuse"`
+	ServerName        bool   `json:"serverName"`
+	SSLName           string `json:"sslName"`
 }
 
 // HealthCheck defines the parameters for active Upstream HealthChecks.
@@ -187,18 +203,45 @@ type Route struct {
 	Route            string            `json:"route"`
 	Action           *Action           `json:"action"`
 	Splits           []Split           `json:"splits"`
+	Canary           *Canary           `json:"canary"`
 	Matches          []Match           `json:"matches"`
 	ErrorPages       []ErrorPage       `json:"errorPages"`
 	LocationSnippets string            `json:"location-snippets"`
 	Dos              string            `json:"dos"`
+	Tracing          *Tracing          `json:"tracing"`
+}
+
+// Tracing defines the OpenTelemetry tracing of the requests.
+type Tracing struct {
+	// Enable enables or disables the tracing of the requests. If not set, the otel-trace-in-http ConfigMap key applies.
+	Enable *bool `json:"enable"`
+	// SpanAttributes are the attributes added to the spans of the requests.
+	SpanAttributes []SpanAttribute `json:"spanAttributes"`
+}
+
+// SpanAttribute defines an attribute of a span.
+type SpanAttribute struct {
+	Name string `json:"name"`
+	// Value can contain NGINX variables.
+	Value string `json:"value"`
+}
+
+// AccessLog defines the access logs of a VirtualServer.
+type AccessLog struct {
+	// Destination is syslog, /dev/stdout, /dev/stderr or a file in /var/log/nginx. The default is /dev/stdout.
+	Destination string `json:"destination"`
+	// Format is the name of a log format defined in the ConfigMap or of a preset. The default is main.
+	Format string `json:"format"`
 }
 
 // Action defines an action.
 type Action struct {
-	Pass     string          `json:"pass"`
-	Redirect *ActionRedirect `json:"redirect"`
-	Return   *ActionReturn   `json:"return"`
-	Proxy    *ActionProxy    `json:"proxy"`
+	Pass       string            `json:"pass"`
+	Redirect   *ActionRedirect   `json:"redirect"`
+	Return     *ActionReturn     `json:"return"`
+	Proxy      *ActionProxy      `json:"proxy"`
+	Mirror     *ActionMirror     `json:"mirror"`
+	GRPCReturn *ActionGRPCReturn `json:"grpcReturn"`
 }
 
 // ActionRedirect defines a redirect in an Action.
@@ -215,14 +258,28 @@ type ActionReturn struct {
 	Headers []Header `json:"headers"`
 }
 
+// ActionGRPCReturn defines a return of a gRPC status and message in an Action.
+type ActionGRPCReturn struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
 // ActionProxy defines a proxy in an Action.
 type ActionProxy struct {
 	Upstream        string                `json:"upstream"`
 	RewritePath     string                `json:"rewritePath"`
+	Rewrites        []ProxyRewrite        `json:"rewrites"`
 	RequestHeaders  *ProxyRequestHeaders  `json:"requestHeaders"`
 	ResponseHeaders *ProxyResponseHeaders `json:"responseHeaders"`
 }
 
+// ProxyRewrite defines a regex-based rewrite of the request URI in an ActionProxy.
+type ProxyRewrite struct {
+	Match   string  `json:"match"`
+	Replace string  `json:"replace"`
+	Query   *string `json:"query"`
+}
+
 // ProxyRequestHeaders defines the request headers manipulation in an ActionProxy.
 type ProxyRequestHeaders struct {
 	Pass *bool    `json:"pass"`
@@ -237,6 +294,13 @@ type ProxyResponseHeaders struct {
 	Add    []AddHeader `json:"add"`
 }
 
+// ActionMirror defines the mirroring of requests to an upstream in an Action.
+type ActionMirror struct {
+	Upstream    string `json:"upstream"`
+	Percentage  *int   `json:"percentage"`
+	RequestBody *bool  `json:"requestBody"`
+}
+
 // AddHeader defines an HTTP Header with an optional Always field to use with the add_header NGINX directive.
 type AddHeader struct {
 	Header `json:",inline"`
@@ -249,12 +313,25 @@ type Split struct {
 	Action *Action `json:"action"`
 }
 
+// Canary defines a progressive shift of the traffic of a Route from the first to the second of its two Splits.
+// The weight of the second Split starts at its configured weight and is increased by StepWeight every Interval
+// until it reaches MaxWeight. Every step is gated on the error rate and the response time of the upstream of the
+// second Split. If a threshold is crossed, the weight of the second Split is rolled back to 0.
+type Canary struct {
+	Interval        string `json:"interval"`
+	StepWeight      int    `json:"stepWeight"`
+	MaxWeight       *int   `json:"maxWeight"`
+	MaxErrorRate    *int   `json:"maxErrorRate"`
+	MaxResponseTime string `json:"maxResponseTime"`
+}
+
 // Condition defines a condition in a MatchRule.
 type Condition struct {
 	Header   string `json:"header"`
 	Cookie   string `json:"cookie"`
 	Argument string `json:"argument"`
 	Variable string `json:"variable"`
+	GRPC     string `json:"grpc"`
 	Value    string `json:"value"`
 }
 
@@ -315,6 +392,15 @@ type VirtualServerStatus struct {
 	Reason            string             `json:"reason"`
 	Message           string             `json:"message"`
 	ExternalEndpoints []ExternalEndpoint `json:"externalEndpoints,omitempty"`
+	Canaries          []CanaryStatus     `json:"canaries,omitempty"`
+}
+
+// CanaryStatus defines the state of the Canary of a Route.
+type CanaryStatus struct {
+	Path               string `json:"path"`
+	Weight             int    `json:"weight"`
+	Phase              string `json:"phase"`
+	ObservedGeneration int64  `json:"observedGeneration"`
 }
 
 // ExternalEndpoint defines the IP/ Hostname and ports used to connect to this resource.
@@ -386,6 +472,7 @@ type VirtualServerRouteStatus struct {
 	Message           string             `json:"message"`
 	ReferencedBy      string             `json:"referencedBy"`
 	ExternalEndpoints []ExternalEndpoint `json:"externalEndpoints,omitempty"`
+	Canaries          []CanaryStatus     `json:"canaries,omitempty"`
 }
 
 // +genclient
@@ -458,11 +545,18 @@ type TransportServerSpec struct {
 	UpstreamParameters *UpstreamParameters       `json:"upstreamParameters"`
 	SessionParameters  *SessionParameters        `json:"sessionParameters"`
 	Action             *TransportServerAction    `json:"action"`
+	ExternalDNS        ExternalDNS               `json:"externalDNS"`
+	Policies           []PolicyReference         `json:"policies"`
 }
 
 // TransportServerTLS defines TransportServerTLS configuration for a TransportServer.
 type TransportServerTLS struct {
-	Secret string `json:"secret"`
+	Secret           string       `json:"secret"`
+	CertManager      *CertManager `json:"cert-manager"`
+	ClientCertSecret string       `json:"clientCertSecret"`
+	CrlFileName      string       `json:"crlFileName"`
+	VerifyClient     string       `json:"verifyClient"`
+	VerifyDepth      *int         `json:"verifyDepth"`
 }
 
 // TransportServerListener defines a listener for a TransportServer.
@@ -483,6 +577,7 @@ type TransportServerUpstream struct {
 	LoadBalancingMethod string                      `json:"loadBalancingMethod"`
 	Backup              string                      `json:"backup"`
 	BackupPort          *uint16                     `json:"backupPort"`
+	TLS                 UpstreamTLS                 `json:"tls"`
 }
 
 // TransportServerHealthCheck defines the parameters for active Upstream HealthChecks.
@@ -526,9 +621,10 @@ type TransportServerAction struct {
 
 // TransportServerStatus defines the status for the TransportServer resource.
 type TransportServerStatus struct {
-	State   string `json:"state"`
-	Reason  string `json:"reason"`
-	Message string `json:"message"`
+	State             string             `json:"state"`
+	Reason            string             `json:"reason"`
+	Message           string             `json:"message"`
+	ExternalEndpoints []ExternalEndpoint `json:"externalEndpoints,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -550,7 +646,7 @@ type TransportServerList struct {
 // +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`,description="Current state of the Policy. If the resource has a valid status, it means it has been validated and accepted by the Ingress Controller."
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
-// Policy defines a Policy for VirtualServer and VirtualServerRoute resources.
+// Policy defines a Policy for VirtualServer, VirtualServerRoute and TransportServer resources.
 type Policy struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -570,16 +666,22 @@ type PolicyStatus struct {
 // The spec includes multiple fields, where each field represents a different policy.
 // Only one policy (field) is allowed.
 type PolicySpec struct {
-	IngressClass  string         `json:"ingressClassName"`
-	AccessControl *AccessControl `json:"accessControl"`
-	RateLimit     *RateLimit     `json:"rateLimit"`
-	JWTAuth       *JWTAuth       `json:"jwt"`
-	BasicAuth     *BasicAuth     `json:"basicAuth"`
-	IngressMTLS   *IngressMTLS   `json:"ingressMTLS"`
-	EgressMTLS    *EgressMTLS    `json:"egressMTLS"`
-	OIDC          *OIDC          `json:"oidc"`
-	WAF           *WAF           `json:"waf"`
-	APIKey        *APIKey        `json:"apiKey"`
+	IngressClass    string           `json:"ingressClassName"`
+	AccessControl   *AccessControl   `json:"accessControl"`
+	RateLimit       *RateLimit       `json:"rateLimit"`
+	JWTAuth         *JWTAuth         `json:"jwt"`
+	BasicAuth       *BasicAuth       `json:"basicAuth"`
+	IngressMTLS     *IngressMTLS     `json:"ingressMTLS"`
+	EgressMTLS      *EgressMTLS      `json:"egressMTLS"`
+	OIDC            *OIDC            `json:"oidc"`
+	WAF             *WAF             `json:"waf"`
+	APIKey          *APIKey          `json:"apiKey"`
+	Retry           *Retry           `json:"retry"`
+	CORS            *CORS            `json:"cors"`
+	ExternalAuth    *ExternalAuth    `json:"externalAuth"`
+	Cache           *Cache           `json:"cache"`
+	ConnectionLimit *ConnectionLimit `json:"connectionLimit"`
+	Bandwidth       *Bandwidth       `json:"bandwidth"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -598,6 +700,20 @@ type AccessControl struct {
 	Deny  []string `json:"deny"`
 }
 
+// ConnectionLimit defines a policy that limits the number of simultaneous connections from a single client address.
+// The policy is only supported for TransportServers.
+type ConnectionLimit struct {
+	Connections int    `json:"connections"`
+	ZoneSize    string `json:"zoneSize"`
+}
+
+// Bandwidth defines a policy that limits the rate at which data is read from the client and from the upstream server
+// within a connection. The policy is only supported for TransportServers.
+type Bandwidth struct {
+	UploadRate   string `json:"uploadRate"`
+	DownloadRate string `json:"downloadRate"`
+}
+
 // RateLimit defines a rate limit policy.
 type RateLimit struct {
 	Rate       string `json:"rate"`
@@ -687,6 +803,67 @@ type APIKey struct {
 	ClientSecret string      `json:"clientSecret"`
 }
 
+// Retry defines a Retry policy.
+type Retry struct {
+	RetryOn       []string `json:"retryOn"`
+	Tries         *int     `json:"tries"`
+	PerTryTimeout string   `json:"perTryTimeout"`
+	Timeout       string   `json:"timeout"`
+	Sampling      *int     `json:"sampling"`
+}
+
+// CORS defines a Cross-Origin Resource Sharing policy.
+type CORS struct {
+	AllowOrigins     []string `json:"allowOrigins"`
+	AllowMethods     []string `json:"allowMethods"`
+	AllowHeaders     []string `json:"allowHeaders"`
+	ExposeHeaders    []string `json:"exposeHeaders"`
+	AllowCredentials *bool    `json:"allowCredentials"`
+	MaxAge           *int     `json:"maxAge"`
+}
+
+// ExternalAuth defines an External Authorization policy.
+type ExternalAuth struct {
+	Service         *ExternalAuthService `json:"service"`
+	URL             string               `json:"url"`
+	ForwardHeaders  []string             `json:"forwardHeaders"`
+	ResponseHeaders []string             `json:"responseHeaders"`
+	CacheTTL        string               `json:"cacheTTL"`
+	SkipPaths       []string             `json:"skipPaths"`
+}
+
+// ExternalAuthService defines a Service that authorizes requests in an External Authorization policy.
+type ExternalAuthService struct {
+	Name string `json:"name"`
+	Port int    `json:"port"`
+	Path string `json:"path"`
+}
+
+// Cache defines a response caching policy.
+type Cache struct {
+	Size     string       `json:"size"`
+	MaxSize  string       `json:"maxSize"`
+	Inactive string       `json:"inactive"`
+	Key      string       `json:"key"`
+	Valid    []CacheValid `json:"valid"`
+	Lock     *CacheLock   `json:"lock"`
+	UseStale []string     `json:"useStale"`
+	Bypass   []string     `json:"bypass"`
+}
+
+// CacheValid defines the caching time for responses with the specified status codes.
+type CacheValid struct {
+	Codes []int  `json:"codes"`
+	Time  string `json:"time"`
+}
+
+// CacheLock defines the locking of a cache element while it is being populated.
+type CacheLock struct {
+	Enable  bool   `json:"enable"`
+	Timeout string `json:"timeout"`
+	Age     string `json:"age"`
+}
+
 // SuppliedIn defines the locations API Key should be supplied in.
 type SuppliedIn struct {
 	Header []string `json:"header"`
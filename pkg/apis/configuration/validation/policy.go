@@ -5,11 +5,14 @@ import (
 	"net"
 	"net/url"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"unicode"
 
+	"github.com/dlclark/regexp2"
 	v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
@@ -77,6 +80,36 @@ func validatePolicySpec(spec *v1.PolicySpec, fieldPath *field.Path, isPlus, enab
 		fieldCount++
 	}
 
+	if spec.Retry != nil {
+		allErrs = append(allErrs, validateRetry(spec.Retry, fieldPath.Child("retry"))...)
+		fieldCount++
+	}
+
+	if spec.CORS != nil {
+		allErrs = append(allErrs, validateCORS(spec.CORS, fieldPath.Child("cors"))...)
+		fieldCount++
+	}
+
+	if spec.ExternalAuth != nil {
+		allErrs = append(allErrs, validateExternalAuth(spec.ExternalAuth, fieldPath.Child("externalAuth"))...)
+		fieldCount++
+	}
+
+	if spec.Cache != nil {
+		allErrs = append(allErrs, validateCache(spec.Cache, fieldPath.Child("cache"), isPlus)...)
+		fieldCount++
+	}
+
+	if spec.ConnectionLimit != nil {
+		allErrs = append(allErrs, validateConnectionLimit(spec.ConnectionLimit, fieldPath.Child("connectionLimit"))...)
+		fieldCount++
+	}
+
+	if spec.Bandwidth != nil {
+		allErrs = append(allErrs, validateBandwidth(spec.Bandwidth, fieldPath.Child("bandwidth"))...)
+		fieldCount++
+	}
+
 	if spec.WAF != nil {
 		if !isPlus {
 			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("waf"), "WAF is only supported in NGINX Plus"))
@@ -91,7 +124,7 @@ func validatePolicySpec(spec *v1.PolicySpec, fieldPath *field.Path, isPlus, enab
 	}
 
 	if fieldCount != 1 {
-		msg := "must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `basicAuth`, `apiKey`"
+		msg := "must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `basicAuth`, `apiKey`, `retry`, `cors`, `externalAuth`, `cache`, `connectionLimit`, `bandwidth`"
 		if isPlus {
 			msg = fmt.Sprint(msg, ", `jwt`, `oidc`, `waf`")
 		}
@@ -154,6 +187,25 @@ func validateRateLimit(rateLimit *v1.RateLimit, fieldPath *field.Path, isPlus bo
 	return allErrs
 }
 
+func validateConnectionLimit(connectionLimit *v1.ConnectionLimit, fieldPath *field.Path) field.ErrorList {
+	allErrs := validatePositiveInt(connectionLimit.Connections, fieldPath.Child("connections"))
+
+	if connectionLimit.ZoneSize != "" {
+		allErrs = append(allErrs, validateRateLimitZoneSize(connectionLimit.ZoneSize, fieldPath.Child("zoneSize"))...)
+	}
+
+	return allErrs
+}
+
+func validateBandwidth(bandwidth *v1.Bandwidth, fieldPath *field.Path) field.ErrorList {
+	if bandwidth.UploadRate == "" && bandwidth.DownloadRate == "" {
+		return field.ErrorList{field.Required(fieldPath, "must specify at least one of: `uploadRate` or `downloadRate`")}
+	}
+
+	allErrs := validateSize(bandwidth.UploadRate, fieldPath.Child("uploadRate"))
+	return append(allErrs, validateSize(bandwidth.DownloadRate, fieldPath.Child("downloadRate"))...)
+}
+
 // validateJWT validates JWT Policy according the rules specified in documentation
 // for using [jwt] local k8s secrets and using [jwks] from remote location.
 //
@@ -292,6 +344,283 @@ func validateOIDC(oidc *v1.OIDC, fieldPath *field.Path) field.ErrorList {
 	return append(allErrs, validateClientID(oidc.ClientID, fieldPath.Child("clientID"))...)
 }
 
+func validateRetry(retry *v1.Retry, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	retryOnPath := fieldPath.Child("retryOn")
+	retryOn := sets.Set[string]{}
+	for i, condition := range retry.RetryOn {
+		if !slices.Contains(retryOnConditions, condition) {
+			allErrs = append(allErrs, field.NotSupported(retryOnPath.Index(i), condition, retryOnConditions))
+		} else if retryOn.Has(condition) {
+			allErrs = append(allErrs, field.Duplicate(retryOnPath.Index(i), condition))
+		}
+		retryOn.Insert(condition)
+	}
+
+	if retry.Tries != nil {
+		allErrs = append(allErrs, validatePositiveIntOrZero(*retry.Tries, fieldPath.Child("tries"))...)
+	}
+
+	if retry.Sampling != nil {
+		for _, msg := range validation.IsInRange(*retry.Sampling, 0, 100) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("sampling"), *retry.Sampling, msg))
+		}
+	}
+
+	allErrs = append(allErrs, validateTime(retry.PerTryTimeout, fieldPath.Child("perTryTimeout"))...)
+	return append(allErrs, validateTime(retry.Timeout, fieldPath.Child("timeout"))...)
+}
+
+var retryOnConditions = []string{
+	"error",
+	"timeout",
+	"invalid_header",
+	"http_500",
+	"http_502",
+	"http_503",
+	"http_504",
+	"http_403",
+	"http_404",
+	"http_429",
+	"non_idempotent",
+}
+
+func validateCORS(cors *v1.CORS, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	originsPath := fieldPath.Child("allowOrigins")
+	if len(cors.AllowOrigins) == 0 {
+		allErrs = append(allErrs, field.Required(originsPath, "at least one origin must be provided"))
+	}
+	for i, origin := range cors.AllowOrigins {
+		allErrs = append(allErrs, validateCORSOrigin(origin, originsPath.Index(i))...)
+	}
+
+	for i, method := range cors.AllowMethods {
+		if !corsMethodRegexp.MatchString(method) {
+			msg := validation.RegexError("must consist of upper case alphabetic characters", corsMethodFmt, "GET", "POST")
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("allowMethods").Index(i), method, msg))
+		}
+	}
+
+	allErrs = append(allErrs, validateCORSHeaders(cors.AllowHeaders, fieldPath.Child("allowHeaders"))...)
+	allErrs = append(allErrs, validateCORSHeaders(cors.ExposeHeaders, fieldPath.Child("exposeHeaders"))...)
+
+	if cors.MaxAge != nil {
+		allErrs = append(allErrs, validatePositiveIntOrZero(*cors.MaxAge, fieldPath.Child("maxAge"))...)
+	}
+
+	return allErrs
+}
+
+const corsMethodFmt = `[A-Z]+`
+
+var corsMethodRegexp = regexp.MustCompile("^" + corsMethodFmt + "$")
+
+// validateCORSOrigin validates an allowed origin of a CORS policy, which can be `*`, a regular expression
+// that starts with `~` or `~*`, or an origin with an optional wildcard subdomain like `https://*.example.com`.
+func validateCORSOrigin(origin string, fieldPath *field.Path) field.ErrorList {
+	if origin == "*" {
+		return nil
+	}
+
+	if strings.HasPrefix(origin, "~") {
+		regex := strings.TrimPrefix(strings.TrimPrefix(origin, "~"), "*")
+		if _, err := regexp2.Compile(regex, 0); err != nil {
+			return field.ErrorList{field.Invalid(fieldPath, origin, fmt.Sprintf("must be a valid regular expression: %v", err))}
+		}
+		if err := ValidateEscapedString(origin, `~^https://.*\.example\.com$`); err != nil {
+			return field.ErrorList{field.Invalid(fieldPath, origin, err.Error())}
+		}
+		return nil
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fieldPath, origin, err.Error())}
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return field.ErrorList{field.Invalid(fieldPath, origin, "scheme required, please use the prefix http(s)://")}
+	}
+	if u.Host == "" {
+		return field.ErrorList{field.Invalid(fieldPath, origin, "hostname required")}
+	}
+	if u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return field.ErrorList{field.Invalid(fieldPath, origin, "must only consist of a scheme, a host and an optional port")}
+	}
+
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		host = u.Host
+	}
+
+	allErrs := validateSSLName(strings.TrimPrefix(host, "*."), fieldPath)
+	if port != "" {
+		allErrs = append(allErrs, validatePortNumber(port, fieldPath)...)
+	}
+	return allErrs
+}
+
+func validateCORSHeaders(headers []string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, header := range headers {
+		if header == "*" {
+			continue
+		}
+		for _, msg := range validation.IsHTTPHeaderName(header) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Index(i), header, msg))
+		}
+	}
+	return allErrs
+}
+
+func validateExternalAuth(externalAuth *v1.ExternalAuth, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch {
+	case externalAuth.Service != nil && externalAuth.URL != "":
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify exactly one of: `service` or `url`"))
+	case externalAuth.Service != nil:
+		allErrs = append(allErrs, validateExternalAuthService(externalAuth.Service, fieldPath.Child("service"))...)
+	case externalAuth.URL != "":
+		allErrs = append(allErrs, validateURL(externalAuth.URL, fieldPath.Child("url"))...)
+		if strings.ContainsAny(externalAuth.URL, "$\"") {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("url"), externalAuth.URL, "must not include `$` or `\"`"))
+		}
+	default:
+		allErrs = append(allErrs, field.Required(fieldPath, "must specify exactly one of: `service` or `url`"))
+	}
+
+	for i, header := range externalAuth.ForwardHeaders {
+		for _, msg := range validation.IsHTTPHeaderName(header) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("forwardHeaders").Index(i), header, msg))
+		}
+	}
+	for i, header := range externalAuth.ResponseHeaders {
+		for _, msg := range validation.IsHTTPHeaderName(header) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("responseHeaders").Index(i), header, msg))
+		}
+	}
+
+	if externalAuth.CacheTTL != "" {
+		allErrs = append(allErrs, validateTime(externalAuth.CacheTTL, fieldPath.Child("cacheTTL"))...)
+		if len(externalAuth.ForwardHeaders) == 0 {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("cacheTTL"), "can only be used together with `forwardHeaders`"))
+		}
+	}
+
+	for i, path := range externalAuth.SkipPaths {
+		allErrs = append(allErrs, validateExternalAuthPath(path, fieldPath.Child("skipPaths").Index(i))...)
+	}
+
+	return allErrs
+}
+
+func validateExternalAuthService(service *v1.ExternalAuthService, fieldPath *field.Path) field.ErrorList {
+	allErrs := validateServiceName(service.Name, fieldPath.Child("name"))
+	for _, msg := range validation.IsValidPortNum(service.Port) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("port"), service.Port, msg))
+	}
+	if service.Path != "" {
+		allErrs = append(allErrs, validateExternalAuthPath(service.Path, fieldPath.Child("path"))...)
+	}
+	return allErrs
+}
+
+func validateExternalAuthPath(path string, fieldPath *field.Path) field.ErrorList {
+	allErrs := validatePath(path, fieldPath)
+	if strings.ContainsAny(path, "$\"") {
+		allErrs = append(allErrs, field.Invalid(fieldPath, path, "must not include `$` or `\"`"))
+	}
+	return allErrs
+}
+
+func validateCache(cache *v1.Cache, fieldPath *field.Path, isPlus bool) field.ErrorList {
+	allErrs := validateSize(cache.Size, fieldPath.Child("size"))
+	allErrs = append(allErrs, validateOffset(cache.MaxSize, fieldPath.Child("maxSize"))...)
+	allErrs = append(allErrs, validateTime(cache.Inactive, fieldPath.Child("inactive"))...)
+
+	if cache.Key != "" {
+		allErrs = append(allErrs, validateCacheString(cache.Key, fieldPath.Child("key"), isPlus)...)
+	}
+
+	validPath := fieldPath.Child("valid")
+	for i, valid := range cache.Valid {
+		if valid.Time == "" {
+			allErrs = append(allErrs, field.Required(validPath.Index(i).Child("time"), ""))
+		} else {
+			allErrs = append(allErrs, validateTime(valid.Time, validPath.Index(i).Child("time"))...)
+		}
+		for j, code := range valid.Codes {
+			for _, msg := range validation.IsInRange(code, 100, 599) {
+				allErrs = append(allErrs, field.Invalid(validPath.Index(i).Child("codes").Index(j), code, msg))
+			}
+		}
+	}
+
+	if cache.Lock != nil {
+		allErrs = append(allErrs, validateTime(cache.Lock.Timeout, fieldPath.Child("lock", "timeout"))...)
+		allErrs = append(allErrs, validateTime(cache.Lock.Age, fieldPath.Child("lock", "age"))...)
+	}
+
+	useStalePath := fieldPath.Child("useStale")
+	useStale := sets.Set[string]{}
+	for i, condition := range cache.UseStale {
+		if !slices.Contains(cacheUseStaleConditions, condition) {
+			allErrs = append(allErrs, field.NotSupported(useStalePath.Index(i), condition, cacheUseStaleConditions))
+		} else if useStale.Has(condition) {
+			allErrs = append(allErrs, field.Duplicate(useStalePath.Index(i), condition))
+		}
+		useStale.Insert(condition)
+	}
+
+	for i, bypass := range cache.Bypass {
+		if bypass == "" {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("bypass").Index(i), ""))
+			continue
+		}
+		allErrs = append(allErrs, validateCacheString(bypass, fieldPath.Child("bypass").Index(i), isPlus)...)
+	}
+
+	return allErrs
+}
+
+var cacheUseStaleConditions = []string{
+	"error",
+	"timeout",
+	"invalid_header",
+	"updating",
+	"http_500",
+	"http_502",
+	"http_503",
+	"http_504",
+	"http_403",
+	"http_404",
+	"http_429",
+}
+
+var cacheSpecialVariables = []string{"arg_", "http_", "cookie_"}
+
+// cacheVariables includes NGINX variables allowed to be used in the key and the bypass conditions of a cache policy.
+var cacheVariables = map[string]bool{
+	"scheme":         true,
+	"host":           true,
+	"proxy_host":     true,
+	"request_method": true,
+	"request_uri":    true,
+	"uri":            true,
+	"args":           true,
+}
+
+func validateCacheString(str string, fieldPath *field.Path, isPlus bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if err := ValidateEscapedString(str, `${scheme}${host}${request_uri}`, `${cookie_nocache}`); err != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath, str, err.Error()))
+	}
+	return append(allErrs, validateStringWithVariables(str, fieldPath, cacheSpecialVariables, cacheVariables, isPlus)...)
+}
+
 func validateAPIKey(apiKey *v1.APIKey, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -565,6 +565,126 @@ func TestValidateAccessControl_FailsOnInvalidInput(t *testing.T) {
 	}
 }
 
+func TestValidateConnectionLimit_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	validInput := []*v1.ConnectionLimit{
+		{
+			Connections: 1,
+		},
+		{
+			Connections: 100,
+			ZoneSize:    "32k",
+		},
+		{
+			Connections: 10,
+			ZoneSize:    "10m",
+		},
+	}
+
+	for _, input := range validInput {
+		allErrs := validateConnectionLimit(input, field.NewPath("connectionLimit"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateConnectionLimit(%+v) returned errors %v for valid input", input, allErrs)
+		}
+	}
+}
+
+func TestValidateConnectionLimit_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		connectionLimit *v1.ConnectionLimit
+		msg             string
+	}{
+		{
+			connectionLimit: &v1.ConnectionLimit{},
+			msg:             "connections is not defined",
+		},
+		{
+			connectionLimit: &v1.ConnectionLimit{
+				Connections: -1,
+			},
+			msg: "negative connections",
+		},
+		{
+			connectionLimit: &v1.ConnectionLimit{
+				Connections: 10,
+				ZoneSize:    "16k",
+			},
+			msg: "zone size too small",
+		},
+		{
+			connectionLimit: &v1.ConnectionLimit{
+				Connections: 10,
+				ZoneSize:    "10x",
+			},
+			msg: "invalid zone size",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateConnectionLimit(test.connectionLimit, field.NewPath("connectionLimit"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateConnectionLimit() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateBandwidth_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	validInput := []*v1.Bandwidth{
+		{
+			UploadRate: "100k",
+		},
+		{
+			DownloadRate: "1m",
+		},
+		{
+			UploadRate:   "0",
+			DownloadRate: "512",
+		},
+	}
+
+	for _, input := range validInput {
+		allErrs := validateBandwidth(input, field.NewPath("bandwidth"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateBandwidth(%+v) returned errors %v for valid input", input, allErrs)
+		}
+	}
+}
+
+func TestValidateBandwidth_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		bandwidth *v1.Bandwidth
+		msg       string
+	}{
+		{
+			bandwidth: &v1.Bandwidth{},
+			msg:       "neither uploadRate nor downloadRate is defined",
+		},
+		{
+			bandwidth: &v1.Bandwidth{
+				UploadRate: "fast",
+			},
+			msg: "invalid uploadRate",
+		},
+		{
+			bandwidth: &v1.Bandwidth{
+				UploadRate:   "1m",
+				DownloadRate: "1mb",
+			},
+			msg: "invalid downloadRate",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateBandwidth(test.bandwidth, field.NewPath("bandwidth"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateBandwidth() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidateRateLimit_PassesOnValidInput(t *testing.T) {
 	t.Parallel()
 	dryRun := true
@@ -2176,3 +2296,494 @@ func TestValidateWAF_FailsOnInvalidApLogBundle(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRetryPolicy_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		retry *v1.Retry
+		msg   string
+	}{
+		{
+			retry: &v1.Retry{},
+			msg:   "empty retry",
+		},
+		{
+			retry: &v1.Retry{
+				RetryOn:       []string{"error", "timeout", "http_503", "non_idempotent"},
+				Tries:         createPointerFromInt(3),
+				PerTryTimeout: "5s",
+				Timeout:       "1m",
+			},
+			msg: "all fields set",
+		},
+		{
+			retry: &v1.Retry{
+				Tries: createPointerFromInt(0),
+			},
+			msg: "unlimited tries",
+		},
+		{
+			retry: &v1.Retry{
+				Sampling: createPointerFromInt(20),
+			},
+			msg: "sampling",
+		},
+		{
+			retry: &v1.Retry{
+				Sampling: createPointerFromInt(0),
+			},
+			msg: "zero sampling",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateRetry(test.retry, field.NewPath("retry"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateRetry() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateRetryPolicy_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		retry *v1.Retry
+		msg   string
+	}{
+		{
+			retry: &v1.Retry{
+				RetryOn: []string{"error", "http_418"},
+			},
+			msg: "invalid retry condition",
+		},
+		{
+			retry: &v1.Retry{
+				RetryOn: []string{"off"},
+			},
+			msg: "off is not a retry condition",
+		},
+		{
+			retry: &v1.Retry{
+				RetryOn: []string{"error", "error"},
+			},
+			msg: "duplicate retry condition",
+		},
+		{
+			retry: &v1.Retry{
+				Tries: createPointerFromInt(-1),
+			},
+			msg: "negative tries",
+		},
+		{
+			retry: &v1.Retry{
+				PerTryTimeout: "5 seconds",
+			},
+			msg: "invalid per try timeout",
+		},
+		{
+			retry: &v1.Retry{
+				Timeout: "-1s",
+			},
+			msg: "invalid timeout",
+		},
+		{
+			retry: &v1.Retry{
+				Sampling: createPointerFromInt(-1),
+			},
+			msg: "negative sampling",
+		},
+		{
+			retry: &v1.Retry{
+				Sampling: createPointerFromInt(101),
+			},
+			msg: "sampling above 100",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateRetry(test.retry, field.NewPath("retry"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateRetry() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateCORSPolicy_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		cors *v1.CORS
+		msg  string
+	}{
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"*"},
+			},
+			msg: "any origin",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins:     []string{"https://example.com", "http://localhost:8080", "https://*.example.com", `~^https://(foo|bar)\.example\.org$`},
+				AllowMethods:     []string{"GET", "POST", "PUT"},
+				AllowHeaders:     []string{"Content-Type", "Authorization"},
+				ExposeHeaders:    []string{"*"},
+				AllowCredentials: createPointerFromBool(true),
+				MaxAge:           createPointerFromInt(3600),
+			},
+			msg: "all fields set",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateCORS(test.cors, field.NewPath("cors"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateCORS() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateCORSPolicy_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		cors *v1.CORS
+		msg  string
+	}{
+		{
+			cors: &v1.CORS{},
+			msg:  "no origins",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"example.com"},
+			},
+			msg: "origin without scheme",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"https://example.com/path"},
+			},
+			msg: "origin with path",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"https://"},
+			},
+			msg: "origin without host",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"https://foo.*.example.com"},
+			},
+			msg: "origin with wildcard not in the first label",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"~^https://(foo.example.com$"},
+			},
+			msg: "origin with invalid regex",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{`~^https://"foo".example.com$`},
+			},
+			msg: "origin with unescaped double quotes",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"https://example.com"},
+				AllowMethods: []string{"get"},
+			},
+			msg: "lower case method",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"https://example.com"},
+				AllowHeaders: []string{"Content Type"},
+			},
+			msg: "invalid allowed header",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins:  []string{"https://example.com"},
+				ExposeHeaders: []string{`X-"Version"`},
+			},
+			msg: "invalid exposed header",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"https://example.com"},
+				MaxAge:       createPointerFromInt(-1),
+			},
+			msg: "negative max age",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateCORS(test.cors, field.NewPath("cors"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateCORS() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateExternalAuthPolicy_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		externalAuth *v1.ExternalAuth
+		msg          string
+	}{
+		{
+			externalAuth: &v1.ExternalAuth{
+				URL: "https://authz.example.com/check",
+			},
+			msg: "url",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				Service: &v1.ExternalAuthService{
+					Name: "authz",
+					Port: 8080,
+					Path: "/check",
+				},
+				ForwardHeaders:  []string{"Authorization", "Cookie"},
+				ResponseHeaders: []string{"X-User-ID"},
+				CacheTTL:        "30s",
+				SkipPaths:       []string{"/public", "/healthz"},
+			},
+			msg: "service with all fields set",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateExternalAuth(test.externalAuth, field.NewPath("externalAuth"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateExternalAuth() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateExternalAuthPolicy_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		externalAuth *v1.ExternalAuth
+		msg          string
+	}{
+		{
+			externalAuth: &v1.ExternalAuth{},
+			msg:          "no service or url",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				Service: &v1.ExternalAuthService{Name: "authz", Port: 8080},
+				URL:     "https://authz.example.com/check",
+			},
+			msg: "both service and url",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				URL: "authz.example.com/check",
+			},
+			msg: "url without scheme",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				URL: "https://authz.example.com/check$uri",
+			},
+			msg: "url with a variable",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				Service: &v1.ExternalAuthService{Name: "authz_svc", Port: 8080},
+			},
+			msg: "invalid service name",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				Service: &v1.ExternalAuthService{Name: "authz"},
+			},
+			msg: "missing service port",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				Service: &v1.ExternalAuthService{Name: "authz", Port: 8080, Path: "check"},
+			},
+			msg: "invalid service path",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				URL:            "https://authz.example.com/check",
+				ForwardHeaders: []string{"Bad Header"},
+			},
+			msg: "invalid forward header",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				URL:             "https://authz.example.com/check",
+				ResponseHeaders: []string{"Bad Header"},
+			},
+			msg: "invalid response header",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				URL:      "https://authz.example.com/check",
+				CacheTTL: "30s",
+			},
+			msg: "cache ttl without forward headers",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				URL:            "https://authz.example.com/check",
+				ForwardHeaders: []string{"Authorization"},
+				CacheTTL:       "30 seconds",
+			},
+			msg: "invalid cache ttl",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				URL:       "https://authz.example.com/check",
+				SkipPaths: []string{`/public"`},
+			},
+			msg: "invalid skip path",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateExternalAuth(test.externalAuth, field.NewPath("externalAuth"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateExternalAuth() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateCachePolicy_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		cache *v1.Cache
+		msg   string
+	}{
+		{
+			cache: &v1.Cache{},
+			msg:   "empty cache",
+		},
+		{
+			cache: &v1.Cache{
+				Size:     "10m",
+				MaxSize:  "1g",
+				Inactive: "1h",
+				Key:      "${scheme}${host}${request_uri}",
+				Valid: []v1.CacheValid{
+					{Codes: []int{200, 302}, Time: "10m"},
+					{Time: "1m"},
+				},
+				Lock: &v1.CacheLock{
+					Enable:  true,
+					Timeout: "5s",
+					Age:     "5s",
+				},
+				UseStale: []string{"error", "timeout", "updating", "http_503"},
+				Bypass:   []string{"${cookie_nocache}", "${arg_nocache}${http_pragma}"},
+			},
+			msg: "all fields set",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateCache(test.cache, field.NewPath("cache"), false)
+		if len(allErrs) != 0 {
+			t.Errorf("validateCache() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateCachePolicy_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		cache *v1.Cache
+		msg   string
+	}{
+		{
+			cache: &v1.Cache{
+				Size: "10mb",
+			},
+			msg: "invalid size",
+		},
+		{
+			cache: &v1.Cache{
+				MaxSize: "1t",
+			},
+			msg: "invalid max size",
+		},
+		{
+			cache: &v1.Cache{
+				Inactive: "1 hour",
+			},
+			msg: "invalid inactive time",
+		},
+		{
+			cache: &v1.Cache{
+				Key: "$request_uri",
+			},
+			msg: "variable without curly braces in key",
+		},
+		{
+			cache: &v1.Cache{
+				Key: "${remote_user}",
+			},
+			msg: "unsupported variable in key",
+		},
+		{
+			cache: &v1.Cache{
+				Key: `"${request_uri}`,
+			},
+			msg: "unescaped quote in key",
+		},
+		{
+			cache: &v1.Cache{
+				Valid: []v1.CacheValid{{Codes: []int{200}}},
+			},
+			msg: "missing valid time",
+		},
+		{
+			cache: &v1.Cache{
+				Valid: []v1.CacheValid{{Codes: []int{600}, Time: "1m"}},
+			},
+			msg: "invalid status code",
+		},
+		{
+			cache: &v1.Cache{
+				Lock: &v1.CacheLock{Enable: true, Timeout: "five seconds"},
+			},
+			msg: "invalid lock timeout",
+		},
+		{
+			cache: &v1.Cache{
+				UseStale: []string{"http_418"},
+			},
+			msg: "invalid use stale condition",
+		},
+		{
+			cache: &v1.Cache{
+				UseStale: []string{"updating", "updating"},
+			},
+			msg: "duplicate use stale condition",
+		},
+		{
+			cache: &v1.Cache{
+				Bypass: []string{""},
+			},
+			msg: "empty bypass condition",
+		},
+		{
+			cache: &v1.Cache{
+				Bypass: []string{"${jwt_claim_sub}"},
+			},
+			msg: "unsupported variable in bypass condition",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateCache(test.cache, field.NewPath("cache"), false)
+		if len(allErrs) == 0 {
+			t.Errorf("validateCache() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
@@ -14,27 +14,31 @@ import (
 
 // TransportServerValidator validates a TransportServer resource.
 type TransportServerValidator struct {
-	tlsPassthrough  bool
-	snippetsEnabled bool
-	isPlus          bool
+	tlsPassthrough       bool
+	snippetsEnabled      bool
+	isPlus               bool
+	isCertManagerEnabled bool
+	isExternalDNSEnabled bool
 }
 
 // NewTransportServerValidator creates a new TransportServerValidator.
-func NewTransportServerValidator(tlsPassthrough bool, snippetsEnabled bool, isPlus bool) *TransportServerValidator {
+func NewTransportServerValidator(tlsPassthrough bool, snippetsEnabled bool, isPlus bool, isCertManagerEnabled bool, isExternalDNSEnabled bool) *TransportServerValidator {
 	return &TransportServerValidator{
-		tlsPassthrough:  tlsPassthrough,
-		snippetsEnabled: snippetsEnabled,
-		isPlus:          isPlus,
+		tlsPassthrough:       tlsPassthrough,
+		snippetsEnabled:      snippetsEnabled,
+		isPlus:               isPlus,
+		isCertManagerEnabled: isCertManagerEnabled,
+		isExternalDNSEnabled: isExternalDNSEnabled,
 	}
 }
 
 // ValidateTransportServer validates a TransportServer.
 func (tsv *TransportServerValidator) ValidateTransportServer(transportServer *conf_v1.TransportServer) error {
-	allErrs := tsv.validateTransportServerSpec(&transportServer.Spec, field.NewPath("spec"))
+	allErrs := tsv.validateTransportServerSpec(&transportServer.Spec, field.NewPath("spec"), transportServer.Namespace)
 	return allErrs.ToAggregate()
 }
 
-func (tsv *TransportServerValidator) validateTransportServerSpec(spec *conf_v1.TransportServerSpec, fieldPath *field.Path) field.ErrorList {
+func (tsv *TransportServerValidator) validateTransportServerSpec(spec *conf_v1.TransportServerSpec, fieldPath *field.Path, namespace string) field.ErrorList {
 	allErrs := tsv.validateTransportListener(&spec.Listener, fieldPath.Child("listener"))
 
 	isTLSPassthroughListener := isPotentialTLSPassthroughListener(&spec.Listener)
@@ -43,6 +47,8 @@ func (tsv *TransportServerValidator) validateTransportServerSpec(spec *conf_v1.T
 	upstreamErrs, upstreamNames := validateTransportServerUpstreams(spec.Upstreams, fieldPath.Child("upstreams"), tsv.isPlus)
 	allErrs = append(allErrs, upstreamErrs...)
 
+	allErrs = append(allErrs, validateTransportServerUpstreamsTLS(spec.Upstreams, fieldPath.Child("upstreams"), isTLSPassthroughListener, spec.Listener.Protocol)...)
+
 	allErrs = append(allErrs, validateTransportServerUpstreamParameters(spec.UpstreamParameters, fieldPath.Child("upstreamParameters"), spec.Listener.Protocol)...)
 
 	allErrs = append(allErrs, validateSessionParameters(spec.SessionParameters, fieldPath.Child("sessionParameters"))...)
@@ -60,6 +66,14 @@ func (tsv *TransportServerValidator) validateTransportServerSpec(spec *conf_v1.T
 	hostSpecified := spec.Host != ""
 	allErrs = append(allErrs, validateTLS(spec.TLS, isTLSPassthroughListener, fieldPath.Child("tls"), hostSpecified)...)
 
+	if spec.TLS != nil && spec.TLS.CertManager != nil {
+		allErrs = append(allErrs, tsv.validateTLSCertManager(spec.TLS, fieldPath.Child("tls", "cert-manager"), hostSpecified)...)
+	}
+
+	allErrs = append(allErrs, tsv.validateExternalDNS(&spec.ExternalDNS, fieldPath.Child("externalDNS"), hostSpecified)...)
+
+	allErrs = append(allErrs, validatePolicies(spec.Policies, fieldPath.Child("policies"), namespace)...)
+
 	return allErrs
 }
 
@@ -76,17 +90,92 @@ func validateTLS(
 		return nil
 	}
 
-	if hostSpecified {
-		if tls == nil || tls.Secret == "" {
-			return field.ErrorList{field.Required(fieldPath, "must specify spec.tls.secret when host is specified, and the TransportServer is not using the TLS Passthrough listener")}
+	if hostSpecified && (tls == nil || tls.Secret == "") {
+		return field.ErrorList{field.Required(fieldPath, "must specify spec.tls.secret when host is specified, and the TransportServer is not using the TLS Passthrough listener")}
+	}
+
+	if tls == nil {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+	if tls.Secret != "" {
+		allErrs = append(allErrs, validateSecretName(tls.Secret, fieldPath.Child("secret"))...)
+	}
+
+	return append(allErrs, validateTLSClientVerification(tls, fieldPath)...)
+}
+
+// validateTLSClientVerification validates the client certificate verification fields of a TransportServer.
+// The verification requires TLS termination, so the secret is required.
+func validateTLSClientVerification(tls *conf_v1.TransportServerTLS, fieldPath *field.Path) field.ErrorList {
+	if tls.ClientCertSecret == "" {
+		if tls.CrlFileName != "" || tls.VerifyClient != "" || tls.VerifyDepth != nil {
+			return field.ErrorList{field.Required(fieldPath.Child("clientCertSecret"), "must be set when crlFileName, verifyClient or verifyDepth is set")}
 		}
-		return validateSecretName(tls.Secret, fieldPath.Child("secret"))
+		return nil
 	}
 
-	if tls != nil && tls.Secret != "" {
-		return validateSecretName(tls.Secret, fieldPath.Child("secret"))
+	allErrs := field.ErrorList{}
+	if tls.Secret == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("secret"), "must be set when clientCertSecret is set"))
 	}
 
+	allErrs = append(allErrs, validateSecretName(tls.ClientCertSecret, fieldPath.Child("clientCertSecret"))...)
+	allErrs = append(allErrs, validateIngressMTLSVerifyClient(tls.VerifyClient, fieldPath.Child("verifyClient"))...)
+	allErrs = append(allErrs, validatePositiveIntOrZeroFromPointer(tls.VerifyDepth, fieldPath.Child("verifyDepth"))...)
+
+	if tls.CrlFileName != "" {
+		for _, msg := range validation.IsConfigMapKey(tls.CrlFileName) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("crlFileName"), tls.CrlFileName, msg))
+		}
+	}
+
+	return allErrs
+}
+
+// validateTransportServerUpstreamsTLS validates the TLS of the upstreams of a TransportServer.
+// NGINX can't re-encrypt the connections of the TLS Passthrough and UDP listeners.
+func validateTransportServerUpstreamsTLS(upstreams []conf_v1.TransportServerUpstream, fieldPath *field.Path, isTLSPassthroughListener bool, protocol string) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, u := range upstreams {
+		idxPath := fieldPath.Index(i).Child("tls")
+
+		if u.TLS.Enable && (isTLSPassthroughListener || protocol == "UDP") {
+			allErrs = append(allErrs, field.Forbidden(idxPath.Child("enable"), "TLS is not supported for the upstreams of TLS Passthrough and UDP TransportServers"))
+			continue
+		}
+
+		allErrs = append(allErrs, validateUpstreamTLS(u.TLS, idxPath)...)
+	}
+
+	return allErrs
+}
+
+// validateTLSCertManager validates the cert-manager fields of a TransportServer.
+// The host is required, because it is used as the DNS name of the certificate.
+func (tsv *TransportServerValidator) validateTLSCertManager(tls *conf_v1.TransportServerTLS, fieldPath *field.Path, hostSpecified bool) field.ErrorList {
+	allErrs := validateTLSCmFields(tls.CertManager, tsv.isCertManagerEnabled, tls.Secret, fieldPath)
+	if !hostSpecified {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "field requires spec.host to be specified"))
+	}
+	return allErrs
+}
+
+// validateExternalDNS validates the externalDNS field of a TransportServer.
+// The host is required, because it is used as the DNS name of the record.
+func (tsv *TransportServerValidator) validateExternalDNS(ed *conf_v1.ExternalDNS, fieldPath *field.Path, hostSpecified bool) field.ErrorList {
+	if ed == nil || !ed.Enable {
+		// valid, externalDNS is not required
+		return nil
+	}
+	if !tsv.isExternalDNSEnabled {
+		return field.ErrorList{field.Forbidden(fieldPath, "field requires externalDNS enablement")}
+	}
+	if !hostSpecified {
+		return field.ErrorList{field.Forbidden(fieldPath, "field requires spec.host to be specified")}
+	}
 	return nil
 }
 
@@ -131,6 +131,152 @@ func TestValidateTransportServer_FailsOnNotSupportedLBMethodForBackup(t *testing
 	}
 }
 
+func TestValidateTransportServer_Policies(t *testing.T) {
+	t.Parallel()
+
+	ts := makeTransportServer()
+	ts.Namespace = "default"
+	ts.Spec.Policies = []conf_v1.PolicyReference{
+		{
+			Name: "connection-limit",
+		},
+		{
+			Name:      "allow-list",
+			Namespace: "policies",
+		},
+	}
+
+	tsv := createTransportServerValidator()
+
+	err := tsv.ValidateTransportServer(&ts)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidateTransportServer_FailsOnInvalidPolicies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		policies []conf_v1.PolicyReference
+		msg      string
+	}{
+		{
+			policies: []conf_v1.PolicyReference{
+				{
+					Name: "",
+				},
+			},
+			msg: "missing name",
+		},
+		{
+			policies: []conf_v1.PolicyReference{
+				{
+					Name:      "connection-limit",
+					Namespace: "default",
+				},
+				{
+					Name: "connection-limit",
+				},
+			},
+			msg: "duplicate policy",
+		},
+	}
+
+	for _, test := range tests {
+		ts := makeTransportServer()
+		ts.Namespace = "default"
+		ts.Spec.Policies = test.policies
+
+		tsv := createTransportServerValidator()
+
+		err := tsv.ValidateTransportServer(&ts)
+		if err == nil {
+			t.Errorf("ValidateTransportServer() returned no error for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateTransportServerUpstreamsTLS(t *testing.T) {
+	t.Parallel()
+
+	upstreams := []conf_v1.TransportServerUpstream{
+		{
+			Name: "upstream1",
+			TLS: conf_v1.UpstreamTLS{
+				Enable:            true,
+				TrustedCertSecret: "my-ca-secret",
+				VerifyServer:      true,
+				SSLName:           "app.example.com",
+			},
+		},
+		{
+			Name: "upstream2",
+		},
+	}
+
+	allErrs := validateTransportServerUpstreamsTLS(upstreams, field.NewPath("upstreams"), false, "TCP")
+	if len(allErrs) > 0 {
+		t.Errorf("validateTransportServerUpstreamsTLS() returned errors %v for valid input", allErrs)
+	}
+}
+
+func TestValidateTransportServerUpstreamsTLS_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tls              conf_v1.UpstreamTLS
+		isTLSPassthrough bool
+		protocol         string
+		msg              string
+	}{
+		{
+			tls: conf_v1.UpstreamTLS{
+				Enable: true,
+			},
+			isTLSPassthrough: true,
+			protocol:         "TLS_PASSTHROUGH",
+			msg:              "TLS Passthrough listener",
+		},
+		{
+			tls: conf_v1.UpstreamTLS{
+				Enable: true,
+			},
+			protocol: "UDP",
+			msg:      "UDP listener",
+		},
+		{
+			tls: conf_v1.UpstreamTLS{
+				Enable:       true,
+				VerifyServer: true,
+			},
+			protocol: "TCP",
+			msg:      "missing trustedCertSecret",
+		},
+		{
+			tls: conf_v1.UpstreamTLS{
+				VerifyServer: true,
+			},
+			protocol: "TCP",
+			msg:      "TLS is not enabled",
+		},
+	}
+
+	for _, test := range tests {
+		upstreams := []conf_v1.TransportServerUpstream{
+			{
+				Name: "upstream1",
+				TLS:  test.tls,
+			},
+		}
+
+		allErrs := validateTransportServerUpstreamsTLS(upstreams, field.NewPath("upstreams"), test.isTLSPassthrough, test.protocol)
+		if len(allErrs) == 0 {
+			t.Errorf("validateTransportServerUpstreamsTLS() returned no errors for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidateTransportServer_FailsOnInvalidInput(t *testing.T) {
 	t.Parallel()
 	ts := conf_v1.TransportServer{
@@ -1151,6 +1297,25 @@ func TestValidateTsTLS(t *testing.T) {
 			isTLSPassthrough: false,
 			hostSpecified:    false,
 		},
+		{
+			tls: &conf_v1.TransportServerTLS{
+				Secret:           "my-secret",
+				ClientCertSecret: "my-ca-secret",
+			},
+			isTLSPassthrough: false,
+			hostSpecified:    true,
+		},
+		{
+			tls: &conf_v1.TransportServerTLS{
+				Secret:           "my-secret",
+				ClientCertSecret: "my-ca-secret",
+				CrlFileName:      "webapp.crl",
+				VerifyClient:     "optional",
+				VerifyDepth:      createPointerFromInt(2),
+			},
+			isTLSPassthrough: false,
+			hostSpecified:    false,
+		},
 	}
 
 	for _, tc := range validTestCases {
@@ -1196,6 +1361,48 @@ func TestValidateTsTLS_FailsOnInvalidInput(t *testing.T) {
 			isTLSPassthrough: false,
 			hostSpecified:    true,
 		},
+		{
+			tls: &conf_v1.TransportServerTLS{
+				ClientCertSecret: "my-ca-secret",
+			},
+			isTLSPassthrough: false,
+			hostSpecified:    false,
+		},
+		{
+			tls: &conf_v1.TransportServerTLS{
+				Secret:       "my-secret",
+				VerifyClient: "on",
+			},
+			isTLSPassthrough: false,
+			hostSpecified:    true,
+		},
+		{
+			tls: &conf_v1.TransportServerTLS{
+				Secret:           "my-secret",
+				ClientCertSecret: "my-ca-secret",
+				VerifyClient:     "always",
+			},
+			isTLSPassthrough: false,
+			hostSpecified:    true,
+		},
+		{
+			tls: &conf_v1.TransportServerTLS{
+				Secret:           "my-secret",
+				ClientCertSecret: "my-ca-secret",
+				VerifyDepth:      createPointerFromInt(-1),
+			},
+			isTLSPassthrough: false,
+			hostSpecified:    true,
+		},
+		{
+			tls: &conf_v1.TransportServerTLS{
+				Secret:           "my-secret",
+				ClientCertSecret: "my-ca-secret",
+				CrlFileName:      "../webapp.crl",
+			},
+			isTLSPassthrough: false,
+			hostSpecified:    true,
+		},
 	}
 
 	for _, test := range invalidTLSes {
@@ -1205,3 +1412,131 @@ func TestValidateTsTLS_FailsOnInvalidInput(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateTransportServer_CertManager(t *testing.T) {
+	t.Parallel()
+
+	ts := makeTransportServer()
+	ts.Spec.Host = "tcp.example.com"
+	ts.Spec.TLS = &conf_v1.TransportServerTLS{
+		Secret: "tcp-secret",
+		CertManager: &conf_v1.CertManager{
+			ClusterIssuer: "issuer",
+		},
+	}
+
+	tsv := &TransportServerValidator{isCertManagerEnabled: true}
+
+	err := tsv.ValidateTransportServer(&ts)
+	if err != nil {
+		t.Errorf("ValidateTransportServer() returned error %v for valid input", err)
+	}
+}
+
+func TestValidateTransportServer_FailsOnInvalidCertManager(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		host                 string
+		tls                  *conf_v1.TransportServerTLS
+		isCertManagerEnabled bool
+		msg                  string
+	}{
+		{
+			host: "tcp.example.com",
+			tls: &conf_v1.TransportServerTLS{
+				Secret:      "tcp-secret",
+				CertManager: &conf_v1.CertManager{ClusterIssuer: "issuer"},
+			},
+			isCertManagerEnabled: false,
+			msg:                  "cert-manager not enabled",
+		},
+		{
+			host: "",
+			tls: &conf_v1.TransportServerTLS{
+				Secret:      "tcp-secret",
+				CertManager: &conf_v1.CertManager{ClusterIssuer: "issuer"},
+			},
+			isCertManagerEnabled: true,
+			msg:                  "no host",
+		},
+		{
+			host: "tcp.example.com",
+			tls: &conf_v1.TransportServerTLS{
+				CertManager: &conf_v1.CertManager{ClusterIssuer: "issuer"},
+			},
+			isCertManagerEnabled: true,
+			msg:                  "no secret",
+		},
+	}
+
+	for _, test := range tests {
+		ts := makeTransportServer()
+		ts.Spec.Host = test.host
+		ts.Spec.TLS = test.tls
+
+		tsv := &TransportServerValidator{isCertManagerEnabled: test.isCertManagerEnabled}
+
+		err := tsv.ValidateTransportServer(&ts)
+		if err == nil {
+			t.Errorf("ValidateTransportServer() returned no error for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateTransportServer_ExternalDNS(t *testing.T) {
+	t.Parallel()
+
+	ts := makeTransportServer()
+	ts.Spec.Host = "tcp.example.com"
+	ts.Spec.TLS = &conf_v1.TransportServerTLS{Secret: "tcp-secret"}
+	ts.Spec.ExternalDNS = conf_v1.ExternalDNS{
+		Enable:     true,
+		RecordType: "A",
+		RecordTTL:  30,
+	}
+
+	tsv := &TransportServerValidator{isExternalDNSEnabled: true}
+
+	err := tsv.ValidateTransportServer(&ts)
+	if err != nil {
+		t.Errorf("ValidateTransportServer() returned error %v for valid input", err)
+	}
+}
+
+func TestValidateTransportServer_FailsOnInvalidExternalDNS(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		host                 string
+		tls                  *conf_v1.TransportServerTLS
+		isExternalDNSEnabled bool
+		msg                  string
+	}{
+		{
+			host:                 "tcp.example.com",
+			tls:                  &conf_v1.TransportServerTLS{Secret: "tcp-secret"},
+			isExternalDNSEnabled: false,
+			msg:                  "externalDNS not enabled",
+		},
+		{
+			host:                 "",
+			isExternalDNSEnabled: true,
+			msg:                  "no host",
+		},
+	}
+
+	for _, test := range tests {
+		ts := makeTransportServer()
+		ts.Spec.Host = test.host
+		ts.Spec.TLS = test.tls
+		ts.Spec.ExternalDNS = conf_v1.ExternalDNS{Enable: true}
+
+		tsv := &TransportServerValidator{isExternalDNSEnabled: test.isExternalDNSEnabled}
+
+		err := tsv.ValidateTransportServer(&ts)
+		if err == nil {
+			t.Errorf("ValidateTransportServer() returned no error for invalid input for the case of %v", test.msg)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dlclark/regexp2"
 	"github.com/nginx/kubernetes-ingress/internal/configs"
@@ -88,6 +89,10 @@ func (vsv *VirtualServerValidator) validateVirtualServerSpec(spec *v1.VirtualSer
 
 	allErrs = append(allErrs, validateDos(vsv.isDosEnabled, spec.Dos, fieldPath.Child("dos"))...)
 
+	allErrs = append(allErrs, vsv.validateTracing(spec.Tracing, fieldPath.Child("tracing"))...)
+
+	allErrs = append(allErrs, validateAccessLog(spec.AccessLog, fieldPath.Child("accessLog"))...)
+
 	allErrs = append(allErrs, vsv.validateExternalDNS(&spec.ExternalDNS, fieldPath.Child("externalDNS"))...)
 
 	return allErrs
@@ -181,6 +186,55 @@ func validateTLSCmFields(cm *v1.CertManager, isCertManagerEnabled bool, secret s
 	return allErrs
 }
 
+const (
+	spanAttributeNameFmt    = `[a-zA-Z0-9._-]+`
+	spanAttributeNameErrMsg = "must contain only alphanumeric characters, '.', '_' or '-'"
+)
+
+var spanAttributeNameRegexp = regexp.MustCompile("^" + spanAttributeNameFmt + "$")
+
+func (vsv *VirtualServerValidator) validateTracing(tracing *v1.Tracing, fieldPath *field.Path) field.ErrorList {
+	if tracing == nil {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+	for i, attr := range tracing.SpanAttributes {
+		idxPath := fieldPath.Child("spanAttributes").Index(i)
+
+		if attr.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), ""))
+		} else if !spanAttributeNameRegexp.MatchString(attr.Name) {
+			msg := validation.RegexError(spanAttributeNameErrMsg, spanAttributeNameFmt, "http.route", "tenant_id")
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), attr.Name, msg))
+		}
+
+		allErrs = append(allErrs, validateEscapedStringWithVariables(attr.Value, idxPath.Child("value"),
+			actionProxyHeaderSpecialVariables, actionProxyHeaderVariables, vsv.isPlus)...)
+	}
+	return allErrs
+}
+
+func validateAccessLog(accessLog *v1.AccessLog, fieldPath *field.Path) field.ErrorList {
+	if accessLog == nil {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+	if accessLog.Destination != "" {
+		if _, err := configs.ParseAccessLogDestination(accessLog.Destination); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("destination"), accessLog.Destination, err.Error()))
+		}
+	}
+	if accessLog.Format != "" {
+		if _, err := configs.ParseLogFormatName(accessLog.Format); err != nil {
+			msg := validation.RegexError("must contain only alphanumeric characters, '_' or '-'", configs.LogFormatNameFmt, "main", "json")
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("format"), accessLog.Format, msg))
+		}
+	}
+	return allErrs
+}
+
 func validateDos(isDosEnabled bool, dos string, fieldPath *field.Path) field.ErrorList {
 	if dos == "" {
 		// valid, dos is not required
@@ -609,6 +663,7 @@ func (vsv *VirtualServerValidator) validateUpstreams(upstreams []v1.Upstream, fi
 		allErrs = append(allErrs, validateQueue(u.Queue, idxPath.Child("queue"))...)
 		allErrs = append(allErrs, validateSessionCookie(u.SessionCookie, idxPath.Child("sessionCookie"))...)
 		allErrs = append(allErrs, validateUpstreamType(u.Type, idxPath.Child("type"))...)
+		allErrs = append(allErrs, validateUpstreamTLS(u.TLS, idxPath.Child("tls"))...)
 
 		for _, msg := range validation.IsValidPortNum(int(u.Port)) {
 			allErrs = append(allErrs, field.Invalid(idxPath.Child("port"), u.Port, msg))
@@ -648,6 +703,60 @@ func validateBackup(backup string, backupPort *uint16, lbMethod string, idxPath
 	return allErrs
 }
 
+func validateUpstreamTLS(tls v1.UpstreamTLS, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !tls.Enable {
+		if tls != (v1.UpstreamTLS{}) {
+			allErrs = append(allErrs, field.Forbidden(fieldPath, "TLS settings require enable to be 'true'"))
+		}
+		return allErrs
+	}
+
+	if tls.VerifyServer && tls.TrustedCertSecret == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("trustedCertSecret"), "must be set when verifyServer is 'true'"))
+	}
+	allErrs = append(allErrs, validateSecretName(tls.TrustedCertSecret, fieldPath.Child("trustedCertSecret"))...)
+	allErrs = append(allErrs, validatePositiveIntOrZeroFromPointer(tls.VerifyDepth, fieldPath.Child("verifyDepth"))...)
+	allErrs = append(allErrs, validateUpstreamTLSProtocols(tls.Protocols, fieldPath.Child("protocols"))...)
+	allErrs = append(allErrs, validateUpstreamTLSCiphers(tls.Ciphers, fieldPath.Child("ciphers"))...)
+	return append(allErrs, validateSSLName(tls.SSLName, fieldPath.Child("sslName"))...)
+}
+
+var validUpstreamTLSProtocols = map[string]bool{
+	"SSLv2":   true,
+	"SSLv3":   true,
+	"TLSv1":   true,
+	"TLSv1.1": true,
+	"TLSv1.2": true,
+	"TLSv1.3": true,
+}
+
+func validateUpstreamTLSProtocols(protocols string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, p := range strings.Fields(protocols) {
+		if !validUpstreamTLSProtocols[p] {
+			allErrs = append(allErrs, field.NotSupported(fieldPath, p, getProtocolsFromMap(validUpstreamTLSProtocols)))
+		}
+	}
+	return allErrs
+}
+
+const (
+	upstreamTLSCiphersFmt    = `[A-Za-z0-9_!+@=:.-]*`
+	upstreamTLSCiphersErrMsg = "must be a valid OpenSSL cipher list"
+)
+
+var upstreamTLSCiphersRegexp = regexp.MustCompile("^" + upstreamTLSCiphersFmt + "$")
+
+func validateUpstreamTLSCiphers(ciphers string, fieldPath *field.Path) field.ErrorList {
+	if !upstreamTLSCiphersRegexp.MatchString(ciphers) {
+		msg := validation.RegexError(upstreamTLSCiphersErrMsg, upstreamTLSCiphersFmt, "HIGH:!aNULL:!MD5", "ECDHE-RSA-AES256-GCM-SHA384")
+		return field.ErrorList{field.Invalid(fieldPath, ciphers, msg)}
+	}
+	return nil
+}
+
 var validNextUpstreamParams = map[string]bool{
 	"error":          true,
 	"timeout":        true,
@@ -751,6 +860,10 @@ func (vsv *VirtualServerValidator) validateRoute(route v1.Route, fieldPath *fiel
 		fieldCount++
 	}
 
+	if route.Canary != nil {
+		allErrs = append(allErrs, validateCanary(route.Canary, route.Splits, fieldPath.Child("canary"))...)
+	}
+
 	// Matches are optional. that's why we don't do fieldCount++
 	if len(route.Matches) > 0 {
 		for i, m := range route.Matches {
@@ -782,6 +895,8 @@ func (vsv *VirtualServerValidator) validateRoute(route v1.Route, fieldPath *fiel
 
 	allErrs = append(allErrs, validateDos(vsv.isDosEnabled, route.Dos, fieldPath.Child("dos"))...)
 
+	allErrs = append(allErrs, vsv.validateTracing(route.Tracing, fieldPath.Child("tracing"))...)
+
 	return allErrs
 }
 
@@ -877,6 +992,10 @@ func countActions(action *v1.Action) int {
 		count++
 	}
 
+	if action.GRPCReturn != nil {
+		count++
+	}
+
 	if action.Proxy != nil {
 		count++
 	}
@@ -923,7 +1042,7 @@ var validRedirectVariableNames = map[string]bool{
 
 func (vsv *VirtualServerValidator) validateAction(action *v1.Action, fieldPath *field.Path, upstreamNames sets.Set[string], path string, internal bool) field.ErrorList {
 	if countActions(action) != 1 {
-		return field.ErrorList{field.Required(fieldPath, "action must specify exactly one of `pass`, `redirect`, `return` or `proxy`")}
+		return field.ErrorList{field.Required(fieldPath, "action must specify exactly one of `pass`, `redirect`, `return`, `grpcReturn` or `proxy`")}
 	}
 
 	allErrs := field.ErrorList{}
@@ -939,10 +1058,42 @@ func (vsv *VirtualServerValidator) validateAction(action *v1.Action, fieldPath *
 		allErrs = append(allErrs, vsv.validateActionReturn(action.Return, fieldPath.Child("return"), returnBodySpecialVariables, returnBodyVariables)...)
 	}
 
+	if action.GRPCReturn != nil {
+		allErrs = append(allErrs, validateActionGRPCReturn(action.GRPCReturn, fieldPath.Child("grpcReturn"))...)
+	}
+
 	if action.Proxy != nil {
 		allErrs = append(allErrs, vsv.validateActionProxy(action.Proxy, fieldPath.Child("proxy"), upstreamNames, path, internal)...)
 	}
 
+	if action.Mirror != nil {
+		allErrs = append(allErrs, validateActionMirror(action, fieldPath.Child("mirror"), upstreamNames)...)
+	}
+
+	return allErrs
+}
+
+func validateActionMirror(action *v1.Action, fieldPath *field.Path, upstreamNames sets.Set[string]) field.ErrorList {
+	primaryUpstream := action.Pass
+	if action.Proxy != nil {
+		primaryUpstream = action.Proxy.Upstream
+	}
+	if primaryUpstream == "" {
+		return field.ErrorList{field.Forbidden(fieldPath, "mirror can only be used with `pass` or `proxy` actions")}
+	}
+
+	m := action.Mirror
+	allErrs := validateReferencedUpstream(m.Upstream, fieldPath.Child("upstream"), upstreamNames)
+	if m.Upstream == primaryUpstream {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("upstream"), m.Upstream, "must not be the upstream of the action"))
+	}
+
+	if m.Percentage != nil {
+		for _, msg := range validation.IsInRange(*m.Percentage, 1, 100) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("percentage"), *m.Percentage, msg))
+		}
+	}
+
 	return allErrs
 }
 
@@ -1005,6 +1156,29 @@ func (vsv *VirtualServerValidator) validateActionReturn(r *v1.ActionReturn, fiel
 	return allErrs
 }
 
+const (
+	grpcMessageFmt    = `[^"\\$\r\n]*`
+	grpcMessageErrMsg = "must not include `\"` (double quotes), `\\` (backslash), `$` or line breaks"
+)
+
+var grpcMessageRegexp = regexp.MustCompile("^" + grpcMessageFmt + "$")
+
+func validateActionGRPCReturn(r *v1.ActionGRPCReturn, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// 0 (OK) is a valid gRPC status code, 16 (UNAUTHENTICATED) is the last one
+	for _, msg := range validation.IsInRange(r.Status, 0, 16) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("status"), r.Status, msg))
+	}
+
+	if !grpcMessageRegexp.MatchString(r.Message) {
+		msg := validation.RegexError(grpcMessageErrMsg, grpcMessageFmt, "unavailable", "method not allowed")
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("message"), r.Message, msg))
+	}
+
+	return allErrs
+}
+
 func validateEscapedStringWithVariables(body string, fieldPath *field.Path, specialValidVars []string, validVars map[string]bool, isPlus bool) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -1066,6 +1240,53 @@ func (vsv *VirtualServerValidator) validateActionProxy(p *v1.ActionProxy, fieldP
 		allErrs = append(allErrs, validateActionProxyRewritePath(p.RewritePath, fieldPath.Child("rewritePath"))...)
 	}
 
+	if len(p.Rewrites) > 0 && p.RewritePath != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("rewrites"), "must not be used together with rewritePath"))
+	}
+	allErrs = append(allErrs, validateActionProxyRewrites(p.Rewrites, fieldPath.Child("rewrites"))...)
+
+	return allErrs
+}
+
+func validateActionProxyRewrites(rewrites []v1.ProxyRewrite, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, r := range rewrites {
+		idxPath := fieldPath.Index(i)
+
+		if r.Match == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("match"), ""))
+		} else {
+			allErrs = append(allErrs, validateRegexPath(r.Match, idxPath.Child("match"))...)
+		}
+
+		if r.Replace == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("replace"), ""))
+		} else {
+			allErrs = append(allErrs, validateActionProxyRewriteReplacement(r.Replace, "/rewrite/$1", idxPath.Child("replace"))...)
+		}
+
+		if r.Query != nil && *r.Query != "" {
+			allErrs = append(allErrs, validateActionProxyRewriteReplacement(*r.Query, "version=$1", idxPath.Child("query"))...)
+		}
+	}
+
+	return allErrs
+}
+
+// validateActionProxyRewriteReplacement validates the replacement part of a rewrite, which can only reference
+// the captures of the match, must not include a query string and must not start with a scheme.
+func validateActionProxyRewriteReplacement(replacement string, example string, fieldPath *field.Path) field.ErrorList {
+	allErrs := validateStringNoVariables(replacement, fieldPath)
+	if err := ValidateEscapedString(replacement, example); err != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath, replacement, err.Error()))
+	}
+	if strings.Contains(replacement, "?") {
+		allErrs = append(allErrs, field.Invalid(fieldPath, replacement, "must not include `?`"))
+	}
+	if strings.Contains(replacement, "://") {
+		allErrs = append(allErrs, field.Invalid(fieldPath, replacement, "must not include a scheme"))
+	}
 	return allErrs
 }
 
@@ -1263,6 +1484,61 @@ func (vsv *VirtualServerValidator) validateSplits(splits []v1.Split, fieldPath *
 	return allErrs
 }
 
+func validateCanary(canary *v1.Canary, splits []v1.Split, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(splits) != 2 {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "requires exactly 2 splits"))
+	} else if splits[1].Action != nil && splits[1].Action.Pass == "" && splits[1].Action.Proxy == nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "requires the action of the second split to pass or proxy the requests to an upstream"))
+	}
+
+	allErrs = append(allErrs, validateCanaryDuration(canary.Interval, fieldPath.Child("interval"), true)...)
+
+	for _, msg := range validation.IsInRange(canary.StepWeight, 1, 100) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("stepWeight"), canary.StepWeight, msg))
+	}
+
+	if canary.MaxWeight != nil {
+		for _, msg := range validation.IsInRange(*canary.MaxWeight, 1, 100) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxWeight"), *canary.MaxWeight, msg))
+		}
+		if len(splits) == 2 && *canary.MaxWeight < splits[1].Weight {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxWeight"), *canary.MaxWeight, "must not be less than the weight of the second split"))
+		}
+	}
+
+	if canary.MaxErrorRate != nil {
+		for _, msg := range validation.IsInRange(*canary.MaxErrorRate, 0, 100) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxErrorRate"), *canary.MaxErrorRate, msg))
+		}
+	}
+
+	allErrs = append(allErrs, validateCanaryDuration(canary.MaxResponseTime, fieldPath.Child("maxResponseTime"), false)...)
+
+	return allErrs
+}
+
+// validateCanaryDuration validates a duration in the format of Go durations, for example "30s" or "1m30s".
+func validateCanaryDuration(duration string, fieldPath *field.Path, required bool) field.ErrorList {
+	if duration == "" {
+		if required {
+			return field.ErrorList{field.Required(fieldPath, "")}
+		}
+		return nil
+	}
+
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fieldPath, duration, "must be a valid duration, for example 30s or 1m30s")}
+	}
+	if d <= 0 {
+		return field.ErrorList{field.Invalid(fieldPath, duration, "must be positive")}
+	}
+
+	return nil
+}
+
 // We support prefix-based NGINX locations, positive case-sensitive/insensitive regular expressions matches and exact matches.
 // More info http://nginx.org/en/docs/http/ngx_http_core_module.html#location
 func validateRoutePath(path string, fieldPath *field.Path) field.ErrorList {
@@ -1393,8 +1669,13 @@ func validateCondition(condition v1.Condition, fieldPath *field.Path) field.Erro
 		fieldCount++
 	}
 
+	if condition.GRPC != "" {
+		allErrs = append(allErrs, validateGRPCCondition(condition, fieldPath)...)
+		fieldCount++
+	}
+
 	if fieldCount != 1 {
-		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify exactly one of: `header`, `cookie`, `argument` or `variable`"))
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify exactly one of: `header`, `cookie`, `argument`, `variable` or `grpc`"))
 	}
 
 	for _, msg := range isValidMatchValue(condition.Value) {
@@ -1404,6 +1685,41 @@ func validateCondition(condition v1.Condition, fieldPath *field.Path) field.Erro
 	return allErrs
 }
 
+const (
+	grpcServiceNameFmt    = `[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*`
+	grpcServiceNameErrMsg = "a valid gRPC service name must consist of identifiers separated by '.'"
+	grpcMethodNameFmt     = `[A-Za-z_][A-Za-z0-9_]*`
+	grpcMethodNameErrMsg  = "a valid gRPC method name must consist of alphanumeric characters or '_'"
+)
+
+var (
+	grpcServiceNameRegexp = regexp.MustCompile("^" + grpcServiceNameFmt + "$")
+	grpcMethodNameRegexp  = regexp.MustCompile("^" + grpcMethodNameFmt + "$")
+)
+
+// validateGRPCCondition validates a condition on the service or the method of a gRPC call.
+// The value is the fully qualified name of the service or the name of the method, optionally negated with '!'.
+func validateGRPCCondition(condition v1.Condition, fieldPath *field.Path) field.ErrorList {
+	value := strings.TrimPrefix(condition.Value, "!")
+
+	switch condition.GRPC {
+	case v1.GRPCConditionService:
+		if !grpcServiceNameRegexp.MatchString(value) {
+			msg := validation.RegexError(grpcServiceNameErrMsg, grpcServiceNameFmt, "helloworld.Greeter", "!helloworld.Greeter")
+			return field.ErrorList{field.Invalid(fieldPath.Child("value"), condition.Value, msg)}
+		}
+	case v1.GRPCConditionMethod:
+		if !grpcMethodNameRegexp.MatchString(value) {
+			msg := validation.RegexError(grpcMethodNameErrMsg, grpcMethodNameFmt, "SayHello", "!SayHello")
+			return field.ErrorList{field.Invalid(fieldPath.Child("value"), condition.Value, msg)}
+		}
+	default:
+		return field.ErrorList{field.NotSupported(fieldPath.Child("grpc"), condition.GRPC, []string{v1.GRPCConditionService, v1.GRPCConditionMethod})}
+	}
+
+	return nil
+}
+
 const (
 	cookieNameFmt    string = "[_A-Za-z0-9]+"
 	cookieNameErrMsg string = "a valid cookie name must consist of alphanumeric characters or '_'"
@@ -277,6 +277,171 @@ func TestValidateDosDisabled(t *testing.T) {
 	}
 }
 
+func TestValidateTracing(t *testing.T) {
+	t.Parallel()
+	tests := []*v1.Tracing{
+		nil,
+		{},
+		{
+			Enable: createPointerFromBool(true),
+		},
+		{
+			Enable: createPointerFromBool(false),
+			SpanAttributes: []v1.SpanAttribute{
+				{
+					Name:  "tenant_id",
+					Value: "cafe",
+				},
+				{
+					Name:  "http.request_uri",
+					Value: "${request_uri}",
+				},
+				{
+					Name:  "user-agent",
+					Value: "${http_user_agent}",
+				},
+			},
+		},
+	}
+
+	vsv := &VirtualServerValidator{isPlus: false}
+
+	for _, test := range tests {
+		allErrs := vsv.validateTracing(test, field.NewPath("tracing"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateTracing() returned errors %v for valid input %v", allErrs, test)
+		}
+	}
+}
+
+func TestValidateTracingFails(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		attr v1.SpanAttribute
+		msg  string
+	}{
+		{
+			attr: v1.SpanAttribute{
+				Value: "cafe",
+			},
+			msg: "missing name",
+		},
+		{
+			attr: v1.SpanAttribute{
+				Name:  "tenant id",
+				Value: "cafe",
+			},
+			msg: "invalid name with spaces",
+		},
+		{
+			attr: v1.SpanAttribute{
+				Name:  "tenant;",
+				Value: "cafe",
+			},
+			msg: "invalid name with ';'",
+		},
+		{
+			attr: v1.SpanAttribute{
+				Name:  "tenant",
+				Value: `"cafe`,
+			},
+			msg: `invalid value with unescaped '"'`,
+		},
+		{
+			attr: v1.SpanAttribute{
+				Name:  "tenant",
+				Value: "${realpath_root}",
+			},
+			msg: "invalid variable",
+		},
+	}
+
+	vsv := &VirtualServerValidator{isPlus: false}
+
+	for _, test := range tests {
+		tracing := &v1.Tracing{SpanAttributes: []v1.SpanAttribute{test.attr}}
+		allErrs := vsv.validateTracing(tracing, field.NewPath("tracing"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateTracing() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateAccessLog(t *testing.T) {
+	t.Parallel()
+	tests := []*v1.AccessLog{
+		nil,
+		{},
+		{
+			Format: "json",
+		},
+		{
+			Destination: "/dev/stderr",
+			Format:      "main",
+		},
+		{
+			Destination: "syslog:server=localhost:514",
+			Format:      "cafe_json",
+		},
+		{
+			Destination: "/var/log/nginx/cafe-access.log",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateAccessLog(test, field.NewPath("accessLog"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateAccessLog() returned errors %v for valid input %v", allErrs, test)
+		}
+	}
+}
+
+func TestValidateAccessLogFails(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		accessLog *v1.AccessLog
+		msg       string
+	}{
+		{
+			accessLog: &v1.AccessLog{
+				Destination: "/etc/nginx/nginx.conf",
+			},
+			msg: "file outside of /var/log/nginx",
+		},
+		{
+			accessLog: &v1.AccessLog{
+				Destination: "/var/log/nginx/../../etc/passwd",
+			},
+			msg: "file with path traversal",
+		},
+		{
+			accessLog: &v1.AccessLog{
+				Destination: "syslog:server=localhost:514;",
+			},
+			msg: "syslog server with ';'",
+		},
+		{
+			accessLog: &v1.AccessLog{
+				Format: "json;",
+			},
+			msg: "format with ';'",
+		},
+		{
+			accessLog: &v1.AccessLog{
+				Format: "my format",
+			},
+			msg: "format with spaces",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateAccessLog(test.accessLog, field.NewPath("accessLog"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateAccessLog() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidatePolicies(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -1281,6 +1446,21 @@ func TestValidateAction(t *testing.T) {
 			},
 			msg: "proxy action with rewritePath, requestHeaders and responseHeaders",
 		},
+		{
+			action: &v1.Action{
+				GRPCReturn: &v1.ActionGRPCReturn{
+					Status:  14,
+					Message: "service unavailable",
+				},
+			},
+			msg: "grpcReturn action",
+		},
+		{
+			action: &v1.Action{
+				GRPCReturn: &v1.ActionGRPCReturn{},
+			},
+			msg: "grpcReturn action with OK status and no message",
+		},
 	}
 
 	vsv := &VirtualServerValidator{isPlus: false}
@@ -1337,6 +1517,139 @@ func TestValidateActionFails(t *testing.T) {
 			},
 			msg: "proxy action with missing upstream field",
 		},
+		{
+			action: &v1.Action{
+				GRPCReturn: &v1.ActionGRPCReturn{
+					Status: 17,
+				},
+			},
+			msg: "grpcReturn action with invalid status",
+		},
+		{
+			action: &v1.Action{
+				GRPCReturn: &v1.ActionGRPCReturn{
+					Status:  13,
+					Message: `internal "error"`,
+				},
+			},
+			msg: "grpcReturn action with invalid message",
+		},
+		{
+			action: &v1.Action{
+				Pass: "test",
+				GRPCReturn: &v1.ActionGRPCReturn{
+					Status: 13,
+				},
+			},
+			msg: "grpcReturn action with another action",
+		},
+	}
+
+	vsv := &VirtualServerValidator{isPlus: false}
+
+	for _, test := range tests {
+		allErrs := vsv.validateAction(test.action, field.NewPath("action"), upstreamNames, "", false)
+		if len(allErrs) == 0 {
+			t.Errorf("validateAction() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateActionMirror(t *testing.T) {
+	t.Parallel()
+	upstreamNames := map[string]sets.Empty{
+		"test":   {},
+		"shadow": {},
+	}
+	tests := []struct {
+		action *v1.Action
+		msg    string
+	}{
+		{
+			action: &v1.Action{
+				Pass: "test",
+				Mirror: &v1.ActionMirror{
+					Upstream: "shadow",
+				},
+			},
+			msg: "pass action with mirror",
+		},
+		{
+			action: &v1.Action{
+				Proxy: &v1.ActionProxy{
+					Upstream: "test",
+				},
+				Mirror: &v1.ActionMirror{
+					Upstream:    "shadow",
+					Percentage:  createPointerFromInt(10),
+					RequestBody: createPointerFromBool(false),
+				},
+			},
+			msg: "proxy action with mirror percentage and request body",
+		},
+	}
+
+	vsv := &VirtualServerValidator{isPlus: false}
+
+	for _, test := range tests {
+		allErrs := vsv.validateAction(test.action, field.NewPath("action"), upstreamNames, "", false)
+		if len(allErrs) > 0 {
+			t.Errorf("validateAction() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateActionMirrorFails(t *testing.T) {
+	t.Parallel()
+	upstreamNames := map[string]sets.Empty{
+		"test":   {},
+		"shadow": {},
+	}
+	tests := []struct {
+		action *v1.Action
+		msg    string
+	}{
+		{
+			action: &v1.Action{
+				Pass: "test",
+				Mirror: &v1.ActionMirror{
+					Upstream: "missing",
+				},
+			},
+			msg: "mirror upstream does not exist",
+		},
+		{
+			action: &v1.Action{
+				Pass: "test",
+				Mirror: &v1.ActionMirror{
+					Upstream: "test",
+				},
+			},
+			msg: "mirror upstream is the primary upstream",
+		},
+		{
+			action: &v1.Action{
+				Proxy: &v1.ActionProxy{
+					Upstream: "test",
+				},
+				Mirror: &v1.ActionMirror{
+					Upstream:   "shadow",
+					Percentage: createPointerFromInt(0),
+				},
+			},
+			msg: "mirror percentage out of range",
+		},
+		{
+			action: &v1.Action{
+				Redirect: &v1.ActionRedirect{
+					URL: "http://www.nginx.com",
+				},
+				Mirror: &v1.ActionMirror{
+					Upstream: "shadow",
+				},
+			},
+			msg: "mirror with redirect action",
+		},
 	}
 
 	vsv := &VirtualServerValidator{isPlus: false}
@@ -1928,6 +2241,106 @@ func TestValidateSplitsFails(t *testing.T) {
 	}
 }
 
+func TestValidateCanary(t *testing.T) {
+	t.Parallel()
+
+	splits := []v1.Split{
+		{Weight: 90, Action: &v1.Action{Pass: "stable"}},
+		{Weight: 10, Action: &v1.Action{Pass: "canary"}},
+	}
+	tests := []struct {
+		canary *v1.Canary
+		msg    string
+	}{
+		{
+			canary: &v1.Canary{Interval: "1m", StepWeight: 10},
+			msg:    "only required fields",
+		},
+		{
+			canary: &v1.Canary{
+				Interval:        "30s",
+				StepWeight:      20,
+				MaxWeight:       createPointerFromInt(50),
+				MaxErrorRate:    createPointerFromInt(0),
+				MaxResponseTime: "500ms",
+			},
+			msg: "all fields",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateCanary(test.canary, splits, field.NewPath("canary"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateCanary() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateCanaryFails(t *testing.T) {
+	t.Parallel()
+
+	splits := []v1.Split{
+		{Weight: 70, Action: &v1.Action{Pass: "stable"}},
+		{Weight: 30, Action: &v1.Action{Pass: "canary"}},
+	}
+	tests := []struct {
+		canary *v1.Canary
+		splits []v1.Split
+		msg    string
+	}{
+		{
+			canary: &v1.Canary{StepWeight: 10},
+			splits: splits,
+			msg:    "missing interval",
+		},
+		{
+			canary: &v1.Canary{Interval: "1 minute", StepWeight: 10},
+			splits: splits,
+			msg:    "invalid interval",
+		},
+		{
+			canary: &v1.Canary{Interval: "1m"},
+			splits: splits,
+			msg:    "missing step weight",
+		},
+		{
+			canary: &v1.Canary{Interval: "1m", StepWeight: 10, MaxWeight: createPointerFromInt(20)},
+			splits: splits,
+			msg:    "max weight less than the weight of the second split",
+		},
+		{
+			canary: &v1.Canary{Interval: "1m", StepWeight: 10, MaxErrorRate: createPointerFromInt(101)},
+			splits: splits,
+			msg:    "invalid max error rate",
+		},
+		{
+			canary: &v1.Canary{Interval: "1m", StepWeight: 10, MaxResponseTime: "-1s"},
+			splits: splits,
+			msg:    "negative max response time",
+		},
+		{
+			canary: &v1.Canary{Interval: "1m", StepWeight: 10},
+			splits: append(splits, v1.Split{Action: &v1.Action{Pass: "other"}}),
+			msg:    "three splits",
+		},
+		{
+			canary: &v1.Canary{Interval: "1m", StepWeight: 10},
+			splits: []v1.Split{
+				{Weight: 90, Action: &v1.Action{Pass: "stable"}},
+				{Weight: 10, Action: &v1.Action{Return: &v1.ActionReturn{Body: "canary"}}},
+			},
+			msg: "second split without an upstream",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateCanary(test.canary, test.splits, field.NewPath("canary"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateCanary() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidateCondition(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -1962,6 +2375,20 @@ func TestValidateCondition(t *testing.T) {
 			},
 			msg: "valid variable",
 		},
+		{
+			condition: v1.Condition{
+				GRPC:  "service",
+				Value: "helloworld.Greeter",
+			},
+			msg: "valid grpc service",
+		},
+		{
+			condition: v1.Condition{
+				GRPC:  "method",
+				Value: "!SayHello",
+			},
+			msg: "valid negated grpc method",
+		},
 	}
 
 	for _, test := range tests {
@@ -2016,6 +2443,35 @@ func TestValidateConditionFails(t *testing.T) {
 			},
 			msg: "invalid variable",
 		},
+		{
+			condition: v1.Condition{
+				GRPC:  "package",
+				Value: "helloworld",
+			},
+			msg: "invalid grpc",
+		},
+		{
+			condition: v1.Condition{
+				GRPC:  "service",
+				Value: "helloworld/Greeter",
+			},
+			msg: "invalid grpc service",
+		},
+		{
+			condition: v1.Condition{
+				GRPC:  "method",
+				Value: "helloworld.Greeter.SayHello",
+			},
+			msg: "invalid grpc method",
+		},
+		{
+			condition: v1.Condition{
+				GRPC:   "method",
+				Header: "x-version",
+				Value:  "SayHello",
+			},
+			msg: "grpc with header",
+		},
 	}
 
 	for _, test := range tests {
@@ -3008,6 +3464,111 @@ func TestValidateUpstreamHealthCheckFails(t *testing.T) {
 	}
 }
 
+func TestValidateUpstreamTLS(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		tls v1.UpstreamTLS
+		msg string
+	}{
+		{
+			tls: v1.UpstreamTLS{},
+			msg: "tls disabled",
+		},
+		{
+			tls: v1.UpstreamTLS{
+				Enable: true,
+			},
+			msg: "tls enabled",
+		},
+		{
+			tls: v1.UpstreamTLS{
+				Enable:            true,
+				TrustedCertSecret: "ca-secret",
+				VerifyServer:      true,
+				VerifyDepth:       createPointerFromInt(2),
+				Protocols:         "TLSv1.2 TLSv1.3",
+				Ciphers:           "HIGH:!aNULL:!MD5",
+				SessionReuse:      createPointerFromBool(false),
+				ServerName:        true,
+				SSLName:           "backend.example.com",
+			},
+			msg: "all fields set",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateUpstreamTLS(test.tls, field.NewPath("tls"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateUpstreamTLS() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateUpstreamTLSFails(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		tls v1.UpstreamTLS
+		msg string
+	}{
+		{
+			tls: v1.UpstreamTLS{
+				TrustedCertSecret: "ca-secret",
+				VerifyServer:      true,
+			},
+			msg: "settings without enable",
+		},
+		{
+			tls: v1.UpstreamTLS{
+				Enable:       true,
+				VerifyServer: true,
+			},
+			msg: "verifyServer without trustedCertSecret",
+		},
+		{
+			tls: v1.UpstreamTLS{
+				Enable:            true,
+				TrustedCertSecret: "-invalid-",
+			},
+			msg: "invalid trustedCertSecret",
+		},
+		{
+			tls: v1.UpstreamTLS{
+				Enable:      true,
+				VerifyDepth: createPointerFromInt(-1),
+			},
+			msg: "negative verifyDepth",
+		},
+		{
+			tls: v1.UpstreamTLS{
+				Enable:    true,
+				Protocols: "TLSv1.2 TLSv1.4",
+			},
+			msg: "unsupported protocol",
+		},
+		{
+			tls: v1.UpstreamTLS{
+				Enable:  true,
+				Ciphers: "HIGH; proxy_pass http://example.com",
+			},
+			msg: "invalid ciphers",
+		},
+		{
+			tls: v1.UpstreamTLS{
+				Enable:  true,
+				SSLName: "$host",
+			},
+			msg: "invalid sslName",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateUpstreamTLS(test.tls, field.NewPath("tls"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateUpstreamTLS() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidateGrpcUpstreamHealthCheckFails(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -3786,6 +4347,129 @@ func TestValidateActionProxyRewritePathForRegexpFails(t *testing.T) {
 	}
 }
 
+func TestValidateActionProxyRewrites(t *testing.T) {
+	t.Parallel()
+	query := "version=$1"
+	emptyQuery := ""
+	rewrites := []v1.ProxyRewrite{
+		{
+			Match:   "^/tea/(.*)/green$",
+			Replace: "/green-tea/$1",
+		},
+		{
+			Match:   `^/tea/(v\d{1,3})/(.*)$`,
+			Replace: "/$2",
+			Query:   &query,
+		},
+		{
+			Match:   "^/tea/legacy$",
+			Replace: "/tea",
+			Query:   &emptyQuery,
+		},
+	}
+
+	allErrs := validateActionProxyRewrites(rewrites, field.NewPath("rewrites"))
+	if len(allErrs) != 0 {
+		t.Errorf("validateActionProxyRewrites() returned errors for valid input: %v", allErrs)
+	}
+}
+
+func TestValidateActionProxyRewritesFails(t *testing.T) {
+	t.Parallel()
+	query := "version=$arg_version"
+	tests := []struct {
+		rewrite v1.ProxyRewrite
+		msg     string
+	}{
+		{
+			rewrite: v1.ProxyRewrite{
+				Replace: "/tea",
+			},
+			msg: "missing match",
+		},
+		{
+			rewrite: v1.ProxyRewrite{
+				Match: "^/tea$",
+			},
+			msg: "missing replace",
+		},
+		{
+			rewrite: v1.ProxyRewrite{
+				Match:   "^/tea/(.*$",
+				Replace: "/tea",
+			},
+			msg: "invalid regex in match",
+		},
+		{
+			rewrite: v1.ProxyRewrite{
+				Match:   `^/"tea"$`,
+				Replace: "/tea",
+			},
+			msg: "unescaped double quotes in match",
+		},
+		{
+			rewrite: v1.ProxyRewrite{
+				Match:   "^/tea$",
+				Replace: "$request_uri",
+			},
+			msg: "variable in replace",
+		},
+		{
+			rewrite: v1.ProxyRewrite{
+				Match:   "^/tea$",
+				Replace: "/tea?version=1",
+			},
+			msg: "query string in replace",
+		},
+		{
+			rewrite: v1.ProxyRewrite{
+				Match:   "^/tea$",
+				Replace: "https://example.com/tea",
+			},
+			msg: "scheme in replace",
+		},
+		{
+			rewrite: v1.ProxyRewrite{
+				Match:   "^/tea$",
+				Replace: "/tea",
+				Query:   &query,
+			},
+			msg: "variable in query",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateActionProxyRewrites([]v1.ProxyRewrite{test.rewrite}, field.NewPath("rewrites"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateActionProxyRewrites() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateActionProxyFailsForRewritesWithRewritePath(t *testing.T) {
+	t.Parallel()
+	upstreamNames := map[string]sets.Empty{
+		"upstream1": {},
+	}
+	actionProxy := &v1.ActionProxy{
+		Upstream:    "upstream1",
+		RewritePath: "/test",
+		Rewrites: []v1.ProxyRewrite{
+			{
+				Match:   "^/path/(.*)$",
+				Replace: "/test/$1",
+			},
+		},
+	}
+
+	vsv := &VirtualServerValidator{isPlus: false}
+
+	allErrs := vsv.validateActionProxy(actionProxy, field.NewPath("proxy"), upstreamNames, "/path", false)
+	if len(allErrs) == 0 {
+		t.Errorf("validateActionProxy(%+v) returned no errors for invalid input", actionProxy)
+	}
+}
+
 func TestValidateActionProxyHeader(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
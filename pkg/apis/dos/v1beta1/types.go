@@ -6,13 +6,17 @@ import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:validation:Optional
 // +kubebuilder:resource:shortName=pr
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`,description="Current state of the DosProtectedResource. If the resource has a valid status, it means it has been validated and accepted by the Ingress Controller."
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // DosProtectedResource defines a Dos protected resource.
 type DosProtectedResource struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec DosProtectedResourceSpec `json:"spec"`
+	Spec   DosProtectedResourceSpec   `json:"spec"`
+	Status DosProtectedResourceStatus `json:"status"`
 }
 
 // DosProtectedResourceSpec defines the properties and values a DosProtectedResource can have.
@@ -31,6 +35,15 @@ type DosProtectedResourceSpec struct {
 	AllowList []AllowListEntry `json:"allowList,omitempty"`
 }
 
+// DosProtectedResourceStatus defines the status for the DosProtectedResource resource.
+type DosProtectedResourceStatus struct {
+	State   string `json:"state"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	// ReferencedBy is the list of the kind/namespace/name of the VirtualServers and Ingresses that reference the resource.
+	ReferencedBy []string `json:"referencedBy,omitempty"`
+}
+
 // AllowListEntry represents an IP address and a subnet mask.
 type AllowListEntry struct {
 	IPWithMask string `json:"ipWithMask"`
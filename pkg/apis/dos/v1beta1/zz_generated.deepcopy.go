@@ -47,6 +47,7 @@ func (in *DosProtectedResource) DeepCopyInto(out *DosProtectedResource) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -132,6 +133,27 @@ func (in *DosProtectedResourceSpec) DeepCopy() *DosProtectedResourceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DosProtectedResourceStatus) DeepCopyInto(out *DosProtectedResourceStatus) {
+	*out = *in
+	if in.ReferencedBy != nil {
+		in, out := &in.ReferencedBy, &out.ReferencedBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DosProtectedResourceStatus.
+func (in *DosProtectedResourceStatus) DeepCopy() *DosProtectedResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DosProtectedResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DosSecurityLog) DeepCopyInto(out *DosSecurityLog) {
 	*out = *in